@@ -19,6 +19,7 @@ import (
 	pkgcfg "github.com/whatsapp-clone/backend/pkg/config"
 	"github.com/whatsapp-clone/backend/pkg/grpcclient"
 	"github.com/whatsapp-clone/backend/pkg/health"
+	"github.com/whatsapp-clone/backend/pkg/jwt"
 	"github.com/whatsapp-clone/backend/pkg/logger"
 	"github.com/whatsapp-clone/backend/pkg/metrics"
 	"github.com/whatsapp-clone/backend/pkg/middleware"
@@ -59,13 +60,24 @@ func main() {
 		log.Fatal().Err(err).Msg("failed to connect to auth-service gRPC")
 	}
 
-	// Services
+	// Services. Tokens are validated locally against auth-service's JWKS;
+	// without a JWKS URL every request is checked over gRPC.
 	authValidator := service.NewAuthValidator(authConn)
+	if cfg.JWKSURL != "" {
+		jwks := jwt.NewJWKSValidator(cfg.JWKSURL, cfg.JWKSRefresh)
+		if err := jwks.Refresh(context.Background()); err != nil {
+			log.Warn().Err(err).Msg("failed to fetch JWKS, will retry on first request")
+		}
+		authValidator = jwt.NewLocalValidator(jwks, service.NewRedisRevocationChecker(rdb))
+	}
 	rateLimiter := service.NewRedisRateLimiter(rdb)
 
 	// Gin engine
 	gin.SetMode(gin.ReleaseMode)
 	engine := gin.New()
+	if err := engine.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal().Err(err).Msg("invalid GATEWAY_TRUSTED_PROXIES")
+	}
 
 	// Global middleware chain
 	corsOrigins := strings.Split(cfg.CORSOrigins, ",")
@@ -95,11 +107,6 @@ func main() {
 	authMW := handler.AuthMiddleware(authValidator)
 	rateLimitMW := handler.RateLimitMiddleware(rateLimiter, cfg.RateLimitRPS)
 
-	// Public routes (no auth required)
-	publicRoutes := []model.RouteTarget{
-		{PathPrefix: "/api/v1/auth", TargetURL: cfg.AuthHTTPAddr, StripPrefix: false, RequireAuth: false},
-	}
-
 	// Protected routes (auth + rate limit required)
 	protectedRoutes := []model.RouteTarget{
 		{PathPrefix: "/api/v1/messages", TargetURL: cfg.MessageHTTPAddr, StripPrefix: false, RequireAuth: true},
@@ -107,9 +114,12 @@ func main() {
 	}
 
 	// Register proxy routes
-	handler.RegisterProxyRoutes(engine, publicRoutes, nil, nil)
 	handler.RegisterProxyRoutes(engine, protectedRoutes, authMW, rateLimitMW)
 
+	// Auth routes: login and token endpoints are public, device and session
+	// management requires auth.
+	handler.RegisterAuthRoutes(engine, cfg.AuthHTTPAddr, authMW, rateLimitMW)
+
 	// User routes with path rewriting for client-compatible block/unblock paths
 	handler.RegisterUserRoutes(engine, cfg.UserHTTPAddr, authMW, rateLimitMW)
 
@@ -6,6 +6,8 @@ type Config struct {
 	Port            string        `env:"GATEWAY_PORT"              envDefault:":8080"`
 	AuthGRPCAddr    string        `env:"AUTH_GRPC_ADDR"            envDefault:"auth-service:9081"`
 	AuthHTTPAddr    string        `env:"AUTH_HTTP_ADDR"            envDefault:"http://auth-service:8081"`
+	JWKSURL         string        `env:"GATEWAY_JWKS_URL"          envDefault:"http://auth-service:8081/.well-known/jwks.json"`
+	JWKSRefresh     time.Duration `env:"GATEWAY_JWKS_REFRESH"      envDefault:"5m"`
 	UserHTTPAddr    string        `env:"USER_HTTP_ADDR"            envDefault:"http://user-service:8082"`
 	ChatHTTPAddr    string        `env:"CHAT_HTTP_ADDR"            envDefault:"http://chat-service:8083"`
 	MessageHTTPAddr string        `env:"MESSAGE_HTTP_ADDR"         envDefault:"http://message-service:8084"`
@@ -20,4 +22,5 @@ type Config struct {
 	GRPCTimeout     time.Duration `env:"GATEWAY_GRPC_TIMEOUT"      envDefault:"5s"`
 	MaxBodySize     int64         `env:"GATEWAY_MAX_BODY_SIZE"     envDefault:"104857600"`
 	OTLPEndpoint    string        `env:"OTLP_ENDPOINT"             envDefault:""`
+	TrustedProxies  []string      `env:"GATEWAY_TRUSTED_PROXIES"   envSeparator:","`
 }
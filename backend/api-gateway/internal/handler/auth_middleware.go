@@ -19,7 +19,7 @@ func AuthMiddleware(validator service.AuthValidator) gin.HandlerFunc {
 		}
 		token := strings.TrimPrefix(auth, "Bearer ")
 
-		userID, phone, err := validator.ValidateToken(c.Request.Context(), token)
+		userID, phone, deviceID, err := validator.ValidateToken(c.Request.Context(), token)
 		if err != nil {
 			response.Error(c, apperr.NewUnauthorized("invalid token"))
 			c.Abort()
@@ -28,6 +28,7 @@ func AuthMiddleware(validator service.AuthValidator) gin.HandlerFunc {
 
 		c.Set("user_id", userID)
 		c.Set("phone", phone)
+		c.Set("device_id", deviceID)
 		c.Next()
 	}
 }
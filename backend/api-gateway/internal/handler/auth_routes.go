@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAuthRoutes proxies /api/v1/auth/* to the auth-service. Login and
+// token endpoints are public. The linked-device, session and two-step
+// endpoints act on the caller's account and require auth, except
+// /devices/link, which a new device calls before it has a token. The public
+// token verification keys are served at /.well-known/jwks.json.
+//
+// gin cannot mix a catch-all with static siblings, so the public routes use
+// path parameters instead of /*path.
+func RegisterAuthRoutes(
+	engine *gin.Engine,
+	authHTTPAddr string,
+	authMW, rateLimitMW gin.HandlerFunc,
+) {
+	target, err := url.Parse(authHTTPAddr)
+	if err != nil {
+		panic("invalid auth target URL: " + authHTTPAddr)
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.ErrorHandler = proxyErrorHandler
+
+	authProxy := func(c *gin.Context) {
+		// Public endpoints must never see client-supplied identity headers.
+		c.Request.Header.Del("X-User-ID")
+		c.Request.Header.Del("X-User-Phone")
+		setUserHeaders(c)
+		proxy.ServeHTTP(c.Writer, c.Request)
+	}
+
+	engine.Any("/api/v1/auth", authProxy)
+	engine.Any("/api/v1/auth/:action", authProxy)
+	engine.Any("/api/v1/auth/:action/:sub", authProxy)
+	engine.Any("/api/v1/auth/devices/link", authProxy)
+	engine.GET("/.well-known/jwks.json", authProxy)
+
+	protected := append(buildMiddlewareChain(authMW, rateLimitMW), authProxy)
+	engine.Any("/api/v1/auth/devices", protected...)
+	engine.Any("/api/v1/auth/devices/:id", protected...)
+	engine.Any("/api/v1/auth/sessions", protected...)
+	engine.Any("/api/v1/auth/sessions/:id", protected...)
+	engine.Any("/api/v1/auth/two-step", protected...)
+}
@@ -136,6 +136,10 @@ func setUserHeaders(c *gin.Context) {
 	if phone := c.GetString("phone"); phone != "" {
 		c.Request.Header.Set("X-User-Phone", phone)
 	}
+	c.Request.Header.Del("X-Device-ID")
+	if did := c.GetString("device_id"); did != "" {
+		c.Request.Header.Set("X-Device-ID", did)
+	}
 	if rid := c.GetString("request_id"); rid != "" {
 		c.Request.Header.Set("X-Request-ID", rid)
 	}
@@ -16,6 +16,8 @@ var userBlockPattern = regexp.MustCompile(
 
 // RegisterUserRoutes registers a smart proxy for /api/v1/users/* that rewrites
 // client-specific paths (e.g., /users/:id/block -> /users/contacts/:id/block).
+// Account deletion progress is public at /api/v1/account-deletions/:id, as
+// the account it belongs to may already be gone.
 func RegisterUserRoutes(
 	engine *gin.Engine,
 	userHTTPAddr string,
@@ -52,4 +54,13 @@ func RegisterUserRoutes(
 	handlers := append(mws, smartProxy)
 	engine.Any("/api/v1/users", handlers...)
 	engine.Any("/api/v1/users/*path", handlers...)
+
+	engine.GET("/api/v1/account-deletions/:id", func(c *gin.Context) {
+		// Public endpoints must never see client-supplied identity headers.
+		c.Request.Header.Del("X-User-ID")
+		c.Request.Header.Del("X-User-Phone")
+		setUserHeaders(c)
+		c.Request.URL.Path = "/api/v1/users/deletions/" + c.Param("id")
+		proxy.ServeHTTP(c.Writer, c.Request)
+	})
 }
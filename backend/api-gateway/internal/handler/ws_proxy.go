@@ -59,9 +59,17 @@ func WSProxyHandler(wsTargetURL string) gin.HandlerFunc {
 				header.Set("X-User-Phone", phoneStr)
 			}
 		}
+		if did := c.GetString("device_id"); did != "" {
+			header.Set("X-Device-ID", did)
+		}
 		header.Set("Authorization", c.GetHeader("Authorization"))
 
-		backendConn, _, err := websocket.DefaultDialer.Dial(target.String(), header)
+		// Forward the query string so resume parameters (device_id, last_seq)
+		// reach the websocket-service.
+		backendURL := *target
+		backendURL.RawQuery = c.Request.URL.RawQuery
+
+		backendConn, _, err := websocket.DefaultDialer.Dial(backendURL.String(), header)
 		if err != nil {
 			clientConn.WriteMessage(websocket.CloseMessage,
 				websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "backend unavailable"))
@@ -3,5 +3,7 @@ package service
 import "context"
 
 type AuthValidator interface {
-	ValidateToken(ctx context.Context, token string) (userID string, phone string, err error)
+	// ValidateToken returns the token's user and, for tokens bound to a
+	// linked device, the device ID.
+	ValidateToken(ctx context.Context, token string) (userID, phone, deviceID string, err error)
 }
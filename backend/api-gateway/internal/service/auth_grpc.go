@@ -16,13 +16,13 @@ func NewAuthValidator(conn *grpc.ClientConn) AuthValidator {
 	return &authGRPCValidator{client: authv1.NewAuthServiceClient(conn)}
 }
 
-func (v *authGRPCValidator) ValidateToken(ctx context.Context, token string) (string, string, error) {
+func (v *authGRPCValidator) ValidateToken(ctx context.Context, token string) (string, string, string, error) {
 	resp, err := v.client.ValidateToken(ctx, &authv1.ValidateTokenRequest{Token: token})
 	if err != nil {
-		return "", "", fmt.Errorf("auth grpc: %w", err)
+		return "", "", "", fmt.Errorf("auth grpc: %w", err)
 	}
 	if !resp.Valid {
-		return "", "", fmt.Errorf("token invalid")
+		return "", "", "", fmt.Errorf("token invalid")
 	}
-	return resp.UserId, resp.Phone, nil
+	return resp.UserId, resp.Phone, resp.DeviceId, nil
 }
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/whatsapp-clone/backend/pkg/jwt"
+)
+
+// redisRevocationChecker reads the revocation markers auth-service keeps in
+// the shared Redis for logged-out devices.
+type redisRevocationChecker struct {
+	rdb *redis.Client
+}
+
+func NewRedisRevocationChecker(rdb *redis.Client) jwt.RevocationChecker {
+	return &redisRevocationChecker{rdb: rdb}
+}
+
+func (r *redisRevocationChecker) IsRevoked(ctx context.Context, deviceID string) (bool, error) {
+	n, err := r.rdb.Exists(ctx, jwt.RevokedDeviceKey(deviceID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"net"
 	"net/http"
 	"os"
@@ -12,7 +13,9 @@ import (
 	"github.com/caarlos0/env/v11"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nats-io/nats.go"
 	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
@@ -22,6 +25,8 @@ import (
 	"github.com/whatsapp-clone/backend/auth-service/internal/handler"
 	"github.com/whatsapp-clone/backend/auth-service/internal/repository"
 	"github.com/whatsapp-clone/backend/auth-service/internal/service"
+	"github.com/whatsapp-clone/backend/pkg/accountdeletion"
+	"github.com/whatsapp-clone/backend/pkg/deviceevents"
 	"github.com/whatsapp-clone/backend/pkg/jwt"
 	"github.com/whatsapp-clone/backend/pkg/logger"
 	"github.com/whatsapp-clone/backend/pkg/metrics"
@@ -72,29 +77,101 @@ func main() {
 	defer rdb.Close()
 	log.Info().Msg("connected to Redis")
 
+	// NATS
+	nc, err := nats.Connect(cfg.NATSUrl)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to NATS")
+	}
+	defer nc.Drain()
+	log.Info().Msg("connected to NATS")
+
+	js, err := nc.JetStream()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to get JetStream context")
+	}
+
+	if err := deviceevents.EnsureStream(js); err != nil {
+		log.Fatal().Err(err).Msg("failed to create DEVICES stream")
+	}
+
 	// JWT Manager
-	jwtManager := jwt.NewManager(cfg.JWTSecret, cfg.AccessTokenTTL)
+	keySet, err := newKeySet(cfg.JWTKeyFiles, cfg.DevMode, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load JWT keys")
+	}
+	jwtManager := jwt.NewManager(keySet, cfg.AccessTokenTTL)
 
 	// Repositories
 	userRepo := repository.NewPostgresUserRepository(pgPool)
 	otpRepo := repository.NewRedisOTPRepository(rdb, cfg.OTPTTL)
 	refreshRepo := repository.NewPostgresRefreshTokenRepository(pgPool)
+	deviceRepo := repository.NewPostgresDeviceRepository(pgPool)
+	deviceState := repository.NewRedisDeviceStateRepository(rdb)
+	securityRepo := repository.NewPostgresSecurityEventRepository(pgPool)
+	deliveryRepo := repository.NewRedisOTPDeliveryRepository(rdb)
+	twoStepRepo := repository.NewPostgresTwoStepRepository(pgPool)
+	twoStepState := repository.NewRedisTwoStepStateRepository(rdb)
+
+	// OTP delivery
+	otpRouter, err := newOTPRouter(cfg.OTPDelivery, cfg.DevMode, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid OTP delivery config")
+	}
+
+	resetMailer, err := newPINResetMailer(cfg.PINReset, cfg.DevMode, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid PIN reset mail config")
+	}
 
 	// Service
 	authSvc := service.NewAuthService(
 		userRepo,
 		otpRepo,
+		deliveryRepo,
 		refreshRepo,
+		deviceRepo,
+		deviceState,
+		securityRepo,
+		twoStepRepo,
+		twoStepState,
+		rdb,
+		js,
 		jwtManager,
+		otpRouter,
+		resetMailer,
+		cfg.AccessTokenTTL,
 		cfg.RefreshTokenTTL,
+		cfg.LinkCodeTTL,
+		cfg.MaxLinked,
 		cfg.OTPLength,
 		cfg.OTPMaxAttempts,
+		cfg.OTPTTL,
+		service.OTPResendPolicy{
+			Cooldown:    cfg.OTPDelivery.ResendCooldown,
+			MaxCooldown: cfg.OTPDelivery.ResendMaxCooldown,
+			Window:      cfg.OTPDelivery.ResendWindow,
+		},
+		service.TwoStepPolicy{
+			MaxAttempts:  cfg.PINMaxAttempts,
+			Lockout:      cfg.PINLockout,
+			ChallengeTTL: cfg.PINChallengeTTL,
+		},
 		log,
 	)
 
+	// Account deletion
+	if err := accountdeletion.Subscribe(context.Background(), js, accountdeletion.ServiceAuth, func(ctx context.Context, e *accountdeletion.Event) error {
+		return authSvc.EraseUser(ctx, e.UserID)
+	}, log); err != nil {
+		log.Fatal().Err(err).Msg("failed to subscribe to account deletions")
+	}
+
 	// --- HTTP Server ---
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal().Err(err).Msg("invalid AUTH_TRUSTED_PROXIES")
+	}
 	router.Use(
 		middleware.RequestID(),
 		middleware.Logger(log),
@@ -119,9 +196,10 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
 	})
 
-	httpHandler := handler.NewHTTPHandler(authSvc, cfg.DevMode, log)
+	httpHandler := handler.NewHTTPHandler(authSvc, cfg.DevMode, cfg.OTPDelivery.ReceiptSecret, log)
 	apiV1 := router.Group("/api/v1")
 	httpHandler.RegisterRoutes(apiV1)
+	router.GET("/.well-known/jwks.json", httpHandler.JWKS)
 
 	// Prometheus metrics endpoint
 	metrics.RegisterMetricsEndpoint(router)
@@ -190,3 +268,67 @@ func errString(err error) string {
 	}
 	return "ok"
 }
+
+// newOTPRouter sets up whichever gateways are configured, plus the log sink
+// in dev mode, and routes OTPs between them.
+func newOTPRouter(cfg config.OTPDeliveryConfig, devMode bool, log zerolog.Logger) (*service.OTPRouter, error) {
+	var senders []service.OTPSender
+	if devMode {
+		senders = append(senders, service.NewLogOTPSender(cfg.LogFile, log))
+	}
+	if cfg.SMSGatewayURL != "" {
+		sms, err := service.NewHTTPSMSSender(cfg.SMSGatewayURL, cfg.SMSGatewayToken, cfg.SMSSender, cfg.SMSTemplate, cfg.SMSTemplates)
+		if err != nil {
+			return nil, err
+		}
+		senders = append(senders, sms)
+	}
+	if cfg.VoiceGatewayURL != "" {
+		voice, err := service.NewHTTPVoiceSender(cfg.VoiceGatewayURL, cfg.VoiceGatewayToken, cfg.VoiceCaller, cfg.VoiceTemplate, cfg.VoiceTemplates)
+		if err != nil {
+			return nil, err
+		}
+		senders = append(senders, voice)
+	}
+	return service.NewOTPRouter(cfg.Routes, senders...)
+}
+
+// newPINResetMailer returns the mail gateway for PIN reset codes, the log
+// mailer in dev mode, or nil, in which case PINs cannot be reset.
+func newPINResetMailer(cfg config.PINResetMailConfig, devMode bool, log zerolog.Logger) (service.PINResetMailer, error) {
+	switch {
+	case cfg.GatewayURL != "":
+		return service.NewHTTPPINResetMailer(cfg.GatewayURL, cfg.GatewayToken, cfg.From, cfg.Subject, cfg.Template)
+	case devMode:
+		return service.NewLogPINResetMailer(log), nil
+	}
+	log.Warn().Msg("AUTH_MAIL_GATEWAY_URL not set, forgotten PINs cannot be reset by email")
+	return nil, nil
+}
+
+// newKeySet loads the JWT signing and verification keys. Without key files
+// dev mode generates an ephemeral Ed25519 key, which is enough for a single
+// instance; access tokens issued before a restart stop validating and
+// clients have to refresh them. Anywhere else that would log users out on
+// every restart and split replicas, so the key files are required.
+func newKeySet(files []string, devMode bool, log zerolog.Logger) (*jwt.KeySet, error) {
+	if len(files) > 0 {
+		keys, err := jwt.LoadKeySet(files)
+		if err != nil {
+			return nil, err
+		}
+		log.Info().Str("kid", keys.SigningKey().ID).Str("alg", keys.SigningKey().Alg).Msg("loaded JWT signing key")
+		return keys, nil
+	}
+
+	if !devMode {
+		return nil, errors.New("AUTH_JWT_KEY_FILES is required unless AUTH_DEV_MODE is set")
+	}
+
+	key, err := jwt.GenerateEd25519Key()
+	if err != nil {
+		return nil, err
+	}
+	log.Warn().Str("kid", key.ID).Msg("AUTH_JWT_KEY_FILES not set, signing tokens with an ephemeral key")
+	return jwt.NewKeySet(key)
+}
@@ -8,13 +8,59 @@ type Config struct {
 	PostgresDSN     string        `env:"AUTH_POSTGRES_DSN"       envRequired:"true"`
 	RedisAddr       string        `env:"AUTH_REDIS_ADDR"         envDefault:"redis:6379"`
 	RedisPassword   string        `env:"AUTH_REDIS_PASSWORD"     envDefault:""`
-	JWTSecret       string        `env:"AUTH_JWT_SECRET"         envRequired:"true"`
+	NATSUrl         string        `env:"AUTH_NATS_URL"           envDefault:"nats://nats:4222"`
+	JWTKeyFiles     []string      `env:"AUTH_JWT_KEY_FILES"      envSeparator:","`
 	AccessTokenTTL  time.Duration `env:"AUTH_ACCESS_TOKEN_TTL"   envDefault:"15m"`
 	RefreshTokenTTL time.Duration `env:"AUTH_REFRESH_TOKEN_TTL"  envDefault:"720h"`
 	OTPLength       int           `env:"AUTH_OTP_LENGTH"         envDefault:"6"`
 	OTPTTL          time.Duration `env:"AUTH_OTP_TTL"            envDefault:"5m"`
 	OTPMaxAttempts  int           `env:"AUTH_OTP_MAX_ATTEMPTS"   envDefault:"5"`
+	LinkCodeTTL     time.Duration `env:"AUTH_LINK_CODE_TTL"      envDefault:"2m"`
+	MaxLinked       int           `env:"AUTH_MAX_LINKED_DEVICES" envDefault:"4"`
+	PINMaxAttempts  int           `env:"AUTH_PIN_MAX_ATTEMPTS"   envDefault:"5"`
+	PINLockout      time.Duration `env:"AUTH_PIN_LOCKOUT"        envDefault:"1h"`
+	PINChallengeTTL time.Duration `env:"AUTH_PIN_CHALLENGE_TTL"  envDefault:"10m"`
 	DevMode         bool          `env:"AUTH_DEV_MODE"           envDefault:"false"`
 	LogLevel        string        `env:"AUTH_LOG_LEVEL"          envDefault:"info"`
 	OTLPEndpoint    string        `env:"OTLP_ENDPOINT"           envDefault:""`
+	TrustedProxies  []string      `env:"AUTH_TRUSTED_PROXIES"    envDefault:"10.0.0.0/8,172.16.0.0/12,192.168.0.0/16" envSeparator:","`
+
+	OTPDelivery OTPDeliveryConfig
+	PINReset    PINResetMailConfig
+}
+
+// PINResetMailConfig configures the mail gateway that sends the codes for
+// resetting a forgotten two-step PIN to recovery emails. Without a gateway
+// URL, resets are only possible in dev mode, which shows the code instead.
+type PINResetMailConfig struct {
+	GatewayURL   string `env:"AUTH_MAIL_GATEWAY_URL"   envDefault:""`
+	GatewayToken string `env:"AUTH_MAIL_GATEWAY_TOKEN" envDefault:""`
+	From         string `env:"AUTH_MAIL_FROM"          envDefault:""`
+	Subject      string `env:"AUTH_PIN_RESET_SUBJECT"  envDefault:"Turn off two-step verification"`
+	Template     string `env:"AUTH_PIN_RESET_TEMPLATE" envDefault:"Your code to turn off two-step verification is {{.Code}}. It expires in {{.TTLMinutes}} minutes. If you didn't ask for it, someone may be trying to get into your account; don't share it."`
+}
+
+// OTPDeliveryConfig configures OTP providers and resend limits. Routes map
+// phone prefixes ("+91", or "*" for all other numbers) to the providers
+// tried in order, e.g. "+1=sms,voice;*=sms". Providers are "sms" and
+// "voice" once their gateway URL is set, and "log" in dev mode only; a
+// route naming an unavailable provider stops startup. Template overrides
+// are keyed by phone prefix: "+49=...|+33=...".
+type OTPDeliveryConfig struct {
+	Routes            map[string]string `env:"AUTH_OTP_ROUTES"              envDefault:"*=sms" envSeparator:";" envKeyValSeparator:"="`
+	LogFile           string            `env:"AUTH_OTP_LOG_FILE"            envDefault:""`
+	ReceiptSecret     string            `env:"AUTH_OTP_RECEIPT_SECRET"      envDefault:""`
+	ResendCooldown    time.Duration     `env:"AUTH_OTP_RESEND_COOLDOWN"     envDefault:"30s"`
+	ResendMaxCooldown time.Duration     `env:"AUTH_OTP_RESEND_MAX_COOLDOWN" envDefault:"10m"`
+	ResendWindow      time.Duration     `env:"AUTH_OTP_RESEND_WINDOW"       envDefault:"1h"`
+	SMSGatewayURL     string            `env:"AUTH_SMS_GATEWAY_URL"         envDefault:""`
+	SMSGatewayToken   string            `env:"AUTH_SMS_GATEWAY_TOKEN"       envDefault:""`
+	SMSSender         string            `env:"AUTH_SMS_SENDER"              envDefault:""`
+	SMSTemplate       string            `env:"AUTH_SMS_TEMPLATE"            envDefault:"{{.Code}} is your WhatsApp verification code. It expires in {{.TTLMinutes}} minutes. Don't share it."`
+	SMSTemplates      map[string]string `env:"AUTH_SMS_TEMPLATES"           envSeparator:"|" envKeyValSeparator:"="`
+	VoiceGatewayURL   string            `env:"AUTH_VOICE_GATEWAY_URL"       envDefault:""`
+	VoiceGatewayToken string            `env:"AUTH_VOICE_GATEWAY_TOKEN"     envDefault:""`
+	VoiceCaller       string            `env:"AUTH_VOICE_CALLER"            envDefault:""`
+	VoiceTemplate     string            `env:"AUTH_VOICE_TEMPLATE"          envDefault:"Your WhatsApp verification code is {{.SpokenCode}}. Again, your code is {{.SpokenCode}}."`
+	VoiceTemplates    map[string]string `env:"AUTH_VOICE_TEMPLATES"         envSeparator:"|" envKeyValSeparator:"="`
 }
@@ -26,7 +26,7 @@ func (h *GRPCHandler) ValidateToken(ctx context.Context, req *authv1.ValidateTok
 		return &authv1.ValidateTokenResponse{Valid: false}, nil
 	}
 
-	userID, phone, err := h.authSvc.ValidateToken(ctx, req.Token)
+	userID, phone, deviceID, err := h.authSvc.ValidateToken(ctx, req.Token)
 	if err != nil {
 		h.log.Debug().Err(err).Msg("token validation failed")
 		return &authv1.ValidateTokenResponse{Valid: false}, nil
@@ -37,8 +37,9 @@ func (h *GRPCHandler) ValidateToken(ctx context.Context, req *authv1.ValidateTok
 	}
 
 	return &authv1.ValidateTokenResponse{
-		Valid:  true,
-		UserId: userID,
-		Phone:  phone,
+		Valid:    true,
+		UserId:   userID,
+		Phone:    phone,
+		DeviceId: deviceID,
 	}, nil
 }
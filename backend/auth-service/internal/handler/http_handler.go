@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"crypto/subtle"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 
@@ -11,13 +14,22 @@ import (
 )
 
 type HTTPHandler struct {
-	authSvc service.AuthService
-	devMode bool
-	log     zerolog.Logger
+	authSvc       service.AuthService
+	devMode       bool
+	receiptSecret string
+	log           zerolog.Logger
+}
+
+// NewHTTPHandler creates the handler. receiptSecret authenticates OTP
+// delivery receipts; when empty the receipt endpoint is disabled.
+func NewHTTPHandler(authSvc service.AuthService, devMode bool, receiptSecret string, log zerolog.Logger) *HTTPHandler {
+	return &HTTPHandler{authSvc: authSvc, devMode: devMode, receiptSecret: receiptSecret, log: log}
 }
 
-func NewHTTPHandler(authSvc service.AuthService, devMode bool, log zerolog.Logger) *HTTPHandler {
-	return &HTTPHandler{authSvc: authSvc, devMode: devMode, log: log}
+// JWKS serves the token verification keys at /.well-known/jwks.json.
+func (h *HTTPHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.authSvc.JWKS())
 }
 
 func (h *HTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
@@ -26,6 +38,9 @@ func (h *HTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
 		// Original backend routes
 		auth.POST("/request-otp", h.RequestOTP)
 		auth.POST("/verify-otp", h.VerifyOTP)
+		auth.POST("/verify-pin", h.VerifyPIN)
+		auth.POST("/pin-reset/request", h.RequestPINReset)
+		auth.POST("/pin-reset/confirm", h.ConfirmPINReset)
 		auth.POST("/refresh", h.Refresh)
 		auth.POST("/logout", h.Logout)
 
@@ -33,9 +48,37 @@ func (h *HTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
 		auth.POST("/otp/send", h.RequestOTP)
 		auth.POST("/otp/verify", h.VerifyOTP)
 		auth.POST("/token/refresh", h.Refresh)
+
+		// OTP delivery status, and the providers' status callbacks.
+		auth.GET("/otp-deliveries/:id", h.GetOTPDelivery)
+		auth.POST("/otp-receipts/:provider", h.RecordOTPReceipt)
+
+		// Linked devices. All but /devices/link need the api-gateway's
+		// X-User-ID and X-Device-ID headers.
+		auth.GET("/devices", h.ListDevices)
+		auth.POST("/devices/link-code", h.CreateLinkCode)
+		auth.POST("/devices/link", h.LinkDevice)
+		auth.DELETE("/devices/:id", h.RevokeDevice)
+
+		// Sessions: one per linked device, authenticated like /devices.
+		auth.GET("/sessions", h.ListSessions)
+		auth.POST("/sessions/logout-others", h.RevokeOtherSessions)
+		auth.DELETE("/sessions/:id", h.RevokeSession)
+
+		// Two-step verification PIN, authenticated like /devices.
+		auth.GET("/two-step", h.GetTwoStep)
+		auth.PUT("/two-step", h.SetTwoStep)
+		auth.DELETE("/two-step", h.DisableTwoStep)
 	}
 }
 
+// clientInfo captures where a request came from. Behind the api-gateway
+// the client IP is taken from X-Forwarded-For, as far as it was added by
+// AUTH_TRUSTED_PROXIES.
+func clientInfo(c *gin.Context) model.ClientInfo {
+	return model.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
 func (h *HTTPHandler) RequestOTP(c *gin.Context) {
 	var req model.SendOTPRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -43,23 +86,62 @@ func (h *HTTPHandler) RequestOTP(c *gin.Context) {
 		return
 	}
 
-	otp, err := h.authSvc.SendOTP(c.Request.Context(), req.Phone)
+	result, err := h.authSvc.SendOTP(c.Request.Context(), req.Phone, req.Channel)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
 	resp := gin.H{
-		"message":            "OTP sent successfully",
-		"expires_in_seconds": 300,
+		"message":              "OTP sent successfully",
+		"expires_in_seconds":   int64(result.ExpiresIn.Seconds()),
+		"delivery_id":          result.Delivery.ID,
+		"channel":              result.Delivery.Channel,
+		"status":               result.Delivery.Status,
+		"resend_after_seconds": int64(result.ResendAfter.Seconds()),
 	}
 	if h.devMode {
-		resp["otp"] = otp
+		resp["otp"] = result.Code
 	}
 
 	response.OK(c, resp)
 }
 
+func (h *HTTPHandler) GetOTPDelivery(c *gin.Context) {
+	delivery, err := h.authSvc.GetOTPDelivery(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, delivery)
+}
+
+// RecordOTPReceipt is called by delivery providers, which authenticate with
+// the shared secret in X-Receipt-Secret.
+func (h *HTTPHandler) RecordOTPReceipt(c *gin.Context) {
+	secret := c.GetHeader("X-Receipt-Secret")
+	if h.receiptSecret == "" {
+		response.Error(c, apperr.NewNotFound("receipts are not enabled"))
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(h.receiptSecret)) != 1 {
+		response.Error(c, apperr.NewUnauthorized("invalid receipt secret"))
+		return
+	}
+
+	var receipt model.OTPDeliveryReceipt
+	if err := c.ShouldBindJSON(&receipt); err != nil {
+		response.Error(c, apperr.NewBadRequest("message_id and status are required"))
+		return
+	}
+
+	if err := h.authSvc.RecordOTPReceipt(c.Request.Context(), c.Param("provider"), receipt); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
 func (h *HTTPHandler) VerifyOTP(c *gin.Context) {
 	var req model.VerifyOTPRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -77,7 +159,65 @@ func (h *HTTPHandler) VerifyOTP(c *gin.Context) {
 		return
 	}
 
-	result, err := h.authSvc.VerifyOTP(c.Request.Context(), req.Phone, req.Code)
+	req.ClientInfo = clientInfo(c)
+	result, err := h.authSvc.VerifyOTP(c.Request.Context(), req.Phone, req.Code, req.DeviceInfo)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, result)
+}
+
+func (h *HTTPHandler) VerifyPIN(c *gin.Context) {
+	var req model.VerifyPINRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, apperr.NewBadRequest("pin_token and pin are required"))
+		return
+	}
+
+	result, err := h.authSvc.VerifyPIN(c.Request.Context(), req.PINToken, req.PIN)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, result)
+}
+
+func (h *HTTPHandler) RequestPINReset(c *gin.Context) {
+	var req model.PINResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, apperr.NewBadRequest("pin_token is required"))
+		return
+	}
+
+	result, err := h.authSvc.RequestPINReset(c.Request.Context(), req.PINToken)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	resp := gin.H{
+		"message":             "reset code sent to the recovery email",
+		"recovery_email_hint": result.RecoveryEmailHint,
+		"expires_in_seconds":  int64(result.ExpiresIn.Seconds()),
+	}
+	if h.devMode {
+		resp["code"] = result.Code
+	}
+
+	response.OK(c, resp)
+}
+
+func (h *HTTPHandler) ConfirmPINReset(c *gin.Context) {
+	var req model.ConfirmPINResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, apperr.NewBadRequest("pin_token and code are required"))
+		return
+	}
+
+	result, err := h.authSvc.ConfirmPINReset(c.Request.Context(), req.PINToken, req.Code)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -93,7 +233,7 @@ func (h *HTTPHandler) Refresh(c *gin.Context) {
 		return
 	}
 
-	tokens, err := h.authSvc.RefreshTokens(c.Request.Context(), req.RefreshToken)
+	tokens, err := h.authSvc.RefreshTokens(c.Request.Context(), req.RefreshToken, clientInfo(c))
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -122,3 +262,174 @@ func (h *HTTPHandler) Logout(c *gin.Context) {
 
 	response.OK(c, gin.H{"message": "logged out successfully"})
 }
+
+// authenticatedDevice reads the caller's identity forwarded by the api-gateway.
+func authenticatedDevice(c *gin.Context) (userID, deviceID string, ok bool) {
+	userID = c.GetHeader("X-User-ID")
+	if userID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return "", "", false
+	}
+	return userID, c.GetHeader("X-Device-ID"), true
+}
+
+func (h *HTTPHandler) ListDevices(c *gin.Context) {
+	userID, deviceID, ok := authenticatedDevice(c)
+	if !ok {
+		return
+	}
+
+	devices, err := h.authSvc.ListDevices(c.Request.Context(), userID, deviceID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, devices)
+}
+
+func (h *HTTPHandler) CreateLinkCode(c *gin.Context) {
+	userID, deviceID, ok := authenticatedDevice(c)
+	if !ok {
+		return
+	}
+
+	code, err := h.authSvc.CreateLinkCode(c.Request.Context(), userID, deviceID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, code)
+}
+
+func (h *HTTPHandler) LinkDevice(c *gin.Context) {
+	var req model.LinkDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, apperr.NewBadRequest("code is required"))
+		return
+	}
+
+	req.ClientInfo = clientInfo(c)
+	result, err := h.authSvc.LinkDevice(c.Request.Context(), req.Code, req.DeviceInfo)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, result)
+}
+
+func (h *HTTPHandler) RevokeDevice(c *gin.Context) {
+	userID, _, ok := authenticatedDevice(c)
+	if !ok {
+		return
+	}
+
+	if err := h.authSvc.RevokeDevice(c.Request.Context(), userID, c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+func (h *HTTPHandler) ListSessions(c *gin.Context) {
+	userID, deviceID, ok := authenticatedDevice(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.authSvc.ListSessions(c.Request.Context(), userID, deviceID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, sessions)
+}
+
+func (h *HTTPHandler) RevokeSession(c *gin.Context) {
+	userID, _, ok := authenticatedDevice(c)
+	if !ok {
+		return
+	}
+
+	if err := h.authSvc.RevokeSession(c.Request.Context(), userID, c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+func (h *HTTPHandler) RevokeOtherSessions(c *gin.Context) {
+	userID, deviceID, ok := authenticatedDevice(c)
+	if !ok {
+		return
+	}
+
+	n, err := h.authSvc.RevokeOtherSessions(c.Request.Context(), userID, deviceID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"revoked": n})
+}
+
+func (h *HTTPHandler) GetTwoStep(c *gin.Context) {
+	userID, _, ok := authenticatedDevice(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.authSvc.GetTwoStep(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, status)
+}
+
+func (h *HTTPHandler) SetTwoStep(c *gin.Context) {
+	userID, _, ok := authenticatedDevice(c)
+	if !ok {
+		return
+	}
+
+	var req model.SetTwoStepRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, apperr.NewBadRequest("pin is required"))
+		return
+	}
+
+	status, err := h.authSvc.SetTwoStep(c.Request.Context(), userID, req.CurrentPIN, req.PIN, req.RecoveryEmail, clientInfo(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, status)
+}
+
+func (h *HTTPHandler) DisableTwoStep(c *gin.Context) {
+	userID, _, ok := authenticatedDevice(c)
+	if !ok {
+		return
+	}
+
+	var req model.DisableTwoStepRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, apperr.NewBadRequest("pin is required"))
+		return
+	}
+
+	if err := h.authSvc.DisableTwoStep(c.Request.Context(), userID, req.PIN, clientInfo(c)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
@@ -7,24 +7,35 @@ type TokenPair struct {
 }
 
 // AuthResult is the enriched response returned after OTP verification.
-// It includes both the token pair and user info for the client.
+// It includes both the token pair and user info for the client. A PIN
+// challenge carries none of them, so they are omitted when empty.
 type AuthResult struct {
-	AccessToken      string `json:"access_token"`
-	RefreshToken     string `json:"refresh_token"`
-	ExpiresIn        int64  `json:"expires_in"`
-	ExpiresInSeconds int64  `json:"expires_in_seconds"`
-	User             *User  `json:"user"`
+	AccessToken      string `json:"access_token,omitempty"`
+	RefreshToken     string `json:"refresh_token,omitempty"`
+	ExpiresIn        int64  `json:"expires_in,omitempty"`
+	ExpiresInSeconds int64  `json:"expires_in_seconds,omitempty"`
+	User             *User  `json:"user,omitempty"`
 	IsNewUser        bool   `json:"is_new_user"`
+	DeviceID         string `json:"device_id,omitempty"`
+
+	// PINRequired is set instead of tokens when the account has two-step
+	// verification; the login is finished with PINToken and the PIN.
+	PINRequired       bool   `json:"pin_required"`
+	PINToken          string `json:"pin_token,omitempty"`
+	RecoveryEmailHint string `json:"recovery_email_hint,omitempty"`
 }
 
 type SendOTPRequest struct {
 	Phone string `json:"phone" binding:"required"`
+	// Channel asks for "sms" or "voice"; empty lets routing decide.
+	Channel string `json:"channel"`
 }
 
 type VerifyOTPRequest struct {
 	Phone string `json:"phone" binding:"required"`
 	Code  string `json:"code"`
 	OTP   string `json:"otp"`
+	DeviceInfo
 }
 
 type RefreshRequest struct {
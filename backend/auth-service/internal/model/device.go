@@ -0,0 +1,96 @@
+package model
+
+import "time"
+
+// Device platforms accepted at login and link time. Anything else is stored
+// as PlatformOther.
+const (
+	PlatformAndroid = "android"
+	PlatformIOS     = "ios"
+	PlatformWeb     = "web"
+	PlatformDesktop = "desktop"
+	PlatformOther   = "other"
+
+	DeviceNameMaxLen = 64
+	UserAgentMaxLen  = 512
+)
+
+// Device is a named login of a user. The device created by OTP verification
+// is primary; devices linked by scanning a QR code from another device are
+// secondary.
+type Device struct {
+	ID           string     `json:"id"             db:"id"`
+	UserID       string     `json:"user_id"        db:"user_id"`
+	Name         string     `json:"name"           db:"name"`
+	Platform     string     `json:"platform"       db:"platform"`
+	IsPrimary    bool       `json:"is_primary"     db:"is_primary"`
+	LinkedBy     string     `json:"linked_by,omitempty" db:"linked_by"`
+	IP           string     `json:"-"              db:"ip"`
+	UserAgent    string     `json:"-"              db:"user_agent"`
+	CreatedAt    time.Time  `json:"created_at"     db:"created_at"`
+	LastActiveAt time.Time  `json:"last_active_at" db:"last_active_at"`
+	RevokedAt    *time.Time `json:"-"              db:"revoked_at"`
+	Current      bool       `json:"current"`
+}
+
+// Session is a device's refresh token family as shown to its owner. Each
+// login or link starts one; rotating the refresh token keeps it, and it
+// ends when the device is logged out. A session's ID is its device's ID.
+type Session struct {
+	ID         string    `json:"id"`
+	DeviceName string    `json:"device_name"`
+	Platform   string    `json:"platform"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	Current    bool      `json:"current"`
+}
+
+// Session returns the device's session view.
+func (d *Device) Session() *Session {
+	return &Session{
+		ID:         d.ID,
+		DeviceName: d.Name,
+		Platform:   d.Platform,
+		IP:         d.IP,
+		UserAgent:  d.UserAgent,
+		CreatedAt:  d.CreatedAt,
+		LastUsedAt: d.LastActiveAt,
+		Current:    d.Current,
+	}
+}
+
+// ClientInfo is what the server observes about a caller.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// DeviceInfo is what a client reports about itself when logging in or
+// linking, plus what the server observed about the request.
+type DeviceInfo struct {
+	Name     string `json:"device_name"`
+	Platform string `json:"platform"`
+
+	ClientInfo `json:"-"`
+}
+
+// LinkCode is a short-lived, single-use code an authenticated device shows
+// as a QR code for a new device to scan.
+type LinkCode struct {
+	Code             string `json:"code"`
+	QRPayload        string `json:"qr_payload"`
+	ExpiresInSeconds int64  `json:"expires_in_seconds"`
+}
+
+// PendingLink is what the server keeps for an issued link code.
+type PendingLink struct {
+	UserID   string `json:"user_id"`
+	IssuedBy string `json:"issued_by"`
+}
+
+type LinkDeviceRequest struct {
+	Code string `json:"code" binding:"required"`
+	DeviceInfo
+}
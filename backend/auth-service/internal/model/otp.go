@@ -1,6 +1,60 @@
 package model
 
+import "time"
+
 type OTPEntry struct {
 	HashedOTP string `json:"hashed_otp"`
 	Attempts  int    `json:"attempts"`
 }
+
+// OTP delivery channels.
+const (
+	OTPChannelSMS   = "sms"
+	OTPChannelVoice = "voice"
+)
+
+// OTP delivery statuses. A delivery is sent once a provider accepted it and
+// becomes delivered or failed when the provider reports back.
+const (
+	OTPStatusSent      = "sent"
+	OTPStatusDelivered = "delivered"
+	OTPStatusFailed    = "failed"
+)
+
+// OTPMessage is one code handed to a delivery provider.
+type OTPMessage struct {
+	Phone   string
+	Code    string
+	Channel string
+	TTL     time.Duration
+}
+
+// OTPDelivery tracks how an OTP reached, or failed to reach, a phone.
+// Provider errors are kept for the logs and never returned to clients.
+type OTPDelivery struct {
+	ID                string    `json:"id"`
+	Phone             string    `json:"-"`
+	Channel           string    `json:"channel"`
+	Provider          string    `json:"provider"`
+	ProviderMessageID string    `json:"-"`
+	Status            string    `json:"status"`
+	Attempts          int       `json:"attempts"`
+	Error             string    `json:"-"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// OTPSendResult is the outcome of SendOTP. Code is only exposed in dev mode.
+type OTPSendResult struct {
+	Code        string
+	Delivery    *OTPDelivery
+	ExpiresIn   time.Duration
+	ResendAfter time.Duration
+}
+
+// OTPDeliveryReceipt is a provider's status callback for a sent message.
+type OTPDeliveryReceipt struct {
+	MessageID string `json:"message_id" binding:"required"`
+	Status    string `json:"status"     binding:"required"`
+	Error     string `json:"error"`
+}
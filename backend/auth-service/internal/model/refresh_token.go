@@ -5,6 +5,7 @@ import "time"
 type RefreshToken struct {
 	ID        string    `db:"id"`
 	UserID    string    `db:"user_id"`
+	DeviceID  string    `db:"device_id"`
 	TokenHash string    `db:"token_hash"`
 	ExpiresAt time.Time `db:"expires_at"`
 	Revoked   bool      `db:"revoked"`
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// Security event types.
+const (
+	// SecurityEventRefreshReuse: a refresh token was presented after it had
+	// been rotated, so it was stolen or the session was cloned. The token's
+	// whole family is revoked.
+	SecurityEventRefreshReuse = "refresh_token_reuse"
+	// SecurityEventPINLockout: too many wrong two-step PINs locked the
+	// account's PIN entry.
+	SecurityEventPINLockout = "pin_lockout"
+	// SecurityEventPINReset: two-step verification was turned off with a
+	// code sent to the recovery email, because the PIN was forgotten.
+	SecurityEventPINReset = "pin_reset"
+)
+
+// SecurityEvent is an audit record of suspicious account activity. IP and
+// UserAgent are those of the request that triggered it.
+type SecurityEvent struct {
+	ID        string    `json:"id"         db:"id"`
+	UserID    string    `json:"user_id"    db:"user_id"`
+	Type      string    `json:"type"       db:"type"`
+	DeviceID  string    `json:"device_id"  db:"device_id"`
+	IP        string    `json:"ip"         db:"ip"`
+	UserAgent string    `json:"user_agent" db:"user_agent"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,68 @@
+package model
+
+import "time"
+
+// PINLength is the number of digits in a two-step verification PIN.
+const PINLength = 6
+
+// TwoStep is a user's two-step verification setup. The PIN is stored only
+// as a bcrypt hash.
+type TwoStep struct {
+	UserID        string    `json:"-"              db:"user_id"`
+	PINHash       string    `json:"-"              db:"pin_hash"`
+	RecoveryEmail string    `json:"recovery_email" db:"recovery_email"`
+	CreatedAt     time.Time `json:"created_at"     db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"     db:"updated_at"`
+}
+
+// TwoStepStatus is what GET /auth/two-step reports.
+type TwoStepStatus struct {
+	Enabled       bool       `json:"enabled"`
+	RecoveryEmail string     `json:"recovery_email,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+// SetTwoStepRequest turns two-step verification on or changes it. Once a
+// PIN is set, CurrentPIN must be given to change anything.
+type SetTwoStepRequest struct {
+	PIN           string `json:"pin"            binding:"required"`
+	CurrentPIN    string `json:"current_pin"`
+	RecoveryEmail string `json:"recovery_email"`
+}
+
+type DisableTwoStepRequest struct {
+	PIN string `json:"pin" binding:"required"`
+}
+
+// PINChallenge is the pending login of a two-step account between a
+// correct OTP and the PIN.
+type PINChallenge struct {
+	UserID string     `json:"user_id"`
+	Device DeviceInfo `json:"device"`
+	// Client is kept separately because DeviceInfo hides it from JSON.
+	Client ClientInfo `json:"client"`
+}
+
+type VerifyPINRequest struct {
+	PINToken string `json:"pin_token" binding:"required"`
+	PIN      string `json:"pin"       binding:"required"`
+}
+
+// PINResetRequest asks for a PIN reset code to be emailed to the recovery
+// address of a pending PIN login.
+type PINResetRequest struct {
+	PINToken string `json:"pin_token" binding:"required"`
+}
+
+// PINResetResult reports where the reset code went. Code is only shown in
+// dev mode.
+type PINResetResult struct {
+	Code              string
+	RecoveryEmailHint string
+	ExpiresIn         time.Duration
+}
+
+type ConfirmPINResetRequest struct {
+	PINToken string `json:"pin_token" binding:"required"`
+	Code     string `json:"code"      binding:"required"`
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+)
+
+type postgresDeviceRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresDeviceRepository(pool *pgxpool.Pool) DeviceRepository {
+	return &postgresDeviceRepository{pool: pool}
+}
+
+const deviceColumns = `id, user_id, name, platform, is_primary, COALESCE(linked_by::text, ''),
+	ip, user_agent, created_at, last_active_at, revoked_at`
+
+func scanDevice(row pgx.Row) (*model.Device, error) {
+	var d model.Device
+	err := row.Scan(&d.ID, &d.UserID, &d.Name, &d.Platform, &d.IsPrimary, &d.LinkedBy,
+		&d.IP, &d.UserAgent, &d.CreatedAt, &d.LastActiveAt, &d.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *postgresDeviceRepository) Create(ctx context.Context, device *model.Device) error {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO linked_devices (user_id, name, platform, is_primary, linked_by, ip, user_agent)
+		VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, $6, $7)
+		RETURNING id, created_at, last_active_at
+	`, device.UserID, device.Name, device.Platform, device.IsPrimary, device.LinkedBy,
+		device.IP, device.UserAgent,
+	).Scan(&device.ID, &device.CreatedAt, &device.LastActiveAt)
+	if err != nil {
+		return apperr.NewInternal("failed to create device", err)
+	}
+	return nil
+}
+
+func (r *postgresDeviceRepository) GetByID(ctx context.Context, id string) (*model.Device, error) {
+	d, err := scanDevice(r.pool.QueryRow(ctx,
+		`SELECT `+deviceColumns+` FROM linked_devices WHERE id = $1`, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, apperr.NewInternal("failed to get device", err)
+	}
+	return d, nil
+}
+
+func (r *postgresDeviceRepository) ListActiveByUserID(ctx context.Context, userID string, activeSince time.Time) ([]*model.Device, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+deviceColumns+` FROM linked_devices
+		WHERE user_id = $1 AND revoked_at IS NULL AND last_active_at > $2
+		ORDER BY last_active_at DESC
+	`, userID, activeSince)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to list devices", err)
+	}
+	defer rows.Close()
+
+	var devices []*model.Device
+	for rows.Next() {
+		d, err := scanDevice(rows)
+		if err != nil {
+			return nil, apperr.NewInternal("failed to scan device", err)
+		}
+		devices = append(devices, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.NewInternal("failed to list devices", err)
+	}
+	return devices, nil
+}
+
+func (r *postgresDeviceRepository) CountLinked(ctx context.Context, userID string, activeSince time.Time) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM linked_devices
+		WHERE user_id = $1 AND is_primary = FALSE AND revoked_at IS NULL AND last_active_at > $2
+	`, userID, activeSince).Scan(&n)
+	if err != nil {
+		return 0, apperr.NewInternal("failed to count linked devices", err)
+	}
+	return n, nil
+}
+
+func (r *postgresDeviceRepository) Touch(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE linked_devices SET last_active_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return apperr.NewInternal("failed to update device activity", err)
+	}
+	return nil
+}
+
+func (r *postgresDeviceRepository) RecordUse(ctx context.Context, id string, client model.ClientInfo) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE linked_devices SET last_active_at = NOW(), ip = $2, user_agent = $3
+		WHERE id = $1 AND revoked_at IS NULL
+	`, id, client.IP, client.UserAgent)
+	if err != nil {
+		return apperr.NewInternal("failed to record device use", err)
+	}
+	return nil
+}
+
+func (r *postgresDeviceRepository) Revoke(ctx context.Context, id string) (bool, error) {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE linked_devices SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return false, apperr.NewInternal("failed to revoke device", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	"github.com/whatsapp-clone/backend/pkg/jwt"
+)
+
+type redisDeviceStateRepository struct {
+	rdb *redis.Client
+}
+
+func NewRedisDeviceStateRepository(rdb *redis.Client) DeviceStateRepository {
+	return &redisDeviceStateRepository{rdb: rdb}
+}
+
+func linkCodeKey(codeHash string) string {
+	return "device:link:" + codeHash
+}
+
+func deviceActivityKey(deviceID string) string {
+	return "device:active:" + deviceID
+}
+
+func (r *redisDeviceStateRepository) StoreLinkCode(ctx context.Context, codeHash string, link *model.PendingLink, ttl time.Duration) error {
+	data, err := json.Marshal(link)
+	if err != nil {
+		return apperr.NewInternal("failed to marshal link code", err)
+	}
+	if err := r.rdb.Set(ctx, linkCodeKey(codeHash), data, ttl).Err(); err != nil {
+		return apperr.NewInternal("failed to store link code", err)
+	}
+	return nil
+}
+
+func (r *redisDeviceStateRepository) ConsumeLinkCode(ctx context.Context, codeHash string) (*model.PendingLink, error) {
+	data, err := r.rdb.GetDel(ctx, linkCodeKey(codeHash)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, apperr.NewInternal("failed to consume link code", err)
+	}
+	var link model.PendingLink
+	if err := json.Unmarshal(data, &link); err != nil {
+		return nil, apperr.NewInternal("failed to unmarshal link code", err)
+	}
+	return &link, nil
+}
+
+func (r *redisDeviceStateRepository) MarkRevoked(ctx context.Context, deviceID string, ttl time.Duration) error {
+	if err := r.rdb.Set(ctx, jwt.RevokedDeviceKey(deviceID), 1, ttl).Err(); err != nil {
+		return apperr.NewInternal("failed to mark device revoked", err)
+	}
+	return nil
+}
+
+func (r *redisDeviceStateRepository) IsRevoked(ctx context.Context, deviceID string) (bool, error) {
+	n, err := r.rdb.Exists(ctx, jwt.RevokedDeviceKey(deviceID)).Result()
+	if err != nil {
+		return false, apperr.NewInternal("failed to check device revocation", err)
+	}
+	return n > 0, nil
+}
+
+func (r *redisDeviceStateRepository) ClaimActivity(ctx context.Context, deviceID string, interval time.Duration) (bool, error) {
+	ok, err := r.rdb.SetNX(ctx, deviceActivityKey(deviceID), 1, interval).Result()
+	if err != nil {
+		return false, apperr.NewInternal("failed to throttle device activity", err)
+	}
+	return ok, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+)
+
+type DeviceRepository interface {
+	// Create inserts a device and fills in its ID and timestamps.
+	Create(ctx context.Context, device *model.Device) error
+	// GetByID returns a device, revoked or not, or nil if it does not exist.
+	GetByID(ctx context.Context, id string) (*model.Device, error)
+	// ListActiveByUserID returns the user's unrevoked devices seen since
+	// activeSince, most recently active first.
+	ListActiveByUserID(ctx context.Context, userID string, activeSince time.Time) ([]*model.Device, error)
+	// CountLinked counts the user's unrevoked secondary devices seen since
+	// activeSince.
+	CountLinked(ctx context.Context, userID string, activeSince time.Time) (int, error)
+	Touch(ctx context.Context, id string) error
+	// RecordUse updates the device's last-active time together with the IP
+	// and user agent its session was last used from.
+	RecordUse(ctx context.Context, id string, client model.ClientInfo) error
+	// Revoke marks a device revoked. It reports false if the device was
+	// already revoked.
+	Revoke(ctx context.Context, id string) (bool, error)
+}
+
+// DeviceStateRepository holds the short-lived device state kept outside
+// Postgres: pending QR link codes, revocation markers checked on every
+// token validation, and last-active throttling.
+type DeviceStateRepository interface {
+	StoreLinkCode(ctx context.Context, codeHash string, link *model.PendingLink, ttl time.Duration) error
+	// ConsumeLinkCode returns and deletes a pending link, or returns nil if
+	// the code is unknown, expired or already used.
+	ConsumeLinkCode(ctx context.Context, codeHash string) (*model.PendingLink, error)
+	MarkRevoked(ctx context.Context, deviceID string, ttl time.Duration) error
+	IsRevoked(ctx context.Context, deviceID string) (bool, error)
+	// ClaimActivity reports whether the device's last-active time is due an
+	// update; it returns true at most once per interval.
+	ClaimActivity(ctx context.Context, deviceID string, interval time.Duration) (bool, error)
+}
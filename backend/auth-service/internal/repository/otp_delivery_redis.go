@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+)
+
+type redisOTPDeliveryRepository struct {
+	rdb *redis.Client
+}
+
+func NewRedisOTPDeliveryRepository(rdb *redis.Client) OTPDeliveryRepository {
+	return &redisOTPDeliveryRepository{rdb: rdb}
+}
+
+// storedOTPDelivery carries the fields model.OTPDelivery hides from JSON
+// responses.
+type storedOTPDelivery struct {
+	model.OTPDelivery
+	Phone             string `json:"phone"`
+	ProviderMessageID string `json:"provider_message_id"`
+	Error             string `json:"error"`
+}
+
+func otpDeliveryKey(id string) string {
+	return "otp:delivery:" + id
+}
+
+func otpDeliveryRefKey(provider, messageID string) string {
+	return "otp:delivery:ref:" + provider + ":" + messageID
+}
+
+func (r *redisOTPDeliveryRepository) Save(ctx context.Context, delivery *model.OTPDelivery, ttl time.Duration) error {
+	data, err := json.Marshal(storedOTPDelivery{
+		OTPDelivery:       *delivery,
+		Phone:             delivery.Phone,
+		ProviderMessageID: delivery.ProviderMessageID,
+		Error:             delivery.Error,
+	})
+	if err != nil {
+		return apperr.NewInternal("failed to marshal OTP delivery", err)
+	}
+
+	pipe := r.rdb.TxPipeline()
+	pipe.Set(ctx, otpDeliveryKey(delivery.ID), data, ttl)
+	if delivery.ProviderMessageID != "" {
+		pipe.Set(ctx, otpDeliveryRefKey(delivery.Provider, delivery.ProviderMessageID), delivery.ID, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return apperr.NewInternal("failed to store OTP delivery", err)
+	}
+	return nil
+}
+
+func (r *redisOTPDeliveryRepository) Get(ctx context.Context, id string) (*model.OTPDelivery, error) {
+	data, err := r.rdb.Get(ctx, otpDeliveryKey(id)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, apperr.NewInternal("failed to get OTP delivery", err)
+	}
+	var stored storedOTPDelivery
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, apperr.NewInternal("failed to unmarshal OTP delivery", err)
+	}
+	delivery := stored.OTPDelivery
+	delivery.Phone = stored.Phone
+	delivery.ProviderMessageID = stored.ProviderMessageID
+	delivery.Error = stored.Error
+	return &delivery, nil
+}
+
+func (r *redisOTPDeliveryRepository) GetByProviderMessageID(ctx context.Context, provider, messageID string) (*model.OTPDelivery, error) {
+	id, err := r.rdb.Get(ctx, otpDeliveryRefKey(provider, messageID)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, apperr.NewInternal("failed to look up OTP delivery", err)
+	}
+	return r.Get(ctx, id)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+)
+
+// OTPDeliveryRepository keeps OTP delivery records for status lookups and
+// provider receipts.
+type OTPDeliveryRepository interface {
+	// Save creates or overwrites a delivery and indexes it by the provider's
+	// message ID.
+	Save(ctx context.Context, delivery *model.OTPDelivery, ttl time.Duration) error
+	// Get returns nil, nil when the delivery is unknown or has expired.
+	Get(ctx context.Context, id string) (*model.OTPDelivery, error)
+	// GetByProviderMessageID returns nil, nil when no delivery matches.
+	GetByProviderMessageID(ctx context.Context, provider, messageID string) (*model.OTPDelivery, error)
+}
@@ -75,3 +75,82 @@ func (r *redisOTPRepository) IncrementAttempts(ctx context.Context, phone string
 func (r *redisOTPRepository) Delete(ctx context.Context, phone string) error {
 	return r.rdb.Del(ctx, r.key(phone)).Err()
 }
+
+func (r *redisOTPRepository) cooldownKey(phone string) string {
+	return "otp:cooldown:" + phone
+}
+
+func (r *redisOTPRepository) sendsKey(phone string) string {
+	return "otp:sends:" + phone
+}
+
+// otpCooldownRetries bounds how often AcquireCooldown retries when a
+// concurrent send changes the count or the cooldown under it.
+const otpCooldownRetries = 5
+
+func (r *redisOTPRepository) AcquireCooldown(ctx context.Context, phone string, cooldownAfter func(n int) time.Duration) (bool, time.Duration, error) {
+	sendsKey, cooldownKey := r.sendsKey(phone), r.cooldownKey(phone)
+
+	for i := 0; i < otpCooldownRetries; i++ {
+		var acquired bool
+		var d time.Duration
+		err := r.rdb.Watch(ctx, func(tx *redis.Tx) error {
+			remaining, err := tx.PTTL(ctx, cooldownKey).Result()
+			if err != nil {
+				return err
+			}
+			if remaining > 0 {
+				d = remaining
+				return nil
+			}
+
+			sent, err := tx.Get(ctx, sendsKey).Int()
+			if err != nil && err != redis.Nil {
+				return err
+			}
+			d = cooldownAfter(sent + 1)
+			if d <= 0 {
+				acquired = true
+				return nil
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, cooldownKey, 1, d)
+				return nil
+			})
+			acquired = err == nil
+			return err
+		}, sendsKey, cooldownKey)
+		if err == redis.TxFailedErr {
+			continue
+		}
+		if err != nil {
+			return false, 0, apperr.NewInternal("failed to claim OTP cooldown", err)
+		}
+		return acquired, d, nil
+	}
+	return false, 0, apperr.NewInternal("failed to claim OTP cooldown", redis.TxFailedErr)
+}
+
+func (r *redisOTPRepository) RecordSend(ctx context.Context, phone string, window time.Duration) error {
+	pipe := r.rdb.TxPipeline()
+	pipe.Incr(ctx, r.sendsKey(phone))
+	pipe.Expire(ctx, r.sendsKey(phone), window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return apperr.NewInternal("failed to record OTP send", err)
+	}
+	return nil
+}
+
+func (r *redisOTPRepository) ReleaseCooldown(ctx context.Context, phone string) error {
+	if err := r.rdb.Del(ctx, r.cooldownKey(phone)).Err(); err != nil {
+		return apperr.NewInternal("failed to release OTP cooldown", err)
+	}
+	return nil
+}
+
+func (r *redisOTPRepository) ResetSends(ctx context.Context, phone string) error {
+	if err := r.rdb.Del(ctx, r.cooldownKey(phone), r.sendsKey(phone)).Err(); err != nil {
+		return apperr.NewInternal("failed to reset OTP sends", err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/whatsapp-clone/backend/auth-service/internal/model"
 )
@@ -11,4 +12,17 @@ type OTPRepository interface {
 	Get(ctx context.Context, phone string) (*model.OTPEntry, error)
 	IncrementAttempts(ctx context.Context, phone string) (int, error)
 	Delete(ctx context.Context, phone string) error
+
+	// AcquireCooldown claims the right to send an OTP to phone and blocks
+	// further sends for cooldownAfter(n), where n counts this send among
+	// those within the current resend window. The count is read and the
+	// cooldown set atomically. It returns true and the cooldown set, or, if
+	// a cooldown is already running, false and the time left.
+	AcquireCooldown(ctx context.Context, phone string, cooldownAfter func(n int) time.Duration) (bool, time.Duration, error)
+	// RecordSend counts a successful send; the count expires after window.
+	RecordSend(ctx context.Context, phone string, window time.Duration) error
+	// ReleaseCooldown lifts a running cooldown, e.g. after a failed delivery.
+	ReleaseCooldown(ctx context.Context, phone string) error
+	// ResetSends clears the cooldown and send count.
+	ResetSends(ctx context.Context, phone string) error
 }
@@ -19,9 +19,9 @@ func NewPostgresRefreshTokenRepository(pool *pgxpool.Pool) RefreshTokenRepositor
 
 func (r *postgresRefreshTokenRepository) Create(ctx context.Context, token *model.RefreshToken) error {
 	_, err := r.pool.Exec(ctx, `
-		INSERT INTO refresh_tokens (user_id, token_hash, expires_at)
-		VALUES ($1, $2, $3)
-	`, token.UserID, token.TokenHash, token.ExpiresAt)
+		INSERT INTO refresh_tokens (user_id, token_hash, expires_at, device_id)
+		VALUES ($1, $2, $3, NULLIF($4, '')::uuid)
+	`, token.UserID, token.TokenHash, token.ExpiresAt, token.DeviceID)
 	if err != nil {
 		return apperr.NewInternal("failed to create refresh token", err)
 	}
@@ -31,11 +31,30 @@ func (r *postgresRefreshTokenRepository) Create(ctx context.Context, token *mode
 func (r *postgresRefreshTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
 	var token model.RefreshToken
 	err := r.pool.QueryRow(ctx, `
-		SELECT id, user_id, token_hash, expires_at, revoked, created_at
+		SELECT id, user_id, COALESCE(device_id::text, ''), token_hash, expires_at, revoked, created_at
 		FROM refresh_tokens
 		WHERE token_hash = $1 AND revoked = FALSE AND expires_at > NOW()
 	`, tokenHash).Scan(
-		&token.ID, &token.UserID, &token.TokenHash,
+		&token.ID, &token.UserID, &token.DeviceID, &token.TokenHash,
+		&token.ExpiresAt, &token.Revoked, &token.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, apperr.NewInternal("failed to get refresh token", err)
+	}
+	return &token, nil
+}
+
+func (r *postgresRefreshTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, user_id, COALESCE(device_id::text, ''), token_hash, expires_at, revoked, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`, tokenHash).Scan(
+		&token.ID, &token.UserID, &token.DeviceID, &token.TokenHash,
 		&token.ExpiresAt, &token.Revoked, &token.CreatedAt,
 	)
 	if err != nil {
@@ -55,6 +74,30 @@ func (r *postgresRefreshTokenRepository) RevokeByID(ctx context.Context, id stri
 	return nil
 }
 
+func (r *postgresRefreshTokenRepository) ListActiveIDsByDeviceID(ctx context.Context, deviceID string) ([]string, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id FROM refresh_tokens
+		WHERE device_id = $1 AND revoked = FALSE AND expires_at > NOW()
+	`, deviceID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to list device refresh tokens", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, apperr.NewInternal("failed to scan refresh token id", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.NewInternal("failed to list device refresh tokens", err)
+	}
+	return ids, nil
+}
+
 func (r *postgresRefreshTokenRepository) RevokeAllByUserID(ctx context.Context, userID string) error {
 	_, err := r.pool.Exec(ctx, `UPDATE refresh_tokens SET revoked = TRUE WHERE user_id = $1`, userID)
 	if err != nil {
@@ -63,6 +106,17 @@ func (r *postgresRefreshTokenRepository) RevokeAllByUserID(ctx context.Context,
 	return nil
 }
 
+func (r *postgresRefreshTokenRepository) RevokeUnboundByUserID(ctx context.Context, userID string) (int, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE refresh_tokens SET revoked = TRUE
+		WHERE user_id = $1 AND device_id IS NULL AND revoked = FALSE AND expires_at > NOW()
+	`, userID)
+	if err != nil {
+		return 0, apperr.NewInternal("failed to revoke refresh tokens", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
 func (r *postgresRefreshTokenRepository) ReplaceToken(ctx context.Context, oldID string, newToken *model.RefreshToken) error {
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
@@ -70,14 +124,18 @@ func (r *postgresRefreshTokenRepository) ReplaceToken(ctx context.Context, oldID
 	}
 	defer tx.Rollback(ctx)
 
-	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET revoked = TRUE WHERE id = $1`, oldID); err != nil {
+	tag, err := tx.Exec(ctx, `UPDATE refresh_tokens SET revoked = TRUE WHERE id = $1 AND revoked = FALSE`, oldID)
+	if err != nil {
 		return apperr.NewInternal("failed to revoke old refresh token", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return ErrRefreshTokenRevoked
+	}
 
 	if _, err := tx.Exec(ctx, `
-		INSERT INTO refresh_tokens (user_id, token_hash, expires_at)
-		VALUES ($1, $2, $3)
-	`, newToken.UserID, newToken.TokenHash, newToken.ExpiresAt); err != nil {
+		INSERT INTO refresh_tokens (user_id, token_hash, expires_at, device_id)
+		VALUES ($1, $2, $3, NULLIF($4, '')::uuid)
+	`, newToken.UserID, newToken.TokenHash, newToken.ExpiresAt, newToken.DeviceID); err != nil {
 		return apperr.NewInternal("failed to create new refresh token", err)
 	}
 
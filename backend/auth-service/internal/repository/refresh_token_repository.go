@@ -2,16 +2,32 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/whatsapp-clone/backend/auth-service/internal/model"
 )
 
+// ErrRefreshTokenRevoked is returned by ReplaceToken when the old token was
+// revoked before the replacement committed, e.g. by a concurrent refresh.
+var ErrRefreshTokenRevoked = errors.New("refresh token already revoked")
+
 type RefreshTokenRepository interface {
 	Create(ctx context.Context, token *model.RefreshToken) error
 	GetByTokenHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	// FindByTokenHash returns the token whether or not it has been revoked
+	// or has expired, so callers can recognise a rotated token being reused.
+	FindByTokenHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
 	RevokeByID(ctx context.Context, id string) error
 	RevokeAllByUserID(ctx context.Context, userID string) error
+	// RevokeUnboundByUserID revokes the user's live refresh tokens that
+	// belong to no device, issued before devices were tracked, and returns
+	// how many there were.
+	RevokeUnboundByUserID(ctx context.Context, userID string) (int, error)
+	// ListActiveIDsByDeviceID returns the IDs of a device's unrevoked,
+	// unexpired refresh tokens.
+	ListActiveIDsByDeviceID(ctx context.Context, deviceID string) ([]string, error)
 	// ReplaceToken atomically revokes the old token and creates a new one
-	// within a single database transaction.
+	// within a single database transaction. It fails with
+	// ErrRefreshTokenRevoked if the old token is no longer active.
 	ReplaceToken(ctx context.Context, oldID string, newToken *model.RefreshToken) error
 }
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+)
+
+type postgresSecurityEventRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresSecurityEventRepository(pool *pgxpool.Pool) SecurityEventRepository {
+	return &postgresSecurityEventRepository{pool: pool}
+}
+
+func (r *postgresSecurityEventRepository) Create(ctx context.Context, event *model.SecurityEvent) error {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO security_events (user_id, type, device_id, ip, user_agent)
+		VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5)
+		RETURNING id, created_at
+	`, event.UserID, event.Type, event.DeviceID, event.IP, event.UserAgent,
+	).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return apperr.NewInternal("failed to record security event", err)
+	}
+	return nil
+}
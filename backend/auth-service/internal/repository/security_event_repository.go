@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+)
+
+type SecurityEventRepository interface {
+	Create(ctx context.Context, event *model.SecurityEvent) error
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+)
+
+type postgresTwoStepRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresTwoStepRepository(pool *pgxpool.Pool) TwoStepRepository {
+	return &postgresTwoStepRepository{pool: pool}
+}
+
+func (r *postgresTwoStepRepository) Get(ctx context.Context, userID string) (*model.TwoStep, error) {
+	var ts model.TwoStep
+	err := r.pool.QueryRow(ctx, `
+		SELECT user_id, pin_hash, recovery_email, created_at, updated_at
+		FROM two_step_verification
+		WHERE user_id = $1
+	`, userID).Scan(&ts.UserID, &ts.PINHash, &ts.RecoveryEmail, &ts.CreatedAt, &ts.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, apperr.NewInternal("failed to get two-step verification", err)
+	}
+	return &ts, nil
+}
+
+func (r *postgresTwoStepRepository) Upsert(ctx context.Context, ts *model.TwoStep) error {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO two_step_verification (user_id, pin_hash, recovery_email)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET pin_hash = EXCLUDED.pin_hash,
+		    recovery_email = EXCLUDED.recovery_email,
+		    updated_at = NOW()
+		RETURNING created_at, updated_at
+	`, ts.UserID, ts.PINHash, ts.RecoveryEmail).Scan(&ts.CreatedAt, &ts.UpdatedAt)
+	if err != nil {
+		return apperr.NewInternal("failed to save two-step verification", err)
+	}
+	return nil
+}
+
+func (r *postgresTwoStepRepository) Delete(ctx context.Context, userID string) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM two_step_verification WHERE user_id = $1`, userID)
+	if err != nil {
+		return false, apperr.NewInternal("failed to disable two-step verification", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
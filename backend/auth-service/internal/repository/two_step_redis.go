@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+)
+
+type redisTwoStepStateRepository struct {
+	rdb *redis.Client
+}
+
+func NewRedisTwoStepStateRepository(rdb *redis.Client) TwoStepStateRepository {
+	return &redisTwoStepStateRepository{rdb: rdb}
+}
+
+func pinChallengeKey(tokenHash string) string {
+	return "pin:challenge:" + tokenHash
+}
+
+func pinResetKey(tokenHash string) string {
+	return "pin:reset:" + tokenHash
+}
+
+func pinAttemptsKey(userID string) string {
+	return "pin:attempts:" + userID
+}
+
+func pinLockKey(userID string) string {
+	return "pin:lock:" + userID
+}
+
+func (r *redisTwoStepStateRepository) StoreChallenge(ctx context.Context, tokenHash string, challenge *model.PINChallenge, ttl time.Duration) error {
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return apperr.NewInternal("failed to marshal PIN challenge", err)
+	}
+	if err := r.rdb.Set(ctx, pinChallengeKey(tokenHash), data, ttl).Err(); err != nil {
+		return apperr.NewInternal("failed to store PIN challenge", err)
+	}
+	return nil
+}
+
+func (r *redisTwoStepStateRepository) GetChallenge(ctx context.Context, tokenHash string) (*model.PINChallenge, error) {
+	data, err := r.rdb.Get(ctx, pinChallengeKey(tokenHash)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, apperr.NewInternal("failed to get PIN challenge", err)
+	}
+	var challenge model.PINChallenge
+	if err := json.Unmarshal(data, &challenge); err != nil {
+		return nil, apperr.NewInternal("failed to unmarshal PIN challenge", err)
+	}
+	return &challenge, nil
+}
+
+func (r *redisTwoStepStateRepository) DeleteChallenge(ctx context.Context, tokenHash string) (bool, error) {
+	pipe := r.rdb.TxPipeline()
+	del := pipe.Del(ctx, pinChallengeKey(tokenHash))
+	pipe.Del(ctx, pinResetKey(tokenHash))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, apperr.NewInternal("failed to delete PIN challenge", err)
+	}
+	return del.Val() > 0, nil
+}
+
+func (r *redisTwoStepStateRepository) StoreResetCode(ctx context.Context, tokenHash, codeHash string, ttl time.Duration) (bool, error) {
+	ok, err := r.rdb.SetNX(ctx, pinResetKey(tokenHash), codeHash, ttl).Result()
+	if err != nil {
+		return false, apperr.NewInternal("failed to store PIN reset code", err)
+	}
+	return ok, nil
+}
+
+func (r *redisTwoStepStateRepository) GetResetCode(ctx context.Context, tokenHash string) (string, error) {
+	codeHash, err := r.rdb.Get(ctx, pinResetKey(tokenHash)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", nil
+		}
+		return "", apperr.NewInternal("failed to get PIN reset code", err)
+	}
+	return codeHash, nil
+}
+
+func (r *redisTwoStepStateRepository) RecordAttempt(ctx context.Context, userID string, window time.Duration) (int, error) {
+	pipe := r.rdb.TxPipeline()
+	incr := pipe.Incr(ctx, pinAttemptsKey(userID))
+	pipe.ExpireNX(ctx, pinAttemptsKey(userID), window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, apperr.NewInternal("failed to record PIN attempt", err)
+	}
+	return int(incr.Val()), nil
+}
+
+func (r *redisTwoStepStateRepository) ClearAttempts(ctx context.Context, userID string) error {
+	if err := r.rdb.Del(ctx, pinAttemptsKey(userID)).Err(); err != nil {
+		return apperr.NewInternal("failed to clear PIN attempts", err)
+	}
+	return nil
+}
+
+func (r *redisTwoStepStateRepository) Lock(ctx context.Context, userID string, d time.Duration) error {
+	if err := r.rdb.Set(ctx, pinLockKey(userID), 1, d).Err(); err != nil {
+		return apperr.NewInternal("failed to lock PIN entry", err)
+	}
+	return nil
+}
+
+func (r *redisTwoStepStateRepository) LockedFor(ctx context.Context, userID string) (time.Duration, error) {
+	ttl, err := r.rdb.PTTL(ctx, pinLockKey(userID)).Result()
+	if err != nil {
+		return 0, apperr.NewInternal("failed to get PIN lock", err)
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
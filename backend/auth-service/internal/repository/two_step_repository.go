@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+)
+
+type TwoStepRepository interface {
+	// Get returns nil, nil when the user has no two-step PIN.
+	Get(ctx context.Context, userID string) (*model.TwoStep, error)
+	// Upsert sets the PIN hash and recovery email, enabling two-step
+	// verification if it was off.
+	Upsert(ctx context.Context, twoStep *model.TwoStep) error
+	// Delete turns two-step verification off. It reports false if it was
+	// not on.
+	Delete(ctx context.Context, userID string) (bool, error)
+}
+
+// TwoStepStateRepository holds pending PIN logins, their PIN reset codes and
+// PIN attempt counters.
+type TwoStepStateRepository interface {
+	StoreChallenge(ctx context.Context, tokenHash string, challenge *model.PINChallenge, ttl time.Duration) error
+	// GetChallenge returns nil, nil when the token is unknown or expired.
+	GetChallenge(ctx context.Context, tokenHash string) (*model.PINChallenge, error)
+	// DeleteChallenge consumes a challenge and its reset code. It reports
+	// false if it was already gone, e.g. used by a concurrent request.
+	DeleteChallenge(ctx context.Context, tokenHash string) (bool, error)
+	// StoreResetCode keeps the hash of a PIN reset code for a challenge. It
+	// reports false if a code was already sent for it.
+	StoreResetCode(ctx context.Context, tokenHash, codeHash string, ttl time.Duration) (bool, error)
+	// GetResetCode returns "" when no reset code was sent for the challenge.
+	GetResetCode(ctx context.Context, tokenHash string) (string, error)
+
+	// RecordAttempt counts a PIN attempt and returns the attempts within
+	// window, including this one.
+	RecordAttempt(ctx context.Context, userID string, window time.Duration) (int, error)
+	ClearAttempts(ctx context.Context, userID string) error
+	// Lock blocks PIN entry for the user for d.
+	Lock(ctx context.Context, userID string, d time.Duration) error
+	// LockedFor returns how long PIN entry stays blocked, or 0.
+	LockedFor(ctx context.Context, userID string) (time.Duration, error)
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+	"github.com/whatsapp-clone/backend/pkg/deviceevents"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	"github.com/whatsapp-clone/backend/pkg/validator"
+)
+
+const (
+	// linkQRPrefix is prepended to a link code to form the QR payload. The
+	// link endpoint accepts either form.
+	linkQRPrefix = "whatsapp-clone://link-device?code="
+
+	// deviceActivityInterval bounds how often token validation writes a
+	// device's last-active time.
+	deviceActivityInterval = time.Minute
+)
+
+func (s *authServiceImpl) CreateLinkCode(ctx context.Context, userID, deviceID string) (*model.LinkCode, error) {
+	if err := s.checkLinkedLimit(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	code, err := generateOpaqueToken(16)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to generate link code", err)
+	}
+
+	link := &model.PendingLink{UserID: userID, IssuedBy: deviceID}
+	if err := s.deviceState.StoreLinkCode(ctx, sha256Hash(code), link, s.linkCodeTTL); err != nil {
+		return nil, err
+	}
+
+	s.log.Info().Str("user_id", userID).Str("device_id", deviceID).Msg("device link code issued")
+
+	return &model.LinkCode{
+		Code:             code,
+		QRPayload:        linkQRPrefix + code,
+		ExpiresInSeconds: int64(s.linkCodeTTL.Seconds()),
+	}, nil
+}
+
+func (s *authServiceImpl) LinkDevice(ctx context.Context, code string, info model.DeviceInfo) (*model.AuthResult, error) {
+	code = strings.TrimPrefix(strings.TrimSpace(code), linkQRPrefix)
+	invalid := apperr.Wrap(apperr.CodeLinkCodeInvalid, 400, "link code is invalid or expired", nil)
+	if code == "" {
+		return nil, invalid
+	}
+
+	link, err := s.deviceState.ConsumeLinkCode(ctx, sha256Hash(code))
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, invalid
+	}
+
+	// A code shown by a device that has since been logged out is void.
+	if link.IssuedBy != "" {
+		issuer, err := s.deviceRepo.GetByID(ctx, link.IssuedBy)
+		if err != nil {
+			return nil, err
+		}
+		if issuer == nil || issuer.RevokedAt != nil {
+			return nil, invalid
+		}
+	}
+
+	if err := s.checkLinkedLimit(ctx, link.UserID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, link.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	device, err := s.registerDevice(ctx, user.ID, info, false, link.IssuedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	pair, err := s.issueTokenPair(ctx, user.ID, user.Phone, device.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishDeviceEvent(ctx, user.ID, "device.linked", device)
+	s.log.Info().Str("user_id", user.ID).Str("device_id", device.ID).Str("linked_by", link.IssuedBy).Msg("device linked")
+
+	return &model.AuthResult{
+		AccessToken:      pair.AccessToken,
+		RefreshToken:     pair.RefreshToken,
+		ExpiresIn:        pair.ExpiresIn,
+		ExpiresInSeconds: pair.ExpiresIn,
+		User:             user,
+		DeviceID:         device.ID,
+	}, nil
+}
+
+func (s *authServiceImpl) ListDevices(ctx context.Context, userID, currentDeviceID string) ([]*model.Device, error) {
+	devices, err := s.deviceRepo.ListActiveByUserID(ctx, userID, time.Now().Add(-s.refreshTTL))
+	if err != nil {
+		return nil, err
+	}
+	if devices == nil {
+		devices = []*model.Device{}
+	}
+	for _, d := range devices {
+		d.Current = d.ID == currentDeviceID
+	}
+	return devices, nil
+}
+
+func (s *authServiceImpl) RevokeDevice(ctx context.Context, userID, deviceID string) error {
+	if !validator.IsValidUUID(deviceID) {
+		return apperr.NewNotFound("device not found")
+	}
+	device, err := s.deviceRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	if device == nil || device.UserID != userID || device.RevokedAt != nil {
+		return apperr.NewNotFound("device not found")
+	}
+
+	if _, err := s.revokeDevice(ctx, userID, deviceID); err != nil {
+		return err
+	}
+
+	s.log.Info().Str("user_id", userID).Str("device_id", deviceID).Msg("device logged out remotely")
+	return nil
+}
+
+// revokeDevice logs a device out. Its access tokens are blocked first so
+// nothing it still holds works once its refresh tokens are gone; the
+// device.revoked event then tells websocket-service to drop its connections
+// and the user's other devices to refresh their device lists. Other services
+// hear of it on the DEVICES stream, which is published to on every call so
+// that retrying a revocation that failed there catches up. It reports
+// whether this call did the revoking, as opposed to finding the device
+// already logged out.
+func (s *authServiceImpl) revokeDevice(ctx context.Context, userID, deviceID string) (bool, error) {
+	revoked, err := s.deviceRepo.Revoke(ctx, deviceID)
+	if err != nil {
+		return false, err
+	}
+	if err := s.deviceState.MarkRevoked(ctx, deviceID, s.accessTTL); err != nil {
+		return false, err
+	}
+
+	ids, err := s.refreshRepo.ListActiveIDsByDeviceID(ctx, deviceID)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range ids {
+		if err := s.refreshRepo.RevokeByID(ctx, id); err != nil {
+			return false, err
+		}
+	}
+
+	if err := deviceevents.Publish(s.js, &deviceevents.Revoked{UserID: userID, DeviceID: deviceID, RevokedAt: time.Now()}); err != nil {
+		return false, apperr.NewInternal("failed to announce device revocation", err)
+	}
+	if revoked {
+		s.publishDeviceEvent(ctx, userID, "device.revoked", map[string]string{"device_id": deviceID})
+	}
+	return revoked, nil
+}
+
+func (s *authServiceImpl) registerDevice(ctx context.Context, userID string, info model.DeviceInfo, primary bool, linkedBy string) (*model.Device, error) {
+	client := normalizeClientInfo(info.ClientInfo)
+	device := &model.Device{
+		UserID:    userID,
+		Platform:  normalizePlatform(info.Platform),
+		IsPrimary: primary,
+		LinkedBy:  linkedBy,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	}
+	device.Name = normalizeDeviceName(info.Name, device.Platform)
+	if err := s.deviceRepo.Create(ctx, device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+func (s *authServiceImpl) checkLinkedLimit(ctx context.Context, userID string) error {
+	if s.maxLinked <= 0 {
+		return nil
+	}
+	n, err := s.deviceRepo.CountLinked(ctx, userID, time.Now().Add(-s.refreshTTL))
+	if err != nil {
+		return err
+	}
+	if n >= s.maxLinked {
+		return apperr.NewConflict(fmt.Sprintf("at most %d linked devices are allowed; log one out first", s.maxLinked))
+	}
+	return nil
+}
+
+// touchDevice records device activity, at most once per interval.
+func (s *authServiceImpl) touchDevice(ctx context.Context, deviceID string) {
+	due, err := s.deviceState.ClaimActivity(ctx, deviceID, deviceActivityInterval)
+	if err != nil || !due {
+		return
+	}
+	if err := s.deviceRepo.Touch(ctx, deviceID); err != nil {
+		s.log.Warn().Err(err).Str("device_id", deviceID).Msg("failed to update device activity")
+	}
+}
+
+// publishDeviceEvent sends a transient event to all of the user's websocket
+// connections.
+func (s *authServiceImpl) publishDeviceEvent(ctx context.Context, userID, event string, payload any) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	name, _ := json.Marshal(event)
+	data, _ := json.Marshal(map[string]json.RawMessage{
+		"event": name,
+		"data":  body,
+	})
+	if err := s.rdb.Publish(ctx, "user:channel:"+userID, data).Err(); err != nil {
+		s.log.Warn().Err(err).Str("user_id", userID).Str("event", event).Msg("failed to publish device event")
+	}
+}
+
+func normalizeClientInfo(client model.ClientInfo) model.ClientInfo {
+	if len(client.UserAgent) > model.UserAgentMaxLen {
+		client.UserAgent = strings.ToValidUTF8(client.UserAgent[:model.UserAgentMaxLen], "")
+	}
+	return client
+}
+
+func normalizePlatform(platform string) string {
+	switch p := strings.ToLower(strings.TrimSpace(platform)); p {
+	case model.PlatformAndroid, model.PlatformIOS, model.PlatformWeb, model.PlatformDesktop:
+		return p
+	default:
+		return model.PlatformOther
+	}
+}
+
+func normalizeDeviceName(name, platform string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		switch platform {
+		case model.PlatformAndroid:
+			return "Android"
+		case model.PlatformIOS:
+			return "iPhone"
+		case model.PlatformWeb:
+			return "Web browser"
+		case model.PlatformDesktop:
+			return "Desktop"
+		default:
+			return "Unknown device"
+		}
+	}
+	if r := []rune(name); len(r) > model.DeviceNameMaxLen {
+		name = string(r[:model.DeviceNameMaxLen])
+	}
+	return name
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+)
+
+// otpDeliveryRetention is how long delivery records are kept for status
+// lookups and late provider receipts.
+const otpDeliveryRetention = time.Hour
+
+// OTPResendPolicy spaces out codes sent to one number. After the n-th send
+// within Window the next one has to wait Cooldown·2^(n-1), capped at
+// MaxCooldown. A zero Cooldown disables the limit.
+type OTPResendPolicy struct {
+	Cooldown    time.Duration
+	MaxCooldown time.Duration
+	Window      time.Duration
+}
+
+func (p OTPResendPolicy) cooldownAfter(n int) time.Duration {
+	if p.Cooldown <= 0 {
+		return 0
+	}
+	d := p.Cooldown
+	for i := 1; i < n && (p.MaxCooldown <= 0 || d < p.MaxCooldown); i++ {
+		d *= 2
+	}
+	if p.MaxCooldown > 0 && d > p.MaxCooldown {
+		d = p.MaxCooldown
+	}
+	return d
+}
+
+// otpReceiptStatuses maps the statuses providers report to ours.
+var otpReceiptStatuses = map[string]string{
+	"queued":      model.OTPStatusSent,
+	"accepted":    model.OTPStatusSent,
+	"sending":     model.OTPStatusSent,
+	"sent":        model.OTPStatusSent,
+	"delivered":   model.OTPStatusDelivered,
+	"answered":    model.OTPStatusDelivered,
+	"completed":   model.OTPStatusDelivered,
+	"failed":      model.OTPStatusFailed,
+	"undelivered": model.OTPStatusFailed,
+	"rejected":    model.OTPStatusFailed,
+	"expired":     model.OTPStatusFailed,
+	"busy":        model.OTPStatusFailed,
+	"no-answer":   model.OTPStatusFailed,
+}
+
+func (s *authServiceImpl) SendOTP(ctx context.Context, phone, channel string) (*model.OTPSendResult, error) {
+	channel = strings.ToLower(strings.TrimSpace(channel))
+	if channel != "" && channel != model.OTPChannelSMS && channel != model.OTPChannelVoice {
+		return nil, apperr.NewBadRequest("channel must be sms or voice")
+	}
+
+	attempts := s.otpRouter.plan(phone, channel)
+	if len(attempts) == 0 {
+		if channel != "" {
+			return nil, apperr.NewBadRequest(channel + " delivery is not available for this number")
+		}
+		return nil, apperr.NewBadRequest("OTP delivery is not available for this number")
+	}
+
+	ok, cooldown, err := s.otpRepo.AcquireCooldown(ctx, phone, s.resend.cooldownAfter)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		wait := int64(math.Ceil(cooldown.Seconds()))
+		return nil, apperr.Wrap(apperr.CodeOTPResendTooSoon, 429,
+			fmt.Sprintf("wait %d seconds before requesting another code", wait), nil)
+	}
+
+	otp, err := s.newOTP(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveryID, err := generateOpaqueToken(16)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to generate delivery id", err)
+	}
+	delivery := &model.OTPDelivery{ID: deliveryID, Phone: phone, CreatedAt: time.Now()}
+	s.deliverOTP(ctx, delivery, attempts, otp)
+
+	if delivery.Status == model.OTPStatusFailed {
+		// Nothing reached the user, so neither the code nor the cooldown
+		// should stand in the way of another try.
+		_ = s.otpRepo.Delete(ctx, phone)
+		_ = s.otpRepo.ReleaseCooldown(ctx, phone)
+		if err := s.deliveryRepo.Save(ctx, delivery, otpDeliveryRetention); err != nil {
+			s.log.Warn().Err(err).Str("delivery_id", delivery.ID).Msg("failed to store OTP delivery")
+		}
+		return nil, apperr.Wrap(apperr.CodeOTPDeliveryFailed, 503, "the code could not be delivered; try again or use another channel", nil)
+	}
+
+	if err := s.otpRepo.RecordSend(ctx, phone, s.resend.Window); err != nil {
+		return nil, err
+	}
+	if err := s.deliveryRepo.Save(ctx, delivery, otpDeliveryRetention); err != nil {
+		return nil, err
+	}
+
+	s.log.Info().
+		Str("phone", maskPhone(phone)).
+		Str("delivery_id", delivery.ID).
+		Str("provider", delivery.Provider).
+		Str("channel", delivery.Channel).
+		Int("attempts", delivery.Attempts).
+		Msg("OTP generated and sent")
+
+	return &model.OTPSendResult{
+		Code:        otp,
+		Delivery:    delivery,
+		ExpiresIn:   s.otpTTL,
+		ResendAfter: cooldown,
+	}, nil
+}
+
+// newOTP generates a code for phone and stores its hash, replacing any
+// earlier one.
+func (s *authServiceImpl) newOTP(ctx context.Context, phone string) (string, error) {
+	otp, err := generateOTP(s.otpLength)
+	if err != nil {
+		return "", apperr.NewInternal("failed to generate OTP", err)
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(otp), bcrypt.DefaultCost)
+	if err != nil {
+		return "", apperr.NewInternal("failed to hash OTP", err)
+	}
+
+	entry := &model.OTPEntry{
+		HashedOTP: string(hashed),
+		Attempts:  0,
+	}
+
+	if err := s.otpRepo.Store(ctx, phone, entry); err != nil {
+		return "", err
+	}
+	return otp, nil
+}
+
+// deliverOTP tries each provider in turn until one accepts the code and
+// records the outcome on delivery.
+func (s *authServiceImpl) deliverOTP(ctx context.Context, delivery *model.OTPDelivery, attempts []otpAttempt, code string) {
+	msg := &model.OTPMessage{Phone: delivery.Phone, Code: code, TTL: s.otpTTL}
+	delivery.Status = model.OTPStatusFailed
+
+	for _, a := range attempts {
+		msg.Channel = a.channel
+		delivery.Attempts++
+		delivery.Provider = a.sender.Name()
+		delivery.Channel = a.channel
+
+		messageID, err := a.sender.Send(ctx, msg)
+		if err != nil {
+			delivery.Error = err.Error()
+			s.log.Warn().Err(err).
+				Str("phone", maskPhone(delivery.Phone)).
+				Str("provider", delivery.Provider).
+				Str("channel", delivery.Channel).
+				Msg("OTP delivery attempt failed")
+			continue
+		}
+
+		delivery.ProviderMessageID = messageID
+		delivery.Status = model.OTPStatusSent
+		delivery.Error = ""
+		break
+	}
+	delivery.UpdatedAt = time.Now()
+}
+
+func (s *authServiceImpl) GetOTPDelivery(ctx context.Context, id string) (*model.OTPDelivery, error) {
+	delivery, err := s.deliveryRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, apperr.NewNotFound("OTP delivery not found")
+	}
+	return delivery, nil
+}
+
+func (s *authServiceImpl) RecordOTPReceipt(ctx context.Context, provider string, receipt model.OTPDeliveryReceipt) error {
+	status, ok := otpReceiptStatuses[strings.ToLower(strings.TrimSpace(receipt.Status))]
+	if !ok {
+		return apperr.NewBadRequest("unknown delivery status " + receipt.Status)
+	}
+
+	delivery, err := s.deliveryRepo.GetByProviderMessageID(ctx, provider, receipt.MessageID)
+	if err != nil {
+		return err
+	}
+	if delivery == nil {
+		return apperr.NewNotFound("OTP delivery not found")
+	}
+
+	// Receipts may arrive out of order; a final status is never overwritten,
+	// nor is a later attempt's by one for a message it replaced.
+	if delivery.Status != model.OTPStatusSent || status == model.OTPStatusSent ||
+		delivery.Provider != provider || delivery.ProviderMessageID != receipt.MessageID {
+		return nil
+	}
+
+	delivery.Status = status
+	delivery.Error = receipt.Error
+	delivery.UpdatedAt = time.Now()
+	if err := s.deliveryRepo.Save(ctx, delivery, otpDeliveryRetention); err != nil {
+		return err
+	}
+
+	if status != model.OTPStatusFailed {
+		return nil
+	}
+	s.log.Warn().
+		Str("phone", maskPhone(delivery.Phone)).
+		Str("delivery_id", delivery.ID).
+		Str("provider", provider).
+		Str("error", receipt.Error).
+		Msg("provider reported OTP delivery failure")
+
+	resent, err := s.redeliverOTP(ctx, delivery)
+	if err != nil || resent {
+		return err
+	}
+	// Let the user ask again straight away, e.g. for a voice call.
+	return s.otpRepo.ReleaseCooldown(ctx, delivery.Phone)
+}
+
+// redeliverOTP sends a new code through the providers the number's route
+// lists after the one that reported the delivery failed, e.g. a voice call
+// after an SMS the carrier rejected. The failed code never arrived, so it is
+// replaced. It reports whether another provider took the new code.
+func (s *authServiceImpl) redeliverOTP(ctx context.Context, delivery *model.OTPDelivery) (bool, error) {
+	attempts := s.otpRouter.fallbacks(delivery.Phone, delivery.Provider, delivery.Channel)
+	if len(attempts) == 0 {
+		return false, nil
+	}
+	// Once the user has logged in, or the code expired, there is nothing to
+	// deliver any more.
+	entry, err := s.otpRepo.Get(ctx, delivery.Phone)
+	if err != nil || entry == nil {
+		return false, err
+	}
+
+	otp, err := s.newOTP(ctx, delivery.Phone)
+	if err != nil {
+		return false, err
+	}
+	s.deliverOTP(ctx, delivery, attempts, otp)
+	if err := s.deliveryRepo.Save(ctx, delivery, otpDeliveryRetention); err != nil {
+		return false, err
+	}
+	if delivery.Status != model.OTPStatusSent {
+		_ = s.otpRepo.Delete(ctx, delivery.Phone)
+		return false, nil
+	}
+
+	s.log.Info().
+		Str("phone", maskPhone(delivery.Phone)).
+		Str("delivery_id", delivery.ID).
+		Str("provider", delivery.Provider).
+		Str("channel", delivery.Channel).
+		Msg("OTP resent after a failed delivery")
+	return true, nil
+}
@@ -4,12 +4,67 @@ import (
 	"context"
 
 	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+	"github.com/whatsapp-clone/backend/pkg/jwt"
 )
 
 type AuthService interface {
-	SendOTP(ctx context.Context, phone string) (otp string, err error)
-	VerifyOTP(ctx context.Context, phone, code string) (*model.AuthResult, error)
-	RefreshTokens(ctx context.Context, refreshToken string) (*model.TokenPair, error)
+	// SendOTP generates a code for phone and delivers it over channel ("sms",
+	// "voice" or "" to let routing decide). Sends to one number are spaced
+	// out by exponentially growing cooldowns.
+	SendOTP(ctx context.Context, phone, channel string) (*model.OTPSendResult, error)
+	GetOTPDelivery(ctx context.Context, id string) (*model.OTPDelivery, error)
+	// RecordOTPReceipt applies a provider's delivery status callback.
+	RecordOTPReceipt(ctx context.Context, provider string, receipt model.OTPDeliveryReceipt) error
+	// VerifyOTP logs the user in and registers the device as primary. For
+	// accounts with two-step verification it returns a PIN challenge
+	// instead, which VerifyPIN completes.
+	VerifyOTP(ctx context.Context, phone, code string, device model.DeviceInfo) (*model.AuthResult, error)
+	// VerifyPIN finishes a two-step login. Wrong PINs are counted per
+	// account and lock PIN entry once the limit is reached.
+	VerifyPIN(ctx context.Context, pinToken, pin string) (*model.AuthResult, error)
+	// RequestPINReset emails a code to the recovery address of the account
+	// behind a pending PIN login, for users who forgot their PIN.
+	RequestPINReset(ctx context.Context, pinToken string) (*model.PINResetResult, error)
+	// ConfirmPINReset checks the emailed code, turns two-step verification
+	// off and finishes the login. Wrong codes count like wrong PINs.
+	ConfirmPINReset(ctx context.Context, pinToken, code string) (*model.AuthResult, error)
+	RefreshTokens(ctx context.Context, refreshToken string, client model.ClientInfo) (*model.TokenPair, error)
+	// Logout revokes the refresh token and logs out the device it belongs to.
 	Logout(ctx context.Context, refreshToken string) error
-	ValidateToken(ctx context.Context, token string) (userID string, phone string, err error)
+	// ValidateToken checks an access token and rejects tokens of devices that
+	// have been logged out.
+	ValidateToken(ctx context.Context, token string) (userID, phone, deviceID string, err error)
+	// JWKS returns the public keys access tokens are verified with.
+	JWKS() *jwt.JWKS
+
+	// CreateLinkCode issues a single-use code for the authenticated device to
+	// show as a QR code.
+	CreateLinkCode(ctx context.Context, userID, deviceID string) (*model.LinkCode, error)
+	// LinkDevice redeems a link code and logs the scanning device in as a
+	// secondary device of the code's owner.
+	LinkDevice(ctx context.Context, code string, device model.DeviceInfo) (*model.AuthResult, error)
+	ListDevices(ctx context.Context, userID, currentDeviceID string) ([]*model.Device, error)
+	// RevokeDevice logs out one of the user's devices: its refresh tokens are
+	// revoked, its access tokens stop validating and its websocket
+	// connections are closed.
+	RevokeDevice(ctx context.Context, userID, deviceID string) error
+
+	// ListSessions returns the user's active sessions, one per device.
+	ListSessions(ctx context.Context, userID, currentDeviceID string) ([]*model.Session, error)
+	// RevokeSession ends a session, logging its device out.
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	// RevokeOtherSessions ends every session but the caller's and returns
+	// how many were ended.
+	RevokeOtherSessions(ctx context.Context, userID, currentDeviceID string) (int, error)
+	// EraseUser logs every device of a deleted account out and revokes all
+	// of its refresh tokens.
+	EraseUser(ctx context.Context, userID string) error
+
+	// GetTwoStep reports whether the user has two-step verification on.
+	GetTwoStep(ctx context.Context, userID string) (*model.TwoStepStatus, error)
+	// SetTwoStep turns two-step verification on or changes the PIN and
+	// recovery email. Changes need the current PIN.
+	SetTwoStep(ctx context.Context, userID, currentPIN, pin, recoveryEmail string, client model.ClientInfo) (*model.TwoStepStatus, error)
+	// DisableTwoStep turns two-step verification off; it needs the PIN.
+	DisableTwoStep(ctx context.Context, userID, pin string, client model.ClientInfo) error
 }
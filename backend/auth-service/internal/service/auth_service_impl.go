@@ -5,10 +5,13 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
 
+	"github.com/nats-io/nats.go"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	"golang.org/x/crypto/bcrypt"
 
@@ -21,62 +24,85 @@ import (
 type authServiceImpl struct {
 	userRepo     repository.UserRepository
 	otpRepo      repository.OTPRepository
+	deliveryRepo repository.OTPDeliveryRepository
 	refreshRepo  repository.RefreshTokenRepository
+	deviceRepo   repository.DeviceRepository
+	deviceState  repository.DeviceStateRepository
+	securityRepo repository.SecurityEventRepository
+	twoStepRepo  repository.TwoStepRepository
+	twoStepState repository.TwoStepStateRepository
+	rdb          *redis.Client
+	js           nats.JetStreamContext
 	jwtManager   *jwt.Manager
+	otpRouter    *OTPRouter
+	resetMailer  PINResetMailer
+	accessTTL    time.Duration
 	refreshTTL   time.Duration
+	linkCodeTTL  time.Duration
+	maxLinked    int
 	otpLength    int
 	maxAttempts  int
+	otpTTL       time.Duration
+	resend       OTPResendPolicy
+	twoStep      TwoStepPolicy
 	log          zerolog.Logger
 }
 
 func NewAuthService(
 	userRepo repository.UserRepository,
 	otpRepo repository.OTPRepository,
+	deliveryRepo repository.OTPDeliveryRepository,
 	refreshRepo repository.RefreshTokenRepository,
+	deviceRepo repository.DeviceRepository,
+	deviceState repository.DeviceStateRepository,
+	securityRepo repository.SecurityEventRepository,
+	twoStepRepo repository.TwoStepRepository,
+	twoStepState repository.TwoStepStateRepository,
+	rdb *redis.Client,
+	js nats.JetStreamContext,
 	jwtManager *jwt.Manager,
+	otpRouter *OTPRouter,
+	resetMailer PINResetMailer,
+	accessTTL time.Duration,
 	refreshTTL time.Duration,
+	linkCodeTTL time.Duration,
+	maxLinked int,
 	otpLength int,
 	maxAttempts int,
+	otpTTL time.Duration,
+	resend OTPResendPolicy,
+	twoStep TwoStepPolicy,
 	log zerolog.Logger,
 ) AuthService {
 	return &authServiceImpl{
-		userRepo:    userRepo,
-		otpRepo:     otpRepo,
-		refreshRepo: refreshRepo,
-		jwtManager:  jwtManager,
-		refreshTTL:  refreshTTL,
-		otpLength:   otpLength,
-		maxAttempts: maxAttempts,
-		log:         log,
+		userRepo:     userRepo,
+		otpRepo:      otpRepo,
+		deliveryRepo: deliveryRepo,
+		refreshRepo:  refreshRepo,
+		deviceRepo:   deviceRepo,
+		deviceState:  deviceState,
+		securityRepo: securityRepo,
+		twoStepRepo:  twoStepRepo,
+		twoStepState: twoStepState,
+		rdb:          rdb,
+		js:           js,
+		jwtManager:   jwtManager,
+		otpRouter:    otpRouter,
+		resetMailer:  resetMailer,
+		accessTTL:    accessTTL,
+		refreshTTL:   refreshTTL,
+		linkCodeTTL:  linkCodeTTL,
+		maxLinked:    maxLinked,
+		otpLength:    otpLength,
+		maxAttempts:  maxAttempts,
+		otpTTL:       otpTTL,
+		resend:       resend,
+		twoStep:      twoStep,
+		log:          log,
 	}
 }
 
-func (s *authServiceImpl) SendOTP(ctx context.Context, phone string) (string, error) {
-	otp, err := generateOTP(s.otpLength)
-	if err != nil {
-		return "", apperr.NewInternal("failed to generate OTP", err)
-	}
-
-	hashed, err := bcrypt.GenerateFromPassword([]byte(otp), bcrypt.DefaultCost)
-	if err != nil {
-		return "", apperr.NewInternal("failed to hash OTP", err)
-	}
-
-	entry := &model.OTPEntry{
-		HashedOTP: string(hashed),
-		Attempts:  0,
-	}
-
-	if err := s.otpRepo.Store(ctx, phone, entry); err != nil {
-		return "", err
-	}
-
-	s.log.Info().Str("phone", maskPhone(phone)).Msg("OTP generated and stored")
-
-	return otp, nil
-}
-
-func (s *authServiceImpl) VerifyOTP(ctx context.Context, phone, code string) (*model.AuthResult, error) {
+func (s *authServiceImpl) VerifyOTP(ctx context.Context, phone, code string, info model.DeviceInfo) (*model.AuthResult, error) {
 	entry, err := s.otpRepo.Get(ctx, phone)
 	if err != nil {
 		return nil, err
@@ -96,6 +122,7 @@ func (s *authServiceImpl) VerifyOTP(ctx context.Context, phone, code string) (*m
 	}
 
 	_ = s.otpRepo.Delete(ctx, phone)
+	_ = s.otpRepo.ResetSends(ctx, phone)
 
 	user, err := s.userRepo.UpsertByPhone(ctx, phone)
 	if err != nil {
@@ -105,12 +132,32 @@ func (s *authServiceImpl) VerifyOTP(ctx context.Context, phone, code string) (*m
 	// Detect new user: if created_at == updated_at (within 1 second), user was just created
 	isNewUser := user.UpdatedAt.Sub(user.CreatedAt) < time.Second
 
-	pair, err := s.issueTokenPair(ctx, user.ID, user.Phone)
+	// Accounts with two-step verification also need the PIN.
+	twoStep, err := s.twoStepRepo.Get(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if twoStep != nil {
+		return s.startPINChallenge(ctx, user, twoStep, info)
+	}
+
+	return s.loginPrimary(ctx, user, info, isNewUser)
+}
+
+// loginPrimary registers the device of a completed login as primary and
+// issues its tokens.
+func (s *authServiceImpl) loginPrimary(ctx context.Context, user *model.User, info model.DeviceInfo, isNewUser bool) (*model.AuthResult, error) {
+	device, err := s.registerDevice(ctx, user.ID, info, true, "")
+	if err != nil {
+		return nil, err
+	}
+
+	pair, err := s.issueTokenPair(ctx, user.ID, user.Phone, device.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	s.log.Info().Str("user_id", user.ID).Str("phone", maskPhone(phone)).Bool("is_new_user", isNewUser).Msg("OTP verified, tokens issued")
+	s.log.Info().Str("user_id", user.ID).Str("phone", maskPhone(user.Phone)).Str("device_id", device.ID).Bool("is_new_user", isNewUser).Msg("login verified, tokens issued")
 
 	return &model.AuthResult{
 		AccessToken:      pair.AccessToken,
@@ -119,26 +166,47 @@ func (s *authServiceImpl) VerifyOTP(ctx context.Context, phone, code string) (*m
 		ExpiresInSeconds: pair.ExpiresIn,
 		User:             user,
 		IsNewUser:        isNewUser,
+		DeviceID:         device.ID,
 	}, nil
 }
 
-func (s *authServiceImpl) RefreshTokens(ctx context.Context, refreshToken string) (*model.TokenPair, error) {
+func (s *authServiceImpl) RefreshTokens(ctx context.Context, refreshToken string, client model.ClientInfo) (*model.TokenPair, error) {
 	tokenHash := sha256Hash(refreshToken)
 
-	stored, err := s.refreshRepo.GetByTokenHash(ctx, tokenHash)
+	stored, err := s.refreshRepo.FindByTokenHash(ctx, tokenHash)
 	if err != nil {
 		return nil, err
 	}
 	if stored == nil {
 		return nil, apperr.Wrap(apperr.CodeTokenInvalid, 401, "invalid or expired refresh token", nil)
 	}
+	if stored.Revoked {
+		return nil, s.refreshTokenReused(ctx, stored, client)
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, apperr.Wrap(apperr.CodeTokenInvalid, 401, "invalid or expired refresh token", nil)
+	}
+
+	if stored.DeviceID != "" {
+		device, err := s.deviceRepo.GetByID(ctx, stored.DeviceID)
+		if err != nil {
+			return nil, err
+		}
+		if device == nil || device.RevokedAt != nil {
+			_ = s.refreshRepo.RevokeByID(ctx, stored.ID)
+			return nil, apperr.Wrap(apperr.CodeTokenInvalid, 401, "device has been logged out", nil)
+		}
+		if err := s.deviceRepo.RecordUse(ctx, device.ID, normalizeClientInfo(client)); err != nil {
+			s.log.Warn().Err(err).Str("device_id", device.ID).Msg("failed to update device activity")
+		}
+	}
 
 	user, err := s.userRepo.GetByID(ctx, stored.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	accessToken, err := s.jwtManager.CreateAccessToken(user.ID, user.Phone)
+	accessToken, err := s.jwtManager.CreateAccessToken(user.ID, user.Phone, stored.DeviceID)
 	if err != nil {
 		return nil, apperr.NewInternal("failed to create access token", err)
 	}
@@ -150,11 +218,16 @@ func (s *authServiceImpl) RefreshTokens(ctx context.Context, refreshToken string
 
 	newRT := &model.RefreshToken{
 		UserID:    user.ID,
+		DeviceID:  stored.DeviceID,
 		TokenHash: sha256Hash(opaqueToken),
 		ExpiresAt: time.Now().Add(s.refreshTTL),
 	}
 
 	if err := s.refreshRepo.ReplaceToken(ctx, stored.ID, newRT); err != nil {
+		// Another request rotated the same token first.
+		if errors.Is(err, repository.ErrRefreshTokenRevoked) {
+			return nil, s.refreshTokenReused(ctx, stored, client)
+		}
 		return nil, err
 	}
 
@@ -182,21 +255,43 @@ func (s *authServiceImpl) Logout(ctx context.Context, refreshToken string) error
 		return err
 	}
 
-	s.log.Info().Str("user_id", stored.UserID).Msg("user logged out")
+	if stored.DeviceID != "" {
+		if _, err := s.revokeDevice(ctx, stored.UserID, stored.DeviceID); err != nil {
+			return err
+		}
+	}
+
+	s.log.Info().Str("user_id", stored.UserID).Str("device_id", stored.DeviceID).Msg("user logged out")
 
 	return nil
 }
 
-func (s *authServiceImpl) ValidateToken(ctx context.Context, token string) (string, string, error) {
+func (s *authServiceImpl) JWKS() *jwt.JWKS {
+	return s.jwtManager.JWKS()
+}
+
+func (s *authServiceImpl) ValidateToken(ctx context.Context, token string) (string, string, string, error) {
 	claims, err := s.jwtManager.ValidateToken(token)
 	if err != nil {
-		return "", "", apperr.Wrap(apperr.CodeTokenInvalid, 401, "invalid token", err)
+		return "", "", "", apperr.Wrap(apperr.CodeTokenInvalid, 401, "invalid token", err)
+	}
+
+	if claims.DeviceID != "" {
+		revoked, err := s.deviceState.IsRevoked(ctx, claims.DeviceID)
+		if err != nil {
+			return "", "", "", err
+		}
+		if revoked {
+			return "", "", "", apperr.Wrap(apperr.CodeTokenInvalid, 401, "device has been logged out", nil)
+		}
+		s.touchDevice(ctx, claims.DeviceID)
 	}
-	return claims.UserID, claims.Phone, nil
+
+	return claims.UserID, claims.Phone, claims.DeviceID, nil
 }
 
-func (s *authServiceImpl) issueTokenPair(ctx context.Context, userID, phone string) (*model.TokenPair, error) {
-	accessToken, err := s.jwtManager.CreateAccessToken(userID, phone)
+func (s *authServiceImpl) issueTokenPair(ctx context.Context, userID, phone, deviceID string) (*model.TokenPair, error) {
+	accessToken, err := s.jwtManager.CreateAccessToken(userID, phone, deviceID)
 	if err != nil {
 		return nil, apperr.NewInternal("failed to create access token", err)
 	}
@@ -209,6 +304,7 @@ func (s *authServiceImpl) issueTokenPair(ctx context.Context, userID, phone stri
 	refreshTokenHash := sha256Hash(opaqueToken)
 	rt := &model.RefreshToken{
 		UserID:    userID,
+		DeviceID:  deviceID,
 		TokenHash: refreshTokenHash,
 		ExpiresAt: time.Now().Add(s.refreshTTL),
 	}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+)
+
+// Sessions are the refresh token families of linked devices. Every login or
+// link creates a device with exactly one session, so a session shares its
+// device's ID and ending a session logs the device out. Each refresh rotates
+// the session's token, so only one party can hold a live token at a time.
+
+func (s *authServiceImpl) ListSessions(ctx context.Context, userID, currentDeviceID string) ([]*model.Session, error) {
+	devices, err := s.ListDevices(ctx, userID, currentDeviceID)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]*model.Session, 0, len(devices))
+	for _, d := range devices {
+		sessions = append(sessions, d.Session())
+	}
+	return sessions, nil
+}
+
+func (s *authServiceImpl) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	return s.RevokeDevice(ctx, userID, sessionID)
+}
+
+func (s *authServiceImpl) RevokeOtherSessions(ctx context.Context, userID, currentDeviceID string) (int, error) {
+	// Without the caller's device there is no session to keep, and every
+	// session, the caller's included, would be logged out.
+	if currentDeviceID == "" {
+		return 0, apperr.NewBadRequest("access token is not bound to a device; log in again")
+	}
+	devices, err := s.deviceRepo.ListActiveByUserID(ctx, userID, time.Now().Add(-s.refreshTTL))
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := 0
+	for _, d := range devices {
+		if d.ID == currentDeviceID {
+			continue
+		}
+		if _, err := s.revokeDevice(ctx, userID, d.ID); err != nil {
+			return revoked, err
+		}
+		revoked++
+	}
+	// Tokens issued before devices were tracked belong to no device, and
+	// none of them can be the caller's.
+	legacy, err := s.refreshRepo.RevokeUnboundByUserID(ctx, userID)
+	if err != nil {
+		return revoked, err
+	}
+	revoked += legacy
+
+	s.log.Info().Str("user_id", userID).Str("device_id", currentDeviceID).Int("revoked", revoked).Msg("other sessions logged out")
+	return revoked, nil
+}
+
+func (s *authServiceImpl) EraseUser(ctx context.Context, userID string) error {
+	devices, err := s.deviceRepo.ListActiveByUserID(ctx, userID, time.Time{})
+	if err != nil {
+		return err
+	}
+	for _, d := range devices {
+		if _, err := s.revokeDevice(ctx, userID, d.ID); err != nil {
+			return err
+		}
+	}
+	// Tokens issued before devices were tracked belong to no device.
+	if err := s.refreshRepo.RevokeAllByUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	s.log.Info().Str("user_id", userID).Int("revoked", len(devices)).Msg("deleted account logged out everywhere")
+	return nil
+}
+
+// refreshTokenReused handles a refresh token presented after it was rotated
+// away. Either the token was stolen or the legitimate client is replaying an
+// old copy, and the two cannot be told apart, so the whole session is logged
+// out, a security event is recorded and the user's other devices are warned
+// with session.compromised. The returned error is what the caller reports.
+func (s *authServiceImpl) refreshTokenReused(ctx context.Context, stored *model.RefreshToken, client model.ClientInfo) error {
+	if stored.DeviceID == "" {
+		return s.unboundRefreshTokenReused(ctx, stored, client)
+	}
+
+	device, err := s.deviceRepo.GetByID(ctx, stored.DeviceID)
+	if err != nil {
+		return err
+	}
+	// Tokens of a session that has already ended are merely stale.
+	if device == nil || device.RevokedAt != nil {
+		return apperr.Wrap(apperr.CodeTokenInvalid, 401, "device has been logged out", nil)
+	}
+
+	revoked, err := s.revokeDevice(ctx, stored.UserID, device.ID)
+	if err != nil {
+		return err
+	}
+
+	// A concurrent request may have detected the same reuse first.
+	if revoked {
+		s.reportRefreshReuse(ctx, stored.UserID, device, client)
+	}
+
+	return apperr.Wrap(apperr.CodeTokenInvalid, 401, "refresh token reuse detected; the session has been logged out", nil)
+}
+
+// unboundRefreshTokenReused is refreshTokenReused for tokens issued before
+// devices were tracked. Their lineage is not recorded, so every such token
+// of the user is revoked, the same set "log out other sessions" ends.
+func (s *authServiceImpl) unboundRefreshTokenReused(ctx context.Context, stored *model.RefreshToken, client model.ClientInfo) error {
+	revoked, err := s.refreshRepo.RevokeUnboundByUserID(ctx, stored.UserID)
+	if err != nil {
+		return err
+	}
+	if revoked > 0 {
+		s.reportRefreshReuse(ctx, stored.UserID, nil, client)
+	}
+	return apperr.Wrap(apperr.CodeTokenInvalid, 401, "refresh token reuse detected; the session has been logged out", nil)
+}
+
+// reportRefreshReuse records the security event of a detected refresh token
+// reuse and sends session.compromised. device is nil for tokens that belong
+// to no device.
+func (s *authServiceImpl) reportRefreshReuse(ctx context.Context, userID string, device *model.Device, client model.ClientInfo) {
+	client = normalizeClientInfo(client)
+	event := &model.SecurityEvent{
+		UserID:    userID,
+		Type:      model.SecurityEventRefreshReuse,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	}
+	payload := map[string]any{"ip": client.IP}
+	if device != nil {
+		event.DeviceID = device.ID
+		payload["session_id"] = device.ID
+		payload["device_name"] = device.Name
+		payload["platform"] = device.Platform
+	}
+
+	if err := s.securityRepo.Create(ctx, event); err != nil {
+		s.log.Error().Err(err).Str("user_id", userID).Str("device_id", event.DeviceID).Msg("failed to record security event")
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	payload["detected_at"] = event.CreatedAt.UTC()
+
+	s.publishDeviceEvent(ctx, userID, "session.compromised", payload)
+	s.log.Warn().
+		Str("user_id", userID).
+		Str("device_id", event.DeviceID).
+		Str("ip", client.IP).
+		Msg("refresh token reuse detected, session revoked")
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"math"
+	"net/mail"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+)
+
+// pinHashCost makes each PIN guess against a leaked hash expensive; a
+// 6-digit PIN has only a million values.
+const pinHashCost = 12
+
+// recoveryEmailMaxLen matches the two_step_verification column.
+const recoveryEmailMaxLen = 254
+
+// TwoStepPolicy throttles PIN entry. MaxAttempts wrong PINs within Lockout
+// lock the account's PIN entry for Lockout. A PIN challenge, the pending
+// login between OTP and PIN, lives for ChallengeTTL.
+type TwoStepPolicy struct {
+	MaxAttempts  int
+	Lockout      time.Duration
+	ChallengeTTL time.Duration
+}
+
+func (s *authServiceImpl) startPINChallenge(ctx context.Context, user *model.User, twoStep *model.TwoStep, info model.DeviceInfo) (*model.AuthResult, error) {
+	token, err := generateOpaqueToken(32)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to generate PIN token", err)
+	}
+
+	challenge := &model.PINChallenge{UserID: user.ID, Device: info, Client: info.ClientInfo}
+	if err := s.twoStepState.StoreChallenge(ctx, sha256Hash(token), challenge, s.twoStep.ChallengeTTL); err != nil {
+		return nil, err
+	}
+
+	s.log.Info().Str("user_id", user.ID).Str("phone", maskPhone(user.Phone)).Msg("OTP verified, PIN required")
+
+	return &model.AuthResult{
+		PINRequired:       true,
+		PINToken:          token,
+		RecoveryEmailHint: maskEmail(twoStep.RecoveryEmail),
+	}, nil
+}
+
+func (s *authServiceImpl) VerifyPIN(ctx context.Context, pinToken, pin string) (*model.AuthResult, error) {
+	tokenHash := sha256Hash(pinToken)
+	challenge, err := s.twoStepState.GetChallenge(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, apperr.Wrap(apperr.CodePINTokenInvalid, 400, "PIN session is invalid or expired; verify your phone number again", nil)
+	}
+	userID := challenge.UserID
+
+	attempts, err := s.countPINAttempt(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	twoStep, err := s.twoStepRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// A PIN turned off since the OTP was verified no longer applies.
+	if twoStep != nil && bcrypt.CompareHashAndPassword([]byte(twoStep.PINHash), []byte(pin)) != nil {
+		return nil, s.wrongPIN(ctx, userID, tokenHash, "PIN", attempts, challenge.Client)
+	}
+
+	consumed, err := s.twoStepState.DeleteChallenge(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, apperr.Wrap(apperr.CodePINTokenInvalid, 400, "PIN session is invalid or expired; verify your phone number again", nil)
+	}
+	if err := s.twoStepState.ClearAttempts(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := challenge.Device
+	info.ClientInfo = challenge.Client
+	return s.loginPrimary(ctx, user, info, false)
+}
+
+// RequestPINReset only sends one code per PIN challenge; getting another
+// needs a new OTP, which is rate limited.
+func (s *authServiceImpl) RequestPINReset(ctx context.Context, pinToken string) (*model.PINResetResult, error) {
+	tokenHash := sha256Hash(pinToken)
+	challenge, err := s.twoStepState.GetChallenge(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, apperr.Wrap(apperr.CodePINTokenInvalid, 400, "PIN session is invalid or expired; verify your phone number again", nil)
+	}
+
+	twoStep, err := s.twoStepRepo.Get(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if twoStep == nil || twoStep.RecoveryEmail == "" {
+		return nil, apperr.NewBadRequest("the account has no recovery email")
+	}
+	if s.resetMailer == nil {
+		return nil, apperr.NewBadRequest("PIN reset by email is not available")
+	}
+
+	code, err := generateOTP(s.otpLength)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to generate PIN reset code", err)
+	}
+	stored, err := s.twoStepState.StoreResetCode(ctx, tokenHash, sha256Hash(code), s.twoStep.ChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+	if !stored {
+		return nil, apperr.NewTooManyRequests("a reset code was already sent; verify your phone number again to get another")
+	}
+	if err := s.resetMailer.SendPINReset(ctx, twoStep.RecoveryEmail, code, s.twoStep.ChallengeTTL); err != nil {
+		return nil, apperr.NewInternal("failed to send PIN reset email", err)
+	}
+
+	s.log.Info().Str("user_id", challenge.UserID).Msg("PIN reset code sent to recovery email")
+	return &model.PINResetResult{
+		Code:              code,
+		RecoveryEmailHint: maskEmail(twoStep.RecoveryEmail),
+		ExpiresIn:         s.twoStep.ChallengeTTL,
+	}, nil
+}
+
+func (s *authServiceImpl) ConfirmPINReset(ctx context.Context, pinToken, code string) (*model.AuthResult, error) {
+	tokenHash := sha256Hash(pinToken)
+	challenge, err := s.twoStepState.GetChallenge(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, apperr.Wrap(apperr.CodePINTokenInvalid, 400, "PIN session is invalid or expired; verify your phone number again", nil)
+	}
+	userID := challenge.UserID
+
+	attempts, err := s.countPINAttempt(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	codeHash, err := s.twoStepState.GetResetCode(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if codeHash == "" {
+		return nil, apperr.NewBadRequest("no PIN reset code was requested for this login")
+	}
+	if subtle.ConstantTimeCompare([]byte(sha256Hash(code)), []byte(codeHash)) != 1 {
+		return nil, s.wrongPIN(ctx, userID, tokenHash, "reset code", attempts, challenge.Client)
+	}
+
+	consumed, err := s.twoStepState.DeleteChallenge(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, apperr.Wrap(apperr.CodePINTokenInvalid, 400, "PIN session is invalid or expired; verify your phone number again", nil)
+	}
+	if _, err := s.twoStepRepo.Delete(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := s.twoStepState.ClearAttempts(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	client := normalizeClientInfo(challenge.Client)
+	event := &model.SecurityEvent{
+		UserID:    userID,
+		Type:      model.SecurityEventPINReset,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	}
+	if err := s.securityRepo.Create(ctx, event); err != nil {
+		s.log.Error().Err(err).Str("user_id", userID).Msg("failed to record security event")
+	}
+	s.log.Warn().Str("user_id", userID).Str("ip", client.IP).Msg("two-step verification reset with recovery email")
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := challenge.Device
+	info.ClientInfo = challenge.Client
+	return s.loginPrimary(ctx, user, info, false)
+}
+
+// countPINAttempt counts an attempt at the user's PIN, or at a code standing
+// in for it, and returns its number. It is counted before the PIN is
+// checked so that parallel guesses cannot get past the limit.
+func (s *authServiceImpl) countPINAttempt(ctx context.Context, userID string) (int, error) {
+	locked, err := s.twoStepState.LockedFor(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if locked > 0 {
+		return 0, pinLockedError(locked)
+	}
+
+	attempts, err := s.twoStepState.RecordAttempt(ctx, userID, s.twoStep.Lockout)
+	if err != nil {
+		return 0, err
+	}
+	if attempts > s.twoStep.MaxAttempts {
+		return 0, pinLockedError(s.twoStep.Lockout)
+	}
+	return attempts, nil
+}
+
+// checkCurrentPIN guards changing or turning off two-step verification, so
+// that a stolen session cannot take it over. Wrong PINs count like at login.
+func (s *authServiceImpl) checkCurrentPIN(ctx context.Context, twoStep *model.TwoStep, pin string, client model.ClientInfo) error {
+	if pin == "" {
+		return apperr.NewBadRequest("the current PIN is required")
+	}
+	attempts, err := s.countPINAttempt(ctx, twoStep.UserID)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(twoStep.PINHash), []byte(pin)) != nil {
+		return s.wrongPIN(ctx, twoStep.UserID, "", "PIN", attempts, client)
+	}
+	return s.twoStepState.ClearAttempts(ctx, twoStep.UserID)
+}
+
+// wrongPIN returns the error for a wrong PIN (or reset code, as named by
+// what) that was attempt number attempts. The attempt that reaches the limit
+// locks PIN entry and voids the login's challenge, if any, so the next try
+// also needs a fresh OTP.
+func (s *authServiceImpl) wrongPIN(ctx context.Context, userID, tokenHash, what string, attempts int, client model.ClientInfo) error {
+	left := s.twoStep.MaxAttempts - attempts
+	if left > 0 {
+		return apperr.Wrap(apperr.CodePINInvalid, 400, fmt.Sprintf("incorrect %s; %d attempts left", what, left), nil)
+	}
+
+	if err := s.twoStepState.Lock(ctx, userID, s.twoStep.Lockout); err != nil {
+		return err
+	}
+	_ = s.twoStepState.ClearAttempts(ctx, userID)
+	if tokenHash != "" {
+		_, _ = s.twoStepState.DeleteChallenge(ctx, tokenHash)
+	}
+
+	client = normalizeClientInfo(client)
+	event := &model.SecurityEvent{
+		UserID:    userID,
+		Type:      model.SecurityEventPINLockout,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	}
+	if err := s.securityRepo.Create(ctx, event); err != nil {
+		s.log.Error().Err(err).Str("user_id", userID).Msg("failed to record security event")
+	}
+	s.log.Warn().Str("user_id", userID).Str("ip", client.IP).Msg("too many wrong PINs, PIN entry locked")
+
+	return pinLockedError(s.twoStep.Lockout)
+}
+
+func pinLockedError(remaining time.Duration) error {
+	minutes := int64(math.Ceil(remaining.Minutes()))
+	return apperr.Wrap(apperr.CodePINLocked, 429, fmt.Sprintf("too many incorrect PINs; try again in %d minutes", minutes), nil)
+}
+
+func (s *authServiceImpl) GetTwoStep(ctx context.Context, userID string) (*model.TwoStepStatus, error) {
+	twoStep, err := s.twoStepRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return twoStepStatus(twoStep), nil
+}
+
+func (s *authServiceImpl) SetTwoStep(ctx context.Context, userID, currentPIN, pin, recoveryEmail string, client model.ClientInfo) (*model.TwoStepStatus, error) {
+	if !isValidPIN(pin) {
+		return nil, apperr.NewBadRequest(fmt.Sprintf("PIN must be %d digits", model.PINLength))
+	}
+	recoveryEmail, err := normalizeRecoveryEmail(recoveryEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.twoStepRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		if err := s.checkCurrentPIN(ctx, current, currentPIN, client); err != nil {
+			return nil, err
+		}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), pinHashCost)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to hash PIN", err)
+	}
+
+	twoStep := &model.TwoStep{UserID: userID, PINHash: string(hash), RecoveryEmail: recoveryEmail}
+	if err := s.twoStepRepo.Upsert(ctx, twoStep); err != nil {
+		return nil, err
+	}
+
+	s.log.Info().Str("user_id", userID).Bool("recovery_email", recoveryEmail != "").Msg("two-step verification PIN set")
+	return twoStepStatus(twoStep), nil
+}
+
+func (s *authServiceImpl) DisableTwoStep(ctx context.Context, userID, pin string, client model.ClientInfo) error {
+	current, err := s.twoStepRepo.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return nil
+	}
+	if err := s.checkCurrentPIN(ctx, current, pin, client); err != nil {
+		return err
+	}
+
+	disabled, err := s.twoStepRepo.Delete(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if disabled {
+		s.log.Info().Str("user_id", userID).Msg("two-step verification disabled")
+	}
+	return nil
+}
+
+func twoStepStatus(twoStep *model.TwoStep) *model.TwoStepStatus {
+	if twoStep == nil {
+		return &model.TwoStepStatus{}
+	}
+	return &model.TwoStepStatus{
+		Enabled:       true,
+		RecoveryEmail: twoStep.RecoveryEmail,
+		UpdatedAt:     &twoStep.UpdatedAt,
+	}
+}
+
+func isValidPIN(pin string) bool {
+	if len(pin) != model.PINLength {
+		return false
+	}
+	for _, c := range pin {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeRecoveryEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return "", nil
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || len(email) > recoveryEmailMaxLen {
+		return "", apperr.NewBadRequest("recovery_email is not a valid email address")
+	}
+	return email, nil
+}
+
+// maskEmail hides all but the first character of the local part, e.g.
+// "a***@example.com", so a login screen can hint at the recovery address.
+func maskEmail(email string) string {
+	at := strings.LastIndexByte(email, '@')
+	if at < 1 {
+		return ""
+	}
+	return email[:1] + "***" + email[at:]
+}
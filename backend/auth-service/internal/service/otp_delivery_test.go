@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+)
+
+// gatewayRequest is what HTTPGatewaySender posts to a gateway.
+type gatewayRequest struct {
+	Auth    string
+	To      string `json:"to"`
+	From    string `json:"from"`
+	Channel string `json:"channel"`
+	Body    string `json:"body"`
+}
+
+// fakeGateway is an in-process SMS/voice gateway. It records every request
+// and answers with status, or stalls for delay first.
+type fakeGateway struct {
+	*httptest.Server
+	status int
+	delay  time.Duration
+
+	mu       sync.Mutex
+	requests []gatewayRequest
+}
+
+func newFakeGateway(t *testing.T, status int) *fakeGateway {
+	t.Helper()
+	g := &fakeGateway{status: status}
+	g.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req gatewayRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req), "gateway: decode request")
+		req.Auth = r.Header.Get("Authorization")
+
+		g.mu.Lock()
+		g.requests = append(g.requests, req)
+		n := len(g.requests)
+		g.mu.Unlock()
+
+		if g.delay > 0 {
+			select {
+			case <-time.After(g.delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		w.WriteHeader(g.status)
+		if g.status < 300 {
+			_ = json.NewEncoder(w).Encode(map[string]string{"message_id": req.Channel + "-" + strconv.Itoa(n)})
+		} else {
+			_, _ = w.Write([]byte("gateway unavailable"))
+		}
+	}))
+	t.Cleanup(g.Close)
+	return g
+}
+
+func (g *fakeGateway) received() []gatewayRequest {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]gatewayRequest(nil), g.requests...)
+}
+
+// memOTPRepo keeps codes, send counts and cooldowns in memory against a
+// clock the test moves forward.
+type memOTPRepo struct {
+	now        time.Time
+	entries    map[string]*model.OTPEntry
+	sends      map[string]int
+	cooldownTo map[string]time.Time
+}
+
+func newMemOTPRepo() *memOTPRepo {
+	return &memOTPRepo{
+		now:        time.Now(),
+		entries:    make(map[string]*model.OTPEntry),
+		sends:      make(map[string]int),
+		cooldownTo: make(map[string]time.Time),
+	}
+}
+
+func (m *memOTPRepo) Store(_ context.Context, phone string, entry *model.OTPEntry) error {
+	m.entries[phone] = entry
+	return nil
+}
+
+func (m *memOTPRepo) Get(_ context.Context, phone string) (*model.OTPEntry, error) {
+	return m.entries[phone], nil
+}
+
+func (m *memOTPRepo) IncrementAttempts(_ context.Context, phone string) (int, error) {
+	m.entries[phone].Attempts++
+	return m.entries[phone].Attempts, nil
+}
+
+func (m *memOTPRepo) Delete(_ context.Context, phone string) error {
+	delete(m.entries, phone)
+	return nil
+}
+
+func (m *memOTPRepo) AcquireCooldown(_ context.Context, phone string, cooldownAfter func(int) time.Duration) (bool, time.Duration, error) {
+	if until, ok := m.cooldownTo[phone]; ok && m.now.Before(until) {
+		return false, until.Sub(m.now), nil
+	}
+	cooldown := cooldownAfter(m.sends[phone] + 1)
+	m.cooldownTo[phone] = m.now.Add(cooldown)
+	return true, cooldown, nil
+}
+
+func (m *memOTPRepo) RecordSend(_ context.Context, phone string, _ time.Duration) error {
+	m.sends[phone]++
+	return nil
+}
+
+func (m *memOTPRepo) ReleaseCooldown(_ context.Context, phone string) error {
+	delete(m.cooldownTo, phone)
+	return nil
+}
+
+func (m *memOTPRepo) ResetSends(_ context.Context, phone string) error {
+	delete(m.cooldownTo, phone)
+	delete(m.sends, phone)
+	return nil
+}
+
+// memDeliveryRepo keeps deliveries and, like the Redis one, the IDs of
+// every provider message they were sent as.
+type memDeliveryRepo struct {
+	deliveries map[string]*model.OTPDelivery
+	refs       map[string]string
+}
+
+func (m *memDeliveryRepo) Save(_ context.Context, delivery *model.OTPDelivery, _ time.Duration) error {
+	m.deliveries[delivery.ID] = delivery
+	if delivery.ProviderMessageID != "" {
+		m.refs[delivery.Provider+":"+delivery.ProviderMessageID] = delivery.ID
+	}
+	return nil
+}
+
+func (m *memDeliveryRepo) Get(_ context.Context, id string) (*model.OTPDelivery, error) {
+	return m.deliveries[id], nil
+}
+
+func (m *memDeliveryRepo) GetByProviderMessageID(_ context.Context, provider, messageID string) (*model.OTPDelivery, error) {
+	return m.deliveries[m.refs[provider+":"+messageID]], nil
+}
+
+// newOTPTestService returns a service that sends codes through router and
+// keeps its state in memory.
+func newOTPTestService(router *OTPRouter, resend OTPResendPolicy) (*authServiceImpl, *memOTPRepo) {
+	otpRepo := newMemOTPRepo()
+	return &authServiceImpl{
+		otpRepo:      otpRepo,
+		deliveryRepo: &memDeliveryRepo{deliveries: make(map[string]*model.OTPDelivery), refs: make(map[string]string)},
+		otpRouter:    router,
+		otpLength:    6,
+		maxAttempts:  5,
+		otpTTL:       5 * time.Minute,
+		resend:       resend,
+		log:          zerolog.Nop(),
+	}, otpRepo
+}
+
+func mustSMS(t *testing.T, url string, templates map[string]string) *HTTPGatewaySender {
+	t.Helper()
+	s, err := NewHTTPSMSSender(url, "sms-token", "WhatsApp", "Your code is {{.Code}}", templates)
+	require.NoError(t, err)
+	return s
+}
+
+func mustVoice(t *testing.T, url string) *HTTPGatewaySender {
+	t.Helper()
+	s, err := NewHTTPVoiceSender(url, "voice-token", "WhatsApp", "Your code is {{.SpokenCode}}", nil)
+	require.NoError(t, err)
+	return s
+}
+
+func mustRouter(t *testing.T, routes map[string]string, senders ...OTPSender) *OTPRouter {
+	t.Helper()
+	r, err := NewOTPRouter(routes, senders...)
+	require.NoError(t, err)
+	return r
+}
+
+func TestHTTPGatewaySender_Send(t *testing.T) {
+	gw := newFakeGateway(t, http.StatusOK)
+	sms := mustSMS(t, gw.URL, map[string]string{"+49": "Dein Code: {{.Code}}"})
+
+	cases := []struct {
+		phone, body string
+	}{
+		{"+14155550100", "Your code is 123456"},
+		{"+4930123456", "Dein Code: 123456"},
+	}
+	for i, tc := range cases {
+		id, err := sms.Send(context.Background(), &model.OTPMessage{
+			Phone: tc.phone, Code: "123456", Channel: model.OTPChannelSMS, TTL: 5 * time.Minute,
+		})
+		require.NoError(t, err, tc.phone)
+		assert.Equal(t, "sms-"+strconv.Itoa(i+1), id, tc.phone)
+
+		req := gw.received()[i]
+		assert.Equal(t, "Bearer sms-token", req.Auth, tc.phone)
+		assert.Equal(t, tc.phone, req.To)
+		assert.Equal(t, "WhatsApp", req.From, tc.phone)
+		assert.Equal(t, model.OTPChannelSMS, req.Channel, tc.phone)
+		assert.Equal(t, tc.body, req.Body, tc.phone)
+	}
+}
+
+func TestSendOTP_FallsBackToVoice(t *testing.T) {
+	cases := map[string]func(*fakeGateway, *HTTPGatewaySender){
+		"gateway error": func(*fakeGateway, *HTTPGatewaySender) {},
+		"gateway timeout": func(gw *fakeGateway, sms *HTTPGatewaySender) {
+			gw.status = http.StatusOK
+			gw.delay = time.Second
+			sms.httpClient.Timeout = 50 * time.Millisecond
+		},
+	}
+	for name, setup := range cases {
+		t.Run(name, func(t *testing.T) {
+			smsGW := newFakeGateway(t, http.StatusServiceUnavailable)
+			voiceGW := newFakeGateway(t, http.StatusOK)
+			sms, voice := mustSMS(t, smsGW.URL, nil), mustVoice(t, voiceGW.URL)
+			setup(smsGW, sms)
+
+			router := mustRouter(t, map[string]string{"*": "sms,voice"}, sms, voice)
+			svc, _ := newOTPTestService(router, OTPResendPolicy{})
+
+			res, err := svc.SendOTP(context.Background(), "+14155550101", "")
+			require.NoError(t, err)
+			d := res.Delivery
+			assert.Equal(t, model.OTPStatusSent, d.Status)
+			assert.Equal(t, "voice", d.Provider)
+			assert.Equal(t, model.OTPChannelVoice, d.Channel)
+			assert.Equal(t, 2, d.Attempts)
+			assert.Equal(t, "voice-1", d.ProviderMessageID)
+			assert.Len(t, smsGW.received(), 1)
+			calls := voiceGW.received()
+			require.Len(t, calls, 1)
+			assert.Contains(t, calls[0].Body, strings.Join(strings.Split(res.Code, ""), ", "), "voice call should read out the code")
+		})
+	}
+}
+
+func TestSendOTP_AllProvidersFail(t *testing.T) {
+	sms := mustSMS(t, newFakeGateway(t, http.StatusInternalServerError).URL, nil)
+	voice := mustVoice(t, newFakeGateway(t, http.StatusBadGateway).URL)
+	router := mustRouter(t, map[string]string{"*": "sms,voice"}, sms, voice)
+	svc, otpRepo := newOTPTestService(router, OTPResendPolicy{Cooldown: 30 * time.Second})
+
+	_, err := svc.SendOTP(context.Background(), "+14155550102", "")
+	var appErr *apperr.AppError
+	require.True(t, errors.As(err, &appErr), "SendOTP error = %v", err)
+	assert.Equal(t, apperr.CodeOTPDeliveryFailed, appErr.Code)
+	assert.NotContains(t, otpRepo.cooldownTo, "+14155550102", "cooldown kept after a failed delivery")
+}
+
+func TestOTPRouter_Plan(t *testing.T) {
+	sms := mustSMS(t, "http://sms.invalid", nil)
+	voice := mustVoice(t, "http://voice.invalid")
+	logSink := NewLogOTPSender("", zerolog.Nop())
+	router := mustRouter(t, map[string]string{
+		"+1":   "sms,voice",
+		"+91":  "voice",
+		"+919": "log",
+		"*":    "log",
+	}, sms, voice, logSink)
+
+	cases := []struct {
+		phone, channel string
+		want           []string
+	}{
+		{"+14155550103", "", []string{"sms/sms", "voice/voice"}},
+		{"+14155550103", "voice", []string{"voice/voice"}},
+		{"+918012345678", "", []string{"voice/voice"}},
+		{"+918012345678", "sms", nil},
+		{"+919812345678", "", []string{"log/sms"}},
+		{"+4930123456", "voice", []string{"log/voice"}},
+	}
+	for _, tc := range cases {
+		var got []string
+		for _, a := range router.plan(tc.phone, tc.channel) {
+			got = append(got, a.sender.Name()+"/"+a.channel)
+		}
+		assert.Equal(t, tc.want, got, "plan(%s, %q)", tc.phone, tc.channel)
+	}
+
+	_, err := NewOTPRouter(map[string]string{"49": "log"}, logSink)
+	assert.Error(t, err, "prefix without + accepted")
+	_, err = NewOTPRouter(map[string]string{"*": "sms"}, logSink)
+	assert.Error(t, err, "unknown provider accepted")
+}
+
+func TestSendOTP_ResendCooldownDoubles(t *testing.T) {
+	gw := newFakeGateway(t, http.StatusOK)
+	router := mustRouter(t, map[string]string{"*": "sms"}, mustSMS(t, gw.URL, nil))
+	svc, otpRepo := newOTPTestService(router, OTPResendPolicy{
+		Cooldown:    30 * time.Second,
+		MaxCooldown: 2 * time.Minute,
+		Window:      time.Hour,
+	})
+	const phone = "+14155550104"
+
+	for i, want := range []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 2 * time.Minute} {
+		res, err := svc.SendOTP(context.Background(), phone, "")
+		require.NoError(t, err, "send %d", i+1)
+		assert.Equal(t, want, res.ResendAfter, "send %d", i+1)
+
+		// Asking again before the cooldown ran out is refused.
+		otpRepo.now = otpRepo.now.Add(want - time.Second)
+		_, err = svc.SendOTP(context.Background(), phone, "")
+		var appErr *apperr.AppError
+		require.True(t, errors.As(err, &appErr), "send %d: early resend error = %v", i+1, err)
+		assert.Equal(t, apperr.CodeOTPResendTooSoon, appErr.Code)
+		assert.Equal(t, http.StatusTooManyRequests, appErr.HTTPStatus)
+		otpRepo.now = otpRepo.now.Add(time.Second)
+	}
+}
+
+func TestRecordOTPReceipt_FailedSMSFallsBackToVoice(t *testing.T) {
+	smsGW := newFakeGateway(t, http.StatusOK)
+	voiceGW := newFakeGateway(t, http.StatusOK)
+	router := mustRouter(t, map[string]string{"*": "sms,voice"}, mustSMS(t, smsGW.URL, nil), mustVoice(t, voiceGW.URL))
+	svc, otpRepo := newOTPTestService(router, OTPResendPolicy{Cooldown: 30 * time.Second})
+	const phone = "+14155550105"
+
+	res, err := svc.SendOTP(context.Background(), phone, "")
+	require.NoError(t, err)
+	require.Equal(t, "sms", res.Delivery.Provider)
+	firstCode := otpRepo.entries[phone].HashedOTP
+
+	// The carrier rejects the SMS after the gateway accepted it.
+	err = svc.RecordOTPReceipt(context.Background(), "sms", model.OTPDeliveryReceipt{MessageID: "sms-1", Status: "undelivered"})
+	require.NoError(t, err)
+
+	d, err := svc.GetOTPDelivery(context.Background(), res.Delivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OTPStatusSent, d.Status)
+	assert.Equal(t, "voice", d.Provider)
+	assert.Equal(t, "voice-1", d.ProviderMessageID)
+	assert.Equal(t, 2, d.Attempts)
+	require.Len(t, voiceGW.received(), 1)
+	assert.NotEqual(t, firstCode, otpRepo.entries[phone].HashedOTP, "the undelivered code should be replaced")
+	assert.Contains(t, otpRepo.cooldownTo, phone, "cooldown should stand while the call is on its way")
+
+	// A late receipt for the SMS does not touch the voice attempt.
+	err = svc.RecordOTPReceipt(context.Background(), "sms", model.OTPDeliveryReceipt{MessageID: "sms-1", Status: "failed"})
+	require.NoError(t, err)
+	assert.Len(t, voiceGW.received(), 1)
+
+	// Once the call fails too there is nothing left to try.
+	err = svc.RecordOTPReceipt(context.Background(), "voice", model.OTPDeliveryReceipt{MessageID: "voice-1", Status: "no-answer"})
+	require.NoError(t, err)
+	d, err = svc.GetOTPDelivery(context.Background(), res.Delivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OTPStatusFailed, d.Status)
+	assert.NotContains(t, otpRepo.cooldownTo, phone, "cooldown kept after every provider failed")
+}
+
+func TestLogOTPSender_KeepsCodesOutOfTheLog(t *testing.T) {
+	var logs strings.Builder
+	path := t.TempDir() + "/otp.log"
+	sink := NewLogOTPSender(path, zerolog.New(&logs))
+
+	_, err := sink.Send(context.Background(), &model.OTPMessage{Phone: "+14155550106", Code: "482913", Channel: model.OTPChannelSMS})
+	require.NoError(t, err)
+	assert.NotContains(t, logs.String(), "482913")
+
+	file, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(file), `"code":"482913"`)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+)
+
+// otpRouteDefault is the route key matching numbers no prefix covers.
+const otpRouteDefault = "*"
+
+// OTPRouter chooses delivery providers per country. A route maps a phone
+// prefix such as "+91", or "*" for all other numbers, to the providers to
+// try in order; the longest matching prefix wins. Listing "sms,voice" makes
+// a voice call the fallback when the SMS gateway fails.
+type OTPRouter struct {
+	routes map[string][]OTPSender
+}
+
+// otpAttempt is one provider to try and the channel to use with it.
+type otpAttempt struct {
+	sender  OTPSender
+	channel string
+}
+
+// NewOTPRouter builds a router from routes such as {"+1": "sms,voice",
+// "*": "log"}. Every provider a route names must be among senders.
+func NewOTPRouter(routes map[string]string, senders ...OTPSender) (*OTPRouter, error) {
+	byName := make(map[string]OTPSender, len(senders))
+	for _, s := range senders {
+		byName[s.Name()] = s
+	}
+
+	r := &OTPRouter{routes: make(map[string][]OTPSender, len(routes))}
+	for prefix, names := range routes {
+		prefix = strings.TrimSpace(prefix)
+		if prefix != otpRouteDefault && !isPhonePrefix(prefix) {
+			return nil, fmt.Errorf("otp route %q: prefix must be \"*\" or \"+\" followed by digits", prefix)
+		}
+		for _, name := range strings.Split(names, ",") {
+			name = strings.TrimSpace(name)
+			s, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("otp route %q: provider %q is not configured", prefix, name)
+			}
+			r.routes[prefix] = append(r.routes[prefix], s)
+		}
+	}
+	return r, nil
+}
+
+// plan lists the attempts for phone. With no channel requested each provider
+// is used for SMS if it can send SMS, otherwise for voice; a requested
+// channel skips providers that cannot deliver over it.
+func (r *OTPRouter) plan(phone, channel string) []otpAttempt {
+	prefix := longestPrefix(phone, r.routes)
+	senders := r.routes[prefix]
+	if prefix == "" {
+		senders = r.routes[otpRouteDefault]
+	}
+
+	var attempts []otpAttempt
+	for _, s := range senders {
+		switch {
+		case channel != "":
+			if s.Supports(channel) {
+				attempts = append(attempts, otpAttempt{sender: s, channel: channel})
+			}
+		case s.Supports(model.OTPChannelSMS):
+			attempts = append(attempts, otpAttempt{sender: s, channel: model.OTPChannelSMS})
+		case s.Supports(model.OTPChannelVoice):
+			attempts = append(attempts, otpAttempt{sender: s, channel: model.OTPChannelVoice})
+		}
+	}
+	return attempts
+}
+
+// fallbacks lists the attempts the router plans for phone after the one
+// with provider and channel, which failed.
+func (r *OTPRouter) fallbacks(phone, provider, channel string) []otpAttempt {
+	attempts := r.plan(phone, "")
+	for i, a := range attempts {
+		if a.sender.Name() == provider && a.channel == channel {
+			return attempts[i+1:]
+		}
+	}
+	return nil
+}
+
+func isPhonePrefix(prefix string) bool {
+	if len(prefix) < 2 || prefix[0] != '+' {
+		return false
+	}
+	for _, c := range prefix[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
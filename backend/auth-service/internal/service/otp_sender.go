@@ -0,0 +1,233 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/whatsapp-clone/backend/auth-service/internal/model"
+)
+
+// OTPSender delivers one-time codes through a provider.
+type OTPSender interface {
+	// Name identifies the provider in routes, delivery records and receipts.
+	Name() string
+	// Supports reports whether the provider can deliver over channel.
+	Supports(channel string) bool
+	// Send delivers the code and returns the provider's message ID.
+	Send(ctx context.Context, msg *model.OTPMessage) (string, error)
+}
+
+// otpTemplateData is what message templates can reference.
+type otpTemplateData struct {
+	Code       string
+	SpokenCode string
+	TTLMinutes int
+}
+
+func newOTPTemplateData(msg *model.OTPMessage) otpTemplateData {
+	return otpTemplateData{
+		Code:       msg.Code,
+		SpokenCode: strings.Join(strings.Split(msg.Code, ""), ", "),
+		TTLMinutes: int(msg.TTL.Round(time.Minute) / time.Minute),
+	}
+}
+
+// ---------------------------------------------------------------------------
+// LogOTPSender — records sends in the log and codes in a file (dev mode)
+// ---------------------------------------------------------------------------
+
+type LogOTPSender struct {
+	path string
+	mu   sync.Mutex
+	seq  int64
+	log  zerolog.Logger
+}
+
+// NewLogOTPSender returns a sink that delivers over any channel. Codes never
+// go to the log itself; when path is set, each one is appended to it as a
+// JSON line. It is only available in dev mode.
+func NewLogOTPSender(path string, log zerolog.Logger) *LogOTPSender {
+	return &LogOTPSender{path: path, log: log}
+}
+
+func (l *LogOTPSender) Name() string { return "log" }
+
+func (l *LogOTPSender) Supports(string) bool { return true }
+
+func (l *LogOTPSender) Send(_ context.Context, msg *model.OTPMessage) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.seq++
+	id := fmt.Sprintf("log-%d-%d", time.Now().UnixNano(), l.seq)
+
+	if l.path != "" {
+		line, err := json.Marshal(map[string]string{
+			"id":      id,
+			"phone":   msg.Phone,
+			"channel": msg.Channel,
+			"code":    msg.Code,
+			"sent_at": time.Now().UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			return "", fmt.Errorf("marshal OTP log line: %w", err)
+		}
+		f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return "", fmt.Errorf("open OTP log file: %w", err)
+		}
+		defer f.Close()
+		if _, err := f.Write(append(line, '\n')); err != nil {
+			return "", fmt.Errorf("write OTP log file: %w", err)
+		}
+	}
+
+	l.log.Info().
+		Str("phone", maskPhone(msg.Phone)).
+		Str("channel", msg.Channel).
+		Str("id", id).
+		Msg("[LOG OTP] code delivered")
+	return id, nil
+}
+
+// ---------------------------------------------------------------------------
+// HTTPGatewaySender — SMS or voice delivery through an HTTP gateway
+// ---------------------------------------------------------------------------
+
+// HTTPGatewaySender posts messages as JSON to a gateway:
+//
+//	POST <url>  Authorization: Bearer <token>
+//	{"to": "+1...", "from": "...", "channel": "sms", "body": "..."}
+//
+// and expects a 2xx response with the message ID in "id" or "message_id".
+// The body comes from a text/template chosen by the longest matching phone
+// prefix, falling back to the default template.
+type HTTPGatewaySender struct {
+	name       string
+	channel    string
+	url        string
+	token      string
+	from       string
+	tmpl       *template.Template
+	byPrefix   map[string]*template.Template
+	httpClient *http.Client
+}
+
+// NewHTTPSMSSender returns the "sms" provider. templates maps phone prefixes
+// such as "+49" to country-specific message templates.
+func NewHTTPSMSSender(url, token, from, tmpl string, templates map[string]string) (*HTTPGatewaySender, error) {
+	return newHTTPGatewaySender("sms", model.OTPChannelSMS, url, token, from, tmpl, templates)
+}
+
+// NewHTTPVoiceSender returns the "voice" provider, which has the gateway
+// read the message out in a call. Templates usually use {{.SpokenCode}}.
+func NewHTTPVoiceSender(url, token, from, tmpl string, templates map[string]string) (*HTTPGatewaySender, error) {
+	return newHTTPGatewaySender("voice", model.OTPChannelVoice, url, token, from, tmpl, templates)
+}
+
+func newHTTPGatewaySender(name, channel, url, token, from, tmpl string, templates map[string]string) (*HTTPGatewaySender, error) {
+	def, err := template.New(name).Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("%s template: %w", name, err)
+	}
+	byPrefix := make(map[string]*template.Template, len(templates))
+	for prefix, text := range templates {
+		t, err := template.New(name + prefix).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s template for %s: %w", name, prefix, err)
+		}
+		byPrefix[prefix] = t
+	}
+	return &HTTPGatewaySender{
+		name:       name,
+		channel:    channel,
+		url:        url,
+		token:      token,
+		from:       from,
+		tmpl:       def,
+		byPrefix:   byPrefix,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (g *HTTPGatewaySender) Name() string { return g.name }
+
+func (g *HTTPGatewaySender) Supports(channel string) bool { return channel == g.channel }
+
+func (g *HTTPGatewaySender) Send(ctx context.Context, msg *model.OTPMessage) (string, error) {
+	tmpl := g.tmpl
+	if prefix := longestPrefix(msg.Phone, g.byPrefix); prefix != "" {
+		tmpl = g.byPrefix[prefix]
+	}
+	var text bytes.Buffer
+	if err := tmpl.Execute(&text, newOTPTemplateData(msg)); err != nil {
+		return "", fmt.Errorf("render %s template: %w", g.name, err)
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"to":      msg.Phone,
+		"from":    g.from,
+		"channel": g.channel,
+		"body":    text.String(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal %s request: %w", g.name, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("create %s request: %w", g.name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%s gateway request failed: %w", g.name, err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("%s gateway error %d: %s", g.name, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var out struct {
+		ID        string `json:"id"`
+		MessageID string `json:"message_id"`
+	}
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return "", fmt.Errorf("decode %s gateway response: %w", g.name, err)
+	}
+	if out.ID == "" {
+		out.ID = out.MessageID
+	}
+	if out.ID == "" {
+		return "", fmt.Errorf("%s gateway response has no message id", g.name)
+	}
+	return out.ID, nil
+}
+
+// longestPrefix returns the longest key of m that phone starts with, or "".
+func longestPrefix[V any](phone string, m map[string]V) string {
+	best := ""
+	for prefix := range m {
+		if strings.HasPrefix(phone, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	return best
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// PINResetMailer emails the code that turns off a forgotten two-step PIN to
+// the account's recovery address.
+type PINResetMailer interface {
+	SendPINReset(ctx context.Context, to, code string, ttl time.Duration) error
+}
+
+// pinResetTemplateData is what the mail template can reference.
+type pinResetTemplateData struct {
+	Code       string
+	TTLMinutes int
+}
+
+// ---------------------------------------------------------------------------
+// LogPINResetMailer — sends nothing (dev mode)
+// ---------------------------------------------------------------------------
+
+// LogPINResetMailer only logs that a reset was requested. In dev mode the
+// code is returned to the client instead of mailed.
+type LogPINResetMailer struct {
+	log zerolog.Logger
+}
+
+func NewLogPINResetMailer(log zerolog.Logger) *LogPINResetMailer {
+	return &LogPINResetMailer{log: log}
+}
+
+func (l *LogPINResetMailer) SendPINReset(_ context.Context, to, _ string, _ time.Duration) error {
+	l.log.Info().Str("to", maskEmail(to)).Msg("[LOG MAIL] PIN reset code sent")
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// HTTPPINResetMailer — delivery through an HTTP mail gateway
+// ---------------------------------------------------------------------------
+
+// HTTPPINResetMailer posts mails as JSON to a gateway:
+//
+//	POST <url>  Authorization: Bearer <token>
+//	{"to": "...", "from": "...", "subject": "...", "body": "..."}
+//
+// and expects a 2xx response.
+type HTTPPINResetMailer struct {
+	url        string
+	token      string
+	from       string
+	subject    string
+	tmpl       *template.Template
+	httpClient *http.Client
+}
+
+func NewHTTPPINResetMailer(url, token, from, subject, tmpl string) (*HTTPPINResetMailer, error) {
+	t, err := template.New("pin-reset").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("PIN reset template: %w", err)
+	}
+	return &HTTPPINResetMailer{
+		url:        url,
+		token:      token,
+		from:       from,
+		subject:    subject,
+		tmpl:       t,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (m *HTTPPINResetMailer) SendPINReset(ctx context.Context, to, code string, ttl time.Duration) error {
+	var text bytes.Buffer
+	data := pinResetTemplateData{Code: code, TTLMinutes: int(ttl.Round(time.Minute) / time.Minute)}
+	if err := m.tmpl.Execute(&text, data); err != nil {
+		return fmt.Errorf("render PIN reset template: %w", err)
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"to":      to,
+		"from":    m.from,
+		"subject": m.subject,
+		"body":    text.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal mail request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create mail request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.token != "" {
+		req.Header.Set("Authorization", "Bearer "+m.token)
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("mail gateway request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return fmt.Errorf("mail gateway error %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
@@ -23,12 +23,15 @@ import (
 	"github.com/whatsapp-clone/backend/chat-service/internal/handler"
 	"github.com/whatsapp-clone/backend/chat-service/internal/repository"
 	"github.com/whatsapp-clone/backend/chat-service/internal/service"
+	"github.com/whatsapp-clone/backend/pkg/accountdeletion"
 	"github.com/whatsapp-clone/backend/pkg/logger"
 	"github.com/whatsapp-clone/backend/pkg/metrics"
 	"github.com/whatsapp-clone/backend/pkg/middleware"
 	"github.com/whatsapp-clone/backend/pkg/tracing"
 	chatv1 "github.com/whatsapp-clone/backend/proto/chat/v1"
+	mediav1 "github.com/whatsapp-clone/backend/proto/media/v1"
 	messagev1 "github.com/whatsapp-clone/backend/proto/message/v1"
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
 )
 
 func main() {
@@ -96,9 +99,41 @@ func main() {
 
 	messageClient := messagev1.NewMessageServiceClient(msgConn)
 
+	// --- gRPC client to media-service ---
+	mediaConn, err := grpc.NewClient(
+		cfg.MediaGRPC,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create media-service gRPC client")
+	}
+	defer mediaConn.Close()
+
+	mediaClient := mediav1.NewMediaServiceClient(mediaConn)
+
+	// --- gRPC client to user-service ---
+	userConn, err := grpc.NewClient(
+		cfg.UserGRPC,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create user-service gRPC client")
+	}
+	defer userConn.Close()
+
+	userClient := userv1.NewUserServiceClient(userConn)
+
 	// --- Repositories, Service ---
 	chatRepo := repository.NewChatPostgres(pgPool)
-	chatSvc := service.NewChatService(chatRepo, messageClient, js, log)
+	inviteRepo := repository.NewInvitePostgres(pgPool)
+	chatSvc := service.NewChatService(chatRepo, inviteRepo, messageClient, mediaClient, userClient, cfg.InvitationTTL, js, log)
+
+	// --- Account deletion ---
+	if err := accountdeletion.Subscribe(context.Background(), js, accountdeletion.ServiceChat, func(ctx context.Context, e *accountdeletion.Event) error {
+		return chatSvc.RemoveUser(ctx, e.UserID)
+	}, log); err != nil {
+		log.Fatal().Err(err).Msg("failed to subscribe to account deletions")
+	}
 
 	// --- HTTP Server ---
 	gin.SetMode(gin.ReleaseMode)
@@ -127,7 +162,7 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
 	})
 
-	httpHandler := handler.NewHTTPHandler(chatSvc, log)
+	httpHandler := handler.NewHTTPHandler(chatSvc, cfg.DevMode, log)
 	apiV1 := router.Group("/api/v1")
 	httpHandler.RegisterRoutes(apiV1)
 
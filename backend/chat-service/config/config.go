@@ -1,11 +1,17 @@
 package config
 
+import "time"
+
 type Config struct {
-	HTTPPort    string `env:"CHAT_HTTP_PORT"         envDefault:":8083"`
-	GRPCPort    string `env:"CHAT_GRPC_PORT"         envDefault:":9083"`
-	PostgresDSN string `env:"CHAT_POSTGRES_DSN"      envRequired:"true"`
-	NATSUrl     string `env:"CHAT_NATS_URL"          envDefault:"nats://nats:4222"`
-	MessageGRPC string `env:"CHAT_MESSAGE_GRPC_ADDR" envDefault:"message-service:9084"`
-	LogLevel    string `env:"CHAT_LOG_LEVEL"         envDefault:"info"`
-	OTLPEndpoint string `env:"OTLP_ENDPOINT"          envDefault:""`
+	HTTPPort      string        `env:"CHAT_HTTP_PORT"        envDefault:":8083"`
+	GRPCPort      string        `env:"CHAT_GRPC_PORT"        envDefault:":9083"`
+	PostgresDSN   string        `env:"CHAT_POSTGRES_DSN"     envRequired:"true"`
+	NATSUrl       string        `env:"CHAT_NATS_URL"         envDefault:"nats://nats:4222"`
+	MessageGRPC   string        `env:"CHAT_MESSAGE_GRPC_ADDR" envDefault:"message-service:9084"`
+	MediaGRPC     string        `env:"CHAT_MEDIA_GRPC_ADDR"  envDefault:"media-service:9086"`
+	UserGRPC      string        `env:"CHAT_USER_GRPC_ADDR"   envDefault:"user-service:9082"`
+	InvitationTTL time.Duration `env:"CHAT_INVITATION_TTL"   envDefault:"72h"`
+	DevMode       bool          `env:"CHAT_DEV_MODE"         envDefault:"false"`
+	LogLevel      string        `env:"CHAT_LOG_LEVEL"        envDefault:"info"`
+	OTLPEndpoint  string        `env:"OTLP_ENDPOINT"         envDefault:""`
 }
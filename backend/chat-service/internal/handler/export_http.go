@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/whatsapp-clone/backend/chat-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	"github.com/whatsapp-clone/backend/pkg/response"
+)
+
+// ExportChat queues an export of the chat's history. The export is built in
+// the background; its status and download link come from
+// GET /media/exports/:exportId.
+func (h *HTTPHandler) ExportChat(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return
+	}
+	chatID, ok := requireChatID(c)
+	if !ok {
+		return
+	}
+
+	var req model.ChatExportRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, apperr.NewBadRequest("invalid request body"))
+			return
+		}
+	}
+
+	export, err := h.chatSvc.ExportChat(c.Request.Context(), userID, chatID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Accepted(c, export)
+}
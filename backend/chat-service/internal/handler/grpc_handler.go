@@ -6,7 +6,9 @@ import (
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/whatsapp-clone/backend/chat-service/internal/model"
 	"github.com/whatsapp-clone/backend/chat-service/internal/repository"
 	chatv1 "github.com/whatsapp-clone/backend/proto/chat/v1"
 )
@@ -75,12 +77,26 @@ func (h *GRPCHandler) CheckChatPermission(ctx context.Context, req *chatv1.Check
 	}
 
 	resp.ChatType = string(chat.Type)
+	if chat.AutoDeleteTimer != nil {
+		resp.AutoDeleteSeconds = int64(chat.AutoDeleteTimer.Seconds())
+	}
 
 	if chat.Type == "group" {
 		group, err := h.chatRepo.GetGroup(ctx, req.ChatId)
 		if err == nil && group != nil {
 			resp.IsAdminOnly = group.IsAdminOnly
 		}
+	} else {
+		participants, err := h.chatRepo.GetParticipants(ctx, req.ChatId)
+		if err != nil {
+			h.log.Error().Err(err).Str("chat_id", req.ChatId).Msg("failed to get participants")
+			return nil, status.Errorf(codes.Internal, "failed to get participants: %v", err)
+		}
+		for _, p := range participants {
+			if p.UserID != req.UserId {
+				resp.PeerUserId = p.UserID
+			}
+		}
 	}
 
 	// Check admin status
@@ -97,3 +113,92 @@ func (h *GRPCHandler) CheckChatPermission(ctx context.Context, req *chatv1.Check
 
 	return resp, nil
 }
+
+func (h *GRPCHandler) GetUserChats(ctx context.Context, req *chatv1.GetUserChatsRequest) (*chatv1.GetUserChatsResponse, error) {
+	chatIDs, err := h.chatRepo.GetUserChats(ctx, req.UserId)
+	if err != nil {
+		h.log.Error().Err(err).Str("user_id", req.UserId).Msg("failed to get user chats")
+		return nil, status.Error(codes.Internal, "failed to get user chats")
+	}
+	return &chatv1.GetUserChatsResponse{ChatIds: chatIDs}, nil
+}
+
+// ListUserChats describes every chat the user belongs to. It looks each chat
+// up separately, which is fine for its one caller, the personal data export.
+func (h *GRPCHandler) ListUserChats(ctx context.Context, req *chatv1.ListUserChatsRequest) (*chatv1.ListUserChatsResponse, error) {
+	chatIDs, err := h.chatRepo.GetUserChats(ctx, req.UserId)
+	if err != nil {
+		h.log.Error().Err(err).Str("user_id", req.UserId).Msg("failed to get user chats")
+		return nil, status.Error(codes.Internal, "failed to get user chats")
+	}
+
+	resp := &chatv1.ListUserChatsResponse{Chats: make([]*chatv1.ChatSummary, 0, len(chatIDs))}
+	for _, chatID := range chatIDs {
+		summary, err := h.chatSummary(ctx, chatID, req.UserId)
+		if err != nil {
+			h.log.Error().Err(err).Str("chat_id", chatID).Msg("failed to describe chat")
+			return nil, status.Error(codes.Internal, "failed to describe chat")
+		}
+		if summary != nil {
+			resp.Chats = append(resp.Chats, summary)
+		}
+	}
+	return resp, nil
+}
+
+// GetChatSummary describes one chat from a member's point of view. Non-members
+// get NotFound, so the chat's existence is not revealed.
+func (h *GRPCHandler) GetChatSummary(ctx context.Context, req *chatv1.GetChatSummaryRequest) (*chatv1.GetChatSummaryResponse, error) {
+	summary, err := h.chatSummary(ctx, req.ChatId, req.UserId)
+	if err != nil {
+		h.log.Error().Err(err).Str("chat_id", req.ChatId).Msg("failed to describe chat")
+		return nil, status.Error(codes.Internal, "failed to describe chat")
+	}
+	if summary == nil {
+		return nil, status.Error(codes.NotFound, "chat not found")
+	}
+	return &chatv1.GetChatSummaryResponse{Chat: summary}, nil
+}
+
+// chatSummary describes chatID from userID's point of view, or returns nil
+// if the chat is gone or the user is no longer in it.
+func (h *GRPCHandler) chatSummary(ctx context.Context, chatID, userID string) (*chatv1.ChatSummary, error) {
+	chat, err := h.chatRepo.GetByID(ctx, chatID)
+	if err != nil || chat == nil {
+		return nil, err
+	}
+	participants, err := h.chatRepo.GetParticipants(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &chatv1.ChatSummary{
+		ChatId:         chat.ID,
+		Type:           string(chat.Type),
+		ParticipantIds: make([]string, 0, len(participants)),
+		CreatedAt:      timestamppb.New(chat.CreatedAt),
+	}
+	member := false
+	for _, p := range participants {
+		summary.ParticipantIds = append(summary.ParticipantIds, p.UserID)
+		if p.UserID == userID {
+			member = true
+			summary.Role = p.Role
+			summary.JoinedAt = timestamppb.New(p.JoinedAt)
+		}
+	}
+	if !member {
+		return nil, nil
+	}
+
+	if chat.Type == model.ChatTypeGroup {
+		group, err := h.chatRepo.GetGroup(ctx, chatID)
+		if err != nil {
+			return nil, err
+		}
+		if group != nil {
+			summary.Name = group.Name
+		}
+	}
+	return summary, nil
+}
@@ -15,11 +15,14 @@ import (
 
 type HTTPHandler struct {
 	chatSvc service.ChatService
+	devMode bool
 	log     zerolog.Logger
 }
 
-func NewHTTPHandler(chatSvc service.ChatService, log zerolog.Logger) *HTTPHandler {
-	return &HTTPHandler{chatSvc: chatSvc, log: log}
+// NewHTTPHandler creates the chat HTTP handler. In dev mode disappearing
+// messages also accept a "10s" timer, so they can be seen expiring locally.
+func NewHTTPHandler(chatSvc service.ChatService, devMode bool, log zerolog.Logger) *HTTPHandler {
+	return &HTTPHandler{chatSvc: chatSvc, devMode: devMode, log: log}
 }
 
 func (h *HTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
@@ -36,6 +39,17 @@ func (h *HTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
 		chats.PUT("/:id/pin", h.PinChat)
 		chats.PUT("/:id/avatar", h.UploadGroupAvatar)
 		chats.PUT("/:id/disappearing", h.SetDisappearingMessages)
+		chats.POST("/:id/export", h.ExportChat)
+		chats.GET("/:id/invite-link", h.GetInviteLink)
+		chats.POST("/:id/invite-link", h.ManageInviteLink)
+		chats.GET("/:id/join-requests", h.ListJoinRequests)
+		chats.POST("/:id/join-requests/:userId/approve", h.ApproveJoinRequest)
+		chats.POST("/:id/join-requests/:userId/reject", h.RejectJoinRequest)
+		chats.GET("/invite/:code", h.PreviewInvite)
+		chats.POST("/invite/:code/join", h.JoinViaInvite)
+		chats.GET("/invitations", h.ListInvitations)
+		chats.POST("/invitations/:invitationId/accept", h.AcceptInvitation)
+		chats.POST("/invitations/:invitationId/decline", h.DeclineInvitation)
 	}
 }
 
@@ -130,16 +144,21 @@ func (h *HTTPHandler) CreateChat(c *gin.Context) {
 			Description: description,
 			MemberIDs:   memberIDs,
 		}
-		chat, group, err := h.chatSvc.CreateGroup(c.Request.Context(), userID, &req)
+		chat, group, invitations, err := h.chatSvc.CreateGroup(c.Request.Context(), userID, &req)
 		if err != nil {
 			response.Error(c, err)
 			return
 		}
 
-		response.Created(c, flattenChat(&model.ChatListItem{
+		body := flattenChat(&model.ChatListItem{
 			Chat:  *chat,
 			Group: group,
-		}))
+		})
+		// Members who could only be invited are listed apart.
+		if len(invitations) > 0 {
+			body["invitations"] = invitations
+		}
+		response.Created(c, body)
 	}
 }
 
@@ -245,10 +264,15 @@ func (h *HTTPHandler) AddMember(c *gin.Context) {
 		return
 	}
 
-	if err := h.chatSvc.AddMember(c.Request.Context(), userID, chatID, req.UserID); err != nil {
+	invitation, err := h.chatSvc.AddMember(c.Request.Context(), userID, chatID, req.UserID)
+	if err != nil {
 		response.Error(c, err)
 		return
 	}
+	if invitation != nil {
+		response.Accepted(c, invitation)
+		return
+	}
 
 	response.NoContent(c)
 }
@@ -451,6 +475,13 @@ func (h *HTTPHandler) SetDisappearingMessages(c *gin.Context) {
 	case "90d":
 		d := 90 * 24 * time.Hour
 		duration = &d
+	case "10s":
+		if !h.devMode {
+			response.Error(c, apperr.NewBadRequest("invalid timer value, must be 'off', '24h', '7d', or '90d'"))
+			return
+		}
+		d := 10 * time.Second
+		duration = &d
 	default:
 		response.Error(c, apperr.NewBadRequest("invalid timer value, must be 'off', '24h', '7d', or '90d'"))
 		return
@@ -517,6 +548,11 @@ func flattenChat(item *model.ChatListItem) gin.H {
 		}
 	}
 
+	var autoDeleteSeconds int64
+	if item.Chat.AutoDeleteTimer != nil {
+		autoDeleteSeconds = int64(item.Chat.AutoDeleteTimer.Seconds())
+	}
+
 	return gin.H{
 		"chat_id":      item.Chat.ID,
 		"type":         string(item.Chat.Type),
@@ -529,5 +565,7 @@ func flattenChat(item *model.ChatListItem) gin.H {
 		"is_muted":     isMuted,
 		"created_at":   item.Chat.CreatedAt.Format(time.RFC3339),
 		"updated_at":   item.Chat.UpdatedAt.Format(time.RFC3339),
+
+		"auto_delete_seconds": autoDeleteSeconds,
 	}
 }
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/whatsapp-clone/backend/chat-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	"github.com/whatsapp-clone/backend/pkg/response"
+)
+
+func (h *HTTPHandler) GetInviteLink(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return
+	}
+	chatID, ok := requireChatID(c)
+	if !ok {
+		return
+	}
+
+	link, err := h.chatSvc.GetInviteLink(c.Request.Context(), userID, chatID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, link)
+}
+
+func (h *HTTPHandler) ManageInviteLink(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return
+	}
+	chatID, ok := requireChatID(c)
+	if !ok {
+		return
+	}
+
+	var req model.InviteLinkRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, apperr.NewBadRequest("invalid request body"))
+			return
+		}
+	}
+
+	link, err := h.chatSvc.ManageInviteLink(c.Request.Context(), userID, chatID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if link == nil {
+		response.NoContent(c)
+		return
+	}
+	response.OK(c, link)
+}
+
+func (h *HTTPHandler) PreviewInvite(c *gin.Context) {
+	if getUserID(c) == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return
+	}
+
+	preview, err := h.chatSvc.PreviewInvite(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, preview)
+}
+
+func (h *HTTPHandler) JoinViaInvite(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return
+	}
+
+	result, err := h.chatSvc.JoinViaInvite(c.Request.Context(), userID, c.Param("code"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, result)
+}
+
+func (h *HTTPHandler) ListJoinRequests(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return
+	}
+	chatID, ok := requireChatID(c)
+	if !ok {
+		return
+	}
+
+	requests, err := h.chatSvc.ListJoinRequests(c.Request.Context(), userID, chatID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, requests)
+}
+
+func (h *HTTPHandler) ApproveJoinRequest(c *gin.Context) {
+	h.decideJoinRequest(c, true)
+}
+
+func (h *HTTPHandler) RejectJoinRequest(c *gin.Context) {
+	h.decideJoinRequest(c, false)
+}
+
+func (h *HTTPHandler) decideJoinRequest(c *gin.Context, approve bool) {
+	userID := getUserID(c)
+	if userID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return
+	}
+	chatID, ok := requireChatID(c)
+	if !ok {
+		return
+	}
+
+	jr, err := h.chatSvc.DecideJoinRequest(c.Request.Context(), userID, chatID, c.Param("userId"), approve)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, jr)
+}
+
+func (h *HTTPHandler) ListInvitations(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return
+	}
+
+	invitations, err := h.chatSvc.ListInvitations(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, invitations)
+}
+
+func (h *HTTPHandler) AcceptInvitation(c *gin.Context) {
+	h.decideInvitation(c, true)
+}
+
+func (h *HTTPHandler) DeclineInvitation(c *gin.Context) {
+	h.decideInvitation(c, false)
+}
+
+func (h *HTTPHandler) decideInvitation(c *gin.Context, accept bool) {
+	userID := getUserID(c)
+	if userID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return
+	}
+
+	decide := h.chatSvc.DeclineInvitation
+	if accept {
+		decide = h.chatSvc.AcceptInvitation
+	}
+	invitation, err := decide(c.Request.Context(), userID, c.Param("invitationId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, invitation)
+}
@@ -9,23 +9,25 @@ const (
 	ChatTypeGroup  ChatType = "group"
 )
 
+// Chat is a direct or group conversation. AutoDeleteTimer is the chat-wide
+// disappearing-messages timer; nil means messages never expire.
 type Chat struct {
-	ID        string    `json:"id"         db:"id"`
-	Type      ChatType  `json:"type"       db:"type"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID              string         `json:"id"                          db:"id"`
+	Type            ChatType       `json:"type"                        db:"type"`
+	AutoDeleteTimer *time.Duration `json:"auto_delete_timer,omitempty" db:"auto_delete_timer"`
+	CreatedAt       time.Time      `json:"created_at"                  db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"                  db:"updated_at"`
 }
 
 type ChatParticipant struct {
-	ID              string         `json:"id"                        db:"id"`
-	ChatID          string         `json:"chat_id"                   db:"chat_id"`
-	UserID          string         `json:"user_id"                   db:"user_id"`
-	Role            string         `json:"role"                      db:"role"`
-	IsMuted         bool           `json:"is_muted"                  db:"is_muted"`
-	MuteUntil       *time.Time     `json:"mute_until"                db:"mute_until"`
-	IsPinned        bool           `json:"is_pinned"                 db:"is_pinned"`
-	AutoDeleteTimer *time.Duration `json:"auto_delete_timer,omitempty" db:"auto_delete_timer"`
-	JoinedAt        time.Time      `json:"joined_at"                 db:"joined_at"`
+	ID        string     `json:"id"         db:"id"`
+	ChatID    string     `json:"chat_id"    db:"chat_id"`
+	UserID    string     `json:"user_id"    db:"user_id"`
+	Role      string     `json:"role"       db:"role"`
+	IsMuted   bool       `json:"is_muted"   db:"is_muted"`
+	MuteUntil *time.Time `json:"mute_until" db:"mute_until"`
+	IsPinned  bool       `json:"is_pinned"  db:"is_pinned"`
+	JoinedAt  time.Time  `json:"joined_at"  db:"joined_at"`
 }
 
 type Group struct {
@@ -0,0 +1,81 @@
+package model
+
+import "time"
+
+// InviteLink is a group's shareable join code. A group has at most one
+// active link; rotating replaces the code and revoking deletes the row.
+type InviteLink struct {
+	ChatID           string    `json:"chat_id"           db:"chat_id"`
+	Code             string    `json:"code"              db:"code"`
+	RequiresApproval bool      `json:"requires_approval" db:"requires_approval"`
+	CreatedBy        string    `json:"created_by"        db:"created_by"`
+	CreatedAt        time.Time `json:"created_at"        db:"created_at"`
+}
+
+// InvitePreview is what anyone holding an invite code can see before joining.
+type InvitePreview struct {
+	ChatID           string `json:"chat_id"`
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	AvatarURL        string `json:"avatar_url"`
+	MemberCount      int    `json:"member_count"`
+	RequiresApproval bool   `json:"requires_approval"`
+}
+
+type JoinRequestStatus string
+
+const (
+	JoinRequestPending  JoinRequestStatus = "pending"
+	JoinRequestApproved JoinRequestStatus = "approved"
+	JoinRequestRejected JoinRequestStatus = "rejected"
+)
+
+// JoinRequest is a queued request to join a group whose invite link requires
+// admin approval.
+type JoinRequest struct {
+	ID        string            `json:"id"                   db:"id"`
+	ChatID    string            `json:"chat_id"              db:"chat_id"`
+	UserID    string            `json:"user_id"              db:"user_id"`
+	Status    JoinRequestStatus `json:"status"               db:"status"`
+	DecidedBy *string           `json:"decided_by,omitempty" db:"decided_by"`
+	CreatedAt time.Time         `json:"created_at"           db:"created_at"`
+	DecidedAt *time.Time        `json:"decided_at,omitempty" db:"decided_at"`
+}
+
+// JoinResult reports whether a join via invite link took effect immediately
+// or was queued for admin approval.
+type JoinResult struct {
+	ChatID  string       `json:"chat_id"`
+	Joined  bool         `json:"joined"`
+	Request *JoinRequest `json:"request,omitempty"`
+}
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+)
+
+// GroupInvitation invites a user to a group whose admin may not add them
+// directly under the user's group_add privacy setting. It is delivered as a
+// group_invite message in the direct chat between the admin and the user
+// (MessageID), and lapses at ExpiresAt unless accepted or declined.
+type GroupInvitation struct {
+	ID        string           `json:"id"                   db:"id"`
+	ChatID    string           `json:"chat_id"              db:"chat_id"`
+	GroupName string           `json:"group_name"           db:"-"`
+	UserID    string           `json:"user_id"              db:"user_id"`
+	InvitedBy string           `json:"invited_by"           db:"invited_by"`
+	Status    InvitationStatus `json:"status"               db:"status"`
+	MessageID string           `json:"message_id,omitempty" db:"message_id"`
+	CreatedAt time.Time        `json:"created_at"           db:"created_at"`
+	ExpiresAt time.Time        `json:"expires_at"           db:"expires_at"`
+	DecidedAt *time.Time       `json:"decided_at,omitempty" db:"decided_at"`
+}
+
+// Expired reports whether the invitation is still pending past its expiry.
+func (i *GroupInvitation) Expired(now time.Time) bool {
+	return i.Status == InvitationPending && !now.Before(i.ExpiresAt)
+}
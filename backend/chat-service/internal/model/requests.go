@@ -21,6 +21,27 @@ type AddMemberRequest struct {
 	UserID string `json:"user_id" binding:"required"`
 }
 
+// InviteLinkRequest manages a group's invite link. Action is "create"
+// (default; returns the current link if one exists), "rotate" or "revoke".
+type InviteLinkRequest struct {
+	Action           string `json:"action"`
+	RequiresApproval *bool  `json:"requires_approval"`
+}
+
+// Chat export formats.
+const (
+	ChatExportFormatTxt  = "txt"
+	ChatExportFormatHTML = "html"
+)
+
+// ChatExportRequest asks for a chat's history as a WhatsApp-style text file
+// ("txt", the default) or a standalone HTML transcript ("html"), optionally
+// bundled with the chat's media files.
+type ChatExportRequest struct {
+	Format       string `json:"format"`
+	IncludeMedia bool   `json:"include_media"`
+}
+
 type ChatListItem struct {
 	Chat         Chat              `json:"chat"`
 	Participants []ChatParticipant `json:"participants"`
@@ -35,4 +56,5 @@ type MessagePreview struct {
 	Type      string `json:"type"`
 	Body      string `json:"body"`
 	CreatedAt int64  `json:"created_at"`
+	Seq       int64  `json:"seq"`
 }
@@ -50,7 +50,7 @@ func (r *chatPostgres) CreateDirect(ctx context.Context, chat *model.Chat, parti
 
 func (r *chatPostgres) FindDirectChat(ctx context.Context, userID1, userID2 string) (*model.Chat, error) {
 	query := `
-		SELECT c.id, c.type, c.created_at, c.updated_at
+		SELECT c.id, c.type, c.auto_delete_timer, c.created_at, c.updated_at
 		FROM chats c
 		JOIN chat_participants cp1 ON c.id = cp1.chat_id AND cp1.user_id = $1
 		JOIN chat_participants cp2 ON c.id = cp2.chat_id AND cp2.user_id = $2
@@ -61,7 +61,7 @@ func (r *chatPostgres) FindDirectChat(ctx context.Context, userID1, userID2 stri
 
 	var chat model.Chat
 	err := r.pool.QueryRow(ctx, query, userID1, userID2).
-		Scan(&chat.ID, &chat.Type, &chat.CreatedAt, &chat.UpdatedAt)
+		Scan(&chat.ID, &chat.Type, &chat.AutoDeleteTimer, &chat.CreatedAt, &chat.UpdatedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -112,8 +112,8 @@ func (r *chatPostgres) CreateGroup(ctx context.Context, chat *model.Chat, group
 func (r *chatPostgres) GetByID(ctx context.Context, chatID string) (*model.Chat, error) {
 	var chat model.Chat
 	err := r.pool.QueryRow(ctx,
-		`SELECT id, type, created_at, updated_at FROM chats WHERE id = $1`, chatID,
-	).Scan(&chat.ID, &chat.Type, &chat.CreatedAt, &chat.UpdatedAt)
+		`SELECT id, type, auto_delete_timer, created_at, updated_at FROM chats WHERE id = $1`, chatID,
+	).Scan(&chat.ID, &chat.Type, &chat.AutoDeleteTimer, &chat.CreatedAt, &chat.UpdatedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -237,7 +237,7 @@ func (r *chatPostgres) UpdatePin(ctx context.Context, chatID, userID string, isP
 func (r *chatPostgres) GetGroup(ctx context.Context, chatID string) (*model.Group, error) {
 	var g model.Group
 	err := r.pool.QueryRow(ctx,
-		`SELECT chat_id, name, description, avatar_url, created_by, is_admin_only, created_at, updated_at
+		`SELECT chat_id, name, description, avatar_url, COALESCE(created_by::text, ''), is_admin_only, created_at, updated_at
 		 FROM groups WHERE chat_id = $1`, chatID,
 	).Scan(&g.ChatID, &g.Name, &g.Description, &g.AvatarURL, &g.CreatedBy, &g.IsAdminOnly, &g.CreatedAt, &g.UpdatedAt)
 	if err != nil {
@@ -311,10 +311,10 @@ func (r *chatPostgres) UpdateGroupRaw(ctx context.Context, chatID string, fields
 	return nil
 }
 
-func (r *chatPostgres) UpdateAutoDeleteTimer(ctx context.Context, chatID, userID string, timer *time.Duration) error {
+func (r *chatPostgres) UpdateAutoDeleteTimer(ctx context.Context, chatID string, timer *time.Duration) error {
 	_, err := r.pool.Exec(ctx,
-		`UPDATE chat_participants SET auto_delete_timer = $1 WHERE chat_id = $2 AND user_id = $3`,
-		timer, chatID, userID,
+		`UPDATE chats SET auto_delete_timer = $1, updated_at = NOW() WHERE id = $2`,
+		timer, chatID,
 	)
 	if err != nil {
 		return fmt.Errorf("update auto delete timer: %w", err)
@@ -59,6 +59,6 @@ type ChatRepository interface {
 	// UpdateGroupRaw updates group fields using a raw map (for avatar updates etc.).
 	UpdateGroupRaw(ctx context.Context, chatID string, fields map[string]interface{}) error
 
-	// UpdateAutoDeleteTimer sets the auto-delete timer for a participant.
-	UpdateAutoDeleteTimer(ctx context.Context, chatID, userID string, timer *time.Duration) error
+	// UpdateAutoDeleteTimer sets the chat-wide disappearing-messages timer (nil = off).
+	UpdateAutoDeleteTimer(ctx context.Context, chatID string, timer *time.Duration) error
 }
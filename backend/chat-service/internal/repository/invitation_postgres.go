@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/whatsapp-clone/backend/chat-service/internal/model"
+)
+
+const invitationColumns = `i.id, i.chat_id, i.user_id, COALESCE(i.invited_by::text, ''), i.status,
+	COALESCE(i.message_id, ''), i.created_at, i.expires_at, i.decided_at`
+
+func scanInvitation(row pgx.Row, extra ...any) (*model.GroupInvitation, error) {
+	var inv model.GroupInvitation
+	dest := []any{&inv.ID, &inv.ChatID, &inv.UserID, &inv.InvitedBy, &inv.Status,
+		&inv.MessageID, &inv.CreatedAt, &inv.ExpiresAt, &inv.DecidedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *invitePostgres) CreateInvitation(ctx context.Context, inv *model.GroupInvitation) (*model.GroupInvitation, error) {
+	created, err := scanInvitation(r.pool.QueryRow(ctx,
+		`INSERT INTO group_invitations AS i (id, chat_id, user_id, invited_by, status, created_at, expires_at)
+		 VALUES ($1, $2, $3, $4, 'pending', $5, $6)
+		 ON CONFLICT (chat_id, user_id) WHERE status = 'pending' DO UPDATE SET
+		     invited_by = EXCLUDED.invited_by,
+		     message_id = NULL,
+		     created_at = EXCLUDED.created_at,
+		     expires_at = EXCLUDED.expires_at
+		 RETURNING `+invitationColumns,
+		inv.ID, inv.ChatID, inv.UserID, inv.InvitedBy, inv.CreatedAt, inv.ExpiresAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("create invitation: %w", err)
+	}
+	return created, nil
+}
+
+func (r *invitePostgres) SetInvitationMessage(ctx context.Context, invitationID, messageID string) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE group_invitations SET message_id = $1 WHERE id = $2`,
+		messageID, invitationID,
+	)
+	if err != nil {
+		return fmt.Errorf("set invitation message: %w", err)
+	}
+	return nil
+}
+
+func (r *invitePostgres) GetInvitation(ctx context.Context, invitationID string) (*model.GroupInvitation, error) {
+	var groupName string
+	inv, err := scanInvitation(r.pool.QueryRow(ctx,
+		`SELECT `+invitationColumns+`, g.name FROM group_invitations i
+		 JOIN groups g ON g.chat_id = i.chat_id
+		 WHERE i.id = $1`, invitationID,
+	), &groupName)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get invitation: %w", err)
+	}
+	inv.GroupName = groupName
+	return inv, nil
+}
+
+func (r *invitePostgres) ListPendingInvitations(ctx context.Context, userID string) ([]model.GroupInvitation, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+invitationColumns+`, g.name FROM group_invitations i
+		 JOIN groups g ON g.chat_id = i.chat_id
+		 WHERE i.user_id = $1 AND i.status = 'pending' AND i.expires_at > NOW()
+		 ORDER BY i.created_at DESC`, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list invitations: %w", err)
+	}
+	defer rows.Close()
+
+	invitations := make([]model.GroupInvitation, 0)
+	for rows.Next() {
+		var groupName string
+		inv, err := scanInvitation(rows, &groupName)
+		if err != nil {
+			return nil, fmt.Errorf("scan invitation: %w", err)
+		}
+		inv.GroupName = groupName
+		invitations = append(invitations, *inv)
+	}
+	return invitations, rows.Err()
+}
+
+func (r *invitePostgres) AcceptInvitation(ctx context.Context, invitationID string, p *model.ChatParticipant) (*model.GroupInvitation, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	inv, err := decideInvitation(ctx, tx, invitationID, model.InvitationAccepted)
+	if err != nil || inv == nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO chat_participants (id, chat_id, user_id, role, is_muted, is_pinned, joined_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (chat_id, user_id) DO NOTHING`,
+		p.ID, p.ChatID, p.UserID, p.Role, p.IsMuted, p.IsPinned, p.JoinedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("add participant: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return inv, nil
+}
+
+func (r *invitePostgres) DeclineInvitation(ctx context.Context, invitationID string) (*model.GroupInvitation, error) {
+	return decideInvitation(ctx, r.pool, invitationID, model.InvitationDeclined)
+}
+
+// decideInvitation settles a pending invitation that has not expired.
+func decideInvitation(ctx context.Context, q rowQuerier, invitationID string, status model.InvitationStatus) (*model.GroupInvitation, error) {
+	inv, err := scanInvitation(q.QueryRow(ctx,
+		`UPDATE group_invitations AS i SET status = $1, decided_at = NOW()
+		 WHERE i.id = $2 AND i.status = 'pending' AND i.expires_at > NOW()
+		 RETURNING `+invitationColumns,
+		status, invitationID,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("decide invitation: %w", err)
+	}
+	return inv, nil
+}
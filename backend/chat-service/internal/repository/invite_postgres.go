@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/whatsapp-clone/backend/chat-service/internal/model"
+)
+
+type invitePostgres struct {
+	pool *pgxpool.Pool
+}
+
+func NewInvitePostgres(pool *pgxpool.Pool) InviteRepository {
+	return &invitePostgres{pool: pool}
+}
+
+const joinRequestColumns = `id, chat_id, user_id, status, decided_by, created_at, decided_at`
+
+func scanJoinRequest(row pgx.Row) (*model.JoinRequest, error) {
+	var jr model.JoinRequest
+	err := row.Scan(&jr.ID, &jr.ChatID, &jr.UserID, &jr.Status, &jr.DecidedBy, &jr.CreatedAt, &jr.DecidedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &jr, nil
+}
+
+func (r *invitePostgres) GetInviteLink(ctx context.Context, chatID string) (*model.InviteLink, error) {
+	return r.getInviteLink(ctx, `WHERE chat_id = $1`, chatID)
+}
+
+func (r *invitePostgres) GetInviteLinkByCode(ctx context.Context, code string) (*model.InviteLink, error) {
+	return r.getInviteLink(ctx, `WHERE code = $1`, code)
+}
+
+func (r *invitePostgres) getInviteLink(ctx context.Context, where string, arg string) (*model.InviteLink, error) {
+	var link model.InviteLink
+	err := r.pool.QueryRow(ctx,
+		`SELECT chat_id, code, requires_approval, COALESCE(created_by::text, ''), created_at FROM group_invite_links `+where, arg,
+	).Scan(&link.ChatID, &link.Code, &link.RequiresApproval, &link.CreatedBy, &link.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get invite link: %w", err)
+	}
+	return &link, nil
+}
+
+func (r *invitePostgres) UpsertInviteLink(ctx context.Context, link *model.InviteLink) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO group_invite_links (chat_id, code, requires_approval, created_by, created_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (chat_id) DO UPDATE SET
+		     code = EXCLUDED.code,
+		     requires_approval = EXCLUDED.requires_approval,
+		     created_by = EXCLUDED.created_by,
+		     created_at = EXCLUDED.created_at`,
+		link.ChatID, link.Code, link.RequiresApproval, link.CreatedBy, link.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert invite link: %w", err)
+	}
+	return nil
+}
+
+func (r *invitePostgres) UpdateInviteApproval(ctx context.Context, chatID string, requiresApproval bool) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE group_invite_links SET requires_approval = $1 WHERE chat_id = $2`,
+		requiresApproval, chatID,
+	)
+	if err != nil {
+		return fmt.Errorf("update invite approval: %w", err)
+	}
+	return nil
+}
+
+func (r *invitePostgres) DeleteInviteLink(ctx context.Context, chatID string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM group_invite_links WHERE chat_id = $1`, chatID)
+	if err != nil {
+		return fmt.Errorf("delete invite link: %w", err)
+	}
+	return nil
+}
+
+func (r *invitePostgres) CreateJoinRequest(ctx context.Context, req *model.JoinRequest) (*model.JoinRequest, error) {
+	created, err := scanJoinRequest(r.pool.QueryRow(ctx,
+		`INSERT INTO group_join_requests (id, chat_id, user_id, status, created_at)
+		 VALUES ($1, $2, $3, 'pending', $4)
+		 ON CONFLICT (chat_id, user_id) WHERE status = 'pending' DO NOTHING
+		 RETURNING `+joinRequestColumns,
+		req.ID, req.ChatID, req.UserID, req.CreatedAt,
+	))
+	if err == nil {
+		return created, nil
+	}
+	if err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("create join request: %w", err)
+	}
+
+	// Already pending: return the existing request.
+	existing, err := scanJoinRequest(r.pool.QueryRow(ctx,
+		`SELECT `+joinRequestColumns+` FROM group_join_requests
+		 WHERE chat_id = $1 AND user_id = $2 AND status = 'pending'`,
+		req.ChatID, req.UserID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("get pending join request: %w", err)
+	}
+	return existing, nil
+}
+
+func (r *invitePostgres) ListPendingJoinRequests(ctx context.Context, chatID string) ([]model.JoinRequest, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+joinRequestColumns+` FROM group_join_requests
+		 WHERE chat_id = $1 AND status = 'pending'
+		 ORDER BY created_at`, chatID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list join requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := make([]model.JoinRequest, 0)
+	for rows.Next() {
+		jr, err := scanJoinRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan join request: %w", err)
+		}
+		requests = append(requests, *jr)
+	}
+	return requests, rows.Err()
+}
+
+func (r *invitePostgres) ApproveJoinRequest(ctx context.Context, chatID, userID, decidedBy string, p *model.ChatParticipant) (*model.JoinRequest, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	jr, err := decideJoinRequest(ctx, tx, chatID, userID, decidedBy, model.JoinRequestApproved)
+	if err != nil || jr == nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO chat_participants (id, chat_id, user_id, role, is_muted, is_pinned, joined_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (chat_id, user_id) DO NOTHING`,
+		p.ID, p.ChatID, p.UserID, p.Role, p.IsMuted, p.IsPinned, p.JoinedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("add participant: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return jr, nil
+}
+
+func (r *invitePostgres) RejectJoinRequest(ctx context.Context, chatID, userID, decidedBy string) (*model.JoinRequest, error) {
+	return decideJoinRequest(ctx, r.pool, chatID, userID, decidedBy, model.JoinRequestRejected)
+}
+
+// rowQuerier is satisfied by both *pgxpool.Pool and pgx.Tx.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func decideJoinRequest(ctx context.Context, q rowQuerier, chatID, userID, decidedBy string, status model.JoinRequestStatus) (*model.JoinRequest, error) {
+	jr, err := scanJoinRequest(q.QueryRow(ctx,
+		`UPDATE group_join_requests SET status = $1, decided_by = $2, decided_at = NOW()
+		 WHERE chat_id = $3 AND user_id = $4 AND status = 'pending'
+		 RETURNING `+joinRequestColumns,
+		status, decidedBy, chatID, userID,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("decide join request: %w", err)
+	}
+	return jr, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/whatsapp-clone/backend/chat-service/internal/model"
+)
+
+type InviteRepository interface {
+	// GetInviteLink returns the group's active invite link, or nil if none.
+	GetInviteLink(ctx context.Context, chatID string) (*model.InviteLink, error)
+
+	// GetInviteLinkByCode resolves an invite code, or returns nil if it is unknown or revoked.
+	GetInviteLinkByCode(ctx context.Context, code string) (*model.InviteLink, error)
+
+	// UpsertInviteLink creates the group's invite link or replaces its code and settings.
+	UpsertInviteLink(ctx context.Context, link *model.InviteLink) error
+
+	// UpdateInviteApproval toggles whether joins via the link need admin approval.
+	UpdateInviteApproval(ctx context.Context, chatID string, requiresApproval bool) error
+
+	// DeleteInviteLink revokes the group's invite link. Pending join requests are kept.
+	DeleteInviteLink(ctx context.Context, chatID string) error
+
+	// CreateJoinRequest queues a pending join request. If the user already has a
+	// pending request for the chat, that request is returned instead.
+	CreateJoinRequest(ctx context.Context, req *model.JoinRequest) (*model.JoinRequest, error)
+
+	// ListPendingJoinRequests returns a chat's pending join requests, oldest first.
+	ListPendingJoinRequests(ctx context.Context, chatID string) ([]model.JoinRequest, error)
+
+	// ApproveJoinRequest marks the user's pending request approved and adds the
+	// participant in a single transaction. Returns nil if no request was pending.
+	ApproveJoinRequest(ctx context.Context, chatID, userID, decidedBy string, p *model.ChatParticipant) (*model.JoinRequest, error)
+
+	// RejectJoinRequest marks the user's pending request rejected.
+	// Returns nil if no request was pending.
+	RejectJoinRequest(ctx context.Context, chatID, userID, decidedBy string) (*model.JoinRequest, error)
+
+	// CreateInvitation stores a pending group invitation. If the user already
+	// has one pending for the chat, it is renewed with inv's inviter and
+	// expiry and returned instead.
+	CreateInvitation(ctx context.Context, inv *model.GroupInvitation) (*model.GroupInvitation, error)
+
+	// SetInvitationMessage records the message that delivered the invitation.
+	SetInvitationMessage(ctx context.Context, invitationID, messageID string) error
+
+	// GetInvitation returns an invitation with its group's name, or nil if none.
+	GetInvitation(ctx context.Context, invitationID string) (*model.GroupInvitation, error)
+
+	// ListPendingInvitations returns the user's unexpired pending invitations,
+	// newest first, with their groups' names.
+	ListPendingInvitations(ctx context.Context, userID string) ([]model.GroupInvitation, error)
+
+	// AcceptInvitation marks a pending, unexpired invitation accepted and adds
+	// the participant in a single transaction. Returns nil if it was not pending.
+	AcceptInvitation(ctx context.Context, invitationID string, p *model.ChatParticipant) (*model.GroupInvitation, error)
+
+	// DeclineInvitation marks a pending invitation declined.
+	// Returns nil if it was not pending.
+	DeclineInvitation(ctx context.Context, invitationID string) (*model.GroupInvitation, error)
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
+)
+
+// checkNotBlocked refuses to bring callerID together with any of otherIDs
+// that it has a block with, in either direction.
+func (s *chatServiceImpl) checkNotBlocked(ctx context.Context, callerID string, otherIDs ...string) error {
+	resp, err := s.userClient.GetBlockRelations(ctx, &userv1.GetBlockRelationsRequest{
+		UserId:       callerID,
+		OtherUserIds: otherIDs,
+	})
+	if err != nil {
+		return apperr.NewInternal("failed to check blocks", err)
+	}
+	for _, r := range resp.Relations {
+		if r.Blocking {
+			return apperr.Wrap(apperr.CodeUserBlocked, 403, "unblock this contact first", nil)
+		}
+		if r.BlockedBy {
+			return apperr.Wrap(apperr.CodeUserBlocked, 403, "user not available", nil)
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/whatsapp-clone/backend/chat-service/internal/model"
@@ -11,8 +12,10 @@ type ChatService interface {
 	// CreateDirectChat creates or returns an existing direct chat between two users.
 	CreateDirectChat(ctx context.Context, callerID string, req *model.CreateDirectChatRequest) (*model.Chat, error)
 
-	// CreateGroup creates a new group chat with the caller as admin.
-	CreateGroup(ctx context.Context, callerID string, req *model.CreateGroupRequest) (*model.Chat, *model.Group, error)
+	// CreateGroup creates a new group chat with the caller as admin. Members
+	// whose privacy settings keep the caller from adding them are invited
+	// instead; their invitations are returned.
+	CreateGroup(ctx context.Context, callerID string, req *model.CreateGroupRequest) (*model.Chat, *model.Group, []model.GroupInvitation, error)
 
 	// ListChats returns all chats for a user with last message previews and unread counts.
 	ListChats(ctx context.Context, userID string) ([]*model.ChatListItem, error)
@@ -20,8 +23,10 @@ type ChatService interface {
 	// GetChat retrieves a single chat by ID with full details.
 	GetChat(ctx context.Context, callerID, chatID string) (*model.ChatListItem, error)
 
-	// AddMember adds a user to a group chat (admin only).
-	AddMember(ctx context.Context, callerID, chatID, targetUserID string) error
+	// AddMember adds a user to a group chat (admin only). If the user's privacy
+	// settings keep the caller from adding them, they are sent an invitation
+	// instead, which is returned.
+	AddMember(ctx context.Context, callerID, chatID, targetUserID string) (*model.GroupInvitation, error)
 
 	// RemoveMember removes a user from a group chat (admin only, or self-removal).
 	RemoveMember(ctx context.Context, callerID, chatID, targetUserID string) error
@@ -44,6 +49,46 @@ type ChatService interface {
 	// UploadGroupAvatar updates the avatar for a group chat (admin only).
 	UploadGroupAvatar(ctx context.Context, chatID, userID string) (string, error)
 
-	// SetDisappearingMessages sets the auto-delete timer for a chat participant.
+	// SetDisappearingMessages sets the disappearing-messages timer for the whole chat.
+	// Messages sent afterwards expire once the timer elapses.
 	SetDisappearingMessages(ctx context.Context, chatID, userID string, timer *time.Duration) error
+
+	// ManageInviteLink creates, rotates, or revokes a group's invite link (admin only).
+	// Returns nil after a revoke.
+	ManageInviteLink(ctx context.Context, callerID, chatID string, req *model.InviteLinkRequest) (*model.InviteLink, error)
+
+	// GetInviteLink returns the group's active invite link (admin only).
+	GetInviteLink(ctx context.Context, callerID, chatID string) (*model.InviteLink, error)
+
+	// PreviewInvite returns the group's public details for an invite code.
+	PreviewInvite(ctx context.Context, code string) (*model.InvitePreview, error)
+
+	// JoinViaInvite adds the user to the group behind an invite code, or queues a
+	// join request if the link requires admin approval.
+	JoinViaInvite(ctx context.Context, userID, code string) (*model.JoinResult, error)
+
+	// ListJoinRequests returns the group's pending join requests (admin only).
+	ListJoinRequests(ctx context.Context, callerID, chatID string) ([]model.JoinRequest, error)
+
+	// DecideJoinRequest approves (adding the user) or rejects a pending join request (admin only).
+	DecideJoinRequest(ctx context.Context, callerID, chatID, targetUserID string, approve bool) (*model.JoinRequest, error)
+
+	// ListInvitations returns the user's pending group invitations.
+	ListInvitations(ctx context.Context, userID string) ([]model.GroupInvitation, error)
+
+	// AcceptInvitation adds the user to the group they were invited to.
+	AcceptInvitation(ctx context.Context, userID, invitationID string) (*model.GroupInvitation, error)
+
+	// DeclineInvitation turns down a pending group invitation.
+	DeclineInvitation(ctx context.Context, userID, invitationID string) (*model.GroupInvitation, error)
+
+	// ExportChat queues an export of the chat's history as the caller sees it.
+	// It returns the export as JSON; an identical export already in progress
+	// is returned instead of queueing another.
+	ExportChat(ctx context.Context, userID, chatID string, req *model.ChatExportRequest) (json.RawMessage, error)
+
+	// RemoveUser takes a deleted account out of every group it belongs to,
+	// handing admin rights on as if the user had left. Direct chats are left to
+	// the user record's cascade.
+	RemoveUser(ctx context.Context, userID string) error
 }
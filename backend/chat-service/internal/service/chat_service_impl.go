@@ -12,24 +12,38 @@ import (
 	"github.com/whatsapp-clone/backend/chat-service/internal/model"
 	"github.com/whatsapp-clone/backend/chat-service/internal/repository"
 	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	mediav1 "github.com/whatsapp-clone/backend/proto/media/v1"
 	messagev1 "github.com/whatsapp-clone/backend/proto/message/v1"
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
 )
 
 type chatServiceImpl struct {
 	chatRepo      repository.ChatRepository
+	inviteRepo    repository.InviteRepository
 	messageClient messagev1.MessageServiceClient
+	mediaClient   mediav1.MediaServiceClient
+	userClient    userv1.UserServiceClient
+	invitationTTL time.Duration
 	eventPublisher
 }
 
 func NewChatService(
 	chatRepo repository.ChatRepository,
+	inviteRepo repository.InviteRepository,
 	messageClient messagev1.MessageServiceClient,
+	mediaClient mediav1.MediaServiceClient,
+	userClient userv1.UserServiceClient,
+	invitationTTL time.Duration,
 	js nats.JetStreamContext,
 	log zerolog.Logger,
 ) ChatService {
 	return &chatServiceImpl{
 		chatRepo:      chatRepo,
+		inviteRepo:    inviteRepo,
 		messageClient: messageClient,
+		mediaClient:   mediaClient,
+		userClient:    userClient,
+		invitationTTL: invitationTTL,
 		eventPublisher: eventPublisher{
 			js:  js,
 			log: log,
@@ -49,6 +63,9 @@ func (s *chatServiceImpl) CreateDirectChat(ctx context.Context, callerID string,
 	if existing != nil {
 		return existing, nil
 	}
+	if err := s.checkNotBlocked(ctx, callerID, req.OtherUserID); err != nil {
+		return nil, err
+	}
 
 	chatID := uuid.New().String()
 	now := time.Now()
@@ -78,7 +95,15 @@ func (s *chatServiceImpl) CreateDirectChat(ctx context.Context, callerID string,
 	return chat, nil
 }
 
-func (s *chatServiceImpl) CreateGroup(ctx context.Context, callerID string, req *model.CreateGroupRequest) (*model.Chat, *model.Group, error) {
+func (s *chatServiceImpl) CreateGroup(ctx context.Context, callerID string, req *model.CreateGroupRequest) (*model.Chat, *model.Group, []model.GroupInvitation, error) {
+	if err := s.checkNotBlocked(ctx, callerID, req.MemberIDs...); err != nil {
+		return nil, nil, nil, err
+	}
+	denied, err := s.groupAddDenied(ctx, callerID, req.MemberIDs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	chatID := uuid.New().String()
 	now := time.Now()
 
@@ -99,36 +124,40 @@ func (s *chatServiceImpl) CreateGroup(ctx context.Context, callerID string, req
 	}
 
 	// Build participant list: caller is admin, all others are members.
+	// Those who may not be added directly are invited once the group exists.
 	seen := map[string]bool{callerID: true}
 	participants := make([]model.ChatParticipant, 0, len(req.MemberIDs)+1)
 	participants = append(participants, model.ChatParticipant{
 		ID: uuid.New().String(), ChatID: chatID, UserID: callerID, Role: "admin", JoinedAt: now,
 	})
+	var memberIDs, invitees []string
 	for _, memberID := range req.MemberIDs {
 		if seen[memberID] {
 			continue
 		}
 		seen[memberID] = true
+		if denied[memberID] {
+			invitees = append(invitees, memberID)
+			continue
+		}
+		memberIDs = append(memberIDs, memberID)
 		participants = append(participants, model.ChatParticipant{
 			ID: uuid.New().String(), ChatID: chatID, UserID: memberID, Role: "member", JoinedAt: now,
 		})
 	}
 
 	if err := s.chatRepo.CreateGroup(ctx, chat, group, participants); err != nil {
-		return nil, nil, apperr.NewInternal("failed to create group", err)
+		return nil, nil, nil, apperr.NewInternal("failed to create group", err)
 	}
 
 	s.publishEvent("chat.created", map[string]interface{}{
 		"chat_id": chatID,
 		"type":    "group",
 		"name":    req.Name,
-		"members": append(req.MemberIDs, callerID),
+		"members": append(memberIDs, callerID),
 	})
 
-	for _, memberID := range req.MemberIDs {
-		if memberID == callerID {
-			continue
-		}
+	for _, memberID := range memberIDs {
 		s.publishEvent("group.member.added", map[string]interface{}{
 			"chat_id":  chatID,
 			"user_id":  memberID,
@@ -136,7 +165,19 @@ func (s *chatServiceImpl) CreateGroup(ctx context.Context, callerID string, req
 		})
 	}
 
-	return chat, group, nil
+	// The group already exists, so an invitation that cannot be created is
+	// logged and left out rather than failing the whole request.
+	invitations := make([]model.GroupInvitation, 0, len(invitees))
+	for _, userID := range invitees {
+		inv, err := s.inviteMember(ctx, callerID, group, userID)
+		if err != nil {
+			s.log.Warn().Err(err).Str("chat_id", chat.ID).Str("user_id", userID).Msg("failed to invite group member")
+			continue
+		}
+		invitations = append(invitations, *inv)
+	}
+
+	return chat, group, invitations, nil
 }
 
 func (s *chatServiceImpl) ListChats(ctx context.Context, userID string) ([]*model.ChatListItem, error) {
@@ -192,6 +233,7 @@ func (s *chatServiceImpl) ListChats(ctx context.Context, userID string) ([]*mode
 				Type:      preview.Type,
 				Body:      preview.Body,
 				CreatedAt: preview.CreatedAt.AsTime().UnixMilli(),
+				Seq:       preview.Seq,
 			}
 		}
 
@@ -243,6 +285,7 @@ func (s *chatServiceImpl) GetChat(ctx context.Context, callerID, chatID string)
 				Type:      preview.Type,
 				Body:      preview.Body,
 				CreatedAt: preview.CreatedAt.AsTime().UnixMilli(),
+				Seq:       preview.Seq,
 			}
 		}
 	}
@@ -261,34 +304,51 @@ func (s *chatServiceImpl) GetChat(ctx context.Context, callerID, chatID string)
 
 const maxGroupMembers = 1024
 
-func (s *chatServiceImpl) AddMember(ctx context.Context, callerID, chatID, targetUserID string) error {
+func (s *chatServiceImpl) AddMember(ctx context.Context, callerID, chatID, targetUserID string) (*model.GroupInvitation, error) {
 	caller, err := s.chatRepo.GetParticipant(ctx, chatID, callerID)
 	if err != nil {
-		return apperr.NewInternal("failed to check caller membership", err)
+		return nil, apperr.NewInternal("failed to check caller membership", err)
 	}
 	if caller == nil {
-		return apperr.Wrap(apperr.CodeNotChatMember, 403, "you are not a member of this chat", nil)
+		return nil, apperr.Wrap(apperr.CodeNotChatMember, 403, "you are not a member of this chat", nil)
 	}
 	if caller.Role != "admin" {
-		return apperr.Wrap(apperr.CodeNotAdmin, 403, "only admins can add members", nil)
+		return nil, apperr.Wrap(apperr.CodeNotAdmin, 403, "only admins can add members", nil)
 	}
 
 	participants, err := s.chatRepo.GetParticipants(ctx, chatID)
 	if err != nil {
-		return apperr.NewInternal("failed to get participants", err)
+		return nil, apperr.NewInternal("failed to get participants", err)
 	}
 	if len(participants) >= maxGroupMembers {
-		return apperr.NewBadRequest(
+		return nil, apperr.NewBadRequest(
 			fmt.Sprintf("group cannot exceed %d members", maxGroupMembers),
 		)
 	}
 
 	existing, err := s.chatRepo.GetParticipant(ctx, chatID, targetUserID)
 	if err != nil {
-		return apperr.NewInternal("failed to check target membership", err)
+		return nil, apperr.NewInternal("failed to check target membership", err)
 	}
 	if existing != nil {
-		return apperr.Wrap(apperr.CodeAlreadyMember, 409, "user is already a member", nil)
+		return nil, apperr.Wrap(apperr.CodeAlreadyMember, 409, "user is already a member", nil)
+	}
+	if err := s.checkNotBlocked(ctx, callerID, targetUserID); err != nil {
+		return nil, err
+	}
+	denied, err := s.groupAddDenied(ctx, callerID, []string{targetUserID})
+	if err != nil {
+		return nil, err
+	}
+	if denied[targetUserID] {
+		group, err := s.chatRepo.GetGroup(ctx, chatID)
+		if err != nil {
+			return nil, apperr.NewInternal("failed to get group", err)
+		}
+		if group == nil {
+			return nil, apperr.NewBadRequest("members can only be added to groups")
+		}
+		return s.inviteMember(ctx, callerID, group, targetUserID)
 	}
 
 	p := &model.ChatParticipant{
@@ -300,7 +360,7 @@ func (s *chatServiceImpl) AddMember(ctx context.Context, callerID, chatID, targe
 	}
 
 	if err := s.chatRepo.AddParticipant(ctx, p); err != nil {
-		return apperr.NewInternal("failed to add member", err)
+		return nil, apperr.NewInternal("failed to add member", err)
 	}
 
 	s.publishEvent("group.member.added", map[string]interface{}{
@@ -309,7 +369,7 @@ func (s *chatServiceImpl) AddMember(ctx context.Context, callerID, chatID, targe
 		"added_by": callerID,
 	})
 
-	return nil
+	return nil, nil
 }
 
 func (s *chatServiceImpl) RemoveMember(ctx context.Context, callerID, chatID, targetUserID string) error {
@@ -374,6 +434,26 @@ func (s *chatServiceImpl) RemoveMember(ctx context.Context, callerID, chatID, ta
 	return nil
 }
 
+func (s *chatServiceImpl) RemoveUser(ctx context.Context, userID string) error {
+	chatIDs, err := s.chatRepo.GetUserChats(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user chats: %w", err)
+	}
+	for _, chatID := range chatIDs {
+		chat, err := s.chatRepo.GetByID(ctx, chatID)
+		if err != nil {
+			return fmt.Errorf("get chat %s: %w", chatID, err)
+		}
+		if chat == nil || chat.Type != model.ChatTypeGroup {
+			continue
+		}
+		if err := s.RemoveMember(ctx, userID, chatID, userID); err != nil {
+			return fmt.Errorf("leave group %s: %w", chatID, err)
+		}
+	}
+	return nil
+}
+
 func (s *chatServiceImpl) PromoteMember(ctx context.Context, callerID, chatID, targetUserID string) error {
 	caller, err := s.chatRepo.GetParticipant(ctx, chatID, callerID)
 	if err != nil {
@@ -519,9 +599,31 @@ func (s *chatServiceImpl) SetDisappearingMessages(ctx context.Context, chatID, u
 		return apperr.NewForbidden("not a member of this chat")
 	}
 
-	err = s.chatRepo.UpdateAutoDeleteTimer(ctx, chatID, userID, timer)
+	err = s.chatRepo.UpdateAutoDeleteTimer(ctx, chatID, timer)
 	if err != nil {
 		return apperr.NewInternal("failed to set disappearing messages", err)
 	}
+
+	participants, err := s.chatRepo.GetParticipants(ctx, chatID)
+	if err != nil {
+		s.log.Warn().Err(err).Str("chat_id", chatID).Msg("failed to load participants for timer event")
+		return nil
+	}
+	memberIDs := make([]string, 0, len(participants))
+	for _, p := range participants {
+		memberIDs = append(memberIDs, p.UserID)
+	}
+	var seconds int64
+	if timer != nil {
+		seconds = int64(timer.Seconds())
+	}
+	s.publishEvent("chat.updated", map[string]interface{}{
+		"chat_id":             chatID,
+		"action":              "disappearing_updated",
+		"user_id":             userID,
+		"auto_delete_seconds": seconds,
+		"participants":        memberIDs,
+	})
+
 	return nil
 }
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/whatsapp-clone/backend/chat-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	mediav1 "github.com/whatsapp-clone/backend/proto/media/v1"
+)
+
+// ExportChat hands the export to media-service, which builds it in the
+// background and stores it next to the personal data exports.
+func (s *chatServiceImpl) ExportChat(ctx context.Context, userID, chatID string, req *model.ChatExportRequest) (json.RawMessage, error) {
+	switch req.Format {
+	case "":
+		req.Format = model.ChatExportFormatTxt
+	case model.ChatExportFormatTxt, model.ChatExportFormatHTML:
+	default:
+		return nil, apperr.NewBadRequest("invalid format, must be 'txt' or 'html'")
+	}
+
+	isMember, err := s.chatRepo.IsMember(ctx, chatID, userID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to check membership", err)
+	}
+	if !isMember {
+		return nil, apperr.Wrap(apperr.CodeNotChatMember, 403, "you are not a member of this chat", nil)
+	}
+
+	resp, err := s.mediaClient.CreateChatExport(ctx, &mediav1.CreateChatExportRequest{
+		UserId:       userID,
+		ChatId:       chatID,
+		Format:       req.Format,
+		IncludeMedia: req.IncludeMedia,
+	})
+	if err != nil {
+		// media-service reports what is wrong with the request as a client
+		// error; pass it on rather than failing with a 500.
+		switch st := status.Convert(err); st.Code() {
+		case codes.InvalidArgument:
+			return nil, apperr.NewBadRequest(st.Message())
+		case codes.PermissionDenied:
+			return nil, apperr.NewForbidden(st.Message())
+		case codes.NotFound:
+			return nil, apperr.NewNotFound(st.Message())
+		}
+		return nil, apperr.NewInternal("failed to queue chat export", err)
+	}
+	return json.RawMessage(resp.Export), nil
+}
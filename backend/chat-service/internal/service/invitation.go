@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/whatsapp-clone/backend/chat-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	messagev1 "github.com/whatsapp-clone/backend/proto/message/v1"
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
+)
+
+// groupAddDenied returns those of userIDs whose group_add privacy setting
+// keeps adderID from adding them to a group; they can only be invited.
+func (s *chatServiceImpl) groupAddDenied(ctx context.Context, adderID string, userIDs []string) (map[string]bool, error) {
+	resp, err := s.userClient.CheckGroupAdd(ctx, &userv1.CheckGroupAddRequest{
+		AdderId: adderID,
+		UserIds: userIDs,
+	})
+	if err != nil {
+		return nil, apperr.NewInternal("failed to check group add privacy", err)
+	}
+	denied := make(map[string]bool, len(resp.DeniedUserIds))
+	for _, uid := range resp.DeniedUserIds {
+		denied[uid] = true
+	}
+	return denied, nil
+}
+
+// inviteMember invites userID to the group in place of adding them, renewing
+// any invitation already pending, and sends it to them in a direct message
+// from adminID. A message that fails to send is logged: the invitation still
+// shows up in the user's list of invitations.
+func (s *chatServiceImpl) inviteMember(ctx context.Context, adminID string, group *model.Group, userID string) (*model.GroupInvitation, error) {
+	now := time.Now()
+	inv, err := s.inviteRepo.CreateInvitation(ctx, &model.GroupInvitation{
+		ID:        uuid.New().String(),
+		ChatID:    group.ChatID,
+		UserID:    userID,
+		InvitedBy: adminID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.invitationTTL),
+	})
+	if err != nil {
+		return nil, apperr.NewInternal("failed to create invitation", err)
+	}
+	inv.GroupName = group.Name
+
+	messageID, err := s.sendInvitation(ctx, inv)
+	if err != nil {
+		s.log.Warn().Err(err).Str("invitation_id", inv.ID).Msg("failed to send group invitation message")
+	} else {
+		inv.MessageID = messageID
+	}
+	return inv, nil
+}
+
+// sendInvitation delivers inv as a group_invite message in the direct chat
+// between the inviter and the invitee, starting that chat if need be.
+func (s *chatServiceImpl) sendInvitation(ctx context.Context, inv *model.GroupInvitation) (string, error) {
+	direct, err := s.CreateDirectChat(ctx, inv.InvitedBy, &model.CreateDirectChatRequest{OtherUserID: inv.UserID})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.messageClient.SendMessage(ctx, &messagev1.SendMessageRequest{
+		ChatId:      direct.ID,
+		SenderId:    inv.InvitedBy,
+		Type:        "group_invite",
+		ClientMsgId: uuid.New().String(),
+		Payload: &messagev1.MessagePayload{
+			GroupInvite: &messagev1.GroupInvite{
+				InvitationId: inv.ID,
+				GroupChatId:  inv.ChatID,
+				GroupName:    inv.GroupName,
+				ExpiresAt:    timestamppb.New(inv.ExpiresAt),
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := s.inviteRepo.SetInvitationMessage(ctx, inv.ID, resp.MessageId); err != nil {
+		return "", err
+	}
+	return resp.MessageId, nil
+}
+
+func (s *chatServiceImpl) ListInvitations(ctx context.Context, userID string) ([]model.GroupInvitation, error) {
+	invitations, err := s.inviteRepo.ListPendingInvitations(ctx, userID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to list invitations", err)
+	}
+	return invitations, nil
+}
+
+func (s *chatServiceImpl) AcceptInvitation(ctx context.Context, userID, invitationID string) (*model.GroupInvitation, error) {
+	inv, err := s.pendingInvitation(ctx, userID, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := s.chatRepo.GetParticipants(ctx, inv.ChatID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get participants", err)
+	}
+	inviterIsAdmin := false
+	for _, p := range participants {
+		if p.UserID == userID {
+			return nil, apperr.Wrap(apperr.CodeAlreadyMember, 409, "you are already a member of this group", nil)
+		}
+		if p.UserID == inv.InvitedBy && p.Role == "admin" {
+			inviterIsAdmin = true
+		}
+	}
+	// The invitation only stands while the admin who sent it could still add
+	// the user themselves.
+	if !inviterIsAdmin {
+		return nil, apperr.NewConflict("the admin who invited you can no longer add members")
+	}
+	if err := s.checkNotBlocked(ctx, userID, inv.InvitedBy); err != nil {
+		return nil, err
+	}
+	if len(participants) >= maxGroupMembers {
+		return nil, apperr.NewBadRequest(
+			fmt.Sprintf("group cannot exceed %d members", maxGroupMembers),
+		)
+	}
+
+	accepted, err := s.inviteRepo.AcceptInvitation(ctx, invitationID, &model.ChatParticipant{
+		ID:       uuid.New().String(),
+		ChatID:   inv.ChatID,
+		UserID:   userID,
+		Role:     "member",
+		JoinedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, apperr.NewInternal("failed to accept invitation", err)
+	}
+	if accepted == nil {
+		return nil, apperr.NewConflict("invitation is no longer pending")
+	}
+	accepted.GroupName = inv.GroupName
+
+	s.publishMemberJoined(inv.ChatID, userID, inv.InvitedBy, inv.GroupName, "invitation", participants)
+	return accepted, nil
+}
+
+func (s *chatServiceImpl) DeclineInvitation(ctx context.Context, userID, invitationID string) (*model.GroupInvitation, error) {
+	inv, err := s.pendingInvitation(ctx, userID, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	declined, err := s.inviteRepo.DeclineInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to decline invitation", err)
+	}
+	if declined == nil {
+		return nil, apperr.NewConflict("invitation is no longer pending")
+	}
+	declined.GroupName = inv.GroupName
+
+	if inv.InvitedBy != "" {
+		s.publishEvent("group.invitation.declined", map[string]interface{}{
+			"chat_id":       inv.ChatID,
+			"invitation_id": inv.ID,
+			"user_id":       userID,
+			"participants":  []string{inv.InvitedBy},
+		})
+	}
+	return declined, nil
+}
+
+// pendingInvitation returns userID's invitation if it can still be accepted
+// or declined. Other users' invitations are reported as not found.
+func (s *chatServiceImpl) pendingInvitation(ctx context.Context, userID, invitationID string) (*model.GroupInvitation, error) {
+	if _, err := uuid.Parse(invitationID); err != nil {
+		return nil, apperr.NewNotFound("invitation not found")
+	}
+	inv, err := s.inviteRepo.GetInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get invitation", err)
+	}
+	if inv == nil || inv.UserID != userID {
+		return nil, apperr.NewNotFound("invitation not found")
+	}
+	if inv.Expired(time.Now()) {
+		return nil, apperr.Wrap(apperr.CodeInvitationExpired, 410, "invitation has expired", nil)
+	}
+	if inv.Status != model.InvitationPending {
+		return nil, apperr.NewConflict("invitation has already been " + string(inv.Status))
+	}
+	return inv, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/whatsapp-clone/backend/chat-service/internal/model"
+	"github.com/whatsapp-clone/backend/chat-service/internal/repository"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
+)
+
+// memInvites holds invitations in memory. Only the methods AcceptInvitation
+// reaches before adding the participant are implemented.
+type memInvites struct {
+	repository.InviteRepository
+	invitations map[string]*model.GroupInvitation
+	accepted    []string
+}
+
+func (m *memInvites) GetInvitation(_ context.Context, id string) (*model.GroupInvitation, error) {
+	inv, ok := m.invitations[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *inv
+	return &cp, nil
+}
+
+func (m *memInvites) AcceptInvitation(_ context.Context, id string, _ *model.ChatParticipant) (*model.GroupInvitation, error) {
+	m.accepted = append(m.accepted, id)
+	return nil, nil
+}
+
+// memParticipants returns a fixed member list for every chat.
+type memParticipants struct {
+	repository.ChatRepository
+	participants []model.ChatParticipant
+}
+
+func (m *memParticipants) GetParticipants(context.Context, string) ([]model.ChatParticipant, error) {
+	return m.participants, nil
+}
+
+// fakeBlocks answers GetBlockRelations from a fixed set of relations.
+type fakeBlocks struct {
+	userv1.UserServiceClient
+	relations []*userv1.BlockRelation
+}
+
+func (f *fakeBlocks) GetBlockRelations(context.Context, *userv1.GetBlockRelationsRequest, ...grpc.CallOption) (*userv1.GetBlockRelationsResponse, error) {
+	return &userv1.GetBlockRelationsResponse{Relations: f.relations}, nil
+}
+
+const (
+	testChatID  = "chat-1"
+	testAdminID = "admin-1"
+	testUserID  = "invitee-1"
+)
+
+func newInvitationFixture(expiresAt time.Time) (*chatServiceImpl, *memInvites, *memParticipants, *fakeBlocks, string) {
+	id := uuid.New().String()
+	invites := &memInvites{invitations: map[string]*model.GroupInvitation{
+		id: {
+			ID:        id,
+			ChatID:    testChatID,
+			UserID:    testUserID,
+			InvitedBy: testAdminID,
+			Status:    model.InvitationPending,
+			CreatedAt: expiresAt.Add(-time.Hour),
+			ExpiresAt: expiresAt,
+		},
+	}}
+	chats := &memParticipants{participants: []model.ChatParticipant{
+		{ChatID: testChatID, UserID: testAdminID, Role: "admin"},
+	}}
+	blocks := &fakeBlocks{}
+	svc := &chatServiceImpl{chatRepo: chats, inviteRepo: invites, userClient: blocks}
+	return svc, invites, chats, blocks, id
+}
+
+func requireAppErr(t *testing.T, err error, status int) *apperr.AppError {
+	t.Helper()
+	var appErr *apperr.AppError
+	require.True(t, errors.As(err, &appErr), "want an AppError, got %v", err)
+	assert.Equal(t, status, appErr.HTTPStatus)
+	return appErr
+}
+
+func TestAcceptInvitation_Expired(t *testing.T) {
+	svc, invites, _, _, id := newInvitationFixture(time.Now().Add(-time.Minute))
+
+	_, err := svc.AcceptInvitation(context.Background(), testUserID, id)
+	appErr := requireAppErr(t, err, 410)
+	assert.Equal(t, apperr.CodeInvitationExpired, appErr.Code)
+	assert.Empty(t, invites.accepted)
+
+	_, err = svc.DeclineInvitation(context.Background(), testUserID, id)
+	requireAppErr(t, err, 410)
+}
+
+func TestAcceptInvitation_BlockedSinceInvited(t *testing.T) {
+	for name, relation := range map[string]*userv1.BlockRelation{
+		"invitee blocked inviter": {UserId: testAdminID, Blocking: true},
+		"inviter blocked invitee": {UserId: testAdminID, BlockedBy: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc, invites, _, blocks, id := newInvitationFixture(time.Now().Add(time.Hour))
+			blocks.relations = []*userv1.BlockRelation{relation}
+
+			_, err := svc.AcceptInvitation(context.Background(), testUserID, id)
+			appErr := requireAppErr(t, err, 403)
+			assert.Equal(t, apperr.CodeUserBlocked, appErr.Code)
+			assert.Empty(t, invites.accepted)
+		})
+	}
+}
+
+func TestAcceptInvitation_InviterNoLongerAdmin(t *testing.T) {
+	for name, participants := range map[string][]model.ChatParticipant{
+		"demoted": {{ChatID: testChatID, UserID: testAdminID, Role: "member"}},
+		"left":    {{ChatID: testChatID, UserID: "admin-2", Role: "admin"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc, invites, chats, _, id := newInvitationFixture(time.Now().Add(time.Hour))
+			chats.participants = participants
+
+			_, err := svc.AcceptInvitation(context.Background(), testUserID, id)
+			requireAppErr(t, err, 409)
+			assert.Empty(t, invites.accepted)
+		})
+	}
+}
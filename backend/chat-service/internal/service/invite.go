@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/whatsapp-clone/backend/chat-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+)
+
+// newInviteCode returns a random 22-character URL-safe invite code.
+func newInviteCode() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// requireGroupAdmin returns the group's metadata if chatID is a group and
+// callerID is one of its admins.
+func (s *chatServiceImpl) requireGroupAdmin(ctx context.Context, chatID, callerID string) (*model.Group, error) {
+	caller, err := s.chatRepo.GetParticipant(ctx, chatID, callerID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to check caller membership", err)
+	}
+	if caller == nil {
+		return nil, apperr.Wrap(apperr.CodeNotChatMember, 403, "you are not a member of this chat", nil)
+	}
+	if caller.Role != "admin" {
+		return nil, apperr.Wrap(apperr.CodeNotAdmin, 403, "only admins can manage invite links", nil)
+	}
+
+	group, err := s.chatRepo.GetGroup(ctx, chatID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get group", err)
+	}
+	if group == nil {
+		return nil, apperr.NewBadRequest("invite links are only available for groups")
+	}
+	return group, nil
+}
+
+// resolveInvite looks up a live invite code and its group.
+func (s *chatServiceImpl) resolveInvite(ctx context.Context, code string) (*model.InviteLink, *model.Group, error) {
+	link, err := s.inviteRepo.GetInviteLinkByCode(ctx, code)
+	if err != nil {
+		return nil, nil, apperr.NewInternal("failed to look up invite link", err)
+	}
+	if link == nil {
+		return nil, nil, apperr.Wrap(apperr.CodeInviteLinkInvalid, 404, "invite link is invalid or has been revoked", nil)
+	}
+	group, err := s.chatRepo.GetGroup(ctx, link.ChatID)
+	if err != nil {
+		return nil, nil, apperr.NewInternal("failed to get group", err)
+	}
+	if group == nil {
+		return nil, nil, apperr.Wrap(apperr.CodeChatNotFound, 404, "group not found", nil)
+	}
+	return link, group, nil
+}
+
+func (s *chatServiceImpl) ManageInviteLink(ctx context.Context, callerID, chatID string, req *model.InviteLinkRequest) (*model.InviteLink, error) {
+	if _, err := s.requireGroupAdmin(ctx, chatID, callerID); err != nil {
+		return nil, err
+	}
+
+	current, err := s.inviteRepo.GetInviteLink(ctx, chatID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get invite link", err)
+	}
+
+	switch req.Action {
+	case "", "create":
+		if current != nil {
+			if req.RequiresApproval != nil && *req.RequiresApproval != current.RequiresApproval {
+				if err := s.inviteRepo.UpdateInviteApproval(ctx, chatID, *req.RequiresApproval); err != nil {
+					return nil, apperr.NewInternal("failed to update invite link", err)
+				}
+				current.RequiresApproval = *req.RequiresApproval
+			}
+			return current, nil
+		}
+	case "rotate":
+	case "revoke":
+		if err := s.inviteRepo.DeleteInviteLink(ctx, chatID); err != nil {
+			return nil, apperr.NewInternal("failed to revoke invite link", err)
+		}
+		return nil, nil
+	default:
+		return nil, apperr.NewBadRequest("invalid action, must be 'create', 'rotate', or 'revoke'")
+	}
+
+	code, err := newInviteCode()
+	if err != nil {
+		return nil, apperr.NewInternal("failed to generate invite code", err)
+	}
+	link := &model.InviteLink{
+		ChatID:    chatID,
+		Code:      code,
+		CreatedBy: callerID,
+		CreatedAt: time.Now(),
+	}
+	if current != nil {
+		link.RequiresApproval = current.RequiresApproval
+	}
+	if req.RequiresApproval != nil {
+		link.RequiresApproval = *req.RequiresApproval
+	}
+
+	if err := s.inviteRepo.UpsertInviteLink(ctx, link); err != nil {
+		return nil, apperr.NewInternal("failed to save invite link", err)
+	}
+	return link, nil
+}
+
+func (s *chatServiceImpl) GetInviteLink(ctx context.Context, callerID, chatID string) (*model.InviteLink, error) {
+	if _, err := s.requireGroupAdmin(ctx, chatID, callerID); err != nil {
+		return nil, err
+	}
+	link, err := s.inviteRepo.GetInviteLink(ctx, chatID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get invite link", err)
+	}
+	if link == nil {
+		return nil, apperr.NewNotFound("group has no active invite link")
+	}
+	return link, nil
+}
+
+func (s *chatServiceImpl) PreviewInvite(ctx context.Context, code string) (*model.InvitePreview, error) {
+	link, group, err := s.resolveInvite(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	participants, err := s.chatRepo.GetParticipants(ctx, link.ChatID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get participants", err)
+	}
+	return &model.InvitePreview{
+		ChatID:           link.ChatID,
+		Name:             group.Name,
+		Description:      group.Description,
+		AvatarURL:        group.AvatarURL,
+		MemberCount:      len(participants),
+		RequiresApproval: link.RequiresApproval,
+	}, nil
+}
+
+func (s *chatServiceImpl) JoinViaInvite(ctx context.Context, userID, code string) (*model.JoinResult, error) {
+	link, group, err := s.resolveInvite(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := s.chatRepo.GetParticipants(ctx, link.ChatID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get participants", err)
+	}
+	for _, p := range participants {
+		if p.UserID == userID {
+			return nil, apperr.Wrap(apperr.CodeAlreadyMember, 409, "you are already a member of this group", nil)
+		}
+	}
+	if len(participants) >= maxGroupMembers {
+		return nil, apperr.NewBadRequest(
+			fmt.Sprintf("group cannot exceed %d members", maxGroupMembers),
+		)
+	}
+
+	if link.RequiresApproval {
+		jr, err := s.inviteRepo.CreateJoinRequest(ctx, &model.JoinRequest{
+			ID:        uuid.New().String(),
+			ChatID:    link.ChatID,
+			UserID:    userID,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return nil, apperr.NewInternal("failed to create join request", err)
+		}
+
+		admins := make([]string, 0, 1)
+		for _, p := range participants {
+			if p.Role == "admin" {
+				admins = append(admins, p.UserID)
+			}
+		}
+		s.publishEvent("group.join.requested", map[string]interface{}{
+			"chat_id":      link.ChatID,
+			"user_id":      userID,
+			"request_id":   jr.ID,
+			"group_name":   group.Name,
+			"participants": admins,
+		})
+		return &model.JoinResult{ChatID: link.ChatID, Request: jr}, nil
+	}
+
+	p := &model.ChatParticipant{
+		ID:       uuid.New().String(),
+		ChatID:   link.ChatID,
+		UserID:   userID,
+		Role:     "member",
+		JoinedAt: time.Now(),
+	}
+	if err := s.chatRepo.AddParticipant(ctx, p); err != nil {
+		return nil, apperr.NewInternal("failed to join group", err)
+	}
+
+	s.publishMemberJoined(link.ChatID, userID, userID, group.Name, "invite_link", participants)
+	return &model.JoinResult{ChatID: link.ChatID, Joined: true}, nil
+}
+
+func (s *chatServiceImpl) ListJoinRequests(ctx context.Context, callerID, chatID string) ([]model.JoinRequest, error) {
+	if _, err := s.requireGroupAdmin(ctx, chatID, callerID); err != nil {
+		return nil, err
+	}
+	requests, err := s.inviteRepo.ListPendingJoinRequests(ctx, chatID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to list join requests", err)
+	}
+	return requests, nil
+}
+
+func (s *chatServiceImpl) DecideJoinRequest(ctx context.Context, callerID, chatID, targetUserID string, approve bool) (*model.JoinRequest, error) {
+	group, err := s.requireGroupAdmin(ctx, chatID, callerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !approve {
+		jr, err := s.inviteRepo.RejectJoinRequest(ctx, chatID, targetUserID, callerID)
+		if err != nil {
+			return nil, apperr.NewInternal("failed to reject join request", err)
+		}
+		if jr == nil {
+			return nil, apperr.NewNotFound("no pending join request for this user")
+		}
+		s.publishEvent("group.join.rejected", map[string]interface{}{
+			"chat_id":      chatID,
+			"user_id":      targetUserID,
+			"rejected_by":  callerID,
+			"participants": []string{targetUserID},
+		})
+		return jr, nil
+	}
+
+	participants, err := s.chatRepo.GetParticipants(ctx, chatID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get participants", err)
+	}
+	if len(participants) >= maxGroupMembers {
+		return nil, apperr.NewBadRequest(
+			fmt.Sprintf("group cannot exceed %d members", maxGroupMembers),
+		)
+	}
+
+	jr, err := s.inviteRepo.ApproveJoinRequest(ctx, chatID, targetUserID, callerID, &model.ChatParticipant{
+		ID:       uuid.New().String(),
+		ChatID:   chatID,
+		UserID:   targetUserID,
+		Role:     "member",
+		JoinedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, apperr.NewInternal("failed to approve join request", err)
+	}
+	if jr == nil {
+		return nil, apperr.NewNotFound("no pending join request for this user")
+	}
+
+	s.publishMemberJoined(chatID, targetUserID, callerID, group.Name, "invite_link", participants)
+	return jr, nil
+}
+
+// publishMemberJoined emits group.member.added for a user who joined through
+// an invite link or invitation (via), addressed to the existing members and
+// the new one.
+func (s *chatServiceImpl) publishMemberJoined(chatID, userID, addedBy, groupName, via string, existing []model.ChatParticipant) {
+	memberIDs := make([]string, 0, len(existing)+1)
+	for _, p := range existing {
+		memberIDs = append(memberIDs, p.UserID)
+	}
+	memberIDs = append(memberIDs, userID)
+
+	s.publishEvent("group.member.added", map[string]interface{}{
+		"chat_id":      chatID,
+		"user_id":      userID,
+		"added_by":     addedBy,
+		"group_name":   groupName,
+		"via":          via,
+		"participants": memberIDs,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"syscall"
@@ -13,11 +14,14 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/nats-io/nats.go"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
@@ -26,11 +30,15 @@ import (
 	"github.com/whatsapp-clone/backend/media-service/internal/handler"
 	"github.com/whatsapp-clone/backend/media-service/internal/repository"
 	"github.com/whatsapp-clone/backend/media-service/internal/service"
+	"github.com/whatsapp-clone/backend/pkg/accountdeletion"
 	"github.com/whatsapp-clone/backend/pkg/logger"
 	"github.com/whatsapp-clone/backend/pkg/metrics"
 	"github.com/whatsapp-clone/backend/pkg/middleware"
 	"github.com/whatsapp-clone/backend/pkg/tracing"
+	chatv1 "github.com/whatsapp-clone/backend/proto/chat/v1"
 	mediav1 "github.com/whatsapp-clone/backend/proto/media/v1"
+	messagev1 "github.com/whatsapp-clone/backend/proto/message/v1"
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
 )
 
 func main() {
@@ -74,11 +82,30 @@ func main() {
 	minioClient, err := minio.New(cfg.MinIOEndpoint, &minio.Options{
 		Creds:  credentials.NewStaticV4(cfg.MinIOAccessKey, cfg.MinIOSecretKey, ""),
 		Secure: cfg.MinIOUseSSL,
+		Region: cfg.MinIORegion,
 	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to create MinIO client")
 	}
 
+	// Presigned URLs carry the host they were signed for, so links handed to
+	// clients are signed for MinIO's public address when it differs.
+	var presignClient *minio.Client
+	if cfg.MinIOPublicURL != "" {
+		publicURL, err := url.Parse(cfg.MinIOPublicURL)
+		if err != nil || publicURL.Host == "" {
+			log.Fatal().Str("url", cfg.MinIOPublicURL).Msg("invalid MEDIA_MINIO_PUBLIC_URL")
+		}
+		presignClient, err = minio.New(publicURL.Host, &minio.Options{
+			Creds:  credentials.NewStaticV4(cfg.MinIOAccessKey, cfg.MinIOSecretKey, ""),
+			Secure: publicURL.Scheme == "https",
+			Region: cfg.MinIORegion,
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to create MinIO presign client")
+		}
+	}
+
 	// Ensure bucket exists.
 	bucketExists, err := minioClient.BucketExists(ctx, cfg.MinIOBucket)
 	if err != nil {
@@ -92,13 +119,73 @@ func main() {
 	}
 	log.Info().Str("endpoint", cfg.MinIOEndpoint).Msg("connected to MinIO")
 
+	// --- NATS ---
+	nc, err := nats.Connect(cfg.NATSUrl)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to NATS")
+	}
+	defer nc.Drain()
+	log.Info().Msg("connected to NATS")
+
+	js, err := nc.JetStream()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to get JetStream context")
+	}
+
+	// Create EXPORTS stream if not exists.
+	_, err = js.AddStream(&nats.StreamConfig{
+		Name:     "EXPORTS",
+		Subjects: []string{"export.>"},
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to create EXPORTS stream (may already exist)")
+	}
+
+	// --- Redis ---
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+	})
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to Redis")
+	}
+	defer rdb.Close()
+	log.Info().Msg("connected to Redis")
+
+	// --- gRPC clients (data exports) ---
+	userConn, err := grpc.NewClient(cfg.UserServiceGRPC, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to user-service gRPC")
+	}
+	defer userConn.Close()
+
+	chatConn, err := grpc.NewClient(cfg.ChatServiceGRPC, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to chat-service gRPC")
+	}
+	defer chatConn.Close()
+
+	msgConn, err := grpc.NewClient(cfg.MessageGRPC, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to message-service gRPC")
+	}
+	defer msgConn.Close()
+
 	// --- Repositories ---
 	mediaRepo := repository.NewMediaMongoRepository(mongoDB, log)
-	storageRepo := repository.NewStorageMinIORepository(minioClient, cfg.MinIOBucket, log)
+	exportRepo := repository.NewExportMongoRepository(mongoDB, log)
+	storageRepo := repository.NewStorageMinIORepository(minioClient, presignClient, cfg.MinIOBucket, log)
 
 	// --- Services ---
 	thumbGen := service.NewThumbnailGenerator(cfg.FFmpegPath, storageRepo, log)
 	mediaSvc := service.NewMediaService(&cfg, mediaRepo, storageRepo, thumbGen, log)
+	exportSvc := service.NewExportService(
+		&cfg, exportRepo, mediaRepo, storageRepo,
+		userv1.NewUserServiceClient(userConn),
+		chatv1.NewChatServiceClient(chatConn),
+		messagev1.NewMessageServiceClient(msgConn),
+		rdb, js, log,
+	)
 
 	// --- Start orphan cleanup goroutine ---
 	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
@@ -106,6 +193,20 @@ func main() {
 	mediaSvc.StartCleanupJob(cleanupCtx)
 	log.Info().Dur("interval", cfg.CleanupInterval).Msg("orphan cleanup job started")
 
+	// --- Start export worker ---
+	exportSvc.Start(cleanupCtx)
+	log.Info().Dur("poll", cfg.ExportPoll).Msg("export worker started")
+
+	// --- Account deletion ---
+	if err := accountdeletion.Subscribe(cleanupCtx, js, accountdeletion.ServiceMedia, func(ctx context.Context, e *accountdeletion.Event) error {
+		if err := exportSvc.DeleteUserExports(ctx, e.UserID); err != nil {
+			return err
+		}
+		return mediaSvc.DeleteUserMedia(ctx, e.UserID)
+	}, log); err != nil {
+		log.Fatal().Err(err).Msg("failed to subscribe to account deletions")
+	}
+
 	// --- HTTP Server ---
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
@@ -123,6 +224,7 @@ func main() {
 	httpHandler := handler.NewHTTPHandler(mediaSvc, cfg.PresignedURLTTL, log)
 	apiV1 := router.Group("/api/v1")
 	httpHandler.RegisterRoutes(apiV1)
+	handler.NewExportHandler(exportSvc).RegisterRoutes(apiV1)
 
 	// Prometheus metrics endpoint
 	metrics.RegisterMetricsEndpoint(router)
@@ -149,7 +251,7 @@ func main() {
 	}
 
 	grpcServer := grpc.NewServer()
-	grpcHandler := handler.NewGRPCHandler(mediaSvc)
+	grpcHandler := handler.NewGRPCHandler(mediaSvc, exportSvc)
 	mediav1.RegisterMediaServiceServer(grpcServer, grpcHandler)
 
 	healthServer := health.NewServer()
@@ -12,6 +12,8 @@ type Config struct {
 	MinIOSecretKey    string        `env:"MEDIA_MINIO_SECRET_KEY"   envRequired:"true"`
 	MinIOBucket       string        `env:"MEDIA_MINIO_BUCKET"       envDefault:"whatsapp-media"`
 	MinIOUseSSL       bool          `env:"MEDIA_MINIO_USE_SSL"      envDefault:"false"`
+	MinIORegion       string        `env:"MEDIA_MINIO_REGION"       envDefault:"us-east-1"`
+	MinIOPublicURL    string        `env:"MEDIA_MINIO_PUBLIC_URL"   envDefault:""`
 	PresignedURLTTL   time.Duration `env:"MEDIA_PRESIGNED_TTL"      envDefault:"1h"`
 	FFmpegPath        string        `env:"MEDIA_FFMPEG_PATH"        envDefault:"/usr/bin/ffmpeg"`
 	MaxImageSize      int64         `env:"MEDIA_MAX_IMAGE_SIZE"     envDefault:"16777216"`
@@ -20,6 +22,17 @@ type Config struct {
 	MaxDocSize        int64         `env:"MEDIA_MAX_DOC_SIZE"       envDefault:"104857600"`
 	ThumbnailMaxWidth int           `env:"MEDIA_THUMB_MAX_WIDTH"    envDefault:"200"`
 	CleanupInterval   time.Duration `env:"MEDIA_CLEANUP_INTERVAL"   envDefault:"6h"`
+	NATSUrl           string        `env:"MEDIA_NATS_URL"           envDefault:"nats://nats:4222"`
+	RedisAddr         string        `env:"MEDIA_REDIS_ADDR"         envDefault:"redis:6379"`
+	RedisPassword     string        `env:"MEDIA_REDIS_PASSWORD"     envDefault:""`
+	UserServiceGRPC   string        `env:"MEDIA_USER_GRPC_ADDR"     envDefault:"user-service:9082"`
+	ChatServiceGRPC   string        `env:"MEDIA_CHAT_GRPC_ADDR"     envDefault:"chat-service:9083"`
+	MessageGRPC       string        `env:"MEDIA_MESSAGE_GRPC_ADDR"  envDefault:"message-service:9084"`
+	ExportPoll        time.Duration `env:"MEDIA_EXPORT_POLL"        envDefault:"5s"`
+	ExportTimeout     time.Duration `env:"MEDIA_EXPORT_TIMEOUT"     envDefault:"30m"`
+	ExportRetention   time.Duration `env:"MEDIA_EXPORT_RETENTION"   envDefault:"168h"`
+	ExportLinkTTL     time.Duration `env:"MEDIA_EXPORT_LINK_TTL"    envDefault:"24h"`
+	ExportMaxMedia    int64         `env:"MEDIA_EXPORT_MAX_MEDIA"   envDefault:"1073741824"`
 	LogLevel          string        `env:"MEDIA_LOG_LEVEL"          envDefault:"info"`
 	OTLPEndpoint      string        `env:"OTLP_ENDPOINT"            envDefault:""`
 }
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	"github.com/whatsapp-clone/backend/pkg/response"
+
+	"github.com/whatsapp-clone/backend/media-service/internal/service"
+)
+
+// ExportHandler serves data export requests and their status.
+type ExportHandler struct {
+	exportSvc service.ExportService
+}
+
+func NewExportHandler(exportSvc service.ExportService) *ExportHandler {
+	return &ExportHandler{exportSvc: exportSvc}
+}
+
+func (h *ExportHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	exports := rg.Group("/media/exports")
+	{
+		exports.POST("", h.RequestExport)
+		exports.GET("/:exportId", h.GetExport)
+	}
+}
+
+// RequestExport queues a personal data export of the caller's account. The
+// caller is notified with an export.ready event once the archive is built.
+func (h *ExportHandler) RequestExport(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return
+	}
+
+	export, _, err := h.exportSvc.RequestAccountExport(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Accepted(c, export)
+}
+
+// GetExport returns an export's status, with a fresh download link once the
+// archive is ready.
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return
+	}
+
+	export, err := h.exportSvc.GetExport(c.Request.Context(), userID, c.Param("exportId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, export)
+}
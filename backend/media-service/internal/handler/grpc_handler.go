@@ -2,21 +2,25 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/whatsapp-clone/backend/media-service/internal/service"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
 	mediav1 "github.com/whatsapp-clone/backend/proto/media/v1"
 )
 
 type GRPCHandler struct {
 	mediav1.UnimplementedMediaServiceServer
-	mediaSvc service.MediaService
+	mediaSvc  service.MediaService
+	exportSvc service.ExportService
 }
 
-func NewGRPCHandler(mediaSvc service.MediaService) *GRPCHandler {
-	return &GRPCHandler{mediaSvc: mediaSvc}
+func NewGRPCHandler(mediaSvc service.MediaService, exportSvc service.ExportService) *GRPCHandler {
+	return &GRPCHandler{mediaSvc: mediaSvc, exportSvc: exportSvc}
 }
 
 func (h *GRPCHandler) GetMediaMetadata(ctx context.Context, req *mediav1.GetMediaMetadataRequest) (*mediav1.GetMediaMetadataResponse, error) {
@@ -37,3 +41,33 @@ func (h *GRPCHandler) GetMediaMetadata(ctx context.Context, req *mediav1.GetMedi
 		DurationMs:   media.DurationMs,
 	}, nil
 }
+
+func (h *GRPCHandler) CreateChatExport(ctx context.Context, req *mediav1.CreateChatExportRequest) (*mediav1.CreateChatExportResponse, error) {
+	export, _, err := h.exportSvc.RequestChatExport(ctx, req.UserId, req.ChatId, req.Format, req.IncludeMedia)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	data, err := json.Marshal(export)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &mediav1.CreateChatExportResponse{Export: data}, nil
+}
+
+// grpcError maps an AppError to the gRPC code for its HTTP status, so callers
+// can pass client errors on as such. Anything else is Internal.
+func grpcError(err error) error {
+	var appErr *apperr.AppError
+	if !errors.As(err, &appErr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	switch appErr.HTTPStatus {
+	case 400, 422:
+		return status.Error(codes.InvalidArgument, appErr.Message)
+	case 403:
+		return status.Error(codes.PermissionDenied, appErr.Message)
+	case 404:
+		return status.Error(codes.NotFound, appErr.Message)
+	}
+	return status.Error(codes.Internal, appErr.Error())
+}
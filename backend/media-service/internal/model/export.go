@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+// ExportKind is what an export archive contains.
+type ExportKind string
+
+const (
+	// ExportKindAccount is a personal data export of everything the user
+	// has across services.
+	ExportKindAccount ExportKind = "account"
+	// ExportKindChat is one chat's history as a transcript, optionally
+	// bundled with the chat's media files.
+	ExportKindChat ExportKind = "chat"
+)
+
+// Transcript formats of a chat export.
+const (
+	ExportFormatTxt  = "txt"
+	ExportFormatHTML = "html"
+)
+
+// ExportStatus tracks an export through the worker.
+type ExportStatus string
+
+const (
+	ExportStatusPending ExportStatus = "pending"
+	ExportStatusRunning ExportStatus = "running"
+	ExportStatusReady   ExportStatus = "ready"
+	ExportStatusFailed  ExportStatus = "failed"
+	ExportStatusExpired ExportStatus = "expired"
+)
+
+// Export is the MongoDB document for an export job. Once ready, the archive
+// sits at StorageKey until ExpiresAt, after which it is deleted and the
+// export is marked expired.
+type Export struct {
+	ExportID    string       `json:"export_id"              bson:"export_id"`
+	UserID      string       `json:"-"                      bson:"user_id"`
+	Kind        ExportKind   `json:"kind"                   bson:"kind"`
+	Status      ExportStatus `json:"status"                 bson:"status"`
+	StorageKey  string       `json:"-"                      bson:"storage_key,omitempty"`
+	SizeBytes   int64        `json:"size_bytes,omitempty"   bson:"size_bytes,omitempty"`
+	Error       string       `json:"-"                      bson:"error,omitempty"`
+	Attempts    int          `json:"-"                      bson:"attempts"`
+	RetryAt     *time.Time   `json:"-"                      bson:"retry_at,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"             bson:"created_at"`
+	StartedAt   *time.Time   `json:"started_at,omitempty"   bson:"started_at,omitempty"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	ExpiresAt   *time.Time   `json:"expires_at,omitempty"   bson:"expires_at,omitempty"`
+
+	// ChatID, Format and IncludeMedia describe a chat export.
+	ChatID       string `json:"chat_id,omitempty"       bson:"chat_id,omitempty"`
+	Format       string `json:"format,omitempty"        bson:"format,omitempty"`
+	IncludeMedia bool   `json:"include_media,omitempty" bson:"include_media"`
+
+	// DownloadURL is a fresh presigned link to a ready archive, valid until
+	// DownloadURLExpiresAt.
+	DownloadURL          string     `json:"download_url,omitempty"            bson:"-"`
+	DownloadURLExpiresAt *time.Time `json:"download_url_expires_at,omitempty" bson:"-"`
+}
+
+// IsActive reports whether the export is still queued or being built.
+func (e *Export) IsActive() bool {
+	return e.Status == ExportStatusPending || e.Status == ExportStatusRunning
+}
+
+// ExportEvent is published on the EXPORTS stream, as export.ready or
+// export.failed, when an export finishes.
+type ExportEvent struct {
+	ExportID string     `json:"export_id"`
+	UserID   string     `json:"user_id"`
+	Kind     ExportKind `json:"kind"`
+	ChatID   string     `json:"chat_id,omitempty"`
+}
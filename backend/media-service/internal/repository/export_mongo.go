@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/whatsapp-clone/backend/media-service/internal/model"
+)
+
+type exportMongoRepo struct {
+	col *mongo.Collection
+	log zerolog.Logger
+}
+
+func NewExportMongoRepository(db *mongo.Database, log zerolog.Logger) ExportRepository {
+	col := db.Collection("exports")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "export_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}},
+		},
+	}
+
+	if _, err := col.Indexes().CreateMany(ctx, indexes); err != nil {
+		log.Warn().Err(err).Msg("failed to ensure indexes on exports collection")
+	}
+
+	return &exportMongoRepo{col: col, log: log}
+}
+
+func (r *exportMongoRepo) Insert(ctx context.Context, e *model.Export) error {
+	_, err := r.col.InsertOne(ctx, e)
+	return err
+}
+
+func (r *exportMongoRepo) GetByID(ctx context.Context, exportID string) (*model.Export, error) {
+	return r.findOne(ctx, bson.M{"export_id": exportID})
+}
+
+func (r *exportMongoRepo) FindActive(ctx context.Context, userID string, kind model.ExportKind) (*model.Export, error) {
+	return r.findOne(ctx, bson.M{
+		"user_id": userID,
+		"kind":    kind,
+		"status":  bson.M{"$in": bson.A{model.ExportStatusPending, model.ExportStatusRunning}},
+	})
+}
+
+func (r *exportMongoRepo) FindActiveChat(ctx context.Context, userID, chatID, format string, includeMedia bool) (*model.Export, error) {
+	return r.findOne(ctx, bson.M{
+		"user_id":       userID,
+		"kind":          model.ExportKindChat,
+		"chat_id":       chatID,
+		"format":        format,
+		"include_media": includeMedia,
+		"status":        bson.M{"$in": bson.A{model.ExportStatusPending, model.ExportStatusRunning}},
+	})
+}
+
+func (r *exportMongoRepo) FindByUser(ctx context.Context, userID string) ([]*model.Export, error) {
+	return r.find(ctx, bson.M{"user_id": userID}, options.Find())
+}
+
+func (r *exportMongoRepo) ClaimNext(ctx context.Context, now, staleBefore time.Time) (*model.Export, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"status": model.ExportStatusPending, "$or": bson.A{
+			bson.M{"retry_at": bson.M{"$exists": false}},
+			bson.M{"retry_at": bson.M{"$lte": now}},
+		}},
+		bson.M{"status": model.ExportStatusRunning, "started_at": bson.M{"$lt": staleBefore}},
+	}}
+	update := bson.M{
+		"$set": bson.M{"status": model.ExportStatusRunning, "started_at": now},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var e model.Export
+	if err := r.col.FindOneAndUpdate(ctx, filter, update, opts).Decode(&e); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *exportMongoRepo) Release(ctx context.Context, exportID, errMsg string, retryAt time.Time) error {
+	_, err := r.col.UpdateOne(ctx,
+		bson.M{"export_id": exportID, "status": model.ExportStatusRunning},
+		bson.M{
+			"$set":   bson.M{"status": model.ExportStatusPending, "error": errMsg, "retry_at": retryAt},
+			"$unset": bson.M{"started_at": ""},
+		},
+	)
+	return err
+}
+
+func (r *exportMongoRepo) Complete(ctx context.Context, exportID, storageKey string, size int64, completedAt, expiresAt time.Time) error {
+	_, err := r.col.UpdateOne(ctx,
+		bson.M{"export_id": exportID},
+		bson.M{
+			"$set": bson.M{
+				"status":       model.ExportStatusReady,
+				"storage_key":  storageKey,
+				"size_bytes":   size,
+				"completed_at": completedAt,
+				"expires_at":   expiresAt,
+			},
+			"$unset": bson.M{"error": "", "retry_at": ""},
+		},
+	)
+	return err
+}
+
+func (r *exportMongoRepo) Fail(ctx context.Context, exportID, errMsg string, at time.Time) error {
+	_, err := r.col.UpdateOne(ctx,
+		bson.M{"export_id": exportID},
+		bson.M{"$set": bson.M{"status": model.ExportStatusFailed, "error": errMsg, "completed_at": at}},
+	)
+	return err
+}
+
+func (r *exportMongoRepo) FindExpired(ctx context.Context, now time.Time, limit int) ([]*model.Export, error) {
+	return r.find(ctx,
+		bson.M{"status": model.ExportStatusReady, "expires_at": bson.M{"$lte": now}},
+		options.Find().SetLimit(int64(limit)),
+	)
+}
+
+func (r *exportMongoRepo) MarkExpired(ctx context.Context, exportID string) error {
+	_, err := r.col.UpdateOne(ctx,
+		bson.M{"export_id": exportID},
+		bson.M{
+			"$set":   bson.M{"status": model.ExportStatusExpired},
+			"$unset": bson.M{"storage_key": ""},
+		},
+	)
+	return err
+}
+
+func (r *exportMongoRepo) Delete(ctx context.Context, exportID string) error {
+	_, err := r.col.DeleteOne(ctx, bson.M{"export_id": exportID})
+	return err
+}
+
+func (r *exportMongoRepo) findOne(ctx context.Context, filter bson.M) (*model.Export, error) {
+	var e model.Export
+	if err := r.col.FindOne(ctx, filter).Decode(&e); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *exportMongoRepo) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*model.Export, error) {
+	cursor, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var exports []*model.Export
+	if err := cursor.All(ctx, &exports); err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/whatsapp-clone/backend/media-service/internal/model"
+)
+
+// ExportRepository defines persistence operations for export jobs.
+type ExportRepository interface {
+	Insert(ctx context.Context, e *model.Export) error
+	GetByID(ctx context.Context, exportID string) (*model.Export, error)
+	// FindActive returns the user's pending or running export of the given
+	// kind, or nil if there is none.
+	FindActive(ctx context.Context, userID string, kind model.ExportKind) (*model.Export, error)
+	// FindActiveChat returns the user's pending or running export of chatID
+	// with the same options, or nil if there is none.
+	FindActiveChat(ctx context.Context, userID, chatID, format string, includeMedia bool) (*model.Export, error)
+	// FindByUser returns every export of the user.
+	FindByUser(ctx context.Context, userID string) ([]*model.Export, error)
+	// ClaimNext marks the oldest pending export that is not waiting out a
+	// retry delay running and returns it. A
+	// running export started before staleBefore is assumed abandoned by a
+	// crashed worker and claimed again. Returns nil when there is nothing
+	// to do.
+	ClaimNext(ctx context.Context, now, staleBefore time.Time) (*model.Export, error)
+	// Release puts a running export back in the queue after a failed attempt,
+	// to be claimed again from retryAt.
+	Release(ctx context.Context, exportID, errMsg string, retryAt time.Time) error
+	Complete(ctx context.Context, exportID, storageKey string, size int64, completedAt, expiresAt time.Time) error
+	Fail(ctx context.Context, exportID, errMsg string, at time.Time) error
+	// FindExpired returns up to limit ready exports whose archives are past
+	// their expiry.
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]*model.Export, error)
+	MarkExpired(ctx context.Context, exportID string) error
+	Delete(ctx context.Context, exportID string) error
+}
@@ -98,3 +98,18 @@ func (r *mediaMongoRepo) FindOrphaned(ctx context.Context, olderThan time.Time)
 	}
 	return media, nil
 }
+
+// FindByUploader returns up to limit media uploaded by the given user.
+func (r *mediaMongoRepo) FindByUploader(ctx context.Context, uploaderID string, limit int) ([]*model.Media, error) {
+	cursor, err := r.col.Find(ctx, bson.M{"uploader_id": uploaderID}, options.Find().SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var media []*model.Media
+	if err := cursor.All(ctx, &media); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
@@ -13,4 +13,5 @@ type MediaRepository interface {
 	GetByID(ctx context.Context, mediaID string) (*model.Media, error)
 	Delete(ctx context.Context, mediaID string) error
 	FindOrphaned(ctx context.Context, olderThan time.Time) ([]*model.Media, error)
+	FindByUploader(ctx context.Context, uploaderID string, limit int) ([]*model.Media, error)
 }
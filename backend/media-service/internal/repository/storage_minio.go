@@ -11,16 +11,24 @@ import (
 )
 
 type storageMinIORepo struct {
-	client *minio.Client
-	bucket string
-	log    zerolog.Logger
+	client        *minio.Client
+	presignClient *minio.Client
+	bucket        string
+	log           zerolog.Logger
 }
 
-func NewStorageMinIORepository(client *minio.Client, bucket string, log zerolog.Logger) StorageRepository {
+// NewStorageMinIORepository creates a StorageRepository. Presigned URLs are
+// signed by presignClient, which is configured with the address clients
+// reach MinIO at; when nil, client's own address is used.
+func NewStorageMinIORepository(client, presignClient *minio.Client, bucket string, log zerolog.Logger) StorageRepository {
+	if presignClient == nil {
+		presignClient = client
+	}
 	return &storageMinIORepo{
-		client: client,
-		bucket: bucket,
-		log:    log,
+		client:        client,
+		presignClient: presignClient,
+		bucket:        bucket,
+		log:           log,
 	}
 }
 
@@ -46,7 +54,7 @@ func (r *storageMinIORepo) Delete(ctx context.Context, key string) error {
 
 func (r *storageMinIORepo) PresignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
 	reqParams := make(url.Values)
-	presignedURL, err := r.client.PresignedGetObject(ctx, r.bucket, key, expiry, reqParams)
+	presignedURL, err := r.presignClient.PresignedGetObject(ctx, r.bucket, key, expiry, reqParams)
 	if err != nil {
 		r.log.Error().Err(err).Str("key", key).Msg("failed to generate presigned URL")
 		return "", err
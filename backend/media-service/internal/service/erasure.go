@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"fmt"
+)
+
+// erasureBatch is how many of a deleted account's uploads are fetched at a time.
+const erasureBatch = 200
+
+// DeleteUserMedia removes every object a deleted account uploaded, together
+// with its thumbnail and metadata. Metadata is only dropped once the objects
+// are gone, so a failed run is picked up again on retry.
+func (s *mediaServiceImpl) DeleteUserMedia(ctx context.Context, uploaderID string) error {
+	deleted := 0
+	for {
+		media, err := s.mediaRepo.FindByUploader(ctx, uploaderID, erasureBatch)
+		if err != nil {
+			return fmt.Errorf("find user media: %w", err)
+		}
+		if len(media) == 0 {
+			break
+		}
+		for _, m := range media {
+			if err := s.storageRepo.Delete(ctx, m.StorageKey); err != nil {
+				return fmt.Errorf("delete object %s: %w", m.StorageKey, err)
+			}
+			if m.ThumbnailKey != "" {
+				if err := s.storageRepo.Delete(ctx, m.ThumbnailKey); err != nil {
+					return fmt.Errorf("delete thumbnail %s: %w", m.ThumbnailKey, err)
+				}
+			}
+			if err := s.mediaRepo.Delete(ctx, m.MediaID); err != nil {
+				return fmt.Errorf("delete media %s: %w", m.MediaID, err)
+			}
+			deleted++
+		}
+	}
+	s.log.Info().Str("uploader_id", uploaderID).Int("count", deleted).Msg("deleted media of deleted account")
+	return nil
+}
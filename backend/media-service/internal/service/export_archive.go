@@ -0,0 +1,330 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/whatsapp-clone/backend/pkg/accountdeletion"
+	chatv1 "github.com/whatsapp-clone/backend/proto/chat/v1"
+	messagev1 "github.com/whatsapp-clone/backend/proto/message/v1"
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
+)
+
+const (
+	// exportPageSize is how many messages are fetched per ExportChatMessages call.
+	exportPageSize = 500
+	// exportMaxMedia caps the media references listed in media.json.
+	exportMaxMedia = 10000
+	// exportNameBatch is how many users are looked up per GetUsers call.
+	exportNameBatch = 100
+)
+
+// exportChat is one entry of chats.json.
+type exportChat struct {
+	ChatID       string    `json:"chat_id"`
+	Type         string    `json:"type"`
+	Name         string    `json:"name"`
+	Role         string    `json:"role"`
+	Participants []string  `json:"participants"`
+	CreatedAt    time.Time `json:"created_at"`
+	JoinedAt     time.Time `json:"joined_at"`
+	Transcript   string    `json:"transcript"`
+}
+
+// exportMedia is one entry of media.json. Storage keys stay internal; the
+// files themselves remain downloadable through the media API.
+type exportMedia struct {
+	MediaID          string    `json:"media_id"`
+	FileType         string    `json:"file_type"`
+	MIMEType         string    `json:"mime_type"`
+	OriginalFilename string    `json:"original_filename,omitempty"`
+	SizeBytes        int64     `json:"size_bytes"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// writeAccountArchive writes a personal data export into zw:
+//
+//	<section>.json              one file per user-service section (profile, contacts, ...)
+//	chats.json                  the chats the user belongs to
+//	chats/<chat_id>/messages.json  the chat's history as the user sees it
+//	chats/<chat_id>/transcript.html  the same history as a readable transcript
+//	media.json                  the media the user uploaded
+func (s *exportServiceImpl) writeAccountArchive(ctx context.Context, zw *zip.Writer, userID string) error {
+	userData, err := s.userClient.ExportUserData(ctx, &userv1.ExportUserDataRequest{UserId: userID})
+	if err != nil {
+		return fmt.Errorf("export user data: %w", err)
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(userData.Data, &sections); err != nil {
+		return fmt.Errorf("decode user data: %w", err)
+	}
+	sectionNames := make([]string, 0, len(sections))
+	for name := range sections {
+		sectionNames = append(sectionNames, name)
+	}
+	sort.Strings(sectionNames)
+	for _, name := range sectionNames {
+		if err := writeZipJSON(zw, name+".json", sections[name]); err != nil {
+			return err
+		}
+	}
+
+	chatsResp, err := s.chatClient.ListUserChats(ctx, &chatv1.ListUserChatsRequest{UserId: userID})
+	if err != nil {
+		return fmt.Errorf("list chats: %w", err)
+	}
+	names := newNameBook(s.userClient)
+	for _, c := range chatsResp.Chats {
+		names.want(c.ParticipantIds...)
+	}
+	if err := names.resolve(ctx); err != nil {
+		return err
+	}
+
+	chats := make([]exportChat, 0, len(chatsResp.Chats))
+	for _, c := range chatsResp.Chats {
+		dir := "chats/" + c.ChatId + "/"
+		title := chatTitle(c, userID, names)
+		if err := s.writeChatHistory(ctx, zw, dir, userID, title, names, c.ChatId); err != nil {
+			return err
+		}
+		chats = append(chats, exportChat{
+			ChatID:       c.ChatId,
+			Type:         c.Type,
+			Name:         title,
+			Role:         c.Role,
+			Participants: c.ParticipantIds,
+			CreatedAt:    c.CreatedAt.AsTime(),
+			JoinedAt:     c.JoinedAt.AsTime(),
+			Transcript:   dir + "transcript.html",
+		})
+	}
+	chatsJSON, err := json.Marshal(chats)
+	if err != nil {
+		return err
+	}
+	if err := writeZipJSON(zw, "chats.json", chatsJSON); err != nil {
+		return err
+	}
+
+	uploads, err := s.mediaRepo.FindByUploader(ctx, userID, exportMaxMedia)
+	if err != nil {
+		return fmt.Errorf("list media: %w", err)
+	}
+	media := make([]exportMedia, 0, len(uploads))
+	for _, m := range uploads {
+		media = append(media, exportMedia{
+			MediaID:          m.MediaID,
+			FileType:         m.FileType,
+			MIMEType:         m.MIMEType,
+			OriginalFilename: m.OriginalFilename,
+			SizeBytes:        m.SizeBytes,
+			CreatedAt:        m.CreatedAt,
+		})
+	}
+	mediaJSON, err := json.Marshal(media)
+	if err != nil {
+		return err
+	}
+	return writeZipJSON(zw, "media.json", mediaJSON)
+}
+
+// writeChatHistory pages through a chat's history once, writing messages.json
+// straight into the archive and the transcript to a temporary file, since a
+// zip.Writer only has one entry open at a time.
+func (s *exportServiceImpl) writeChatHistory(ctx context.Context, zw *zip.Writer, dir, userID, title string, names *nameBook, chatID string) error {
+	tmp, err := os.CreateTemp("", "transcript-*.html")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	transcript := newHTMLTranscript(tmp, title, userID)
+	if err := transcript.begin(); err != nil {
+		return err
+	}
+
+	w, err := zw.Create(dir + "messages.json")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	first := true
+	err = s.forEachMessage(ctx, chatID, userID, names, func(m *messagev1.ExportedMessage) error {
+		var buf bytes.Buffer
+		if !first {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  ")
+		if err := json.Indent(&buf, m.Data, "  ", "  "); err != nil {
+			return fmt.Errorf("message %s: %w", m.MessageId, err)
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+		first = false
+
+		return transcript.message(m, names.name(m.SenderId), "")
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, "\n]\n"); err != nil {
+		return err
+	}
+	if err := transcript.end(); err != nil {
+		return err
+	}
+
+	return writeZipFile(zw, dir+"transcript.html", tmp)
+}
+
+// forEachMessage pages through a chat's history as userID sees it, oldest
+// first, with every sender's name resolved before fn sees the message.
+func (s *exportServiceImpl) forEachMessage(ctx context.Context, chatID, userID string, names *nameBook, fn func(m *messagev1.ExportedMessage) error) error {
+	req := &messagev1.ExportChatMessagesRequest{ChatId: chatID, UserId: userID, Limit: exportPageSize}
+	for {
+		page, err := s.msgClient.ExportChatMessages(ctx, req)
+		if err != nil {
+			return fmt.Errorf("export messages of chat %s: %w", chatID, err)
+		}
+
+		for _, m := range page.Messages {
+			names.want(m.SenderId)
+		}
+		if err := names.resolve(ctx); err != nil {
+			return err
+		}
+
+		for _, m := range page.Messages {
+			if err := fn(m); err != nil {
+				return err
+			}
+		}
+
+		if !page.HasMore || len(page.Messages) == 0 {
+			return nil
+		}
+		last := page.Messages[len(page.Messages)-1]
+		req.AfterCreatedAt, req.AfterMessageId = last.CreatedAt, last.MessageId
+	}
+}
+
+// chatTitle names a chat the way the user's chat list does: a group by its
+// name, a direct chat by the other participant.
+func chatTitle(c *chatv1.ChatSummary, userID string, names *nameBook) string {
+	if c.Type == "group" {
+		if c.Name != "" {
+			return c.Name
+		}
+		return "Group"
+	}
+	for _, id := range c.ParticipantIds {
+		if id != userID {
+			return names.name(id)
+		}
+	}
+	return names.name(userID)
+}
+
+func writeZipJSON(zw *zip.Writer, name string, data []byte) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	buf.WriteString("\n")
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// writeZipFile copies the whole of f into a new archive entry.
+func writeZipFile(zw *zip.Writer, name string, f *os.File) error {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// nameBook resolves user IDs to display names for transcripts, looking up
+// each user once.
+type nameBook struct {
+	client  userv1.UserServiceClient
+	names   map[string]string
+	pending map[string]struct{}
+}
+
+func newNameBook(client userv1.UserServiceClient) *nameBook {
+	return &nameBook{
+		client:  client,
+		names:   map[string]string{accountdeletion.DeletedUserID: "Deleted account"},
+		pending: make(map[string]struct{}),
+	}
+}
+
+// want queues ids for the next resolve.
+func (b *nameBook) want(ids ...string) {
+	for _, id := range ids {
+		if _, ok := b.names[id]; !ok && id != "" {
+			b.pending[id] = struct{}{}
+		}
+	}
+}
+
+// resolve looks up every queued user. Users that no longer exist keep their
+// ID as their name.
+func (b *nameBook) resolve(ctx context.Context) error {
+	if len(b.pending) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(b.pending))
+	for id := range b.pending {
+		ids = append(ids, id)
+		b.names[id] = id
+	}
+	b.pending = make(map[string]struct{})
+
+	for start := 0; start < len(ids); start += exportNameBatch {
+		end := min(start+exportNameBatch, len(ids))
+		resp, err := b.client.GetUsers(ctx, &userv1.GetUsersRequest{UserIds: ids[start:end]})
+		if err != nil {
+			return fmt.Errorf("look up users: %w", err)
+		}
+		for _, u := range resp.Users {
+			switch {
+			case u.DisplayName != "":
+				b.names[u.UserId] = u.DisplayName
+			case u.Phone != "":
+				b.names[u.UserId] = u.Phone
+			}
+		}
+	}
+	return nil
+}
+
+func (b *nameBook) name(id string) string {
+	if n, ok := b.names[id]; ok {
+		return n
+	}
+	return id
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"github.com/whatsapp-clone/backend/media-service/internal/model"
+	"github.com/whatsapp-clone/backend/media-service/internal/repository"
+	chatv1 "github.com/whatsapp-clone/backend/proto/chat/v1"
+	messagev1 "github.com/whatsapp-clone/backend/proto/message/v1"
+)
+
+// chatTranscriptName is the transcript's name inside a chat export archive,
+// as in WhatsApp's own exports.
+const chatTranscriptName = "_chat"
+
+// writeChatExport writes a chat export to w: the bare transcript, or, with
+// media, a zip of the transcript and the chat's media files. It returns the
+// file's extension and content type.
+func (s *exportServiceImpl) writeChatExport(ctx context.Context, w io.Writer, e *model.Export) (string, string, error) {
+	resp, err := s.chatClient.GetChatSummary(ctx, &chatv1.GetChatSummaryRequest{ChatId: e.ChatID, UserId: e.UserID})
+	if err != nil {
+		return "", "", fmt.Errorf("describe chat: %w", err)
+	}
+	names := newNameBook(s.userClient)
+	names.want(resp.Chat.ParticipantIds...)
+	if err := names.resolve(ctx); err != nil {
+		return "", "", err
+	}
+	title := chatTitle(resp.Chat, e.UserID, names)
+
+	if !e.IncludeMedia {
+		transcript := newTranscript(w, e.Format, title, e.UserID)
+		if err := s.writeTranscript(ctx, transcript, e, names, nil); err != nil {
+			return "", "", err
+		}
+		return e.Format, transcriptContentType(e.Format), nil
+	}
+
+	// The media files go straight into the archive while the transcript is
+	// written to a temporary file and added last.
+	tmp, err := os.CreateTemp("", "transcript-*."+e.Format)
+	if err != nil {
+		return "", "", fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	zw := zip.NewWriter(w)
+	bundle := &mediaBundle{
+		zw:      zw,
+		media:   s.mediaRepo,
+		storage: s.storageRepo,
+		budget:  s.cfg.ExportMaxMedia,
+		names:   make(map[string]struct{}),
+		log:     s.log.With().Str("export_id", e.ExportID).Logger(),
+	}
+	transcript := newTranscript(tmp, e.Format, title, e.UserID)
+	if err := s.writeTranscript(ctx, transcript, e, names, bundle); err != nil {
+		return "", "", err
+	}
+	if err := writeZipFile(zw, chatTranscriptName+"."+e.Format, tmp); err != nil {
+		return "", "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", "", fmt.Errorf("finish archive: %w", err)
+	}
+	return "zip", "application/zip", nil
+}
+
+// writeTranscript writes the chat's history as the user sees it, copying
+// each attachment into bundle first when there is one.
+func (s *exportServiceImpl) writeTranscript(ctx context.Context, t transcriptWriter, e *model.Export, names *nameBook, bundle *mediaBundle) error {
+	if err := t.begin(); err != nil {
+		return err
+	}
+	err := s.forEachMessage(ctx, e.ChatID, e.UserID, names, func(m *messagev1.ExportedMessage) error {
+		var file string
+		if bundle != nil {
+			var err error
+			if file, err = bundle.add(ctx, m); err != nil {
+				return err
+			}
+		}
+		return t.message(m, names.name(m.SenderId), file)
+	})
+	if err != nil {
+		return err
+	}
+	return t.end()
+}
+
+// mediaBundle copies a chat's attachments into an export archive under
+// WhatsApp-style file names, until the export's media size budget runs out.
+// Attachments past the budget, or whose files are gone, are left out.
+type mediaBundle struct {
+	zw      *zip.Writer
+	media   repository.MediaRepository
+	storage repository.StorageRepository
+	budget  int64
+	seq     int
+	names   map[string]struct{}
+	log     zerolog.Logger
+}
+
+// add copies m's attachment into the archive and returns its file name, or
+// "" if it was left out.
+func (b *mediaBundle) add(ctx context.Context, m *messagev1.ExportedMessage) (string, error) {
+	if m.MediaId == "" {
+		return "", nil
+	}
+	media, err := b.media.GetByID(ctx, m.MediaId)
+	if err != nil {
+		return "", fmt.Errorf("look up media %s: %w", m.MediaId, err)
+	}
+	if media == nil || media.SizeBytes > b.budget {
+		return "", nil
+	}
+
+	obj, _, size, err := b.storage.GetObject(ctx, media.StorageKey)
+	if err != nil {
+		b.log.Warn().Err(err).Str("media_id", media.MediaID).Msg("media file missing, leaving it out of the export")
+		return "", nil
+	}
+	defer obj.Close()
+	if size > b.budget {
+		return "", nil
+	}
+
+	name := b.fileName(m, media)
+	w, err := b.zw.Create(name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(w, obj); err != nil {
+		return "", fmt.Errorf("copy media %s: %w", media.MediaID, err)
+	}
+	b.budget -= size
+	return name, nil
+}
+
+// mediaFilePrefixes name attachments the way WhatsApp does, e.g.
+// IMG-20261018-WA0001.jpg. Documents keep their own file name.
+var mediaFilePrefixes = map[string]string{
+	"image": "IMG",
+	"video": "VID",
+	"audio": "AUD",
+}
+
+// mediaExtensions are preferred over mime.ExtensionsByType, which lists
+// several extensions for common types in no useful order.
+var mediaExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"audio/mpeg": ".mp3",
+	"audio/ogg":  ".opus",
+	"video/mp4":  ".mp4",
+}
+
+func (b *mediaBundle) fileName(m *messagev1.ExportedMessage, media *model.Media) string {
+	original := path.Base(strings.ReplaceAll(media.OriginalFilename, "\\", "/"))
+	if original == "." || original == "/" || original == ".." {
+		original = ""
+	}
+
+	name := original
+	prefix, ok := mediaFilePrefixes[m.Type]
+	if ok || name == "" {
+		if !ok {
+			prefix = "DOC"
+		}
+		b.seq++
+		name = fmt.Sprintf("%s-%s-WA%04d%s", prefix, m.CreatedAt.AsTime().UTC().Format("20060102"), b.seq, mediaExtension(media, original))
+	}
+
+	unique := name
+	ext := path.Ext(name)
+	for n := 1; ; n++ {
+		if _, taken := b.names[unique]; !taken && strings.TrimSuffix(unique, path.Ext(unique)) != chatTranscriptName {
+			break
+		}
+		unique = fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(name, ext), n, ext)
+	}
+	b.names[unique] = struct{}{}
+	return unique
+}
+
+func mediaExtension(media *model.Media, original string) string {
+	if ext := path.Ext(original); ext != "" {
+		return ext
+	}
+	if ext, ok := mediaExtensions[media.MIMEType]; ok {
+		return ext
+	}
+	if exts, _ := mime.ExtensionsByType(media.MIMEType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
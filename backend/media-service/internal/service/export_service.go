@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+
+	"github.com/whatsapp-clone/backend/media-service/internal/model"
+)
+
+// ExportService builds downloadable archives of a user's data in the
+// background and hands them out through expiring presigned links.
+type ExportService interface {
+	// RequestAccountExport queues a personal data export. If one is already
+	// queued or running it is returned instead, with created false.
+	RequestAccountExport(ctx context.Context, userID string) (*model.Export, bool, error)
+	// RequestChatExport queues an export of one chat's history in the given
+	// transcript format, optionally with the chat's media files. Membership is
+	// checked by the caller. An identical export already queued or running is
+	// returned instead, with created false.
+	RequestChatExport(ctx context.Context, userID, chatID, format string, includeMedia bool) (*model.Export, bool, error)
+	// GetExport returns one of the user's exports, with a fresh download link
+	// once it is ready.
+	GetExport(ctx context.Context, userID, exportID string) (*model.Export, error)
+	// DeleteUserExports removes every export archive of the user.
+	DeleteUserExports(ctx context.Context, userID string) error
+	// Start runs the export worker and the archive expiry sweep until ctx is
+	// cancelled.
+	Start(ctx context.Context)
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+
+	"github.com/whatsapp-clone/backend/media-service/config"
+	"github.com/whatsapp-clone/backend/media-service/internal/model"
+	"github.com/whatsapp-clone/backend/media-service/internal/repository"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	chatv1 "github.com/whatsapp-clone/backend/proto/chat/v1"
+	messagev1 "github.com/whatsapp-clone/backend/proto/message/v1"
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
+)
+
+const (
+	// exportMaxAttempts is how many times a failing export is retried
+	// before it is marked failed.
+	exportMaxAttempts = 3
+	// exportRetryDelay is how long a failed export waits before its next
+	// attempt.
+	exportRetryDelay = time.Minute
+	// exportSweepInterval is how often expired archives are deleted.
+	exportSweepInterval = 10 * time.Minute
+	exportSweepBatch    = 100
+)
+
+type exportServiceImpl struct {
+	cfg         *config.Config
+	exportRepo  repository.ExportRepository
+	mediaRepo   repository.MediaRepository
+	storageRepo repository.StorageRepository
+	userClient  userv1.UserServiceClient
+	chatClient  chatv1.ChatServiceClient
+	msgClient   messagev1.MessageServiceClient
+	rdb         *redis.Client
+	js          nats.JetStreamContext
+	log         zerolog.Logger
+}
+
+func NewExportService(
+	cfg *config.Config,
+	exportRepo repository.ExportRepository,
+	mediaRepo repository.MediaRepository,
+	storageRepo repository.StorageRepository,
+	userClient userv1.UserServiceClient,
+	chatClient chatv1.ChatServiceClient,
+	msgClient messagev1.MessageServiceClient,
+	rdb *redis.Client,
+	js nats.JetStreamContext,
+	log zerolog.Logger,
+) ExportService {
+	return &exportServiceImpl{
+		cfg:         cfg,
+		exportRepo:  exportRepo,
+		mediaRepo:   mediaRepo,
+		storageRepo: storageRepo,
+		userClient:  userClient,
+		chatClient:  chatClient,
+		msgClient:   msgClient,
+		rdb:         rdb,
+		js:          js,
+		log:         log,
+	}
+}
+
+func (s *exportServiceImpl) RequestAccountExport(ctx context.Context, userID string) (*model.Export, bool, error) {
+	active, err := s.exportRepo.FindActive(ctx, userID, model.ExportKindAccount)
+	if err != nil {
+		return nil, false, apperr.NewInternal("failed to check for a running export", err)
+	}
+	if active != nil {
+		return active, false, nil
+	}
+
+	e := &model.Export{
+		ExportID:  uuid.New().String(),
+		UserID:    userID,
+		Kind:      model.ExportKindAccount,
+		Status:    model.ExportStatusPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.exportRepo.Insert(ctx, e); err != nil {
+		return nil, false, apperr.NewInternal("failed to queue export", err)
+	}
+	s.log.Info().Str("export_id", e.ExportID).Str("user_id", userID).Msg("account export queued")
+	return e, true, nil
+}
+
+func (s *exportServiceImpl) RequestChatExport(ctx context.Context, userID, chatID, format string, includeMedia bool) (*model.Export, bool, error) {
+	if format != model.ExportFormatTxt && format != model.ExportFormatHTML {
+		return nil, false, apperr.NewBadRequest("invalid format, must be 'txt' or 'html'")
+	}
+	active, err := s.exportRepo.FindActiveChat(ctx, userID, chatID, format, includeMedia)
+	if err != nil {
+		return nil, false, apperr.NewInternal("failed to check for a running export", err)
+	}
+	if active != nil {
+		return active, false, nil
+	}
+
+	e := &model.Export{
+		ExportID:     uuid.New().String(),
+		UserID:       userID,
+		Kind:         model.ExportKindChat,
+		Status:       model.ExportStatusPending,
+		CreatedAt:    time.Now().UTC(),
+		ChatID:       chatID,
+		Format:       format,
+		IncludeMedia: includeMedia,
+	}
+	if err := s.exportRepo.Insert(ctx, e); err != nil {
+		return nil, false, apperr.NewInternal("failed to queue export", err)
+	}
+	s.log.Info().Str("export_id", e.ExportID).Str("user_id", userID).Str("chat_id", chatID).Msg("chat export queued")
+	return e, true, nil
+}
+
+func (s *exportServiceImpl) GetExport(ctx context.Context, userID, exportID string) (*model.Export, error) {
+	e, err := s.exportRepo.GetByID(ctx, exportID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get export", err)
+	}
+	if e == nil || e.UserID != userID {
+		return nil, apperr.NewNotFound("export not found")
+	}
+	if err := s.attachDownloadURL(ctx, e); err != nil {
+		return nil, apperr.NewInternal("failed to generate download URL", err)
+	}
+	return e, nil
+}
+
+func (s *exportServiceImpl) DeleteUserExports(ctx context.Context, userID string) error {
+	exports, err := s.exportRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("find exports: %w", err)
+	}
+	for _, e := range exports {
+		if e.StorageKey != "" {
+			if err := s.storageRepo.Delete(ctx, e.StorageKey); err != nil {
+				return fmt.Errorf("delete export %s archive: %w", e.ExportID, err)
+			}
+		}
+		if err := s.exportRepo.Delete(ctx, e.ExportID); err != nil {
+			return fmt.Errorf("delete export %s: %w", e.ExportID, err)
+		}
+	}
+	return nil
+}
+
+func (s *exportServiceImpl) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+func (s *exportServiceImpl) run(ctx context.Context) {
+	poll := time.NewTicker(s.cfg.ExportPoll)
+	defer poll.Stop()
+	sweep := time.NewTicker(exportSweepInterval)
+	defer sweep.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+			s.drainQueue(ctx)
+		case <-sweep.C:
+			s.expireArchives(ctx)
+		}
+	}
+}
+
+// drainQueue builds queued exports one at a time until none are left.
+// Claims are atomic, so several replicas can drain the queue together.
+func (s *exportServiceImpl) drainQueue(ctx context.Context) {
+	for ctx.Err() == nil {
+		now := time.Now().UTC()
+		e, err := s.exportRepo.ClaimNext(ctx, now, now.Add(-s.cfg.ExportTimeout))
+		if err != nil {
+			s.log.Error().Err(err).Msg("failed to claim export")
+			return
+		}
+		if e == nil {
+			return
+		}
+		s.process(ctx, e)
+	}
+}
+
+func (s *exportServiceImpl) process(ctx context.Context, e *model.Export) {
+	log := s.log.With().Str("export_id", e.ExportID).Str("user_id", e.UserID).Int("attempt", e.Attempts).Logger()
+
+	jobCtx, cancel := context.WithTimeout(ctx, s.cfg.ExportTimeout)
+	key, size, err := s.buildAndUpload(jobCtx, e)
+	cancel()
+	if err != nil {
+		if e.Attempts < exportMaxAttempts {
+			log.Warn().Err(err).Msg("export failed, will retry")
+			if err := s.exportRepo.Release(ctx, e.ExportID, err.Error(), time.Now().UTC().Add(exportRetryDelay)); err != nil {
+				log.Error().Err(err).Msg("failed to release export")
+			}
+			return
+		}
+		log.Error().Err(err).Msg("export failed")
+		now := time.Now().UTC()
+		if err := s.exportRepo.Fail(ctx, e.ExportID, err.Error(), now); err != nil {
+			log.Error().Err(err).Msg("failed to mark export failed")
+		}
+		e.Status, e.Error, e.CompletedAt = model.ExportStatusFailed, err.Error(), &now
+		s.notify(ctx, e, "export.failed")
+		return
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(s.cfg.ExportRetention)
+	if err := s.exportRepo.Complete(ctx, e.ExportID, key, size, now, expiresAt); err != nil {
+		log.Error().Err(err).Msg("failed to mark export ready")
+		_ = s.storageRepo.Delete(ctx, key)
+		_ = s.exportRepo.Release(ctx, e.ExportID, err.Error(), time.Now().UTC().Add(exportRetryDelay))
+		return
+	}
+	e.Status, e.StorageKey, e.SizeBytes, e.Error = model.ExportStatusReady, key, size, ""
+	e.CompletedAt, e.ExpiresAt = &now, &expiresAt
+	log.Info().Int64("size_bytes", size).Msg("export ready")
+
+	if err := s.attachDownloadURL(ctx, e); err != nil {
+		log.Error().Err(err).Msg("failed to generate export download URL")
+	}
+	s.notify(ctx, e, "export.ready")
+}
+
+// buildAndUpload writes the export's file to a temporary file, so its size
+// is known up front, and uploads it.
+func (s *exportServiceImpl) buildAndUpload(ctx context.Context, e *model.Export) (string, int64, error) {
+	tmp, err := os.CreateTemp("", "export-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	ext, contentType, err := s.writeExport(ctx, tmp, e)
+	if err != nil {
+		return "", 0, err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", 0, fmt.Errorf("size export: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", 0, fmt.Errorf("rewind export: %w", err)
+	}
+
+	key := fmt.Sprintf("exports/%s/%s.%s", e.UserID, e.ExportID, ext)
+	if err := s.storageRepo.Upload(ctx, key, tmp, size, contentType); err != nil {
+		return "", 0, fmt.Errorf("upload export: %w", err)
+	}
+	return key, size, nil
+}
+
+// writeExport writes the export's file to w and returns its extension and
+// content type.
+func (s *exportServiceImpl) writeExport(ctx context.Context, w io.Writer, e *model.Export) (string, string, error) {
+	if e.Kind == model.ExportKindChat {
+		return s.writeChatExport(ctx, w, e)
+	}
+
+	zw := zip.NewWriter(w)
+	if err := s.writeAccountArchive(ctx, zw, e.UserID); err != nil {
+		return "", "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", "", fmt.Errorf("finish archive: %w", err)
+	}
+	return "zip", "application/zip", nil
+}
+
+// attachDownloadURL presigns a link to a ready archive, valid for the
+// configured link TTL but never past the archive's own expiry. Exports whose
+// archive has expired but not been swept yet are reported as expired.
+func (s *exportServiceImpl) attachDownloadURL(ctx context.Context, e *model.Export) error {
+	if e.Status != model.ExportStatusReady || e.ExpiresAt == nil {
+		return nil
+	}
+	ttl := time.Until(*e.ExpiresAt)
+	if ttl < time.Second {
+		e.Status = model.ExportStatusExpired
+		return nil
+	}
+	if ttl > s.cfg.ExportLinkTTL {
+		ttl = s.cfg.ExportLinkTTL
+	}
+
+	url, err := s.storageRepo.PresignedURL(ctx, e.StorageKey, ttl)
+	if err != nil {
+		return err
+	}
+	urlExpiresAt := time.Now().UTC().Add(ttl)
+	e.DownloadURL, e.DownloadURLExpiresAt = url, &urlExpiresAt
+	return nil
+}
+
+// expireArchives deletes archives past their retention period. The export
+// records are kept, marked expired, so clients can tell why the link is gone.
+func (s *exportServiceImpl) expireArchives(ctx context.Context) {
+	expired, err := s.exportRepo.FindExpired(ctx, time.Now().UTC(), exportSweepBatch)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to find expired exports")
+		return
+	}
+	for _, e := range expired {
+		if err := s.storageRepo.Delete(ctx, e.StorageKey); err != nil {
+			s.log.Error().Err(err).Str("export_id", e.ExportID).Msg("failed to delete expired export archive")
+			continue
+		}
+		if err := s.exportRepo.MarkExpired(ctx, e.ExportID); err != nil {
+			s.log.Error().Err(err).Str("export_id", e.ExportID).Msg("failed to mark export expired")
+		}
+	}
+	if len(expired) > 0 {
+		s.log.Info().Int("count", len(expired)).Msg("expired export archives deleted")
+	}
+}
+
+// notify tells the user's connected devices that an export finished, and
+// publishes the event on the EXPORTS stream so notification-service can push
+// it to users who are offline.
+func (s *exportServiceImpl) notify(ctx context.Context, e *model.Export, event string) {
+	if msg, err := json.Marshal(model.ExportEvent{
+		ExportID: e.ExportID,
+		UserID:   e.UserID,
+		Kind:     e.Kind,
+		ChatID:   e.ChatID,
+	}); err == nil {
+		if _, err := s.js.Publish(event, msg); err != nil {
+			s.log.Warn().Err(err).Str("user_id", e.UserID).Str("event", event).Msg("failed to publish export event to NATS")
+		}
+	}
+
+	body, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	name, _ := json.Marshal(event)
+	data, _ := json.Marshal(map[string]json.RawMessage{
+		"event": name,
+		"data":  body,
+	})
+	if err := s.rdb.Publish(ctx, "user:channel:"+e.UserID, data).Err(); err != nil {
+		s.log.Warn().Err(err).Str("user_id", e.UserID).Str("event", event).Msg("failed to publish export event")
+	}
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/whatsapp-clone/backend/media-service/internal/model"
+	messagev1 "github.com/whatsapp-clone/backend/proto/message/v1"
+)
+
+// transcriptWriter streams a chat's messages, oldest first, into a
+// transcript. file is the name of the message's attachment within the same
+// export, or empty if the attachment is not included.
+type transcriptWriter interface {
+	begin() error
+	message(m *messagev1.ExportedMessage, sender, file string) error
+	end() error
+}
+
+// newTranscript returns a transcript writer for a chat export format.
+func newTranscript(w io.Writer, format, title, ownerID string) transcriptWriter {
+	if format == model.ExportFormatHTML {
+		return newHTMLTranscript(w, title, ownerID)
+	}
+	return &txtTranscript{w: w}
+}
+
+// transcriptContentType is the MIME type of a transcript format.
+func transcriptContentType(format string) string {
+	if format == model.ExportFormatHTML {
+		return "text/html; charset=utf-8"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// transcriptTemplates render a standalone HTML chat transcript: "begin" once,
+// "day" whenever the date changes, "message" per message and "end" once.
+// Times are UTC, since the server does not know the reader's time zone.
+var transcriptTemplates = template.Must(template.New("transcript").Parse(`
+{{define "begin"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; background: #efeae2; margin: 0; }
+header { background: #075e54; color: #fff; padding: 16px 24px; }
+header h1 { font-size: 20px; margin: 0; }
+header p { font-size: 12px; margin: 4px 0 0; opacity: .8; }
+main { max-width: 800px; margin: 0 auto; padding: 16px; }
+.day { text-align: center; margin: 16px 0 8px; }
+.day span { background: #e1f3fb; border-radius: 8px; padding: 4px 12px; font-size: 12px; color: #54656f; }
+.msg { background: #fff; border-radius: 8px; padding: 6px 10px; margin: 4px 0; max-width: 75%; width: fit-content; box-shadow: 0 1px .5px rgba(0,0,0,.13); }
+.msg.own { background: #d9fdd3; margin-left: auto; }
+.sender { font-size: 12px; font-weight: 600; color: #1f7aec; }
+.meta { font-size: 11px; color: #667781; }
+.text { white-space: pre-wrap; word-wrap: break-word; }
+.attachment, .forwarded { font-style: italic; color: #54656f; }
+img { display: block; max-width: 100%; border-radius: 6px; }
+</style>
+</head>
+<body>
+<header><h1>{{.}}</h1><p>Chat transcript. Times are in UTC.</p></header>
+<main>
+{{end}}
+{{define "day"}}<div class="day"><span>{{.}}</span></div>
+{{end}}
+{{define "message"}}<div class="msg{{if .Own}} own{{end}}">
+<div class="sender">{{.Sender}}</div>
+{{if .Forwarded}}<div class="forwarded">Forwarded</div>
+{{end}}{{if .Image}}<a href="{{.File}}"><img src="{{.File}}" alt="{{.File}}"></a>
+{{else if .File}}<div class="attachment"><a href="{{.File}}">{{.File}}</a></div>
+{{else if .Attachment}}<div class="attachment">{{.Attachment}}</div>
+{{end}}{{if .Text}}<div class="text">{{.Text}}</div>
+{{end}}<div class="meta">{{.Time}}{{if .Edited}} · edited{{end}}</div>
+</div>
+{{end}}
+{{define "end"}}</main>
+</body>
+</html>
+{{end}}`))
+
+// transcriptMessage is the view of one message in a transcript.
+type transcriptMessage struct {
+	Sender     string
+	Text       string
+	Attachment string
+	File       string
+	Image      bool
+	Time       string
+	Own        bool
+	Forwarded  bool
+	Edited     bool
+}
+
+// htmlTranscript writes a standalone HTML transcript. Included attachments
+// are linked relative to it, and images are shown inline.
+type htmlTranscript struct {
+	w       io.Writer
+	title   string
+	ownerID string
+	lastDay string
+}
+
+func newHTMLTranscript(w io.Writer, title, ownerID string) *htmlTranscript {
+	return &htmlTranscript{w: w, title: title, ownerID: ownerID}
+}
+
+func (t *htmlTranscript) begin() error {
+	return transcriptTemplates.ExecuteTemplate(t.w, "begin", t.title)
+}
+
+func (t *htmlTranscript) message(m *messagev1.ExportedMessage, sender, file string) error {
+	at := m.CreatedAt.AsTime().UTC()
+	if day := at.Format("January 2, 2006"); day != t.lastDay {
+		if err := transcriptTemplates.ExecuteTemplate(t.w, "day", day); err != nil {
+			return err
+		}
+		t.lastDay = day
+	}
+
+	return transcriptTemplates.ExecuteTemplate(t.w, "message", transcriptMessage{
+		Sender:     sender,
+		Text:       transcriptText(m),
+		Attachment: attachmentLabel(m),
+		File:       file,
+		Image:      file != "" && m.Type == "image",
+		Time:       at.Format(time.TimeOnly),
+		Own:        m.SenderId == t.ownerID,
+		Forwarded:  m.Forwarded,
+		Edited:     m.EditedAt != nil,
+	})
+}
+
+func (t *htmlTranscript) end() error {
+	return transcriptTemplates.ExecuteTemplate(t.w, "end", nil)
+}
+
+// txtTranscript writes a transcript in the format of WhatsApp's "Export
+// chat", one message per line:
+//
+//	18/10/2026, 14:05 - Alice: Hello
+//
+// Text that spans several lines continues on the following lines, as in
+// WhatsApp's own exports.
+type txtTranscript struct {
+	w io.Writer
+}
+
+func (t *txtTranscript) begin() error {
+	return nil
+}
+
+func (t *txtTranscript) message(m *messagev1.ExportedMessage, sender, file string) error {
+	var b strings.Builder
+	b.WriteString(m.CreatedAt.AsTime().UTC().Format("02/01/2006, 15:04"))
+	b.WriteString(" - ")
+	b.WriteString(sender)
+	b.WriteString(": ")
+
+	text := transcriptText(m)
+	switch {
+	case file != "":
+		b.WriteString(file)
+		b.WriteString(" (file attached)")
+		if text != "" {
+			b.WriteString("\n")
+		}
+	case m.MediaId != "":
+		b.WriteString("<Media omitted>")
+		if text != "" {
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(text)
+	if m.EditedAt != nil {
+		b.WriteString(" <This message was edited>")
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(t.w, b.String())
+	return err
+}
+
+func (t *txtTranscript) end() error {
+	return nil
+}
+
+// transcriptText is the message's text, with a note for encrypted messages
+// the server cannot read.
+func transcriptText(m *messagev1.ExportedMessage) string {
+	if m.Type == "encrypted" {
+		return "<end-to-end encrypted message>"
+	}
+	return m.Text
+}
+
+// attachmentLabel describes a media attachment by its file name, or by its
+// media ID (see media.json) when it has none.
+func attachmentLabel(m *messagev1.ExportedMessage) string {
+	if m.MediaId == "" {
+		return ""
+	}
+	label := m.Filename
+	if label == "" {
+		label = m.MediaId
+	}
+	return fmt.Sprintf("%s: %s", m.Type, label)
+}
@@ -16,5 +16,6 @@ type MediaService interface {
 	GetDownloadURL(ctx context.Context, mediaID string, expiry time.Duration) (string, error)
 	StreamFile(ctx context.Context, mediaID string) (io.ReadCloser, string, int64, error)
 	DeleteMedia(ctx context.Context, mediaID string) error
+	DeleteUserMedia(ctx context.Context, uploaderID string) error
 	StartCleanupJob(ctx context.Context)
 }
@@ -5,11 +5,9 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"time"
 
 	"github.com/rs/zerolog"
 
@@ -50,13 +48,9 @@ func (t *ThumbnailGenerator) Generate(ctx context.Context, storageKey, fileType,
 	defer os.Remove(originalPath)
 	defer os.Remove(thumbPath)
 
-	// Download original from MinIO to a temp file.
-	downloadURL, err := t.storageRepo.PresignedURL(ctx, storageKey, 5*time.Minute)
-	if err != nil {
-		return "", fmt.Errorf("failed to get presigned URL for thumbnail: %w", err)
-	}
-
-	if err := downloadFile(downloadURL, originalPath); err != nil {
+	// Download original from MinIO to a temp file. Presigned URLs are
+	// meant for clients and may name a host this service cannot reach.
+	if err := t.downloadFile(ctx, storageKey, originalPath); err != nil {
 		return "", fmt.Errorf("failed to download original for thumbnail: %w", err)
 	}
 
@@ -106,23 +100,13 @@ func (t *ThumbnailGenerator) Generate(ctx context.Context, storageKey, fileType,
 	return thumbKey, nil
 }
 
-var downloadClient = &http.Client{Timeout: 30 * time.Second}
-
-// downloadFile fetches a URL and writes it to the given file path.
-func downloadFile(fileURL, dest string) error {
-	req, err := http.NewRequest(http.MethodGet, fileURL, nil)
+// downloadFile copies a stored object to the given file path.
+func (t *ThumbnailGenerator) downloadFile(ctx context.Context, key, dest string) error {
+	obj, _, _, err := t.storageRepo.GetObject(ctx, key)
 	if err != nil {
 		return err
 	}
-	resp, err := downloadClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("download returned status %d", resp.StatusCode)
-	}
+	defer obj.Close()
 
 	out, err := os.Create(dest)
 	if err != nil {
@@ -130,6 +114,6 @@ func downloadFile(fileURL, dest string) error {
 	}
 	defer out.Close()
 
-	_, err = io.Copy(out, resp.Body)
+	_, err = io.Copy(out, obj)
 	return err
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/whatsapp-clone/backend/message-service/internal/handler"
 	"github.com/whatsapp-clone/backend/message-service/internal/repository"
 	"github.com/whatsapp-clone/backend/message-service/internal/service"
+	"github.com/whatsapp-clone/backend/pkg/accountdeletion"
 	"github.com/whatsapp-clone/backend/pkg/logger"
 	"github.com/whatsapp-clone/backend/pkg/metrics"
 	"github.com/whatsapp-clone/backend/pkg/middleware"
@@ -111,13 +112,25 @@ func main() {
 	}
 	log.Info().Msg("MESSAGES JetStream stream ready")
 
-	msgSvc := service.NewMessageService(msgRepo, publisher, userClient, chatClient, log)
+	msgSvc := service.NewMessageService(msgRepo, publisher, userClient, chatClient, cfg.EditWindow, log)
 
-	// Start disappearing messages cleanup job (runs every 6 hours)
-	cleaner := service.NewDisappearingMessagesCleaner(msgRepo, 6*time.Hour, log)
+	// Start disappearing messages cleanup job
+	cleaner := service.NewDisappearingMessagesCleaner(msgRepo, publisher, cfg.ExpirySweepInterval, log)
 	cleaner.Start(context.Background())
 	defer cleaner.Stop()
 
+	// End live location shares once their duration runs out
+	liveExpirer := service.NewLiveLocationExpirer(msgRepo, publisher, cfg.LiveLocationSweep, log)
+	liveExpirer.Start(context.Background())
+	defer liveExpirer.Stop()
+
+	// Anonymize the messages of deleted accounts
+	if err := accountdeletion.Subscribe(context.Background(), js, accountdeletion.ServiceMessage, func(ctx context.Context, e *accountdeletion.Event) error {
+		return msgSvc.EraseUser(ctx, e.UserID, e.DeletionID)
+	}, log); err != nil {
+		log.Fatal().Err(err).Msg("failed to subscribe to account deletions")
+	}
+
 	// --- HTTP Server ---
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
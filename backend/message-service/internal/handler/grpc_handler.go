@@ -2,6 +2,8 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -35,6 +37,47 @@ func (h *GRPCHandler) SendMessage(ctx context.Context, req *messagev1.SendMessag
 			DurationMs: req.Payload.GetDurationMs(),
 		},
 	}
+	if loc := req.Payload.GetLocation(); loc != nil {
+		sendReq.Payload.Location = &model.Location{
+			Latitude:            loc.Latitude,
+			Longitude:           loc.Longitude,
+			AccuracyM:           loc.AccuracyM,
+			Name:                loc.Name,
+			Address:             loc.Address,
+			LiveDurationSeconds: loc.LiveDurationSeconds,
+		}
+	}
+	for _, c := range req.Payload.GetContacts() {
+		contact := model.Contact{
+			Name:         c.Name,
+			Organization: c.Organization,
+			Emails:       c.Emails,
+			VCard:        c.Vcard,
+		}
+		for _, p := range c.Phones {
+			contact.Phones = append(contact.Phones, model.ContactPhone{Number: p.Number, Type: p.Type})
+		}
+		sendReq.Payload.Contacts = append(sendReq.Payload.Contacts, contact)
+	}
+	if enc := req.Payload.GetEncrypted(); enc != nil {
+		sendReq.Payload.Encrypted = &model.EncryptedPayload{SenderDeviceID: enc.SenderDeviceId}
+		for _, env := range enc.Envelopes {
+			sendReq.Payload.Encrypted.Envelopes = append(sendReq.Payload.Encrypted.Envelopes, model.CipherEnvelope{
+				UserID:     env.UserId,
+				DeviceID:   env.DeviceId,
+				Type:       env.Type,
+				Ciphertext: env.Ciphertext,
+			})
+		}
+	}
+	if inv := req.Payload.GetGroupInvite(); inv != nil {
+		sendReq.Payload.GroupInvite = &model.GroupInvite{
+			InvitationID: inv.InvitationId,
+			GroupChatID:  inv.GroupChatId,
+			GroupName:    inv.GroupName,
+			ExpiresAt:    inv.ExpiresAt.AsTime(),
+		}
+	}
 	if req.ForwardedFrom != nil {
 		sendReq.ForwardedFrom = &model.ForwardedFrom{
 			ChatID:    req.ForwardedFrom.ChatId,
@@ -61,6 +104,42 @@ func (h *GRPCHandler) UpdateMessageStatus(ctx context.Context, req *messagev1.Up
 	return &messagev1.UpdateMessageStatusResponse{Success: true}, nil
 }
 
+func (h *GRPCHandler) EditMessage(ctx context.Context, req *messagev1.EditMessageRequest) (*messagev1.EditMessageResponse, error) {
+	msg, err := h.msgSvc.EditMessage(ctx, req.MessageId, req.UserId, &model.EditMessageRequest{
+		Body:    req.Body,
+		Caption: req.Caption,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &messagev1.EditMessageResponse{MessageId: msg.MessageID}
+	if msg.EditedAt != nil {
+		resp.EditedAt = timestamppb.New(*msg.EditedAt)
+	}
+	return resp, nil
+}
+
+func (h *GRPCHandler) UpdateLiveLocation(ctx context.Context, req *messagev1.UpdateLiveLocationRequest) (*messagev1.UpdateLiveLocationResponse, error) {
+	msg, err := h.msgSvc.UpdateLiveLocation(ctx, req.MessageId, req.UserId, req.Latitude, req.Longitude, req.AccuracyM)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	loc := msg.Payload.Location
+	resp := &messagev1.UpdateLiveLocationResponse{
+		MessageId: msg.MessageID,
+		ChatId:    msg.ChatID,
+	}
+	if loc.UpdatedAt != nil {
+		resp.UpdatedAt = timestamppb.New(*loc.UpdatedAt)
+	}
+	if loc.LiveUntil != nil {
+		resp.LiveUntil = timestamppb.New(*loc.LiveUntil)
+	}
+	return resp, nil
+}
+
 func (h *GRPCHandler) GetLastMessages(ctx context.Context, req *messagev1.GetLastMessagesRequest) (*messagev1.GetLastMessagesResponse, error) {
 	msgs, err := h.msgSvc.GetLastMessages(ctx, req.ChatIds)
 	if err != nil {
@@ -82,6 +161,7 @@ func (h *GRPCHandler) GetLastMessages(ctx context.Context, req *messagev1.GetLas
 			Type:      string(msg.Type),
 			Body:      body,
 			CreatedAt: timestamppb.New(msg.CreatedAt),
+			Seq:       msg.Seq,
 		}
 	}
 
@@ -95,3 +175,48 @@ func (h *GRPCHandler) GetUnreadCounts(ctx context.Context, req *messagev1.GetUnr
 	}
 	return &messagev1.GetUnreadCountsResponse{Counts: counts}, nil
 }
+
+func (h *GRPCHandler) ExportChatMessages(ctx context.Context, req *messagev1.ExportChatMessagesRequest) (*messagev1.ExportChatMessagesResponse, error) {
+	if req.ChatId == "" || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "chat_id and user_id required")
+	}
+
+	var afterTime *time.Time
+	if req.AfterCreatedAt != nil {
+		t := req.AfterCreatedAt.AsTime()
+		afterTime = &t
+	}
+
+	msgs, hasMore, err := h.msgSvc.ExportMessages(ctx, req.ChatId, req.UserId, afterTime, req.AfterMessageId, int(req.Limit))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &messagev1.ExportChatMessagesResponse{
+		Messages: make([]*messagev1.ExportedMessage, 0, len(msgs)),
+		HasMore:  hasMore,
+	}
+	for _, m := range msgs {
+		data, err := json.Marshal(toClientMessage(m, req.UserId))
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to encode message: %v", err)
+		}
+		exported := &messagev1.ExportedMessage{
+			MessageId:        m.MessageID,
+			SenderId:         m.SenderID,
+			Type:             string(m.Type),
+			Text:             m.TranscriptText(),
+			MediaId:          m.Payload.MediaID,
+			Filename:         m.Payload.Filename,
+			Forwarded:        m.ForwardedFrom != nil,
+			ReplyToMessageId: m.ReplyToMessageID,
+			CreatedAt:        timestamppb.New(m.CreatedAt),
+			Data:             data,
+		}
+		if m.EditedAt != nil {
+			exported.EditedAt = timestamppb.New(*m.EditedAt)
+		}
+		resp.Messages = append(resp.Messages, exported)
+	}
+	return resp, nil
+}
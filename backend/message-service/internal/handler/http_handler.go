@@ -20,14 +20,21 @@ import (
 type clientMessage struct {
 	MessageID        string               `json:"message_id"`
 	ChatID           string               `json:"chat_id"`
+	Seq              int64                `json:"seq"`
 	SenderID         string               `json:"sender_id"`
 	ClientMsgID      string               `json:"client_msg_id,omitempty"`
 	Type             model.MessageType    `json:"type"`
 	ReplyToMessageID string               `json:"reply_to_message_id,omitempty"`
+	ThreadID         string               `json:"thread_id,omitempty"`
+	ReplyCount       int64                `json:"reply_count"`
+	LastReplyAt      string               `json:"last_reply_at,omitempty"`
 	Payload          model.MessagePayload `json:"payload"`
 	Status           string               `json:"status"`
 	IsDeleted        bool                 `json:"is_deleted"`
 	IsStarred        bool                 `json:"is_starred"`
+	IsEdited         bool                 `json:"is_edited"`
+	EditedAt         string               `json:"edited_at,omitempty"`
+	ExpiresAt        string               `json:"expires_at,omitempty"`
 	CreatedAt        string               `json:"created_at"`
 }
 
@@ -45,17 +52,45 @@ func toClientMessage(m *model.Message, currentUserID string) *clientMessage {
 		}
 	}
 
+	var editedAt string
+	if m.EditedAt != nil {
+		editedAt = m.EditedAt.Format(time.RFC3339)
+	}
+	var expiresAt string
+	if m.ExpiresAt != nil {
+		expiresAt = m.ExpiresAt.Format(time.RFC3339)
+	}
+	payload := m.Payload
+	if payload.Poll != nil {
+		payload.Poll = payload.Poll.ViewFor(currentUserID)
+	}
+	if payload.Encrypted != nil {
+		payload.Encrypted = payload.Encrypted.ViewFor(currentUserID)
+	}
+
+	var lastReplyAt string
+	if m.LastReplyAt != nil {
+		lastReplyAt = m.LastReplyAt.Format(time.RFC3339)
+	}
+
 	return &clientMessage{
 		MessageID:        m.MessageID,
 		ChatID:           m.ChatID,
+		Seq:              m.Seq,
 		SenderID:         m.SenderID,
 		ClientMsgID:      m.ClientMsgID,
 		Type:             m.Type,
 		ReplyToMessageID: m.ReplyToMessageID,
-		Payload:          m.Payload,
+		ThreadID:         m.ThreadID,
+		ReplyCount:       m.ReplyCount,
+		LastReplyAt:      lastReplyAt,
+		Payload:          payload,
 		Status:           aggStatus,
 		IsDeleted:        m.IsDeleted,
 		IsStarred:        isStarred,
+		IsEdited:         m.EditedAt != nil,
+		EditedAt:         editedAt,
+		ExpiresAt:        expiresAt,
 		CreatedAt:        m.CreatedAt.Format(time.RFC3339),
 	}
 }
@@ -110,6 +145,7 @@ func (h *HTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
 		msgs.POST("/read", h.MarkAsRead)
 		msgs.GET("/search", h.SearchMessages)
 		msgs.GET("/search-global", h.SearchGlobal)
+		msgs.PATCH("/:messageId", h.EditMessage)
 		msgs.DELETE("/:messageId", h.DeleteMessage)
 		msgs.POST("/:messageId/forward", h.ForwardMessage)
 		msgs.POST("/:messageId/star", h.StarMessage)
@@ -117,6 +153,10 @@ func (h *HTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
 		msgs.POST("/:messageId/react", h.ReactToMessage)
 		msgs.DELETE("/:messageId/react", h.RemoveReaction)
 		msgs.GET("/:messageId/receipts", h.GetMessageReceipts)
+		msgs.GET("/:messageId/thread", h.GetThread)
+		msgs.POST("/:messageId/poll/vote", h.VotePoll)
+		msgs.POST("/:messageId/poll/close", h.ClosePoll)
+		msgs.POST("/:messageId/location/stop", h.StopLiveLocation)
 	}
 }
 
@@ -137,14 +177,27 @@ func (h *HTTPHandler) ListMessages(c *gin.Context) {
 		limit = 100
 	}
 
+	afterSeq, err := parseSeqParam(c, "after_seq")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	beforeSeq, err := parseSeqParam(c, "before_seq")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
 	userID := c.GetHeader("X-User-ID")
 
 	query := &model.ListMessagesQuery{
-		ChatID:   chatID,
-		UserID:   userID,
-		Cursor:   c.Query("cursor"),
-		CursorID: c.Query("cursor_id"),
-		Limit:    limit,
+		ChatID:    chatID,
+		UserID:    userID,
+		Cursor:    c.Query("cursor"),
+		CursorID:  c.Query("cursor_id"),
+		AfterSeq:  afterSeq,
+		BeforeSeq: beforeSeq,
+		Limit:     limit,
 	}
 
 	msgs, err := h.msgSvc.GetMessages(c.Request.Context(), query)
@@ -155,22 +208,100 @@ func (h *HTTPHandler) ListMessages(c *gin.Context) {
 	clientMsgs := toClientMessages(msgs, userID)
 
 	var nextCursor string
+	var nextSeq int64
 	hasMore := false
 	if len(msgs) > 0 {
 		last := msgs[len(msgs)-1]
 		nextCursor = last.CreatedAt.Format(time.RFC3339Nano)
+		nextSeq = last.Seq
 		hasMore = len(msgs) == limit
 	}
 
 	// Return in PaginatedData format the client expects:
 	// { success: true, data: { items: [...], nextCursor: "...", hasMore: true } }
+	// nextSeq is the after_seq (or before_seq) to pass for the next page.
 	response.OK(c, gin.H{
 		"items":      clientMsgs,
 		"nextCursor": nextCursor,
+		"nextSeq":    nextSeq,
 		"hasMore":    hasMore,
 	})
 }
 
+// GetThread returns a message's thread: the root message and a page of its
+// replies, oldest first.
+func (h *HTTPHandler) GetThread(c *gin.Context) {
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if v, err := strconv.Atoi(limitStr); err == nil && v > 0 {
+			limit = v
+		}
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	userID := c.GetHeader("X-User-ID")
+	root, replies, err := h.msgSvc.GetThread(c.Request.Context(), &model.ThreadQuery{
+		MessageID: c.Param("messageId"),
+		UserID:    userID,
+		Cursor:    c.Query("cursor"),
+		CursorID:  c.Query("cursor_id"),
+		Limit:     limit,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var nextCursor, nextCursorID string
+	hasMore := false
+	if len(replies) > 0 {
+		last := replies[len(replies)-1]
+		nextCursor = last.CreatedAt.Format(time.RFC3339Nano)
+		nextCursorID = last.MessageID
+		hasMore = len(replies) == limit
+	}
+
+	response.OK(c, gin.H{
+		"root":         toClientMessage(root, userID),
+		"items":        toClientMessages(replies, userID),
+		"nextCursor":   nextCursor,
+		"nextCursorId": nextCursorID,
+		"hasMore":      hasMore,
+	})
+}
+
+// StopLiveLocation ends the caller's live location share early.
+func (h *HTTPHandler) StopLiveLocation(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return
+	}
+
+	msg, err := h.msgSvc.StopLiveLocation(c.Request.Context(), c.Param("messageId"), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, toClientMessage(msg, userID))
+}
+
+// parseSeqParam parses an optional positive seq query parameter; 0 means absent.
+func parseSeqParam(c *gin.Context, name string) (int64, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || v <= 0 {
+		return 0, apperr.NewBadRequest(name + " must be a positive integer")
+	}
+	return v, nil
+}
+
 func (h *HTTPHandler) SendMessage(c *gin.Context) {
 	userID := c.GetHeader("X-User-ID")
 	if userID == "" {
@@ -187,6 +318,10 @@ func (h *HTTPHandler) SendMessage(c *gin.Context) {
 		response.Error(c, apperr.NewBadRequest("chat_id is required"))
 		return
 	}
+	if req.Type == model.MessageTypeGroupInvite {
+		response.Error(c, apperr.NewBadRequest("group invitations are sent by adding members to the group"))
+		return
+	}
 
 	msg, err := h.msgSvc.SendMessage(c.Request.Context(), userID, &req)
 	if err != nil {
@@ -197,6 +332,28 @@ func (h *HTTPHandler) SendMessage(c *gin.Context) {
 	response.Created(c, toClientMessage(msg, userID))
 }
 
+func (h *HTTPHandler) EditMessage(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return
+	}
+
+	var req model.EditMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, apperr.NewBadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	msg, err := h.msgSvc.EditMessage(c.Request.Context(), c.Param("messageId"), userID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, toClientMessage(msg, userID))
+}
+
 func (h *HTTPHandler) DeleteMessage(c *gin.Context) {
 	userID := c.GetHeader("X-User-ID")
 	if userID == "" {
@@ -282,7 +439,8 @@ func (h *HTTPHandler) UnstarMessage(c *gin.Context) {
 	response.NoContent(c)
 }
 
-// MarkAsRead marks all recent unread messages in a chat as read for the user.
+// MarkAsRead moves the user's read watermark in a chat up to message_id, or to
+// the chat's latest message when it is omitted.
 func (h *HTTPHandler) MarkAsRead(c *gin.Context) {
 	userID := c.GetHeader("X-User-ID")
 	if userID == "" {
@@ -291,7 +449,8 @@ func (h *HTTPHandler) MarkAsRead(c *gin.Context) {
 	}
 
 	var body struct {
-		ChatID string `json:"chat_id"`
+		ChatID    string `json:"chat_id"`
+		MessageID string `json:"message_id"`
 	}
 	if err := c.ShouldBindJSON(&body); err != nil {
 		response.Error(c, apperr.NewBadRequest("invalid request body"))
@@ -303,56 +462,34 @@ func (h *HTTPHandler) MarkAsRead(c *gin.Context) {
 		return
 	}
 
-	msgs, err := h.msgSvc.GetMessages(c.Request.Context(), &model.ListMessagesQuery{
-		ChatID: chatID,
-		Limit:  100,
-	})
-	if err != nil {
+	if err := h.msgSvc.MarkChatRead(c.Request.Context(), chatID, userID, body.MessageID); err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	for _, msg := range msgs {
-		if msg.SenderID == userID {
-			continue
-		}
-		if rs, ok := msg.Status[userID]; ok && rs.Status == model.StatusRead {
-			continue
-		}
-		_ = h.msgSvc.UpdateStatus(c.Request.Context(), msg.MessageID, userID, string(model.StatusRead))
-	}
-
 	response.NoContent(c)
 }
 
+// SearchMessages runs a ranked search within one chat.
 func (h *HTTPHandler) SearchMessages(c *gin.Context) {
 	chatID := c.Query("chat_id")
 	if chatID == "" {
 		response.Error(c, apperr.NewBadRequest("chat_id query parameter is required"))
 		return
 	}
-	q := c.Query("q")
-	if q == "" {
-		response.Error(c, apperr.NewBadRequest("query parameter 'q' is required"))
+	query, err := parseSearchQuery(c)
+	if err != nil {
+		response.Error(c, err)
 		return
 	}
+	query.ChatIDs = []string{chatID}
 
-	limit := 20
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if v, err := strconv.Atoi(limitStr); err == nil && v > 0 {
-			limit = v
-		}
-	}
-
-	userID := c.GetHeader("X-User-ID")
-	msgs, err := h.msgSvc.SearchMessages(c.Request.Context(), chatID, userID, q, limit)
+	page, err := h.msgSvc.SearchMessages(c.Request.Context(), query)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-
-	userID := c.GetHeader("X-User-ID")
-	response.OK(c, toClientMessages(msgs, userID))
+	respondSearchPage(c, page, query.UserID)
 }
 
 // ReactToMessage adds or replaces a user's reaction on a message.
@@ -395,7 +532,7 @@ func (h *HTTPHandler) RemoveReaction(c *gin.Context) {
 // GetMessageReceipts returns per-recipient read/delivery status breakdown for a message.
 func (h *HTTPHandler) GetMessageReceipts(c *gin.Context) {
 	messageID := c.Param("messageId")
-	msg, err := h.msgSvc.GetMessageByID(c.Request.Context(), messageID)
+	statuses, err := h.msgSvc.GetMessageReceipts(c.Request.Context(), messageID, c.GetHeader("X-User-ID"))
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -405,8 +542,8 @@ func (h *HTTPHandler) GetMessageReceipts(c *gin.Context) {
 		Status    string    `json:"status"`
 		UpdatedAt time.Time `json:"updated_at"`
 	}
-	receipts := make([]receipt, 0, len(msg.Status))
-	for uid, rs := range msg.Status {
+	receipts := make([]receipt, 0, len(statuses))
+	for uid, rs := range statuses {
 		receipts = append(receipts, receipt{
 			UserID:    uid,
 			Status:    string(rs.Status),
@@ -416,40 +553,30 @@ func (h *HTTPHandler) GetMessageReceipts(c *gin.Context) {
 	response.OK(c, receipts)
 }
 
-// SearchGlobal searches messages across all chats a user participates in.
-// Requires chat_ids as a comma-separated query parameter (provided by the gateway
-// or a prior /chats list call) since message-service does not have a chat-service gRPC client.
+// SearchGlobal runs a ranked search across all chats the user participates in,
+// optionally narrowed by a comma-separated chat_ids parameter.
 func (h *HTTPHandler) SearchGlobal(c *gin.Context) {
-	q := c.Query("q")
-	if q == "" {
-		response.Error(c, apperr.NewBadRequest("query parameter 'q' is required"))
+	query, err := parseSearchQuery(c)
+	if err != nil {
+		response.Error(c, err)
 		return
 	}
-
-	chatIDsParam := c.Query("chat_ids")
-	if chatIDsParam == "" {
-		response.Error(c, apperr.NewBadRequest("query parameter 'chat_ids' is required"))
+	if query.UserID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
 		return
 	}
-
-	chatIDs := strings.Split(chatIDsParam, ",")
-	for i := range chatIDs {
-		chatIDs[i] = strings.TrimSpace(chatIDs[i])
-	}
-
-	limit := 20
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if v, err := strconv.Atoi(limitStr); err == nil && v > 0 {
-			limit = v
+	if raw := c.Query("chat_ids"); raw != "" {
+		for _, id := range strings.Split(raw, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				query.ChatIDs = append(query.ChatIDs, id)
+			}
 		}
 	}
 
-	userID := c.GetHeader("X-User-ID")
-	msgs, err := h.msgSvc.SearchGlobal(c.Request.Context(), userID, q, chatIDs, limit)
+	page, err := h.msgSvc.SearchGlobal(c.Request.Context(), query)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-
-	response.OK(c, toClientMessages(msgs, userID))
+	respondSearchPage(c, page, query.UserID)
 }
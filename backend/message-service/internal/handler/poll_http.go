@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	"github.com/whatsapp-clone/backend/pkg/response"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+)
+
+// VotePoll replaces the caller's vote on a poll message.
+func (h *HTTPHandler) VotePoll(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return
+	}
+
+	var req model.PollVoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, apperr.NewBadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	msg, err := h.msgSvc.VotePoll(c.Request.Context(), c.Param("messageId"), userID, req.OptionIDs)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, toClientMessage(msg, userID))
+}
+
+// ClosePoll stops voting on a poll. Only its creator may close it.
+func (h *HTTPHandler) ClosePoll(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		response.Error(c, apperr.NewUnauthorized("missing X-User-ID header"))
+		return
+	}
+
+	msg, err := h.msgSvc.ClosePoll(c.Request.Context(), c.Param("messageId"), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, toClientMessage(msg, userID))
+}
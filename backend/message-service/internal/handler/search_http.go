@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	"github.com/whatsapp-clone/backend/pkg/response"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+	"github.com/whatsapp-clone/backend/message-service/internal/service"
+)
+
+// searchHit is a clientMessage annotated with its relevance and a highlighted
+// snippet of the matched text.
+type searchHit struct {
+	*clientMessage
+	Score      float64  `json:"score"`
+	Snippet    string   `json:"snippet"`
+	Highlights [][2]int `json:"highlights"`
+}
+
+// parseSearchQuery reads the shared search parameters: q, sender_id, type
+// (comma-separated), has_media, from/to (RFC 3339), cursor and limit.
+func parseSearchQuery(c *gin.Context) (*model.SearchQuery, error) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		return nil, apperr.NewBadRequest("query parameter 'q' is required")
+	}
+	query := &model.SearchQuery{
+		UserID:   c.GetHeader("X-User-ID"),
+		Text:     q,
+		SenderID: c.Query("sender_id"),
+	}
+
+	if raw := c.Query("type"); raw != "" {
+		for _, t := range strings.Split(raw, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				query.Types = append(query.Types, model.MessageType(t))
+			}
+		}
+	}
+	if raw := c.Query("has_media"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, apperr.NewBadRequest("has_media must be true or false")
+		}
+		query.HasMedia = &v
+	}
+	for name, dst := range map[string]**time.Time{"from": &query.From, "to": &query.To} {
+		if raw := c.Query(name); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return nil, apperr.NewBadRequest(name + " must be an RFC 3339 timestamp")
+			}
+			*dst = &t
+		}
+	}
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := service.DecodeSearchCursor(raw)
+		if err != nil {
+			return nil, err
+		}
+		query.After = cursor
+	}
+	if raw := c.Query("limit"); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v > 0 {
+			query.Limit = v
+		}
+	}
+	return query, nil
+}
+
+func respondSearchPage(c *gin.Context, page *model.SearchPage, userID string) {
+	hits := make([]searchHit, 0, len(page.Hits))
+	for _, hit := range page.Hits {
+		hits = append(hits, searchHit{
+			clientMessage: toClientMessage(hit.Message, userID),
+			Score:         hit.Score,
+			Snippet:       hit.Snippet,
+			Highlights:    hit.Highlights,
+		})
+	}
+	response.OKWithMeta(c, hits, &response.Meta{
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	})
+}
@@ -0,0 +1,32 @@
+package model
+
+const (
+	ContactMaxCards       = 20
+	ContactMaxPhones      = 10
+	ContactMaxEmails      = 10
+	ContactMaxNameLength  = 200
+	ContactMaxVCardLength = 16 * 1024
+)
+
+// Contact is one card of a contact message. Senders may pass a raw vCard or
+// the structured fields; the server parses the vCard, normalizes both forms
+// and regenerates VCard from the result, so every stored contact is rendered
+// the same way. UserID is the first registered user among Phones, letting
+// clients offer "message" without a lookup of their own.
+type Contact struct {
+	Name         string         `json:"name"                   bson:"name"`
+	Organization string         `json:"organization,omitempty" bson:"organization,omitempty"`
+	Phones       []ContactPhone `json:"phones,omitempty"       bson:"phones,omitempty"`
+	Emails       []string       `json:"emails,omitempty"       bson:"emails,omitempty"`
+	UserID       string         `json:"user_id,omitempty"      bson:"user_id,omitempty"`
+	VCard        string         `json:"vcard,omitempty"        bson:"vcard,omitempty"`
+}
+
+// ContactPhone is a phone number on a contact card. Number is normalized to
+// E.164 when it has a country code; UserID is set when the number belongs to
+// a registered user at send time.
+type ContactPhone struct {
+	Number string `json:"number"            bson:"number"`
+	Type   string `json:"type,omitempty"    bson:"type,omitempty"`
+	UserID string `json:"user_id,omitempty" bson:"user_id,omitempty"`
+}
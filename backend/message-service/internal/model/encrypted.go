@@ -0,0 +1,38 @@
+package model
+
+const (
+	EnvelopeTypePreKey  = "prekey"
+	EnvelopeTypeMessage = "message"
+
+	EncryptedMaxEnvelopes     = 2048
+	EncryptedMaxCiphertextLen = 64 * 1024
+)
+
+// EncryptedPayload is an end-to-end encrypted message: one ciphertext per
+// recipient device, including the sender's own other devices. The server
+// never sees the plaintext; it only routes each envelope to its device.
+type EncryptedPayload struct {
+	SenderDeviceID string           `json:"sender_device_id" bson:"sender_device_id"`
+	Envelopes      []CipherEnvelope `json:"envelopes"        bson:"envelopes"`
+}
+
+// CipherEnvelope is the ciphertext for one device. Type is "prekey" for
+// messages that also set up a session, otherwise "message".
+type CipherEnvelope struct {
+	UserID     string `json:"user_id"    bson:"user_id"`
+	DeviceID   string `json:"device_id"  bson:"device_id"`
+	Type       string `json:"type"       bson:"type"`
+	Ciphertext []byte `json:"ciphertext" bson:"ciphertext"`
+}
+
+// ViewFor returns the payload with only the envelopes addressed to userID's
+// devices.
+func (e *EncryptedPayload) ViewFor(userID string) *EncryptedPayload {
+	view := &EncryptedPayload{SenderDeviceID: e.SenderDeviceID, Envelopes: []CipherEnvelope{}}
+	for _, env := range e.Envelopes {
+		if env.UserID == userID {
+			view.Envelopes = append(view.Envelopes, env)
+		}
+	}
+	return view
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TranscriptText renders the message's content as plain text for chat
+// transcripts, in the style of WhatsApp's own chat exports. Media messages
+// render as their caption; encrypted messages render as nothing, since the
+// server cannot read them.
+func (m *Message) TranscriptText() string {
+	p := m.Payload
+	switch m.Type {
+	case MessageTypeText:
+		return p.Body
+	case MessageTypeLocation:
+		if p.Location == nil {
+			return ""
+		}
+		text := fmt.Sprintf("location: https://maps.google.com/?q=%f,%f", p.Location.Latitude, p.Location.Longitude)
+		if p.Location.Name != "" {
+			text = p.Location.Name + "\n" + text
+		}
+		return text
+	case MessageTypeContact:
+		names := make([]string, 0, len(p.Contacts))
+		for _, c := range p.Contacts {
+			names = append(names, c.Name)
+		}
+		return "contact: " + strings.Join(names, ", ")
+	case MessageTypePoll:
+		if p.Poll == nil {
+			return ""
+		}
+		var b strings.Builder
+		b.WriteString("POLL:\n" + p.Poll.Question)
+		for _, o := range p.Poll.Options {
+			fmt.Fprintf(&b, "\nOPTION: %s (%d votes)", o.Text, o.VoteCount)
+		}
+		return b.String()
+	case MessageTypeEncrypted:
+		return ""
+	case MessageTypeGroupInvite:
+		if p.GroupInvite == nil {
+			return ""
+		}
+		return "group invite: " + p.GroupInvite.GroupName
+	default:
+		return p.Caption
+	}
+}
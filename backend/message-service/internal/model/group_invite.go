@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// GroupInvite is the payload of a group_invite message: an invitation to join
+// a group, sent by chat-service in the admin's name when the invitee's
+// privacy settings keep the admin from adding them directly. The invitee
+// accepts or declines it through chat-service by InvitationID.
+type GroupInvite struct {
+	InvitationID string    `json:"invitation_id" bson:"invitation_id"`
+	GroupChatID  string    `json:"group_chat_id" bson:"group_chat_id"`
+	GroupName    string    `json:"group_name"    bson:"group_name"`
+	ExpiresAt    time.Time `json:"expires_at"    bson:"expires_at"`
+}
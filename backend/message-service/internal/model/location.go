@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+const (
+	LiveLocationMinDuration = time.Minute
+	LiveLocationMaxDuration = 8 * time.Hour
+
+	LocationMaxNameLength    = 200
+	LocationMaxAddressLength = 500
+)
+
+// Location is the payload of a location message. A live location is one sent
+// with LiveDurationSeconds: the sender streams position updates until
+// LiveUntil, or until they stop sharing (StoppedAt).
+type Location struct {
+	Latitude  float64 `json:"latitude"             bson:"latitude"`
+	Longitude float64 `json:"longitude"            bson:"longitude"`
+	AccuracyM float64 `json:"accuracy_m,omitempty" bson:"accuracy_m,omitempty"`
+	Name      string  `json:"name,omitempty"       bson:"name,omitempty"`
+	Address   string  `json:"address,omitempty"    bson:"address,omitempty"`
+
+	// LiveDurationSeconds is only read when the message is sent.
+	LiveDurationSeconds int64      `json:"live_duration_seconds,omitempty" bson:"-"`
+	LiveUntil           *time.Time `json:"live_until,omitempty"            bson:"live_until,omitempty"`
+	StoppedAt           *time.Time `json:"stopped_at,omitempty"            bson:"stopped_at,omitempty"`
+	UpdatedAt           *time.Time `json:"updated_at,omitempty"            bson:"updated_at,omitempty"`
+}
+
+// IsLive reports whether the location is a live share still accepting updates.
+func (l *Location) IsLive(now time.Time) bool {
+	return l.LiveUntil != nil && l.StoppedAt == nil && now.Before(*l.LiveUntil)
+}
@@ -5,12 +5,18 @@ import "time"
 type MessageType string
 
 const (
-	MessageTypeText     MessageType = "text"
-	MessageTypeImage    MessageType = "image"
-	MessageTypeVideo    MessageType = "video"
-	MessageTypeAudio    MessageType = "audio"
-	MessageTypeDocument MessageType = "document"
-	MessageTypeLocation MessageType = "location"
+	MessageTypeText      MessageType = "text"
+	MessageTypeImage     MessageType = "image"
+	MessageTypeVideo     MessageType = "video"
+	MessageTypeAudio     MessageType = "audio"
+	MessageTypeDocument  MessageType = "document"
+	MessageTypeLocation  MessageType = "location"
+	MessageTypePoll      MessageType = "poll"
+	MessageTypeContact   MessageType = "contact"
+	MessageTypeEncrypted MessageType = "encrypted"
+	// MessageTypeGroupInvite is only sent by chat-service; clients cannot
+	// send or forward it.
+	MessageTypeGroupInvite MessageType = "group_invite"
 )
 
 type MessageStatus string
@@ -27,24 +33,44 @@ type Reaction struct {
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 }
 
+// Message is a chat message. A reply belongs to the thread of the message it
+// answers: ThreadID is the root message's ID, so replies to replies stay in
+// one flat thread. ReplyCount and LastReplyAt are kept on the root. Status is
+// not stored: it is worked out from the chat's watermarks when read, and the
+// per-message status maps older messages still hold are ignored.
 type Message struct {
 	MessageID        string                     `json:"message_id"                    bson:"message_id"`
 	ChatID           string                     `json:"chat_id"                       bson:"chat_id"`
+	Seq              int64                      `json:"seq"                           bson:"seq,omitempty"`
 	SenderID         string                     `json:"sender_id"                     bson:"sender_id"`
 	ClientMsgID      string                     `json:"client_msg_id"                 bson:"client_msg_id"`
 	Type             MessageType                `json:"type"                          bson:"type"`
 	ReplyToMessageID string                     `json:"reply_to_message_id,omitempty" bson:"reply_to_message_id,omitempty"`
+	ThreadID         string                     `json:"thread_id,omitempty"           bson:"thread_id,omitempty"`
+	ReplyCount       int64                      `json:"reply_count,omitempty"         bson:"reply_count,omitempty"`
+	LastReplyAt      *time.Time                 `json:"last_reply_at,omitempty"       bson:"last_reply_at,omitempty"`
 	ForwardedFrom    *ForwardedFrom             `json:"forwarded_from,omitempty"      bson:"forwarded_from,omitempty"`
 	Payload          MessagePayload             `json:"payload"                       bson:"payload"`
-	Status           map[string]RecipientStatus `json:"status"                        bson:"status"`
+	Status           map[string]RecipientStatus `json:"status"                        bson:"-"`
 	Reactions        []Reaction                 `json:"reactions,omitempty"           bson:"reactions,omitempty"`
 	IsDeleted        bool                       `json:"is_deleted"                    bson:"is_deleted"`
 	IsStarredBy      []string                   `json:"is_starred_by"                 bson:"is_starred_by"`
+	EditedAt         *time.Time                 `json:"edited_at,omitempty"           bson:"edited_at,omitempty"`
+	Revisions        []MessageRevision          `json:"revisions,omitempty"           bson:"revisions,omitempty"`
+	ExpiresAt        *time.Time                 `json:"expires_at,omitempty"          bson:"expires_at,omitempty"`
 	ReplyToPreview   *ReplyPreview              `json:"reply_to_preview,omitempty"    bson:"-"`
 	CreatedAt        time.Time                  `json:"created_at"                    bson:"created_at"`
 	UpdatedAt        time.Time                  `json:"updated_at"                    bson:"updated_at"`
 }
 
+// MessageRevision is a prior version of an edited message's text. CreatedAt
+// is when that version was written (the send time or the previous edit).
+type MessageRevision struct {
+	Body      string    `json:"body,omitempty"    bson:"body,omitempty"`
+	Caption   string    `json:"caption,omitempty" bson:"caption,omitempty"`
+	CreatedAt time.Time `json:"created_at"        bson:"created_at"`
+}
+
 type ReplyPreview struct {
 	MessageID string      `json:"message_id"`
 	SenderID  string      `json:"sender_id"`
@@ -58,11 +84,16 @@ type ForwardedFrom struct {
 }
 
 type MessagePayload struct {
-	Body       string `json:"body,omitempty"        bson:"body,omitempty"`
-	MediaID    string `json:"media_id,omitempty"    bson:"media_id,omitempty"`
-	Caption    string `json:"caption,omitempty"     bson:"caption,omitempty"`
-	Filename   string `json:"filename,omitempty"    bson:"filename,omitempty"`
-	DurationMs int64  `json:"duration_ms,omitempty" bson:"duration_ms,omitempty"`
+	Body        string            `json:"body,omitempty"         bson:"body,omitempty"`
+	MediaID     string            `json:"media_id,omitempty"     bson:"media_id,omitempty"`
+	Caption     string            `json:"caption,omitempty"      bson:"caption,omitempty"`
+	Filename    string            `json:"filename,omitempty"     bson:"filename,omitempty"`
+	DurationMs  int64             `json:"duration_ms,omitempty"  bson:"duration_ms,omitempty"`
+	Poll        *Poll             `json:"poll,omitempty"         bson:"poll,omitempty"`
+	Location    *Location         `json:"location,omitempty"     bson:"location,omitempty"`
+	Contacts    []Contact         `json:"contacts,omitempty"     bson:"contacts,omitempty"`
+	Encrypted   *EncryptedPayload `json:"encrypted,omitempty"    bson:"encrypted,omitempty"`
+	GroupInvite *GroupInvite      `json:"group_invite,omitempty" bson:"group_invite,omitempty"`
 }
 
 type RecipientStatus struct {
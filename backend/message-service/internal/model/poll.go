@@ -0,0 +1,69 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	PollMinOptions        = 2
+	PollMaxOptions        = 12
+	PollMaxQuestionLength = 300
+	PollMaxOptionLength   = 100
+)
+
+// Poll is the payload of a poll message. Votes maps each voter to the option
+// IDs they picked; it is never sent to clients directly (see ViewFor), so
+// anonymous polls do not leak who voted for what.
+type Poll struct {
+	Question    string              `json:"question"            bson:"question"`
+	Options     []PollOption        `json:"options"             bson:"options"`
+	MultiSelect bool                `json:"multi_select"        bson:"multi_select"`
+	Anonymous   bool                `json:"anonymous"           bson:"anonymous"`
+	ClosesAt    *time.Time          `json:"closes_at,omitempty" bson:"closes_at,omitempty"`
+	ClosedAt    *time.Time          `json:"closed_at,omitempty" bson:"closed_at,omitempty"`
+	Votes       map[string][]string `json:"-"                   bson:"votes,omitempty"`
+	MyVotes     []string            `json:"my_votes,omitempty"  bson:"-"`
+}
+
+// PollOption is one answer with its running tally. Voters is only filled in
+// client views of non-anonymous polls.
+type PollOption struct {
+	ID        string   `json:"id"               bson:"id"`
+	Text      string   `json:"text"             bson:"text"`
+	VoteCount int64    `json:"vote_count"       bson:"vote_count"`
+	Voters    []string `json:"voters,omitempty" bson:"-"`
+}
+
+// IsClosed reports whether the poll was closed by its creator or reached its
+// close time.
+func (p *Poll) IsClosed(now time.Time) bool {
+	return p.ClosedAt != nil || (p.ClosesAt != nil && !now.Before(*p.ClosesAt))
+}
+
+// ViewFor returns a copy of the poll as userID may see it: their own votes,
+// and per-option voters unless the poll is anonymous.
+func (p *Poll) ViewFor(userID string) *Poll {
+	view := *p
+	view.Votes = nil
+	view.MyVotes = p.Votes[userID]
+	view.Options = make([]PollOption, len(p.Options))
+	copy(view.Options, p.Options)
+	if !p.Anonymous {
+		index := make(map[string]int, len(p.Options))
+		for i, o := range p.Options {
+			index[o.ID] = i
+		}
+		for voter, optionIDs := range p.Votes {
+			for _, id := range optionIDs {
+				if i, ok := index[id]; ok {
+					view.Options[i].Voters = append(view.Options[i].Voters, voter)
+				}
+			}
+		}
+		for i := range view.Options {
+			sort.Strings(view.Options[i].Voters)
+		}
+	}
+	return &view
+}
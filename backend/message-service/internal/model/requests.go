@@ -14,11 +14,29 @@ type UpdateStatusRequest struct {
 }
 
 type ListMessagesQuery struct {
-	ChatID   string `form:"chat_id"   binding:"required"`
-	UserID   string `form:"-"`
-	Cursor   string `form:"cursor"`
-	CursorID string `form:"cursor_id"`
-	Limit    int    `form:"limit"`
+	ChatID    string `form:"chat_id"    binding:"required"`
+	UserID    string `form:"-"`
+	Cursor    string `form:"cursor"`
+	CursorID  string `form:"cursor_id"`
+	AfterSeq  int64  `form:"after_seq"`
+	BeforeSeq int64  `form:"before_seq"`
+	Limit     int    `form:"limit"`
+}
+
+// BySeq reports whether the query selects an exact seq range rather than
+// paginating by (created_at, message_id).
+func (q *ListMessagesQuery) BySeq() bool {
+	return q.AfterSeq > 0 || q.BeforeSeq > 0
+}
+
+// ThreadQuery pages through the replies in a thread, oldest first. Cursor and
+// CursorID are the created_at (RFC3339Nano) and message_id of the last reply seen.
+type ThreadQuery struct {
+	MessageID string
+	UserID    string
+	Cursor    string
+	CursorID  string
+	Limit     int
 }
 
 type SearchMessagesQuery struct {
@@ -27,6 +45,18 @@ type SearchMessagesQuery struct {
 	Limit  int    `form:"limit"`
 }
 
+// EditMessageRequest replaces the body of a text message or the caption of a
+// media message.
+type EditMessageRequest struct {
+	Body    string `json:"body"`
+	Caption string `json:"caption"`
+}
+
 type ForwardRequest struct {
 	TargetChatIDs []string `json:"target_chat_ids" binding:"required"`
 }
+
+// PollVoteRequest replaces the caller's vote. An empty OptionIDs retracts it.
+type PollVoteRequest struct {
+	OptionIDs []string `json:"option_ids"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// SearchQuery is a full-text message search over one or more chats. UserID is
+// the searcher; messages they deleted for themselves are excluded.
+type SearchQuery struct {
+	UserID   string
+	ChatIDs  []string
+	Text     string
+	SenderID string
+	Types    []MessageType
+	HasMedia *bool
+	From     *time.Time
+	To       *time.Time
+	After    *SearchCursor
+	Limit    int
+}
+
+// SearchCursor is the sort key of the last hit on a page. Hits are ordered by
+// relevance score, then newest first, then message_id.
+type SearchCursor struct {
+	Score     float64   `json:"s"`
+	CreatedAt time.Time `json:"t"`
+	MessageID string    `json:"id"`
+}
+
+// SearchHit is a matching message with its relevance score and a snippet of
+// the matched text. Highlights are [start, end) code point offsets into Snippet.
+type SearchHit struct {
+	Message    *Message
+	Score      float64
+	Snippet    string
+	Highlights [][2]int
+}
+
+type SearchPage struct {
+	Hits       []*SearchHit
+	NextCursor string
+	HasMore    bool
+}
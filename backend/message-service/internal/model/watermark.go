@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Watermark records how far one member has got through a chat: every message
+// with a seq up to DeliveredSeq has reached them, and every one up to ReadSeq
+// has been read. Watermarks only move forward. UnreadCount is the number of
+// live messages from others after ReadSeq; it is adjusted as messages are
+// sent and deleted rather than counted on every chat list. UnreadRev is
+// bumped with every such adjustment, so a recount can tell whether one raced
+// it.
+type Watermark struct {
+	ChatID       string    `json:"chat_id"       bson:"chat_id"`
+	UserID       string    `json:"user_id"       bson:"user_id"`
+	DeliveredSeq int64     `json:"delivered_seq" bson:"delivered_seq"`
+	DeliveredAt  time.Time `json:"delivered_at"  bson:"delivered_at"`
+	ReadSeq      int64     `json:"read_seq"      bson:"read_seq"`
+	ReadAt       time.Time `json:"read_at"       bson:"read_at"`
+	UnreadCount  int64     `json:"unread_count"  bson:"unread_count"`
+	UnreadRev    int64     `json:"-"             bson:"unread_rev"`
+}
+
+// StatusOf returns the member's receipt for the message at seq, dated when
+// the watermark that covers it last moved, or false if the message has not
+// reached them yet.
+func (w *Watermark) StatusOf(seq int64) (RecipientStatus, bool) {
+	switch {
+	case seq <= 0:
+		return RecipientStatus{}, false
+	case seq <= w.ReadSeq:
+		return RecipientStatus{Status: StatusRead, UpdatedAt: w.ReadAt}, true
+	case seq <= w.DeliveredSeq:
+		return RecipientStatus{Status: StatusDelivered, UpdatedAt: w.DeliveredAt}, true
+	}
+	return RecipientStatus{}, false
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/whatsapp-clone/backend/pkg/accountdeletion"
+)
+
+// AnonymizeUser runs one update per field the user can appear in. Each is
+// idempotent, so a retry after a partial failure picks up where it stopped.
+func (r *messageMongoRepo) AnonymizeUser(ctx context.Context, userID, pseudonym string) error {
+	statusKey := "status." + userID
+	voteKey := "payload.poll.votes." + userID
+
+	updates := []struct {
+		name   string
+		filter bson.M
+		update bson.M
+	}{
+		{"sender", bson.M{"sender_id": userID}, bson.M{"$set": bson.M{"sender_id": accountdeletion.DeletedUserID}}},
+		{"stars", bson.M{"is_starred_by": userID}, bson.M{"$pull": bson.M{"is_starred_by": userID}}},
+		{"deletions", bson.M{"deleted_for_users": userID}, bson.M{"$pull": bson.M{"deleted_for_users": userID}}},
+		{"reactions", bson.M{"reactions.user_id": userID}, bson.M{"$pull": bson.M{"reactions": bson.M{"user_id": userID}}}},
+		{"receipts", bson.M{statusKey: bson.M{"$exists": true}}, bson.M{"$unset": bson.M{statusKey: ""}}},
+		{"envelopes", bson.M{"payload.encrypted.envelopes.user_id": userID}, bson.M{"$pull": bson.M{"payload.encrypted.envelopes": bson.M{"user_id": userID}}}},
+		{"poll votes", bson.M{voteKey: bson.M{"$exists": true}}, bson.M{"$rename": bson.M{voteKey: "payload.poll.votes." + pseudonym}}},
+	}
+	for _, u := range updates {
+		res, err := r.col.UpdateMany(ctx, u.filter, u.update)
+		if err != nil {
+			return fmt.Errorf("anonymize %s: %w", u.name, err)
+		}
+		if res.ModifiedCount > 0 {
+			r.log.Info().Str("user_id", userID).Int64("count", res.ModifiedCount).Msgf("anonymized %s", u.name)
+		}
+	}
+
+	res, err := r.watermarks.DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return fmt.Errorf("anonymize watermarks: %w", err)
+	}
+	if res.DeletedCount > 0 {
+		r.log.Info().Str("user_id", userID).Int64("count", res.DeletedCount).Msg("anonymized watermarks")
+	}
+	return nil
+}
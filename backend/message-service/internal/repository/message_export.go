@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+)
+
+// ListForExport returns up to limit of a chat's messages oldest first,
+// starting after the (afterTime, afterID) message when afterTime is set.
+// Deleted messages and messages userID deleted for themselves are skipped.
+func (r *messageMongoRepo) ListForExport(ctx context.Context, chatID, userID string, afterTime *time.Time, afterID string, limit int) ([]*model.Message, error) {
+	filter := bson.M{
+		"chat_id":           chatID,
+		"is_deleted":        false,
+		"deleted_for_users": bson.M{"$ne": userID},
+	}
+	if afterTime != nil {
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$gt": *afterTime}},
+			bson.M{
+				"created_at": *afterTime,
+				"message_id": bson.M{"$gt": afterID},
+			},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "message_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []*model.Message
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
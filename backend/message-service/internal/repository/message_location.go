@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+)
+
+// liveFilter matches a live location share that is still accepting updates.
+func liveFilter(now time.Time) bson.M {
+	return bson.M{
+		"type":                        model.MessageTypeLocation,
+		"is_deleted":                  false,
+		"payload.location.live_until": bson.M{"$gt": now},
+		"payload.location.stopped_at": bson.M{"$exists": false},
+	}
+}
+
+// UpdateLiveLocation moves an active live share to a new position. Only the
+// sender's update matches; returns mongo.ErrNoDocuments otherwise.
+func (r *messageMongoRepo) UpdateLiveLocation(ctx context.Context, messageID, senderID string, lat, lng, accuracy float64, now time.Time) (*model.Message, error) {
+	filter := liveFilter(now)
+	filter["message_id"] = messageID
+	filter["sender_id"] = senderID
+
+	var msg model.Message
+	err := r.col.FindOneAndUpdate(ctx, filter,
+		bson.M{"$set": bson.M{
+			"payload.location.latitude":   lat,
+			"payload.location.longitude":  lng,
+			"payload.location.accuracy_m": accuracy,
+			"payload.location.updated_at": now,
+			"updated_at":                  now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&msg)
+	if err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// StopLiveLocation ends an active live share. Returns mongo.ErrNoDocuments if
+// it already ended.
+func (r *messageMongoRepo) StopLiveLocation(ctx context.Context, messageID string, now time.Time) (*model.Message, error) {
+	filter := liveFilter(now)
+	filter["message_id"] = messageID
+
+	var msg model.Message
+	err := r.col.FindOneAndUpdate(ctx, filter,
+		bson.M{"$set": bson.M{"payload.location.stopped_at": now, "updated_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&msg)
+	if err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// endedFilter matches a live location share that ran out but has not been
+// stamped as stopped yet. Deleted messages are left alone.
+func endedFilter(now time.Time) bson.M {
+	return bson.M{
+		"type":                        model.MessageTypeLocation,
+		"is_deleted":                  false,
+		"payload.location.live_until": bson.M{"$lte": now},
+		"payload.location.stopped_at": bson.M{"$exists": false},
+	}
+}
+
+// EndExpiredLiveLocations stamps stopped_at = live_until on up to limit shares
+// that ran out and returns them.
+func (r *messageMongoRepo) EndExpiredLiveLocations(ctx context.Context, now time.Time, limit int) ([]*model.Message, error) {
+	filter := endedFilter(now)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "payload.location.live_until", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find expired live locations: %w", err)
+	}
+	var expired []*model.Message
+	if err := cursor.All(ctx, &expired); err != nil {
+		return nil, fmt.Errorf("decode expired live locations: %w", err)
+	}
+
+	ended := expired[:0]
+	for _, msg := range expired {
+		until := *msg.Payload.Location.LiveUntil
+		// Re-check the share: it may have been stopped or deleted since.
+		match := endedFilter(now)
+		match["message_id"] = msg.MessageID
+		res, err := r.col.UpdateOne(ctx, match,
+			bson.M{"$set": bson.M{"payload.location.stopped_at": until, "updated_at": now}},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("end live location: %w", err)
+		}
+		if res.ModifiedCount == 1 {
+			msg.Payload.Location.StoppedAt = &until
+			ended = append(ended, msg)
+		}
+	}
+	return ended, nil
+}
@@ -15,12 +15,16 @@ import (
 )
 
 type messageMongoRepo struct {
-	col *mongo.Collection
-	log zerolog.Logger
+	col        *mongo.Collection
+	counters   *mongo.Collection
+	watermarks *mongo.Collection
+	log        zerolog.Logger
 }
 
 func NewMessageMongoRepository(db *mongo.Database, log zerolog.Logger) MessageRepository {
 	col := db.Collection("messages")
+	counters := db.Collection("chat_sequences")
+	watermarks := db.Collection("chat_watermarks")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -42,23 +46,74 @@ func NewMessageMongoRepository(db *mongo.Database, log zerolog.Logger) MessageRe
 			},
 		},
 		{
-			Keys: bson.D{{Key: "payload.body", Value: "text"}},
+			Keys: bson.D{{Key: "chat_id", Value: 1}, {Key: "seq", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"seq": bson.M{"$exists": true}}),
+		},
+		{
+			Keys: bson.D{
+				{Key: "thread_id", Value: 1},
+				{Key: "created_at", Value: 1},
+				{Key: "message_id", Value: 1},
+			},
+			Options: options.Index().
+				SetPartialFilterExpression(bson.M{"thread_id": bson.M{"$exists": true}}),
+		},
+		{
+			Keys: bson.D{{Key: "payload.location.live_until", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{
+				"payload.location.live_until": bson.M{"$exists": true},
+			}),
+		},
+		{
+			Keys: bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{
+				"expires_at": bson.M{"$exists": true},
+				"is_deleted": false,
+			}),
 		},
 	}
 
 	if _, err := col.Indexes().CreateMany(ctx, indexes); err != nil {
 		log.Warn().Err(err).Msg("failed to ensure indexes on messages collection")
 	}
+	ensureSearchIndex(ctx, col, log)
+
+	_, err := watermarks.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "chat_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to ensure indexes on chat_watermarks collection")
+	}
 
-	return &messageMongoRepo{col: col, log: log}
+	return &messageMongoRepo{col: col, counters: counters, watermarks: watermarks, log: log}
 }
 
-// Insert creates a new message with idempotency on client_msg_id.
+// Insert creates a new message with idempotency on client_msg_id and assigns
+// it the next per-chat seq. Mongo runs standalone (no multi-document
+// transactions), so retries are resolved before a seq is reserved and a seq
+// reserved for a failed insert is handed back if no later message claimed one.
 func (r *messageMongoRepo) Insert(ctx context.Context, msg *model.Message) (*model.Message, error) {
-	_, err := r.col.InsertOne(ctx, msg)
+	var existing model.Message
+	err := r.col.FindOne(ctx, bson.M{"client_msg_id": msg.ClientMsgID}).Decode(&existing)
+	if err == nil {
+		return &existing, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("check client_msg_id: %w", err)
+	}
+
+	seq, err := r.nextSeq(ctx, msg.ChatID)
+	if err != nil {
+		return nil, err
+	}
+	msg.Seq = seq
+
+	_, err = r.col.InsertOne(ctx, msg)
 	if err != nil {
+		r.releaseSeq(ctx, msg.ChatID, seq)
 		if mongo.IsDuplicateKeyError(err) {
-			var existing model.Message
 			findErr := r.col.FindOne(ctx, bson.M{"client_msg_id": msg.ClientMsgID}).Decode(&existing)
 			if findErr != nil {
 				return nil, fmt.Errorf("duplicate client_msg_id but failed to find existing: %w", findErr)
@@ -67,9 +122,37 @@ func (r *messageMongoRepo) Insert(ctx context.Context, msg *model.Message) (*mod
 		}
 		return nil, err
 	}
+	r.adjustUnread(ctx, msg, 1)
 	return msg, nil
 }
 
+// nextSeq atomically increments and returns the chat's message counter.
+func (r *messageMongoRepo) nextSeq(ctx context.Context, chatID string) (int64, error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := r.counters.FindOneAndUpdate(ctx,
+		bson.M{"_id": chatID},
+		bson.M{"$inc": bson.M{"seq": int64(1)}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	if err != nil {
+		return 0, fmt.Errorf("reserve seq: %w", err)
+	}
+	return counter.Seq, nil
+}
+
+// releaseSeq rolls the chat's counter back if seq is still the latest reservation.
+func (r *messageMongoRepo) releaseSeq(ctx context.Context, chatID string, seq int64) {
+	_, err := r.counters.UpdateOne(ctx,
+		bson.M{"_id": chatID, "seq": seq},
+		bson.M{"$inc": bson.M{"seq": int64(-1)}},
+	)
+	if err != nil {
+		r.log.Warn().Err(err).Str("chat_id", chatID).Int64("seq", seq).Msg("failed to release reserved seq")
+	}
+}
+
 // GetByID retrieves a single message by message_id. Returns (nil, nil) if not found.
 func (r *messageMongoRepo) GetByID(ctx context.Context, messageID string) (*model.Message, error) {
 	var msg model.Message
@@ -122,74 +205,197 @@ func (r *messageMongoRepo) ListByChatID(ctx context.Context, chatID string, curs
 	return messages, nil
 }
 
-// UpdateStatus atomically updates a recipient's status with monotonicity enforcement.
-// Status transitions: sent -> delivered -> read. Backward transitions are no-ops.
-func (r *messageMongoRepo) UpdateStatus(ctx context.Context, messageID, userID string, recipientStatus model.RecipientStatus) error {
-	statusKey := fmt.Sprintf("status.%s", userID)
-	statusStatusKey := fmt.Sprintf("status.%s.status", userID)
-	statusUpdatedKey := fmt.Sprintf("status.%s.updated_at", userID)
-
-	var allowedPrev []string
-	switch recipientStatus.Status {
-	case model.StatusDelivered:
-		allowedPrev = []string{string(model.StatusSent)}
-	case model.StatusRead:
-		allowedPrev = []string{string(model.StatusSent), string(model.StatusDelivered)}
-	default:
-		allowedPrev = []string{}
+// ListBySeq returns messages in the open range (afterSeq, beforeSeq), where 0
+// leaves that side unbounded. Deleted messages are included as tombstones.
+func (r *messageMongoRepo) ListBySeq(ctx context.Context, chatID string, afterSeq, beforeSeq int64, limit int) ([]*model.Message, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
 	}
 
+	seqFilter := bson.M{"$exists": true}
+	if afterSeq > 0 {
+		seqFilter["$gt"] = afterSeq
+	}
+	if beforeSeq > 0 {
+		seqFilter["$lt"] = beforeSeq
+	}
 	filter := bson.M{
-		"message_id": messageID,
-		"$or": bson.A{
-			bson.M{statusKey: bson.M{"$exists": false}},
-			bson.M{statusStatusKey: bson.M{"$in": allowedPrev}},
-		},
+		"chat_id": chatID,
+		"seq":     seqFilter,
 	}
 
-	update := bson.M{
-		"$set": bson.M{
-			statusStatusKey:  string(recipientStatus.Status),
-			statusUpdatedKey: recipientStatus.UpdatedAt,
-			"updated_at":     time.Now(),
-		},
+	order := -1
+	if afterSeq > 0 {
+		order = 1
 	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "seq", Value: order}}).
+		SetLimit(int64(limit))
 
-	result, err := r.col.UpdateOne(ctx, filter, update)
+	cursor, err := r.col.Find(ctx, filter, opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer cursor.Close(ctx)
 
-	if result.MatchedCount == 0 {
-		count, err := r.col.CountDocuments(ctx, bson.M{"message_id": messageID})
-		if err != nil {
-			return err
-		}
-		if count == 0 {
-			return mongo.ErrNoDocuments
+	var messages []*model.Message
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// ListThread returns replies in a thread sorted by (created_at asc, message_id
+// asc). Filters out deleted messages.
+func (r *messageMongoRepo) ListThread(ctx context.Context, rootID string, cursorTime *time.Time, cursorID string, limit int) ([]*model.Message, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	filter := bson.M{
+		"thread_id":  rootID,
+		"is_deleted": false,
+	}
+
+	if cursorTime != nil {
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$gt": *cursorTime}},
+			bson.M{
+				"created_at": *cursorTime,
+				"message_id": bson.M{"$gt": cursorID},
+			},
 		}
-		// Message exists but status transition not allowed — no-op.
 	}
 
-	return nil
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "message_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []*model.Message
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// UpdateReplyCount adjusts a thread root's reply counter. Decrements only
+// match while the counter can absorb them, so it never goes negative, and
+// take last_reply_at back to the newest reply that is left.
+func (r *messageMongoRepo) UpdateReplyCount(ctx context.Context, rootID string, delta int64, replyAt *time.Time) (*model.Message, error) {
+	filter := bson.M{"message_id": rootID}
+	if delta < 0 {
+		filter["reply_count"] = bson.M{"$gte": -delta}
+	}
+	update := bson.M{"$inc": bson.M{"reply_count": delta}}
+	if replyAt != nil {
+		update["$max"] = bson.M{"last_reply_at": *replyAt}
+	}
+
+	var root model.Message
+	err := r.col.FindOneAndUpdate(ctx, filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&root)
+	if err != nil {
+		return nil, err
+	}
+	if delta < 0 {
+		return r.resetLastReplyAt(ctx, rootID)
+	}
+	return &root, nil
+}
+
+// resetLastReplyAt sets a thread root's last_reply_at to the creation time of
+// its newest non-deleted reply, or removes it if no reply is left.
+func (r *messageMongoRepo) resetLastReplyAt(ctx context.Context, rootID string) (*model.Message, error) {
+	var latest model.Message
+	err := r.col.FindOne(ctx,
+		bson.M{"thread_id": rootID, "is_deleted": false},
+		options.FindOne().
+			SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "message_id", Value: -1}}).
+			SetProjection(bson.M{"created_at": 1}),
+	).Decode(&latest)
+
+	var update bson.M
+	switch {
+	case err == nil:
+		update = bson.M{"$set": bson.M{"last_reply_at": latest.CreatedAt}}
+	case errors.Is(err, mongo.ErrNoDocuments):
+		update = bson.M{"$unset": bson.M{"last_reply_at": ""}}
+	default:
+		return nil, fmt.Errorf("find latest thread reply: %w", err)
+	}
+
+	var root model.Message
+	err = r.col.FindOneAndUpdate(ctx, bson.M{"message_id": rootID}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&root)
+	if err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// EditMessage replaces the body and caption and pushes the previous version
+// onto revisions in a single pipeline update, so concurrent edits each record
+// the version they actually replaced.
+func (r *messageMongoRepo) EditMessage(ctx context.Context, messageID, senderID string, payload model.MessagePayload, editedAt time.Time) (*model.Message, error) {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$set", Value: bson.M{
+			"revisions": bson.M{
+				"$concatArrays": bson.A{
+					bson.M{"$ifNull": bson.A{"$revisions", bson.A{}}},
+					bson.A{bson.M{
+						"body":       "$payload.body",
+						"caption":    "$payload.caption",
+						"created_at": bson.M{"$ifNull": bson.A{"$edited_at", "$created_at"}},
+					}},
+				},
+			},
+		}}},
+		bson.D{{Key: "$set", Value: bson.M{
+			"payload.body":    payload.Body,
+			"payload.caption": payload.Caption,
+			"edited_at":       editedAt,
+			"updated_at":      editedAt,
+		}}},
+	}
+
+	var msg model.Message
+	err := r.col.FindOneAndUpdate(ctx,
+		bson.M{"message_id": messageID, "sender_id": senderID, "is_deleted": false},
+		pipeline,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&msg)
+	if err != nil {
+		return nil, err
+	}
+	return &msg, nil
 }
 
 // SoftDelete marks a message as deleted and clears its payload.
 // Only the sender (verified by senderID) can delete.
 func (r *messageMongoRepo) SoftDelete(ctx context.Context, messageID, senderID string) error {
-	result, err := r.col.UpdateOne(ctx,
+	var prev model.Message
+	err := r.col.FindOneAndUpdate(ctx,
 		bson.M{"message_id": messageID, "sender_id": senderID},
 		bson.M{"$set": bson.M{
 			"is_deleted": true,
 			"payload":    model.MessagePayload{},
 			"updated_at": time.Now(),
 		}},
-	)
+		options.FindOneAndUpdate().SetProjection(bson.M{"chat_id": 1, "seq": 1, "sender_id": 1, "is_deleted": 1}),
+	).Decode(&prev)
 	if err != nil {
 		return err
 	}
-	if result.MatchedCount == 0 {
-		return mongo.ErrNoDocuments
+	if !prev.IsDeleted {
+		r.adjustUnread(ctx, &prev, -1)
 	}
 	return nil
 }
@@ -287,64 +493,6 @@ func (r *messageMongoRepo) RemoveReaction(ctx context.Context, messageID, userID
 	return nil
 }
 
-// Search performs a full-text search on payload.body within a chat.
-func (r *messageMongoRepo) Search(ctx context.Context, chatID, query string, limit int) ([]*model.Message, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 20
-	}
-
-	filter := bson.M{
-		"chat_id":    chatID,
-		"is_deleted": false,
-		"$text":      bson.M{"$search": query},
-	}
-
-	opts := options.Find().
-		SetSort(bson.D{{Key: "created_at", Value: -1}}).
-		SetLimit(int64(limit))
-
-	cursor, err := r.col.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var messages []*model.Message
-	if err := cursor.All(ctx, &messages); err != nil {
-		return nil, err
-	}
-	return messages, nil
-}
-
-// SearchGlobal performs a full-text search across multiple chats.
-func (r *messageMongoRepo) SearchGlobal(ctx context.Context, chatIDs []string, query string, limit int) ([]*model.Message, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 20
-	}
-
-	filter := bson.M{
-		"chat_id":    bson.M{"$in": chatIDs},
-		"is_deleted": false,
-		"$text":      bson.M{"$search": query},
-	}
-
-	opts := options.Find().
-		SetSort(bson.D{{Key: "created_at", Value: -1}}).
-		SetLimit(int64(limit))
-
-	cursor, err := r.col.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var messages []*model.Message
-	if err := cursor.All(ctx, &messages); err != nil {
-		return nil, err
-	}
-	return messages, nil
-}
-
 // GetLastPerChat returns the latest message for each chat using aggregation.
 func (r *messageMongoRepo) GetLastPerChat(ctx context.Context, chatIDs []string) (map[string]*model.Message, error) {
 	pipeline := mongo.Pipeline{
@@ -353,6 +501,7 @@ func (r *messageMongoRepo) GetLastPerChat(ctx context.Context, chatIDs []string)
 			"is_deleted": false,
 		}}},
 		bson.D{{Key: "$sort", Value: bson.D{
+			{Key: "seq", Value: -1},
 			{Key: "created_at", Value: -1},
 		}}},
 		bson.D{{Key: "$group", Value: bson.M{
@@ -385,72 +534,52 @@ func (r *messageMongoRepo) GetLastPerChat(ctx context.Context, chatIDs []string)
 	return lastMessages, nil
 }
 
-// DeleteExpiredMessages soft-deletes messages older than the given cutoff time.
-// Used by the disappearing messages cleanup job.
-func (r *messageMongoRepo) DeleteExpiredMessages(ctx context.Context, olderThan time.Time) (int64, error) {
+// ExpireMessages soft-deletes up to limit messages whose expires_at is at or
+// before now and returns them (ID, chat and thread only). Expired messages are kept as
+// tombstones rather than removed by a TTL index so seq ranges stay gap-free
+// and clients can be told which messages disappeared.
+func (r *messageMongoRepo) ExpireMessages(ctx context.Context, now time.Time, limit int) ([]*model.Message, error) {
 	filter := bson.M{
-		"created_at": bson.M{"$lt": olderThan},
+		"expires_at": bson.M{"$lte": now},
 		"is_deleted": false,
 	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "expires_at", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"message_id": 1, "chat_id": 1, "seq": 1, "sender_id": 1, "thread_id": 1, "expires_at": 1})
 
-	result, err := r.col.UpdateMany(ctx, filter, bson.M{
-		"$set": bson.M{
-			"is_deleted": true,
-			"payload":    bson.M{},
-			"updated_at": time.Now(),
-		},
-	})
+	cursor, err := r.col.Find(ctx, filter, opts)
 	if err != nil {
-		return 0, err
+		return nil, fmt.Errorf("find expired messages: %w", err)
 	}
-	return result.ModifiedCount, nil
-}
-
-// CountUnread counts messages per chat that the given user has not read.
-// Uses aggregation to batch across all requested chat IDs.
-func (r *messageMongoRepo) CountUnread(ctx context.Context, userID string, chatIDs []string) (map[string]int64, error) {
-	statusKey := fmt.Sprintf("status.%s", userID)
-	statusStatusKey := fmt.Sprintf("status.%s.status", userID)
-
-	pipeline := mongo.Pipeline{
-		bson.D{{Key: "$match", Value: bson.M{
-			"chat_id":    bson.M{"$in": chatIDs},
-			"sender_id":  bson.M{"$ne": userID},
-			"is_deleted": false,
-			"$or": bson.A{
-				bson.M{statusKey: bson.M{"$exists": false}},
-				bson.M{statusStatusKey: bson.M{"$ne": string(model.StatusRead)}},
-			},
-		}}},
-		bson.D{{Key: "$group", Value: bson.M{
-			"_id":   "$chat_id",
-			"count": bson.M{"$sum": 1},
-		}}},
+	var expired []*model.Message
+	if err := cursor.All(ctx, &expired); err != nil {
+		return nil, fmt.Errorf("decode expired messages: %w", err)
 	}
-
-	cursor, err := r.col.Aggregate(ctx, pipeline)
-	if err != nil {
-		return nil, err
+	if len(expired) == 0 {
+		return nil, nil
 	}
-	defer cursor.Close(ctx)
 
-	result := make(map[string]int64, len(chatIDs))
-	for cursor.Next(ctx) {
-		var item struct {
-			ID    string `bson:"_id"`
-			Count int64  `bson:"count"`
-		}
-		if err := cursor.Decode(&item); err != nil {
-			return nil, err
-		}
-		result[item.ID] = item.Count
+	ids := make([]string, 0, len(expired))
+	for _, m := range expired {
+		ids = append(ids, m.MessageID)
 	}
-
-	for _, chatID := range chatIDs {
-		if _, ok := result[chatID]; !ok {
-			result[chatID] = 0
-		}
+	_, err = r.col.UpdateMany(ctx,
+		bson.M{"message_id": bson.M{"$in": ids}, "is_deleted": false},
+		bson.M{
+			"$set": bson.M{
+				"is_deleted": true,
+				"payload":    model.MessagePayload{},
+				"updated_at": now,
+			},
+			"$unset": bson.M{"revisions": ""},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("expire messages: %w", err)
 	}
-
-	return result, nil
+	for _, m := range expired {
+		r.adjustUnread(ctx, m, -1)
+	}
+	return expired, nil
 }
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+)
+
+var (
+	// ErrPollClosed is returned when voting on a poll that has been closed.
+	ErrPollClosed = errors.New("poll is closed")
+	// ErrPollVoteConflict is returned when a user's vote kept changing
+	// underneath VotePoll and it gave up retrying.
+	ErrPollVoteConflict = errors.New("poll vote changed concurrently")
+)
+
+// pollVoteAttempts bounds VotePoll's compare-and-set retries.
+const pollVoteAttempts = 3
+
+// VotePoll replaces userID's vote with optionIDs (empty retracts it). The
+// update only applies if the user's stored vote is still the one it was
+// computed from, and adjusts option tallies with $inc in the same write, so
+// concurrent votes never double count.
+func (r *messageMongoRepo) VotePoll(ctx context.Context, messageID, userID string, optionIDs []string, now time.Time) (*model.Message, error) {
+	voteKey := "payload.poll.votes." + userID
+
+	for attempt := 0; attempt < pollVoteAttempts; attempt++ {
+		msg, err := r.GetByID(ctx, messageID)
+		if err != nil {
+			return nil, err
+		}
+		if msg == nil || msg.IsDeleted || msg.Payload.Poll == nil {
+			return nil, mongo.ErrNoDocuments
+		}
+		poll := msg.Payload.Poll
+		if poll.IsClosed(now) {
+			return nil, ErrPollClosed
+		}
+
+		index := make(map[string]int, len(poll.Options))
+		for i, o := range poll.Options {
+			index[o.ID] = i
+		}
+		delta := make(map[int]int64)
+		prev, voted := poll.Votes[userID]
+		for _, id := range prev {
+			if i, ok := index[id]; ok {
+				delta[i]--
+			}
+		}
+		for _, id := range optionIDs {
+			delta[index[id]]++
+		}
+
+		inc := bson.M{}
+		for i, d := range delta {
+			if d != 0 {
+				inc[fmt.Sprintf("payload.poll.options.%d.vote_count", i)] = d
+			}
+		}
+		if len(inc) == 0 && voted == (len(optionIDs) > 0) {
+			return msg, nil
+		}
+
+		filter := bson.M{
+			"message_id":             messageID,
+			"is_deleted":             false,
+			"payload.poll.closed_at": bson.M{"$exists": false},
+		}
+		if voted {
+			filter[voteKey] = prev
+		} else {
+			filter[voteKey] = bson.M{"$exists": false}
+		}
+
+		update := bson.M{"$set": bson.M{"updated_at": now}}
+		if len(optionIDs) > 0 {
+			update["$set"].(bson.M)[voteKey] = optionIDs
+		} else {
+			update["$unset"] = bson.M{voteKey: ""}
+		}
+		if len(inc) > 0 {
+			update["$inc"] = inc
+		}
+
+		var updated model.Message
+		err = r.col.FindOneAndUpdate(ctx, filter, update,
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&updated)
+		if err == nil {
+			return &updated, nil
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("vote poll: %w", err)
+		}
+		// The vote or poll changed since it was read; re-read and retry.
+	}
+	return nil, ErrPollVoteConflict
+}
+
+// ClosePoll stamps closed_at on an open poll and returns the updated message.
+func (r *messageMongoRepo) ClosePoll(ctx context.Context, messageID string, now time.Time) (*model.Message, error) {
+	var msg model.Message
+	err := r.col.FindOneAndUpdate(ctx,
+		bson.M{"message_id": messageID, "type": model.MessageTypePoll, "is_deleted": false},
+		bson.M{"$set": bson.M{"payload.poll.closed_at": now, "updated_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&msg)
+	if err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
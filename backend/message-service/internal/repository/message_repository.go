@@ -10,6 +10,8 @@ import (
 type MessageRepository interface {
 	// Insert creates a new message. Uses client_msg_id unique index for idempotency.
 	// Returns the existing message if client_msg_id already exists.
+	// Assigns the next per-chat seq atomically before inserting, and counts
+	// the message as unread for the chat's other members.
 	Insert(ctx context.Context, msg *model.Message) (*model.Message, error)
 
 	// GetByID retrieves a single message by message_id.
@@ -19,10 +21,33 @@ type MessageRepository interface {
 	// Cursor is (created_at, message_id) for deterministic ordering.
 	ListByChatID(ctx context.Context, chatID string, cursorTime *time.Time, cursorID string, limit int) ([]*model.Message, error)
 
-	// UpdateStatus updates the status map entry for a specific recipient.
-	UpdateStatus(ctx context.Context, messageID, userID string, status model.RecipientStatus) error
-
-	// SoftDelete marks a message as deleted (sets is_deleted=true, clears payload).
+	// ListForExport returns up to limit messages of a chat oldest first,
+	// starting after the (afterTime, afterID) message when afterTime is set.
+	// Deleted messages and those userID deleted for themselves are skipped.
+	ListForExport(ctx context.Context, chatID, userID string, afterTime *time.Time, afterID string, limit int) ([]*model.Message, error)
+
+	// ListBySeq returns messages with afterSeq < seq < beforeSeq (0 = unbounded),
+	// including deleted tombstones so callers can tell deletions from holes.
+	// Sorted ascending when afterSeq is set, otherwise descending.
+	ListBySeq(ctx context.Context, chatID string, afterSeq, beforeSeq int64, limit int) ([]*model.Message, error)
+
+	// ListThread returns the non-deleted replies in a thread oldest first,
+	// starting after the (cursorTime, cursorID) reply when cursorTime is set.
+	ListThread(ctx context.Context, rootID string, cursorTime *time.Time, cursorID string, limit int) ([]*model.Message, error)
+
+	// UpdateReplyCount adds delta to a thread root's reply_count (never taking
+	// it below zero) and raises last_reply_at to replyAt if set. When replies
+	// are removed, last_reply_at is recomputed from the ones left. Returns the
+	// updated root, or mongo.ErrNoDocuments if it does not exist.
+	UpdateReplyCount(ctx context.Context, rootID string, delta int64, replyAt *time.Time) (*model.Message, error)
+
+	// EditMessage replaces the message's body and caption, appending the prior
+	// version to revisions atomically. Only the sender can edit a non-deleted
+	// message; returns mongo.ErrNoDocuments otherwise.
+	EditMessage(ctx context.Context, messageID, senderID string, payload model.MessagePayload, editedAt time.Time) (*model.Message, error)
+
+	// SoftDelete marks a message as deleted (sets is_deleted=true, clears payload)
+	// and takes it off the unread counts of members who had not read it.
 	SoftDelete(ctx context.Context, messageID, senderID string) error
 
 	// SoftDeleteForUser adds a user to the deleted_for_users list for per-user deletion.
@@ -40,19 +65,56 @@ type MessageRepository interface {
 	// RemoveReaction removes a user's reaction from a message.
 	RemoveReaction(ctx context.Context, messageID, userID string) error
 
-	// Search performs a full-text search within a chat using MongoDB $text index.
-	Search(ctx context.Context, chatID, query string, limit int) ([]*model.Message, error)
+	// VotePoll replaces userID's vote on a poll with optionIDs (empty retracts
+	// it) and adjusts option tallies atomically. Returns ErrPollClosed if the
+	// poll is closed and mongo.ErrNoDocuments if it is missing or deleted.
+	VotePoll(ctx context.Context, messageID, userID string, optionIDs []string, now time.Time) (*model.Message, error)
+
+	// ClosePoll marks a poll closed so no further votes are accepted.
+	ClosePoll(ctx context.Context, messageID string, now time.Time) (*model.Message, error)
+
+	// UpdateLiveLocation sets a new position on the sender's active live
+	// location share. Returns mongo.ErrNoDocuments if the message is not an
+	// active share sent by senderID.
+	UpdateLiveLocation(ctx context.Context, messageID, senderID string, lat, lng, accuracy float64, now time.Time) (*model.Message, error)
+
+	// StopLiveLocation ends an active live location share.
+	StopLiveLocation(ctx context.Context, messageID string, now time.Time) (*model.Message, error)
 
-	// SearchGlobal performs a full-text search across multiple chats.
-	SearchGlobal(ctx context.Context, chatIDs []string, query string, limit int) ([]*model.Message, error)
+	// EndExpiredLiveLocations ends up to limit live shares whose live_until
+	// has passed and returns them. Used by the live location expirer.
+	EndExpiredLiveLocations(ctx context.Context, now time.Time, limit int) ([]*model.Message, error)
+
+	// Search runs a ranked full-text search over the query's chats using the
+	// weighted $text index, applying its filters and cursor, and returns up to
+	// query.Limit hits with Score set (Snippet is left to the caller).
+	Search(ctx context.Context, query *model.SearchQuery) ([]*model.SearchHit, error)
 
 	// GetLastPerChat returns the latest message for each given chat ID.
 	GetLastPerChat(ctx context.Context, chatIDs []string) (map[string]*model.Message, error)
 
+	// AdvanceWatermark moves userID's delivered and read watermarks in chatID
+	// up to deliveredSeq and readSeq, creating them on first use. Seqs at or
+	// below the current watermarks leave them as they are. Returns the
+	// updated watermark and whether either seq moved.
+	AdvanceWatermark(ctx context.Context, chatID, userID string, deliveredSeq, readSeq int64, at time.Time) (*model.Watermark, bool, error)
+
+	// ListWatermarks returns the watermarks of every user who has received
+	// or read messages in chatID.
+	ListWatermarks(ctx context.Context, chatID string) ([]*model.Watermark, error)
+
 	// CountUnread returns the count of unread messages per chat for the given user.
 	CountUnread(ctx context.Context, userID string, chatIDs []string) (map[string]int64, error)
 
-	// DeleteExpiredMessages soft-deletes messages older than the given cutoff time.
+	// ExpireMessages soft-deletes up to limit messages whose expires_at has
+	// passed and returns their message_id/chat_id/seq/thread_id.
 	// Used by the disappearing messages cleanup job.
-	DeleteExpiredMessages(ctx context.Context, olderThan time.Time) (int64, error)
+	ExpireMessages(ctx context.Context, now time.Time, limit int) ([]*model.Message, error)
+
+	// AnonymizeUser strips a deleted account from every message: its sent
+	// messages are attributed to accountdeletion.DeletedUserID and its
+	// stars, reactions, receipts, watermarks, per-user deletions and
+	// encrypted envelopes are removed. Poll votes are kept under pseudonym
+	// so tallies stay consistent.
+	AnonymizeUser(ctx context.Context, userID, pseudonym string) error
 }
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+)
+
+const (
+	searchIndexName = "message_search"
+	// legacyTextIndexName is the body-only text index created before captions
+	// and filenames were searchable. A collection may hold one text index.
+	legacyTextIndexName = "payload.body_text"
+)
+
+// ensureSearchIndex creates the weighted text index used for ranked search,
+// replacing the legacy body-only text index if present.
+func ensureSearchIndex(ctx context.Context, col *mongo.Collection, log zerolog.Logger) {
+	_, _ = col.Indexes().DropOne(ctx, legacyTextIndexName)
+
+	_, err := col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "payload.body", Value: "text"},
+			{Key: "payload.caption", Value: "text"},
+			{Key: "payload.filename", Value: "text"},
+		},
+		Options: options.Index().
+			SetName(searchIndexName).
+			SetWeights(bson.D{
+				{Key: "payload.body", Value: 10},
+				{Key: "payload.caption", Value: 5},
+				{Key: "payload.filename", Value: 2},
+			}).
+			SetDefaultLanguage("english"),
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to ensure search index on messages collection")
+	}
+}
+
+// searchRow decodes a message together with its computed text score.
+type searchRow struct {
+	model.Message `bson:",inline"`
+	Score         float64 `bson:"score"`
+}
+
+func (r *messageMongoRepo) Search(ctx context.Context, q *model.SearchQuery) ([]*model.SearchHit, error) {
+	match := bson.M{
+		"$text":      bson.M{"$search": q.Text},
+		"chat_id":    bson.M{"$in": q.ChatIDs},
+		"is_deleted": false,
+	}
+	if q.UserID != "" {
+		match["deleted_for_users"] = bson.M{"$ne": q.UserID}
+	}
+	if q.SenderID != "" {
+		match["sender_id"] = q.SenderID
+	}
+	if len(q.Types) > 0 {
+		match["type"] = bson.M{"$in": q.Types}
+	}
+	if q.HasMedia != nil {
+		if *q.HasMedia {
+			match["payload.media_id"] = bson.M{"$exists": true, "$ne": ""}
+		} else {
+			match["$or"] = bson.A{
+				bson.M{"payload.media_id": bson.M{"$exists": false}},
+				bson.M{"payload.media_id": ""},
+			}
+		}
+	}
+	if q.From != nil || q.To != nil {
+		created := bson.M{}
+		if q.From != nil {
+			created["$gte"] = *q.From
+		}
+		if q.To != nil {
+			created["$lt"] = *q.To
+		}
+		match["created_at"] = created
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}},
+	}
+	if c := q.After; c != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"score": bson.M{"$lt": c.Score}},
+			bson.M{"score": c.Score, "created_at": bson.M{"$lt": c.CreatedAt}},
+			bson.M{"score": c.Score, "created_at": c.CreatedAt, "message_id": bson.M{"$lt": c.MessageID}},
+		}}}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: bson.D{
+			{Key: "score", Value: -1},
+			{Key: "created_at", Value: -1},
+			{Key: "message_id", Value: -1},
+		}}},
+		bson.D{{Key: "$limit", Value: q.Limit}},
+	)
+
+	cursor, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("search messages: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []searchRow
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("decode search results: %w", err)
+	}
+
+	hits := make([]*model.SearchHit, 0, len(rows))
+	for i := range rows {
+		hits = append(hits, &model.SearchHit{Message: &rows[i].Message, Score: rows[i].Score})
+	}
+	return hits, nil
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+)
+
+// maxUnreadRecounts bounds how often AdvanceWatermark recounts the unread
+// counter while sends and deletes keep adjusting it.
+const maxUnreadRecounts = 3
+
+// AdvanceWatermark raises the watermarks with $max in a pipeline update, so
+// concurrent calls never move them back and each *_at only changes along
+// with its seq. When the read watermark moves, or is new, the unread counter
+// is recounted from the messages after it: these are few once a chat has
+// been read, and recounting settles any drift adjustUnread left behind. The
+// recount is only stored if read_seq and unread_rev are still what it was
+// counted against; a send or delete landing in between is retried rather
+// than overwritten.
+func (r *messageMongoRepo) AdvanceWatermark(ctx context.Context, chatID, userID string, deliveredSeq, readSeq int64, at time.Time) (*model.Watermark, bool, error) {
+	advance := func(seqField, atField string, seq int64) bson.E {
+		return bson.E{Key: atField, Value: bson.M{"$cond": bson.A{
+			bson.M{"$gt": bson.A{seq, bson.M{"$ifNull": bson.A{"$" + seqField, int64(0)}}}},
+			at,
+			"$" + atField,
+		}}}
+	}
+	update := mongo.Pipeline{
+		bson.D{{Key: "$set", Value: bson.D{
+			advance("delivered_seq", "delivered_at", deliveredSeq),
+			advance("read_seq", "read_at", readSeq),
+			{Key: "delivered_seq", Value: bson.M{"$max": bson.A{bson.M{"$ifNull": bson.A{"$delivered_seq", int64(0)}}, deliveredSeq}}},
+			{Key: "read_seq", Value: bson.M{"$max": bson.A{bson.M{"$ifNull": bson.A{"$read_seq", int64(0)}}, readSeq}}},
+			{Key: "unread_count", Value: bson.M{"$ifNull": bson.A{"$unread_count", int64(0)}}},
+			{Key: "unread_rev", Value: bson.M{"$ifNull": bson.A{"$unread_rev", int64(0)}}},
+		}}},
+	}
+
+	var prev model.Watermark
+	err := r.watermarks.FindOneAndUpdate(ctx,
+		bson.M{"chat_id": chatID, "user_id": userID},
+		update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before),
+	).Decode(&prev)
+	created := errors.Is(err, mongo.ErrNoDocuments)
+	if err != nil && !created {
+		return nil, false, fmt.Errorf("advance watermark: %w", err)
+	}
+
+	wm := prev
+	wm.ChatID, wm.UserID = chatID, userID
+	if deliveredSeq > wm.DeliveredSeq {
+		wm.DeliveredSeq, wm.DeliveredAt = deliveredSeq, at
+	}
+	if readSeq > wm.ReadSeq {
+		wm.ReadSeq, wm.ReadAt = readSeq, at
+	}
+	if !created && wm.ReadSeq == prev.ReadSeq {
+		return &wm, wm.DeliveredSeq > prev.DeliveredSeq, nil
+	}
+
+	key := bson.M{"chat_id": chatID, "user_id": userID}
+	rev := prev.UnreadRev
+	for attempt := 1; ; attempt++ {
+		n, err := r.col.CountDocuments(ctx, bson.M{
+			"chat_id":    chatID,
+			"seq":        bson.M{"$gt": wm.ReadSeq},
+			"sender_id":  bson.M{"$ne": userID},
+			"is_deleted": false,
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("count unread: %w", err)
+		}
+		res, err := r.watermarks.UpdateOne(ctx,
+			bson.M{"chat_id": chatID, "user_id": userID, "read_seq": wm.ReadSeq, "unread_rev": rev},
+			bson.M{"$set": bson.M{"unread_count": n}},
+		)
+		if err != nil {
+			return nil, false, fmt.Errorf("update unread count: %w", err)
+		}
+		if res.MatchedCount > 0 {
+			wm.UnreadCount = n
+			return &wm, true, nil
+		}
+
+		var cur model.Watermark
+		if err := r.watermarks.FindOne(ctx, key).Decode(&cur); err != nil {
+			return nil, false, fmt.Errorf("reload watermark: %w", err)
+		}
+		// A later read recounts on its own; past the last attempt the
+		// adjusted counter is kept as it is.
+		if cur.ReadSeq != wm.ReadSeq || attempt == maxUnreadRecounts {
+			wm.UnreadCount = cur.UnreadCount
+			return &wm, true, nil
+		}
+		rev = cur.UnreadRev
+	}
+}
+
+// ListWatermarks returns every watermark in the chat.
+func (r *messageMongoRepo) ListWatermarks(ctx context.Context, chatID string) ([]*model.Watermark, error) {
+	cursor, err := r.watermarks.Find(ctx, bson.M{"chat_id": chatID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var watermarks []*model.Watermark
+	if err := cursor.All(ctx, &watermarks); err != nil {
+		return nil, err
+	}
+	return watermarks, nil
+}
+
+// CountUnread reads the unread counters off the user's watermarks. Chats the
+// user has no watermark in yet have never been read, so every live message
+// from others in them is counted.
+func (r *messageMongoRepo) CountUnread(ctx context.Context, userID string, chatIDs []string) (map[string]int64, error) {
+	cursor, err := r.watermarks.Find(ctx,
+		bson.M{"chat_id": bson.M{"$in": chatIDs}, "user_id": userID},
+		options.Find().SetProjection(bson.M{"chat_id": 1, "unread_count": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var watermarks []*model.Watermark
+	if err := cursor.All(ctx, &watermarks); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64, len(chatIDs))
+	for _, wm := range watermarks {
+		result[wm.ChatID] = wm.UnreadCount
+	}
+	var unseen []string
+	for _, chatID := range chatIDs {
+		if _, ok := result[chatID]; !ok {
+			unseen = append(unseen, chatID)
+			result[chatID] = 0
+		}
+	}
+	if len(unseen) == 0 {
+		return result, nil
+	}
+
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.M{
+			"chat_id":    bson.M{"$in": unseen},
+			"sender_id":  bson.M{"$ne": userID},
+			"is_deleted": false,
+		}}},
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":   "$chat_id",
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+	agg, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer agg.Close(ctx)
+
+	for agg.Next(ctx) {
+		var item struct {
+			ID    string `bson:"_id"`
+			Count int64  `bson:"count"`
+		}
+		if err := agg.Decode(&item); err != nil {
+			return nil, err
+		}
+		result[item.ID] = item.Count
+	}
+	return result, agg.Err()
+}
+
+// adjustUnread adds delta to the unread counters of the members who have not
+// read msg yet: +1 when it is sent, -1 when it is deleted. Members without a
+// watermark have their counter seeded when they get one. Failures are logged,
+// as the message itself was already written; the counter is recounted the
+// next time the member reads the chat.
+func (r *messageMongoRepo) adjustUnread(ctx context.Context, msg *model.Message, delta int64) {
+	if msg.Seq == 0 {
+		return
+	}
+	filter := bson.M{
+		"chat_id":  msg.ChatID,
+		"user_id":  bson.M{"$ne": msg.SenderID},
+		"read_seq": bson.M{"$lt": msg.Seq},
+	}
+	if delta < 0 {
+		filter["unread_count"] = bson.M{"$gt": 0}
+	}
+	_, err := r.watermarks.UpdateMany(ctx, filter, bson.M{"$inc": bson.M{"unread_count": delta, "unread_rev": 1}})
+	if err != nil {
+		r.log.Warn().Err(err).Str("message_id", msg.MessageID).Int64("delta", delta).Msg("failed to adjust unread counters")
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	chatv1 "github.com/whatsapp-clone/backend/proto/chat/v1"
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
+)
+
+// checkNotBlocked refuses a direct message when either user has blocked the
+// other. A failed lookup refuses it too, so a user-service outage cannot let
+// a blocked user's messages through.
+func (s *messageServiceImpl) checkNotBlocked(ctx context.Context, senderID, recipientID string) error {
+	resp, err := s.userClient.IsBlocked(ctx, &userv1.IsBlockedRequest{
+		UserId:      senderID,
+		OtherUserId: recipientID,
+	})
+	if err != nil {
+		return apperr.NewInternal("failed to check blocks", err)
+	}
+	if resp.Blocking {
+		return apperr.Wrap(apperr.CodeUserBlocked, 403, "unblock this contact to send a message", nil)
+	}
+	if resp.BlockedBy {
+		return apperr.Wrap(apperr.CodeUserBlocked, 403, "you cannot message this user", nil)
+	}
+	return nil
+}
+
+// checkChatNotBlocked checks that userID is a member of the chat and, if it
+// is a direct chat, that neither member has blocked the other. Like
+// checkNotBlocked it fails closed.
+func (s *messageServiceImpl) checkChatNotBlocked(ctx context.Context, userID, chatID string) error {
+	permResp, err := s.chatClient.CheckChatPermission(ctx, &chatv1.CheckChatPermissionRequest{
+		ChatId: chatID,
+		UserId: userID,
+	})
+	if err != nil {
+		return apperr.NewInternal("failed to verify chat membership", err)
+	}
+	if !permResp.IsMember {
+		return apperr.NewForbidden("not a member of this chat")
+	}
+	if permResp.PeerUserId == "" {
+		return nil
+	}
+	return s.checkNotBlocked(ctx, userID, permResp.PeerUserId)
+}
@@ -9,21 +9,28 @@ import (
 	"github.com/whatsapp-clone/backend/message-service/internal/repository"
 )
 
-// DisappearingMessagesCleaner runs a periodic job to delete expired messages.
+// expireBatchSize caps how many messages one sweep pass soft-deletes, so a
+// backlog is worked off in bounded updates.
+const expireBatchSize = 500
+
+// DisappearingMessagesCleaner runs a periodic job that soft-deletes messages
+// whose expires_at (set at send time from the chat's timer) has passed.
 type DisappearingMessagesCleaner struct {
-	repo     repository.MessageRepository
-	interval time.Duration
-	log      zerolog.Logger
-	stopCh   chan struct{}
+	repo      repository.MessageRepository
+	publisher *EventPublisher
+	interval  time.Duration
+	log       zerolog.Logger
+	stopCh    chan struct{}
 }
 
 // NewDisappearingMessagesCleaner creates a new cleaner with the given check interval.
-func NewDisappearingMessagesCleaner(repo repository.MessageRepository, interval time.Duration, log zerolog.Logger) *DisappearingMessagesCleaner {
+func NewDisappearingMessagesCleaner(repo repository.MessageRepository, pub *EventPublisher, interval time.Duration, log zerolog.Logger) *DisappearingMessagesCleaner {
 	return &DisappearingMessagesCleaner{
-		repo:     repo,
-		interval: interval,
-		log:      log.With().Str("component", "disappearing-cleaner").Logger(),
-		stopCh:   make(chan struct{}),
+		repo:      repo,
+		publisher: pub,
+		interval:  interval,
+		log:       log.With().Str("component", "disappearing-cleaner").Logger(),
+		stopCh:    make(chan struct{}),
 	}
 }
 
@@ -56,25 +63,38 @@ func (c *DisappearingMessagesCleaner) Stop() {
 }
 
 func (c *DisappearingMessagesCleaner) runCleanup(ctx context.Context) {
-	// Standard disappearing message thresholds matching WhatsApp options
-	thresholds := []time.Duration{
-		24 * time.Hour,      // 24h
-		7 * 24 * time.Hour,  // 7d
-		90 * 24 * time.Hour, // 90d
-	}
-
-	totalDeleted := int64(0)
-	for _, threshold := range thresholds {
-		cutoff := time.Now().Add(-threshold)
-		count, err := c.repo.DeleteExpiredMessages(ctx, cutoff)
+	totalDeleted := 0
+	for {
+		expired, err := c.repo.ExpireMessages(ctx, time.Now(), expireBatchSize)
 		if err != nil {
-			c.log.Error().Err(err).Dur("threshold", threshold).Msg("failed to delete expired messages")
-			continue
+			c.log.Error().Err(err).Msg("failed to delete expired messages")
+			break
+		}
+
+		byChat := make(map[string][]string)
+		byThread := make(map[string]int64)
+		for _, m := range expired {
+			byChat[m.ChatID] = append(byChat[m.ChatID], m.MessageID)
+			if m.ThreadID != "" {
+				byThread[m.ThreadID]++
+			}
+		}
+		for chatID, ids := range byChat {
+			if err := c.publisher.PublishMessagesExpired(ctx, chatID, ids); err != nil {
+				c.log.Error().Err(err).Str("chat_id", chatID).Msg("failed to publish msg.expired event")
+			}
+		}
+		for rootID, n := range byThread {
+			adjustReplyCount(ctx, c.repo, c.publisher, c.log, rootID, -n, nil)
+		}
+
+		totalDeleted += len(expired)
+		if len(expired) < expireBatchSize {
+			break
 		}
-		totalDeleted += count
 	}
 
 	if totalDeleted > 0 {
-		c.log.Info().Int64("deleted", totalDeleted).Msg("cleaned up disappearing messages")
+		c.log.Info().Int("deleted", totalDeleted).Msg("cleaned up disappearing messages")
 	}
 }
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
+)
+
+var e164Regex = regexp.MustCompile(`^\+[1-9]\d{1,14}$`)
+
+// validateContacts checks the raw shape of a contact message before any
+// vCard is parsed.
+func validateContacts(contacts []model.Contact) error {
+	if len(contacts) == 0 {
+		return apperr.NewBadRequest("contact message requires at least one contact")
+	}
+	if len(contacts) > model.ContactMaxCards {
+		return apperr.NewBadRequest(fmt.Sprintf("contact message cannot have more than %d contacts", model.ContactMaxCards))
+	}
+	for _, c := range contacts {
+		if len(c.VCard) > model.ContactMaxVCardLength {
+			return apperr.NewBadRequest(fmt.Sprintf("vcard cannot exceed %d bytes", model.ContactMaxVCardLength))
+		}
+	}
+	return nil
+}
+
+// newContacts expands vCards into cards, normalizes every card and checks
+// the result. A single vCard may hold several cards.
+func newContacts(contacts []model.Contact) ([]model.Contact, error) {
+	var cards []model.Contact
+	for _, c := range contacts {
+		if strings.TrimSpace(c.VCard) == "" {
+			cards = append(cards, c)
+			continue
+		}
+		parsed := parseVCard(c.VCard)
+		if len(parsed) == 0 {
+			return nil, apperr.NewBadRequest("vcard contains no BEGIN:VCARD ... END:VCARD block")
+		}
+		cards = append(cards, parsed...)
+	}
+	if len(cards) > model.ContactMaxCards {
+		return nil, apperr.NewBadRequest(fmt.Sprintf("contact message cannot have more than %d contacts", model.ContactMaxCards))
+	}
+
+	out := make([]model.Contact, 0, len(cards))
+	for _, c := range cards {
+		n, err := normalizeContact(c)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+// normalizeContact trims and deduplicates a card's fields, drops phone
+// numbers and emails that cannot be used, and regenerates its vCard.
+func normalizeContact(c model.Contact) (model.Contact, error) {
+	n := model.Contact{
+		Name:         strings.TrimSpace(c.Name),
+		Organization: strings.TrimSpace(c.Organization),
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range c.Phones {
+		number := normalizePhone(p.Number)
+		if number == "" || seen[number] {
+			continue
+		}
+		seen[number] = true
+		n.Phones = append(n.Phones, model.ContactPhone{Number: number, Type: normalizePhoneType(p.Type)})
+	}
+	for _, e := range c.Emails {
+		addr, err := mail.ParseAddress(strings.TrimSpace(e))
+		if err != nil {
+			continue
+		}
+		email := strings.ToLower(addr.Address)
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+		n.Emails = append(n.Emails, email)
+	}
+
+	if n.Name == "" {
+		switch {
+		case len(n.Phones) > 0:
+			n.Name = n.Phones[0].Number
+		case len(n.Emails) > 0:
+			n.Name = n.Emails[0]
+		}
+	}
+	if n.Name == "" {
+		return model.Contact{}, apperr.NewBadRequest("each contact requires a name, phone number or email")
+	}
+	if utf8.RuneCountInString(n.Name) > model.ContactMaxNameLength {
+		return model.Contact{}, apperr.NewBadRequest(fmt.Sprintf("contact name cannot exceed %d characters", model.ContactMaxNameLength))
+	}
+	if utf8.RuneCountInString(n.Organization) > model.ContactMaxNameLength {
+		return model.Contact{}, apperr.NewBadRequest(fmt.Sprintf("contact organization cannot exceed %d characters", model.ContactMaxNameLength))
+	}
+	if len(n.Phones) > model.ContactMaxPhones {
+		return model.Contact{}, apperr.NewBadRequest(fmt.Sprintf("a contact cannot have more than %d phone numbers", model.ContactMaxPhones))
+	}
+	if len(n.Emails) > model.ContactMaxEmails {
+		return model.Contact{}, apperr.NewBadRequest(fmt.Sprintf("a contact cannot have more than %d emails", model.ContactMaxEmails))
+	}
+
+	n.VCard = formatVCard(n)
+	return n, nil
+}
+
+// normalizePhone strips formatting from a phone number. Numbers with an
+// international prefix ("+" or "00") become E.164; numbers without one are
+// kept as bare digits, since there is no region to resolve them against.
+// Extensions are dropped. It returns "" for anything unusable.
+func normalizePhone(raw string) string {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if i := strings.Index(raw, "ext"); i >= 0 {
+		raw = raw[:i]
+	}
+	if i := strings.IndexAny(raw, ";,wx"); i >= 0 {
+		raw = raw[:i]
+	}
+
+	international := strings.HasPrefix(raw, "+")
+	var digits strings.Builder
+	for _, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')' || r == '/' || r == '+':
+		default:
+			return ""
+		}
+	}
+	d := digits.String()
+	if !international && strings.HasPrefix(d, "00") {
+		international = true
+		d = d[2:]
+	}
+	if international {
+		if e164Regex.MatchString("+" + d) {
+			return "+" + d
+		}
+		return ""
+	}
+	if len(d) < 3 || len(d) > 15 {
+		return ""
+	}
+	return d
+}
+
+// normalizePhoneType maps a phone label, either one of the stored labels or
+// a vCard TYPE such as CELL, to a stored label. Unknown labels become "other".
+func normalizePhoneType(t string) string {
+	t = strings.TrimSpace(t)
+	if t == "" {
+		return ""
+	}
+	for _, pt := range vcardPhoneTypes {
+		if strings.EqualFold(t, pt.label) || strings.EqualFold(t, pt.param) {
+			return pt.label
+		}
+	}
+	return "other"
+}
+
+// resolveContactUsers marks the phone numbers that belong to registered
+// users. Lookup failures are logged and the contacts are sent unresolved.
+func (s *messageServiceImpl) resolveContactUsers(ctx context.Context, contacts []model.Contact) {
+	var phones []string
+	for _, c := range contacts {
+		for _, p := range c.Phones {
+			if strings.HasPrefix(p.Number, "+") {
+				phones = append(phones, p.Number)
+			}
+		}
+	}
+	if len(phones) == 0 {
+		return
+	}
+
+	resp, err := s.userClient.GetUsersByPhones(ctx, &userv1.GetUsersByPhonesRequest{Phones: phones})
+	if err != nil {
+		s.log.Warn().Err(err).Msg("failed to resolve contact phone numbers")
+		return
+	}
+	byPhone := make(map[string]string, len(resp.Users))
+	for _, u := range resp.Users {
+		byPhone[u.Phone] = u.UserId
+	}
+
+	for i := range contacts {
+		c := &contacts[i]
+		for j := range c.Phones {
+			if uid, ok := byPhone[c.Phones[j].Number]; ok {
+				c.Phones[j].UserID = uid
+				if c.UserID == "" {
+					c.UserID = uid
+				}
+			}
+		}
+	}
+}
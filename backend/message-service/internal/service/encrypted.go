@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	chatv1 "github.com/whatsapp-clone/backend/proto/chat/v1"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+)
+
+// validateEncrypted checks an encrypted message's envelopes. Everything but
+// the envelopes must be empty, so no plaintext is stored alongside them.
+func validateEncrypted(payload model.MessagePayload) error {
+	enc := payload.Encrypted
+	if enc == nil {
+		return apperr.NewBadRequest("encrypted message requires encrypted payload")
+	}
+	if payload.Body != "" || payload.Caption != "" || payload.MediaID != "" || payload.Filename != "" ||
+		payload.Poll != nil || payload.Location != nil || len(payload.Contacts) > 0 {
+		return apperr.NewBadRequest("encrypted message cannot carry plaintext fields")
+	}
+	if enc.SenderDeviceID == "" {
+		return apperr.NewBadRequest("encrypted message requires sender_device_id")
+	}
+	if len(enc.Envelopes) == 0 {
+		return apperr.NewBadRequest("encrypted message requires at least one envelope")
+	}
+	if len(enc.Envelopes) > model.EncryptedMaxEnvelopes {
+		return apperr.NewBadRequest(fmt.Sprintf("encrypted message cannot have more than %d envelopes", model.EncryptedMaxEnvelopes))
+	}
+
+	seen := make(map[[2]string]bool, len(enc.Envelopes))
+	for _, env := range enc.Envelopes {
+		if env.UserID == "" || env.DeviceID == "" {
+			return apperr.NewBadRequest("each envelope requires user_id and device_id")
+		}
+		key := [2]string{env.UserID, env.DeviceID}
+		if seen[key] {
+			return apperr.NewBadRequest("duplicate envelope for device " + env.DeviceID)
+		}
+		seen[key] = true
+		if env.Type != model.EnvelopeTypePreKey && env.Type != model.EnvelopeTypeMessage {
+			return apperr.NewBadRequest("envelope type must be 'prekey' or 'message'")
+		}
+		if len(env.Ciphertext) == 0 || len(env.Ciphertext) > model.EncryptedMaxCiphertextLen {
+			return apperr.NewBadRequest(fmt.Sprintf("envelope ciphertext must be 1-%d bytes", model.EncryptedMaxCiphertextLen))
+		}
+	}
+	return nil
+}
+
+// checkEnvelopeRecipients rejects envelopes addressed to users outside the
+// chat, which would otherwise leak ciphertext to them on delivery.
+func (s *messageServiceImpl) checkEnvelopeRecipients(ctx context.Context, chatID string, enc *model.EncryptedPayload) error {
+	resp, err := s.chatClient.GetChatParticipants(ctx, &chatv1.GetChatParticipantsRequest{ChatId: chatID})
+	if err != nil {
+		return apperr.NewInternal("failed to get chat participants", err)
+	}
+	members := make(map[string]bool, len(resp.UserIds))
+	for _, uid := range resp.UserIds {
+		members[uid] = true
+	}
+	for _, env := range enc.Envelopes {
+		if !members[env.UserID] {
+			return apperr.NewBadRequest("envelope addressed to a user who is not in this chat")
+		}
+	}
+	return nil
+}
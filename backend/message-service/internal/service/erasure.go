@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+)
+
+// EraseUser anonymizes a deleted account's messages. Messages stay in their
+// chats so conversations keep making sense to the other participants; only
+// the link to the account is removed. Its poll votes move to a pseudonym
+// derived from the deletion, distinct for every deleted account.
+func (s *messageServiceImpl) EraseUser(ctx context.Context, userID, deletionID string) error {
+	return s.messageRepo.AnonymizeUser(ctx, userID, "deleted-"+deletionID)
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+)
+
+const exportMaxPage = 500
+
+// ExportMessages returns one page of a chat's history, oldest first, as
+// userID sees it, and whether more messages follow. It backs chat and
+// personal data exports; callers check membership themselves.
+func (s *messageServiceImpl) ExportMessages(ctx context.Context, chatID, userID string, afterTime *time.Time, afterID string, limit int) ([]*model.Message, bool, error) {
+	if chatID == "" || userID == "" {
+		return nil, false, apperr.NewBadRequest("chat_id and user_id are required")
+	}
+	if limit <= 0 || limit > exportMaxPage {
+		limit = exportMaxPage
+	}
+
+	msgs, err := s.messageRepo.ListForExport(ctx, chatID, userID, afterTime, afterID, limit+1)
+	if err != nil {
+		return nil, false, apperr.NewInternal("failed to list messages", err)
+	}
+	hasMore := len(msgs) > limit
+	if hasMore {
+		msgs = msgs[:limit]
+	}
+	if err := s.applyReceipts(ctx, chatID, userID, msgs); err != nil {
+		return nil, false, err
+	}
+	return msgs, hasMore, nil
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+)
+
+func validateGroupInvite(g *model.GroupInvite) error {
+	if g == nil {
+		return apperr.NewBadRequest("group_invite message requires a group_invite")
+	}
+	if g.InvitationID == "" || g.GroupChatID == "" {
+		return apperr.NewBadRequest("group_invite requires invitation_id and group_chat_id")
+	}
+	if g.ExpiresAt.IsZero() {
+		return apperr.NewBadRequest("group_invite requires expires_at")
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/repository"
+)
+
+// liveLocationBatchSize caps how many shares one sweep pass ends.
+const liveLocationBatchSize = 500
+
+// LiveLocationExpirer runs a periodic job that ends live location shares
+// whose live_until has passed and tells participants they stopped.
+type LiveLocationExpirer struct {
+	repo      repository.MessageRepository
+	publisher *EventPublisher
+	interval  time.Duration
+	log       zerolog.Logger
+	stopCh    chan struct{}
+}
+
+// NewLiveLocationExpirer creates a new expirer with the given check interval.
+func NewLiveLocationExpirer(repo repository.MessageRepository, pub *EventPublisher, interval time.Duration, log zerolog.Logger) *LiveLocationExpirer {
+	return &LiveLocationExpirer{
+		repo:      repo,
+		publisher: pub,
+		interval:  interval,
+		log:       log.With().Str("component", "live-location-expirer").Logger(),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start begins the sweep loop in a goroutine.
+func (e *LiveLocationExpirer) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		e.log.Info().Dur("interval", e.interval).Msg("live location expirer started")
+
+		for {
+			select {
+			case <-ticker.C:
+				e.runSweep(ctx)
+			case <-e.stopCh:
+				e.log.Info().Msg("live location expirer stopped")
+				return
+			case <-ctx.Done():
+				e.log.Info().Msg("live location expirer context cancelled")
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the sweep loop to exit.
+func (e *LiveLocationExpirer) Stop() {
+	close(e.stopCh)
+}
+
+func (e *LiveLocationExpirer) runSweep(ctx context.Context) {
+	for {
+		ended, err := e.repo.EndExpiredLiveLocations(ctx, time.Now(), liveLocationBatchSize)
+		if err != nil {
+			e.log.Error().Err(err).Msg("failed to end expired live locations")
+			return
+		}
+		for _, msg := range ended {
+			if err := e.publisher.PublishLiveLocationStopped(ctx, msg, "expired"); err != nil {
+				e.log.Error().Err(err).Str("message_id", msg.MessageID).Msg("failed to publish msg.location.stopped event")
+			}
+		}
+		if len(ended) < liveLocationBatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+)
+
+// validateCoordinates checks a latitude/longitude pair and accuracy radius.
+func validateCoordinates(lat, lng, accuracy float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return apperr.NewBadRequest("latitude must be between -90 and 90")
+	}
+	if math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return apperr.NewBadRequest("longitude must be between -180 and 180")
+	}
+	if math.IsNaN(accuracy) || accuracy < 0 {
+		return apperr.NewBadRequest("accuracy_m cannot be negative")
+	}
+	return nil
+}
+
+// validateLocation checks a location message's payload, including the
+// requested duration of a live share.
+func validateLocation(l *model.Location) error {
+	if l == nil {
+		return apperr.NewBadRequest("location message requires a location")
+	}
+	if err := validateCoordinates(l.Latitude, l.Longitude, l.AccuracyM); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(l.Name) > model.LocationMaxNameLength {
+		return apperr.NewBadRequest(fmt.Sprintf("location name cannot exceed %d characters", model.LocationMaxNameLength))
+	}
+	if utf8.RuneCountInString(l.Address) > model.LocationMaxAddressLength {
+		return apperr.NewBadRequest(fmt.Sprintf("location address cannot exceed %d characters", model.LocationMaxAddressLength))
+	}
+	if l.LiveDurationSeconds != 0 {
+		d := time.Duration(l.LiveDurationSeconds) * time.Second
+		if d < model.LiveLocationMinDuration || d > model.LiveLocationMaxDuration {
+			return apperr.NewBadRequest(fmt.Sprintf("live_duration_seconds must be between %d and %d",
+				int64(model.LiveLocationMinDuration.Seconds()), int64(model.LiveLocationMaxDuration.Seconds())))
+		}
+	}
+	return nil
+}
+
+// newLocation returns the stored form of a validated location. A requested
+// live duration becomes LiveUntil; forwarded live locations are sent as a
+// static snapshot of their last position.
+func newLocation(l *model.Location, now time.Time, forwarded bool) *model.Location {
+	loc := &model.Location{
+		Latitude:  l.Latitude,
+		Longitude: l.Longitude,
+		AccuracyM: l.AccuracyM,
+		Name:      l.Name,
+		Address:   l.Address,
+	}
+	if l.LiveDurationSeconds > 0 && !forwarded {
+		until := now.Add(time.Duration(l.LiveDurationSeconds) * time.Second)
+		loc.LiveUntil = &until
+		loc.UpdatedAt = &now
+	}
+	return loc
+}
+
+// UpdateLiveLocation records the sender's latest position on an active live
+// location share. Fan-out to participants is left to the caller, since
+// positions are transient and not worth a durable event.
+func (s *messageServiceImpl) UpdateLiveLocation(ctx context.Context, messageID, userID string, lat, lng, accuracy float64) (*model.Message, error) {
+	if err := validateCoordinates(lat, lng, accuracy); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	updated, err := s.messageRepo.UpdateLiveLocation(ctx, messageID, userID, lat, lng, accuracy, now)
+	if err == nil {
+		return updated, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, apperr.NewInternal("failed to update live location", err)
+	}
+
+	// Work out why the update matched nothing.
+	msg, getErr := s.messageRepo.GetByID(ctx, messageID)
+	if getErr != nil {
+		return nil, apperr.NewInternal("failed to get message", getErr)
+	}
+	switch {
+	case msg == nil || msg.IsDeleted:
+		return nil, apperr.NewNotFound("message not found")
+	case msg.Type != model.MessageTypeLocation || msg.Payload.Location == nil || msg.Payload.Location.LiveUntil == nil:
+		return nil, apperr.NewBadRequest("message is not a live location")
+	case msg.SenderID != userID:
+		return nil, apperr.NewForbidden("only the sender can update a live location")
+	default:
+		return nil, apperr.NewConflict("live location sharing has ended")
+	}
+}
+
+// StopLiveLocation ends the sender's live location share early.
+func (s *messageServiceImpl) StopLiveLocation(ctx context.Context, messageID, userID string) (*model.Message, error) {
+	msg, err := s.messageRepo.GetByID(ctx, messageID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get message", err)
+	}
+	if msg == nil || msg.IsDeleted {
+		return nil, apperr.NewNotFound("message not found")
+	}
+	loc := msg.Payload.Location
+	if msg.Type != model.MessageTypeLocation || loc == nil || loc.LiveUntil == nil {
+		return nil, apperr.NewBadRequest("message is not a live location")
+	}
+	if msg.SenderID != userID {
+		return nil, apperr.NewForbidden("only the sender can stop a live location")
+	}
+	if !loc.IsLive(time.Now()) {
+		s.withReceipts(ctx, userID, msg)
+		return msg, nil
+	}
+
+	updated, err := s.messageRepo.StopLiveLocation(ctx, messageID, time.Now())
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// Expired or stopped concurrently.
+			msg, err := s.GetMessageByID(ctx, messageID)
+			if err != nil {
+				return nil, err
+			}
+			s.withReceipts(ctx, userID, msg)
+			return msg, nil
+		}
+		return nil, apperr.NewInternal("failed to stop live location", err)
+	}
+
+	if pubErr := s.publisher.PublishLiveLocationStopped(ctx, updated, "stopped"); pubErr != nil {
+		s.log.Error().Err(pubErr).Str("message_id", messageID).Msg("failed to publish msg.location.stopped event")
+	}
+	s.withReceipts(ctx, userID, updated)
+	return updated, nil
+}
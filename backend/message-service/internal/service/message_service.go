@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/whatsapp-clone/backend/message-service/internal/model"
 )
@@ -9,17 +10,27 @@ import (
 type MessageService interface {
 	SendMessage(ctx context.Context, senderID string, req *model.SendMessageRequest) (*model.Message, error)
 	GetMessages(ctx context.Context, query *model.ListMessagesQuery) ([]*model.Message, error)
+	GetThread(ctx context.Context, query *model.ThreadQuery) (*model.Message, []*model.Message, error)
 	GetMessageByID(ctx context.Context, messageID string) (*model.Message, error)
+	GetMessageReceipts(ctx context.Context, messageID, userID string) (map[string]model.RecipientStatus, error)
 	UpdateStatus(ctx context.Context, messageID, userID, status string) error
+	MarkChatRead(ctx context.Context, chatID, userID, messageID string) error
+	EditMessage(ctx context.Context, messageID, senderID string, req *model.EditMessageRequest) (*model.Message, error)
 	DeleteMessage(ctx context.Context, messageID, senderID string) error
 	SoftDeleteForUser(ctx context.Context, messageID, userID string) error
 	StarMessage(ctx context.Context, messageID, userID string) error
 	UnstarMessage(ctx context.Context, messageID, userID string) error
 	ReactToMessage(ctx context.Context, messageID, userID, emoji string) error
 	RemoveReaction(ctx context.Context, messageID, userID string) error
+	VotePoll(ctx context.Context, messageID, userID string, optionIDs []string) (*model.Message, error)
+	ClosePoll(ctx context.Context, messageID, userID string) (*model.Message, error)
+	UpdateLiveLocation(ctx context.Context, messageID, userID string, lat, lng, accuracy float64) (*model.Message, error)
+	StopLiveLocation(ctx context.Context, messageID, userID string) (*model.Message, error)
 	ForwardMessage(ctx context.Context, senderID, targetChatID, sourceMessageID string) (*model.Message, error)
-	SearchMessages(ctx context.Context, chatID, userID, query string, limit int) ([]*model.Message, error)
-	SearchGlobal(ctx context.Context, userID, query string, chatIDs []string, limit int) ([]*model.Message, error)
+	SearchMessages(ctx context.Context, query *model.SearchQuery) (*model.SearchPage, error)
+	SearchGlobal(ctx context.Context, query *model.SearchQuery) (*model.SearchPage, error)
 	GetLastMessages(ctx context.Context, chatIDs []string) (map[string]*model.Message, error)
 	GetUnreadCounts(ctx context.Context, userID string, chatIDs []string) (map[string]int64, error)
+	ExportMessages(ctx context.Context, chatID, userID string, afterTime *time.Time, afterID string, limit int) ([]*model.Message, bool, error)
+	EraseUser(ctx context.Context, userID, deletionID string) error
 }
@@ -22,15 +22,17 @@ type messageServiceImpl struct {
 	publisher   *EventPublisher
 	userClient  userv1.UserServiceClient
 	chatClient  chatv1.ChatServiceClient
+	editWindow  time.Duration
 	log         zerolog.Logger
 }
 
-func NewMessageService(repo repository.MessageRepository, pub *EventPublisher, userClient userv1.UserServiceClient, chatClient chatv1.ChatServiceClient, log zerolog.Logger) MessageService {
+func NewMessageService(repo repository.MessageRepository, pub *EventPublisher, userClient userv1.UserServiceClient, chatClient chatv1.ChatServiceClient, editWindow time.Duration, log zerolog.Logger) MessageService {
 	return &messageServiceImpl{
 		messageRepo: repo,
 		publisher:   pub,
 		userClient:  userClient,
 		chatClient:  chatClient,
+		editWindow:  editWindow,
 		log:         log,
 	}
 }
@@ -51,15 +53,53 @@ func (s *messageServiceImpl) SendMessage(ctx context.Context, senderID string, r
 		if permResp.IsAdminOnly && !permResp.IsAdmin {
 			return nil, apperr.NewForbidden("only admins can send messages in this chat")
 		}
+		if permResp.PeerUserId != "" {
+			if err := s.checkNotBlocked(ctx, senderID, permResp.PeerUserId); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if err := validateMessagePayload(req.Type, req.Payload); err != nil {
 		return nil, err
 	}
+	if req.Type == model.MessageTypePoll {
+		req.Payload.Poll = newPoll(req.Payload.Poll)
+	}
+	if req.Type == model.MessageTypeContact {
+		if req.Payload.Contacts, err = newContacts(req.Payload.Contacts); err != nil {
+			return nil, err
+		}
+		s.resolveContactUsers(ctx, req.Payload.Contacts)
+	}
+	if req.Type == model.MessageTypeEncrypted {
+		if err := s.checkEnvelopeRecipients(ctx, req.ChatID, req.Payload.Encrypted); err != nil {
+			return nil, err
+		}
+	}
 
 	now := time.Now()
+	if req.Type == model.MessageTypeLocation {
+		req.Payload.Location = newLocation(req.Payload.Location, now, req.ForwardedFrom != nil)
+	}
+
+	var threadID string
+	if req.ReplyToMessageID != "" {
+		threadID, err = s.threadRootFor(ctx, req.ChatID, req.ReplyToMessageID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	msgID := uuid.New().String()
 
+	// Disappearing messages: the chat's timer at send time fixes the expiry.
+	var expiresAt *time.Time
+	if secs := permResp.GetAutoDeleteSeconds(); secs > 0 {
+		t := now.Add(time.Duration(secs) * time.Second)
+		expiresAt = &t
+	}
+
 	msg := &model.Message{
 		MessageID:        msgID,
 		ChatID:           req.ChatID,
@@ -67,11 +107,12 @@ func (s *messageServiceImpl) SendMessage(ctx context.Context, senderID string, r
 		ClientMsgID:      req.ClientMsgID,
 		Type:             req.Type,
 		ReplyToMessageID: req.ReplyToMessageID,
+		ThreadID:         threadID,
 		ForwardedFrom:    req.ForwardedFrom,
 		Payload:          req.Payload,
-		Status:           make(map[string]model.RecipientStatus),
 		IsDeleted:        false,
 		IsStarredBy:      []string{},
+		ExpiresAt:        expiresAt,
 		CreatedAt:        now,
 		UpdatedAt:        now,
 	}
@@ -85,10 +126,17 @@ func (s *messageServiceImpl) SendMessage(ctx context.Context, senderID string, r
 		s.log.Error().Err(pubErr).Str("message_id", result.MessageID).Msg("failed to publish msg.new event")
 	}
 
+	// Count the reply only when this call inserted it, not on a client_msg_id retry.
+	if result.ThreadID != "" && result.MessageID == msgID {
+		adjustReplyCount(ctx, s.messageRepo, s.publisher, s.log, result.ThreadID, 1, &result.CreatedAt)
+	}
+
+	s.withReceipts(ctx, senderID, result)
 	return result, nil
 }
 
-// GetMessages returns messages for a chat with cursor-based pagination.
+// GetMessages returns messages for a chat with cursor-based pagination, or an
+// exact seq range when after_seq/before_seq is set.
 func (s *messageServiceImpl) GetMessages(ctx context.Context, query *model.ListMessagesQuery) ([]*model.Message, error) {
 	if query.UserID != "" {
 		permResp, err := s.chatClient.CheckChatPermission(ctx, &chatv1.CheckChatPermissionRequest{
@@ -108,18 +156,34 @@ func (s *messageServiceImpl) GetMessages(ctx context.Context, query *model.ListM
 		limit = 50
 	}
 
-	var cursorTime *time.Time
-	if query.Cursor != "" {
-		t, err := time.Parse(time.RFC3339Nano, query.Cursor)
+	var msgs []*model.Message
+	if query.BySeq() {
+		if query.AfterSeq < 0 || query.BeforeSeq < 0 {
+			return nil, apperr.NewBadRequest("after_seq and before_seq must be positive")
+		}
+		if query.AfterSeq > 0 && query.BeforeSeq > 0 && query.BeforeSeq <= query.AfterSeq+1 {
+			return nil, apperr.NewBadRequest("before_seq must be greater than after_seq + 1")
+		}
+		var err error
+		msgs, err = s.messageRepo.ListBySeq(ctx, query.ChatID, query.AfterSeq, query.BeforeSeq, limit)
 		if err != nil {
-			return nil, apperr.NewBadRequest("invalid cursor format, expected RFC3339Nano")
+			return nil, apperr.NewInternal("failed to list messages", err)
+		}
+	} else {
+		var cursorTime *time.Time
+		if query.Cursor != "" {
+			t, err := time.Parse(time.RFC3339Nano, query.Cursor)
+			if err != nil {
+				return nil, apperr.NewBadRequest("invalid cursor format, expected RFC3339Nano")
+			}
+			cursorTime = &t
 		}
-		cursorTime = &t
-	}
 
-	msgs, err := s.messageRepo.ListByChatID(ctx, query.ChatID, cursorTime, query.CursorID, limit)
-	if err != nil {
-		return nil, apperr.NewInternal("failed to list messages", err)
+		var err error
+		msgs, err = s.messageRepo.ListByChatID(ctx, query.ChatID, cursorTime, query.CursorID, limit)
+		if err != nil {
+			return nil, apperr.NewInternal("failed to list messages", err)
+		}
 	}
 
 	// Populate reply previews
@@ -141,56 +205,88 @@ func (s *messageServiceImpl) GetMessages(ctx context.Context, query *model.ListM
 		}
 	}
 
+	if query.UserID != "" {
+		if err := s.applyReceipts(ctx, query.ChatID, query.UserID, msgs); err != nil {
+			return nil, err
+		}
+	}
 	return msgs, nil
 }
 
-// UpdateStatus validates the status transition, updates the repo, and publishes an event.
+// UpdateStatus moves the user's delivered or read watermark in the message's
+// chat up to the message; see advanceWatermark.
 func (s *messageServiceImpl) UpdateStatus(ctx context.Context, messageID, userID, status string) error {
 	msgStatus := model.MessageStatus(status)
 	if msgStatus != model.StatusDelivered && msgStatus != model.StatusRead {
 		return apperr.NewBadRequest("invalid status, must be 'delivered' or 'read'")
 	}
 
-	recipientStatus := model.RecipientStatus{
-		Status:    msgStatus,
-		UpdatedAt: time.Now(),
+	msg, err := s.messageRepo.GetByID(ctx, messageID)
+	if err != nil {
+		return apperr.NewInternal("failed to get message", err)
 	}
+	if msg == nil {
+		return apperr.NewNotFound("message not found")
+	}
+	return s.advanceWatermark(ctx, msg, userID, msgStatus)
+}
 
-	err := s.messageRepo.UpdateStatus(ctx, messageID, userID, recipientStatus)
+// EditMessage lets the sender replace a text body or media caption within the
+// edit window. The previous version is kept in the message's revisions.
+func (s *messageServiceImpl) EditMessage(ctx context.Context, messageID, senderID string, req *model.EditMessageRequest) (*model.Message, error) {
+	msg, err := s.messageRepo.GetByID(ctx, messageID)
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return apperr.NewNotFound("message not found")
-		}
-		return apperr.NewInternal("failed to update status", err)
+		return nil, apperr.NewInternal("failed to get message", err)
+	}
+	if msg == nil || msg.IsDeleted {
+		return nil, apperr.NewNotFound("message not found")
+	}
+	if msg.SenderID != senderID {
+		return nil, apperr.NewForbidden("only the sender can edit a message")
+	}
+	if msg.ForwardedFrom != nil {
+		return nil, apperr.NewForbidden("forwarded messages cannot be edited")
+	}
+	if time.Since(msg.CreatedAt) > s.editWindow {
+		return nil, apperr.NewForbidden("edit window has expired")
+	}
+	if err := s.checkChatNotBlocked(ctx, senderID, msg.ChatID); err != nil {
+		return nil, err
 	}
 
-	// P4-09: Check reading user's read_receipts privacy setting.
-	if msgStatus == model.StatusRead {
-		privacyResp, err := s.userClient.GetPrivacySettings(ctx, &userv1.GetPrivacySettingsRequest{
-			UserId: userID,
-		})
-		if err != nil {
-			s.log.Warn().Err(err).Str("user_id", userID).Msg("failed to check read receipt privacy, publishing anyway")
-		} else if !privacyResp.ReadReceipts {
-			s.log.Debug().Str("user_id", userID).Msg("user has read receipts disabled, skipping publish")
-			return nil
+	payload := msg.Payload
+	switch msg.Type {
+	case model.MessageTypeText:
+		if req.Body == "" {
+			return nil, apperr.NewBadRequest("text message requires a non-empty body")
 		}
+		payload.Body = req.Body
+	case model.MessageTypeImage, model.MessageTypeVideo, model.MessageTypeDocument:
+		payload.Caption = req.Caption
+	default:
+		return nil, apperr.NewBadRequest(string(msg.Type) + " messages cannot be edited")
 	}
 
-	// Fetch the message to get sender_id and chat_id for routing the status update
-	msg, msgErr := s.messageRepo.GetByID(ctx, messageID)
-	senderID := ""
-	chatID := ""
-	if msgErr == nil && msg != nil {
-		senderID = msg.SenderID
-		chatID = msg.ChatID
+	// Retried or no-op edits return the message unchanged without a revision.
+	if payload.Body == msg.Payload.Body && payload.Caption == msg.Payload.Caption {
+		s.withReceipts(ctx, senderID, msg)
+		return msg, nil
 	}
 
-	if pubErr := s.publisher.PublishStatusUpdate(ctx, messageID, chatID, userID, status, senderID); pubErr != nil {
-		s.log.Error().Err(pubErr).Str("message_id", messageID).Msg("failed to publish msg.status.updated event")
+	updated, err := s.messageRepo.EditMessage(ctx, messageID, senderID, payload, time.Now())
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperr.NewNotFound("message not found")
+		}
+		return nil, apperr.NewInternal("failed to edit message", err)
 	}
 
-	return nil
+	if pubErr := s.publisher.PublishMessageEdited(ctx, updated); pubErr != nil {
+		s.log.Error().Err(pubErr).Str("message_id", messageID).Msg("failed to publish msg.edited event")
+	}
+
+	s.withReceipts(ctx, senderID, updated)
+	return updated, nil
 }
 
 // DeleteMessage verifies sender ownership, soft-deletes, and publishes a delete event.
@@ -214,6 +310,10 @@ func (s *messageServiceImpl) DeleteMessage(ctx context.Context, messageID, sende
 		s.log.Error().Err(pubErr).Str("message_id", messageID).Msg("failed to publish msg.deleted event")
 	}
 
+	if msg.ThreadID != "" && !msg.IsDeleted {
+		adjustReplyCount(ctx, s.messageRepo, s.publisher, s.log, msg.ThreadID, -1, nil)
+	}
+
 	return nil
 }
 
@@ -242,6 +342,9 @@ func (s *messageServiceImpl) ForwardMessage(ctx context.Context, senderID, targe
 	if original == nil {
 		return nil, apperr.NewNotFound("source message not found")
 	}
+	if original.Type == model.MessageTypeGroupInvite {
+		return nil, apperr.NewBadRequest("group invitations cannot be forwarded")
+	}
 
 	forwardReq := &model.SendMessageRequest{
 		ChatID:      targetChatID,
@@ -302,7 +405,18 @@ func (s *messageServiceImpl) ReactToMessage(ctx context.Context, messageID, user
 	if len(emoji) > 32 {
 		return apperr.NewBadRequest("invalid emoji: too long")
 	}
-	err := s.messageRepo.AddReaction(ctx, messageID, userID, emoji)
+	msg, err := s.messageRepo.GetByID(ctx, messageID)
+	if err != nil {
+		return apperr.NewInternal("failed to get message", err)
+	}
+	if msg == nil || msg.IsDeleted {
+		return apperr.NewNotFound("message not found")
+	}
+	if err := s.checkChatNotBlocked(ctx, userID, msg.ChatID); err != nil {
+		return err
+	}
+
+	err = s.messageRepo.AddReaction(ctx, messageID, userID, emoji)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return apperr.NewNotFound("message not found")
@@ -310,13 +424,7 @@ func (s *messageServiceImpl) ReactToMessage(ctx context.Context, messageID, user
 		return apperr.NewInternal("failed to add reaction", err)
 	}
 
-	msg, msgErr := s.messageRepo.GetByID(ctx, messageID)
-	chatID := ""
-	if msgErr == nil && msg != nil {
-		chatID = msg.ChatID
-	}
-
-	if pubErr := s.publisher.PublishReaction(ctx, messageID, chatID, userID, emoji, false); pubErr != nil {
+	if pubErr := s.publisher.PublishReaction(ctx, messageID, msg.ChatID, userID, emoji, false); pubErr != nil {
 		s.log.Error().Err(pubErr).Str("message_id", messageID).Msg("failed to publish msg.reaction event")
 	}
 
@@ -346,43 +454,6 @@ func (s *messageServiceImpl) RemoveReaction(ctx context.Context, messageID, user
 	return nil
 }
 
-// SearchMessages delegates full-text search to the repository after verifying membership.
-func (s *messageServiceImpl) SearchMessages(ctx context.Context, chatID, userID, query string, limit int) ([]*model.Message, error) {
-	if userID != "" {
-		permResp, err := s.chatClient.CheckChatPermission(ctx, &chatv1.CheckChatPermissionRequest{
-			ChatId: chatID,
-			UserId: userID,
-		})
-		if err != nil {
-			return nil, apperr.NewInternal("failed to verify chat membership", err)
-		}
-		if !permResp.IsMember {
-			return nil, apperr.NewForbidden("not a member of this chat")
-		}
-	}
-
-	msgs, err := s.messageRepo.Search(ctx, chatID, query, limit)
-	if err != nil {
-		return nil, apperr.NewInternal("failed to search messages", err)
-	}
-	return msgs, nil
-}
-
-// SearchGlobal searches messages across multiple chats.
-// NOTE: In a full implementation, this would call chat-service gRPC to resolve
-// the user's chat IDs. For now, chatIDs must be provided by the caller (HTTP handler / gateway).
-func (s *messageServiceImpl) SearchGlobal(ctx context.Context, userID, query string, chatIDs []string, limit int) ([]*model.Message, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 20
-	}
-
-	msgs, err := s.messageRepo.SearchGlobal(ctx, chatIDs, query, limit)
-	if err != nil {
-		return nil, apperr.NewInternal("failed to search messages globally", err)
-	}
-	return msgs, nil
-}
-
 // GetLastMessages delegates to the repository aggregation.
 func (s *messageServiceImpl) GetLastMessages(ctx context.Context, chatIDs []string) (map[string]*model.Message, error) {
 	msgs, err := s.messageRepo.GetLastPerChat(ctx, chatIDs)
@@ -392,7 +463,7 @@ func (s *messageServiceImpl) GetLastMessages(ctx context.Context, chatIDs []stri
 	return msgs, nil
 }
 
-// GetUnreadCounts delegates to the repository aggregation.
+// GetUnreadCounts reads the counters kept on the user's watermarks.
 func (s *messageServiceImpl) GetUnreadCounts(ctx context.Context, userID string, chatIDs []string) (map[string]int64, error) {
 	counts, err := s.messageRepo.CountUnread(ctx, userID, chatIDs)
 	if err != nil {
@@ -413,9 +484,15 @@ func validateMessagePayload(msgType model.MessageType, payload model.MessagePayl
 			return apperr.NewBadRequest(string(msgType) + " message requires media_id")
 		}
 	case model.MessageTypeLocation:
-		if payload.Body == "" {
-			return apperr.NewBadRequest("location message requires body with coordinates")
-		}
+		return validateLocation(payload.Location)
+	case model.MessageTypePoll:
+		return validatePoll(payload.Poll)
+	case model.MessageTypeContact:
+		return validateContacts(payload.Contacts)
+	case model.MessageTypeEncrypted:
+		return validateEncrypted(payload)
+	case model.MessageTypeGroupInvite:
+		return validateGroupInvite(payload.GroupInvite)
 	default:
 		return apperr.NewBadRequest("unsupported message type: " + string(msgType))
 	}
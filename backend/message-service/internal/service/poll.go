@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+	"github.com/whatsapp-clone/backend/message-service/internal/repository"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	chatv1 "github.com/whatsapp-clone/backend/proto/chat/v1"
+)
+
+func pollClosedError() error {
+	return apperr.Wrap(apperr.CodePollClosed, 409, "poll is closed", nil)
+}
+
+// validatePoll checks a new poll's question, options and close time.
+func validatePoll(p *model.Poll) error {
+	if p == nil {
+		return apperr.NewBadRequest("poll message requires a poll")
+	}
+	question := strings.TrimSpace(p.Question)
+	if question == "" {
+		return apperr.NewBadRequest("poll requires a question")
+	}
+	if utf8.RuneCountInString(question) > model.PollMaxQuestionLength {
+		return apperr.NewBadRequest(fmt.Sprintf("poll question cannot exceed %d characters", model.PollMaxQuestionLength))
+	}
+	if len(p.Options) < model.PollMinOptions || len(p.Options) > model.PollMaxOptions {
+		return apperr.NewBadRequest(fmt.Sprintf("poll must have between %d and %d options", model.PollMinOptions, model.PollMaxOptions))
+	}
+	seen := make(map[string]bool, len(p.Options))
+	for _, o := range p.Options {
+		text := strings.TrimSpace(o.Text)
+		if text == "" {
+			return apperr.NewBadRequest("poll options cannot be empty")
+		}
+		if utf8.RuneCountInString(text) > model.PollMaxOptionLength {
+			return apperr.NewBadRequest(fmt.Sprintf("poll options cannot exceed %d characters", model.PollMaxOptionLength))
+		}
+		key := strings.ToLower(text)
+		if seen[key] {
+			return apperr.NewBadRequest("poll options must be unique")
+		}
+		seen[key] = true
+	}
+	if p.ClosesAt != nil && !p.ClosesAt.After(time.Now()) {
+		return apperr.NewBadRequest("poll closes_at must be in the future")
+	}
+	return nil
+}
+
+// newPoll returns a fresh copy of a validated poll: trimmed text, server
+// assigned option IDs and no votes. Forwarded polls start over the same way.
+func newPoll(p *model.Poll) *model.Poll {
+	poll := &model.Poll{
+		Question:    strings.TrimSpace(p.Question),
+		Options:     make([]model.PollOption, 0, len(p.Options)),
+		MultiSelect: p.MultiSelect,
+		Anonymous:   p.Anonymous,
+		ClosesAt:    p.ClosesAt,
+	}
+	if poll.ClosesAt != nil && !poll.ClosesAt.After(time.Now()) {
+		poll.ClosesAt = nil
+	}
+	for i, o := range p.Options {
+		poll.Options = append(poll.Options, model.PollOption{
+			ID:   strconv.Itoa(i),
+			Text: strings.TrimSpace(o.Text),
+		})
+	}
+	return poll
+}
+
+// getPoll loads a live poll message and checks that userID is in its chat.
+// It also returns the other member if the chat is a direct chat.
+func (s *messageServiceImpl) getPoll(ctx context.Context, messageID, userID string) (*model.Message, string, error) {
+	msg, err := s.messageRepo.GetByID(ctx, messageID)
+	if err != nil {
+		return nil, "", apperr.NewInternal("failed to get message", err)
+	}
+	if msg == nil || msg.IsDeleted {
+		return nil, "", apperr.NewNotFound("message not found")
+	}
+	if msg.Type != model.MessageTypePoll || msg.Payload.Poll == nil {
+		return nil, "", apperr.NewBadRequest("message is not a poll")
+	}
+
+	permResp, err := s.chatClient.CheckChatPermission(ctx, &chatv1.CheckChatPermissionRequest{
+		ChatId: msg.ChatID,
+		UserId: userID,
+	})
+	if err != nil {
+		return nil, "", apperr.NewInternal("failed to verify chat membership", err)
+	}
+	if !permResp.IsMember {
+		return nil, "", apperr.NewForbidden("not a member of this chat")
+	}
+	return msg, permResp.PeerUserId, nil
+}
+
+// VotePoll replaces the user's vote on a poll; an empty optionIDs retracts it.
+// Tallies are adjusted atomically on the message document.
+func (s *messageServiceImpl) VotePoll(ctx context.Context, messageID, userID string, optionIDs []string) (*model.Message, error) {
+	msg, peerID, err := s.getPoll(ctx, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if peerID != "" {
+		if err := s.checkNotBlocked(ctx, userID, peerID); err != nil {
+			return nil, err
+		}
+	}
+	poll := msg.Payload.Poll
+	if poll.IsClosed(time.Now()) {
+		return nil, pollClosedError()
+	}
+
+	valid := make(map[string]bool, len(poll.Options))
+	for _, o := range poll.Options {
+		valid[o.ID] = true
+	}
+	choice := make([]string, 0, len(optionIDs))
+	picked := make(map[string]bool, len(optionIDs))
+	for _, id := range optionIDs {
+		if !valid[id] {
+			return nil, apperr.NewBadRequest("unknown poll option: " + id)
+		}
+		if !picked[id] {
+			picked[id] = true
+			choice = append(choice, id)
+		}
+	}
+	if !poll.MultiSelect && len(choice) > 1 {
+		return nil, apperr.NewBadRequest("this poll allows only one option")
+	}
+
+	updated, err := s.messageRepo.VotePoll(ctx, messageID, userID, choice, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrPollClosed):
+			return nil, pollClosedError()
+		case errors.Is(err, mongo.ErrNoDocuments):
+			return nil, apperr.NewNotFound("message not found")
+		case errors.Is(err, repository.ErrPollVoteConflict):
+			return nil, apperr.NewConflict("poll vote was changed concurrently, please retry")
+		}
+		return nil, apperr.NewInternal("failed to record vote", err)
+	}
+
+	if pubErr := s.publisher.PublishPollVoted(ctx, updated, userID, choice); pubErr != nil {
+		s.log.Error().Err(pubErr).Str("message_id", messageID).Msg("failed to publish msg.poll.voted event")
+	}
+	s.withReceipts(ctx, userID, updated)
+	return updated, nil
+}
+
+// ClosePoll lets the poll's creator stop further voting.
+func (s *messageServiceImpl) ClosePoll(ctx context.Context, messageID, userID string) (*model.Message, error) {
+	msg, _, err := s.getPoll(ctx, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if msg.SenderID != userID {
+		return nil, apperr.NewForbidden("only the poll creator can close it")
+	}
+	if msg.Payload.Poll.ClosedAt != nil {
+		s.withReceipts(ctx, userID, msg)
+		return msg, nil
+	}
+
+	updated, err := s.messageRepo.ClosePoll(ctx, messageID, time.Now())
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperr.NewNotFound("message not found")
+		}
+		return nil, apperr.NewInternal("failed to close poll", err)
+	}
+
+	if pubErr := s.publisher.PublishPollClosed(ctx, updated); pubErr != nil {
+		s.log.Error().Err(pubErr).Str("message_id", messageID).Msg("failed to publish msg.poll.closed event")
+	}
+	s.withReceipts(ctx, userID, updated)
+	return updated, nil
+}
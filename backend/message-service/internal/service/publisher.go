@@ -35,12 +35,15 @@ func (p *EventPublisher) EnsureStream() error {
 // PublishNewMessage publishes a msg.new event for real-time delivery.
 func (p *EventPublisher) PublishNewMessage(ctx context.Context, msg *model.Message) error {
 	data, err := json.Marshal(map[string]interface{}{
-		"message_id": msg.MessageID,
-		"chat_id":    msg.ChatID,
-		"sender_id":  msg.SenderID,
-		"type":       msg.Type,
-		"payload":    msg.Payload,
-		"created_at": msg.CreatedAt,
+		"message_id":          msg.MessageID,
+		"chat_id":             msg.ChatID,
+		"seq":                 msg.Seq,
+		"sender_id":           msg.SenderID,
+		"type":                msg.Type,
+		"reply_to_message_id": msg.ReplyToMessageID,
+		"thread_id":           msg.ThreadID,
+		"payload":             msg.Payload,
+		"created_at":          msg.CreatedAt,
 	})
 	if err != nil {
 		return err
@@ -49,14 +52,16 @@ func (p *EventPublisher) PublishNewMessage(ctx context.Context, msg *model.Messa
 	return err
 }
 
-// PublishStatusUpdate publishes a msg.status.updated event.
-func (p *EventPublisher) PublishStatusUpdate(ctx context.Context, msgID, chatID, userID, status, senderID string) error {
-	data, err := json.Marshal(map[string]string{
+// PublishStatusUpdate publishes a msg.status.updated event when userID's
+// delivered or read watermark in chatID moves: every message up to seq, the
+// one with msgID, now has that status for them.
+func (p *EventPublisher) PublishStatusUpdate(ctx context.Context, chatID, userID, status, msgID string, seq int64) error {
+	data, err := json.Marshal(map[string]interface{}{
 		"message_id": msgID,
 		"chat_id":    chatID,
 		"user_id":    userID,
 		"status":     status,
-		"sender_id":  senderID,
+		"seq":        seq,
 	})
 	if err != nil {
 		return err
@@ -81,6 +86,23 @@ func (p *EventPublisher) PublishReaction(ctx context.Context, messageID, chatID,
 	return err
 }
 
+// PublishMessageEdited publishes a msg.edited event with the new payload.
+func (p *EventPublisher) PublishMessageEdited(ctx context.Context, msg *model.Message) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"message_id": msg.MessageID,
+		"chat_id":    msg.ChatID,
+		"seq":        msg.Seq,
+		"sender_id":  msg.SenderID,
+		"payload":    msg.Payload,
+		"edited_at":  msg.EditedAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.js.Publish("msg.edited", data)
+	return err
+}
+
 // PublishMessageDeleted publishes a msg.deleted event.
 func (p *EventPublisher) PublishMessageDeleted(ctx context.Context, msgID, chatID, userID string, forEveryone bool) error {
 	data, err := json.Marshal(map[string]interface{}{
@@ -95,3 +117,98 @@ func (p *EventPublisher) PublishMessageDeleted(ctx context.Context, msgID, chatI
 	_, err = p.js.Publish("msg.deleted", data)
 	return err
 }
+
+// PublishMessagesExpired publishes a msg.expired event listing the messages
+// in one chat that were removed by the disappearing messages timer.
+func (p *EventPublisher) PublishMessagesExpired(ctx context.Context, chatID string, messageIDs []string) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"chat_id":     chatID,
+		"message_ids": messageIDs,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.js.Publish("msg.expired", data)
+	return err
+}
+
+// PublishThreadUpdated publishes a msg.thread.updated event with a thread
+// root's current reply count, so clients can keep reply counters live.
+func (p *EventPublisher) PublishThreadUpdated(ctx context.Context, root *model.Message) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"message_id":    root.MessageID,
+		"chat_id":       root.ChatID,
+		"reply_count":   root.ReplyCount,
+		"last_reply_at": root.LastReplyAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.js.Publish("msg.thread.updated", data)
+	return err
+}
+
+// pollTallies returns each option's current vote count.
+func pollTallies(poll *model.Poll) []map[string]interface{} {
+	tallies := make([]map[string]interface{}, 0, len(poll.Options))
+	for _, o := range poll.Options {
+		tallies = append(tallies, map[string]interface{}{"id": o.ID, "vote_count": o.VoteCount})
+	}
+	return tallies
+}
+
+// PublishPollVoted publishes a msg.poll.voted event with the poll's new
+// tallies. The voter and their choice are left out for anonymous polls.
+func (p *EventPublisher) PublishPollVoted(ctx context.Context, msg *model.Message, userID string, optionIDs []string) error {
+	poll := msg.Payload.Poll
+	event := map[string]interface{}{
+		"message_id":   msg.MessageID,
+		"chat_id":      msg.ChatID,
+		"options":      pollTallies(poll),
+		"total_voters": len(poll.Votes),
+	}
+	if !poll.Anonymous {
+		event["user_id"] = userID
+		event["option_ids"] = optionIDs
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = p.js.Publish("msg.poll.voted", data)
+	return err
+}
+
+// PublishPollClosed publishes a msg.poll.closed event with the final tallies.
+func (p *EventPublisher) PublishPollClosed(ctx context.Context, msg *model.Message) error {
+	poll := msg.Payload.Poll
+	data, err := json.Marshal(map[string]interface{}{
+		"message_id":   msg.MessageID,
+		"chat_id":      msg.ChatID,
+		"options":      pollTallies(poll),
+		"total_voters": len(poll.Votes),
+		"closed_at":    poll.ClosedAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.js.Publish("msg.poll.closed", data)
+	return err
+}
+
+// PublishLiveLocationStopped publishes a msg.location.stopped event when a
+// live location share ends. reason is "stopped" or "expired".
+func (p *EventPublisher) PublishLiveLocationStopped(ctx context.Context, msg *model.Message, reason string) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"message_id": msg.MessageID,
+		"chat_id":    msg.ChatID,
+		"sender_id":  msg.SenderID,
+		"location":   msg.Payload.Location,
+		"reason":     reason,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.js.Publish("msg.location.stopped", data)
+	return err
+}
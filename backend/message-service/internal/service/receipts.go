@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	chatv1 "github.com/whatsapp-clone/backend/proto/chat/v1"
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
+)
+
+// MarkChatRead moves the user's read watermark in chatID up to messageID, or
+// to the chat's latest message when messageID is empty.
+func (s *messageServiceImpl) MarkChatRead(ctx context.Context, chatID, userID, messageID string) error {
+	var msg *model.Message
+	if messageID != "" {
+		m, err := s.messageRepo.GetByID(ctx, messageID)
+		if err != nil {
+			return apperr.NewInternal("failed to get message", err)
+		}
+		if m == nil || m.ChatID != chatID {
+			return apperr.NewNotFound("message not found")
+		}
+		msg = m
+	} else {
+		latest, err := s.messageRepo.ListBySeq(ctx, chatID, 0, 0, 1)
+		if err != nil {
+			return apperr.NewInternal("failed to get latest message", err)
+		}
+		if len(latest) == 0 {
+			return nil
+		}
+		msg = latest[0]
+	}
+	return s.advanceWatermark(ctx, msg, userID, model.StatusRead)
+}
+
+// advanceWatermark moves userID's watermark for status in msg's chat up to
+// msg; a read also counts as delivered. If it moved, one event tells the
+// other members that every message up to msg has that status for userID.
+// Reads that read receipts keep hidden are published as delivered, while the
+// stored watermark still clears the unread count.
+func (s *messageServiceImpl) advanceWatermark(ctx context.Context, msg *model.Message, userID string, status model.MessageStatus) error {
+	perm, err := s.chatClient.CheckChatPermission(ctx, &chatv1.CheckChatPermissionRequest{
+		ChatId: msg.ChatID,
+		UserId: userID,
+	})
+	if err != nil {
+		return apperr.NewInternal("failed to verify chat membership", err)
+	}
+	if !perm.IsMember {
+		return apperr.NewForbidden("not a member of this chat")
+	}
+
+	var readSeq int64
+	if status == model.StatusRead {
+		readSeq = msg.Seq
+	}
+	_, moved, err := s.messageRepo.AdvanceWatermark(ctx, msg.ChatID, userID, msg.Seq, readSeq, time.Now())
+	if err != nil {
+		return apperr.NewInternal("failed to update watermark", err)
+	}
+	if !moved {
+		return nil
+	}
+
+	shown := status
+	if status == model.StatusRead {
+		userIDs := []string{userID}
+		if perm.PeerUserId != "" {
+			userIDs = append(userIDs, perm.PeerUserId)
+		}
+		shown = s.readReceipts(ctx, msg.ChatID, userID, userIDs).status(perm.PeerUserId, userID, status)
+	}
+	if err := s.publisher.PublishStatusUpdate(ctx, msg.ChatID, userID, string(shown), msg.MessageID, msg.Seq); err != nil {
+		s.log.Error().Err(err).Str("message_id", msg.MessageID).Msg("failed to publish msg.status.updated event")
+	}
+	return nil
+}
+
+// GetMessageReceipts returns the message's per-recipient statuses; see
+// applyReceipts.
+func (s *messageServiceImpl) GetMessageReceipts(ctx context.Context, messageID, userID string) (map[string]model.RecipientStatus, error) {
+	msg, err := s.GetMessageByID(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyReceipts(ctx, msg.ChatID, userID, []*model.Message{msg}); err != nil {
+		return nil, err
+	}
+	return msg.Status, nil
+}
+
+// applyReceipts fills in each message's per-recipient statuses from the
+// chat's watermarks, as viewerID may see them: reads that read receipts keep
+// hidden are reported as delivered, as their senders were told. Read receipt
+// settings are only looked up when one of the messages has been read.
+func (s *messageServiceImpl) applyReceipts(ctx context.Context, chatID, viewerID string, msgs []*model.Message) error {
+	watermarks, err := s.messageRepo.ListWatermarks(ctx, chatID)
+	if err != nil {
+		return apperr.NewInternal("failed to get watermarks", err)
+	}
+
+	var userIDs []string
+	for _, msg := range msgs {
+		msg.Status = make(map[string]model.RecipientStatus)
+		for _, wm := range watermarks {
+			if wm.UserID == msg.SenderID {
+				continue
+			}
+			rs, ok := wm.StatusOf(msg.Seq)
+			if !ok {
+				continue
+			}
+			if rs.Status == model.StatusRead {
+				userIDs = append(userIDs, msg.SenderID, wm.UserID)
+			}
+			msg.Status[wm.UserID] = rs
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	receipts := s.readReceipts(ctx, chatID, viewerID, userIDs)
+	delivered := make(map[string]time.Time, len(watermarks))
+	for _, wm := range watermarks {
+		delivered[wm.UserID] = wm.DeliveredAt
+	}
+	for _, msg := range msgs {
+		for userID, rs := range msg.Status {
+			if rs.Status == model.StatusRead && receipts.status(msg.SenderID, userID, rs.Status) != model.StatusRead {
+				msg.Status[userID] = model.RecipientStatus{Status: model.StatusDelivered, UpdatedAt: delivered[userID]}
+			}
+		}
+	}
+	return nil
+}
+
+// withReceipts applies receipts to messages returned after a change or from
+// a search, which can span chats. The change has already been made, so a
+// failed lookup is logged and leaves the statuses empty rather than failing
+// the request.
+func (s *messageServiceImpl) withReceipts(ctx context.Context, viewerID string, msgs ...*model.Message) {
+	byChat := make(map[string][]*model.Message)
+	for _, msg := range msgs {
+		byChat[msg.ChatID] = append(byChat[msg.ChatID], msg)
+	}
+	for chatID, chatMsgs := range byChat {
+		if err := s.applyReceipts(ctx, chatID, viewerID, chatMsgs); err != nil {
+			s.log.Warn().Err(err).Str("chat_id", chatID).Msg("failed to apply receipts")
+		}
+	}
+}
+
+// receiptPolicy decides whose reads in one chat are shown. A direct chat
+// shows reads only if both users have read receipts on; a group shows each
+// member's reads unless that member turned them off. With allOff no reads
+// are shown at all.
+type receiptPolicy struct {
+	direct bool
+	allOff bool
+	off    map[string]struct{}
+}
+
+// status is what senderID is told when readerID reaches status: reads the
+// policy hides are reported as delivered.
+func (p *receiptPolicy) status(senderID, readerID string, status model.MessageStatus) model.MessageStatus {
+	if status != model.StatusRead {
+		return status
+	}
+	if p.allOff {
+		return model.StatusDelivered
+	}
+	_, readerOff := p.off[readerID]
+	_, senderOff := p.off[senderID]
+	if readerOff || (p.direct && senderOff) {
+		return model.StatusDelivered
+	}
+	return status
+}
+
+// readReceipts loads the receipt policy of chatID, of which memberID is a
+// member, for the senders and readers in userIDs. Lookups that fail are
+// logged and hide reads, reporting them as delivered: a read is only shown
+// once it is known that nobody involved turned read receipts off.
+func (s *messageServiceImpl) readReceipts(ctx context.Context, chatID, memberID string, userIDs []string) *receiptPolicy {
+	policy := &receiptPolicy{off: make(map[string]struct{})}
+
+	resp, err := s.userClient.GetReadReceiptSettings(ctx, &userv1.GetReadReceiptSettingsRequest{UserIds: userIDs})
+	if err != nil {
+		s.log.Warn().Err(err).Str("chat_id", chatID).Msg("failed to check read receipt privacy, reporting reads as delivered")
+		policy.allOff = true
+		return policy
+	}
+	for _, uid := range resp.ReceiptsOffUserIds {
+		policy.off[uid] = struct{}{}
+	}
+	if len(policy.off) == 0 {
+		return policy
+	}
+
+	perm, err := s.chatClient.CheckChatPermission(ctx, &chatv1.CheckChatPermissionRequest{
+		ChatId: chatID,
+		UserId: memberID,
+	})
+	if err != nil {
+		// Treating the chat as direct hides the most reads.
+		s.log.Warn().Err(err).Str("chat_id", chatID).Msg("failed to look up chat type for read receipts")
+		policy.direct = true
+	} else {
+		policy.direct = perm.PeerUserId != ""
+	}
+	return policy
+}
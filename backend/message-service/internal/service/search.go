@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	chatv1 "github.com/whatsapp-clone/backend/proto/chat/v1"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+
+	// snippetLength is the maximum snippet size in code points; snippetLead is
+	// how much context is kept before the first match.
+	snippetLength = 120
+	snippetLead   = 40
+)
+
+// SearchMessages runs a ranked search within one chat after verifying membership.
+func (s *messageServiceImpl) SearchMessages(ctx context.Context, query *model.SearchQuery) (*model.SearchPage, error) {
+	if len(query.ChatIDs) != 1 {
+		return nil, apperr.NewBadRequest("exactly one chat_id is required")
+	}
+	if query.UserID != "" {
+		permResp, err := s.chatClient.CheckChatPermission(ctx, &chatv1.CheckChatPermissionRequest{
+			ChatId: query.ChatIDs[0],
+			UserId: query.UserID,
+		})
+		if err != nil {
+			return nil, apperr.NewInternal("failed to verify chat membership", err)
+		}
+		if !permResp.IsMember {
+			return nil, apperr.NewForbidden("not a member of this chat")
+		}
+	}
+	return s.search(ctx, query)
+}
+
+// SearchGlobal runs a ranked search across the user's chats. If ChatIDs is
+// set it narrows the search, but only chats the user belongs to are searched.
+func (s *messageServiceImpl) SearchGlobal(ctx context.Context, query *model.SearchQuery) (*model.SearchPage, error) {
+	resp, err := s.chatClient.GetUserChats(ctx, &chatv1.GetUserChatsRequest{UserId: query.UserID})
+	if err != nil {
+		return nil, apperr.NewInternal("failed to resolve user chats", err)
+	}
+
+	if len(query.ChatIDs) == 0 {
+		query.ChatIDs = resp.ChatIds
+	} else {
+		member := make(map[string]bool, len(resp.ChatIds))
+		for _, id := range resp.ChatIds {
+			member[id] = true
+		}
+		allowed := query.ChatIDs[:0]
+		for _, id := range query.ChatIDs {
+			if member[id] {
+				allowed = append(allowed, id)
+			}
+		}
+		query.ChatIDs = allowed
+	}
+	if len(query.ChatIDs) == 0 {
+		return &model.SearchPage{Hits: []*model.SearchHit{}}, nil
+	}
+	return s.search(ctx, query)
+}
+
+func (s *messageServiceImpl) search(ctx context.Context, query *model.SearchQuery) (*model.SearchPage, error) {
+	if strings.TrimSpace(query.Text) == "" {
+		return nil, apperr.NewBadRequest("query parameter 'q' is required")
+	}
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return nil, apperr.NewBadRequest("from must be before to")
+	}
+	limit := query.Limit
+	if limit <= 0 || limit > maxSearchLimit {
+		limit = defaultSearchLimit
+	}
+
+	// Fetch one extra hit to learn whether another page exists.
+	query.Limit = limit + 1
+	hits, err := s.messageRepo.Search(ctx, query)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to search messages", err)
+	}
+
+	page := &model.SearchPage{Hits: hits}
+	if len(hits) > limit {
+		page.Hits = hits[:limit]
+		page.HasMore = true
+		last := page.Hits[limit-1]
+		page.NextCursor = EncodeSearchCursor(&model.SearchCursor{
+			Score:     last.Score,
+			CreatedAt: last.Message.CreatedAt,
+			MessageID: last.Message.MessageID,
+		})
+	}
+
+	terms := searchTerms(query.Text)
+	msgs := make([]*model.Message, 0, len(page.Hits))
+	for _, hit := range page.Hits {
+		hit.Snippet, hit.Highlights = buildSnippet(searchableText(hit.Message), terms)
+		msgs = append(msgs, hit.Message)
+	}
+	s.withReceipts(ctx, query.UserID, msgs...)
+	return page, nil
+}
+
+// EncodeSearchCursor returns the opaque cursor string for a search page boundary.
+func EncodeSearchCursor(c *model.SearchCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeSearchCursor parses a cursor produced by EncodeSearchCursor.
+func DecodeSearchCursor(s string) (*model.SearchCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, apperr.NewBadRequest("invalid cursor")
+	}
+	var c model.SearchCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.MessageID == "" {
+		return nil, apperr.NewBadRequest("invalid cursor")
+	}
+	return &c, nil
+}
+
+// searchableText returns the indexed text a hit most likely matched on.
+func searchableText(m *model.Message) string {
+	switch {
+	case m.Payload.Body != "":
+		return m.Payload.Body
+	case m.Payload.Caption != "":
+		return m.Payload.Caption
+	default:
+		return m.Payload.Filename
+	}
+}
+
+// searchTerms extracts the lowercase words of a $text query, skipping negated
+// terms ("-word") and treating quoted phrases as their individual words.
+func searchTerms(query string) []string {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		for _, w := range strings.FieldsFunc(strings.ToLower(field), isNotWordRune) {
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// termMatches approximates the text index's stemming: a word matches when it
+// extends the term ("run" -> "running") or is a stem of it of 3+ letters.
+func termMatches(word string, terms []string) bool {
+	for _, t := range terms {
+		if strings.HasPrefix(word, t) {
+			return true
+		}
+		if len([]rune(word)) >= 3 && strings.HasPrefix(t, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildSnippet cuts a window of text around the first matching word and
+// returns it with the [start, end) code point ranges of every match inside it.
+func buildSnippet(text string, terms []string) (string, [][2]int) {
+	runes := []rune(text)
+
+	var matches [][2]int
+	for i := 0; i < len(runes); {
+		if isNotWordRune(runes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && !isNotWordRune(runes[j]) {
+			j++
+		}
+		if termMatches(strings.ToLower(string(runes[i:j])), terms) {
+			matches = append(matches, [2]int{i, j})
+		}
+		i = j
+	}
+
+	start, end := 0, len(runes)
+	if len(runes) > snippetLength {
+		if len(matches) > 0 && matches[0][0] > snippetLead {
+			start = matches[0][0] - snippetLead
+			// Don't start mid-word.
+			for start < matches[0][0] && !unicode.IsSpace(runes[start-1]) {
+				start++
+			}
+		}
+		end = start + snippetLength
+		if end > len(runes) {
+			end = len(runes)
+		}
+		for end > start && end < len(runes) && !unicode.IsSpace(runes[end]) {
+			end--
+		}
+		if end == start {
+			end = start + snippetLength
+		}
+	}
+
+	var b strings.Builder
+	offset := -start
+	if start > 0 {
+		b.WriteRune('…')
+		offset++
+	}
+	b.WriteString(strings.TrimSpace(string(runes[start:end])))
+	if end < len(runes) {
+		b.WriteRune('…')
+	}
+
+	// TrimSpace can only remove leading spaces when the window starts on one.
+	lead := 0
+	for k := start; k < end && unicode.IsSpace(runes[k]); k++ {
+		lead++
+	}
+	offset -= lead
+
+	highlights := make([][2]int, 0, len(matches))
+	for _, m := range matches {
+		if m[0] >= start && m[1] <= end {
+			highlights = append(highlights, [2]int{m[0] + offset, m[1] + offset})
+		}
+	}
+	return b.String(), highlights
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+	"github.com/whatsapp-clone/backend/message-service/internal/repository"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	chatv1 "github.com/whatsapp-clone/backend/proto/chat/v1"
+)
+
+// GetThread returns a thread's root message and a page of its replies, oldest
+// first. messageID may be the root or any reply in the thread.
+func (s *messageServiceImpl) GetThread(ctx context.Context, query *model.ThreadQuery) (*model.Message, []*model.Message, error) {
+	msg, err := s.messageRepo.GetByID(ctx, query.MessageID)
+	if err != nil {
+		return nil, nil, apperr.NewInternal("failed to get message", err)
+	}
+	if msg == nil {
+		return nil, nil, apperr.NewNotFound("message not found")
+	}
+
+	if query.UserID != "" {
+		permResp, err := s.chatClient.CheckChatPermission(ctx, &chatv1.CheckChatPermissionRequest{
+			ChatId: msg.ChatID,
+			UserId: query.UserID,
+		})
+		if err != nil {
+			return nil, nil, apperr.NewInternal("failed to verify chat membership", err)
+		}
+		if !permResp.IsMember {
+			return nil, nil, apperr.NewForbidden("not a member of this chat")
+		}
+	}
+
+	root := msg
+	if msg.ThreadID != "" {
+		root, err = s.messageRepo.GetByID(ctx, msg.ThreadID)
+		if err != nil {
+			return nil, nil, apperr.NewInternal("failed to get thread root", err)
+		}
+		if root == nil {
+			return nil, nil, apperr.NewNotFound("thread root not found")
+		}
+	}
+
+	var cursorTime *time.Time
+	if query.Cursor != "" {
+		t, err := time.Parse(time.RFC3339Nano, query.Cursor)
+		if err != nil {
+			return nil, nil, apperr.NewBadRequest("invalid cursor format, expected RFC3339Nano")
+		}
+		cursorTime = &t
+	}
+
+	limit := query.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	replies, err := s.messageRepo.ListThread(ctx, root.MessageID, cursorTime, query.CursorID, limit)
+	if err != nil {
+		return nil, nil, apperr.NewInternal("failed to list thread replies", err)
+	}
+	if query.UserID != "" {
+		if err := s.applyReceipts(ctx, root.ChatID, query.UserID, append([]*model.Message{root}, replies...)); err != nil {
+			return nil, nil, err
+		}
+	}
+	return root, replies, nil
+}
+
+// threadRootFor resolves the thread a reply to parentID joins. The parent must
+// be a live message in chatID; replies to a reply join the parent's thread.
+func (s *messageServiceImpl) threadRootFor(ctx context.Context, chatID, parentID string) (string, error) {
+	parent, err := s.messageRepo.GetByID(ctx, parentID)
+	if err != nil {
+		return "", apperr.NewInternal("failed to get replied-to message", err)
+	}
+	if parent == nil || parent.IsDeleted || parent.ChatID != chatID {
+		return "", apperr.NewBadRequest("reply_to_message_id must reference a message in this chat")
+	}
+	if parent.ThreadID != "" {
+		return parent.ThreadID, nil
+	}
+	return parent.MessageID, nil
+}
+
+// adjustReplyCount applies delta to a thread root's reply count and publishes
+// the new count. replyAt is set for new replies so last_reply_at moves forward;
+// removals recompute it from the remaining replies.
+// Failures are logged: the reply itself has already been stored or deleted.
+func adjustReplyCount(ctx context.Context, repo repository.MessageRepository, pub *EventPublisher, log zerolog.Logger, rootID string, delta int64, replyAt *time.Time) {
+	root, err := repo.UpdateReplyCount(ctx, rootID, delta, replyAt)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			log.Error().Err(err).Str("message_id", rootID).Msg("failed to update thread reply count")
+		}
+		return
+	}
+	if pubErr := pub.PublishThreadUpdated(ctx, root); pubErr != nil {
+		log.Error().Err(pubErr).Str("message_id", rootID).Msg("failed to publish msg.thread.updated event")
+	}
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"io"
+	"mime/quotedprintable"
+	"strings"
+
+	"github.com/whatsapp-clone/backend/message-service/internal/model"
+)
+
+// vcardLine is one unfolded content line: "group.NAME;PARAM=a,b:value".
+type vcardLine struct {
+	name   string
+	params map[string][]string
+	value  string
+}
+
+// parseVCard reads every BEGIN:VCARD ... END:VCARD block in raw. It accepts
+// vCard 2.1, 3.0 and 4.0, ignores properties it does not render, and returns
+// the cards as-is; normalizeContact cleans them up afterwards.
+func parseVCard(raw string) []model.Contact {
+	var (
+		cards []model.Contact
+		cur   *model.Contact
+	)
+	for _, line := range unfoldVCard(raw) {
+		l, ok := parseVCardLine(line)
+		if !ok {
+			continue
+		}
+		switch l.name {
+		case "BEGIN":
+			if strings.EqualFold(l.value, "VCARD") {
+				cur = &model.Contact{}
+			}
+			continue
+		case "END":
+			if cur != nil && strings.EqualFold(l.value, "VCARD") {
+				cards = append(cards, *cur)
+				cur = nil
+			}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+
+		value := l.value
+		if l.hasParam("ENCODING", "QUOTED-PRINTABLE") {
+			if b, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(value))); err == nil {
+				value = string(b)
+			}
+		}
+
+		switch l.name {
+		case "FN":
+			cur.Name = unescapeVCard(value)
+		case "N":
+			// Only used when FN is missing: N is Family;Given;Middle;Prefix;Suffix.
+			if cur.Name == "" {
+				parts := splitVCardValue(value)
+				var given, family string
+				if len(parts) > 0 {
+					family = parts[0]
+				}
+				if len(parts) > 1 {
+					given = parts[1]
+				}
+				cur.Name = strings.TrimSpace(given + " " + family)
+			}
+		case "ORG":
+			var units []string
+			for _, p := range splitVCardValue(value) {
+				if p = strings.TrimSpace(p); p != "" {
+					units = append(units, p)
+				}
+			}
+			cur.Organization = strings.Join(units, ", ")
+		case "TEL":
+			cur.Phones = append(cur.Phones, model.ContactPhone{
+				Number: strings.TrimPrefix(unescapeVCard(value), "tel:"),
+				Type:   phoneTypeFromParams(l),
+			})
+		case "EMAIL":
+			cur.Emails = append(cur.Emails, unescapeVCard(value))
+		}
+	}
+	return cards
+}
+
+// unfoldVCard splits raw into logical lines, joining folded continuation
+// lines (leading space or tab) and quoted-printable soft line breaks.
+func unfoldVCard(raw string) []string {
+	raw = strings.ReplaceAll(raw, "\r\n", "\n")
+	raw = strings.ReplaceAll(raw, "\r", "\n")
+
+	var lines []string
+	for _, line := range strings.Split(raw, "\n") {
+		n := len(lines)
+		switch {
+		case n > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")):
+			lines[n-1] += line[1:]
+		case n > 0 && strings.HasSuffix(lines[n-1], "=") && strings.Contains(strings.ToUpper(lines[n-1]), "QUOTED-PRINTABLE"):
+			lines[n-1] = strings.TrimSuffix(lines[n-1], "=") + line
+		default:
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// parseVCardLine splits a content line into name, parameters and value. The
+// name is upper-cased and stripped of any group prefix.
+func parseVCardLine(line string) (vcardLine, bool) {
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return vcardLine{}, false
+	}
+
+	head := strings.Split(line[:colon], ";")
+	name := strings.ToUpper(strings.TrimSpace(head[0]))
+	if dot := strings.LastIndexByte(name, '.'); dot >= 0 {
+		name = name[dot+1:]
+	}
+
+	l := vcardLine{name: name, params: make(map[string][]string), value: strings.TrimSpace(line[colon+1:])}
+	for _, p := range head[1:] {
+		key, val, found := strings.Cut(p, "=")
+		if !found {
+			// vCard 2.1 allows bare parameter values, e.g. TEL;CELL;HOME.
+			key, val = "TYPE", p
+			if strings.EqualFold(p, "QUOTED-PRINTABLE") {
+				key = "ENCODING"
+			}
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		for _, v := range strings.Split(strings.Trim(val, `"`), ",") {
+			if v = strings.ToUpper(strings.TrimSpace(v)); v != "" {
+				l.params[key] = append(l.params[key], v)
+			}
+		}
+	}
+	return l, true
+}
+
+func (l vcardLine) hasParam(key, value string) bool {
+	for _, v := range l.params[key] {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// vcardPhoneTypes maps TEL TYPE parameters to the labels stored on a
+// ContactPhone, in order of preference.
+var vcardPhoneTypes = []struct{ param, label string }{
+	{"CELL", "mobile"},
+	{"IPHONE", "mobile"},
+	{"MAIN", "main"},
+	{"HOME", "home"},
+	{"WORK", "work"},
+	{"FAX", "fax"},
+}
+
+func phoneTypeFromParams(l vcardLine) string {
+	for _, t := range vcardPhoneTypes {
+		if l.hasParam("TYPE", t.param) {
+			return t.label
+		}
+	}
+	return ""
+}
+
+// splitVCardValue splits a structured value on unescaped semicolons and
+// unescapes each component.
+func splitVCardValue(value string) []string {
+	var (
+		parts []string
+		b     strings.Builder
+	)
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value):
+			b.WriteByte(value[i])
+			b.WriteByte(value[i+1])
+			i++
+		case value[i] == ';':
+			parts = append(parts, unescapeVCard(b.String()))
+			b.Reset()
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return append(parts, unescapeVCard(b.String()))
+}
+
+var (
+	vcardUnescaper = strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	vcardEscaper   = strings.NewReplacer(`\`, `\\`, "\n", `\n`, ",", `\,`, ";", `\;`)
+)
+
+func unescapeVCard(s string) string {
+	return strings.TrimSpace(vcardUnescaper.Replace(s))
+}
+
+// formatVCard renders a normalized contact as a vCard 3.0 card.
+func formatVCard(c model.Contact) string {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCARD\r\nVERSION:3.0\r\n")
+	b.WriteString("FN:" + vcardEscaper.Replace(c.Name) + "\r\n")
+	b.WriteString("N:;" + vcardEscaper.Replace(c.Name) + ";;;\r\n")
+	if c.Organization != "" {
+		b.WriteString("ORG:" + vcardEscaper.Replace(c.Organization) + "\r\n")
+	}
+	for _, p := range c.Phones {
+		b.WriteString("TEL")
+		for _, t := range vcardPhoneTypes {
+			if t.label == p.Type {
+				b.WriteString(";TYPE=" + t.param)
+				break
+			}
+		}
+		b.WriteString(":" + vcardEscaper.Replace(p.Number) + "\r\n")
+	}
+	for _, e := range c.Emails {
+		b.WriteString("EMAIL;TYPE=INTERNET:" + vcardEscaper.Replace(e) + "\r\n")
+	}
+	b.WriteString("END:VCARD\r\n")
+	return b.String()
+}
@@ -0,0 +1,52 @@
+db = db.getSiblingDB('whatsapp');
+
+// One-off backfill of chat_watermarks from the per-message status maps
+// written before delivered/read watermarks replaced them. Each member's
+// watermarks are raised to the highest seq they had delivered or read, and
+// their unread counter is seeded from the live messages from others after
+// their read watermark. Run after backfill_message_seq.js. Safe to re-run:
+// watermarks only move forward.
+let watermarks = 0;
+
+db.messages.aggregate([
+  { $match: { seq: { $exists: true }, status: { $exists: true, $ne: {} } } },
+  { $project: { chat_id: 1, seq: 1, status: { $objectToArray: "$status" } } },
+  { $unwind: "$status" },
+  {
+    $group: {
+      _id: { chat_id: "$chat_id", user_id: "$status.k" },
+      delivered_seq: { $max: "$seq" },
+      delivered_at: { $max: "$status.v.updated_at" },
+      read_seq: { $max: { $cond: [{ $eq: ["$status.v.status", "read"] }, "$seq", NumberLong(0)] } },
+      read_at: { $max: { $cond: [{ $eq: ["$status.v.status", "read"] }, "$status.v.updated_at", null] } },
+    },
+  },
+], { allowDiskUse: true }).forEach(function (wm) {
+  const chatId = wm._id.chat_id;
+  const userId = wm._id.user_id;
+  const readSeq = wm.read_seq || NumberLong(0);
+
+  db.chat_watermarks.updateOne(
+    { chat_id: chatId, user_id: userId },
+    {
+      $max: { delivered_seq: wm.delivered_seq, read_seq: readSeq },
+      $setOnInsert: {
+        delivered_at: wm.delivered_at,
+        read_at: wm.read_at,
+      },
+    },
+    { upsert: true }
+  );
+
+  const current = db.chat_watermarks.findOne({ chat_id: chatId, user_id: userId });
+  const unread = db.messages.countDocuments({
+    chat_id: chatId,
+    seq: { $gt: current.read_seq },
+    sender_id: { $ne: userId },
+    is_deleted: false,
+  });
+  db.chat_watermarks.updateOne({ _id: current._id }, { $set: { unread_count: NumberLong(unread) } });
+  watermarks++;
+});
+
+print("Backfilled " + watermarks + " chat watermarks");
@@ -0,0 +1,31 @@
+db = db.getSiblingDB('whatsapp');
+
+// One-off backfill of per-chat message seq for messages written before seq
+// was assigned at insert time. Numbers each chat's messages in
+// (created_at, message_id) order and seeds chat_sequences so new inserts
+// continue from there. Chats that already have a counter are skipped, since
+// their new messages already hold the low seq values.
+let chats = 0;
+let updated = 0;
+
+db.messages.distinct("chat_id", { seq: { $exists: false } }).forEach(function (chatId) {
+  if (db.chat_sequences.findOne({ _id: chatId })) {
+    print("skipping chat " + chatId + ": counter already exists");
+    return;
+  }
+
+  let seq = 0;
+  db.messages
+    .find({ chat_id: chatId }, { _id: 1 })
+    .sort({ created_at: 1, message_id: 1 })
+    .forEach(function (msg) {
+      seq++;
+      db.messages.updateOne({ _id: msg._id }, { $set: { seq: NumberLong(seq) } });
+      updated++;
+    });
+
+  db.chat_sequences.insertOne({ _id: chatId, seq: NumberLong(seq) });
+  chats++;
+});
+
+print("Backfilled seq for " + updated + " messages across " + chats + " chats");
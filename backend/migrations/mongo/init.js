@@ -3,9 +3,35 @@ db = db.getSiblingDB('whatsapp');
 // Messages collection indexes
 db.messages.createIndex({ "chat_id": 1, "created_at": -1 });
 db.messages.createIndex({ "client_msg_id": 1 }, { unique: true, sparse: true });
-db.messages.createIndex({ "payload.body": "text" });
+db.messages.createIndex(
+  { "payload.body": "text", "payload.caption": "text", "payload.filename": "text" },
+  {
+    name: "message_search",
+    weights: { "payload.body": 10, "payload.caption": 5, "payload.filename": 2 },
+    default_language: "english"
+  }
+);
 db.messages.createIndex({ "sender_id": 1, "created_at": -1 });
 db.messages.createIndex({ "chat_id": 1, "message_id": 1 });
+db.messages.createIndex(
+  { "chat_id": 1, "seq": 1 },
+  { unique: true, partialFilterExpression: { "seq": { $exists: true } } }
+);
+db.messages.createIndex(
+  { "thread_id": 1, "created_at": 1, "message_id": 1 },
+  { partialFilterExpression: { "thread_id": { $exists: true } } }
+);
+db.messages.createIndex(
+  { "payload.location.live_until": 1 },
+  { partialFilterExpression: { "payload.location.live_until": { $exists: true } } }
+);
+db.messages.createIndex(
+  { "expires_at": 1 },
+  { partialFilterExpression: { "expires_at": { $exists: true }, "is_deleted": false } }
+);
+
+// Per-member delivered/read watermarks
+db.chat_watermarks.createIndex({ "chat_id": 1, "user_id": 1 }, { unique: true });
 
 // Media collection indexes
 db.media.createIndex({ "media_id": 1 }, { unique: true });
@@ -0,0 +1,8 @@
+ALTER TABLE chat_participants ADD COLUMN IF NOT EXISTS auto_delete_timer INTERVAL;
+
+UPDATE chat_participants cp
+SET auto_delete_timer = c.auto_delete_timer
+FROM chats c
+WHERE c.id = cp.chat_id AND c.auto_delete_timer IS NOT NULL;
+
+ALTER TABLE chats DROP COLUMN IF EXISTS auto_delete_timer;
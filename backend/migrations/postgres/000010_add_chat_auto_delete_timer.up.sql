@@ -0,0 +1,19 @@
+ALTER TABLE chats ADD COLUMN IF NOT EXISTS auto_delete_timer INTERVAL;
+
+-- The timer used to be kept per participant. Carry it over to the chat,
+-- keeping the shortest one any member had set, then drop the old column.
+-- The column is added first (if missing) so this also runs on databases
+-- that never had it.
+ALTER TABLE chat_participants ADD COLUMN IF NOT EXISTS auto_delete_timer INTERVAL;
+
+UPDATE chats c
+SET auto_delete_timer = p.timer
+FROM (
+    SELECT chat_id, MIN(auto_delete_timer) AS timer
+    FROM chat_participants
+    WHERE auto_delete_timer IS NOT NULL
+    GROUP BY chat_id
+) p
+WHERE c.id = p.chat_id AND c.auto_delete_timer IS NULL;
+
+ALTER TABLE chat_participants DROP COLUMN auto_delete_timer;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS group_join_requests;
+DROP TABLE IF EXISTS group_invite_links;
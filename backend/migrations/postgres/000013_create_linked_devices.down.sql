@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_device_id;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS device_id;
+DROP TABLE IF EXISTS linked_devices;
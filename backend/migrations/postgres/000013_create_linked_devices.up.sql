@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS linked_devices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    platform VARCHAR(16) NOT NULL,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    linked_by UUID REFERENCES linked_devices(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_active_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_linked_devices_user_id ON linked_devices(user_id, last_active_at DESC) WHERE revoked_at IS NULL;
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS device_id UUID REFERENCES linked_devices(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_refresh_tokens_device_id ON refresh_tokens(device_id) WHERE revoked = FALSE;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS two_step_verification (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    pin_hash TEXT NOT NULL,
+    recovery_email VARCHAR(254) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,16 @@
+ALTER TABLE group_join_requests DROP CONSTRAINT IF EXISTS group_join_requests_decided_by_fkey;
+ALTER TABLE group_join_requests ADD CONSTRAINT group_join_requests_decided_by_fkey
+    FOREIGN KEY (decided_by) REFERENCES users(id);
+
+ALTER TABLE group_invite_links DROP CONSTRAINT IF EXISTS group_invite_links_created_by_fkey;
+ALTER TABLE group_invite_links ADD CONSTRAINT group_invite_links_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users(id);
+ALTER TABLE group_invite_links ALTER COLUMN created_by SET NOT NULL;
+
+ALTER TABLE groups DROP CONSTRAINT IF EXISTS groups_created_by_fkey;
+ALTER TABLE groups ADD CONSTRAINT groups_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users(id);
+ALTER TABLE groups ALTER COLUMN created_by SET NOT NULL;
+
+DROP TABLE IF EXISTS account_deletion_steps;
+DROP TABLE IF EXISTS account_deletions;
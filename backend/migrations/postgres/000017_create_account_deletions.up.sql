@@ -0,0 +1,45 @@
+-- user_id has no foreign key: the deletion record outlives the user so its
+-- progress can still be looked up.
+CREATE TABLE IF NOT EXISTS account_deletions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'scheduled'
+        CHECK (status IN ('scheduled', 'cancelled', 'in_progress', 'completed')),
+    execute_after TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_account_deletions_active ON account_deletions(user_id)
+    WHERE status IN ('scheduled', 'in_progress');
+CREATE INDEX idx_account_deletions_due ON account_deletions(execute_after)
+    WHERE status = 'scheduled';
+CREATE INDEX idx_account_deletions_user_id ON account_deletions(user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS account_deletion_steps (
+    deletion_id UUID NOT NULL REFERENCES account_deletions(id) ON DELETE CASCADE,
+    service VARCHAR(32) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'completed', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (deletion_id, service)
+);
+
+-- Groups, invite links and join decisions outlive the user who created or
+-- decided them.
+ALTER TABLE groups ALTER COLUMN created_by DROP NOT NULL;
+ALTER TABLE groups DROP CONSTRAINT IF EXISTS groups_created_by_fkey;
+ALTER TABLE groups ADD CONSTRAINT groups_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE group_invite_links ALTER COLUMN created_by DROP NOT NULL;
+ALTER TABLE group_invite_links DROP CONSTRAINT IF EXISTS group_invite_links_created_by_fkey;
+ALTER TABLE group_invite_links ADD CONSTRAINT group_invite_links_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE group_join_requests DROP CONSTRAINT IF EXISTS group_join_requests_decided_by_fkey;
+ALTER TABLE group_join_requests ADD CONSTRAINT group_join_requests_decided_by_fkey
+    FOREIGN KEY (decided_by) REFERENCES users(id) ON DELETE SET NULL;
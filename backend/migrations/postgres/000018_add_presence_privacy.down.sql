@@ -0,0 +1,2 @@
+ALTER TABLE privacy_settings DROP COLUMN IF EXISTS last_seen_except;
+ALTER TABLE privacy_settings DROP COLUMN IF EXISTS online;
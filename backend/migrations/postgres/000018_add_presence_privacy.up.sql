@@ -0,0 +1,6 @@
+-- online: who sees the user online, "everyone" or "same_as_last_seen".
+-- last_seen_except: users for whom the last_seen setting is inverted, so
+-- "nobody" becomes "nobody except" and "contacts" becomes "contacts except".
+ALTER TABLE privacy_settings ADD COLUMN IF NOT EXISTS online VARCHAR(20) NOT NULL DEFAULT 'everyone'
+    CHECK (online IN ('everyone', 'same_as_last_seen'));
+ALTER TABLE privacy_settings ADD COLUMN IF NOT EXISTS last_seen_except UUID[] NOT NULL DEFAULT '{}';
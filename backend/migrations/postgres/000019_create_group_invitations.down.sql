@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS group_invitations;
+ALTER TABLE privacy_settings DROP COLUMN IF EXISTS group_add_except;
+ALTER TABLE privacy_settings DROP COLUMN IF EXISTS group_add;
@@ -0,0 +1,20 @@
+-- group_add: who may add the user to groups directly; anyone else can only
+-- invite them. group_add_except: contacts who may not, under "contacts_except".
+ALTER TABLE privacy_settings ADD COLUMN IF NOT EXISTS group_add VARCHAR(20) NOT NULL DEFAULT 'everyone'
+    CHECK (group_add IN ('everyone', 'contacts', 'contacts_except', 'nobody'));
+ALTER TABLE privacy_settings ADD COLUMN IF NOT EXISTS group_add_except UUID[] NOT NULL DEFAULT '{}';
+
+CREATE TABLE IF NOT EXISTS group_invitations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    chat_id UUID NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined')),
+    message_id VARCHAR(64),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    decided_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_group_invitations_pending ON group_invitations(chat_id, user_id) WHERE status = 'pending';
+CREATE INDEX idx_group_invitations_user_id ON group_invitations(user_id, created_at);
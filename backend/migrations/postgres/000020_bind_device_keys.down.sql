@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_device_keys_linked_device;
+ALTER TABLE device_keys DROP COLUMN IF EXISTS linked_device_id;
@@ -0,0 +1,7 @@
+-- linked_device_id: the auth-service device whose session uploaded the keys.
+-- Revoking that device drops them. Keys uploaded before this column existed
+-- stay unbound until their device uploads again.
+ALTER TABLE device_keys ADD COLUMN IF NOT EXISTS linked_device_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_device_keys_linked_device ON device_keys(user_id, linked_device_id)
+    WHERE linked_device_id IS NOT NULL;
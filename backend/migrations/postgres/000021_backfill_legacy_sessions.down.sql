@@ -0,0 +1,9 @@
+-- Unbind the tokens first: deleting their device would cascade to them.
+UPDATE refresh_tokens SET device_id = NULL
+WHERE device_id IN (
+    SELECT id FROM linked_devices
+    WHERE name = 'Legacy session' AND platform = 'other' AND is_primary AND ip = '' AND user_agent = ''
+);
+
+DELETE FROM linked_devices
+WHERE name = 'Legacy session' AND platform = 'other' AND is_primary AND ip = '' AND user_agent = '';
@@ -0,0 +1,16 @@
+-- Refresh tokens issued before devices were tracked have no device_id, so
+-- the devices and sessions APIs could neither list nor revoke them. Each user
+-- holding live ones gets one "Legacy session" device that owns all of them:
+-- their lineage was never recorded, so they cannot be told apart.
+WITH legacy AS (
+    INSERT INTO linked_devices (user_id, name, platform, is_primary, created_at, last_active_at)
+    SELECT user_id, 'Legacy session', 'other', TRUE, MIN(created_at), MAX(created_at)
+    FROM refresh_tokens
+    WHERE device_id IS NULL AND revoked = FALSE AND expires_at > NOW()
+    GROUP BY user_id
+    RETURNING id, user_id
+)
+UPDATE refresh_tokens rt SET device_id = legacy.id
+FROM legacy
+WHERE rt.user_id = legacy.user_id
+  AND rt.device_id IS NULL AND rt.revoked = FALSE AND rt.expires_at > NOW();
@@ -19,6 +19,7 @@ import (
 	"github.com/whatsapp-clone/backend/notification-service/config"
 	"github.com/whatsapp-clone/backend/notification-service/internal/repository"
 	"github.com/whatsapp-clone/backend/notification-service/internal/service"
+	"github.com/whatsapp-clone/backend/pkg/accountdeletion"
 	"github.com/whatsapp-clone/backend/pkg/health"
 	"github.com/whatsapp-clone/backend/pkg/logger"
 	"github.com/whatsapp-clone/backend/pkg/metrics"
@@ -131,6 +132,13 @@ func main() {
 		}
 	}()
 
+	// --- Account deletion ---
+	if err := accountdeletion.Subscribe(consumerCtx, js, accountdeletion.ServiceNotification, func(ctx context.Context, e *accountdeletion.Event) error {
+		return tokenRepo.DeleteByUser(ctx, e.UserID)
+	}, log); err != nil {
+		log.Fatal().Err(err).Msg("failed to subscribe to account deletions")
+	}
+
 	// --- HTTP Server (health checks only) ---
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
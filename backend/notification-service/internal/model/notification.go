@@ -28,3 +28,12 @@ type MemberEvent struct {
 	AddedBy   string `json:"added_by"`
 	GroupName string `json:"group_name"`
 }
+
+// ExportEvent is the NATS event received when a data or chat export
+// finishes; the subject says whether it is ready or failed.
+type ExportEvent struct {
+	ExportID string `json:"export_id"`
+	UserID   string `json:"user_id"`
+	Kind     string `json:"kind"`
+	ChatID   string `json:"chat_id,omitempty"`
+}
@@ -12,6 +12,7 @@ import (
 type DeviceTokenRepository interface {
 	GetByUserID(ctx context.Context, userID string) ([]string, error)
 	DeleteByToken(ctx context.Context, token string) error
+	DeleteByUser(ctx context.Context, userID string) error
 }
 
 type deviceTokenPostgres struct {
@@ -51,3 +52,15 @@ func (r *deviceTokenPostgres) DeleteByToken(ctx context.Context, token string) e
 	r.log.Info().Str("token", token[:min(len(token), 12)]+"...").Msg("deleted stale FCM token")
 	return nil
 }
+
+// DeleteByUser purges every token a user registered, when their account is
+// deleted.
+func (r *deviceTokenPostgres) DeleteByUser(ctx context.Context, userID string) error {
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM device_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("delete user device tokens: %w", err)
+	}
+	r.log.Info().Str("user_id", userID).Int64("count", tag.RowsAffected()).Msg("purged device tokens of deleted account")
+	return nil
+}
@@ -44,7 +44,7 @@ func NewConsumer(
 	}
 }
 
-// ensureStreams creates MESSAGES and CHATS JetStream streams if they do not exist.
+// ensureStreams creates the MESSAGES, CHATS and EXPORTS JetStream streams if they do not exist.
 func (c *Consumer) ensureStreams() error {
 	streams := []struct {
 		name     string
@@ -52,6 +52,7 @@ func (c *Consumer) ensureStreams() error {
 	}{
 		{name: "MESSAGES", subjects: []string{"msg.>"}},
 		{name: "CHATS", subjects: []string{"chat.>", "group.>"}},
+		{name: "EXPORTS", subjects: []string{"export.>"}},
 	}
 	for _, st := range streams {
 		info, _ := c.js.StreamInfo(st.name)
@@ -81,6 +82,9 @@ func (c *Consumer) Start(ctx context.Context) error {
 	if err := c.subscribeMemberEvents(ctx); err != nil {
 		return err
 	}
+	if err := c.subscribeExportEvents(ctx); err != nil {
+		return err
+	}
 
 	c.log.Info().Msg("NATS consumers started")
 	<-ctx.Done()
@@ -140,6 +144,33 @@ func (c *Consumer) subscribeMemberEvents(ctx context.Context) error {
 	return nil
 }
 
+// subscribeExportEvents sets up a durable consumer for export.ready and
+// export.failed events.
+func (c *Consumer) subscribeExportEvents(ctx context.Context) error {
+	_, err := c.js.Subscribe("export.*", func(natsMsg *nats.Msg) {
+		var event model.ExportEvent
+		if err := json.Unmarshal(natsMsg.Data, &event); err != nil {
+			c.log.Error().Err(err).Str("subject", natsMsg.Subject).Msg("failed to unmarshal export event")
+			_ = natsMsg.Nak()
+			return
+		}
+
+		if err := c.handleExportFinished(ctx, natsMsg.Subject, &event); err != nil {
+			c.log.Error().Err(err).
+				Str("export_id", event.ExportID).
+				Msg("failed to handle export notification")
+			_ = natsMsg.Nak()
+			return
+		}
+		_ = natsMsg.Ack()
+	}, nats.Durable("notif-export-consumer"), nats.ManualAck(), nats.AckWait(30*time.Second))
+
+	if err != nil {
+		return fmt.Errorf("subscribe to export events: %w", err)
+	}
+	return nil
+}
+
 // handleNewMessage processes a single message event and sends push notifications
 // to all offline, non-muted recipients.
 func (c *Consumer) handleNewMessage(ctx context.Context, event *model.MessageEvent) error {
@@ -205,6 +236,39 @@ func (c *Consumer) handleGroupMemberAdded(ctx context.Context, event *model.Memb
 	return nil
 }
 
+// handleExportFinished tells an offline user that their export is ready to
+// download, or that it failed. Online users already got the WebSocket event.
+func (c *Consumer) handleExportFinished(ctx context.Context, subject string, event *model.ExportEvent) error {
+	online, err := c.presenceRepo.IsOnline(ctx, event.UserID)
+	if err == nil && online {
+		return nil
+	}
+
+	title := "Account data export"
+	if event.Kind == "chat" {
+		title = "Chat export"
+	}
+	body := "Your export is ready to download"
+	status := "ready"
+	if subject == "export.failed" {
+		body = "Your export could not be created. Please try again."
+		status = "failed"
+	}
+
+	data := map[string]string{
+		"type":      "export",
+		"export_id": event.ExportID,
+		"kind":      event.Kind,
+		"status":    status,
+	}
+	if event.ChatID != "" {
+		data["chat_id"] = event.ChatID
+	}
+
+	c.sendPushToUser(ctx, event.UserID, title, body, data)
+	return nil
+}
+
 // sendPushToUser retrieves device tokens for a user and sends push notifications.
 func (c *Consumer) sendPushToUser(ctx context.Context, userID, title, body string, data map[string]string) {
 	tokens, err := c.tokenRepo.GetByUserID(ctx, userID)
@@ -0,0 +1,150 @@
+// Package accountdeletion carries the account deletion saga between
+// services. Once a deletion's grace period has passed, user-service publishes
+// an Event on SubjectDeleted. Every participating service erases its share of
+// the user's data and reports the outcome on SubjectProgress; user-service
+// deletes the user record itself after all of them have completed.
+package accountdeletion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog"
+)
+
+const (
+	StreamName      = "USERS"
+	SubjectDeleted  = "user.deleted"
+	SubjectProgress = "user.deletion.progress"
+)
+
+// Service names used in progress reports.
+const (
+	ServiceAuth         = "auth"
+	ServiceChat         = "chat"
+	ServiceMessage      = "message"
+	ServiceMedia        = "media"
+	ServiceNotification = "notification"
+	ServiceUser         = "user"
+)
+
+// Participants are the services user-service waits for before it deletes
+// the user record.
+var Participants = []string{ServiceAuth, ServiceChat, ServiceMessage, ServiceMedia, ServiceNotification}
+
+// Progress statuses.
+const (
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// DeletedUserID is who a deleted account's messages are attributed to once
+// they have been anonymized.
+const DeletedUserID = "00000000-0000-0000-0000-000000000000"
+
+// retryDelay is how long a failed erasure waits before it is redelivered.
+const retryDelay = 30 * time.Second
+
+// handlerTimeout bounds one erasure attempt.
+const handlerTimeout = 2 * time.Minute
+
+// Event asks every service to erase a user's data.
+type Event struct {
+	DeletionID  string    `json:"deletion_id"`
+	UserID      string    `json:"user_id"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// Progress reports one service's outcome for a deletion.
+type Progress struct {
+	DeletionID string    `json:"deletion_id"`
+	UserID     string    `json:"user_id"`
+	Service    string    `json:"service"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// Handler erases one service's data for the user in e. It must be
+// idempotent: the event is redelivered until the handler succeeds.
+type Handler func(ctx context.Context, e *Event) error
+
+// EnsureStream creates the USERS stream if it does not already exist. The
+// stream lists its subjects explicitly because websocket-service's
+// USER_EVENTS stream already owns user.events.>, and JetStream rejects
+// streams with overlapping subjects.
+func EnsureStream(js nats.JetStreamContext) error {
+	if info, _ := js.StreamInfo(StreamName); info != nil {
+		return nil
+	}
+	_, err := js.AddStream(&nats.StreamConfig{
+		Name:     StreamName,
+		Subjects: []string{SubjectDeleted, SubjectProgress},
+	})
+	return err
+}
+
+// Publish announces that a user's data is to be erased.
+func Publish(js nats.JetStreamContext, e *Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = js.Publish(SubjectDeleted, data)
+	return err
+}
+
+// Subscribe runs handler for every deletion on a durable consumer named after
+// service and reports each outcome on SubjectProgress. Failed attempts are
+// reported too and retried after a delay.
+func Subscribe(ctx context.Context, js nats.JetStreamContext, service string, handler Handler, log zerolog.Logger) error {
+	if err := EnsureStream(js); err != nil {
+		return fmt.Errorf("ensure %s stream: %w", StreamName, err)
+	}
+
+	_, err := js.Subscribe(SubjectDeleted, func(m *nats.Msg) {
+		var e Event
+		if err := json.Unmarshal(m.Data, &e); err != nil || e.UserID == "" {
+			log.Error().Err(err).Msg("dropping malformed user.deleted event")
+			_ = m.Term()
+			return
+		}
+
+		hctx, cancel := context.WithTimeout(ctx, handlerTimeout)
+		err := handler(hctx, &e)
+		cancel()
+
+		p := &Progress{DeletionID: e.DeletionID, UserID: e.UserID, Service: service, Status: StatusCompleted, At: time.Now()}
+		if err != nil {
+			log.Error().Err(err).Str("deletion_id", e.DeletionID).Str("user_id", e.UserID).Msg("account data erasure failed, will retry")
+			p.Status, p.Error = StatusFailed, err.Error()
+			_ = report(js, p)
+			_ = m.NakWithDelay(retryDelay)
+			return
+		}
+		if err := report(js, p); err != nil {
+			// The erasure is repeated rather than left unreported.
+			log.Error().Err(err).Str("deletion_id", e.DeletionID).Msg("failed to report account erasure")
+			_ = m.NakWithDelay(retryDelay)
+			return
+		}
+		log.Info().Str("deletion_id", e.DeletionID).Str("user_id", e.UserID).Msg("account data erased")
+		_ = m.Ack()
+	}, nats.Durable(service+"-user-deleted"), nats.ManualAck(), nats.AckWait(handlerTimeout+30*time.Second))
+	if err != nil {
+		return fmt.Errorf("subscribe to %s: %w", SubjectDeleted, err)
+	}
+	return nil
+}
+
+func report(js nats.JetStreamContext, p *Progress) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	_, err = js.Publish(SubjectProgress, data)
+	return err
+}
@@ -0,0 +1,92 @@
+// Package deviceevents tells other services about devices that were logged
+// out. Auth-service publishes a Revoked event on SubjectRevoked whenever a
+// device is revoked, and services that keep per-device state, such as the
+// encryption keys in user-service, drop it.
+package deviceevents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog"
+)
+
+const (
+	StreamName     = "DEVICES"
+	SubjectRevoked = "device.revoked"
+)
+
+// retryDelay is how long a failed handler waits before the event is
+// redelivered.
+const retryDelay = 30 * time.Second
+
+// handlerTimeout bounds one handler attempt.
+const handlerTimeout = 30 * time.Second
+
+// Revoked reports that a user's linked device was logged out.
+type Revoked struct {
+	UserID    string    `json:"user_id"`
+	DeviceID  string    `json:"device_id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// Handler drops one service's state for the device in e. It must be
+// idempotent: the event is redelivered until the handler succeeds, and a
+// device may be reported more than once.
+type Handler func(ctx context.Context, e *Revoked) error
+
+// EnsureStream creates the DEVICES stream if it does not already exist.
+func EnsureStream(js nats.JetStreamContext) error {
+	if info, _ := js.StreamInfo(StreamName); info != nil {
+		return nil
+	}
+	_, err := js.AddStream(&nats.StreamConfig{
+		Name:     StreamName,
+		Subjects: []string{SubjectRevoked},
+	})
+	return err
+}
+
+// Publish announces that a device was logged out.
+func Publish(js nats.JetStreamContext, e *Revoked) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = js.Publish(SubjectRevoked, data)
+	return err
+}
+
+// Subscribe runs handler for every revoked device on a durable consumer
+// named after service. Failed attempts are retried after a delay.
+func Subscribe(ctx context.Context, js nats.JetStreamContext, service string, handler Handler, log zerolog.Logger) error {
+	if err := EnsureStream(js); err != nil {
+		return fmt.Errorf("ensure %s stream: %w", StreamName, err)
+	}
+
+	_, err := js.Subscribe(SubjectRevoked, func(m *nats.Msg) {
+		var e Revoked
+		if err := json.Unmarshal(m.Data, &e); err != nil || e.UserID == "" || e.DeviceID == "" {
+			log.Error().Err(err).Msg("dropping malformed device.revoked event")
+			_ = m.Term()
+			return
+		}
+
+		hctx, cancel := context.WithTimeout(ctx, handlerTimeout)
+		err := handler(hctx, &e)
+		cancel()
+		if err != nil {
+			log.Error().Err(err).Str("user_id", e.UserID).Str("device_id", e.DeviceID).Msg("failed to handle revoked device, will retry")
+			_ = m.NakWithDelay(retryDelay)
+			return
+		}
+		_ = m.Ack()
+	}, nats.Durable(service+"-device-revoked"), nats.ManualAck(), nats.AckWait(handlerTimeout+30*time.Second))
+	if err != nil {
+		return fmt.Errorf("subscribe to %s: %w", SubjectRevoked, err)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package e2ee
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// device holds the private keys of one side of a test session.
+type device struct {
+	id  *Identity
+	spk *SignedPreKey
+	opk *PreKey
+}
+
+func newDevice(t *testing.T) *device {
+	t.Helper()
+	id, err := GenerateIdentity()
+	require.NoError(t, err)
+	spk, err := GenerateSignedPreKey(id, 7)
+	require.NoError(t, err)
+	opks, err := GeneratePreKeys(11, 1)
+	require.NoError(t, err)
+	return &device{id: id, spk: spk, opk: opks[0]}
+}
+
+func (d *device) bundle(withOPK bool) *Bundle {
+	b := &Bundle{
+		IdentityKey:           d.id.PublicKey(),
+		SigningKey:            d.id.SigningKey(),
+		SignedPreKeyID:        d.spk.ID,
+		SignedPreKey:          d.spk.PublicKey(),
+		SignedPreKeySignature: d.spk.Signature,
+	}
+	if withOPK {
+		b.OneTimePreKeyID = d.opk.ID
+		b.OneTimePreKey = d.opk.PublicKey()
+	}
+	return b
+}
+
+// establish runs X3DH from alice to bob and has bob accept the first message.
+func establish(t *testing.T, alice, bob *device, withOPK bool) (aliceSession, bobSession *Session) {
+	t.Helper()
+	aliceSession, err := InitiateSession(alice.id, bob.bundle(withOPK))
+	require.NoError(t, err)
+
+	first, err := aliceSession.Encrypt([]byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, EnvelopePreKey, first.Type())
+
+	var opk *PreKey
+	if withOPK {
+		opk = bob.opk
+	}
+	bobSession, err = AcceptSession(bob.id, bob.spk, opk, first)
+	require.NoError(t, err)
+	pt, err := bobSession.Decrypt(first)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(pt))
+	return aliceSession, bobSession
+}
+
+func TestSignedPreKey(t *testing.T) {
+	d := newDevice(t)
+	require.NoError(t, VerifySignedPreKey(d.id.SigningKey(), d.spk.PublicKey(), d.spk.Signature))
+
+	other := newDevice(t)
+	assert.ErrorIs(t, VerifySignedPreKey(other.id.SigningKey(), d.spk.PublicKey(), d.spk.Signature), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignedPreKey(d.id.SigningKey(), d.spk.PublicKey(), d.spk.Signature[1:]), ErrInvalidKey)
+
+	_, err := InitiateSession(other.id, &Bundle{
+		IdentityKey:           d.id.PublicKey(),
+		SigningKey:            other.id.SigningKey(),
+		SignedPreKeyID:        d.spk.ID,
+		SignedPreKey:          d.spk.PublicKey(),
+		SignedPreKeySignature: d.spk.Signature,
+	})
+	assert.ErrorIs(t, err, ErrInvalidSignature, "bundles with a forged signed prekey are refused")
+}
+
+func TestGeneratePreKeys(t *testing.T) {
+	keys, err := GeneratePreKeys(5, 3)
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+	for i, k := range keys {
+		assert.Equal(t, uint32(5+i), k.ID)
+		assert.NoError(t, ValidatePublicKey(k.PublicKey()))
+	}
+	assert.ErrorIs(t, ValidatePublicKey([]byte("short")), ErrInvalidKey)
+}
+
+func TestSession_Conversation(t *testing.T) {
+	for name, withOPK := range map[string]bool{"with one-time prekey": true, "without one-time prekey": false} {
+		t.Run(name, func(t *testing.T) {
+			alice, bob := newDevice(t), newDevice(t)
+			aliceSession, bobSession := establish(t, alice, bob, withOPK)
+
+			reply, err := bobSession.Encrypt([]byte("hi alice"))
+			require.NoError(t, err)
+			assert.Equal(t, EnvelopeMessage, reply.Type())
+			pt, err := aliceSession.Decrypt(reply)
+			require.NoError(t, err)
+			assert.Equal(t, "hi alice", string(pt))
+
+			// The prekey header is dropped once the recipient has answered.
+			next, err := aliceSession.Encrypt([]byte("how are you"))
+			require.NoError(t, err)
+			assert.Equal(t, EnvelopeMessage, next.Type())
+			pt, err = bobSession.Decrypt(next)
+			require.NoError(t, err)
+			assert.Equal(t, "how are you", string(pt))
+		})
+	}
+}
+
+func TestSession_OutOfOrder(t *testing.T) {
+	alice, bob := newDevice(t), newDevice(t)
+	aliceSession, bobSession := establish(t, alice, bob, true)
+
+	var msgs []*Message
+	for _, text := range []string{"one", "two", "three"} {
+		m, err := aliceSession.Encrypt([]byte(text))
+		require.NoError(t, err)
+		msgs = append(msgs, m)
+	}
+	for _, i := range []int{2, 0, 1} {
+		pt, err := bobSession.Decrypt(msgs[i])
+		require.NoError(t, err)
+		assert.Equal(t, []string{"one", "two", "three"}[i], string(pt))
+	}
+
+	_, err := bobSession.Decrypt(msgs[0])
+	assert.ErrorIs(t, err, ErrDecrypt, "a message key is used only once")
+}
+
+func TestSession_TamperedMessageLeavesSessionIntact(t *testing.T) {
+	alice, bob := newDevice(t), newDevice(t)
+	aliceSession, bobSession := establish(t, alice, bob, false)
+
+	m, err := aliceSession.Encrypt([]byte("secret"))
+	require.NoError(t, err)
+
+	forged := *m
+	forged.Ciphertext = append([]byte(nil), m.Ciphertext...)
+	forged.Ciphertext[0] ^= 0xFF
+	_, err = bobSession.Decrypt(&forged)
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	pt, err := bobSession.Decrypt(m)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(pt))
+}
+
+func TestSession_TooManySkips(t *testing.T) {
+	alice, bob := newDevice(t), newDevice(t)
+	aliceSession, bobSession := establish(t, alice, bob, false)
+
+	m, err := aliceSession.Encrypt([]byte("far ahead"))
+	require.NoError(t, err)
+	m.Header.N += MaxSkip + 1
+	_, err = bobSession.Decrypt(m)
+	assert.ErrorIs(t, err, ErrTooManySkips)
+}
+
+func TestAcceptSession_PreKeyMismatch(t *testing.T) {
+	alice, bob := newDevice(t), newDevice(t)
+	aliceSession, err := InitiateSession(alice.id, bob.bundle(true))
+	require.NoError(t, err)
+	first, err := aliceSession.Encrypt([]byte("hello"))
+	require.NoError(t, err)
+
+	_, err = AcceptSession(bob.id, bob.spk, nil, first)
+	assert.ErrorIs(t, err, ErrPreKeyMismatch, "the one-time prekey the initiator used is required")
+
+	otherSPK, err := GenerateSignedPreKey(bob.id, bob.spk.ID+1)
+	require.NoError(t, err)
+	_, err = AcceptSession(bob.id, otherSPK, bob.opk, first)
+	assert.ErrorIs(t, err, ErrPreKeyMismatch)
+}
+
+func TestParseMessage(t *testing.T) {
+	alice, bob := newDevice(t), newDevice(t)
+	aliceSession, err := InitiateSession(alice.id, bob.bundle(true))
+	require.NoError(t, err)
+	m, err := aliceSession.Encrypt([]byte("hello"))
+	require.NoError(t, err)
+
+	data, err := m.Marshal()
+	require.NoError(t, err)
+	parsed, err := ParseMessage(data)
+	require.NoError(t, err)
+	assert.Equal(t, m, parsed)
+
+	for _, bad := range []string{`not json`, `{"header":{"dh":"AAAA"},"ciphertext":"AAAA"}`, `{"header":{}}`} {
+		_, err := ParseMessage([]byte(bad))
+		assert.ErrorIs(t, err, ErrMalformedMessage, bad)
+	}
+}
@@ -0,0 +1,133 @@
+// Package e2ee is a reference implementation of the end-to-end encryption
+// protocol clients speak: X3DH to agree on a session key from a prekey
+// bundle, and the Double Ratchet to encrypt messages within the session.
+// The server only stores public keys and relays ciphertext; this package
+// exists so that flow can be exercised and checked from Go.
+package e2ee
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+)
+
+const (
+	// KeySize is the length of an X25519 public key.
+	KeySize = 32
+	// SigningKeySize is the length of an Ed25519 public key.
+	SigningKeySize = ed25519.PublicKeySize
+	// SignatureSize is the length of a signed prekey signature.
+	SignatureSize = ed25519.SignatureSize
+)
+
+var (
+	ErrInvalidKey       = errors.New("e2ee: invalid public key")
+	ErrInvalidSignature = errors.New("e2ee: signed prekey signature does not verify")
+)
+
+// Identity is a device's long-term key pair. DH takes part in X3DH; Signing
+// signs the device's signed prekeys so peers can tell they are genuine.
+type Identity struct {
+	DH      *ecdh.PrivateKey
+	Signing ed25519.PrivateKey
+}
+
+// GenerateIdentity creates a new identity key pair.
+func GenerateIdentity() (*Identity, error) {
+	dh, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	_, signing, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{DH: dh, Signing: signing}, nil
+}
+
+// PublicKey returns the X25519 identity key published in the key directory.
+func (id *Identity) PublicKey() []byte {
+	return id.DH.PublicKey().Bytes()
+}
+
+// SigningKey returns the Ed25519 key that verifies signed prekeys.
+func (id *Identity) SigningKey() []byte {
+	return id.Signing.Public().(ed25519.PublicKey)
+}
+
+// PreKey is a medium-term (signed) or single-use (one-time) X25519 key.
+// Key IDs start at 1; 0 means "no key".
+type PreKey struct {
+	ID  uint32
+	Key *ecdh.PrivateKey
+}
+
+// PublicKey returns the prekey's public half.
+func (k *PreKey) PublicKey() []byte {
+	return k.Key.PublicKey().Bytes()
+}
+
+// SignedPreKey is a prekey signed by the device's identity.
+type SignedPreKey struct {
+	PreKey
+	Signature []byte
+}
+
+// GenerateSignedPreKey creates a prekey and signs it with id.
+func GenerateSignedPreKey(id *Identity, keyID uint32) (*SignedPreKey, error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedPreKey{
+		PreKey:    PreKey{ID: keyID, Key: key},
+		Signature: ed25519.Sign(id.Signing, key.PublicKey().Bytes()),
+	}, nil
+}
+
+// GeneratePreKeys creates n one-time prekeys with consecutive IDs from startID.
+func GeneratePreKeys(startID uint32, n int) ([]*PreKey, error) {
+	keys := make([]*PreKey, 0, n)
+	for i := 0; i < n; i++ {
+		key, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, &PreKey{ID: startID + uint32(i), Key: key})
+	}
+	return keys, nil
+}
+
+// VerifySignedPreKey checks that signature is signingKey's signature over
+// preKey. The key directory calls it on upload, and initiators call it
+// before trusting a bundle.
+func VerifySignedPreKey(signingKey, preKey, signature []byte) error {
+	if len(signingKey) != SigningKeySize || len(preKey) != KeySize || len(signature) != SignatureSize {
+		return ErrInvalidKey
+	}
+	if !ed25519.Verify(ed25519.PublicKey(signingKey), preKey, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ValidatePublicKey reports whether key is a usable X25519 public key.
+func ValidatePublicKey(key []byte) error {
+	if _, err := ecdh.X25519().NewPublicKey(key); err != nil {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+// Bundle is what the key directory hands out for one device of a user. The
+// one-time prekey is absent when the device has run out of them.
+type Bundle struct {
+	IdentityKey           []byte
+	SigningKey            []byte
+	SignedPreKeyID        uint32
+	SignedPreKey          []byte
+	SignedPreKeySignature []byte
+	OneTimePreKeyID       uint32
+	OneTimePreKey         []byte
+}
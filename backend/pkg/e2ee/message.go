@@ -0,0 +1,73 @@
+package e2ee
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+)
+
+// Envelope types, as carried in an encrypted message's envelopes.
+const (
+	EnvelopePreKey  = "prekey"
+	EnvelopeMessage = "message"
+)
+
+var ErrMalformedMessage = errors.New("e2ee: malformed message")
+
+// Header is the Double Ratchet header: the sender's current ratchet key, the
+// length of its previous sending chain and the message's number in the
+// current one.
+type Header struct {
+	DH []byte `json:"dh"`
+	PN uint32 `json:"pn"`
+	N  uint32 `json:"n"`
+}
+
+// bytes encodes the header for use as associated data.
+func (h Header) bytes() []byte {
+	b := make([]byte, 0, len(h.DH)+8)
+	b = append(b, h.DH...)
+	b = binary.BigEndian.AppendUint32(b, h.PN)
+	return binary.BigEndian.AppendUint32(b, h.N)
+}
+
+// PreKeyHeader carries the initiator's X3DH parameters on the messages of a
+// session the recipient has not answered yet.
+type PreKeyHeader struct {
+	IdentityKey     []byte `json:"identity_key"`
+	BaseKey         []byte `json:"base_key"`
+	SignedPreKeyID  uint32 `json:"signed_prekey_id"`
+	OneTimePreKeyID uint32 `json:"one_time_prekey_id,omitempty"`
+}
+
+// Message is one encrypted message for a single device.
+type Message struct {
+	PreKey     *PreKeyHeader `json:"prekey,omitempty"`
+	Header     Header        `json:"header"`
+	Ciphertext []byte        `json:"ciphertext"`
+}
+
+// Type returns the envelope type a message is sent as.
+func (m *Message) Type() string {
+	if m.PreKey != nil {
+		return EnvelopePreKey
+	}
+	return EnvelopeMessage
+}
+
+// Marshal encodes the message as the opaque ciphertext of an envelope.
+func (m *Message) Marshal() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// ParseMessage decodes an envelope's ciphertext.
+func ParseMessage(data []byte) (*Message, error) {
+	var m Message
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, ErrMalformedMessage
+	}
+	if len(m.Header.DH) != KeySize || len(m.Ciphertext) == 0 {
+		return nil, ErrMalformedMessage
+	}
+	return &m, nil
+}
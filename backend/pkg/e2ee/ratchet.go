@@ -0,0 +1,238 @@
+package e2ee
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"maps"
+)
+
+const (
+	ratchetInfo     = "WhatsAppClone Ratchet"
+	messageKeysInfo = "WhatsAppClone MessageKeys"
+
+	// MaxSkip bounds how many message keys one incoming message may make a
+	// session derive and keep for messages that have not arrived yet.
+	MaxSkip = 1000
+)
+
+var (
+	ErrDecrypt      = errors.New("e2ee: message failed to decrypt")
+	ErrTooManySkips = errors.New("e2ee: too many skipped messages")
+)
+
+type skippedKey struct {
+	dh string
+	n  uint32
+}
+
+// Session is one side of a Double Ratchet session with a single remote
+// device. It is not safe for concurrent use.
+type Session struct {
+	ad []byte
+
+	dhs *ecdh.PrivateKey
+	dhr *ecdh.PublicKey
+	rk  []byte
+	cks []byte
+	ckr []byte
+	ns  uint32
+	nr  uint32
+	pn  uint32
+
+	skipped map[skippedKey][]byte
+
+	// preKey is sent with every message until the first reply arrives.
+	preKey *PreKeyHeader
+}
+
+func newSenderSession(sk []byte, theirRatchetKey *ecdh.PublicKey, ad []byte) (*Session, error) {
+	dhs, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	s := &Session{ad: ad, dhs: dhs, dhr: theirRatchetKey, skipped: make(map[skippedKey][]byte)}
+	dh, err := dhs.ECDH(theirRatchetKey)
+	if err != nil {
+		return nil, err
+	}
+	if s.rk, s.cks, err = kdfRK(sk, dh); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func newReceiverSession(sk []byte, ourRatchetKey *ecdh.PrivateKey, ad []byte) *Session {
+	return &Session{ad: ad, dhs: ourRatchetKey, rk: sk, skipped: make(map[skippedKey][]byte)}
+}
+
+// Encrypt encrypts plaintext as the next message of the session.
+func (s *Session) Encrypt(plaintext []byte) (*Message, error) {
+	if s.cks == nil {
+		return nil, errors.New("e2ee: session cannot send before it has received a message")
+	}
+	var mk []byte
+	s.cks, mk = kdfCK(s.cks)
+	h := Header{DH: s.dhs.PublicKey().Bytes(), PN: s.pn, N: s.ns}
+	s.ns++
+
+	ct, err := seal(mk, plaintext, append(bytes.Clone(s.ad), h.bytes()...))
+	if err != nil {
+		return nil, err
+	}
+	return &Message{PreKey: s.preKey, Header: h, Ciphertext: ct}, nil
+}
+
+// Decrypt decrypts a message of the session. The session is left unchanged
+// when decryption fails, so forged or corrupted messages cannot desync it.
+func (s *Session) Decrypt(m *Message) ([]byte, error) {
+	backup := s.clone()
+	pt, err := s.decrypt(m)
+	if err != nil {
+		*s = *backup
+		return nil, err
+	}
+	s.preKey = nil
+	return pt, nil
+}
+
+func (s *Session) decrypt(m *Message) ([]byte, error) {
+	ad := append(bytes.Clone(s.ad), m.Header.bytes()...)
+
+	key := skippedKey{dh: string(m.Header.DH), n: m.Header.N}
+	if mk, ok := s.skipped[key]; ok {
+		delete(s.skipped, key)
+		return open(mk, m.Ciphertext, ad)
+	}
+
+	if s.dhr == nil || !bytes.Equal(m.Header.DH, s.dhr.Bytes()) {
+		if err := s.skipTo(m.Header.PN); err != nil {
+			return nil, err
+		}
+		if err := s.ratchet(m.Header.DH); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.skipTo(m.Header.N); err != nil {
+		return nil, err
+	}
+
+	var mk []byte
+	s.ckr, mk = kdfCK(s.ckr)
+	s.nr++
+	return open(mk, m.Ciphertext, ad)
+}
+
+// skipTo stores the message keys of the current receiving chain up to n.
+func (s *Session) skipTo(n uint32) error {
+	if s.ckr == nil {
+		return nil
+	}
+	if n > s.nr+MaxSkip || len(s.skipped)+int(n-min(n, s.nr)) > MaxSkip {
+		return ErrTooManySkips
+	}
+	for s.nr < n {
+		var mk []byte
+		s.ckr, mk = kdfCK(s.ckr)
+		s.skipped[skippedKey{dh: string(s.dhr.Bytes()), n: s.nr}] = mk
+		s.nr++
+	}
+	return nil
+}
+
+// ratchet performs a DH ratchet step on a new ratchet key from the peer.
+func (s *Session) ratchet(theirKey []byte) error {
+	dhr, err := ecdh.X25519().NewPublicKey(theirKey)
+	if err != nil {
+		return ErrInvalidKey
+	}
+	s.pn, s.ns, s.nr = s.ns, 0, 0
+	s.dhr = dhr
+
+	dh, err := s.dhs.ECDH(dhr)
+	if err != nil {
+		return err
+	}
+	if s.rk, s.ckr, err = kdfRK(s.rk, dh); err != nil {
+		return err
+	}
+	if s.dhs, err = ecdh.X25519().GenerateKey(rand.Reader); err != nil {
+		return err
+	}
+	if dh, err = s.dhs.ECDH(dhr); err != nil {
+		return err
+	}
+	s.rk, s.cks, err = kdfRK(s.rk, dh)
+	return err
+}
+
+func (s *Session) clone() *Session {
+	c := *s
+	c.skipped = maps.Clone(s.skipped)
+	return &c
+}
+
+// kdfRK advances the root chain with a DH output, yielding a new root key
+// and chain key.
+func kdfRK(rk, dh []byte) ([]byte, []byte, error) {
+	out, err := hkdf.Key(sha256.New, dh, rk, ratchetInfo, 64)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out[:32], out[32:], nil
+}
+
+// kdfCK advances a sending or receiving chain, yielding the next chain key
+// and a message key.
+func kdfCK(ck []byte) ([]byte, []byte) {
+	mac := hmac.New(sha256.New, ck)
+	mac.Write([]byte{0x02})
+	next := mac.Sum(nil)
+	mac.Reset()
+	mac.Write([]byte{0x01})
+	return next, mac.Sum(nil)
+}
+
+// messageCipher expands a message key into an AES-256-GCM key and nonce.
+// Every message key is used once, so the derived nonce never repeats.
+func messageCipher(mk []byte) (cipher.AEAD, []byte, error) {
+	keys, err := hkdf.Key(sha256.New, mk, make([]byte, 32), messageKeysInfo, 32+12)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, err := aes.NewCipher(keys[:32])
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, keys[32:], nil
+}
+
+func seal(mk, plaintext, ad []byte) ([]byte, error) {
+	aead, nonce, err := messageCipher(mk)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nil, nonce, plaintext, ad), nil
+}
+
+func open(mk, ciphertext, ad []byte) ([]byte, error) {
+	aead, nonce, err := messageCipher(mk)
+	if err != nil {
+		return nil, err
+	}
+	pt, err := aead.Open(nil, nonce, ciphertext, ad)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return pt, nil
+}
@@ -0,0 +1,148 @@
+package e2ee
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+)
+
+const x3dhInfo = "WhatsAppClone X3DH"
+
+var ErrPreKeyMismatch = errors.New("e2ee: message was not addressed to this prekey")
+
+// x3dhSecret derives the shared secret from the X3DH DH outputs. Per the
+// X3DH spec the input is prefixed with 32 0xFF bytes and the salt is zero.
+func x3dhSecret(dhs ...[]byte) ([]byte, error) {
+	ikm := bytes.Repeat([]byte{0xFF}, 32)
+	for _, dh := range dhs {
+		ikm = append(ikm, dh...)
+	}
+	return hkdf.Key(sha256.New, ikm, make([]byte, 32), x3dhInfo, 32)
+}
+
+// InitiateSession runs X3DH against a recipient device's bundle and returns a
+// session ready to encrypt. Messages carry the X3DH parameters until the
+// recipient replies, so it can derive the same session from the first one
+// that arrives.
+func InitiateSession(id *Identity, b *Bundle) (*Session, error) {
+	if err := VerifySignedPreKey(b.SigningKey, b.SignedPreKey, b.SignedPreKeySignature); err != nil {
+		return nil, err
+	}
+	curve := ecdh.X25519()
+	theirIdentity, err := curve.NewPublicKey(b.IdentityKey)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	theirSPK, err := curve.NewPublicKey(b.SignedPreKey)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	ephemeral, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	dh1, err := id.DH.ECDH(theirSPK)
+	if err != nil {
+		return nil, err
+	}
+	dh2, err := ephemeral.ECDH(theirIdentity)
+	if err != nil {
+		return nil, err
+	}
+	dh3, err := ephemeral.ECDH(theirSPK)
+	if err != nil {
+		return nil, err
+	}
+	dhs := [][]byte{dh1, dh2, dh3}
+	if b.OneTimePreKeyID != 0 {
+		theirOPK, err := curve.NewPublicKey(b.OneTimePreKey)
+		if err != nil {
+			return nil, ErrInvalidKey
+		}
+		dh4, err := ephemeral.ECDH(theirOPK)
+		if err != nil {
+			return nil, err
+		}
+		dhs = append(dhs, dh4)
+	}
+
+	sk, err := x3dhSecret(dhs...)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := newSenderSession(sk, theirSPK, associatedData(id.PublicKey(), b.IdentityKey))
+	if err != nil {
+		return nil, err
+	}
+	s.preKey = &PreKeyHeader{
+		IdentityKey:     id.PublicKey(),
+		BaseKey:         ephemeral.PublicKey().Bytes(),
+		SignedPreKeyID:  b.SignedPreKeyID,
+		OneTimePreKeyID: b.OneTimePreKeyID,
+	}
+	return s, nil
+}
+
+// AcceptSession is the recipient's side of X3DH: it derives the session from
+// the first message of a new session. opk is the one-time prekey named by
+// m.PreKey.OneTimePreKeyID, or nil when none was used; the caller must delete
+// it afterwards. The returned session has not decrypted m yet.
+func AcceptSession(id *Identity, spk *SignedPreKey, opk *PreKey, m *Message) (*Session, error) {
+	h := m.PreKey
+	if h == nil || h.SignedPreKeyID != spk.ID {
+		return nil, ErrPreKeyMismatch
+	}
+	if (opk == nil) != (h.OneTimePreKeyID == 0) || (opk != nil && opk.ID != h.OneTimePreKeyID) {
+		return nil, ErrPreKeyMismatch
+	}
+
+	curve := ecdh.X25519()
+	theirIdentity, err := curve.NewPublicKey(h.IdentityKey)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	theirBase, err := curve.NewPublicKey(h.BaseKey)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	dh1, err := spk.Key.ECDH(theirIdentity)
+	if err != nil {
+		return nil, err
+	}
+	dh2, err := id.DH.ECDH(theirBase)
+	if err != nil {
+		return nil, err
+	}
+	dh3, err := spk.Key.ECDH(theirBase)
+	if err != nil {
+		return nil, err
+	}
+	dhs := [][]byte{dh1, dh2, dh3}
+	if opk != nil {
+		dh4, err := opk.Key.ECDH(theirBase)
+		if err != nil {
+			return nil, err
+		}
+		dhs = append(dhs, dh4)
+	}
+
+	sk, err := x3dhSecret(dhs...)
+	if err != nil {
+		return nil, err
+	}
+	return newReceiverSession(sk, spk.Key, associatedData(h.IdentityKey, id.PublicKey())), nil
+}
+
+// associatedData binds both identities to every message: initiator first.
+func associatedData(initiator, recipient []byte) []byte {
+	ad := make([]byte, 0, len(initiator)+len(recipient))
+	ad = append(ad, initiator...)
+	return append(ad, recipient...)
+}
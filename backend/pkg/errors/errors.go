@@ -3,26 +3,35 @@ package errors
 import "fmt"
 
 const (
-	CodeInternal         = "INTERNAL_ERROR"
-	CodeNotFound         = "NOT_FOUND"
-	CodeBadRequest       = "BAD_REQUEST"
-	CodeUnauthorized     = "UNAUTHORIZED"
-	CodeForbidden        = "FORBIDDEN"
-	CodeConflict         = "CONFLICT"
-	CodeTooManyRequests  = "TOO_MANY_REQUESTS"
-	CodeValidation       = "VALIDATION_ERROR"
-	CodeOTPExpired       = "OTP_EXPIRED"
-	CodeOTPInvalid       = "OTP_INVALID"
-	CodeOTPMaxAttempts   = "OTP_MAX_ATTEMPTS"
-	CodeTokenExpired     = "TOKEN_EXPIRED"
-	CodeTokenInvalid     = "TOKEN_INVALID"
-	CodeMediaTooLarge    = "MEDIA_TOO_LARGE"
-	CodeMediaInvalidType = "MEDIA_INVALID_TYPE"
-	CodeChatNotFound     = "CHAT_NOT_FOUND"
-	CodeNotChatMember    = "NOT_CHAT_MEMBER"
-	CodeNotAdmin         = "NOT_ADMIN"
-	CodeAlreadyMember    = "ALREADY_MEMBER"
-	CodeUserBlocked      = "USER_BLOCKED"
+	CodeInternal          = "INTERNAL_ERROR"
+	CodeNotFound          = "NOT_FOUND"
+	CodeBadRequest        = "BAD_REQUEST"
+	CodeUnauthorized      = "UNAUTHORIZED"
+	CodeForbidden         = "FORBIDDEN"
+	CodeConflict          = "CONFLICT"
+	CodeTooManyRequests   = "TOO_MANY_REQUESTS"
+	CodeValidation        = "VALIDATION_ERROR"
+	CodeOTPExpired        = "OTP_EXPIRED"
+	CodeOTPInvalid        = "OTP_INVALID"
+	CodeOTPMaxAttempts    = "OTP_MAX_ATTEMPTS"
+	CodeOTPResendTooSoon  = "OTP_RESEND_TOO_SOON"
+	CodeOTPDeliveryFailed = "OTP_DELIVERY_FAILED"
+	CodeTokenExpired      = "TOKEN_EXPIRED"
+	CodeTokenInvalid      = "TOKEN_INVALID"
+	CodeMediaTooLarge     = "MEDIA_TOO_LARGE"
+	CodeMediaInvalidType  = "MEDIA_INVALID_TYPE"
+	CodeChatNotFound      = "CHAT_NOT_FOUND"
+	CodeNotChatMember     = "NOT_CHAT_MEMBER"
+	CodeNotAdmin          = "NOT_ADMIN"
+	CodeAlreadyMember     = "ALREADY_MEMBER"
+	CodeUserBlocked       = "USER_BLOCKED"
+	CodeInviteLinkInvalid = "INVITE_LINK_INVALID"
+	CodeInvitationExpired = "INVITATION_EXPIRED"
+	CodePollClosed        = "POLL_CLOSED"
+	CodeLinkCodeInvalid   = "LINK_CODE_INVALID"
+	CodePINInvalid        = "PIN_INVALID"
+	CodePINLocked         = "PIN_LOCKED"
+	CodePINTokenInvalid   = "PIN_TOKEN_INVALID"
 )
 
 type AppError struct {
@@ -0,0 +1,118 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// JWK is a public key in JSON Web Key form (RFC 7517). Only the members of
+// RSA and Ed25519 (OKP) keys are modelled.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+var b64 = base64.RawURLEncoding
+
+// JWK returns the public half of k.
+func (k *Key) JWK() JWK {
+	j := publicJWK(k.public)
+	j.Use = "sig"
+	j.Alg = k.Alg
+	j.Kid = k.ID
+	return j
+}
+
+func publicJWK(pub crypto.PublicKey) JWK {
+	switch p := pub.(type) {
+	case *rsa.PublicKey:
+		return JWK{Kty: "RSA", N: b64.EncodeToString(p.N.Bytes()), E: b64.EncodeToString(big.NewInt(int64(p.E)).Bytes())}
+	case ed25519.PublicKey:
+		return JWK{Kty: "OKP", Crv: "Ed25519", X: b64.EncodeToString(p)}
+	}
+	return JWK{}
+}
+
+// thumbprint computes the RFC 7638 JWK thumbprint: the SHA-256 of the key's
+// required members in lexicographic order.
+func thumbprint(pub crypto.PublicKey) (string, error) {
+	j := publicJWK(pub)
+	var members interface{}
+	switch j.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{j.E, j.Kty, j.N}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{j.Crv, j.Kty, j.X}
+	default:
+		return "", fmt.Errorf("unsupported key type %T", pub)
+	}
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return b64.EncodeToString(sum[:]), nil
+}
+
+// Key converts the JWK back into a verification key. The kid must match the
+// key's thumbprint so a key cannot be published under another key's ID.
+func (j JWK) Key() (*Key, error) {
+	var pub interface{}
+	switch j.Kty {
+	case "RSA":
+		n, err := b64.DecodeString(j.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %s: bad modulus: %w", j.Kid, err)
+		}
+		e, err := b64.DecodeString(j.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("jwk %s: bad exponent", j.Kid)
+		}
+		pub = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	case "OKP":
+		x, err := b64.DecodeString(j.X)
+		if err != nil || j.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("jwk %s: not an Ed25519 key", j.Kid)
+		}
+		pub = ed25519.PublicKey(x)
+	default:
+		return nil, fmt.Errorf("jwk %s: unsupported key type %q", j.Kid, j.Kty)
+	}
+
+	k, err := NewKey(pub)
+	if err != nil {
+		return nil, fmt.Errorf("jwk %s: %w", j.Kid, err)
+	}
+	if j.Alg != "" && j.Alg != k.Alg {
+		return nil, fmt.Errorf("jwk %s: alg %s does not match the key", j.Kid, j.Alg)
+	}
+	if j.Kid != k.ID {
+		return nil, fmt.Errorf("jwk %s: kid is not the key's thumbprint", j.Kid)
+	}
+	return k, nil
+}
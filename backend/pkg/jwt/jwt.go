@@ -8,54 +8,101 @@ import (
 )
 
 var (
-	ErrTokenExpired  = errors.New("token has expired")
-	ErrTokenInvalid  = errors.New("token is invalid")
+	ErrTokenExpired   = errors.New("token has expired")
+	ErrTokenInvalid   = errors.New("token is invalid")
 	ErrTokenMalformed = errors.New("token is malformed")
 )
 
+// Issuer is the iss claim of access tokens.
+const Issuer = "whatsapp-auth"
+
+// RevokedDeviceKey is the Redis key auth-service sets, for the lifetime of
+// an access token, when a device is logged out. Services that validate
+// tokens themselves check it, as the device's tokens stay validly signed.
+func RevokedDeviceKey(deviceID string) string {
+	return "device:revoked:" + deviceID
+}
+
 type Claims struct {
 	jwtgo.RegisteredClaims
-	UserID string `json:"user_id"`
-	Phone  string `json:"phone"`
+	UserID   string `json:"user_id"`
+	Phone    string `json:"phone"`
+	DeviceID string `json:"device_id,omitempty"`
 }
 
 type Manager struct {
-	signingKey []byte
-	accessTTL  time.Duration
+	keys      *KeySet
+	accessTTL time.Duration
 }
 
-func NewManager(signingKey string, accessTTL time.Duration) *Manager {
+func NewManager(keys *KeySet, accessTTL time.Duration) *Manager {
 	return &Manager{
-		signingKey: []byte(signingKey),
-		accessTTL:  accessTTL,
+		keys:      keys,
+		accessTTL: accessTTL,
 	}
 }
 
-func (m *Manager) CreateAccessToken(userID, phone string) (string, error) {
+// CreateAccessToken issues an access token for a user's linked device.
+// deviceID may be empty for tokens that are not bound to a device.
+func (m *Manager) CreateAccessToken(userID, phone, deviceID string) (string, error) {
 	now := time.Now()
 	claims := Claims{
 		RegisteredClaims: jwtgo.RegisteredClaims{
 			ExpiresAt: jwtgo.NewNumericDate(now.Add(m.accessTTL)),
 			IssuedAt:  jwtgo.NewNumericDate(now),
-			Issuer:    "whatsapp-auth",
+			Issuer:    Issuer,
 		},
-		UserID: userID,
-		Phone:  phone,
+		UserID:   userID,
+		Phone:    phone,
+		DeviceID: deviceID,
 	}
-	token := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, claims)
-	return token.SignedString(m.signingKey)
+	key := m.keys.SigningKey()
+	token := jwtgo.NewWithClaims(jwtgo.GetSigningMethod(key.Alg), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.signer)
 }
 
 func (m *Manager) ValidateToken(tokenStr string) (*Claims, error) {
+	return parseToken(tokenStr, func(kid string) (*Key, error) {
+		return m.keys.Key(kid), nil
+	})
+}
+
+// JWKS returns the keys tokens are currently accepted from.
+func (m *Manager) JWKS() *JWKS {
+	return m.keys.JWKS()
+}
+
+// parseToken verifies tokenStr with the key its kid header names. The
+// token's alg must be the key's, so a public key is never used as an HMAC
+// secret.
+func parseToken(tokenStr string, lookup func(kid string) (*Key, error)) (*Claims, error) {
 	token, err := jwtgo.ParseWithClaims(tokenStr, &Claims{}, func(t *jwtgo.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwtgo.SigningMethodHMAC); !ok {
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
 			return nil, ErrTokenInvalid
 		}
-		return m.signingKey, nil
-	})
+		key, err := lookup(kid)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil || t.Method.Alg() != key.Alg {
+			return nil, ErrTokenInvalid
+		}
+		return key.public, nil
+	},
+		jwtgo.WithValidMethods([]string{AlgRS256, AlgEdDSA}),
+		jwtgo.WithIssuer(Issuer),
+		jwtgo.WithExpirationRequired(),
+	)
 	if err != nil {
-		if errors.Is(err, jwtgo.ErrTokenExpired) {
+		switch {
+		case errors.Is(err, jwtgo.ErrTokenExpired):
 			return nil, ErrTokenExpired
+		case errors.Is(err, jwtgo.ErrTokenMalformed):
+			return nil, ErrTokenMalformed
+		case errors.Is(err, ErrKeysUnavailable):
+			return nil, ErrKeysUnavailable
 		}
 		return nil, ErrTokenInvalid
 	}
@@ -0,0 +1,306 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEd25519Key(t *testing.T) *Key {
+	t.Helper()
+	k, err := GenerateEd25519Key()
+	require.NoError(t, err)
+	return k
+}
+
+func newRSAKey(t *testing.T, bits int) *rsa.PrivateKey {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, bits)
+	require.NoError(t, err)
+	return priv
+}
+
+func newManager(t *testing.T, ttl time.Duration, signing *Key, verify ...*Key) *Manager {
+	t.Helper()
+	ks, err := NewKeySet(signing, verify...)
+	require.NoError(t, err)
+	return NewManager(ks, ttl)
+}
+
+func tokenHeader(t *testing.T, token string) map[string]interface{} {
+	t.Helper()
+	parsed, _, err := jwtgo.NewParser().ParseUnverified(token, &Claims{})
+	require.NoError(t, err)
+	return parsed.Header
+}
+
+func TestManager_SignAndValidate(t *testing.T) {
+	rsaKey, err := NewKey(newRSAKey(t, 2048))
+	require.NoError(t, err)
+
+	for name, key := range map[string]*Key{"EdDSA": newEd25519Key(t), "RS256": rsaKey} {
+		t.Run(name, func(t *testing.T) {
+			m := newManager(t, time.Minute, key)
+			token, err := m.CreateAccessToken("user-1", "+15550001", "device-1")
+			require.NoError(t, err)
+
+			header := tokenHeader(t, token)
+			assert.Equal(t, name, header["alg"])
+			assert.Equal(t, key.ID, header["kid"])
+
+			claims, err := m.ValidateToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", claims.UserID)
+			assert.Equal(t, "+15550001", claims.Phone)
+			assert.Equal(t, "device-1", claims.DeviceID)
+			assert.Equal(t, Issuer, claims.Issuer)
+		})
+	}
+}
+
+func TestManager_Rotation(t *testing.T) {
+	oldKey, newKey := newEd25519Key(t), newEd25519Key(t)
+
+	oldToken, err := newManager(t, time.Minute, oldKey).CreateAccessToken("user-1", "", "")
+	require.NoError(t, err)
+
+	// Step 2 of a rotation: the new key signs, the old one only verifies.
+	rotated := newManager(t, time.Minute, newKey, oldKey)
+	_, err = rotated.ValidateToken(oldToken)
+	assert.NoError(t, err, "tokens of the previous key stay valid")
+
+	newToken, err := rotated.CreateAccessToken("user-1", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, newKey.ID, tokenHeader(t, newToken)["kid"])
+
+	kids := make([]string, 0, 2)
+	for _, k := range rotated.JWKS().Keys {
+		kids = append(kids, k.Kid)
+	}
+	assert.Equal(t, []string{newKey.ID, oldKey.ID}, kids, "the signing key is published first")
+
+	// Step 3: the old key is gone.
+	_, err = newManager(t, time.Minute, newKey).ValidateToken(oldToken)
+	assert.ErrorIs(t, err, ErrTokenInvalid)
+}
+
+func TestManager_RejectsBadTokens(t *testing.T) {
+	key := newEd25519Key(t)
+	m := newManager(t, time.Minute, key)
+
+	expired, err := newManager(t, -time.Minute, key).CreateAccessToken("user-1", "", "")
+	require.NoError(t, err)
+	_, err = m.ValidateToken(expired)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+
+	_, err = m.ValidateToken("not.a.token")
+	assert.ErrorIs(t, err, ErrTokenMalformed)
+
+	sign := func(method jwtgo.SigningMethod, secret interface{}, claims Claims, kid string) string {
+		t.Helper()
+		token := jwtgo.NewWithClaims(method, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		s, err := token.SignedString(secret)
+		require.NoError(t, err)
+		return s
+	}
+	valid := Claims{
+		RegisteredClaims: jwtgo.RegisteredClaims{
+			ExpiresAt: jwtgo.NewNumericDate(time.Now().Add(time.Minute)),
+			Issuer:    Issuer,
+		},
+		UserID: "user-1",
+	}
+	pub := []byte(key.public.(ed25519.PublicKey))
+
+	for name, token := range map[string]string{
+		"HS256 keyed with the public key": sign(jwtgo.SigningMethodHS256, pub, valid, key.ID),
+		"no kid":                          sign(jwtgo.SigningMethodEdDSA, key.signer, valid, ""),
+		"unknown kid":                     sign(jwtgo.SigningMethodEdDSA, newEd25519Key(t).signer, valid, "unknown"),
+		"other issuer": sign(jwtgo.SigningMethodEdDSA, key.signer, Claims{
+			RegisteredClaims: jwtgo.RegisteredClaims{ExpiresAt: valid.ExpiresAt, Issuer: "someone-else"},
+		}, key.ID),
+		"no expiry": sign(jwtgo.SigningMethodEdDSA, key.signer, Claims{
+			RegisteredClaims: jwtgo.RegisteredClaims{Issuer: Issuer},
+		}, key.ID),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := m.ValidateToken(token)
+			assert.ErrorIs(t, err, ErrTokenInvalid)
+		})
+	}
+}
+
+func TestNewKey(t *testing.T) {
+	_, err := NewKey(newRSAKey(t, 1024))
+	assert.Error(t, err, "short RSA keys are refused")
+
+	priv := newRSAKey(t, 2048)
+	fromPrivate, err := NewKey(priv)
+	require.NoError(t, err)
+	fromPublic, err := NewKey(&priv.PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, fromPrivate.ID, fromPublic.ID, "the kid depends on the public key only")
+	assert.False(t, fromPublic.CanSign())
+
+	_, err = NewKeySet(fromPublic)
+	assert.Error(t, err, "a public key cannot sign")
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+func TestLoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+	_, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edDER, err := x509.MarshalPKCS8PrivateKey(edPriv)
+	require.NoError(t, err)
+	rsaPriv := newRSAKey(t, 2048)
+	rsaPubDER, err := x509.MarshalPKIXPublicKey(&rsaPriv.PublicKey)
+	require.NoError(t, err)
+
+	signing := writePEM(t, dir, "signing.pem", "PRIVATE KEY", edDER)
+	verify := writePEM(t, dir, "verify.pem", "PUBLIC KEY", rsaPubDER)
+	rsaPKCS1 := writePEM(t, dir, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaPriv))
+
+	ks, err := LoadKeySet([]string{signing, verify})
+	require.NoError(t, err)
+	assert.Equal(t, AlgEdDSA, ks.SigningKey().Alg)
+	assert.Len(t, ks.JWKS().Keys, 2)
+
+	ks, err = LoadKeySet([]string{rsaPKCS1})
+	require.NoError(t, err)
+	assert.Equal(t, AlgRS256, ks.SigningKey().Alg)
+
+	_, err = LoadKeySet([]string{verify, signing})
+	assert.Error(t, err, "the first key must be a private key")
+	_, err = LoadKeySet(nil)
+	assert.Error(t, err)
+	_, err = LoadKeySet([]string{filepath.Join(dir, "missing.pem")})
+	assert.Error(t, err)
+}
+
+func TestJWK_RoundTrip(t *testing.T) {
+	rsaKey, err := NewKey(newRSAKey(t, 2048))
+	require.NoError(t, err)
+
+	for _, key := range []*Key{newEd25519Key(t), rsaKey} {
+		back, err := key.JWK().Key()
+		require.NoError(t, err)
+		assert.Equal(t, key.ID, back.ID)
+		assert.Equal(t, key.Alg, back.Alg)
+		assert.False(t, back.CanSign())
+	}
+
+	forged := newEd25519Key(t).JWK()
+	forged.Kid = rsaKey.ID
+	_, err = forged.Key()
+	assert.Error(t, err, "a key cannot be published under another key's kid")
+}
+
+// jwksServer serves the JWKS of whichever manager is current.
+type jwksServer struct {
+	*httptest.Server
+	current  atomic.Pointer[Manager]
+	failing  atomic.Bool
+	requests atomic.Int32
+}
+
+func newJWKSServer(t *testing.T, m *Manager) *jwksServer {
+	t.Helper()
+	s := &jwksServer{}
+	s.current.Store(m)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		if s.failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(s.current.Load().JWKS())
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestJWKSValidator_Rotation(t *testing.T) {
+	oldKey, newKey := newEd25519Key(t), newEd25519Key(t)
+	before := newManager(t, time.Minute, oldKey)
+	srv := newJWKSServer(t, before)
+
+	v := NewJWKSValidator(srv.URL, time.Hour)
+	require.NoError(t, v.Refresh(context.Background()))
+
+	oldToken, err := before.CreateAccessToken("user-1", "", "")
+	require.NoError(t, err)
+	claims, err := v.ValidateToken(context.Background(), oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+	assert.Equal(t, int32(1), srv.requests.Load(), "known keys are served from the cache")
+
+	// auth-service starts signing with a key the cache lacks.
+	after := newManager(t, time.Minute, newKey, oldKey)
+	srv.current.Store(after)
+	newToken, err := after.CreateAccessToken("user-2", "", "")
+	require.NoError(t, err)
+
+	_, err = v.ValidateToken(context.Background(), newToken)
+	assert.ErrorIs(t, err, ErrTokenInvalid, "unknown kids refetch at most every jwksMinRefetch")
+
+	v.mu.Lock()
+	v.lastAttempt = time.Now().Add(-jwksMinRefetch)
+	v.mu.Unlock()
+	claims, err = v.ValidateToken(context.Background(), newToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-2", claims.UserID)
+
+	_, err = v.ValidateToken(context.Background(), oldToken)
+	assert.NoError(t, err)
+}
+
+func TestJWKSValidator_Unavailable(t *testing.T) {
+	key := newEd25519Key(t)
+	m := newManager(t, time.Minute, key)
+	token, err := m.CreateAccessToken("user-1", "", "")
+	require.NoError(t, err)
+
+	srv := newJWKSServer(t, m)
+	srv.failing.Store(true)
+	v := NewJWKSValidator(srv.URL, time.Millisecond)
+	_, err = v.ValidateToken(context.Background(), token)
+	assert.ErrorIs(t, err, ErrKeysUnavailable, "nothing cached yet")
+
+	srv.failing.Store(false)
+	require.NoError(t, v.Refresh(context.Background()))
+
+	// Once the cache is stale and auth-service is down, the cached keys
+	// keep being used.
+	srv.failing.Store(true)
+	time.Sleep(5 * time.Millisecond)
+	v.mu.Lock()
+	v.lastAttempt = time.Now().Add(-jwksMinRefetch)
+	v.mu.Unlock()
+	_, err = v.ValidateToken(context.Background(), token)
+	assert.NoError(t, err)
+}
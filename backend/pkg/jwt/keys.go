@@ -0,0 +1,169 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Signing algorithms, as they appear in the token header and the JWKS.
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// minRSABits is the smallest RSA modulus accepted for signing or verifying.
+const minRSABits = 2048
+
+// Key is an RS256 or EdDSA key. Keys loaded from a private key can sign;
+// keys loaded from a public key or a JWKS can only verify.
+type Key struct {
+	ID     string
+	Alg    string
+	public crypto.PublicKey
+	signer crypto.Signer
+}
+
+// CanSign reports whether the key holds its private half.
+func (k *Key) CanSign() bool { return k.signer != nil }
+
+// NewKey wraps an RSA or Ed25519 private or public key. The key ID is its
+// RFC 7638 thumbprint, so every service derives the same ID for a key.
+func NewKey(key interface{}) (*Key, error) {
+	k := &Key{}
+	switch v := key.(type) {
+	case *rsa.PrivateKey:
+		k.signer, k.public = v, &v.PublicKey
+	case ed25519.PrivateKey:
+		k.signer, k.public = v, v.Public()
+	case *rsa.PublicKey, ed25519.PublicKey:
+		k.public = v
+	default:
+		return nil, fmt.Errorf("unsupported key type %T; use RSA or Ed25519", key)
+	}
+
+	switch pub := k.public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA key is %d bits; at least %d are required", pub.N.BitLen(), minRSABits)
+		}
+		k.Alg = AlgRS256
+	case ed25519.PublicKey:
+		k.Alg = AlgEdDSA
+	}
+
+	kid, err := thumbprint(k.public)
+	if err != nil {
+		return nil, err
+	}
+	k.ID = kid
+	return k, nil
+}
+
+// GenerateEd25519Key creates a fresh signing key.
+func GenerateEd25519Key() (*Key, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate ed25519 key: %w", err)
+	}
+	return NewKey(priv)
+}
+
+// ParseKeyPEM reads a PKCS#8, PKCS#1 or PKIX encoded private or public key.
+func ParseKeyPEM(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var (
+		key interface{}
+		err error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", strings.ToLower(block.Type), err)
+	}
+	return NewKey(key)
+}
+
+// KeySet holds the key new tokens are signed with and every key tokens are
+// still accepted from. Rotating keys without logging anyone out means
+// publishing the next key before signing with it, and keeping the old one
+// until the tokens it signed have expired.
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+	order   []*Key
+}
+
+// NewKeySet returns a key set that signs with signing and also accepts
+// tokens signed by any of verify.
+func NewKeySet(signing *Key, verify ...*Key) (*KeySet, error) {
+	if signing == nil || !signing.CanSign() {
+		return nil, errors.New("the signing key must be a private key")
+	}
+	ks := &KeySet{signing: signing, keys: make(map[string]*Key, len(verify)+1)}
+	for _, k := range append([]*Key{signing}, verify...) {
+		if _, dup := ks.keys[k.ID]; dup {
+			continue
+		}
+		ks.keys[k.ID] = k
+		ks.order = append(ks.order, k)
+	}
+	return ks, nil
+}
+
+// LoadKeySet reads PEM key files. The first must hold a private key and is
+// used for signing; the others, private or public, are only verified.
+func LoadKeySet(paths []string) (*KeySet, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no key files given")
+	}
+	keys := make([]*Key, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read key file: %w", err)
+		}
+		k, err := ParseKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("key file %s: %w", path, err)
+		}
+		keys = append(keys, k)
+	}
+	return NewKeySet(keys[0], keys[1:]...)
+}
+
+// SigningKey returns the key new tokens are signed with.
+func (ks *KeySet) SigningKey() *Key { return ks.signing }
+
+// Key returns the key with the given ID, or nil.
+func (ks *KeySet) Key(kid string) *Key { return ks.keys[kid] }
+
+// JWKS returns the public half of every key in the set.
+func (ks *KeySet) JWKS() *JWKS {
+	set := &JWKS{Keys: make([]JWK, 0, len(ks.order))}
+	for _, k := range ks.order {
+		set.Keys = append(set.Keys, k.JWK())
+	}
+	return set
+}
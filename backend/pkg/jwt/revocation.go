@@ -0,0 +1,55 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrDeviceRevoked means the token is validly signed but its device has been
+// logged out.
+var ErrDeviceRevoked = errors.New("device has been logged out")
+
+// RevocationChecker reports whether a device has been logged out, normally
+// by looking up RevokedDeviceKey in the Redis auth-service writes to.
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, deviceID string) (bool, error)
+}
+
+// RevocationCheckerFunc adapts a function to a RevocationChecker.
+type RevocationCheckerFunc func(ctx context.Context, deviceID string) (bool, error)
+
+func (f RevocationCheckerFunc) IsRevoked(ctx context.Context, deviceID string) (bool, error) {
+	return f(ctx, deviceID)
+}
+
+// LocalValidator checks tokens against auth-service's published keys instead
+// of calling it per request. Tokens of logged-out devices stay validly signed
+// until they expire, so they are also checked against revoked.
+type LocalValidator struct {
+	tokens  *JWKSValidator
+	revoked RevocationChecker
+}
+
+func NewLocalValidator(tokens *JWKSValidator, revoked RevocationChecker) *LocalValidator {
+	return &LocalValidator{tokens: tokens, revoked: revoked}
+}
+
+// ValidateToken returns the token's user and, for tokens bound to a linked
+// device, the device ID.
+func (v *LocalValidator) ValidateToken(ctx context.Context, token string) (userID, phone, deviceID string, err error) {
+	claims, err := v.tokens.ValidateToken(ctx, token)
+	if err != nil {
+		return "", "", "", err
+	}
+	if claims.DeviceID != "" {
+		revoked, err := v.revoked.IsRevoked(ctx, claims.DeviceID)
+		if err != nil {
+			return "", "", "", fmt.Errorf("check device revocation: %w", err)
+		}
+		if revoked {
+			return "", "", "", ErrDeviceRevoked
+		}
+	}
+	return claims.UserID, claims.Phone, claims.DeviceID, nil
+}
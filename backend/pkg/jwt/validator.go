@@ -0,0 +1,152 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrKeysUnavailable means a token could not be checked because the key set
+// could not be fetched.
+var ErrKeysUnavailable = errors.New("signing keys are unavailable")
+
+// jwksMinRefetch limits how often a token with an unknown kid can trigger a
+// fetch, so junk tokens cannot hammer auth-service.
+const jwksMinRefetch = 10 * time.Second
+
+// JWKSValidator validates access tokens locally against the key set
+// auth-service publishes. Keys are cached and refetched every refresh
+// interval, or sooner when a token names a key the cache lacks, e.g. right
+// after a rotation. If a refetch fails the cached keys keep being used.
+type JWKSValidator struct {
+	url        string
+	refresh    time.Duration
+	httpClient *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]*Key
+	fetchedAt   time.Time
+	lastAttempt time.Time
+
+	fetchMu sync.Mutex
+}
+
+func NewJWKSValidator(url string, refresh time.Duration) *JWKSValidator {
+	return &JWKSValidator{
+		url:        url,
+		refresh:    refresh,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// ValidateToken checks the token's signature, issuer and expiry.
+func (v *JWKSValidator) ValidateToken(ctx context.Context, tokenStr string) (*Claims, error) {
+	return parseToken(tokenStr, func(kid string) (*Key, error) {
+		return v.key(ctx, kid)
+	})
+}
+
+// Refresh fetches the key set now. Services call it at startup so the first
+// requests do not wait for the fetch.
+func (v *JWKSValidator) Refresh(ctx context.Context) error {
+	v.fetchMu.Lock()
+	defer v.fetchMu.Unlock()
+	return v.fetch(ctx)
+}
+
+func (v *JWKSValidator) key(ctx context.Context, kid string) (*Key, error) {
+	v.mu.RLock()
+	k, fresh := v.keys[kid], time.Since(v.fetchedAt) < v.refresh
+	v.mu.RUnlock()
+	if k != nil && fresh {
+		return k, nil
+	}
+
+	v.fetchMu.Lock()
+	defer v.fetchMu.Unlock()
+
+	// Another request may have fetched while this one waited.
+	v.mu.RLock()
+	k, fresh = v.keys[kid], time.Since(v.fetchedAt) < v.refresh
+	recent := time.Since(v.lastAttempt) < jwksMinRefetch
+	cached := v.keys != nil
+	v.mu.RUnlock()
+	if (k != nil && fresh) || recent {
+		if k == nil && !cached {
+			return nil, ErrKeysUnavailable
+		}
+		return k, nil
+	}
+
+	if err := v.fetch(ctx); err != nil {
+		if !cached {
+			return nil, err
+		}
+		// Fall back to the stale keys rather than rejecting every token
+		// while auth-service is unreachable.
+		return k, nil
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.keys[kid], nil
+}
+
+// fetch replaces the cached keys. The caller holds fetchMu.
+func (v *JWKSValidator) fetch(ctx context.Context) error {
+	v.mu.Lock()
+	v.lastAttempt = time.Now()
+	v.mu.Unlock()
+
+	keys, err := v.download(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrKeysUnavailable, err)
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.mu.Unlock()
+	return nil
+}
+
+func (v *JWKSValidator) download(ctx context.Context) (map[string]*Key, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create jwks request: %w", err)
+	}
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks: status %d", resp.StatusCode)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]*Key, len(set.Keys))
+	for _, j := range set.Keys {
+		if j.Use != "" && j.Use != "sig" {
+			continue
+		}
+		k, err := j.Key()
+		if err != nil {
+			return nil, err
+		}
+		keys[k.ID] = k
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks has no signing keys")
+	}
+	return keys, nil
+}
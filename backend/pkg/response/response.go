@@ -41,6 +41,10 @@ func Created(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusCreated, SuccessResponse{Success: true, Data: data})
 }
 
+func Accepted(c *gin.Context, data interface{}) {
+	c.JSON(http.StatusAccepted, SuccessResponse{Success: true, Data: data})
+}
+
 func NoContent(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
@@ -9,6 +9,7 @@ package chatv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -258,13 +259,15 @@ func (x *CheckChatPermissionRequest) GetUserId() string {
 }
 
 type CheckChatPermissionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	IsMember      bool                   `protobuf:"varint,1,opt,name=is_member,json=isMember,proto3" json:"is_member,omitempty"`
-	IsAdmin       bool                   `protobuf:"varint,2,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
-	IsAdminOnly   bool                   `protobuf:"varint,3,opt,name=is_admin_only,json=isAdminOnly,proto3" json:"is_admin_only,omitempty"` // true if only admins can send messages
-	ChatType      string                 `protobuf:"bytes,4,opt,name=chat_type,json=chatType,proto3" json:"chat_type,omitempty"`             // "direct" or "group"
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	IsMember          bool                   `protobuf:"varint,1,opt,name=is_member,json=isMember,proto3" json:"is_member,omitempty"`
+	IsAdmin           bool                   `protobuf:"varint,2,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
+	IsAdminOnly       bool                   `protobuf:"varint,3,opt,name=is_admin_only,json=isAdminOnly,proto3" json:"is_admin_only,omitempty"`                   // true if only admins can send messages
+	ChatType          string                 `protobuf:"bytes,4,opt,name=chat_type,json=chatType,proto3" json:"chat_type,omitempty"`                               // "direct" or "group"
+	AutoDeleteSeconds int64                  `protobuf:"varint,5,opt,name=auto_delete_seconds,json=autoDeleteSeconds,proto3" json:"auto_delete_seconds,omitempty"` // disappearing-messages timer, 0 = off
+	PeerUserId        string                 `protobuf:"bytes,6,opt,name=peer_user_id,json=peerUserId,proto3" json:"peer_user_id,omitempty"`                       // the other participant of a direct chat
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *CheckChatPermissionResponse) Reset() {
@@ -325,11 +328,390 @@ func (x *CheckChatPermissionResponse) GetChatType() string {
 	return ""
 }
 
+func (x *CheckChatPermissionResponse) GetAutoDeleteSeconds() int64 {
+	if x != nil {
+		return x.AutoDeleteSeconds
+	}
+	return 0
+}
+
+func (x *CheckChatPermissionResponse) GetPeerUserId() string {
+	if x != nil {
+		return x.PeerUserId
+	}
+	return ""
+}
+
+type GetUserChatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserChatsRequest) Reset() {
+	*x = GetUserChatsRequest{}
+	mi := &file_proto_chat_v1_chat_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserChatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserChatsRequest) ProtoMessage() {}
+
+func (x *GetUserChatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_v1_chat_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserChatsRequest.ProtoReflect.Descriptor instead.
+func (*GetUserChatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_v1_chat_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetUserChatsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetUserChatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatIds       []string               `protobuf:"bytes,1,rep,name=chat_ids,json=chatIds,proto3" json:"chat_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserChatsResponse) Reset() {
+	*x = GetUserChatsResponse{}
+	mi := &file_proto_chat_v1_chat_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserChatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserChatsResponse) ProtoMessage() {}
+
+func (x *GetUserChatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_v1_chat_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserChatsResponse.ProtoReflect.Descriptor instead.
+func (*GetUserChatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_v1_chat_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetUserChatsResponse) GetChatIds() []string {
+	if x != nil {
+		return x.ChatIds
+	}
+	return nil
+}
+
+type ListUserChatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserChatsRequest) Reset() {
+	*x = ListUserChatsRequest{}
+	mi := &file_proto_chat_v1_chat_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserChatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserChatsRequest) ProtoMessage() {}
+
+func (x *ListUserChatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_v1_chat_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserChatsRequest.ProtoReflect.Descriptor instead.
+func (*ListUserChatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_v1_chat_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListUserChatsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListUserChatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chats         []*ChatSummary         `protobuf:"bytes,1,rep,name=chats,proto3" json:"chats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserChatsResponse) Reset() {
+	*x = ListUserChatsResponse{}
+	mi := &file_proto_chat_v1_chat_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserChatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserChatsResponse) ProtoMessage() {}
+
+func (x *ListUserChatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_v1_chat_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserChatsResponse.ProtoReflect.Descriptor instead.
+func (*ListUserChatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_v1_chat_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListUserChatsResponse) GetChats() []*ChatSummary {
+	if x != nil {
+		return x.Chats
+	}
+	return nil
+}
+
+type GetChatSummaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChatSummaryRequest) Reset() {
+	*x = GetChatSummaryRequest{}
+	mi := &file_proto_chat_v1_chat_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChatSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChatSummaryRequest) ProtoMessage() {}
+
+func (x *GetChatSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_v1_chat_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChatSummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetChatSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_v1_chat_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetChatSummaryRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *GetChatSummaryRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetChatSummaryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chat          *ChatSummary           `protobuf:"bytes,1,opt,name=chat,proto3" json:"chat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChatSummaryResponse) Reset() {
+	*x = GetChatSummaryResponse{}
+	mi := &file_proto_chat_v1_chat_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChatSummaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChatSummaryResponse) ProtoMessage() {}
+
+func (x *GetChatSummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_v1_chat_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChatSummaryResponse.ProtoReflect.Descriptor instead.
+func (*GetChatSummaryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_v1_chat_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetChatSummaryResponse) GetChat() *ChatSummary {
+	if x != nil {
+		return x.Chat
+	}
+	return nil
+}
+
+// ChatSummary describes a chat from one member's point of view.
+type ChatSummary struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChatId         string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Type           string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // "direct" or "group"
+	Name           string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"` // group name, empty for direct chats
+	Role           string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"` // the member's role
+	ParticipantIds []string               `protobuf:"bytes,5,rep,name=participant_ids,json=participantIds,proto3" json:"participant_ids,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	JoinedAt       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=joined_at,json=joinedAt,proto3" json:"joined_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ChatSummary) Reset() {
+	*x = ChatSummary{}
+	mi := &file_proto_chat_v1_chat_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatSummary) ProtoMessage() {}
+
+func (x *ChatSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_v1_chat_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatSummary.ProtoReflect.Descriptor instead.
+func (*ChatSummary) Descriptor() ([]byte, []int) {
+	return file_proto_chat_v1_chat_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ChatSummary) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ChatSummary) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ChatSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ChatSummary) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ChatSummary) GetParticipantIds() []string {
+	if x != nil {
+		return x.ParticipantIds
+	}
+	return nil
+}
+
+func (x *ChatSummary) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ChatSummary) GetJoinedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.JoinedAt
+	}
+	return nil
+}
+
 var File_proto_chat_v1_chat_proto protoreflect.FileDescriptor
 
 const file_proto_chat_v1_chat_proto_rawDesc = "" +
 	"\n" +
-	"\x18proto/chat/v1/chat.proto\x12\achat.v1\"5\n" +
+	"\x18proto/chat/v1/chat.proto\x12\achat.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"5\n" +
 	"\x1aGetChatParticipantsRequest\x12\x17\n" +
 	"\achat_id\x18\x01 \x01(\tR\x06chatId\"8\n" +
 	"\x1bGetChatParticipantsResponse\x12\x19\n" +
@@ -341,16 +723,44 @@ const file_proto_chat_v1_chat_proto_rawDesc = "" +
 	"\tis_member\x18\x01 \x01(\bR\bisMember\"N\n" +
 	"\x1aCheckChatPermissionRequest\x12\x17\n" +
 	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x96\x01\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xe8\x01\n" +
 	"\x1bCheckChatPermissionResponse\x12\x1b\n" +
 	"\tis_member\x18\x01 \x01(\bR\bisMember\x12\x19\n" +
 	"\bis_admin\x18\x02 \x01(\bR\aisAdmin\x12\"\n" +
 	"\ris_admin_only\x18\x03 \x01(\bR\visAdminOnly\x12\x1b\n" +
-	"\tchat_type\x18\x04 \x01(\tR\bchatType2\x92\x02\n" +
+	"\tchat_type\x18\x04 \x01(\tR\bchatType\x12.\n" +
+	"\x13auto_delete_seconds\x18\x05 \x01(\x03R\x11autoDeleteSeconds\x12 \n" +
+	"\fpeer_user_id\x18\x06 \x01(\tR\n" +
+	"peerUserId\".\n" +
+	"\x13GetUserChatsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"1\n" +
+	"\x14GetUserChatsResponse\x12\x19\n" +
+	"\bchat_ids\x18\x01 \x03(\tR\achatIds\"/\n" +
+	"\x14ListUserChatsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"C\n" +
+	"\x15ListUserChatsResponse\x12*\n" +
+	"\x05chats\x18\x01 \x03(\v2\x14.chat.v1.ChatSummaryR\x05chats\"I\n" +
+	"\x15GetChatSummaryRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"B\n" +
+	"\x16GetChatSummaryResponse\x12(\n" +
+	"\x04chat\x18\x01 \x01(\v2\x14.chat.v1.ChatSummaryR\x04chat\"\xff\x01\n" +
+	"\vChatSummary\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12'\n" +
+	"\x0fparticipant_ids\x18\x05 \x03(\tR\x0eparticipantIds\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x127\n" +
+	"\tjoined_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bjoinedAt2\x82\x04\n" +
 	"\vChatService\x12`\n" +
 	"\x13GetChatParticipants\x12#.chat.v1.GetChatParticipantsRequest\x1a$.chat.v1.GetChatParticipantsResponse\x12?\n" +
 	"\bIsMember\x12\x18.chat.v1.IsMemberRequest\x1a\x19.chat.v1.IsMemberResponse\x12`\n" +
-	"\x13CheckChatPermission\x12#.chat.v1.CheckChatPermissionRequest\x1a$.chat.v1.CheckChatPermissionResponseB8Z6github.com/whatsapp-clone/backend/proto/chat/v1;chatv1b\x06proto3"
+	"\x13CheckChatPermission\x12#.chat.v1.CheckChatPermissionRequest\x1a$.chat.v1.CheckChatPermissionResponse\x12K\n" +
+	"\fGetUserChats\x12\x1c.chat.v1.GetUserChatsRequest\x1a\x1d.chat.v1.GetUserChatsResponse\x12N\n" +
+	"\rListUserChats\x12\x1d.chat.v1.ListUserChatsRequest\x1a\x1e.chat.v1.ListUserChatsResponse\x12Q\n" +
+	"\x0eGetChatSummary\x12\x1e.chat.v1.GetChatSummaryRequest\x1a\x1f.chat.v1.GetChatSummaryResponseB8Z6github.com/whatsapp-clone/backend/proto/chat/v1;chatv1b\x06proto3"
 
 var (
 	file_proto_chat_v1_chat_proto_rawDescOnce sync.Once
@@ -364,7 +774,7 @@ func file_proto_chat_v1_chat_proto_rawDescGZIP() []byte {
 	return file_proto_chat_v1_chat_proto_rawDescData
 }
 
-var file_proto_chat_v1_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_chat_v1_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_proto_chat_v1_chat_proto_goTypes = []any{
 	(*GetChatParticipantsRequest)(nil),  // 0: chat.v1.GetChatParticipantsRequest
 	(*GetChatParticipantsResponse)(nil), // 1: chat.v1.GetChatParticipantsResponse
@@ -372,19 +782,37 @@ var file_proto_chat_v1_chat_proto_goTypes = []any{
 	(*IsMemberResponse)(nil),            // 3: chat.v1.IsMemberResponse
 	(*CheckChatPermissionRequest)(nil),  // 4: chat.v1.CheckChatPermissionRequest
 	(*CheckChatPermissionResponse)(nil), // 5: chat.v1.CheckChatPermissionResponse
+	(*GetUserChatsRequest)(nil),         // 6: chat.v1.GetUserChatsRequest
+	(*GetUserChatsResponse)(nil),        // 7: chat.v1.GetUserChatsResponse
+	(*ListUserChatsRequest)(nil),        // 8: chat.v1.ListUserChatsRequest
+	(*ListUserChatsResponse)(nil),       // 9: chat.v1.ListUserChatsResponse
+	(*GetChatSummaryRequest)(nil),       // 10: chat.v1.GetChatSummaryRequest
+	(*GetChatSummaryResponse)(nil),      // 11: chat.v1.GetChatSummaryResponse
+	(*ChatSummary)(nil),                 // 12: chat.v1.ChatSummary
+	(*timestamppb.Timestamp)(nil),       // 13: google.protobuf.Timestamp
 }
 var file_proto_chat_v1_chat_proto_depIdxs = []int32{
-	0, // 0: chat.v1.ChatService.GetChatParticipants:input_type -> chat.v1.GetChatParticipantsRequest
-	2, // 1: chat.v1.ChatService.IsMember:input_type -> chat.v1.IsMemberRequest
-	4, // 2: chat.v1.ChatService.CheckChatPermission:input_type -> chat.v1.CheckChatPermissionRequest
-	1, // 3: chat.v1.ChatService.GetChatParticipants:output_type -> chat.v1.GetChatParticipantsResponse
-	3, // 4: chat.v1.ChatService.IsMember:output_type -> chat.v1.IsMemberResponse
-	5, // 5: chat.v1.ChatService.CheckChatPermission:output_type -> chat.v1.CheckChatPermissionResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	12, // 0: chat.v1.ListUserChatsResponse.chats:type_name -> chat.v1.ChatSummary
+	12, // 1: chat.v1.GetChatSummaryResponse.chat:type_name -> chat.v1.ChatSummary
+	13, // 2: chat.v1.ChatSummary.created_at:type_name -> google.protobuf.Timestamp
+	13, // 3: chat.v1.ChatSummary.joined_at:type_name -> google.protobuf.Timestamp
+	0,  // 4: chat.v1.ChatService.GetChatParticipants:input_type -> chat.v1.GetChatParticipantsRequest
+	2,  // 5: chat.v1.ChatService.IsMember:input_type -> chat.v1.IsMemberRequest
+	4,  // 6: chat.v1.ChatService.CheckChatPermission:input_type -> chat.v1.CheckChatPermissionRequest
+	6,  // 7: chat.v1.ChatService.GetUserChats:input_type -> chat.v1.GetUserChatsRequest
+	8,  // 8: chat.v1.ChatService.ListUserChats:input_type -> chat.v1.ListUserChatsRequest
+	10, // 9: chat.v1.ChatService.GetChatSummary:input_type -> chat.v1.GetChatSummaryRequest
+	1,  // 10: chat.v1.ChatService.GetChatParticipants:output_type -> chat.v1.GetChatParticipantsResponse
+	3,  // 11: chat.v1.ChatService.IsMember:output_type -> chat.v1.IsMemberResponse
+	5,  // 12: chat.v1.ChatService.CheckChatPermission:output_type -> chat.v1.CheckChatPermissionResponse
+	7,  // 13: chat.v1.ChatService.GetUserChats:output_type -> chat.v1.GetUserChatsResponse
+	9,  // 14: chat.v1.ChatService.ListUserChats:output_type -> chat.v1.ListUserChatsResponse
+	11, // 15: chat.v1.ChatService.GetChatSummary:output_type -> chat.v1.GetChatSummaryResponse
+	10, // [10:16] is the sub-list for method output_type
+	4,  // [4:10] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_proto_chat_v1_chat_proto_init() }
@@ -398,7 +826,7 @@ func file_proto_chat_v1_chat_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_chat_v1_chat_proto_rawDesc), len(file_proto_chat_v1_chat_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,6 +22,9 @@ const (
 	ChatService_GetChatParticipants_FullMethodName = "/chat.v1.ChatService/GetChatParticipants"
 	ChatService_IsMember_FullMethodName            = "/chat.v1.ChatService/IsMember"
 	ChatService_CheckChatPermission_FullMethodName = "/chat.v1.ChatService/CheckChatPermission"
+	ChatService_GetUserChats_FullMethodName        = "/chat.v1.ChatService/GetUserChats"
+	ChatService_ListUserChats_FullMethodName       = "/chat.v1.ChatService/ListUserChats"
+	ChatService_GetChatSummary_FullMethodName      = "/chat.v1.ChatService/GetChatSummary"
 )
 
 // ChatServiceClient is the client API for ChatService service.
@@ -31,6 +34,9 @@ type ChatServiceClient interface {
 	GetChatParticipants(ctx context.Context, in *GetChatParticipantsRequest, opts ...grpc.CallOption) (*GetChatParticipantsResponse, error)
 	IsMember(ctx context.Context, in *IsMemberRequest, opts ...grpc.CallOption) (*IsMemberResponse, error)
 	CheckChatPermission(ctx context.Context, in *CheckChatPermissionRequest, opts ...grpc.CallOption) (*CheckChatPermissionResponse, error)
+	GetUserChats(ctx context.Context, in *GetUserChatsRequest, opts ...grpc.CallOption) (*GetUserChatsResponse, error)
+	ListUserChats(ctx context.Context, in *ListUserChatsRequest, opts ...grpc.CallOption) (*ListUserChatsResponse, error)
+	GetChatSummary(ctx context.Context, in *GetChatSummaryRequest, opts ...grpc.CallOption) (*GetChatSummaryResponse, error)
 }
 
 type chatServiceClient struct {
@@ -71,6 +77,36 @@ func (c *chatServiceClient) CheckChatPermission(ctx context.Context, in *CheckCh
 	return out, nil
 }
 
+func (c *chatServiceClient) GetUserChats(ctx context.Context, in *GetUserChatsRequest, opts ...grpc.CallOption) (*GetUserChatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserChatsResponse)
+	err := c.cc.Invoke(ctx, ChatService_GetUserChats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ListUserChats(ctx context.Context, in *ListUserChatsRequest, opts ...grpc.CallOption) (*ListUserChatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUserChatsResponse)
+	err := c.cc.Invoke(ctx, ChatService_ListUserChats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) GetChatSummary(ctx context.Context, in *GetChatSummaryRequest, opts ...grpc.CallOption) (*GetChatSummaryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetChatSummaryResponse)
+	err := c.cc.Invoke(ctx, ChatService_GetChatSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ChatServiceServer is the server API for ChatService service.
 // All implementations must embed UnimplementedChatServiceServer
 // for forward compatibility.
@@ -78,6 +114,9 @@ type ChatServiceServer interface {
 	GetChatParticipants(context.Context, *GetChatParticipantsRequest) (*GetChatParticipantsResponse, error)
 	IsMember(context.Context, *IsMemberRequest) (*IsMemberResponse, error)
 	CheckChatPermission(context.Context, *CheckChatPermissionRequest) (*CheckChatPermissionResponse, error)
+	GetUserChats(context.Context, *GetUserChatsRequest) (*GetUserChatsResponse, error)
+	ListUserChats(context.Context, *ListUserChatsRequest) (*ListUserChatsResponse, error)
+	GetChatSummary(context.Context, *GetChatSummaryRequest) (*GetChatSummaryResponse, error)
 	mustEmbedUnimplementedChatServiceServer()
 }
 
@@ -97,6 +136,15 @@ func (UnimplementedChatServiceServer) IsMember(context.Context, *IsMemberRequest
 func (UnimplementedChatServiceServer) CheckChatPermission(context.Context, *CheckChatPermissionRequest) (*CheckChatPermissionResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CheckChatPermission not implemented")
 }
+func (UnimplementedChatServiceServer) GetUserChats(context.Context, *GetUserChatsRequest) (*GetUserChatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUserChats not implemented")
+}
+func (UnimplementedChatServiceServer) ListUserChats(context.Context, *ListUserChatsRequest) (*ListUserChatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListUserChats not implemented")
+}
+func (UnimplementedChatServiceServer) GetChatSummary(context.Context, *GetChatSummaryRequest) (*GetChatSummaryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetChatSummary not implemented")
+}
 func (UnimplementedChatServiceServer) mustEmbedUnimplementedChatServiceServer() {}
 func (UnimplementedChatServiceServer) testEmbeddedByValue()                     {}
 
@@ -172,6 +220,60 @@ func _ChatService_CheckChatPermission_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ChatService_GetUserChats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserChatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetUserChats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetUserChats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetUserChats(ctx, req.(*GetUserChatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ListUserChats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserChatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ListUserChats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ListUserChats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ListUserChats(ctx, req.(*ListUserChatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_GetChatSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChatSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetChatSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetChatSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetChatSummary(ctx, req.(*GetChatSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ChatService_ServiceDesc is the grpc.ServiceDesc for ChatService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,6 +293,18 @@ var ChatService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CheckChatPermission",
 			Handler:    _ChatService_CheckChatPermission_Handler,
 		},
+		{
+			MethodName: "GetUserChats",
+			Handler:    _ChatService_GetUserChats_Handler,
+		},
+		{
+			MethodName: "ListUserChats",
+			Handler:    _ChatService_ListUserChats_Handler,
+		},
+		{
+			MethodName: "GetChatSummary",
+			Handler:    _ChatService_GetChatSummary_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/chat/v1/chat.proto",
@@ -173,6 +173,123 @@ func (x *GetMediaMetadataResponse) GetDurationMs() int64 {
 	return 0
 }
 
+// CreateChatExport queues an export of one chat's history for a member. The
+// caller is responsible for checking membership.
+type CreateChatExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Format        string                 `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"` // "txt" or "html"
+	IncludeMedia  bool                   `protobuf:"varint,4,opt,name=include_media,json=includeMedia,proto3" json:"include_media,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateChatExportRequest) Reset() {
+	*x = CreateChatExportRequest{}
+	mi := &file_proto_media_v1_media_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChatExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChatExportRequest) ProtoMessage() {}
+
+func (x *CreateChatExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_media_v1_media_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChatExportRequest.ProtoReflect.Descriptor instead.
+func (*CreateChatExportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_media_v1_media_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateChatExportRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateChatExportRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *CreateChatExportRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *CreateChatExportRequest) GetIncludeMedia() bool {
+	if x != nil {
+		return x.IncludeMedia
+	}
+	return false
+}
+
+// An identical export that is already queued or running is returned instead
+// of queueing another.
+type CreateChatExportResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// export is the export as returned by GET /media/exports/:id, JSON-encoded.
+	Export        []byte `protobuf:"bytes,1,opt,name=export,proto3" json:"export,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateChatExportResponse) Reset() {
+	*x = CreateChatExportResponse{}
+	mi := &file_proto_media_v1_media_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChatExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChatExportResponse) ProtoMessage() {}
+
+func (x *CreateChatExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_media_v1_media_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChatExportResponse.ProtoReflect.Descriptor instead.
+func (*CreateChatExportResponse) Descriptor() ([]byte, []int) {
+	return file_proto_media_v1_media_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateChatExportResponse) GetExport() []byte {
+	if x != nil {
+		return x.Export
+	}
+	return nil
+}
+
 var File_proto_media_v1_media_proto protoreflect.FileDescriptor
 
 const file_proto_media_v1_media_proto_rawDesc = "" +
@@ -191,9 +308,17 @@ const file_proto_media_v1_media_proto_rawDesc = "" +
 	"\x05width\x18\a \x01(\x05R\x05width\x12\x16\n" +
 	"\x06height\x18\b \x01(\x05R\x06height\x12\x1f\n" +
 	"\vduration_ms\x18\t \x01(\x03R\n" +
-	"durationMs2i\n" +
+	"durationMs\"\x88\x01\n" +
+	"\x17CreateChatExportRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x16\n" +
+	"\x06format\x18\x03 \x01(\tR\x06format\x12#\n" +
+	"\rinclude_media\x18\x04 \x01(\bR\fincludeMedia\"2\n" +
+	"\x18CreateChatExportResponse\x12\x16\n" +
+	"\x06export\x18\x01 \x01(\fR\x06export2\xc4\x01\n" +
 	"\fMediaService\x12Y\n" +
-	"\x10GetMediaMetadata\x12!.media.v1.GetMediaMetadataRequest\x1a\".media.v1.GetMediaMetadataResponseB:Z8github.com/whatsapp-clone/backend/proto/media/v1;mediav1b\x06proto3"
+	"\x10GetMediaMetadata\x12!.media.v1.GetMediaMetadataRequest\x1a\".media.v1.GetMediaMetadataResponse\x12Y\n" +
+	"\x10CreateChatExport\x12!.media.v1.CreateChatExportRequest\x1a\".media.v1.CreateChatExportResponseB:Z8github.com/whatsapp-clone/backend/proto/media/v1;mediav1b\x06proto3"
 
 var (
 	file_proto_media_v1_media_proto_rawDescOnce sync.Once
@@ -207,16 +332,20 @@ func file_proto_media_v1_media_proto_rawDescGZIP() []byte {
 	return file_proto_media_v1_media_proto_rawDescData
 }
 
-var file_proto_media_v1_media_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_media_v1_media_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_proto_media_v1_media_proto_goTypes = []any{
 	(*GetMediaMetadataRequest)(nil),  // 0: media.v1.GetMediaMetadataRequest
 	(*GetMediaMetadataResponse)(nil), // 1: media.v1.GetMediaMetadataResponse
+	(*CreateChatExportRequest)(nil),  // 2: media.v1.CreateChatExportRequest
+	(*CreateChatExportResponse)(nil), // 3: media.v1.CreateChatExportResponse
 }
 var file_proto_media_v1_media_proto_depIdxs = []int32{
 	0, // 0: media.v1.MediaService.GetMediaMetadata:input_type -> media.v1.GetMediaMetadataRequest
-	1, // 1: media.v1.MediaService.GetMediaMetadata:output_type -> media.v1.GetMediaMetadataResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
+	2, // 1: media.v1.MediaService.CreateChatExport:input_type -> media.v1.CreateChatExportRequest
+	1, // 2: media.v1.MediaService.GetMediaMetadata:output_type -> media.v1.GetMediaMetadataResponse
+	3, // 3: media.v1.MediaService.CreateChatExport:output_type -> media.v1.CreateChatExportResponse
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -233,7 +362,7 @@ func file_proto_media_v1_media_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_media_v1_media_proto_rawDesc), len(file_proto_media_v1_media_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -20,6 +20,7 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	MediaService_GetMediaMetadata_FullMethodName = "/media.v1.MediaService/GetMediaMetadata"
+	MediaService_CreateChatExport_FullMethodName = "/media.v1.MediaService/CreateChatExport"
 )
 
 // MediaServiceClient is the client API for MediaService service.
@@ -27,6 +28,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MediaServiceClient interface {
 	GetMediaMetadata(ctx context.Context, in *GetMediaMetadataRequest, opts ...grpc.CallOption) (*GetMediaMetadataResponse, error)
+	CreateChatExport(ctx context.Context, in *CreateChatExportRequest, opts ...grpc.CallOption) (*CreateChatExportResponse, error)
 }
 
 type mediaServiceClient struct {
@@ -47,11 +49,22 @@ func (c *mediaServiceClient) GetMediaMetadata(ctx context.Context, in *GetMediaM
 	return out, nil
 }
 
+func (c *mediaServiceClient) CreateChatExport(ctx context.Context, in *CreateChatExportRequest, opts ...grpc.CallOption) (*CreateChatExportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateChatExportResponse)
+	err := c.cc.Invoke(ctx, MediaService_CreateChatExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MediaServiceServer is the server API for MediaService service.
 // All implementations must embed UnimplementedMediaServiceServer
 // for forward compatibility.
 type MediaServiceServer interface {
 	GetMediaMetadata(context.Context, *GetMediaMetadataRequest) (*GetMediaMetadataResponse, error)
+	CreateChatExport(context.Context, *CreateChatExportRequest) (*CreateChatExportResponse, error)
 	mustEmbedUnimplementedMediaServiceServer()
 }
 
@@ -65,6 +78,9 @@ type UnimplementedMediaServiceServer struct{}
 func (UnimplementedMediaServiceServer) GetMediaMetadata(context.Context, *GetMediaMetadataRequest) (*GetMediaMetadataResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetMediaMetadata not implemented")
 }
+func (UnimplementedMediaServiceServer) CreateChatExport(context.Context, *CreateChatExportRequest) (*CreateChatExportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateChatExport not implemented")
+}
 func (UnimplementedMediaServiceServer) mustEmbedUnimplementedMediaServiceServer() {}
 func (UnimplementedMediaServiceServer) testEmbeddedByValue()                      {}
 
@@ -104,6 +120,24 @@ func _MediaService_GetMediaMetadata_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MediaService_CreateChatExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateChatExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).CreateChatExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_CreateChatExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).CreateChatExport(ctx, req.(*CreateChatExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MediaService_ServiceDesc is the grpc.ServiceDesc for MediaService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +149,10 @@ var MediaService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMediaMetadata",
 			Handler:    _MediaService_GetMediaMetadata_Handler,
 		},
+		{
+			MethodName: "CreateChatExport",
+			Handler:    _MediaService_CreateChatExport_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/media/v1/media.proto",
@@ -121,6 +121,10 @@ type MessagePayload struct {
 	Caption       string                 `protobuf:"bytes,3,opt,name=caption,proto3" json:"caption,omitempty"`
 	Filename      string                 `protobuf:"bytes,4,opt,name=filename,proto3" json:"filename,omitempty"`
 	DurationMs    int64                  `protobuf:"varint,5,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	Location      *Location              `protobuf:"bytes,6,opt,name=location,proto3" json:"location,omitempty"`
+	Contacts      []*Contact             `protobuf:"bytes,7,rep,name=contacts,proto3" json:"contacts,omitempty"`
+	Encrypted     *EncryptedPayload      `protobuf:"bytes,8,opt,name=encrypted,proto3" json:"encrypted,omitempty"`
+	GroupInvite   *GroupInvite           `protobuf:"bytes,9,opt,name=group_invite,json=groupInvite,proto3" json:"group_invite,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -190,28 +194,60 @@ func (x *MessagePayload) GetDurationMs() int64 {
 	return 0
 }
 
-type ForwardedFrom struct {
+func (x *MessagePayload) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *MessagePayload) GetContacts() []*Contact {
+	if x != nil {
+		return x.Contacts
+	}
+	return nil
+}
+
+func (x *MessagePayload) GetEncrypted() *EncryptedPayload {
+	if x != nil {
+		return x.Encrypted
+	}
+	return nil
+}
+
+func (x *MessagePayload) GetGroupInvite() *GroupInvite {
+	if x != nil {
+		return x.GroupInvite
+	}
+	return nil
+}
+
+// GroupInvite invites the chat's other member to a group; chat-service sends
+// it when privacy settings keep the admin from adding them directly.
+type GroupInvite struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
-	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	InvitationId  string                 `protobuf:"bytes,1,opt,name=invitation_id,json=invitationId,proto3" json:"invitation_id,omitempty"`
+	GroupChatId   string                 `protobuf:"bytes,2,opt,name=group_chat_id,json=groupChatId,proto3" json:"group_chat_id,omitempty"`
+	GroupName     string                 `protobuf:"bytes,3,opt,name=group_name,json=groupName,proto3" json:"group_name,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ForwardedFrom) Reset() {
-	*x = ForwardedFrom{}
+func (x *GroupInvite) Reset() {
+	*x = GroupInvite{}
 	mi := &file_proto_message_v1_message_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ForwardedFrom) String() string {
+func (x *GroupInvite) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ForwardedFrom) ProtoMessage() {}
+func (*GroupInvite) ProtoMessage() {}
 
-func (x *ForwardedFrom) ProtoReflect() protoreflect.Message {
+func (x *GroupInvite) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_message_v1_message_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -223,47 +259,65 @@ func (x *ForwardedFrom) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ForwardedFrom.ProtoReflect.Descriptor instead.
-func (*ForwardedFrom) Descriptor() ([]byte, []int) {
+// Deprecated: Use GroupInvite.ProtoReflect.Descriptor instead.
+func (*GroupInvite) Descriptor() ([]byte, []int) {
 	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *ForwardedFrom) GetChatId() string {
+func (x *GroupInvite) GetInvitationId() string {
 	if x != nil {
-		return x.ChatId
+		return x.InvitationId
 	}
 	return ""
 }
 
-func (x *ForwardedFrom) GetMessageId() string {
+func (x *GroupInvite) GetGroupChatId() string {
 	if x != nil {
-		return x.MessageId
+		return x.GroupChatId
 	}
 	return ""
 }
 
-type SendMessageResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *GroupInvite) GetGroupName() string {
+	if x != nil {
+		return x.GroupName
+	}
+	return ""
 }
 
-func (x *SendMessageResponse) Reset() {
-	*x = SendMessageResponse{}
+func (x *GroupInvite) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type Location struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Latitude            float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude           float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	AccuracyM           float64                `protobuf:"fixed64,3,opt,name=accuracy_m,json=accuracyM,proto3" json:"accuracy_m,omitempty"`
+	Name                string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Address             string                 `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	LiveDurationSeconds int64                  `protobuf:"varint,6,opt,name=live_duration_seconds,json=liveDurationSeconds,proto3" json:"live_duration_seconds,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
 	mi := &file_proto_message_v1_message_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendMessageResponse) String() string {
+func (x *Location) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendMessageResponse) ProtoMessage() {}
+func (*Location) ProtoMessage() {}
 
-func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
+func (x *Location) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_message_v1_message_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -275,48 +329,80 @@ func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendMessageResponse.ProtoReflect.Descriptor instead.
-func (*SendMessageResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
 	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *SendMessageResponse) GetMessageId() string {
+func (x *Location) GetLatitude() float64 {
 	if x != nil {
-		return x.MessageId
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Location) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *Location) GetAccuracyM() float64 {
+	if x != nil {
+		return x.AccuracyM
+	}
+	return 0
+}
+
+func (x *Location) GetName() string {
+	if x != nil {
+		return x.Name
 	}
 	return ""
 }
 
-func (x *SendMessageResponse) GetCreatedAt() *timestamppb.Timestamp {
+func (x *Location) GetAddress() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.Address
 	}
-	return nil
+	return ""
 }
 
-type UpdateMessageStatusRequest struct {
+func (x *Location) GetLiveDurationSeconds() int64 {
+	if x != nil {
+		return x.LiveDurationSeconds
+	}
+	return 0
+}
+
+// Contact is a shared contact card. Either vcard or the structured fields
+// may be set; message-service parses and normalizes both.
+type Contact struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Organization  string                 `protobuf:"bytes,2,opt,name=organization,proto3" json:"organization,omitempty"`
+	Phones        []*ContactPhone        `protobuf:"bytes,3,rep,name=phones,proto3" json:"phones,omitempty"`
+	Emails        []string               `protobuf:"bytes,4,rep,name=emails,proto3" json:"emails,omitempty"`
+	Vcard         string                 `protobuf:"bytes,5,opt,name=vcard,proto3" json:"vcard,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateMessageStatusRequest) Reset() {
-	*x = UpdateMessageStatusRequest{}
+func (x *Contact) Reset() {
+	*x = Contact{}
 	mi := &file_proto_message_v1_message_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateMessageStatusRequest) String() string {
+func (x *Contact) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateMessageStatusRequest) ProtoMessage() {}
+func (*Contact) ProtoMessage() {}
 
-func (x *UpdateMessageStatusRequest) ProtoReflect() protoreflect.Message {
+func (x *Contact) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_message_v1_message_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -328,53 +414,68 @@ func (x *UpdateMessageStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateMessageStatusRequest.ProtoReflect.Descriptor instead.
-func (*UpdateMessageStatusRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Contact.ProtoReflect.Descriptor instead.
+func (*Contact) Descriptor() ([]byte, []int) {
 	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *UpdateMessageStatusRequest) GetMessageId() string {
+func (x *Contact) GetName() string {
 	if x != nil {
-		return x.MessageId
+		return x.Name
 	}
 	return ""
 }
 
-func (x *UpdateMessageStatusRequest) GetUserId() string {
+func (x *Contact) GetOrganization() string {
 	if x != nil {
-		return x.UserId
+		return x.Organization
 	}
 	return ""
 }
 
-func (x *UpdateMessageStatusRequest) GetStatus() string {
+func (x *Contact) GetPhones() []*ContactPhone {
 	if x != nil {
-		return x.Status
+		return x.Phones
+	}
+	return nil
+}
+
+func (x *Contact) GetEmails() []string {
+	if x != nil {
+		return x.Emails
+	}
+	return nil
+}
+
+func (x *Contact) GetVcard() string {
+	if x != nil {
+		return x.Vcard
 	}
 	return ""
 }
 
-type UpdateMessageStatusResponse struct {
+type ContactPhone struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Number        string                 `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateMessageStatusResponse) Reset() {
-	*x = UpdateMessageStatusResponse{}
+func (x *ContactPhone) Reset() {
+	*x = ContactPhone{}
 	mi := &file_proto_message_v1_message_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateMessageStatusResponse) String() string {
+func (x *ContactPhone) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateMessageStatusResponse) ProtoMessage() {}
+func (*ContactPhone) ProtoMessage() {}
 
-func (x *UpdateMessageStatusResponse) ProtoReflect() protoreflect.Message {
+func (x *ContactPhone) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_message_v1_message_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -386,39 +487,49 @@ func (x *UpdateMessageStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateMessageStatusResponse.ProtoReflect.Descriptor instead.
-func (*UpdateMessageStatusResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ContactPhone.ProtoReflect.Descriptor instead.
+func (*ContactPhone) Descriptor() ([]byte, []int) {
 	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *UpdateMessageStatusResponse) GetSuccess() bool {
+func (x *ContactPhone) GetNumber() string {
 	if x != nil {
-		return x.Success
+		return x.Number
 	}
-	return false
+	return ""
 }
 
-type GetLastMessagesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ChatIds       []string               `protobuf:"bytes,1,rep,name=chat_ids,json=chatIds,proto3" json:"chat_ids,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ContactPhone) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
 }
 
-func (x *GetLastMessagesRequest) Reset() {
-	*x = GetLastMessagesRequest{}
+// EncryptedPayload carries one end-to-end encrypted ciphertext per
+// recipient device.
+type EncryptedPayload struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SenderDeviceId string                 `protobuf:"bytes,1,opt,name=sender_device_id,json=senderDeviceId,proto3" json:"sender_device_id,omitempty"`
+	Envelopes      []*CipherEnvelope      `protobuf:"bytes,2,rep,name=envelopes,proto3" json:"envelopes,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *EncryptedPayload) Reset() {
+	*x = EncryptedPayload{}
 	mi := &file_proto_message_v1_message_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetLastMessagesRequest) String() string {
+func (x *EncryptedPayload) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetLastMessagesRequest) ProtoMessage() {}
+func (*EncryptedPayload) ProtoMessage() {}
 
-func (x *GetLastMessagesRequest) ProtoReflect() protoreflect.Message {
+func (x *EncryptedPayload) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_message_v1_message_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -430,39 +541,49 @@ func (x *GetLastMessagesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetLastMessagesRequest.ProtoReflect.Descriptor instead.
-func (*GetLastMessagesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use EncryptedPayload.ProtoReflect.Descriptor instead.
+func (*EncryptedPayload) Descriptor() ([]byte, []int) {
 	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetLastMessagesRequest) GetChatIds() []string {
+func (x *EncryptedPayload) GetSenderDeviceId() string {
 	if x != nil {
-		return x.ChatIds
+		return x.SenderDeviceId
+	}
+	return ""
+}
+
+func (x *EncryptedPayload) GetEnvelopes() []*CipherEnvelope {
+	if x != nil {
+		return x.Envelopes
 	}
 	return nil
 }
 
-type GetLastMessagesResponse struct {
-	state         protoimpl.MessageState     `protogen:"open.v1"`
-	Messages      map[string]*MessagePreview `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+type CipherEnvelope struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	DeviceId      string                 `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"` // "prekey" or "message"
+	Ciphertext    []byte                 `protobuf:"bytes,4,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetLastMessagesResponse) Reset() {
-	*x = GetLastMessagesResponse{}
+func (x *CipherEnvelope) Reset() {
+	*x = CipherEnvelope{}
 	mi := &file_proto_message_v1_message_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetLastMessagesResponse) String() string {
+func (x *CipherEnvelope) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetLastMessagesResponse) ProtoMessage() {}
+func (*CipherEnvelope) ProtoMessage() {}
 
-func (x *GetLastMessagesResponse) ProtoReflect() protoreflect.Message {
+func (x *CipherEnvelope) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_message_v1_message_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -474,43 +595,61 @@ func (x *GetLastMessagesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetLastMessagesResponse.ProtoReflect.Descriptor instead.
-func (*GetLastMessagesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CipherEnvelope.ProtoReflect.Descriptor instead.
+func (*CipherEnvelope) Descriptor() ([]byte, []int) {
 	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *GetLastMessagesResponse) GetMessages() map[string]*MessagePreview {
+func (x *CipherEnvelope) GetUserId() string {
 	if x != nil {
-		return x.Messages
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CipherEnvelope) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *CipherEnvelope) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CipherEnvelope) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
 	}
 	return nil
 }
 
-type MessagePreview struct {
+type ForwardedFrom struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	SenderId      string                 `protobuf:"bytes,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
-	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
-	Body          string                 `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MessagePreview) Reset() {
-	*x = MessagePreview{}
+func (x *ForwardedFrom) Reset() {
+	*x = ForwardedFrom{}
 	mi := &file_proto_message_v1_message_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MessagePreview) String() string {
+func (x *ForwardedFrom) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MessagePreview) ProtoMessage() {}
+func (*ForwardedFrom) ProtoMessage() {}
 
-func (x *MessagePreview) ProtoReflect() protoreflect.Message {
+func (x *ForwardedFrom) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_message_v1_message_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -522,68 +661,47 @@ func (x *MessagePreview) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MessagePreview.ProtoReflect.Descriptor instead.
-func (*MessagePreview) Descriptor() ([]byte, []int) {
+// Deprecated: Use ForwardedFrom.ProtoReflect.Descriptor instead.
+func (*ForwardedFrom) Descriptor() ([]byte, []int) {
 	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *MessagePreview) GetMessageId() string {
-	if x != nil {
-		return x.MessageId
-	}
-	return ""
-}
-
-func (x *MessagePreview) GetSenderId() string {
-	if x != nil {
-		return x.SenderId
-	}
-	return ""
-}
-
-func (x *MessagePreview) GetType() string {
+func (x *ForwardedFrom) GetChatId() string {
 	if x != nil {
-		return x.Type
+		return x.ChatId
 	}
 	return ""
 }
 
-func (x *MessagePreview) GetBody() string {
+func (x *ForwardedFrom) GetMessageId() string {
 	if x != nil {
-		return x.Body
+		return x.MessageId
 	}
 	return ""
 }
 
-func (x *MessagePreview) GetCreatedAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.CreatedAt
-	}
-	return nil
-}
-
-type GetUnreadCountsRequest struct {
+type SendMessageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	ChatIds       []string               `protobuf:"bytes,2,rep,name=chat_ids,json=chatIds,proto3" json:"chat_ids,omitempty"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUnreadCountsRequest) Reset() {
-	*x = GetUnreadCountsRequest{}
+func (x *SendMessageResponse) Reset() {
+	*x = SendMessageResponse{}
 	mi := &file_proto_message_v1_message_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUnreadCountsRequest) String() string {
+func (x *SendMessageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUnreadCountsRequest) ProtoMessage() {}
+func (*SendMessageResponse) ProtoMessage() {}
 
-func (x *GetUnreadCountsRequest) ProtoReflect() protoreflect.Message {
+func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_message_v1_message_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -595,47 +713,764 @@ func (x *GetUnreadCountsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUnreadCountsRequest.ProtoReflect.Descriptor instead.
-func (*GetUnreadCountsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SendMessageResponse.ProtoReflect.Descriptor instead.
+func (*SendMessageResponse) Descriptor() ([]byte, []int) {
 	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *GetUnreadCountsRequest) GetUserId() string {
+func (x *SendMessageResponse) GetMessageId() string {
 	if x != nil {
-		return x.UserId
+		return x.MessageId
 	}
 	return ""
 }
 
-func (x *GetUnreadCountsRequest) GetChatIds() []string {
+func (x *SendMessageResponse) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type UpdateMessageStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMessageStatusRequest) Reset() {
+	*x = UpdateMessageStatusRequest{}
+	mi := &file_proto_message_v1_message_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMessageStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMessageStatusRequest) ProtoMessage() {}
+
+func (x *UpdateMessageStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_v1_message_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMessageStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMessageStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateMessageStatusRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *UpdateMessageStatusRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateMessageStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type UpdateMessageStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMessageStatusResponse) Reset() {
+	*x = UpdateMessageStatusResponse{}
+	mi := &file_proto_message_v1_message_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMessageStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMessageStatusResponse) ProtoMessage() {}
+
+func (x *UpdateMessageStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_v1_message_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMessageStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateMessageStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateMessageStatusResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type GetLastMessagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatIds       []string               `protobuf:"bytes,1,rep,name=chat_ids,json=chatIds,proto3" json:"chat_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLastMessagesRequest) Reset() {
+	*x = GetLastMessagesRequest{}
+	mi := &file_proto_message_v1_message_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLastMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLastMessagesRequest) ProtoMessage() {}
+
+func (x *GetLastMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_v1_message_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLastMessagesRequest.ProtoReflect.Descriptor instead.
+func (*GetLastMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetLastMessagesRequest) GetChatIds() []string {
+	if x != nil {
+		return x.ChatIds
+	}
+	return nil
+}
+
+type GetLastMessagesResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Messages      map[string]*MessagePreview `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLastMessagesResponse) Reset() {
+	*x = GetLastMessagesResponse{}
+	mi := &file_proto_message_v1_message_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLastMessagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLastMessagesResponse) ProtoMessage() {}
+
+func (x *GetLastMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_v1_message_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLastMessagesResponse.ProtoReflect.Descriptor instead.
+func (*GetLastMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetLastMessagesResponse) GetMessages() map[string]*MessagePreview {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type MessagePreview struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	SenderId      string                 `protobuf:"bytes,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Body          string                 `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Seq           int64                  `protobuf:"varint,6,opt,name=seq,proto3" json:"seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessagePreview) Reset() {
+	*x = MessagePreview{}
+	mi := &file_proto_message_v1_message_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessagePreview) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessagePreview) ProtoMessage() {}
+
+func (x *MessagePreview) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_v1_message_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessagePreview.ProtoReflect.Descriptor instead.
+func (*MessagePreview) Descriptor() ([]byte, []int) {
+	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MessagePreview) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *MessagePreview) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+func (x *MessagePreview) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *MessagePreview) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *MessagePreview) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *MessagePreview) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+type GetUnreadCountsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ChatIds       []string               `protobuf:"bytes,2,rep,name=chat_ids,json=chatIds,proto3" json:"chat_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUnreadCountsRequest) Reset() {
+	*x = GetUnreadCountsRequest{}
+	mi := &file_proto_message_v1_message_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUnreadCountsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUnreadCountsRequest) ProtoMessage() {}
+
+func (x *GetUnreadCountsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_v1_message_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUnreadCountsRequest.ProtoReflect.Descriptor instead.
+func (*GetUnreadCountsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetUnreadCountsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetUnreadCountsRequest) GetChatIds() []string {
+	if x != nil {
+		return x.ChatIds
+	}
+	return nil
+}
+
+type GetUnreadCountsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Counts        map[string]int64       `protobuf:"bytes,1,rep,name=counts,proto3" json:"counts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUnreadCountsResponse) Reset() {
+	*x = GetUnreadCountsResponse{}
+	mi := &file_proto_message_v1_message_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUnreadCountsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUnreadCountsResponse) ProtoMessage() {}
+
+func (x *GetUnreadCountsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_v1_message_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUnreadCountsResponse.ProtoReflect.Descriptor instead.
+func (*GetUnreadCountsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetUnreadCountsResponse) GetCounts() map[string]int64 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+type EditMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Body          string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	Caption       string                 `protobuf:"bytes,4,opt,name=caption,proto3" json:"caption,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditMessageRequest) Reset() {
+	*x = EditMessageRequest{}
+	mi := &file_proto_message_v1_message_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditMessageRequest) ProtoMessage() {}
+
+func (x *EditMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_v1_message_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditMessageRequest.ProtoReflect.Descriptor instead.
+func (*EditMessageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *EditMessageRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *EditMessageRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *EditMessageRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *EditMessageRequest) GetCaption() string {
+	if x != nil {
+		return x.Caption
+	}
+	return ""
+}
+
+type EditMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	EditedAt      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=edited_at,json=editedAt,proto3" json:"edited_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditMessageResponse) Reset() {
+	*x = EditMessageResponse{}
+	mi := &file_proto_message_v1_message_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditMessageResponse) ProtoMessage() {}
+
+func (x *EditMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_v1_message_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditMessageResponse.ProtoReflect.Descriptor instead.
+func (*EditMessageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *EditMessageResponse) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *EditMessageResponse) GetEditedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EditedAt
+	}
+	return nil
+}
+
+type UpdateLiveLocationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Latitude      float64                `protobuf:"fixed64,3,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,4,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	AccuracyM     float64                `protobuf:"fixed64,5,opt,name=accuracy_m,json=accuracyM,proto3" json:"accuracy_m,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLiveLocationRequest) Reset() {
+	*x = UpdateLiveLocationRequest{}
+	mi := &file_proto_message_v1_message_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLiveLocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLiveLocationRequest) ProtoMessage() {}
+
+func (x *UpdateLiveLocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_v1_message_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLiveLocationRequest.ProtoReflect.Descriptor instead.
+func (*UpdateLiveLocationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UpdateLiveLocationRequest) GetMessageId() string {
 	if x != nil {
-		return x.ChatIds
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *UpdateLiveLocationRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateLiveLocationRequest) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *UpdateLiveLocationRequest) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *UpdateLiveLocationRequest) GetAccuracyM() float64 {
+	if x != nil {
+		return x.AccuracyM
+	}
+	return 0
+}
+
+type UpdateLiveLocationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	LiveUntil     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=live_until,json=liveUntil,proto3" json:"live_until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLiveLocationResponse) Reset() {
+	*x = UpdateLiveLocationResponse{}
+	mi := &file_proto_message_v1_message_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLiveLocationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLiveLocationResponse) ProtoMessage() {}
+
+func (x *UpdateLiveLocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_v1_message_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLiveLocationResponse.ProtoReflect.Descriptor instead.
+func (*UpdateLiveLocationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UpdateLiveLocationResponse) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *UpdateLiveLocationResponse) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *UpdateLiveLocationResponse) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
 	}
 	return nil
 }
 
-type GetUnreadCountsResponse struct {
+func (x *UpdateLiveLocationResponse) GetLiveUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LiveUntil
+	}
+	return nil
+}
+
+// ExportChatMessagesRequest pages through a chat's history oldest first as
+// user_id sees it: deleted messages and messages the user deleted for
+// themselves are left out. Pass the last message of the previous page as
+// the cursor.
+type ExportChatMessagesRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChatId         string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AfterCreatedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=after_created_at,json=afterCreatedAt,proto3" json:"after_created_at,omitempty"`
+	AfterMessageId string                 `protobuf:"bytes,4,opt,name=after_message_id,json=afterMessageId,proto3" json:"after_message_id,omitempty"`
+	Limit          int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExportChatMessagesRequest) Reset() {
+	*x = ExportChatMessagesRequest{}
+	mi := &file_proto_message_v1_message_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportChatMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportChatMessagesRequest) ProtoMessage() {}
+
+func (x *ExportChatMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_v1_message_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportChatMessagesRequest.ProtoReflect.Descriptor instead.
+func (*ExportChatMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ExportChatMessagesRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ExportChatMessagesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ExportChatMessagesRequest) GetAfterCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AfterCreatedAt
+	}
+	return nil
+}
+
+func (x *ExportChatMessagesRequest) GetAfterMessageId() string {
+	if x != nil {
+		return x.AfterMessageId
+	}
+	return ""
+}
+
+func (x *ExportChatMessagesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ExportChatMessagesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Counts        map[string]int64       `protobuf:"bytes,1,rep,name=counts,proto3" json:"counts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Messages      []*ExportedMessage     `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	HasMore       bool                   `protobuf:"varint,2,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUnreadCountsResponse) Reset() {
-	*x = GetUnreadCountsResponse{}
-	mi := &file_proto_message_v1_message_proto_msgTypes[10]
+func (x *ExportChatMessagesResponse) Reset() {
+	*x = ExportChatMessagesResponse{}
+	mi := &file_proto_message_v1_message_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUnreadCountsResponse) String() string {
+func (x *ExportChatMessagesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUnreadCountsResponse) ProtoMessage() {}
+func (*ExportChatMessagesResponse) ProtoMessage() {}
 
-func (x *GetUnreadCountsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_v1_message_proto_msgTypes[10]
+func (x *ExportChatMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_v1_message_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -646,14 +1481,145 @@ func (x *GetUnreadCountsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUnreadCountsResponse.ProtoReflect.Descriptor instead.
-func (*GetUnreadCountsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use ExportChatMessagesResponse.ProtoReflect.Descriptor instead.
+func (*ExportChatMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *GetUnreadCountsResponse) GetCounts() map[string]int64 {
+func (x *ExportChatMessagesResponse) GetMessages() []*ExportedMessage {
 	if x != nil {
-		return x.Counts
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *ExportChatMessagesResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+type ExportedMessage struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	MessageId        string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	SenderId         string                 `protobuf:"bytes,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	Type             string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Text             string                 `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"` // body or caption, or a summary for locations, contacts and polls
+	MediaId          string                 `protobuf:"bytes,5,opt,name=media_id,json=mediaId,proto3" json:"media_id,omitempty"`
+	Filename         string                 `protobuf:"bytes,6,opt,name=filename,proto3" json:"filename,omitempty"`
+	Forwarded        bool                   `protobuf:"varint,7,opt,name=forwarded,proto3" json:"forwarded,omitempty"`
+	ReplyToMessageId string                 `protobuf:"bytes,8,opt,name=reply_to_message_id,json=replyToMessageId,proto3" json:"reply_to_message_id,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	EditedAt         *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=edited_at,json=editedAt,proto3" json:"edited_at,omitempty"`
+	Data             []byte                 `protobuf:"bytes,11,opt,name=data,proto3" json:"data,omitempty"` // the message as the REST API returns it to user_id, JSON
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ExportedMessage) Reset() {
+	*x = ExportedMessage{}
+	mi := &file_proto_message_v1_message_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportedMessage) ProtoMessage() {}
+
+func (x *ExportedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_v1_message_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportedMessage.ProtoReflect.Descriptor instead.
+func (*ExportedMessage) Descriptor() ([]byte, []int) {
+	return file_proto_message_v1_message_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ExportedMessage) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ExportedMessage) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+func (x *ExportedMessage) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ExportedMessage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ExportedMessage) GetMediaId() string {
+	if x != nil {
+		return x.MediaId
+	}
+	return ""
+}
+
+func (x *ExportedMessage) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *ExportedMessage) GetForwarded() bool {
+	if x != nil {
+		return x.Forwarded
+	}
+	return false
+}
+
+func (x *ExportedMessage) GetReplyToMessageId() string {
+	if x != nil {
+		return x.ReplyToMessageId
+	}
+	return ""
+}
+
+func (x *ExportedMessage) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ExportedMessage) GetEditedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EditedAt
+	}
+	return nil
+}
+
+func (x *ExportedMessage) GetData() []byte {
+	if x != nil {
+		return x.Data
 	}
 	return nil
 }
@@ -671,14 +1637,52 @@ const file_proto_message_v1_message_proto_rawDesc = "" +
 	"\apayload\x18\x04 \x01(\v2\x1a.message.v1.MessagePayloadR\apayload\x12\"\n" +
 	"\rclient_msg_id\x18\x05 \x01(\tR\vclientMsgId\x12-\n" +
 	"\x13reply_to_message_id\x18\x06 \x01(\tR\x10replyToMessageId\x12@\n" +
-	"\x0eforwarded_from\x18\a \x01(\v2\x19.message.v1.ForwardedFromR\rforwardedFrom\"\x96\x01\n" +
+	"\x0eforwarded_from\x18\a \x01(\v2\x19.message.v1.ForwardedFromR\rforwardedFrom\"\xf1\x02\n" +
 	"\x0eMessagePayload\x12\x12\n" +
 	"\x04body\x18\x01 \x01(\tR\x04body\x12\x19\n" +
 	"\bmedia_id\x18\x02 \x01(\tR\amediaId\x12\x18\n" +
 	"\acaption\x18\x03 \x01(\tR\acaption\x12\x1a\n" +
 	"\bfilename\x18\x04 \x01(\tR\bfilename\x12\x1f\n" +
 	"\vduration_ms\x18\x05 \x01(\x03R\n" +
-	"durationMs\"G\n" +
+	"durationMs\x120\n" +
+	"\blocation\x18\x06 \x01(\v2\x14.message.v1.LocationR\blocation\x12/\n" +
+	"\bcontacts\x18\a \x03(\v2\x13.message.v1.ContactR\bcontacts\x12:\n" +
+	"\tencrypted\x18\b \x01(\v2\x1c.message.v1.EncryptedPayloadR\tencrypted\x12:\n" +
+	"\fgroup_invite\x18\t \x01(\v2\x17.message.v1.GroupInviteR\vgroupInvite\"\xb0\x01\n" +
+	"\vGroupInvite\x12#\n" +
+	"\rinvitation_id\x18\x01 \x01(\tR\finvitationId\x12\"\n" +
+	"\rgroup_chat_id\x18\x02 \x01(\tR\vgroupChatId\x12\x1d\n" +
+	"\n" +
+	"group_name\x18\x03 \x01(\tR\tgroupName\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\xc5\x01\n" +
+	"\bLocation\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\x12\x1d\n" +
+	"\n" +
+	"accuracy_m\x18\x03 \x01(\x01R\taccuracyM\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x18\n" +
+	"\aaddress\x18\x05 \x01(\tR\aaddress\x122\n" +
+	"\x15live_duration_seconds\x18\x06 \x01(\x03R\x13liveDurationSeconds\"\xa1\x01\n" +
+	"\aContact\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\"\n" +
+	"\forganization\x18\x02 \x01(\tR\forganization\x120\n" +
+	"\x06phones\x18\x03 \x03(\v2\x18.message.v1.ContactPhoneR\x06phones\x12\x16\n" +
+	"\x06emails\x18\x04 \x03(\tR\x06emails\x12\x14\n" +
+	"\x05vcard\x18\x05 \x01(\tR\x05vcard\":\n" +
+	"\fContactPhone\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\tR\x06number\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\"v\n" +
+	"\x10EncryptedPayload\x12(\n" +
+	"\x10sender_device_id\x18\x01 \x01(\tR\x0esenderDeviceId\x128\n" +
+	"\tenvelopes\x18\x02 \x03(\v2\x1a.message.v1.CipherEnvelopeR\tenvelopes\"z\n" +
+	"\x0eCipherEnvelope\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x04 \x01(\fR\n" +
+	"ciphertext\"G\n" +
 	"\rForwardedFrom\x12\x17\n" +
 	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x1d\n" +
 	"\n" +
@@ -701,7 +1705,7 @@ const file_proto_message_v1_message_proto_rawDesc = "" +
 	"\bmessages\x18\x01 \x03(\v21.message.v1.GetLastMessagesResponse.MessagesEntryR\bmessages\x1aW\n" +
 	"\rMessagesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
-	"\x05value\x18\x02 \x01(\v2\x1a.message.v1.MessagePreviewR\x05value:\x028\x01\"\xaf\x01\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.message.v1.MessagePreviewR\x05value:\x028\x01\"\xc1\x01\n" +
 	"\x0eMessagePreview\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x1b\n" +
@@ -709,7 +1713,8 @@ const file_proto_message_v1_message_proto_rawDesc = "" +
 	"\x04type\x18\x03 \x01(\tR\x04type\x12\x12\n" +
 	"\x04body\x18\x04 \x01(\tR\x04body\x129\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"L\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x10\n" +
+	"\x03seq\x18\x06 \x01(\x03R\x03seq\"L\n" +
 	"\x16GetUnreadCountsRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
 	"\bchat_ids\x18\x02 \x03(\tR\achatIds\"\x9d\x01\n" +
@@ -717,12 +1722,65 @@ const file_proto_message_v1_message_proto_rawDesc = "" +
 	"\x06counts\x18\x01 \x03(\v2/.message.v1.GetUnreadCountsResponse.CountsEntryR\x06counts\x1a9\n" +
 	"\vCountsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x012\x80\x03\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"z\n" +
+	"\x12EditMessageRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\x12\x18\n" +
+	"\acaption\x18\x04 \x01(\tR\acaption\"m\n" +
+	"\x13EditMessageResponse\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x127\n" +
+	"\tedited_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\beditedAt\"\xac\x01\n" +
+	"\x19UpdateLiveLocationRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1a\n" +
+	"\blatitude\x18\x03 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x04 \x01(\x01R\tlongitude\x12\x1d\n" +
+	"\n" +
+	"accuracy_m\x18\x05 \x01(\x01R\taccuracyM\"\xca\x01\n" +
+	"\x1aUpdateLiveLocationResponse\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
+	"\n" +
+	"live_until\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tliveUntil\"\xd3\x01\n" +
+	"\x19ExportChatMessagesRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12D\n" +
+	"\x10after_created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x0eafterCreatedAt\x12(\n" +
+	"\x10after_message_id\x18\x04 \x01(\tR\x0eafterMessageId\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"p\n" +
+	"\x1aExportChatMessagesResponse\x127\n" +
+	"\bmessages\x18\x01 \x03(\v2\x1b.message.v1.ExportedMessageR\bmessages\x12\x19\n" +
+	"\bhas_more\x18\x02 \x01(\bR\ahasMore\"\x81\x03\n" +
+	"\x0fExportedMessage\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x1b\n" +
+	"\tsender_id\x18\x02 \x01(\tR\bsenderId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x12\n" +
+	"\x04text\x18\x04 \x01(\tR\x04text\x12\x19\n" +
+	"\bmedia_id\x18\x05 \x01(\tR\amediaId\x12\x1a\n" +
+	"\bfilename\x18\x06 \x01(\tR\bfilename\x12\x1c\n" +
+	"\tforwarded\x18\a \x01(\bR\tforwarded\x12-\n" +
+	"\x13reply_to_message_id\x18\b \x01(\tR\x10replyToMessageId\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x127\n" +
+	"\tedited_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\beditedAt\x12\x12\n" +
+	"\x04data\x18\v \x01(\fR\x04data2\x9a\x05\n" +
 	"\x0eMessageService\x12N\n" +
 	"\vSendMessage\x12\x1e.message.v1.SendMessageRequest\x1a\x1f.message.v1.SendMessageResponse\x12f\n" +
 	"\x13UpdateMessageStatus\x12&.message.v1.UpdateMessageStatusRequest\x1a'.message.v1.UpdateMessageStatusResponse\x12Z\n" +
 	"\x0fGetLastMessages\x12\".message.v1.GetLastMessagesRequest\x1a#.message.v1.GetLastMessagesResponse\x12Z\n" +
-	"\x0fGetUnreadCounts\x12\".message.v1.GetUnreadCountsRequest\x1a#.message.v1.GetUnreadCountsResponseB>Z<github.com/whatsapp-clone/backend/proto/message/v1;messagev1b\x06proto3"
+	"\x0fGetUnreadCounts\x12\".message.v1.GetUnreadCountsRequest\x1a#.message.v1.GetUnreadCountsResponse\x12N\n" +
+	"\vEditMessage\x12\x1e.message.v1.EditMessageRequest\x1a\x1f.message.v1.EditMessageResponse\x12c\n" +
+	"\x12UpdateLiveLocation\x12%.message.v1.UpdateLiveLocationRequest\x1a&.message.v1.UpdateLiveLocationResponse\x12c\n" +
+	"\x12ExportChatMessages\x12%.message.v1.ExportChatMessagesRequest\x1a&.message.v1.ExportChatMessagesResponseB>Z<github.com/whatsapp-clone/backend/proto/message/v1;messagev1b\x06proto3"
 
 var (
 	file_proto_message_v1_message_proto_rawDescOnce sync.Once
@@ -736,44 +1794,77 @@ func file_proto_message_v1_message_proto_rawDescGZIP() []byte {
 	return file_proto_message_v1_message_proto_rawDescData
 }
 
-var file_proto_message_v1_message_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_proto_message_v1_message_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_proto_message_v1_message_proto_goTypes = []any{
 	(*SendMessageRequest)(nil),          // 0: message.v1.SendMessageRequest
 	(*MessagePayload)(nil),              // 1: message.v1.MessagePayload
-	(*ForwardedFrom)(nil),               // 2: message.v1.ForwardedFrom
-	(*SendMessageResponse)(nil),         // 3: message.v1.SendMessageResponse
-	(*UpdateMessageStatusRequest)(nil),  // 4: message.v1.UpdateMessageStatusRequest
-	(*UpdateMessageStatusResponse)(nil), // 5: message.v1.UpdateMessageStatusResponse
-	(*GetLastMessagesRequest)(nil),      // 6: message.v1.GetLastMessagesRequest
-	(*GetLastMessagesResponse)(nil),     // 7: message.v1.GetLastMessagesResponse
-	(*MessagePreview)(nil),              // 8: message.v1.MessagePreview
-	(*GetUnreadCountsRequest)(nil),      // 9: message.v1.GetUnreadCountsRequest
-	(*GetUnreadCountsResponse)(nil),     // 10: message.v1.GetUnreadCountsResponse
-	nil,                                 // 11: message.v1.GetLastMessagesResponse.MessagesEntry
-	nil,                                 // 12: message.v1.GetUnreadCountsResponse.CountsEntry
-	(*timestamppb.Timestamp)(nil),       // 13: google.protobuf.Timestamp
+	(*GroupInvite)(nil),                 // 2: message.v1.GroupInvite
+	(*Location)(nil),                    // 3: message.v1.Location
+	(*Contact)(nil),                     // 4: message.v1.Contact
+	(*ContactPhone)(nil),                // 5: message.v1.ContactPhone
+	(*EncryptedPayload)(nil),            // 6: message.v1.EncryptedPayload
+	(*CipherEnvelope)(nil),              // 7: message.v1.CipherEnvelope
+	(*ForwardedFrom)(nil),               // 8: message.v1.ForwardedFrom
+	(*SendMessageResponse)(nil),         // 9: message.v1.SendMessageResponse
+	(*UpdateMessageStatusRequest)(nil),  // 10: message.v1.UpdateMessageStatusRequest
+	(*UpdateMessageStatusResponse)(nil), // 11: message.v1.UpdateMessageStatusResponse
+	(*GetLastMessagesRequest)(nil),      // 12: message.v1.GetLastMessagesRequest
+	(*GetLastMessagesResponse)(nil),     // 13: message.v1.GetLastMessagesResponse
+	(*MessagePreview)(nil),              // 14: message.v1.MessagePreview
+	(*GetUnreadCountsRequest)(nil),      // 15: message.v1.GetUnreadCountsRequest
+	(*GetUnreadCountsResponse)(nil),     // 16: message.v1.GetUnreadCountsResponse
+	(*EditMessageRequest)(nil),          // 17: message.v1.EditMessageRequest
+	(*EditMessageResponse)(nil),         // 18: message.v1.EditMessageResponse
+	(*UpdateLiveLocationRequest)(nil),   // 19: message.v1.UpdateLiveLocationRequest
+	(*UpdateLiveLocationResponse)(nil),  // 20: message.v1.UpdateLiveLocationResponse
+	(*ExportChatMessagesRequest)(nil),   // 21: message.v1.ExportChatMessagesRequest
+	(*ExportChatMessagesResponse)(nil),  // 22: message.v1.ExportChatMessagesResponse
+	(*ExportedMessage)(nil),             // 23: message.v1.ExportedMessage
+	nil,                                 // 24: message.v1.GetLastMessagesResponse.MessagesEntry
+	nil,                                 // 25: message.v1.GetUnreadCountsResponse.CountsEntry
+	(*timestamppb.Timestamp)(nil),       // 26: google.protobuf.Timestamp
 }
 var file_proto_message_v1_message_proto_depIdxs = []int32{
 	1,  // 0: message.v1.SendMessageRequest.payload:type_name -> message.v1.MessagePayload
-	2,  // 1: message.v1.SendMessageRequest.forwarded_from:type_name -> message.v1.ForwardedFrom
-	13, // 2: message.v1.SendMessageResponse.created_at:type_name -> google.protobuf.Timestamp
-	11, // 3: message.v1.GetLastMessagesResponse.messages:type_name -> message.v1.GetLastMessagesResponse.MessagesEntry
-	13, // 4: message.v1.MessagePreview.created_at:type_name -> google.protobuf.Timestamp
-	12, // 5: message.v1.GetUnreadCountsResponse.counts:type_name -> message.v1.GetUnreadCountsResponse.CountsEntry
-	8,  // 6: message.v1.GetLastMessagesResponse.MessagesEntry.value:type_name -> message.v1.MessagePreview
-	0,  // 7: message.v1.MessageService.SendMessage:input_type -> message.v1.SendMessageRequest
-	4,  // 8: message.v1.MessageService.UpdateMessageStatus:input_type -> message.v1.UpdateMessageStatusRequest
-	6,  // 9: message.v1.MessageService.GetLastMessages:input_type -> message.v1.GetLastMessagesRequest
-	9,  // 10: message.v1.MessageService.GetUnreadCounts:input_type -> message.v1.GetUnreadCountsRequest
-	3,  // 11: message.v1.MessageService.SendMessage:output_type -> message.v1.SendMessageResponse
-	5,  // 12: message.v1.MessageService.UpdateMessageStatus:output_type -> message.v1.UpdateMessageStatusResponse
-	7,  // 13: message.v1.MessageService.GetLastMessages:output_type -> message.v1.GetLastMessagesResponse
-	10, // 14: message.v1.MessageService.GetUnreadCounts:output_type -> message.v1.GetUnreadCountsResponse
-	11, // [11:15] is the sub-list for method output_type
-	7,  // [7:11] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	8,  // 1: message.v1.SendMessageRequest.forwarded_from:type_name -> message.v1.ForwardedFrom
+	3,  // 2: message.v1.MessagePayload.location:type_name -> message.v1.Location
+	4,  // 3: message.v1.MessagePayload.contacts:type_name -> message.v1.Contact
+	6,  // 4: message.v1.MessagePayload.encrypted:type_name -> message.v1.EncryptedPayload
+	2,  // 5: message.v1.MessagePayload.group_invite:type_name -> message.v1.GroupInvite
+	26, // 6: message.v1.GroupInvite.expires_at:type_name -> google.protobuf.Timestamp
+	5,  // 7: message.v1.Contact.phones:type_name -> message.v1.ContactPhone
+	7,  // 8: message.v1.EncryptedPayload.envelopes:type_name -> message.v1.CipherEnvelope
+	26, // 9: message.v1.SendMessageResponse.created_at:type_name -> google.protobuf.Timestamp
+	24, // 10: message.v1.GetLastMessagesResponse.messages:type_name -> message.v1.GetLastMessagesResponse.MessagesEntry
+	26, // 11: message.v1.MessagePreview.created_at:type_name -> google.protobuf.Timestamp
+	25, // 12: message.v1.GetUnreadCountsResponse.counts:type_name -> message.v1.GetUnreadCountsResponse.CountsEntry
+	26, // 13: message.v1.EditMessageResponse.edited_at:type_name -> google.protobuf.Timestamp
+	26, // 14: message.v1.UpdateLiveLocationResponse.updated_at:type_name -> google.protobuf.Timestamp
+	26, // 15: message.v1.UpdateLiveLocationResponse.live_until:type_name -> google.protobuf.Timestamp
+	26, // 16: message.v1.ExportChatMessagesRequest.after_created_at:type_name -> google.protobuf.Timestamp
+	23, // 17: message.v1.ExportChatMessagesResponse.messages:type_name -> message.v1.ExportedMessage
+	26, // 18: message.v1.ExportedMessage.created_at:type_name -> google.protobuf.Timestamp
+	26, // 19: message.v1.ExportedMessage.edited_at:type_name -> google.protobuf.Timestamp
+	14, // 20: message.v1.GetLastMessagesResponse.MessagesEntry.value:type_name -> message.v1.MessagePreview
+	0,  // 21: message.v1.MessageService.SendMessage:input_type -> message.v1.SendMessageRequest
+	10, // 22: message.v1.MessageService.UpdateMessageStatus:input_type -> message.v1.UpdateMessageStatusRequest
+	12, // 23: message.v1.MessageService.GetLastMessages:input_type -> message.v1.GetLastMessagesRequest
+	15, // 24: message.v1.MessageService.GetUnreadCounts:input_type -> message.v1.GetUnreadCountsRequest
+	17, // 25: message.v1.MessageService.EditMessage:input_type -> message.v1.EditMessageRequest
+	19, // 26: message.v1.MessageService.UpdateLiveLocation:input_type -> message.v1.UpdateLiveLocationRequest
+	21, // 27: message.v1.MessageService.ExportChatMessages:input_type -> message.v1.ExportChatMessagesRequest
+	9,  // 28: message.v1.MessageService.SendMessage:output_type -> message.v1.SendMessageResponse
+	11, // 29: message.v1.MessageService.UpdateMessageStatus:output_type -> message.v1.UpdateMessageStatusResponse
+	13, // 30: message.v1.MessageService.GetLastMessages:output_type -> message.v1.GetLastMessagesResponse
+	16, // 31: message.v1.MessageService.GetUnreadCounts:output_type -> message.v1.GetUnreadCountsResponse
+	18, // 32: message.v1.MessageService.EditMessage:output_type -> message.v1.EditMessageResponse
+	20, // 33: message.v1.MessageService.UpdateLiveLocation:output_type -> message.v1.UpdateLiveLocationResponse
+	22, // 34: message.v1.MessageService.ExportChatMessages:output_type -> message.v1.ExportChatMessagesResponse
+	28, // [28:35] is the sub-list for method output_type
+	21, // [21:28] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_proto_message_v1_message_proto_init() }
@@ -787,7 +1878,7 @@ func file_proto_message_v1_message_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_message_v1_message_proto_rawDesc), len(file_proto_message_v1_message_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
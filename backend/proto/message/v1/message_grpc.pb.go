@@ -23,6 +23,9 @@ const (
 	MessageService_UpdateMessageStatus_FullMethodName = "/message.v1.MessageService/UpdateMessageStatus"
 	MessageService_GetLastMessages_FullMethodName     = "/message.v1.MessageService/GetLastMessages"
 	MessageService_GetUnreadCounts_FullMethodName     = "/message.v1.MessageService/GetUnreadCounts"
+	MessageService_EditMessage_FullMethodName         = "/message.v1.MessageService/EditMessage"
+	MessageService_UpdateLiveLocation_FullMethodName  = "/message.v1.MessageService/UpdateLiveLocation"
+	MessageService_ExportChatMessages_FullMethodName  = "/message.v1.MessageService/ExportChatMessages"
 )
 
 // MessageServiceClient is the client API for MessageService service.
@@ -33,6 +36,9 @@ type MessageServiceClient interface {
 	UpdateMessageStatus(ctx context.Context, in *UpdateMessageStatusRequest, opts ...grpc.CallOption) (*UpdateMessageStatusResponse, error)
 	GetLastMessages(ctx context.Context, in *GetLastMessagesRequest, opts ...grpc.CallOption) (*GetLastMessagesResponse, error)
 	GetUnreadCounts(ctx context.Context, in *GetUnreadCountsRequest, opts ...grpc.CallOption) (*GetUnreadCountsResponse, error)
+	EditMessage(ctx context.Context, in *EditMessageRequest, opts ...grpc.CallOption) (*EditMessageResponse, error)
+	UpdateLiveLocation(ctx context.Context, in *UpdateLiveLocationRequest, opts ...grpc.CallOption) (*UpdateLiveLocationResponse, error)
+	ExportChatMessages(ctx context.Context, in *ExportChatMessagesRequest, opts ...grpc.CallOption) (*ExportChatMessagesResponse, error)
 }
 
 type messageServiceClient struct {
@@ -83,6 +89,36 @@ func (c *messageServiceClient) GetUnreadCounts(ctx context.Context, in *GetUnrea
 	return out, nil
 }
 
+func (c *messageServiceClient) EditMessage(ctx context.Context, in *EditMessageRequest, opts ...grpc.CallOption) (*EditMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EditMessageResponse)
+	err := c.cc.Invoke(ctx, MessageService_EditMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messageServiceClient) UpdateLiveLocation(ctx context.Context, in *UpdateLiveLocationRequest, opts ...grpc.CallOption) (*UpdateLiveLocationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateLiveLocationResponse)
+	err := c.cc.Invoke(ctx, MessageService_UpdateLiveLocation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messageServiceClient) ExportChatMessages(ctx context.Context, in *ExportChatMessagesRequest, opts ...grpc.CallOption) (*ExportChatMessagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportChatMessagesResponse)
+	err := c.cc.Invoke(ctx, MessageService_ExportChatMessages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MessageServiceServer is the server API for MessageService service.
 // All implementations must embed UnimplementedMessageServiceServer
 // for forward compatibility.
@@ -91,6 +127,9 @@ type MessageServiceServer interface {
 	UpdateMessageStatus(context.Context, *UpdateMessageStatusRequest) (*UpdateMessageStatusResponse, error)
 	GetLastMessages(context.Context, *GetLastMessagesRequest) (*GetLastMessagesResponse, error)
 	GetUnreadCounts(context.Context, *GetUnreadCountsRequest) (*GetUnreadCountsResponse, error)
+	EditMessage(context.Context, *EditMessageRequest) (*EditMessageResponse, error)
+	UpdateLiveLocation(context.Context, *UpdateLiveLocationRequest) (*UpdateLiveLocationResponse, error)
+	ExportChatMessages(context.Context, *ExportChatMessagesRequest) (*ExportChatMessagesResponse, error)
 	mustEmbedUnimplementedMessageServiceServer()
 }
 
@@ -113,6 +152,15 @@ func (UnimplementedMessageServiceServer) GetLastMessages(context.Context, *GetLa
 func (UnimplementedMessageServiceServer) GetUnreadCounts(context.Context, *GetUnreadCountsRequest) (*GetUnreadCountsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetUnreadCounts not implemented")
 }
+func (UnimplementedMessageServiceServer) EditMessage(context.Context, *EditMessageRequest) (*EditMessageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EditMessage not implemented")
+}
+func (UnimplementedMessageServiceServer) UpdateLiveLocation(context.Context, *UpdateLiveLocationRequest) (*UpdateLiveLocationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateLiveLocation not implemented")
+}
+func (UnimplementedMessageServiceServer) ExportChatMessages(context.Context, *ExportChatMessagesRequest) (*ExportChatMessagesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportChatMessages not implemented")
+}
 func (UnimplementedMessageServiceServer) mustEmbedUnimplementedMessageServiceServer() {}
 func (UnimplementedMessageServiceServer) testEmbeddedByValue()                        {}
 
@@ -206,6 +254,60 @@ func _MessageService_GetUnreadCounts_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MessageService_EditMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessageServiceServer).EditMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessageService_EditMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessageServiceServer).EditMessage(ctx, req.(*EditMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessageService_UpdateLiveLocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLiveLocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessageServiceServer).UpdateLiveLocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessageService_UpdateLiveLocation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessageServiceServer).UpdateLiveLocation(ctx, req.(*UpdateLiveLocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessageService_ExportChatMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportChatMessagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessageServiceServer).ExportChatMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessageService_ExportChatMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessageServiceServer).ExportChatMessages(ctx, req.(*ExportChatMessagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MessageService_ServiceDesc is the grpc.ServiceDesc for MessageService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,6 +331,18 @@ var MessageService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUnreadCounts",
 			Handler:    _MessageService_GetUnreadCounts_Handler,
 		},
+		{
+			MethodName: "EditMessage",
+			Handler:    _MessageService_EditMessage_Handler,
+		},
+		{
+			MethodName: "UpdateLiveLocation",
+			Handler:    _MessageService_UpdateLiveLocation_Handler,
+		},
+		{
+			MethodName: "ExportChatMessages",
+			Handler:    _MessageService_ExportChatMessages_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/message/v1/message.proto",
@@ -198,6 +198,52 @@ func (x *GetUsersResponse) GetUsers() []*UserProfile {
 	return nil
 }
 
+// GetUsersByPhonesRequest looks up registered users by E.164 phone number.
+// Numbers that are not registered are left out of the response.
+type GetUsersByPhonesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Phones        []string               `protobuf:"bytes,1,rep,name=phones,proto3" json:"phones,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsersByPhonesRequest) Reset() {
+	*x = GetUsersByPhonesRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsersByPhonesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsersByPhonesRequest) ProtoMessage() {}
+
+func (x *GetUsersByPhonesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsersByPhonesRequest.ProtoReflect.Descriptor instead.
+func (*GetUsersByPhonesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetUsersByPhonesRequest) GetPhones() []string {
+	if x != nil {
+		return x.Phones
+	}
+	return nil
+}
+
 type UserProfile struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -213,7 +259,7 @@ type UserProfile struct {
 
 func (x *UserProfile) Reset() {
 	*x = UserProfile{}
-	mi := &file_proto_user_v1_user_proto_msgTypes[4]
+	mi := &file_proto_user_v1_user_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -225,7 +271,7 @@ func (x *UserProfile) String() string {
 func (*UserProfile) ProtoMessage() {}
 
 func (x *UserProfile) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_user_v1_user_proto_msgTypes[4]
+	mi := &file_proto_user_v1_user_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -238,7 +284,7 @@ func (x *UserProfile) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserProfile.ProtoReflect.Descriptor instead.
 func (*UserProfile) Descriptor() ([]byte, []int) {
-	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{4}
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *UserProfile) GetUserId() string {
@@ -299,7 +345,7 @@ type CheckPresenceRequest struct {
 
 func (x *CheckPresenceRequest) Reset() {
 	*x = CheckPresenceRequest{}
-	mi := &file_proto_user_v1_user_proto_msgTypes[5]
+	mi := &file_proto_user_v1_user_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -311,7 +357,7 @@ func (x *CheckPresenceRequest) String() string {
 func (*CheckPresenceRequest) ProtoMessage() {}
 
 func (x *CheckPresenceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_user_v1_user_proto_msgTypes[5]
+	mi := &file_proto_user_v1_user_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -324,7 +370,7 @@ func (x *CheckPresenceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CheckPresenceRequest.ProtoReflect.Descriptor instead.
 func (*CheckPresenceRequest) Descriptor() ([]byte, []int) {
-	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{5}
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CheckPresenceRequest) GetUserId() string {
@@ -344,7 +390,7 @@ type CheckPresenceResponse struct {
 
 func (x *CheckPresenceResponse) Reset() {
 	*x = CheckPresenceResponse{}
-	mi := &file_proto_user_v1_user_proto_msgTypes[6]
+	mi := &file_proto_user_v1_user_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -356,7 +402,7 @@ func (x *CheckPresenceResponse) String() string {
 func (*CheckPresenceResponse) ProtoMessage() {}
 
 func (x *CheckPresenceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_user_v1_user_proto_msgTypes[6]
+	mi := &file_proto_user_v1_user_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -369,7 +415,7 @@ func (x *CheckPresenceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CheckPresenceResponse.ProtoReflect.Descriptor instead.
 func (*CheckPresenceResponse) Descriptor() ([]byte, []int) {
-	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{6}
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *CheckPresenceResponse) GetOnline() bool {
@@ -395,7 +441,7 @@ type GetPrivacySettingsRequest struct {
 
 func (x *GetPrivacySettingsRequest) Reset() {
 	*x = GetPrivacySettingsRequest{}
-	mi := &file_proto_user_v1_user_proto_msgTypes[7]
+	mi := &file_proto_user_v1_user_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -407,7 +453,7 @@ func (x *GetPrivacySettingsRequest) String() string {
 func (*GetPrivacySettingsRequest) ProtoMessage() {}
 
 func (x *GetPrivacySettingsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_user_v1_user_proto_msgTypes[7]
+	mi := &file_proto_user_v1_user_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -420,7 +466,7 @@ func (x *GetPrivacySettingsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPrivacySettingsRequest.ProtoReflect.Descriptor instead.
 func (*GetPrivacySettingsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{7}
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GetPrivacySettingsRequest) GetUserId() string {
@@ -442,7 +488,7 @@ type GetPrivacySettingsResponse struct {
 
 func (x *GetPrivacySettingsResponse) Reset() {
 	*x = GetPrivacySettingsResponse{}
-	mi := &file_proto_user_v1_user_proto_msgTypes[8]
+	mi := &file_proto_user_v1_user_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -454,7 +500,7 @@ func (x *GetPrivacySettingsResponse) String() string {
 func (*GetPrivacySettingsResponse) ProtoMessage() {}
 
 func (x *GetPrivacySettingsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_user_v1_user_proto_msgTypes[8]
+	mi := &file_proto_user_v1_user_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -467,7 +513,7 @@ func (x *GetPrivacySettingsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPrivacySettingsResponse.ProtoReflect.Descriptor instead.
 func (*GetPrivacySettingsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{8}
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetPrivacySettingsResponse) GetLastSeen() string {
@@ -498,48 +544,818 @@ func (x *GetPrivacySettingsResponse) GetReadReceipts() bool {
 	return false
 }
 
-var File_proto_user_v1_user_proto protoreflect.FileDescriptor
+type ExportUserDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_user_v1_user_proto_rawDesc = "" +
-	"\n" +
-	"\x18proto/user/v1/user.proto\x12\auser.v1\x1a\x1fgoogle/protobuf/timestamp.proto\")\n" +
-	"\x0eGetUserRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\";\n" +
-	"\x0fGetUserResponse\x12(\n" +
-	"\x04user\x18\x01 \x01(\v2\x14.user.v1.UserProfileR\x04user\",\n" +
-	"\x0fGetUsersRequest\x12\x19\n" +
-	"\buser_ids\x18\x01 \x03(\tR\auserIds\">\n" +
-	"\x10GetUsersResponse\x12*\n" +
-	"\x05users\x18\x01 \x03(\v2\x14.user.v1.UserProfileR\x05users\"\x95\x02\n" +
-	"\vUserProfile\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
-	"\x05phone\x18\x02 \x01(\tR\x05phone\x12!\n" +
-	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x1d\n" +
-	"\n" +
-	"avatar_url\x18\x04 \x01(\tR\tavatarUrl\x12\x1f\n" +
-	"\vstatus_text\x18\x05 \x01(\tR\n" +
-	"statusText\x129\n" +
-	"\n" +
-	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
-	"\n" +
-	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"/\n" +
-	"\x14CheckPresenceRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"h\n" +
-	"\x15CheckPresenceResponse\x12\x16\n" +
-	"\x06online\x18\x01 \x01(\bR\x06online\x127\n" +
-	"\tlast_seen\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\"4\n" +
-	"\x19GetPrivacySettingsRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x99\x01\n" +
-	"\x1aGetPrivacySettingsResponse\x12\x1b\n" +
-	"\tlast_seen\x18\x01 \x01(\tR\blastSeen\x12#\n" +
-	"\rprofile_photo\x18\x02 \x01(\tR\fprofilePhoto\x12\x14\n" +
-	"\x05about\x18\x03 \x01(\tR\x05about\x12#\n" +
-	"\rread_receipts\x18\x04 \x01(\bR\freadReceipts2\xbb\x02\n" +
+func (x *ExportUserDataRequest) Reset() {
+	*x = ExportUserDataRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUserDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserDataRequest) ProtoMessage() {}
+
+func (x *ExportUserDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserDataRequest.ProtoReflect.Descriptor instead.
+func (*ExportUserDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ExportUserDataRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// ExportUserDataResponse carries the user's own data for a personal data
+// export, as a JSON object keyed by section ("profile", "contacts",
+// "privacy", "statuses") in the same shape the REST API returns.
+type ExportUserDataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUserDataResponse) Reset() {
+	*x = ExportUserDataResponse{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUserDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserDataResponse) ProtoMessage() {}
+
+func (x *ExportUserDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserDataResponse.ProtoReflect.Descriptor instead.
+func (*ExportUserDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ExportUserDataResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type IsBlockedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OtherUserId   string                 `protobuf:"bytes,2,opt,name=other_user_id,json=otherUserId,proto3" json:"other_user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IsBlockedRequest) Reset() {
+	*x = IsBlockedRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IsBlockedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IsBlockedRequest) ProtoMessage() {}
+
+func (x *IsBlockedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IsBlockedRequest.ProtoReflect.Descriptor instead.
+func (*IsBlockedRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *IsBlockedRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *IsBlockedRequest) GetOtherUserId() string {
+	if x != nil {
+		return x.OtherUserId
+	}
+	return ""
+}
+
+// IsBlockedResponse reports blocks between two users in both directions.
+// Either one stops them from reaching each other.
+type IsBlockedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Blocked       bool                   `protobuf:"varint,1,opt,name=blocked,proto3" json:"blocked,omitempty"`                      // true if either user has blocked the other
+	Blocking      bool                   `protobuf:"varint,2,opt,name=blocking,proto3" json:"blocking,omitempty"`                    // user_id has blocked other_user_id
+	BlockedBy     bool                   `protobuf:"varint,3,opt,name=blocked_by,json=blockedBy,proto3" json:"blocked_by,omitempty"` // other_user_id has blocked user_id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IsBlockedResponse) Reset() {
+	*x = IsBlockedResponse{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IsBlockedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IsBlockedResponse) ProtoMessage() {}
+
+func (x *IsBlockedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IsBlockedResponse.ProtoReflect.Descriptor instead.
+func (*IsBlockedResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *IsBlockedResponse) GetBlocked() bool {
+	if x != nil {
+		return x.Blocked
+	}
+	return false
+}
+
+func (x *IsBlockedResponse) GetBlocking() bool {
+	if x != nil {
+		return x.Blocking
+	}
+	return false
+}
+
+func (x *IsBlockedResponse) GetBlockedBy() bool {
+	if x != nil {
+		return x.BlockedBy
+	}
+	return false
+}
+
+type GetBlockRelationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OtherUserIds  []string               `protobuf:"bytes,2,rep,name=other_user_ids,json=otherUserIds,proto3" json:"other_user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBlockRelationsRequest) Reset() {
+	*x = GetBlockRelationsRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBlockRelationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlockRelationsRequest) ProtoMessage() {}
+
+func (x *GetBlockRelationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlockRelationsRequest.ProtoReflect.Descriptor instead.
+func (*GetBlockRelationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetBlockRelationsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetBlockRelationsRequest) GetOtherUserIds() []string {
+	if x != nil {
+		return x.OtherUserIds
+	}
+	return nil
+}
+
+// GetBlockRelationsResponse lists only the other users with a block in
+// either direction; anyone missing can be reached.
+type GetBlockRelationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Relations     []*BlockRelation       `protobuf:"bytes,1,rep,name=relations,proto3" json:"relations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBlockRelationsResponse) Reset() {
+	*x = GetBlockRelationsResponse{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBlockRelationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlockRelationsResponse) ProtoMessage() {}
+
+func (x *GetBlockRelationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlockRelationsResponse.ProtoReflect.Descriptor instead.
+func (*GetBlockRelationsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetBlockRelationsResponse) GetRelations() []*BlockRelation {
+	if x != nil {
+		return x.Relations
+	}
+	return nil
+}
+
+type BlockRelation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`           // the other user
+	Blocking      bool                   `protobuf:"varint,2,opt,name=blocking,proto3" json:"blocking,omitempty"`                    // the requesting user has blocked them
+	BlockedBy     bool                   `protobuf:"varint,3,opt,name=blocked_by,json=blockedBy,proto3" json:"blocked_by,omitempty"` // they have blocked the requesting user
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockRelation) Reset() {
+	*x = BlockRelation{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockRelation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockRelation) ProtoMessage() {}
+
+func (x *BlockRelation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockRelation.ProtoReflect.Descriptor instead.
+func (*BlockRelation) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BlockRelation) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *BlockRelation) GetBlocking() bool {
+	if x != nil {
+		return x.Blocking
+	}
+	return false
+}
+
+func (x *BlockRelation) GetBlockedBy() bool {
+	if x != nil {
+		return x.BlockedBy
+	}
+	return false
+}
+
+// GetPresenceVisibilityRequest asks about every pair of a viewer and a user.
+// Either viewer_ids or user_ids must hold exactly one user.
+type GetPresenceVisibilityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ViewerIds     []string               `protobuf:"bytes,1,rep,name=viewer_ids,json=viewerIds,proto3" json:"viewer_ids,omitempty"`
+	UserIds       []string               `protobuf:"bytes,2,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPresenceVisibilityRequest) Reset() {
+	*x = GetPresenceVisibilityRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPresenceVisibilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPresenceVisibilityRequest) ProtoMessage() {}
+
+func (x *GetPresenceVisibilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPresenceVisibilityRequest.ProtoReflect.Descriptor instead.
+func (*GetPresenceVisibilityRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetPresenceVisibilityRequest) GetViewerIds() []string {
+	if x != nil {
+		return x.ViewerIds
+	}
+	return nil
+}
+
+func (x *GetPresenceVisibilityRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+type GetPresenceVisibilityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Visibilities  []*PresenceVisibility  `protobuf:"bytes,1,rep,name=visibilities,proto3" json:"visibilities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPresenceVisibilityResponse) Reset() {
+	*x = GetPresenceVisibilityResponse{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPresenceVisibilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPresenceVisibilityResponse) ProtoMessage() {}
+
+func (x *GetPresenceVisibilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPresenceVisibilityResponse.ProtoReflect.Descriptor instead.
+func (*GetPresenceVisibilityResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetPresenceVisibilityResponse) GetVisibilities() []*PresenceVisibility {
+	if x != nil {
+		return x.Visibilities
+	}
+	return nil
+}
+
+// PresenceVisibility is what viewer_id may see of user_id's presence under
+// both users' privacy settings and any block between them.
+type PresenceVisibility struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ViewerId      string                 `protobuf:"bytes,1,opt,name=viewer_id,json=viewerId,proto3" json:"viewer_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Online        bool                   `protobuf:"varint,3,opt,name=online,proto3" json:"online,omitempty"`                     // viewer may see user_id online
+	LastSeen      bool                   `protobuf:"varint,4,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"` // viewer may see user_id's last seen
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PresenceVisibility) Reset() {
+	*x = PresenceVisibility{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PresenceVisibility) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PresenceVisibility) ProtoMessage() {}
+
+func (x *PresenceVisibility) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PresenceVisibility.ProtoReflect.Descriptor instead.
+func (*PresenceVisibility) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *PresenceVisibility) GetViewerId() string {
+	if x != nil {
+		return x.ViewerId
+	}
+	return ""
+}
+
+func (x *PresenceVisibility) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PresenceVisibility) GetOnline() bool {
+	if x != nil {
+		return x.Online
+	}
+	return false
+}
+
+func (x *PresenceVisibility) GetLastSeen() bool {
+	if x != nil {
+		return x.LastSeen
+	}
+	return false
+}
+
+type GetReadReceiptSettingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserIds       []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReadReceiptSettingsRequest) Reset() {
+	*x = GetReadReceiptSettingsRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReadReceiptSettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReadReceiptSettingsRequest) ProtoMessage() {}
+
+func (x *GetReadReceiptSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReadReceiptSettingsRequest.ProtoReflect.Descriptor instead.
+func (*GetReadReceiptSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetReadReceiptSettingsRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+// GetReadReceiptSettingsResponse lists those of the requested users who
+// turned read receipts off; the rest have them on.
+type GetReadReceiptSettingsResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ReceiptsOffUserIds []string               `protobuf:"bytes,1,rep,name=receipts_off_user_ids,json=receiptsOffUserIds,proto3" json:"receipts_off_user_ids,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetReadReceiptSettingsResponse) Reset() {
+	*x = GetReadReceiptSettingsResponse{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReadReceiptSettingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReadReceiptSettingsResponse) ProtoMessage() {}
+
+func (x *GetReadReceiptSettingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReadReceiptSettingsResponse.ProtoReflect.Descriptor instead.
+func (*GetReadReceiptSettingsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetReadReceiptSettingsResponse) GetReceiptsOffUserIds() []string {
+	if x != nil {
+		return x.ReceiptsOffUserIds
+	}
+	return nil
+}
+
+type CheckGroupAddRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AdderId       string                 `protobuf:"bytes,1,opt,name=adder_id,json=adderId,proto3" json:"adder_id,omitempty"`
+	UserIds       []string               `protobuf:"bytes,2,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckGroupAddRequest) Reset() {
+	*x = CheckGroupAddRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckGroupAddRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckGroupAddRequest) ProtoMessage() {}
+
+func (x *CheckGroupAddRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckGroupAddRequest.ProtoReflect.Descriptor instead.
+func (*CheckGroupAddRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CheckGroupAddRequest) GetAdderId() string {
+	if x != nil {
+		return x.AdderId
+	}
+	return ""
+}
+
+func (x *CheckGroupAddRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+// CheckGroupAddResponse lists those of the requested users whose group_add
+// privacy setting keeps adder_id from adding them; they can only be invited.
+type CheckGroupAddResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeniedUserIds []string               `protobuf:"bytes,1,rep,name=denied_user_ids,json=deniedUserIds,proto3" json:"denied_user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckGroupAddResponse) Reset() {
+	*x = CheckGroupAddResponse{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckGroupAddResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckGroupAddResponse) ProtoMessage() {}
+
+func (x *CheckGroupAddResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckGroupAddResponse.ProtoReflect.Descriptor instead.
+func (*CheckGroupAddResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CheckGroupAddResponse) GetDeniedUserIds() []string {
+	if x != nil {
+		return x.DeniedUserIds
+	}
+	return nil
+}
+
+var File_proto_user_v1_user_proto protoreflect.FileDescriptor
+
+const file_proto_user_v1_user_proto_rawDesc = "" +
+	"\n" +
+	"\x18proto/user/v1/user.proto\x12\auser.v1\x1a\x1fgoogle/protobuf/timestamp.proto\")\n" +
+	"\x0eGetUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\";\n" +
+	"\x0fGetUserResponse\x12(\n" +
+	"\x04user\x18\x01 \x01(\v2\x14.user.v1.UserProfileR\x04user\",\n" +
+	"\x0fGetUsersRequest\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\tR\auserIds\">\n" +
+	"\x10GetUsersResponse\x12*\n" +
+	"\x05users\x18\x01 \x03(\v2\x14.user.v1.UserProfileR\x05users\"1\n" +
+	"\x17GetUsersByPhonesRequest\x12\x16\n" +
+	"\x06phones\x18\x01 \x03(\tR\x06phones\"\x95\x02\n" +
+	"\vUserProfile\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05phone\x18\x02 \x01(\tR\x05phone\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x04 \x01(\tR\tavatarUrl\x12\x1f\n" +
+	"\vstatus_text\x18\x05 \x01(\tR\n" +
+	"statusText\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"/\n" +
+	"\x14CheckPresenceRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"h\n" +
+	"\x15CheckPresenceResponse\x12\x16\n" +
+	"\x06online\x18\x01 \x01(\bR\x06online\x127\n" +
+	"\tlast_seen\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\"4\n" +
+	"\x19GetPrivacySettingsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x99\x01\n" +
+	"\x1aGetPrivacySettingsResponse\x12\x1b\n" +
+	"\tlast_seen\x18\x01 \x01(\tR\blastSeen\x12#\n" +
+	"\rprofile_photo\x18\x02 \x01(\tR\fprofilePhoto\x12\x14\n" +
+	"\x05about\x18\x03 \x01(\tR\x05about\x12#\n" +
+	"\rread_receipts\x18\x04 \x01(\bR\freadReceipts\"0\n" +
+	"\x15ExportUserDataRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\",\n" +
+	"\x16ExportUserDataResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"O\n" +
+	"\x10IsBlockedRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\"\n" +
+	"\rother_user_id\x18\x02 \x01(\tR\votherUserId\"h\n" +
+	"\x11IsBlockedResponse\x12\x18\n" +
+	"\ablocked\x18\x01 \x01(\bR\ablocked\x12\x1a\n" +
+	"\bblocking\x18\x02 \x01(\bR\bblocking\x12\x1d\n" +
+	"\n" +
+	"blocked_by\x18\x03 \x01(\bR\tblockedBy\"Y\n" +
+	"\x18GetBlockRelationsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
+	"\x0eother_user_ids\x18\x02 \x03(\tR\fotherUserIds\"Q\n" +
+	"\x19GetBlockRelationsResponse\x124\n" +
+	"\trelations\x18\x01 \x03(\v2\x16.user.v1.BlockRelationR\trelations\"c\n" +
+	"\rBlockRelation\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bblocking\x18\x02 \x01(\bR\bblocking\x12\x1d\n" +
+	"\n" +
+	"blocked_by\x18\x03 \x01(\bR\tblockedBy\"X\n" +
+	"\x1cGetPresenceVisibilityRequest\x12\x1d\n" +
+	"\n" +
+	"viewer_ids\x18\x01 \x03(\tR\tviewerIds\x12\x19\n" +
+	"\buser_ids\x18\x02 \x03(\tR\auserIds\"`\n" +
+	"\x1dGetPresenceVisibilityResponse\x12?\n" +
+	"\fvisibilities\x18\x01 \x03(\v2\x1b.user.v1.PresenceVisibilityR\fvisibilities\"\x7f\n" +
+	"\x12PresenceVisibility\x12\x1b\n" +
+	"\tviewer_id\x18\x01 \x01(\tR\bviewerId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06online\x18\x03 \x01(\bR\x06online\x12\x1b\n" +
+	"\tlast_seen\x18\x04 \x01(\bR\blastSeen\":\n" +
+	"\x1dGetReadReceiptSettingsRequest\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\tR\auserIds\"S\n" +
+	"\x1eGetReadReceiptSettingsResponse\x121\n" +
+	"\x15receipts_off_user_ids\x18\x01 \x03(\tR\x12receiptsOffUserIds\"L\n" +
+	"\x14CheckGroupAddRequest\x12\x19\n" +
+	"\badder_id\x18\x01 \x01(\tR\aadderId\x12\x19\n" +
+	"\buser_ids\x18\x02 \x03(\tR\auserIds\"?\n" +
+	"\x15CheckGroupAddResponse\x12&\n" +
+	"\x0fdenied_user_ids\x18\x01 \x03(\tR\rdeniedUserIds2\xa2\a\n" +
 	"\vUserService\x12<\n" +
 	"\aGetUser\x12\x17.user.v1.GetUserRequest\x1a\x18.user.v1.GetUserResponse\x12?\n" +
-	"\bGetUsers\x12\x18.user.v1.GetUsersRequest\x1a\x19.user.v1.GetUsersResponse\x12N\n" +
+	"\bGetUsers\x12\x18.user.v1.GetUsersRequest\x1a\x19.user.v1.GetUsersResponse\x12O\n" +
+	"\x10GetUsersByPhones\x12 .user.v1.GetUsersByPhonesRequest\x1a\x19.user.v1.GetUsersResponse\x12N\n" +
 	"\rCheckPresence\x12\x1d.user.v1.CheckPresenceRequest\x1a\x1e.user.v1.CheckPresenceResponse\x12]\n" +
-	"\x12GetPrivacySettings\x12\".user.v1.GetPrivacySettingsRequest\x1a#.user.v1.GetPrivacySettingsResponseB8Z6github.com/whatsapp-clone/backend/proto/user/v1;userv1b\x06proto3"
+	"\x12GetPrivacySettings\x12\".user.v1.GetPrivacySettingsRequest\x1a#.user.v1.GetPrivacySettingsResponse\x12Q\n" +
+	"\x0eExportUserData\x12\x1e.user.v1.ExportUserDataRequest\x1a\x1f.user.v1.ExportUserDataResponse\x12B\n" +
+	"\tIsBlocked\x12\x19.user.v1.IsBlockedRequest\x1a\x1a.user.v1.IsBlockedResponse\x12Z\n" +
+	"\x11GetBlockRelations\x12!.user.v1.GetBlockRelationsRequest\x1a\".user.v1.GetBlockRelationsResponse\x12f\n" +
+	"\x15GetPresenceVisibility\x12%.user.v1.GetPresenceVisibilityRequest\x1a&.user.v1.GetPresenceVisibilityResponse\x12i\n" +
+	"\x16GetReadReceiptSettings\x12&.user.v1.GetReadReceiptSettingsRequest\x1a'.user.v1.GetReadReceiptSettingsResponse\x12N\n" +
+	"\rCheckGroupAdd\x12\x1d.user.v1.CheckGroupAddRequest\x1a\x1e.user.v1.CheckGroupAddResponseB8Z6github.com/whatsapp-clone/backend/proto/user/v1;userv1b\x06proto3"
 
 var (
 	file_proto_user_v1_user_proto_rawDescOnce sync.Once
@@ -553,38 +1369,69 @@ func file_proto_user_v1_user_proto_rawDescGZIP() []byte {
 	return file_proto_user_v1_user_proto_rawDescData
 }
 
-var file_proto_user_v1_user_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_proto_user_v1_user_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_proto_user_v1_user_proto_goTypes = []any{
-	(*GetUserRequest)(nil),             // 0: user.v1.GetUserRequest
-	(*GetUserResponse)(nil),            // 1: user.v1.GetUserResponse
-	(*GetUsersRequest)(nil),            // 2: user.v1.GetUsersRequest
-	(*GetUsersResponse)(nil),           // 3: user.v1.GetUsersResponse
-	(*UserProfile)(nil),                // 4: user.v1.UserProfile
-	(*CheckPresenceRequest)(nil),       // 5: user.v1.CheckPresenceRequest
-	(*CheckPresenceResponse)(nil),      // 6: user.v1.CheckPresenceResponse
-	(*GetPrivacySettingsRequest)(nil),  // 7: user.v1.GetPrivacySettingsRequest
-	(*GetPrivacySettingsResponse)(nil), // 8: user.v1.GetPrivacySettingsResponse
-	(*timestamppb.Timestamp)(nil),      // 9: google.protobuf.Timestamp
+	(*GetUserRequest)(nil),                 // 0: user.v1.GetUserRequest
+	(*GetUserResponse)(nil),                // 1: user.v1.GetUserResponse
+	(*GetUsersRequest)(nil),                // 2: user.v1.GetUsersRequest
+	(*GetUsersResponse)(nil),               // 3: user.v1.GetUsersResponse
+	(*GetUsersByPhonesRequest)(nil),        // 4: user.v1.GetUsersByPhonesRequest
+	(*UserProfile)(nil),                    // 5: user.v1.UserProfile
+	(*CheckPresenceRequest)(nil),           // 6: user.v1.CheckPresenceRequest
+	(*CheckPresenceResponse)(nil),          // 7: user.v1.CheckPresenceResponse
+	(*GetPrivacySettingsRequest)(nil),      // 8: user.v1.GetPrivacySettingsRequest
+	(*GetPrivacySettingsResponse)(nil),     // 9: user.v1.GetPrivacySettingsResponse
+	(*ExportUserDataRequest)(nil),          // 10: user.v1.ExportUserDataRequest
+	(*ExportUserDataResponse)(nil),         // 11: user.v1.ExportUserDataResponse
+	(*IsBlockedRequest)(nil),               // 12: user.v1.IsBlockedRequest
+	(*IsBlockedResponse)(nil),              // 13: user.v1.IsBlockedResponse
+	(*GetBlockRelationsRequest)(nil),       // 14: user.v1.GetBlockRelationsRequest
+	(*GetBlockRelationsResponse)(nil),      // 15: user.v1.GetBlockRelationsResponse
+	(*BlockRelation)(nil),                  // 16: user.v1.BlockRelation
+	(*GetPresenceVisibilityRequest)(nil),   // 17: user.v1.GetPresenceVisibilityRequest
+	(*GetPresenceVisibilityResponse)(nil),  // 18: user.v1.GetPresenceVisibilityResponse
+	(*PresenceVisibility)(nil),             // 19: user.v1.PresenceVisibility
+	(*GetReadReceiptSettingsRequest)(nil),  // 20: user.v1.GetReadReceiptSettingsRequest
+	(*GetReadReceiptSettingsResponse)(nil), // 21: user.v1.GetReadReceiptSettingsResponse
+	(*CheckGroupAddRequest)(nil),           // 22: user.v1.CheckGroupAddRequest
+	(*CheckGroupAddResponse)(nil),          // 23: user.v1.CheckGroupAddResponse
+	(*timestamppb.Timestamp)(nil),          // 24: google.protobuf.Timestamp
 }
 var file_proto_user_v1_user_proto_depIdxs = []int32{
-	4, // 0: user.v1.GetUserResponse.user:type_name -> user.v1.UserProfile
-	4, // 1: user.v1.GetUsersResponse.users:type_name -> user.v1.UserProfile
-	9, // 2: user.v1.UserProfile.created_at:type_name -> google.protobuf.Timestamp
-	9, // 3: user.v1.UserProfile.updated_at:type_name -> google.protobuf.Timestamp
-	9, // 4: user.v1.CheckPresenceResponse.last_seen:type_name -> google.protobuf.Timestamp
-	0, // 5: user.v1.UserService.GetUser:input_type -> user.v1.GetUserRequest
-	2, // 6: user.v1.UserService.GetUsers:input_type -> user.v1.GetUsersRequest
-	5, // 7: user.v1.UserService.CheckPresence:input_type -> user.v1.CheckPresenceRequest
-	7, // 8: user.v1.UserService.GetPrivacySettings:input_type -> user.v1.GetPrivacySettingsRequest
-	1, // 9: user.v1.UserService.GetUser:output_type -> user.v1.GetUserResponse
-	3, // 10: user.v1.UserService.GetUsers:output_type -> user.v1.GetUsersResponse
-	6, // 11: user.v1.UserService.CheckPresence:output_type -> user.v1.CheckPresenceResponse
-	8, // 12: user.v1.UserService.GetPrivacySettings:output_type -> user.v1.GetPrivacySettingsResponse
-	9, // [9:13] is the sub-list for method output_type
-	5, // [5:9] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	5,  // 0: user.v1.GetUserResponse.user:type_name -> user.v1.UserProfile
+	5,  // 1: user.v1.GetUsersResponse.users:type_name -> user.v1.UserProfile
+	24, // 2: user.v1.UserProfile.created_at:type_name -> google.protobuf.Timestamp
+	24, // 3: user.v1.UserProfile.updated_at:type_name -> google.protobuf.Timestamp
+	24, // 4: user.v1.CheckPresenceResponse.last_seen:type_name -> google.protobuf.Timestamp
+	16, // 5: user.v1.GetBlockRelationsResponse.relations:type_name -> user.v1.BlockRelation
+	19, // 6: user.v1.GetPresenceVisibilityResponse.visibilities:type_name -> user.v1.PresenceVisibility
+	0,  // 7: user.v1.UserService.GetUser:input_type -> user.v1.GetUserRequest
+	2,  // 8: user.v1.UserService.GetUsers:input_type -> user.v1.GetUsersRequest
+	4,  // 9: user.v1.UserService.GetUsersByPhones:input_type -> user.v1.GetUsersByPhonesRequest
+	6,  // 10: user.v1.UserService.CheckPresence:input_type -> user.v1.CheckPresenceRequest
+	8,  // 11: user.v1.UserService.GetPrivacySettings:input_type -> user.v1.GetPrivacySettingsRequest
+	10, // 12: user.v1.UserService.ExportUserData:input_type -> user.v1.ExportUserDataRequest
+	12, // 13: user.v1.UserService.IsBlocked:input_type -> user.v1.IsBlockedRequest
+	14, // 14: user.v1.UserService.GetBlockRelations:input_type -> user.v1.GetBlockRelationsRequest
+	17, // 15: user.v1.UserService.GetPresenceVisibility:input_type -> user.v1.GetPresenceVisibilityRequest
+	20, // 16: user.v1.UserService.GetReadReceiptSettings:input_type -> user.v1.GetReadReceiptSettingsRequest
+	22, // 17: user.v1.UserService.CheckGroupAdd:input_type -> user.v1.CheckGroupAddRequest
+	1,  // 18: user.v1.UserService.GetUser:output_type -> user.v1.GetUserResponse
+	3,  // 19: user.v1.UserService.GetUsers:output_type -> user.v1.GetUsersResponse
+	3,  // 20: user.v1.UserService.GetUsersByPhones:output_type -> user.v1.GetUsersResponse
+	7,  // 21: user.v1.UserService.CheckPresence:output_type -> user.v1.CheckPresenceResponse
+	9,  // 22: user.v1.UserService.GetPrivacySettings:output_type -> user.v1.GetPrivacySettingsResponse
+	11, // 23: user.v1.UserService.ExportUserData:output_type -> user.v1.ExportUserDataResponse
+	13, // 24: user.v1.UserService.IsBlocked:output_type -> user.v1.IsBlockedResponse
+	15, // 25: user.v1.UserService.GetBlockRelations:output_type -> user.v1.GetBlockRelationsResponse
+	18, // 26: user.v1.UserService.GetPresenceVisibility:output_type -> user.v1.GetPresenceVisibilityResponse
+	21, // 27: user.v1.UserService.GetReadReceiptSettings:output_type -> user.v1.GetReadReceiptSettingsResponse
+	23, // 28: user.v1.UserService.CheckGroupAdd:output_type -> user.v1.CheckGroupAddResponse
+	18, // [18:29] is the sub-list for method output_type
+	7,  // [7:18] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_proto_user_v1_user_proto_init() }
@@ -598,7 +1445,7 @@ func file_proto_user_v1_user_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_user_v1_user_proto_rawDesc), len(file_proto_user_v1_user_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,10 +19,17 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	UserService_GetUser_FullMethodName            = "/user.v1.UserService/GetUser"
-	UserService_GetUsers_FullMethodName           = "/user.v1.UserService/GetUsers"
-	UserService_CheckPresence_FullMethodName      = "/user.v1.UserService/CheckPresence"
-	UserService_GetPrivacySettings_FullMethodName = "/user.v1.UserService/GetPrivacySettings"
+	UserService_GetUser_FullMethodName                = "/user.v1.UserService/GetUser"
+	UserService_GetUsers_FullMethodName               = "/user.v1.UserService/GetUsers"
+	UserService_GetUsersByPhones_FullMethodName       = "/user.v1.UserService/GetUsersByPhones"
+	UserService_CheckPresence_FullMethodName          = "/user.v1.UserService/CheckPresence"
+	UserService_GetPrivacySettings_FullMethodName     = "/user.v1.UserService/GetPrivacySettings"
+	UserService_ExportUserData_FullMethodName         = "/user.v1.UserService/ExportUserData"
+	UserService_IsBlocked_FullMethodName              = "/user.v1.UserService/IsBlocked"
+	UserService_GetBlockRelations_FullMethodName      = "/user.v1.UserService/GetBlockRelations"
+	UserService_GetPresenceVisibility_FullMethodName  = "/user.v1.UserService/GetPresenceVisibility"
+	UserService_GetReadReceiptSettings_FullMethodName = "/user.v1.UserService/GetReadReceiptSettings"
+	UserService_CheckGroupAdd_FullMethodName          = "/user.v1.UserService/CheckGroupAdd"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -31,8 +38,15 @@ const (
 type UserServiceClient interface {
 	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*GetUserResponse, error)
 	GetUsers(ctx context.Context, in *GetUsersRequest, opts ...grpc.CallOption) (*GetUsersResponse, error)
+	GetUsersByPhones(ctx context.Context, in *GetUsersByPhonesRequest, opts ...grpc.CallOption) (*GetUsersResponse, error)
 	CheckPresence(ctx context.Context, in *CheckPresenceRequest, opts ...grpc.CallOption) (*CheckPresenceResponse, error)
 	GetPrivacySettings(ctx context.Context, in *GetPrivacySettingsRequest, opts ...grpc.CallOption) (*GetPrivacySettingsResponse, error)
+	ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (*ExportUserDataResponse, error)
+	IsBlocked(ctx context.Context, in *IsBlockedRequest, opts ...grpc.CallOption) (*IsBlockedResponse, error)
+	GetBlockRelations(ctx context.Context, in *GetBlockRelationsRequest, opts ...grpc.CallOption) (*GetBlockRelationsResponse, error)
+	GetPresenceVisibility(ctx context.Context, in *GetPresenceVisibilityRequest, opts ...grpc.CallOption) (*GetPresenceVisibilityResponse, error)
+	GetReadReceiptSettings(ctx context.Context, in *GetReadReceiptSettingsRequest, opts ...grpc.CallOption) (*GetReadReceiptSettingsResponse, error)
+	CheckGroupAdd(ctx context.Context, in *CheckGroupAddRequest, opts ...grpc.CallOption) (*CheckGroupAddResponse, error)
 }
 
 type userServiceClient struct {
@@ -63,6 +77,16 @@ func (c *userServiceClient) GetUsers(ctx context.Context, in *GetUsersRequest, o
 	return out, nil
 }
 
+func (c *userServiceClient) GetUsersByPhones(ctx context.Context, in *GetUsersByPhonesRequest, opts ...grpc.CallOption) (*GetUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUsersResponse)
+	err := c.cc.Invoke(ctx, UserService_GetUsersByPhones_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *userServiceClient) CheckPresence(ctx context.Context, in *CheckPresenceRequest, opts ...grpc.CallOption) (*CheckPresenceResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CheckPresenceResponse)
@@ -83,14 +107,81 @@ func (c *userServiceClient) GetPrivacySettings(ctx context.Context, in *GetPriva
 	return out, nil
 }
 
+func (c *userServiceClient) ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (*ExportUserDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportUserDataResponse)
+	err := c.cc.Invoke(ctx, UserService_ExportUserData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) IsBlocked(ctx context.Context, in *IsBlockedRequest, opts ...grpc.CallOption) (*IsBlockedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IsBlockedResponse)
+	err := c.cc.Invoke(ctx, UserService_IsBlocked_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetBlockRelations(ctx context.Context, in *GetBlockRelationsRequest, opts ...grpc.CallOption) (*GetBlockRelationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBlockRelationsResponse)
+	err := c.cc.Invoke(ctx, UserService_GetBlockRelations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetPresenceVisibility(ctx context.Context, in *GetPresenceVisibilityRequest, opts ...grpc.CallOption) (*GetPresenceVisibilityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPresenceVisibilityResponse)
+	err := c.cc.Invoke(ctx, UserService_GetPresenceVisibility_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetReadReceiptSettings(ctx context.Context, in *GetReadReceiptSettingsRequest, opts ...grpc.CallOption) (*GetReadReceiptSettingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetReadReceiptSettingsResponse)
+	err := c.cc.Invoke(ctx, UserService_GetReadReceiptSettings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CheckGroupAdd(ctx context.Context, in *CheckGroupAddRequest, opts ...grpc.CallOption) (*CheckGroupAddResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckGroupAddResponse)
+	err := c.cc.Invoke(ctx, UserService_CheckGroupAdd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
 type UserServiceServer interface {
 	GetUser(context.Context, *GetUserRequest) (*GetUserResponse, error)
 	GetUsers(context.Context, *GetUsersRequest) (*GetUsersResponse, error)
+	GetUsersByPhones(context.Context, *GetUsersByPhonesRequest) (*GetUsersResponse, error)
 	CheckPresence(context.Context, *CheckPresenceRequest) (*CheckPresenceResponse, error)
 	GetPrivacySettings(context.Context, *GetPrivacySettingsRequest) (*GetPrivacySettingsResponse, error)
+	ExportUserData(context.Context, *ExportUserDataRequest) (*ExportUserDataResponse, error)
+	IsBlocked(context.Context, *IsBlockedRequest) (*IsBlockedResponse, error)
+	GetBlockRelations(context.Context, *GetBlockRelationsRequest) (*GetBlockRelationsResponse, error)
+	GetPresenceVisibility(context.Context, *GetPresenceVisibilityRequest) (*GetPresenceVisibilityResponse, error)
+	GetReadReceiptSettings(context.Context, *GetReadReceiptSettingsRequest) (*GetReadReceiptSettingsResponse, error)
+	CheckGroupAdd(context.Context, *CheckGroupAddRequest) (*CheckGroupAddResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -107,12 +198,33 @@ func (UnimplementedUserServiceServer) GetUser(context.Context, *GetUserRequest)
 func (UnimplementedUserServiceServer) GetUsers(context.Context, *GetUsersRequest) (*GetUsersResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetUsers not implemented")
 }
+func (UnimplementedUserServiceServer) GetUsersByPhones(context.Context, *GetUsersByPhonesRequest) (*GetUsersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUsersByPhones not implemented")
+}
 func (UnimplementedUserServiceServer) CheckPresence(context.Context, *CheckPresenceRequest) (*CheckPresenceResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CheckPresence not implemented")
 }
 func (UnimplementedUserServiceServer) GetPrivacySettings(context.Context, *GetPrivacySettingsRequest) (*GetPrivacySettingsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetPrivacySettings not implemented")
 }
+func (UnimplementedUserServiceServer) ExportUserData(context.Context, *ExportUserDataRequest) (*ExportUserDataResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportUserData not implemented")
+}
+func (UnimplementedUserServiceServer) IsBlocked(context.Context, *IsBlockedRequest) (*IsBlockedResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method IsBlocked not implemented")
+}
+func (UnimplementedUserServiceServer) GetBlockRelations(context.Context, *GetBlockRelationsRequest) (*GetBlockRelationsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetBlockRelations not implemented")
+}
+func (UnimplementedUserServiceServer) GetPresenceVisibility(context.Context, *GetPresenceVisibilityRequest) (*GetPresenceVisibilityResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPresenceVisibility not implemented")
+}
+func (UnimplementedUserServiceServer) GetReadReceiptSettings(context.Context, *GetReadReceiptSettingsRequest) (*GetReadReceiptSettingsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetReadReceiptSettings not implemented")
+}
+func (UnimplementedUserServiceServer) CheckGroupAdd(context.Context, *CheckGroupAddRequest) (*CheckGroupAddResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CheckGroupAdd not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -170,6 +282,24 @@ func _UserService_GetUsers_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_GetUsersByPhones_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsersByPhonesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUsersByPhones(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetUsersByPhones_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUsersByPhones(ctx, req.(*GetUsersByPhonesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _UserService_CheckPresence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CheckPresenceRequest)
 	if err := dec(in); err != nil {
@@ -206,6 +336,114 @@ func _UserService_GetPrivacySettings_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_ExportUserData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportUserDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ExportUserData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ExportUserData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ExportUserData(ctx, req.(*ExportUserDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_IsBlocked_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IsBlockedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).IsBlocked(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_IsBlocked_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).IsBlocked(ctx, req.(*IsBlockedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetBlockRelations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockRelationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetBlockRelations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetBlockRelations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetBlockRelations(ctx, req.(*GetBlockRelationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetPresenceVisibility_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPresenceVisibilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetPresenceVisibility(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetPresenceVisibility_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetPresenceVisibility(ctx, req.(*GetPresenceVisibilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetReadReceiptSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReadReceiptSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetReadReceiptSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetReadReceiptSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetReadReceiptSettings(ctx, req.(*GetReadReceiptSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CheckGroupAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckGroupAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CheckGroupAdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CheckGroupAdd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CheckGroupAdd(ctx, req.(*CheckGroupAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -221,6 +459,10 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUsers",
 			Handler:    _UserService_GetUsers_Handler,
 		},
+		{
+			MethodName: "GetUsersByPhones",
+			Handler:    _UserService_GetUsersByPhones_Handler,
+		},
 		{
 			MethodName: "CheckPresence",
 			Handler:    _UserService_CheckPresence_Handler,
@@ -229,6 +471,30 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPrivacySettings",
 			Handler:    _UserService_GetPrivacySettings_Handler,
 		},
+		{
+			MethodName: "ExportUserData",
+			Handler:    _UserService_ExportUserData_Handler,
+		},
+		{
+			MethodName: "IsBlocked",
+			Handler:    _UserService_IsBlocked_Handler,
+		},
+		{
+			MethodName: "GetBlockRelations",
+			Handler:    _UserService_GetBlockRelations_Handler,
+		},
+		{
+			MethodName: "GetPresenceVisibility",
+			Handler:    _UserService_GetPresenceVisibility_Handler,
+		},
+		{
+			MethodName: "GetReadReceiptSettings",
+			Handler:    _UserService_GetReadReceiptSettings_Handler,
+		},
+		{
+			MethodName: "CheckGroupAdd",
+			Handler:    _UserService_CheckGroupAdd_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/user/v1/user.proto",
@@ -1,8 +1,14 @@
 package tests
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,6 +35,82 @@ func TestAuthFlow_RequestOTP_MissingPhone(t *testing.T) {
 	_ = parseResponseRaw(t, resp)
 }
 
+// requestOTP asks for a code and returns the response data.
+func requestOTP(t *testing.T, phone, channel string) map[string]interface{} {
+	t.Helper()
+	resp := doRequest(t, "POST", "/api/v1/auth/request-otp", map[string]string{
+		"phone":   phone,
+		"channel": channel,
+	}, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, "request-otp should return 200")
+	return parseResponse(t, resp)["data"].(map[string]interface{})
+}
+
+// verifyOTP logs in with code, which also clears the number's resend cooldown.
+func verifyOTP(t *testing.T, phone, code string) {
+	t.Helper()
+	resp := doRequest(t, "POST", "/api/v1/auth/verify-otp", map[string]string{
+		"phone": phone,
+		"code":  code,
+	}, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, "verify-otp should return 200")
+	_ = parseResponseRaw(t, resp)
+}
+
+func TestAuthFlow_RequestOTP_DeliveryAndResend(t *testing.T) {
+	phone := "+14155555006"
+
+	data := requestOTP(t, phone, "")
+	assert.Equal(t, "sms", data["channel"])
+	assert.Equal(t, "sent", data["status"])
+	assert.Positive(t, data["resend_after_seconds"])
+	deliveryID := data["delivery_id"].(string)
+	require.NotEmpty(t, deliveryID)
+	code := data["otp"].(string)
+
+	resp := doRequest(t, "GET", "/api/v1/auth/otp-deliveries/"+deliveryID, nil, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	delivery := parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, deliveryID, delivery["id"])
+	assert.Equal(t, "sent", delivery["status"])
+	assert.Equal(t, "log", delivery["provider"])
+	assert.Equal(t, float64(1), delivery["attempts"])
+	assert.NotContains(t, delivery, "phone")
+
+	// Asking again straight away is refused and keeps the first code valid.
+	resp = doRequest(t, "POST", "/api/v1/auth/request-otp", map[string]string{
+		"phone": phone,
+	}, "")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	body := parseResponse(t, resp)
+	assert.Equal(t, "OTP_RESEND_TOO_SOON", body["error"].(map[string]interface{})["code"])
+
+	verifyOTP(t, phone, code)
+
+	// Logging in clears the cooldown.
+	data = requestOTP(t, phone, "")
+	verifyOTP(t, phone, data["otp"].(string))
+
+	resp = doRequest(t, "GET", "/api/v1/auth/otp-deliveries/unknown", nil, "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+func TestAuthFlow_RequestOTP_Channel(t *testing.T) {
+	phone := "+14155555007"
+
+	resp := doRequest(t, "POST", "/api/v1/auth/request-otp", map[string]string{
+		"phone":   phone,
+		"channel": "fax",
+	}, "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	data := requestOTP(t, phone, "voice")
+	assert.Equal(t, "voice", data["channel"])
+	verifyOTP(t, phone, data["otp"].(string))
+}
+
 func TestAuthFlow_VerifyOTP_Success(t *testing.T) {
 	phone := "+14155551002"
 	token, refresh, userID := registerUser(t, phone)
@@ -149,3 +231,439 @@ func TestAuthFlow_ProtectedRoute_ValidToken(t *testing.T) {
 	body := parseResponse(t, resp)
 	assert.True(t, body["success"].(bool))
 }
+
+func TestAuthFlow_JWKS(t *testing.T) {
+	token, _, _ := registerUser(t, "+14155555009")
+
+	resp := doRequest(t, "GET", "/.well-known/jwks.json", nil, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body := parseResponse(t, resp)
+	keys := body["keys"].([]interface{})
+	require.NotEmpty(t, keys)
+
+	kids := map[string]bool{}
+	for _, raw := range keys {
+		key := raw.(map[string]interface{})
+		assert.Contains(t, []interface{}{"RS256", "EdDSA"}, key["alg"])
+		assert.Equal(t, "sig", key["use"])
+		assert.NotContains(t, key, "d", "private key material must not be published")
+		kids[key["kid"].(string)] = true
+	}
+
+	// Access tokens name their signing key, which the JWKS lists.
+	header, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+	require.NoError(t, err)
+	var jose map[string]interface{}
+	require.NoError(t, json.Unmarshal(header, &jose))
+	assert.NotEqual(t, "HS256", jose["alg"])
+	assert.True(t, kids[jose["kid"].(string)], "token kid should be in the JWKS")
+}
+
+// linkDevice links a new secondary device to the account of token via the
+// QR code flow and returns the new device's tokens and ID.
+func linkDevice(t *testing.T, token, name, platform string) (accessToken, refreshToken, deviceID string) {
+	t.Helper()
+
+	resp := doRequest(t, "POST", "/api/v1/auth/devices/link-code", nil, token)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	code := parseResponse(t, resp)["data"].(map[string]interface{})
+	require.NotEmpty(t, code["code"])
+	require.Contains(t, code["qr_payload"], code["code"])
+
+	resp = doRequest(t, "POST", "/api/v1/auth/devices/link", map[string]string{
+		"code":        code["qr_payload"].(string),
+		"device_name": name,
+		"platform":    platform,
+	}, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data := parseResponse(t, resp)["data"].(map[string]interface{})
+	return data["access_token"].(string), data["refresh_token"].(string), data["device_id"].(string)
+}
+
+// listDevices returns the account's devices keyed by ID, plus the ID of the
+// calling device.
+func listDevices(t *testing.T, token string) (devices map[string]map[string]interface{}, current string) {
+	t.Helper()
+	resp := doRequest(t, "GET", "/api/v1/auth/devices", nil, token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	devices = make(map[string]map[string]interface{})
+	for _, d := range parseResponse(t, resp)["data"].([]interface{}) {
+		dev := d.(map[string]interface{})
+		devices[dev["id"].(string)] = dev
+		if dev["current"] == true {
+			current = dev["id"].(string)
+		}
+	}
+	return devices, current
+}
+
+func TestAuthFlow_LinkDevice(t *testing.T) {
+	token, _, userID := registerUser(t, "+14155555001")
+
+	devices, primaryID := listDevices(t, token)
+	require.NotEmpty(t, primaryID, "the calling device should be marked current")
+	assert.Equal(t, true, devices[primaryID]["is_primary"])
+	assert.NotEmpty(t, devices[primaryID]["last_active_at"])
+
+	// Link with the bare code rather than the QR payload.
+	resp := doRequest(t, "POST", "/api/v1/auth/devices/link-code", nil, token)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	code := parseResponse(t, resp)["data"].(map[string]interface{})["code"].(string)
+
+	resp = doRequest(t, "POST", "/api/v1/auth/devices/link", map[string]string{
+		"code": code, "device_name": "Work laptop", "platform": "Desktop",
+	}, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	linked := parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, userID, linked["user"].(map[string]interface{})["id"])
+	assert.Equal(t, false, linked["is_new_user"])
+	linkedToken := linked["access_token"].(string)
+	linkedID := linked["device_id"].(string)
+	defer func() {
+		resp := doRequest(t, "DELETE", "/api/v1/auth/devices/"+linkedID, nil, token)
+		resp.Body.Close()
+	}()
+
+	// Link codes are single use.
+	resp = doRequest(t, "POST", "/api/v1/auth/devices/link", map[string]string{"code": code}, "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	body := parseResponse(t, resp)
+	assert.Equal(t, "LINK_CODE_INVALID", body["error"].(map[string]interface{})["code"])
+
+	// The linked device acts as the same user and sees both devices.
+	resp = doRequest(t, "GET", "/api/v1/users/me", nil, linkedToken)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, userID, parseResponse(t, resp)["data"].(map[string]interface{})["id"])
+
+	devices, current := listDevices(t, linkedToken)
+	assert.Equal(t, linkedID, current)
+	require.Contains(t, devices, primaryID)
+	require.Contains(t, devices, linkedID)
+	dev := devices[linkedID]
+	assert.Equal(t, false, dev["is_primary"])
+	assert.Equal(t, "Work laptop", dev["name"])
+	assert.Equal(t, "desktop", dev["platform"])
+	assert.Equal(t, primaryID, dev["linked_by"])
+
+	// Device management needs a token; linking does not.
+	resp = doRequest(t, "GET", "/api/v1/auth/devices", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	resp = doRequest(t, "POST", "/api/v1/auth/devices/link", map[string]string{"code": "not-a-code"}, "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+func TestAuthFlow_RevokeDevice(t *testing.T) {
+	token, _, _ := registerUser(t, "+14155555002")
+	linkedToken, linkedRefresh, linkedID := linkDevice(t, token, "Tablet", "android")
+
+	primaryConn := connectWS(t, token)
+	defer primaryConn.Close()
+	linkedConn := connectWS(t, linkedToken)
+	defer linkedConn.Close()
+	time.Sleep(300 * time.Millisecond)
+
+	// Another account cannot see or revoke the device.
+	otherToken, _, _ := registerUser(t, "+14155555003")
+	resp := doRequest(t, "DELETE", "/api/v1/auth/devices/"+linkedID, nil, otherToken)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "DELETE", "/api/v1/auth/devices/"+linkedID, nil, token)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp.Body.Close()
+
+	// The revoked device's socket is closed; the other device is told.
+	_ = linkedConn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		_, _, err := linkedConn.ReadMessage()
+		if err != nil {
+			var netErr net.Error
+			timedOut := errors.As(err, &netErr) && netErr.Timeout()
+			assert.False(t, timedOut, "revoked device's websocket should be closed")
+			break
+		}
+	}
+	events := readWSUntil(t, primaryConn, "device.revoked", 5*time.Second)
+	revoked := events[len(events)-1]["data"].(map[string]interface{})
+	assert.Equal(t, linkedID, revoked["device_id"])
+
+	// Its access and refresh tokens no longer work.
+	resp = doRequest(t, "GET", "/api/v1/users/me", nil, linkedToken)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	resp = doRequest(t, "POST", "/api/v1/auth/refresh", map[string]string{
+		"refresh_token": linkedRefresh,
+	}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// The primary device is unaffected; the revoked one is no longer listed.
+	devices, current := listDevices(t, token)
+	assert.NotEmpty(t, current)
+	assert.NotContains(t, devices, linkedID)
+
+	resp = doRequest(t, "DELETE", "/api/v1/auth/devices/"+linkedID, nil, token)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+// listSessions returns the account's sessions keyed by ID.
+func listSessions(t *testing.T, token string) map[string]map[string]interface{} {
+	t.Helper()
+	resp := doRequest(t, "GET", "/api/v1/auth/sessions", nil, token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	sessions := make(map[string]map[string]interface{})
+	for _, s := range parseResponse(t, resp)["data"].([]interface{}) {
+		session := s.(map[string]interface{})
+		sessions[session["id"].(string)] = session
+	}
+	return sessions
+}
+
+func TestAuthFlow_Sessions(t *testing.T) {
+	token, _, _ := registerUser(t, "+14155555004")
+	_, currentID := listDevices(t, token)
+	require.NotEmpty(t, currentID)
+
+	// Start from this login alone, whatever earlier runs left behind.
+	resp := doRequest(t, "POST", "/api/v1/auth/sessions/logout-others", nil, token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	tabletToken, tabletRefresh, tabletID := linkDevice(t, token, "Tablet", "android")
+	laptopToken, _, laptopID := linkDevice(t, token, "Laptop", "desktop")
+
+	// Refreshing keeps the session: same ID, new last-used time.
+	resp = doRequest(t, "POST", "/api/v1/auth/refresh", map[string]string{
+		"refresh_token": tabletRefresh,
+	}, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	tabletToken = parseResponse(t, resp)["data"].(map[string]interface{})["access_token"].(string)
+
+	sessions := listSessions(t, token)
+	require.Contains(t, sessions, currentID)
+	require.Contains(t, sessions, tabletID)
+	require.Contains(t, sessions, laptopID)
+	assert.Equal(t, true, sessions[currentID]["current"])
+	assert.Equal(t, false, sessions[tabletID]["current"])
+	tablet := sessions[tabletID]
+	assert.Equal(t, "Tablet", tablet["device_name"])
+	assert.Equal(t, "android", tablet["platform"])
+	assert.NotEmpty(t, tablet["ip"])
+	assert.Contains(t, tablet["user_agent"], "Go-http-client")
+	assert.NotEmpty(t, tablet["created_at"])
+	assert.NotEmpty(t, tablet["last_used_at"])
+
+	// Revoking one session rejects its access token at the gateway.
+	resp = doRequest(t, "DELETE", "/api/v1/auth/sessions/"+laptopID, nil, token)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp.Body.Close()
+	resp = doRequest(t, "GET", "/api/v1/users/me", nil, laptopToken)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "DELETE", "/api/v1/auth/sessions/"+laptopID, nil, token)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// Logging out all other sessions leaves only the caller's: the tablet,
+	// as the laptop is already gone.
+	resp = doRequest(t, "POST", "/api/v1/auth/sessions/logout-others", nil, token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	revoked := parseResponse(t, resp)["data"].(map[string]interface{})["revoked"].(float64)
+	assert.Equal(t, float64(1), revoked)
+
+	resp = doRequest(t, "GET", "/api/v1/users/me", nil, tabletToken)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	sessions = listSessions(t, token)
+	assert.Len(t, sessions, 1)
+	assert.Contains(t, sessions, currentID)
+
+	resp = doRequest(t, "GET", "/api/v1/users/me", nil, token)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+func TestAuthFlow_RefreshToken_ReuseRevokesSession(t *testing.T) {
+	token, _, _ := registerUser(t, "+14155555005")
+	_, staleRefresh, tabletID := linkDevice(t, token, "Tablet", "android")
+
+	primaryConn := connectWS(t, token)
+	defer primaryConn.Close()
+	time.Sleep(300 * time.Millisecond)
+
+	// The legitimate client rotates its token...
+	resp := doRequest(t, "POST", "/api/v1/auth/refresh", map[string]string{
+		"refresh_token": staleRefresh,
+	}, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data := parseResponse(t, resp)["data"].(map[string]interface{})
+	tabletToken := data["access_token"].(string)
+	tabletRefresh := data["refresh_token"].(string)
+
+	// ...then a stolen copy of the old token is replayed.
+	resp = doRequest(t, "POST", "/api/v1/auth/refresh", map[string]string{
+		"refresh_token": staleRefresh,
+	}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	body := parseResponse(t, resp)
+	assert.Contains(t, body["error"].(map[string]interface{})["message"], "reuse detected")
+
+	// The whole session is gone: the current refresh and access tokens fail.
+	resp = doRequest(t, "POST", "/api/v1/auth/refresh", map[string]string{
+		"refresh_token": tabletRefresh,
+	}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	resp = doRequest(t, "GET", "/api/v1/users/me", nil, tabletToken)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// The user's other device is warned.
+	events := readWSUntil(t, primaryConn, "session.compromised", 5*time.Second)
+	compromised := events[len(events)-1]["data"].(map[string]interface{})
+	assert.Equal(t, tabletID, compromised["session_id"])
+	assert.Equal(t, "Tablet", compromised["device_name"])
+	assert.NotEmpty(t, compromised["detected_at"])
+
+	// Replaying again is a plain failure and the other session still works.
+	resp = doRequest(t, "POST", "/api/v1/auth/refresh", map[string]string{
+		"refresh_token": staleRefresh,
+	}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	sessions := listSessions(t, token)
+	assert.NotContains(t, sessions, tabletID)
+	resp = doRequest(t, "GET", "/api/v1/users/me", nil, token)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+func TestAuthFlow_TwoStepVerification(t *testing.T) {
+	phone := "+14155555008"
+	token, _, _ := registerUser(t, phone)
+
+	resp := doRequest(t, "PUT", "/api/v1/auth/two-step", map[string]string{"pin": "12ab"}, token)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "PUT", "/api/v1/auth/two-step", map[string]string{
+		"pin":            "123456",
+		"recovery_email": "pin.tester@example.com",
+	}, token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	// Turning the PIN off again lets the suite be re-run. The recovery
+	// email reset below normally does it already.
+	defer func() {
+		resp := doRequest(t, "DELETE", "/api/v1/auth/two-step", map[string]string{"pin": "123456"}, token)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		_ = parseResponseRaw(t, resp)
+	}()
+
+	// Changing or turning off the PIN needs the current one.
+	resp = doRequest(t, "PUT", "/api/v1/auth/two-step", map[string]string{"pin": "111111"}, token)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	resp = doRequest(t, "DELETE", "/api/v1/auth/two-step", map[string]string{"pin": "999999"}, token)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	body := parseResponse(t, resp)
+	assert.Equal(t, "PIN_INVALID", body["error"].(map[string]interface{})["code"])
+	resp = doRequest(t, "PUT", "/api/v1/auth/two-step", map[string]string{
+		"pin":            "123456",
+		"current_pin":    "123456",
+		"recovery_email": "pin.tester@example.com",
+	}, token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "GET", "/api/v1/auth/two-step", nil, token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	status := parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, true, status["enabled"])
+	assert.Equal(t, "pin.tester@example.com", status["recovery_email"])
+
+	// A correct OTP now only leads to the PIN prompt.
+	code := requestOTP(t, phone, "")["otp"].(string)
+	resp = doRequest(t, "POST", "/api/v1/auth/verify-otp", map[string]string{
+		"phone": phone,
+		"code":  code,
+	}, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data := parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, true, data["pin_required"])
+	assert.NotContains(t, data, "access_token")
+	assert.Equal(t, "p***@example.com", data["recovery_email_hint"])
+	pinToken := data["pin_token"].(string)
+	require.NotEmpty(t, pinToken)
+
+	resp = doRequest(t, "POST", "/api/v1/auth/verify-pin", map[string]string{
+		"pin_token": pinToken,
+		"pin":       "654321",
+	}, "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	body = parseResponse(t, resp)
+	assert.Equal(t, "PIN_INVALID", body["error"].(map[string]interface{})["code"])
+
+	resp = doRequest(t, "POST", "/api/v1/auth/verify-pin", map[string]string{
+		"pin_token": pinToken,
+		"pin":       "123456",
+	}, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data = parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.NotEmpty(t, data["access_token"])
+	assert.NotEmpty(t, data["refresh_token"])
+
+	// The PIN token is single-use.
+	resp = doRequest(t, "POST", "/api/v1/auth/verify-pin", map[string]string{
+		"pin_token": pinToken,
+		"pin":       "123456",
+	}, "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	body = parseResponse(t, resp)
+	assert.Equal(t, "PIN_TOKEN_INVALID", body["error"].(map[string]interface{})["code"])
+
+	// A forgotten PIN is reset with a code sent to the recovery email,
+	// which turns two-step verification off and finishes the login.
+	code = requestOTP(t, phone, "")["otp"].(string)
+	resp = doRequest(t, "POST", "/api/v1/auth/verify-otp", map[string]string{
+		"phone": phone,
+		"code":  code,
+	}, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	pinToken = parseResponse(t, resp)["data"].(map[string]interface{})["pin_token"].(string)
+
+	resp = doRequest(t, "POST", "/api/v1/auth/pin-reset/request", map[string]string{"pin_token": pinToken}, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data = parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, "p***@example.com", data["recovery_email_hint"])
+	resetCode := data["code"].(string)
+
+	resp = doRequest(t, "POST", "/api/v1/auth/pin-reset/confirm", map[string]string{
+		"pin_token": pinToken,
+		"code":      "000000x",
+	}, "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "POST", "/api/v1/auth/pin-reset/confirm", map[string]string{
+		"pin_token": pinToken,
+		"code":      resetCode,
+	}, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data = parseResponse(t, resp)["data"].(map[string]interface{})
+	require.NotEmpty(t, data["access_token"])
+	token = data["access_token"].(string)
+
+	resp = doRequest(t, "GET", "/api/v1/auth/two-step", nil, token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	status = parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, false, status["enabled"])
+}
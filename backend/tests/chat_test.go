@@ -2,8 +2,11 @@ package tests
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -236,3 +239,441 @@ func TestChat_MuteAndPin(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
 	_ = parseResponseRaw(t, resp)
 }
+
+func TestChat_DisappearingMessages(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155553025")
+	tokenB, _, userB := registerUser(t, "+14155553026")
+
+	chatID := createDirectChat(t, tokenA, userB)
+
+	// A message sent before the timer is enabled never expires.
+	before := sendMessage(t, tokenA, chatID, "Kept", uniqueID("disappear"))
+
+	resp := doRequest(t, "PUT", fmt.Sprintf("/api/v1/chats/%s/disappearing", chatID), map[string]interface{}{
+		"timer": "24h",
+	}, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// The timer is chat-wide: the other member sees it too.
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/chats/%s", chatID), nil, tokenB)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	chat := parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, float64(24*60*60), chat["auto_delete_seconds"])
+
+	sentAt := time.Now()
+	after := sendMessage(t, tokenB, chatID, "Gone tomorrow", uniqueID("disappear"))
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages?chat_id=%s", chatID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	messages := extractMessageList(t, parseResponse(t, resp)["data"])
+
+	expiry := make(map[string]interface{})
+	for _, m := range messages {
+		msg := m.(map[string]interface{})
+		expiry[msg["message_id"].(string)] = msg["expires_at"]
+	}
+	assert.Nil(t, expiry[before])
+	require.NotNil(t, expiry[after])
+	expiresAt, err := time.Parse(time.RFC3339, expiry[after].(string))
+	require.NoError(t, err)
+	assert.WithinDuration(t, sentAt.Add(24*time.Hour), expiresAt, time.Minute)
+
+	// A message past its expiry is removed by the next sweep, and members
+	// are told. (The 10s timer is only accepted in dev mode.)
+	conn := connectWS(t, tokenA)
+	defer conn.Close()
+	resp = doRequest(t, "PUT", fmt.Sprintf("/api/v1/chats/%s/disappearing", chatID), map[string]interface{}{
+		"timer": "10s",
+	}, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	brief := sendMessage(t, tokenB, chatID, "Gone in ten", uniqueID("disappear"))
+
+	// Up to the 10s timer plus one MESSAGE_EXPIRY_SWEEP_INTERVAL (30s).
+	events := readWSUntil(t, conn, "message.expired", 50*time.Second)
+	expired := events[len(events)-1]["data"].(map[string]interface{})
+	assert.Equal(t, chatID, expired["chat_id"])
+	assert.Contains(t, expired["message_ids"], brief)
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages?chat_id=%s", chatID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	remaining := make(map[string]bool)
+	for _, m := range extractMessageList(t, parseResponse(t, resp)["data"]) {
+		msg := m.(map[string]interface{})
+		remaining[msg["message_id"].(string)] = msg["is_deleted"] != true
+	}
+	assert.False(t, remaining[brief])
+	assert.True(t, remaining[before])
+	assert.True(t, remaining[after])
+
+	// Turning the timer off stops stamping new messages.
+	resp = doRequest(t, "PUT", fmt.Sprintf("/api/v1/chats/%s/disappearing", chatID), map[string]interface{}{
+		"timer": "off",
+	}, tokenB)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/chats/%s", chatID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	chat = parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, float64(0), chat["auto_delete_seconds"])
+}
+
+func TestChat_InviteLink(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155553027")
+	_, _, userB := registerUser(t, "+14155553028")
+	tokenC, _, _ := registerUser(t, "+14155553029")
+
+	resp := doRequest(t, "POST", "/api/v1/chats", map[string]interface{}{
+		"name":       "Invite Group",
+		"member_ids": []string{userB},
+	}, tokenA)
+	require.Contains(t, []int{http.StatusOK, http.StatusCreated}, resp.StatusCode)
+	chatID, _ := extractChatInfo(parseResponse(t, resp)["data"].(map[string]interface{}))
+
+	// Only admins can create a link.
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/chats/%s/invite-link", chatID), nil, tokenC)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/chats/%s/invite-link", chatID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	code := parseResponse(t, resp)["data"].(map[string]interface{})["code"].(string)
+	require.NotEmpty(t, code)
+
+	resp = doRequest(t, "GET", "/api/v1/chats/invite/"+code, nil, tokenC)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	preview := parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, chatID, preview["chat_id"])
+	assert.Equal(t, "Invite Group", preview["name"])
+	assert.Equal(t, float64(2), preview["member_count"])
+
+	resp = doRequest(t, "POST", "/api/v1/chats/invite/"+code+"/join", nil, tokenC)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	result := parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, true, result["joined"])
+
+	resp = doRequest(t, "POST", "/api/v1/chats/invite/"+code+"/join", nil, tokenC)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// Rotating invalidates the old code.
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/chats/%s/invite-link", chatID), map[string]interface{}{
+		"action": "rotate",
+	}, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	rotated := parseResponse(t, resp)["data"].(map[string]interface{})["code"].(string)
+	assert.NotEqual(t, code, rotated)
+
+	resp = doRequest(t, "GET", "/api/v1/chats/invite/"+code, nil, tokenC)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// Revoking removes the link entirely.
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/chats/%s/invite-link", chatID), map[string]interface{}{
+		"action": "revoke",
+	}, tokenA)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "GET", "/api/v1/chats/invite/"+rotated, nil, tokenC)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+func TestChat_InviteLink_Approval(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155553030")
+	_, _, userB := registerUser(t, "+14155553031")
+	tokenC, _, userC := registerUser(t, "+14155553032")
+	tokenD, _, userD := registerUser(t, "+14155553033")
+
+	resp := doRequest(t, "POST", "/api/v1/chats", map[string]interface{}{
+		"name":       "Approval Group",
+		"member_ids": []string{userB},
+	}, tokenA)
+	require.Contains(t, []int{http.StatusOK, http.StatusCreated}, resp.StatusCode)
+	chatID, _ := extractChatInfo(parseResponse(t, resp)["data"].(map[string]interface{}))
+
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/chats/%s/invite-link", chatID), map[string]interface{}{
+		"requires_approval": true,
+	}, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	link := parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, true, link["requires_approval"])
+	code := link["code"].(string)
+
+	for _, token := range []string{tokenC, tokenD} {
+		resp = doRequest(t, "POST", "/api/v1/chats/invite/"+code+"/join", nil, token)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		result := parseResponse(t, resp)["data"].(map[string]interface{})
+		assert.Equal(t, false, result["joined"])
+		assert.Equal(t, "pending", result["request"].(map[string]interface{})["status"])
+	}
+
+	// A pending requester is not yet a member.
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/chats/%s", chatID), nil, tokenC)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/chats/%s/join-requests", chatID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	requests := parseResponse(t, resp)["data"].([]interface{})
+	assert.Len(t, requests, 2)
+
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/chats/%s/join-requests/%s/approve", chatID, userC), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/chats/%s/join-requests/%s/reject", chatID, userD), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/chats/%s", chatID), nil, tokenC)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/chats/%s", chatID), nil, tokenD)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// Nothing is left to decide.
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/chats/%s/join-requests/%s/approve", chatID, userD), nil, tokenA)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+func TestChat_Export(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155555014")
+	tokenB, _, userB := registerUser(t, "+14155555015")
+	tokenC, _, _ := registerUser(t, "+14155555016")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	sendMessage(t, tokenA, chatID, "first line\nsecond line", uniqueID("chatexport"))
+	hidden := sendMessage(t, tokenB, chatID, "deleted for A", uniqueID("chatexport"))
+	resp := doRequest(t, "DELETE", "/api/v1/messages/"+hidden+"?for=me", nil, tokenA)
+	require.Contains(t, []int{http.StatusOK, http.StatusNoContent}, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// Only members can export a chat.
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/chats/%s/export", chatID), nil, tokenC)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/chats/%s/export", chatID), map[string]interface{}{"format": "pdf"}, tokenA)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	conn := connectWS(t, tokenA)
+	defer conn.Close()
+
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/chats/%s/export", chatID), nil, tokenA)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	export := parseResponse(t, resp)["data"].(map[string]interface{})
+	exportID := export["export_id"].(string)
+	assert.Equal(t, "chat", export["kind"])
+	assert.Equal(t, chatID, export["chat_id"])
+	assert.Equal(t, "txt", export["format"])
+
+	events := readWSUntil(t, conn, "export.ready", 60*time.Second)
+	ready := events[len(events)-1]["data"].(map[string]interface{})
+	assert.Equal(t, exportID, ready["export_id"])
+
+	resp = doRequest(t, "GET", "/api/v1/media/exports/"+exportID, nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	export = parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, "ready", export["status"])
+	downloadURL := export["download_url"].(string)
+	require.NotEmpty(t, downloadURL)
+
+	// The presigned link is signed for MEDIA_MINIO_PUBLIC_URL, MinIO's
+	// published port in docker-compose.
+	dl, err := http.Get(downloadURL)
+	require.NoError(t, err)
+	defer dl.Body.Close()
+	require.Equal(t, http.StatusOK, dl.StatusCode)
+	body, err := io.ReadAll(dl.Body)
+	require.NoError(t, err)
+
+	transcript := string(body)
+	lines := strings.Split(strings.TrimSuffix(transcript, "\n"), "\n")
+	require.Len(t, lines, 2, "multi-line text continues on the next line")
+	assert.Regexp(t, `^\d{2}/\d{2}/\d{4}, \d{2}:\d{2} - .+: first line$`, lines[0])
+	assert.Equal(t, "second line", lines[1])
+	assert.NotContains(t, transcript, "deleted for A")
+}
+
+func chatMemberIDs(t *testing.T, token, chatID string) []string {
+	t.Helper()
+	resp := doRequest(t, "GET", fmt.Sprintf("/api/v1/chats/%s", chatID), nil, token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	chat := parseResponse(t, resp)["data"].(map[string]interface{})
+	var ids []string
+	for _, p := range chat["participants"].([]interface{}) {
+		ids = append(ids, p.(map[string]interface{})["user_id"].(string))
+	}
+	return ids
+}
+
+func TestChat_GroupAddPrivacy(t *testing.T) {
+	tokenA, _, userA := registerUser(t, "+14155553034")
+	tokenB, _, userB := registerUser(t, "+14155553035")
+	tokenC, _, userC := registerUser(t, "+14155553036")
+	tokenD, _, userD := registerUser(t, "+14155553037")
+
+	// B lets nobody add them; C and D only their contacts, and only C has A saved.
+	updatePrivacy(t, tokenB, map[string]interface{}{"group_add": "nobody"})
+	updatePrivacy(t, tokenC, map[string]interface{}{"group_add": "contacts"})
+	updatePrivacy(t, tokenD, map[string]interface{}{"group_add": "contacts"})
+	resp := doRequest(t, "POST", "/api/v1/users/contacts/sync", map[string]interface{}{
+		"phones": []string{"+14155553034"},
+	}, tokenC)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "POST", "/api/v1/chats", map[string]interface{}{
+		"name":       "Privacy Group",
+		"member_ids": []string{userB, userC},
+	}, tokenA)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	data := parseResponse(t, resp)["data"].(map[string]interface{})
+	chatID, _ := extractChatInfo(data)
+	invitations := data["invitations"].([]interface{})
+	require.Len(t, invitations, 1)
+	inv := invitations[0].(map[string]interface{})
+	assert.Equal(t, userB, inv["user_id"])
+	assert.Equal(t, "pending", inv["status"])
+	assert.Equal(t, "Privacy Group", inv["group_name"])
+	invitationID := inv["id"].(string)
+
+	members := chatMemberIDs(t, tokenA, chatID)
+	assert.ElementsMatch(t, []string{userA, userC}, members)
+
+	// The invitation arrives from A in their direct chat.
+	directID := createDirectChat(t, tokenB, userA)
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages?chat_id=%s", directID), nil, tokenB)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var invite map[string]interface{}
+	for _, m := range extractMessageList(t, parseResponse(t, resp)["data"]) {
+		msg := m.(map[string]interface{})
+		if msg["type"] == "group_invite" {
+			assert.Equal(t, userA, msg["sender_id"])
+			invite = msg["payload"].(map[string]interface{})["group_invite"].(map[string]interface{})
+		}
+	}
+	require.NotNil(t, invite, "group_invite message not found")
+	assert.Equal(t, invitationID, invite["invitation_id"])
+	assert.Equal(t, chatID, invite["group_chat_id"])
+
+	// Clients cannot send invitations of their own.
+	resp = doRequest(t, "POST", "/api/v1/messages", map[string]interface{}{
+		"chat_id":       directID,
+		"type":          "group_invite",
+		"payload":       map[string]interface{}{"group_invite": invite},
+		"client_msg_id": uniqueID("invite"),
+	}, tokenB)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// D does not have A as a contact, so adding them sends an invitation.
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/chats/%s/participants", chatID), map[string]interface{}{
+		"user_id": userD,
+	}, tokenA)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	invD := parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, userD, invD["user_id"])
+
+	// Adding B again renews the pending invitation.
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/chats/%s/participants", chatID), map[string]interface{}{
+		"user_id": userB,
+	}, tokenA)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, invitationID, parseResponse(t, resp)["data"].(map[string]interface{})["id"])
+
+	resp = doRequest(t, "GET", "/api/v1/chats/invitations", nil, tokenB)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	pending := parseResponse(t, resp)["data"].([]interface{})
+	require.Len(t, pending, 1)
+	assert.Equal(t, invitationID, pending[0].(map[string]interface{})["id"])
+
+	// Only the invitee can answer.
+	resp = doRequest(t, "POST", "/api/v1/chats/invitations/"+invitationID+"/accept", nil, tokenC)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "POST", "/api/v1/chats/invitations/"+invitationID+"/accept", nil, tokenB)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "accepted", parseResponse(t, resp)["data"].(map[string]interface{})["status"])
+	assert.Contains(t, chatMemberIDs(t, tokenA, chatID), userB)
+
+	resp = doRequest(t, "POST", "/api/v1/chats/invitations/"+invitationID+"/accept", nil, tokenB)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "POST", "/api/v1/chats/invitations/"+invD["id"].(string)+"/decline", nil, tokenD)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "declined", parseResponse(t, resp)["data"].(map[string]interface{})["status"])
+	assert.NotContains(t, chatMemberIDs(t, tokenA, chatID), userD)
+
+	resp = doRequest(t, "POST", "/api/v1/chats/invitations/"+invD["id"].(string)+"/accept", nil, tokenD)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// E and F let their contacts add them except those listed; both have A
+	// saved, but E lists A.
+	tokenE, _, userE := registerUser(t, "+14155553038")
+	tokenF, _, userF := registerUser(t, "+14155553039")
+	for _, token := range []string{tokenE, tokenF} {
+		resp = doRequest(t, "POST", "/api/v1/users/contacts/sync", map[string]interface{}{
+			"phones": []string{"+14155553034"},
+		}, token)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		_ = parseResponseRaw(t, resp)
+	}
+	updatePrivacy(t, tokenE, map[string]interface{}{"group_add": "contacts_except", "group_add_except": []string{userA}})
+	updatePrivacy(t, tokenF, map[string]interface{}{"group_add": "contacts_except", "group_add_except": []string{userB}})
+
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/chats/%s/participants", chatID), map[string]interface{}{
+		"user_id": userE,
+	}, tokenA)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	invE := parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, userE, invE["user_id"])
+
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/chats/%s/participants", chatID), map[string]interface{}{
+		"user_id": userF,
+	}, tokenA)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	assert.Contains(t, chatMemberIDs(t, tokenA, chatID), userF)
+
+	// Blocks and the inviter's role are checked again on accepting: E cannot
+	// join while they have A blocked...
+	invEID := invE["id"].(string)
+	resp = doRequest(t, "POST", "/api/v1/users/contacts/"+userA+"/block", nil, tokenE)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	resp = doRequest(t, "POST", "/api/v1/chats/invitations/"+invEID+"/accept", nil, tokenE)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	body := parseResponse(t, resp)
+	assert.Equal(t, "USER_BLOCKED", body["error"].(map[string]interface{})["code"])
+	resp = doRequest(t, "DELETE", "/api/v1/users/contacts/"+userA+"/block", nil, tokenE)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// ...nor once A is no longer an admin.
+	resp = doRequest(t, "PATCH", fmt.Sprintf("/api/v1/chats/%s/participants/%s/role", chatID, userB), map[string]interface{}{
+		"role": "admin",
+	}, tokenA)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	resp = doRequest(t, "PATCH", fmt.Sprintf("/api/v1/chats/%s/participants/%s/role", chatID, userA), map[string]interface{}{
+		"role": "member",
+	}, tokenB)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	resp = doRequest(t, "POST", "/api/v1/chats/invitations/"+invEID+"/accept", nil, tokenE)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	assert.NotContains(t, chatMemberIDs(t, tokenA, chatID), userE)
+}
@@ -0,0 +1,315 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whatsapp-clone/backend/pkg/e2ee"
+)
+
+// testDevice holds the private keys of a client device for the e2ee tests.
+type testDevice struct {
+	id       string
+	identity *e2ee.Identity
+	spk      *e2ee.SignedPreKey
+	preKeys  map[uint32]*e2ee.PreKey
+}
+
+func newTestDevice(t *testing.T, id string) *testDevice {
+	t.Helper()
+	identity, err := e2ee.GenerateIdentity()
+	require.NoError(t, err)
+	spk, err := e2ee.GenerateSignedPreKey(identity, 1)
+	require.NoError(t, err)
+	return &testDevice{id: id, identity: identity, spk: spk, preKeys: make(map[uint32]*e2ee.PreKey)}
+}
+
+// newPreKeys generates one-time prekeys and returns them in request form.
+func (d *testDevice) newPreKeys(t *testing.T, startID uint32, n int) []map[string]interface{} {
+	t.Helper()
+	keys, err := e2ee.GeneratePreKeys(startID, n)
+	require.NoError(t, err)
+	out := make([]map[string]interface{}, 0, n)
+	for _, k := range keys {
+		d.preKeys[k.ID] = k
+		out = append(out, map[string]interface{}{"key_id": k.ID, "public_key": k.PublicKey()})
+	}
+	return out
+}
+
+func uploadKeys(t *testing.T, token string, d *testDevice, preKeys int) map[string]interface{} {
+	t.Helper()
+	resp := doRequest(t, "PUT", "/api/v1/users/keys", map[string]interface{}{
+		"device_id":    d.id,
+		"identity_key": d.identity.PublicKey(),
+		"signing_key":  d.identity.SigningKey(),
+		"signed_prekey": map[string]interface{}{
+			"key_id":     d.spk.ID,
+			"public_key": d.spk.PublicKey(),
+			"signature":  d.spk.Signature,
+		},
+		"one_time_prekeys": d.newPreKeys(t, 1, preKeys),
+	}, token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	return parseResponse(t, resp)["data"].(map[string]interface{})
+}
+
+func fetchBundles(t *testing.T, token, userID string) []interface{} {
+	t.Helper()
+	resp := doRequest(t, "GET", fmt.Sprintf("/api/v1/users/%s/keys", userID), nil, token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	return parseResponse(t, resp)["data"].([]interface{})
+}
+
+func decodeB64(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	b, err := base64.StdEncoding.DecodeString(v.(string))
+	require.NoError(t, err)
+	return b
+}
+
+// toBundle converts a bundle from the key directory into its e2ee form.
+func toBundle(t *testing.T, raw interface{}) *e2ee.Bundle {
+	t.Helper()
+	m := raw.(map[string]interface{})
+	spk := m["signed_prekey"].(map[string]interface{})
+	b := &e2ee.Bundle{
+		IdentityKey:           decodeB64(t, m["identity_key"]),
+		SigningKey:            decodeB64(t, m["signing_key"]),
+		SignedPreKeyID:        uint32(spk["key_id"].(float64)),
+		SignedPreKey:          decodeB64(t, spk["public_key"]),
+		SignedPreKeySignature: decodeB64(t, spk["signature"]),
+	}
+	if opk, ok := m["one_time_prekey"].(map[string]interface{}); ok {
+		b.OneTimePreKeyID = uint32(opk["key_id"].(float64))
+		b.OneTimePreKey = decodeB64(t, opk["public_key"])
+	}
+	return b
+}
+
+// sendEncrypted sends msg to one device of recipientID.
+func sendEncrypted(t *testing.T, token, chatID, senderDevice, recipientID, recipientDevice string, msg *e2ee.Message) *http.Response {
+	t.Helper()
+	ct, err := msg.Marshal()
+	require.NoError(t, err)
+	return doRequest(t, "POST", "/api/v1/messages", map[string]interface{}{
+		"chat_id": chatID,
+		"type":    "encrypted",
+		"payload": map[string]interface{}{
+			"encrypted": map[string]interface{}{
+				"sender_device_id": senderDevice,
+				"envelopes": []map[string]interface{}{{
+					"user_id":    recipientID,
+					"device_id":  recipientDevice,
+					"type":       msg.Type(),
+					"ciphertext": ct,
+				}},
+			},
+		},
+		"client_msg_id": uniqueID("e2ee"),
+	}, token)
+}
+
+// receiveEncrypted fetches a message and returns the caller's envelopes.
+func receiveEncrypted(t *testing.T, token, chatID, messageID, plaintext string) []interface{} {
+	t.Helper()
+	resp := doRequest(t, "GET", fmt.Sprintf("/api/v1/messages?chat_id=%s", chatID), nil, token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	raw := parseResponseRaw(t, resp)
+	assert.NotContains(t, string(raw), plaintext, "server must never return plaintext")
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages?chat_id=%s", chatID), nil, token)
+	for _, m := range extractMessageList(t, parseResponse(t, resp)["data"]) {
+		msg := m.(map[string]interface{})
+		if msg["message_id"] == messageID {
+			enc := msg["payload"].(map[string]interface{})["encrypted"].(map[string]interface{})
+			return enc["envelopes"].([]interface{})
+		}
+	}
+	t.Fatalf("message %s not found", messageID)
+	return nil
+}
+
+func TestE2EE_KeyDirectory(t *testing.T) {
+	tokenA, _, userA := registerUser(t, "+14155557001")
+	tokenB, _, _ := registerUser(t, "+14155557002")
+
+	// No keys published yet.
+	resp := doRequest(t, "GET", fmt.Sprintf("/api/v1/users/%s/keys", userA), nil, tokenB)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	device := newTestDevice(t, "phone")
+	count := uploadKeys(t, tokenA, device, 2)
+	assert.Equal(t, float64(2), count["remaining"])
+
+	// Each fetch consumes one one-time prekey, lowest ID first.
+	bundles := fetchBundles(t, tokenB, userA)
+	require.Len(t, bundles, 1)
+	b := toBundle(t, bundles[0])
+	assert.Equal(t, uint32(1), b.OneTimePreKeyID)
+	assert.Equal(t, device.identity.PublicKey(), b.IdentityKey)
+	require.NoError(t, e2ee.VerifySignedPreKey(b.SigningKey, b.SignedPreKey, b.SignedPreKeySignature))
+
+	assert.Equal(t, uint32(2), toBundle(t, fetchBundles(t, tokenB, userA)[0]).OneTimePreKeyID)
+	assert.Zero(t, toBundle(t, fetchBundles(t, tokenB, userA)[0]).OneTimePreKeyID, "bundle without one-time prekey once exhausted")
+
+	resp = doRequest(t, "GET", "/api/v1/users/keys/count?device_id=phone", nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, float64(0), parseResponse(t, resp)["data"].(map[string]interface{})["remaining"])
+
+	// Replenish and rotate the signed prekey.
+	newSPK, err := e2ee.GenerateSignedPreKey(device.identity, 2)
+	require.NoError(t, err)
+	resp = doRequest(t, "POST", "/api/v1/users/keys/prekeys", map[string]interface{}{
+		"device_id": "phone",
+		"signed_prekey": map[string]interface{}{
+			"key_id": newSPK.ID, "public_key": newSPK.PublicKey(), "signature": newSPK.Signature,
+		},
+		"one_time_prekeys": device.newPreKeys(t, 3, 3),
+	}, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, float64(3), parseResponse(t, resp)["data"].(map[string]interface{})["remaining"])
+	assert.Equal(t, uint32(2), toBundle(t, fetchBundles(t, tokenB, userA)[0]).SignedPreKeyID)
+
+	// A signed prekey that the signing key did not sign is rejected.
+	other, err := e2ee.GenerateIdentity()
+	require.NoError(t, err)
+	forged, err := e2ee.GenerateSignedPreKey(other, 3)
+	require.NoError(t, err)
+	resp = doRequest(t, "POST", "/api/v1/users/keys/prekeys", map[string]interface{}{
+		"device_id": "phone",
+		"signed_prekey": map[string]interface{}{
+			"key_id": forged.ID, "public_key": forged.PublicKey(), "signature": forged.Signature,
+		},
+	}, tokenA)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// A new identity for the device drops prekeys left from the old one.
+	count = uploadKeys(t, tokenA, newTestDevice(t, "phone"), 1)
+	assert.Equal(t, float64(1), count["remaining"])
+
+	resp = doRequest(t, "GET", "/api/v1/users/not-a-uuid/keys", nil, tokenB)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+func TestE2EE_RevokedDeviceKeysDropped(t *testing.T) {
+	tokenA, _, userA := registerUser(t, "+14155557007")
+	tokenB, _, _ := registerUser(t, "+14155557008")
+
+	uploadKeys(t, tokenA, newTestDevice(t, "phone"), 1)
+	laptopToken, _, laptopID := linkDevice(t, tokenA, "Laptop", "web")
+	uploadKeys(t, laptopToken, newTestDevice(t, "laptop"), 1)
+	require.Len(t, fetchBundles(t, tokenB, userA), 2)
+
+	resp := doRequest(t, "DELETE", "/api/v1/auth/devices/"+laptopID, nil, tokenA)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp.Body.Close()
+
+	// user-service drops the keys once it hears of the revocation.
+	require.Eventually(t, func() bool {
+		bundles := fetchBundles(t, tokenB, userA)
+		return len(bundles) == 1 && bundles[0].(map[string]interface{})["device_id"] == "phone"
+	}, 10*time.Second, 500*time.Millisecond, "keys of the revoked device should be dropped")
+}
+
+func TestE2EE_EncryptedMessage(t *testing.T) {
+	tokenA, _, userA := registerUser(t, "+14155557003")
+	tokenB, _, userB := registerUser(t, "+14155557004")
+	_, _, userC := registerUser(t, "+14155557005")
+
+	chatID := createDirectChat(t, tokenA, userB)
+
+	alice := newTestDevice(t, "alice-phone")
+	bob := newTestDevice(t, "bob-phone")
+	uploadKeys(t, tokenA, alice, 5)
+	uploadKeys(t, tokenB, bob, 5)
+
+	// Alice opens a session from Bob's bundle.
+	bundles := fetchBundles(t, tokenA, userB)
+	require.Len(t, bundles, 1)
+	aliceSession, err := e2ee.InitiateSession(alice.identity, toBundle(t, bundles[0]))
+	require.NoError(t, err)
+
+	const secret = "meet at the usual place at 7"
+	msg, err := aliceSession.Encrypt([]byte(secret))
+	require.NoError(t, err)
+	require.Equal(t, e2ee.EnvelopePreKey, msg.Type())
+
+	resp := sendEncrypted(t, tokenA, chatID, alice.id, userB, bob.id, msg)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	raw := parseResponseRaw(t, resp)
+	assert.NotContains(t, string(raw), secret)
+	var sent map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &sent))
+	first := sent["data"].(map[string]interface{})["message_id"].(string)
+
+	// Bob sets up his side from the prekey message and decrypts it.
+	envelopes := receiveEncrypted(t, tokenB, chatID, first, secret)
+	require.Len(t, envelopes, 1)
+	env := envelopes[0].(map[string]interface{})
+	assert.Equal(t, bob.id, env["device_id"])
+	received, err := e2ee.ParseMessage(decodeB64(t, env["ciphertext"]))
+	require.NoError(t, err)
+	bobSession, err := e2ee.AcceptSession(bob.identity, bob.spk, bob.preKeys[received.PreKey.OneTimePreKeyID], received)
+	require.NoError(t, err)
+	plaintext, err := bobSession.Decrypt(received)
+	require.NoError(t, err)
+	assert.Equal(t, secret, string(plaintext))
+
+	// Alice is not sent envelopes for Bob's devices.
+	assert.Empty(t, receiveEncrypted(t, tokenA, chatID, first, secret))
+
+	// Bob replies on the ratchet; Alice decrypts.
+	const reply = "see you there"
+	msg, err = bobSession.Encrypt([]byte(reply))
+	require.NoError(t, err)
+	require.Equal(t, e2ee.EnvelopeMessage, msg.Type())
+	resp = sendEncrypted(t, tokenB, chatID, bob.id, userA, alice.id, msg)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	second := parseResponse(t, resp)["data"].(map[string]interface{})["message_id"].(string)
+
+	envelopes = receiveEncrypted(t, tokenA, chatID, second, reply)
+	require.Len(t, envelopes, 1)
+	received, err = e2ee.ParseMessage(decodeB64(t, envelopes[0].(map[string]interface{})["ciphertext"]))
+	require.NoError(t, err)
+	plaintext, err = aliceSession.Decrypt(received)
+	require.NoError(t, err)
+	assert.Equal(t, reply, string(plaintext))
+
+	// Envelopes for users outside the chat and plaintext alongside
+	// ciphertext are rejected.
+	msg, err = aliceSession.Encrypt([]byte("hi"))
+	require.NoError(t, err)
+	resp = sendEncrypted(t, tokenA, chatID, alice.id, userC, "carol-phone", msg)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	ct, err := msg.Marshal()
+	require.NoError(t, err)
+	resp = doRequest(t, "POST", "/api/v1/messages", map[string]interface{}{
+		"chat_id": chatID,
+		"type":    "encrypted",
+		"payload": map[string]interface{}{
+			"body": "hi",
+			"encrypted": map[string]interface{}{
+				"sender_device_id": alice.id,
+				"envelopes": []map[string]interface{}{{
+					"user_id": userB, "device_id": bob.id, "type": msg.Type(), "ciphertext": ct,
+				}},
+			},
+		},
+		"client_msg_id": uniqueID("e2ee"),
+	}, tokenA)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
@@ -2,9 +2,12 @@ module github.com/whatsapp-clone/backend/tests
 
 go 1.24.0
 
+replace github.com/whatsapp-clone/backend/pkg => ../pkg
+
 require (
 	github.com/gorilla/websocket v1.5.3
 	github.com/stretchr/testify v1.10.0
+	github.com/whatsapp-clone/backend/pkg v0.0.0-00010101000000-000000000000
 )
 
 require (
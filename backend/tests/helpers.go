@@ -213,11 +213,21 @@ func sendMessage(t *testing.T, token, chatID, text, clientMsgID string) string {
 // connectWS opens a WebSocket connection via the API gateway.
 func connectWS(t *testing.T, token string) *websocket.Conn {
 	t.Helper()
+	return connectWSWithQuery(t, token, "")
+}
+
+// connectWSWithQuery opens a WebSocket connection with the given raw query
+// string (e.g. "device_id=abc&last_seq=42").
+func connectWSWithQuery(t *testing.T, token, query string) *websocket.Conn {
+	t.Helper()
 
 	wsURL := os.Getenv("WS_URL")
 	if wsURL == "" {
 		wsURL = "ws://localhost:8080/ws"
 	}
+	if query != "" {
+		wsURL += "?" + query
+	}
 
 	header := http.Header{}
 	header.Set("Authorization", "Bearer "+token)
@@ -243,3 +253,21 @@ func readWSEvent(t *testing.T, conn *websocket.Conn, timeout time.Duration) map[
 	require.NoError(t, err, "ws message should be valid JSON")
 	return event
 }
+
+// readWSUntil reads events until one of the given type arrives and returns
+// every event read, including the terminating one.
+func readWSUntil(t *testing.T, conn *websocket.Conn, eventType string, timeout time.Duration) []map[string]interface{} {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	var events []map[string]interface{}
+	for {
+		remaining := time.Until(deadline)
+		require.Positive(t, remaining, "timed out waiting for %s", eventType)
+		event := readWSEvent(t, conn, remaining)
+		events = append(events, event)
+		if event["event"] == eventType {
+			return events
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMedia_AccountExport(t *testing.T) {
+	tokenA, _, userA := registerUser(t, "+14155555012")
+	tokenB, _, userB := registerUser(t, "+14155555013")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	sendMessage(t, tokenA, chatID, "export me <b>please</b>", uniqueID("export"))
+	keptForB := sendMessage(t, tokenB, chatID, "only B keeps this", uniqueID("export"))
+	resp := doRequest(t, "DELETE", "/api/v1/messages/"+keptForB+"?for=me", nil, tokenA)
+	require.Contains(t, []int{http.StatusOK, http.StatusNoContent}, resp.StatusCode)
+
+	conn := connectWS(t, tokenA)
+	defer conn.Close()
+
+	resp = doRequest(t, "POST", "/api/v1/media/exports", nil, tokenA)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	export := parseResponse(t, resp)["data"].(map[string]interface{})
+	exportID := export["export_id"].(string)
+	assert.Equal(t, "account", export["kind"])
+	assert.Contains(t, []interface{}{"pending", "running"}, export["status"])
+	assert.NotContains(t, export, "user_id")
+
+	// A second request while the first is in flight returns the same export.
+	resp = doRequest(t, "POST", "/api/v1/media/exports", nil, tokenA)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	again := parseResponse(t, resp)["data"].(map[string]interface{})
+	if again["status"] != "ready" {
+		assert.Equal(t, exportID, again["export_id"])
+	}
+
+	// Other users cannot see it.
+	resp = doRequest(t, "GET", "/api/v1/media/exports/"+exportID, nil, tokenB)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// The owner is told when it is ready.
+	events := readWSUntil(t, conn, "export.ready", 60*time.Second)
+	ready := events[len(events)-1]["data"].(map[string]interface{})
+	assert.Equal(t, exportID, ready["export_id"])
+	assert.NotEmpty(t, ready["download_url"])
+
+	resp = doRequest(t, "GET", "/api/v1/media/exports/"+exportID, nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	export = parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, "ready", export["status"])
+	assert.Positive(t, export["size_bytes"])
+	assert.NotEmpty(t, export["expires_at"])
+	assert.NotEmpty(t, export["download_url_expires_at"])
+	downloadURL := export["download_url"].(string)
+	require.NotEmpty(t, downloadURL)
+
+	// The presigned link is signed for MEDIA_MINIO_PUBLIC_URL, MinIO's
+	// published port in docker-compose.
+	dl, err := http.Get(downloadURL)
+	require.NoError(t, err)
+	defer dl.Body.Close()
+	require.Equal(t, http.StatusOK, dl.StatusCode)
+	archive, err := io.ReadAll(dl.Body)
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+	}
+
+	for _, name := range []string{"profile.json", "contacts.json", "privacy.json", "statuses.json", "chats.json", "media.json"} {
+		assert.Contains(t, files, name)
+	}
+
+	var profile map[string]interface{}
+	require.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+	assert.Equal(t, userA, profile["id"])
+
+	var chats []map[string]interface{}
+	require.NoError(t, json.Unmarshal(files["chats.json"], &chats))
+	require.Len(t, chats, 1)
+	assert.Equal(t, chatID, chats[0]["chat_id"])
+
+	var messages []map[string]interface{}
+	require.NoError(t, json.Unmarshal(files["chats/"+chatID+"/messages.json"], &messages))
+	require.Len(t, messages, 1, "messages deleted for the user are left out")
+	assert.Equal(t, userA, messages[0]["sender_id"])
+
+	transcript := string(files["chats/"+chatID+"/transcript.html"])
+	assert.True(t, strings.HasPrefix(transcript, "<!DOCTYPE html>"))
+	assert.Contains(t, transcript, "export me &lt;b&gt;please&lt;/b&gt;")
+	assert.NotContains(t, transcript, "only B keeps this")
+}
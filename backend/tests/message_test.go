@@ -3,6 +3,8 @@ package tests
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -228,3 +230,806 @@ func TestMessage_Search(t *testing.T) {
 	results := body["data"].([]interface{})
 	assert.GreaterOrEqual(t, len(results), 1, "search should find at least one matching message")
 }
+
+func TestMessage_SeqAssignedInOrder(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554020")
+	_, _, userB := registerUser(t, "+14155554021")
+
+	chatID := createDirectChat(t, tokenA, userB)
+
+	for i := 0; i < 5; i++ {
+		sendMessage(t, tokenA, chatID, fmt.Sprintf("seq msg %d", i), uniqueID("seq"))
+	}
+
+	// after_seq=0 is "unbounded"; ask for everything after seq 1, oldest first.
+	resp := doRequest(t, "GET", fmt.Sprintf("/api/v1/messages?chat_id=%s&after_seq=1", chatID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body := parseResponse(t, resp)
+	messages := extractMessageList(t, body["data"])
+	require.Len(t, messages, 4)
+
+	for i, m := range messages {
+		msg := m.(map[string]interface{})
+		assert.Equal(t, float64(i+2), msg["seq"], "seq should ascend by one when every send succeeds")
+		payload := msg["payload"].(map[string]interface{})
+		assert.Equal(t, fmt.Sprintf("seq msg %d", i+1), payload["body"])
+	}
+	data := body["data"].(map[string]interface{})
+	assert.Equal(t, float64(5), data["nextSeq"])
+}
+
+func TestMessage_SeqRange(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554022")
+	_, _, userB := registerUser(t, "+14155554023")
+
+	chatID := createDirectChat(t, tokenA, userB)
+
+	for i := 0; i < 6; i++ {
+		sendMessage(t, tokenA, chatID, fmt.Sprintf("range msg %d", i), uniqueID("seq-range"))
+	}
+
+	// Exact range (2, 5) returns seq 3 and 4.
+	resp := doRequest(t, "GET", fmt.Sprintf("/api/v1/messages?chat_id=%s&after_seq=2&before_seq=5", chatID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	messages := extractMessageList(t, parseResponse(t, resp)["data"])
+	require.Len(t, messages, 2)
+	assert.Equal(t, float64(3), messages[0].(map[string]interface{})["seq"])
+	assert.Equal(t, float64(4), messages[1].(map[string]interface{})["seq"])
+
+	// before_seq alone pages backwards, newest first.
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages?chat_id=%s&before_seq=4&limit=2", chatID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	messages = extractMessageList(t, parseResponse(t, resp)["data"])
+	require.Len(t, messages, 2)
+	assert.Equal(t, float64(3), messages[0].(map[string]interface{})["seq"])
+	assert.Equal(t, float64(2), messages[1].(map[string]interface{})["seq"])
+
+	// Invalid ranges are rejected.
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages?chat_id=%s&after_seq=4&before_seq=4", chatID), nil, tokenA)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages?chat_id=%s&after_seq=abc", chatID), nil, tokenA)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+func TestMessage_Edit(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554024")
+	tokenB, _, userB := registerUser(t, "+14155554025")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	msgID := sendMessage(t, tokenA, chatID, "Helo", uniqueID("edit"))
+
+	resp := doRequest(t, "PATCH", fmt.Sprintf("/api/v1/messages/%s", msgID), map[string]interface{}{
+		"body": "Hello",
+	}, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data := parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, true, data["is_edited"])
+	assert.NotEmpty(t, data["edited_at"])
+	assert.Equal(t, "Hello", data["payload"].(map[string]interface{})["body"])
+
+	// The recipient sees the edited body and flag.
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages?chat_id=%s", chatID), nil, tokenB)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	messages := extractMessageList(t, parseResponse(t, resp)["data"])
+	require.NotEmpty(t, messages)
+	msg := messages[0].(map[string]interface{})
+	assert.Equal(t, msgID, msg["message_id"])
+	assert.Equal(t, true, msg["is_edited"])
+	assert.Equal(t, "Hello", msg["payload"].(map[string]interface{})["body"])
+}
+
+func TestMessage_Edit_NotSender(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554026")
+	tokenB, _, userB := registerUser(t, "+14155554027")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	msgID := sendMessage(t, tokenA, chatID, "Mine", uniqueID("edit-forbidden"))
+
+	resp := doRequest(t, "PATCH", fmt.Sprintf("/api/v1/messages/%s", msgID), map[string]interface{}{
+		"body": "Not yours",
+	}, tokenB)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "PATCH", fmt.Sprintf("/api/v1/messages/%s", msgID), map[string]interface{}{
+		"body": "",
+	}, tokenA)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+func TestMessage_SearchRankingAndPaging(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554028")
+	_, _, userB := registerUser(t, "+14155554029")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	for i := 0; i < 3; i++ {
+		sendMessage(t, tokenA, chatID, fmt.Sprintf("pelican sighting number %d", i), uniqueID("rank"))
+	}
+	sendMessage(t, tokenA, chatID, "nothing to see here", uniqueID("rank"))
+
+	resp := doRequest(t, "GET", fmt.Sprintf("/api/v1/messages/search?chat_id=%s&q=pelican&limit=2", chatID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body := parseResponse(t, resp)
+	hits := body["data"].([]interface{})
+	require.Len(t, hits, 2)
+
+	first := hits[0].(map[string]interface{})
+	assert.Greater(t, first["score"].(float64), 0.0)
+	assert.Contains(t, first["snippet"], "pelican")
+	highlights := first["highlights"].([]interface{})
+	require.Len(t, highlights, 1)
+	assert.Equal(t, []interface{}{0.0, 7.0}, highlights[0])
+
+	meta := body["meta"].(map[string]interface{})
+	assert.True(t, meta["has_more"].(bool))
+	cursor := meta["next_cursor"].(string)
+	require.NotEmpty(t, cursor)
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages/search?chat_id=%s&q=pelican&limit=2&cursor=%s", chatID, cursor), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body = parseResponse(t, resp)
+	page2 := body["data"].([]interface{})
+	require.Len(t, page2, 1)
+	assert.NotEqual(t, first["message_id"], page2[0].(map[string]interface{})["message_id"])
+	assert.False(t, body["meta"].(map[string]interface{})["has_more"].(bool))
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages/search?chat_id=%s&q=pelican&cursor=garbage!", chatID), nil, tokenA)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+func TestMessage_SearchFilters(t *testing.T) {
+	tokenA, _, userA := registerUser(t, "+14155554030")
+	tokenB, _, userB := registerUser(t, "+14155554031")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	sendMessage(t, tokenA, chatID, "kumquat from alice", uniqueID("filter"))
+	sendMessage(t, tokenB, chatID, "kumquat from bob", uniqueID("filter"))
+	hidden := sendMessage(t, tokenA, chatID, "kumquat to forget", uniqueID("filter"))
+
+	resp := doRequest(t, "DELETE", fmt.Sprintf("/api/v1/messages/%s?for=me", hidden), nil, tokenA)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// Deleted-for-me messages are excluded for the deleter only.
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages/search?chat_id=%s&q=kumquat", chatID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, parseResponse(t, resp)["data"].([]interface{}), 2)
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages/search?chat_id=%s&q=kumquat", chatID), nil, tokenB)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, parseResponse(t, resp)["data"].([]interface{}), 3)
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages/search?chat_id=%s&q=kumquat&sender_id=%s", chatID, userA), nil, tokenB)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, parseResponse(t, resp)["data"].([]interface{}), 2)
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages/search?chat_id=%s&q=kumquat&has_media=true", chatID), nil, tokenB)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, parseResponse(t, resp)["data"].([]interface{}))
+
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages/search?chat_id=%s&q=kumquat&from=%s", chatID, future), nil, tokenB)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, parseResponse(t, resp)["data"].([]interface{}))
+}
+
+func TestMessage_SearchGlobalMembership(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554032")
+	_, _, userB := registerUser(t, "+14155554033")
+	tokenC, _, userC := registerUser(t, "+14155554034")
+
+	chatAB := createDirectChat(t, tokenA, userB)
+	chatAC := createDirectChat(t, tokenA, userC)
+	sendMessage(t, tokenA, chatAB, "secret quokka plan", uniqueID("global"))
+	sendMessage(t, tokenA, chatAC, "public quokka photo", uniqueID("global"))
+
+	resp := doRequest(t, "GET", "/api/v1/messages/search-global?q=quokka", nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, parseResponse(t, resp)["data"].([]interface{}), 2)
+
+	// C cannot widen the search to a chat they are not in.
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages/search-global?q=quokka&chat_ids=%s,%s", chatAB, chatAC), nil, tokenC)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	hits := parseResponse(t, resp)["data"].([]interface{})
+	require.Len(t, hits, 1)
+	assert.Equal(t, chatAC, hits[0].(map[string]interface{})["chat_id"])
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages/search?chat_id=%s&q=quokka", chatAB), nil, tokenC)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+// sendReply sends a text reply to parentID and returns the new message's data.
+func sendReply(t *testing.T, token, chatID, parentID, text string) map[string]interface{} {
+	t.Helper()
+	resp := doRequest(t, "POST", "/api/v1/messages", map[string]interface{}{
+		"chat_id":             chatID,
+		"type":                "text",
+		"payload":             map[string]interface{}{"body": text},
+		"client_msg_id":       uniqueID("reply"),
+		"reply_to_message_id": parentID,
+	}, token)
+	require.Contains(t, []int{http.StatusOK, http.StatusCreated}, resp.StatusCode)
+	return parseResponse(t, resp)["data"].(map[string]interface{})
+}
+
+func TestMessage_Thread(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554035")
+	tokenB, _, userB := registerUser(t, "+14155554036")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	rootID := sendMessage(t, tokenA, chatID, "who's up for lunch?", uniqueID("thread-root"))
+
+	first := sendReply(t, tokenB, chatID, rootID, "me")
+	assert.Equal(t, rootID, first["thread_id"])
+	// A reply to a reply joins the root's thread.
+	second := sendReply(t, tokenA, chatID, first["message_id"].(string), "great, noon?")
+	assert.Equal(t, rootID, second["thread_id"])
+	third := sendReply(t, tokenB, chatID, rootID, "noon works")
+
+	resp := doRequest(t, "GET", fmt.Sprintf("/api/v1/messages/%s/thread?limit=2", rootID), nil, tokenB)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data := parseResponse(t, resp)["data"].(map[string]interface{})
+
+	root := data["root"].(map[string]interface{})
+	assert.Equal(t, rootID, root["message_id"])
+	assert.Equal(t, float64(3), root["reply_count"])
+	assert.NotEmpty(t, root["last_reply_at"])
+
+	items := data["items"].([]interface{})
+	require.Len(t, items, 2)
+	assert.Equal(t, first["message_id"], items[0].(map[string]interface{})["message_id"])
+	assert.Equal(t, second["message_id"], items[1].(map[string]interface{})["message_id"])
+	assert.True(t, data["hasMore"].(bool))
+
+	// Fetching by a reply ID returns the same thread; page two holds the last reply.
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages/%s/thread?limit=2&cursor=%s&cursor_id=%s",
+		second["message_id"], url.QueryEscape(data["nextCursor"].(string)), data["nextCursorId"]), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data = parseResponse(t, resp)["data"].(map[string]interface{})
+	items = data["items"].([]interface{})
+	require.Len(t, items, 1)
+	assert.Equal(t, third["message_id"], items[0].(map[string]interface{})["message_id"])
+
+	// Deleting a reply for everyone takes it out of the count and moves
+	// last_reply_at back to the newest reply left.
+	resp = doRequest(t, "DELETE", fmt.Sprintf("/api/v1/messages/%s?for=everyone", third["message_id"]), nil, tokenB)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages/%s/thread", rootID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data = parseResponse(t, resp)["data"].(map[string]interface{})
+	root = data["root"].(map[string]interface{})
+	assert.Equal(t, float64(2), root["reply_count"])
+	assert.Equal(t, second["created_at"], root["last_reply_at"])
+	assert.Len(t, data["items"].([]interface{}), 2)
+}
+
+func TestMessage_ThreadAccess(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554037")
+	_, _, userB := registerUser(t, "+14155554038")
+	tokenC, _, userC := registerUser(t, "+14155554039")
+
+	chatAB := createDirectChat(t, tokenA, userB)
+	chatAC := createDirectChat(t, tokenA, userC)
+	rootID := sendMessage(t, tokenA, chatAB, "private", uniqueID("thread-access"))
+
+	// Outsiders cannot read the thread.
+	resp := doRequest(t, "GET", fmt.Sprintf("/api/v1/messages/%s/thread", rootID), nil, tokenC)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// Replies must target a message in the same chat.
+	resp = doRequest(t, "POST", "/api/v1/messages", map[string]interface{}{
+		"chat_id":             chatAC,
+		"type":                "text",
+		"payload":             map[string]interface{}{"body": "cross-chat reply"},
+		"client_msg_id":       uniqueID("thread-access"),
+		"reply_to_message_id": rootID,
+	}, tokenA)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+// sendPoll creates a poll message and returns its data.
+func sendPoll(t *testing.T, token, chatID string, poll map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	resp := doRequest(t, "POST", "/api/v1/messages", map[string]interface{}{
+		"chat_id":       chatID,
+		"type":          "poll",
+		"payload":       map[string]interface{}{"poll": poll},
+		"client_msg_id": uniqueID("poll"),
+	}, token)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	return parseResponse(t, resp)["data"].(map[string]interface{})
+}
+
+func pollVote(t *testing.T, token, messageID string, optionIDs ...string) *http.Response {
+	t.Helper()
+	if optionIDs == nil {
+		optionIDs = []string{}
+	}
+	return doRequest(t, "POST", fmt.Sprintf("/api/v1/messages/%s/poll/vote", messageID), map[string]interface{}{
+		"option_ids": optionIDs,
+	}, token)
+}
+
+func pollOf(t *testing.T, data map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	return data["payload"].(map[string]interface{})["poll"].(map[string]interface{})
+}
+
+func TestMessage_Poll(t *testing.T) {
+	tokenA, _, userA := registerUser(t, "+14155554040")
+	tokenB, _, userB := registerUser(t, "+14155554041")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	msg := sendPoll(t, tokenA, chatID, map[string]interface{}{
+		"question": "Lunch?",
+		"options":  []map[string]interface{}{{"text": "Tacos"}, {"text": "Sushi"}, {"text": "Pizza"}},
+	})
+	msgID := msg["message_id"].(string)
+	assert.Equal(t, "poll", msg["type"])
+	options := pollOf(t, msg)["options"].([]interface{})
+	require.Len(t, options, 3)
+	assert.Equal(t, "0", options[0].(map[string]interface{})["id"])
+
+	resp := pollVote(t, tokenA, msgID, "0")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// Single-select polls reject multiple options and unknown IDs.
+	resp = pollVote(t, tokenB, msgID, "0", "1")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	resp = pollVote(t, tokenB, msgID, "9")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// Changing a vote moves the tally rather than adding to it.
+	resp = pollVote(t, tokenB, msgID, "0")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	resp = pollVote(t, tokenB, msgID, "2")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	poll := pollOf(t, parseResponse(t, resp)["data"].(map[string]interface{}))
+
+	options = poll["options"].([]interface{})
+	assert.Equal(t, float64(1), options[0].(map[string]interface{})["vote_count"])
+	assert.Equal(t, []interface{}{userA}, options[0].(map[string]interface{})["voters"])
+	assert.Equal(t, float64(0), options[1].(map[string]interface{})["vote_count"])
+	assert.Equal(t, float64(1), options[2].(map[string]interface{})["vote_count"])
+	assert.Equal(t, []interface{}{"2"}, poll["my_votes"])
+
+	// Retracting removes the vote; only the creator can close.
+	resp = pollVote(t, tokenB, msgID)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	poll = pollOf(t, parseResponse(t, resp)["data"].(map[string]interface{}))
+	assert.Equal(t, float64(0), poll["options"].([]interface{})[2].(map[string]interface{})["vote_count"])
+
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/messages/%s/poll/close", msgID), nil, tokenB)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/messages/%s/poll/close", msgID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, pollOf(t, parseResponse(t, resp)["data"].(map[string]interface{}))["closed_at"])
+
+	resp = pollVote(t, tokenB, msgID, "1")
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	body := parseResponse(t, resp)
+	assert.Equal(t, "POLL_CLOSED", body["error"].(map[string]interface{})["code"])
+}
+
+func TestMessage_PollAnonymousMultiSelect(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554042")
+	tokenB, _, userB := registerUser(t, "+14155554043")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	msg := sendPoll(t, tokenA, chatID, map[string]interface{}{
+		"question":     "Which days work?",
+		"options":      []map[string]interface{}{{"text": "Mon"}, {"text": "Tue"}, {"text": "Wed"}},
+		"multi_select": true,
+		"anonymous":    true,
+	})
+	msgID := msg["message_id"].(string)
+
+	resp := pollVote(t, tokenB, msgID, "0", "2")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "GET", fmt.Sprintf("/api/v1/messages?chat_id=%s", chatID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	messages := extractMessageList(t, parseResponse(t, resp)["data"])
+	require.NotEmpty(t, messages)
+	poll := pollOf(t, messages[0].(map[string]interface{}))
+
+	options := poll["options"].([]interface{})
+	assert.Equal(t, float64(1), options[0].(map[string]interface{})["vote_count"])
+	assert.Equal(t, float64(1), options[2].(map[string]interface{})["vote_count"])
+	for _, o := range options {
+		assert.Nil(t, o.(map[string]interface{})["voters"], "anonymous polls must not expose voters")
+	}
+	assert.Nil(t, poll["my_votes"])
+}
+
+func TestMessage_PollValidation(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554044")
+	_, _, userB := registerUser(t, "+14155554045")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	cases := map[string]map[string]interface{}{
+		"no question":    {"options": []map[string]interface{}{{"text": "a"}, {"text": "b"}}},
+		"one option":     {"question": "q", "options": []map[string]interface{}{{"text": "a"}}},
+		"duplicate":      {"question": "q", "options": []map[string]interface{}{{"text": "a"}, {"text": "A"}}},
+		"closes in past": {"question": "q", "options": []map[string]interface{}{{"text": "a"}, {"text": "b"}}, "closes_at": "2020-01-01T00:00:00Z"},
+	}
+	many := make([]map[string]interface{}, 13)
+	for i := range many {
+		many[i] = map[string]interface{}{"text": fmt.Sprintf("option %d", i)}
+	}
+	cases["too many options"] = map[string]interface{}{"question": "q", "options": many}
+
+	for name, poll := range cases {
+		resp := doRequest(t, "POST", "/api/v1/messages", map[string]interface{}{
+			"chat_id":       chatID,
+			"type":          "poll",
+			"payload":       map[string]interface{}{"poll": poll},
+			"client_msg_id": uniqueID("poll-invalid"),
+		}, tokenA)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, name)
+		_ = parseResponseRaw(t, resp)
+	}
+}
+
+func sendLocation(t *testing.T, token, chatID string, location map[string]interface{}) *http.Response {
+	t.Helper()
+	return doRequest(t, "POST", "/api/v1/messages", map[string]interface{}{
+		"chat_id":       chatID,
+		"type":          "location",
+		"payload":       map[string]interface{}{"location": location},
+		"client_msg_id": uniqueID("location"),
+	}, token)
+}
+
+func TestMessage_Location(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554046")
+	_, _, userB := registerUser(t, "+14155554047")
+
+	chatID := createDirectChat(t, tokenA, userB)
+
+	resp := sendLocation(t, tokenA, chatID, map[string]interface{}{
+		"latitude": 37.7749, "longitude": -122.4194, "accuracy_m": 10, "name": "Ferry Building",
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	data := parseResponse(t, resp)["data"].(map[string]interface{})
+	loc := data["payload"].(map[string]interface{})["location"].(map[string]interface{})
+	assert.Equal(t, 37.7749, loc["latitude"])
+	assert.Equal(t, "Ferry Building", loc["name"])
+	assert.Nil(t, loc["live_until"], "static locations are not live")
+
+	invalid := []map[string]interface{}{
+		{"latitude": 91, "longitude": 0},
+		{"latitude": 0, "longitude": -181},
+		{"latitude": 0, "longitude": 0, "accuracy_m": -1},
+		{"latitude": 0, "longitude": 0, "live_duration_seconds": 10},
+		{"latitude": 0, "longitude": 0, "live_duration_seconds": 9 * 3600},
+	}
+	for _, l := range invalid {
+		resp = sendLocation(t, tokenA, chatID, l)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "%v", l)
+		_ = parseResponseRaw(t, resp)
+	}
+
+	// Free-text coordinates are no longer accepted.
+	resp = doRequest(t, "POST", "/api/v1/messages", map[string]interface{}{
+		"chat_id":       chatID,
+		"type":          "location",
+		"payload":       map[string]interface{}{"body": "37.7749,-122.4194"},
+		"client_msg_id": uniqueID("location"),
+	}, tokenA)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+func TestMessage_LiveLocationStop(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554048")
+	tokenB, _, userB := registerUser(t, "+14155554049")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	resp := sendLocation(t, tokenA, chatID, map[string]interface{}{
+		"latitude": 51.5007, "longitude": -0.1246, "live_duration_seconds": 900,
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	data := parseResponse(t, resp)["data"].(map[string]interface{})
+	msgID := data["message_id"].(string)
+	loc := data["payload"].(map[string]interface{})["location"].(map[string]interface{})
+	require.NotEmpty(t, loc["live_until"])
+	assert.Nil(t, loc["live_duration_seconds"])
+
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/messages/%s/location/stop", msgID), nil, tokenB)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/messages/%s/location/stop", msgID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data = parseResponse(t, resp)["data"].(map[string]interface{})
+	stoppedAt := data["payload"].(map[string]interface{})["location"].(map[string]interface{})["stopped_at"]
+	require.NotEmpty(t, stoppedAt)
+
+	// Stopping again is a no-op.
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/messages/%s/location/stop", msgID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data = parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, stoppedAt, data["payload"].(map[string]interface{})["location"].(map[string]interface{})["stopped_at"])
+}
+
+func sendContacts(t *testing.T, token, chatID string, contacts ...map[string]interface{}) *http.Response {
+	t.Helper()
+	return doRequest(t, "POST", "/api/v1/messages", map[string]interface{}{
+		"chat_id":       chatID,
+		"type":          "contact",
+		"payload":       map[string]interface{}{"contacts": contacts},
+		"client_msg_id": uniqueID("contact"),
+	}, token)
+}
+
+func TestMessage_Contact(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554050")
+	_, _, userB := registerUser(t, "+14155554051")
+	_, _, userC := registerUser(t, "+14155554052")
+
+	chatID := createDirectChat(t, tokenA, userB)
+
+	vcard := "BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Carol\\, from work\r\nORG:Acme;Sales\r\n" +
+		"item1.TEL;TYPE=CELL,VOICE:+1 (415) 555-\r\n 4052\r\nTEL;TYPE=HOME:555 0100\r\n" +
+		"EMAIL:Carol@Example.com\r\nEND:VCARD\r\n"
+	resp := sendContacts(t, tokenA, chatID,
+		map[string]interface{}{"vcard": vcard},
+		map[string]interface{}{
+			"name":   "Dave",
+			"phones": []map[string]interface{}{{"number": "0044 20 7946 0000", "type": "work"}},
+		},
+	)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	data := parseResponse(t, resp)["data"].(map[string]interface{})
+	contacts := data["payload"].(map[string]interface{})["contacts"].([]interface{})
+	require.Len(t, contacts, 2)
+
+	carol := contacts[0].(map[string]interface{})
+	assert.Equal(t, "Carol, from work", carol["name"])
+	assert.Equal(t, "Acme, Sales", carol["organization"])
+	assert.Equal(t, userC, carol["user_id"])
+	assert.Equal(t, []interface{}{"carol@example.com"}, carol["emails"])
+	phones := carol["phones"].([]interface{})
+	require.Len(t, phones, 2)
+	mobile := phones[0].(map[string]interface{})
+	assert.Equal(t, "+14155554052", mobile["number"])
+	assert.Equal(t, "mobile", mobile["type"])
+	assert.Equal(t, userC, mobile["user_id"])
+	home := phones[1].(map[string]interface{})
+	assert.Equal(t, "5550100", home["number"], "numbers without a country code keep their digits")
+	assert.Nil(t, home["user_id"])
+	assert.Contains(t, carol["vcard"], "TEL;TYPE=CELL:+14155554052")
+
+	dave := contacts[1].(map[string]interface{})
+	assert.Equal(t, "+442079460000", dave["phones"].([]interface{})[0].(map[string]interface{})["number"])
+	assert.Nil(t, dave["user_id"], "unregistered numbers are not resolved")
+	assert.Contains(t, dave["vcard"], "FN:Dave")
+}
+
+func TestMessage_ContactValidation(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554053")
+	_, _, userB := registerUser(t, "+14155554054")
+
+	chatID := createDirectChat(t, tokenA, userB)
+
+	tooMany := make([]map[string]interface{}, 21)
+	for i := range tooMany {
+		tooMany[i] = map[string]interface{}{"name": fmt.Sprintf("Contact %d", i)}
+	}
+
+	invalid := map[string][]map[string]interface{}{
+		"no contacts": {},
+		"too many":    tooMany,
+		"empty card":  {{"phones": []map[string]interface{}{{"number": "not a number"}}}},
+		"no vcard":    {{"vcard": "FN:Nobody"}},
+		"long name":   {{"name": strings.Repeat("n", 201)}},
+	}
+	for name, contacts := range invalid {
+		resp := sendContacts(t, tokenA, chatID, contacts...)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, name)
+		_ = parseResponseRaw(t, resp)
+	}
+}
+
+func markChatRead(t *testing.T, token, chatID string) {
+	t.Helper()
+	resp := doRequest(t, "POST", "/api/v1/messages/read", map[string]interface{}{"chat_id": chatID}, token)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+// receiptStatuses returns each recipient's status on messageID as token's
+// user sees it.
+func receiptStatuses(t *testing.T, token, messageID string) map[string]string {
+	t.Helper()
+	resp := doRequest(t, "GET", "/api/v1/messages/"+messageID+"/receipts", nil, token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	statuses := make(map[string]string)
+	for _, r := range parseResponse(t, resp)["data"].([]interface{}) {
+		receipt := r.(map[string]interface{})
+		statuses[receipt["user_id"].(string)] = receipt["status"].(string)
+	}
+	return statuses
+}
+
+func unreadCount(t *testing.T, token, chatID string) float64 {
+	t.Helper()
+	resp := doRequest(t, "GET", "/api/v1/chats", nil, token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	for _, item := range extractChatList(t, parseResponse(t, resp)["data"]) {
+		item := item.(map[string]interface{})
+		if id, _ := extractChatInfo(item); id == chatID {
+			return item["unread_count"].(float64)
+		}
+	}
+	t.Fatalf("chat %s not listed", chatID)
+	return 0
+}
+
+func TestMessage_ReadReceiptPrivacy_Direct(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554055")
+	tokenB, _, userB := registerUser(t, "+14155554056")
+	chatID := createDirectChat(t, tokenA, userB)
+
+	connA := connectWS(t, tokenA)
+	defer connA.Close()
+	time.Sleep(300 * time.Millisecond)
+
+	cases := []struct {
+		name               string
+		senderOn, readerOn bool
+		want               string
+	}{
+		{"both on", true, true, "read"},
+		{"reader off", true, false, "delivered"},
+		{"sender off", false, true, "delivered"},
+		{"both off", false, false, "delivered"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			updatePrivacy(t, tokenA, map[string]interface{}{"read_receipts": tc.senderOn})
+			updatePrivacy(t, tokenB, map[string]interface{}{"read_receipts": tc.readerOn})
+
+			msgID := sendMessage(t, tokenA, chatID, "receipt "+tc.name, uniqueID("receipt"))
+			markChatRead(t, tokenB, chatID)
+
+			events := readWSUntil(t, connA, "message.status", 5*time.Second)
+			status := events[len(events)-1]["data"].(map[string]interface{})
+			assert.Equal(t, msgID, status["message_id"])
+			assert.Equal(t, tc.want, status["status"])
+			assert.Equal(t, tc.want, receiptStatuses(t, tokenA, msgID)[userB])
+
+			// The read is stored either way.
+			assert.Zero(t, unreadCount(t, tokenB, chatID))
+		})
+	}
+}
+
+func TestMessage_ReadReceiptPrivacy_Group(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554057")
+	tokenB, _, userB := registerUser(t, "+14155554058")
+	tokenC, _, userC := registerUser(t, "+14155554059")
+
+	resp := doRequest(t, "POST", "/api/v1/chats", map[string]interface{}{
+		"name":       "Receipts Group",
+		"member_ids": []string{userB, userC},
+	}, tokenA)
+	require.Contains(t, []int{http.StatusOK, http.StatusCreated}, resp.StatusCode)
+	chatID, _ := extractChatInfo(parseResponse(t, resp)["data"].(map[string]interface{}))
+
+	// B opts out. The sender's own setting does not matter in groups.
+	updatePrivacy(t, tokenA, map[string]interface{}{"read_receipts": false})
+	updatePrivacy(t, tokenB, map[string]interface{}{"read_receipts": false})
+	updatePrivacy(t, tokenC, nil)
+
+	msgID := sendMessage(t, tokenA, chatID, "group receipts", uniqueID("receipt"))
+	markChatRead(t, tokenB, chatID)
+	markChatRead(t, tokenC, chatID)
+
+	statuses := receiptStatuses(t, tokenA, msgID)
+	assert.Equal(t, "delivered", statuses[userB])
+	assert.Equal(t, "read", statuses[userC])
+	assert.Zero(t, unreadCount(t, tokenB, chatID))
+}
+
+func TestMessage_ReadWatermark(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155554060")
+	tokenB, _, userB := registerUser(t, "+14155554061")
+	tokenC, _, userC := registerUser(t, "+14155554062")
+
+	resp := doRequest(t, "POST", "/api/v1/chats", map[string]interface{}{
+		"name":       "Watermark Group",
+		"member_ids": []string{userB, userC},
+	}, tokenA)
+	require.Contains(t, []int{http.StatusOK, http.StatusCreated}, resp.StatusCode)
+	chatID, _ := extractChatInfo(parseResponse(t, resp)["data"].(map[string]interface{}))
+
+	connC := connectWS(t, tokenC)
+	defer connC.Close()
+	time.Sleep(300 * time.Millisecond)
+
+	first := sendMessage(t, tokenA, chatID, "watermark 1", uniqueID("watermark"))
+	second := sendMessage(t, tokenA, chatID, "watermark 2", uniqueID("watermark"))
+	third := sendMessage(t, tokenA, chatID, "watermark 3", uniqueID("watermark"))
+	assert.Equal(t, float64(3), unreadCount(t, tokenB, chatID))
+
+	// B reads up to the second message: one event covers both.
+	resp = doRequest(t, "POST", "/api/v1/messages/read", map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": second,
+	}, tokenB)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	events := readWSUntil(t, connC, "message.status", 5*time.Second)
+	status := events[len(events)-1]["data"].(map[string]interface{})
+	assert.Equal(t, second, status["message_id"])
+	assert.Equal(t, userB, status["user_id"])
+	assert.Equal(t, "read", status["status"])
+	assert.Positive(t, status["seq"])
+
+	assert.Equal(t, float64(1), unreadCount(t, tokenB, chatID))
+	assert.Equal(t, "read", receiptStatuses(t, tokenA, first)[userB])
+	assert.Equal(t, "read", receiptStatuses(t, tokenA, second)[userB])
+	assert.NotContains(t, receiptStatuses(t, tokenA, third), userB)
+
+	// Watermarks never move back.
+	resp = doRequest(t, "POST", "/api/v1/messages/read", map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": first,
+	}, tokenB)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	assert.Equal(t, float64(1), unreadCount(t, tokenB, chatID))
+	assert.Equal(t, "read", receiptStatuses(t, tokenA, second)[userB])
+
+	// A message from another chat is rejected.
+	otherChat := createDirectChat(t, tokenA, userB)
+	other := sendMessage(t, tokenA, otherChat, "elsewhere", uniqueID("watermark"))
+	resp = doRequest(t, "POST", "/api/v1/messages/read", map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": other,
+	}, tokenB)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	markChatRead(t, tokenB, chatID)
+	assert.Zero(t, unreadCount(t, tokenB, chatID))
+	assert.Equal(t, "read", receiptStatuses(t, tokenA, third)[userB])
+
+	// Messages returned by other calls carry receipts too, e.g. an edit.
+	markChatRead(t, tokenB, otherChat)
+	resp = doRequest(t, "PATCH", "/api/v1/messages/"+other, map[string]interface{}{
+		"body": "elsewhere, edited",
+	}, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "read", parseResponse(t, resp)["data"].(map[string]interface{})["status"])
+
+	// The counter follows new and deleted messages.
+	fourth := sendMessage(t, tokenA, chatID, "watermark 4", uniqueID("watermark"))
+	assert.Equal(t, float64(1), unreadCount(t, tokenB, chatID))
+	assert.Equal(t, float64(4), unreadCount(t, tokenC, chatID))
+	resp = doRequest(t, "DELETE", fmt.Sprintf("/api/v1/messages/%s?for=everyone", fourth), nil, tokenA)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	assert.Zero(t, unreadCount(t, tokenB, chatID))
+	assert.Equal(t, float64(3), unreadCount(t, tokenC, chatID))
+}
@@ -1,9 +1,13 @@
 package tests
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -172,6 +176,10 @@ func TestUserProfile_Presence(t *testing.T) {
 	// online should be a boolean (probably false since user B is not connected via WS)
 	_, hasOnline := data["online"]
 	assert.True(t, hasOnline, "presence response should contain 'online' field")
+
+	resp = doRequest(t, "GET", "/api/v1/users/not-a-uuid/presence", nil, tokenA)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
 }
 
 func TestUserProfile_RegisterDevice(t *testing.T) {
@@ -203,3 +211,268 @@ func TestUserProfile_RemoveDevice(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
 	_ = parseResponseRaw(t, resp)
 }
+
+func getAccountDeletion(t *testing.T, id string) map[string]interface{} {
+	t.Helper()
+	resp := doRequest(t, "GET", "/api/v1/account-deletions/"+id, nil, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	return parseResponse(t, resp)["data"].(map[string]interface{})
+}
+
+func TestUserProfile_AccountDeletion(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155555010")
+	tokenB, _, userB := registerUser(t, "+14155555011")
+
+	resp := doRequest(t, "POST", "/api/v1/chats", map[string]interface{}{
+		"name":       "Deletion Group",
+		"member_ids": []string{userB},
+	}, tokenA)
+	require.Contains(t, []int{http.StatusOK, http.StatusCreated}, resp.StatusCode)
+	chatID, _ := extractChatInfo(parseResponse(t, resp)["data"].(map[string]interface{}))
+
+	// Schedule, then cancel within the grace period.
+	resp = doRequest(t, "DELETE", "/api/v1/users/me", nil, tokenA)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	deletion := parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, "scheduled", deletion["status"])
+	firstID := deletion["id"].(string)
+
+	resp = doRequest(t, "DELETE", "/api/v1/users/me", nil, tokenA)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, firstID, parseResponse(t, resp)["data"].(map[string]interface{})["id"], "a repeated request returns the scheduled deletion")
+
+	resp = doRequest(t, "DELETE", "/api/v1/users/me/deletion", nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "cancelled", parseResponse(t, resp)["data"].(map[string]interface{})["status"])
+
+	resp = doRequest(t, "DELETE", "/api/v1/users/me/deletion", nil, tokenA)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// Schedule again and let it run.
+	resp = doRequest(t, "DELETE", "/api/v1/users/me", nil, tokenA)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	deletionID := parseResponse(t, resp)["data"].(map[string]interface{})["id"].(string)
+	assert.NotEqual(t, firstID, deletionID)
+
+	resp = doRequest(t, "GET", "/api/v1/users/me/deletion", nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, deletionID, parseResponse(t, resp)["data"].(map[string]interface{})["id"])
+
+	deadline := time.Now().Add(90 * time.Second)
+	for {
+		deletion = getAccountDeletion(t, deletionID)
+		if deletion["status"] == "completed" || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(2 * time.Second)
+	}
+	require.Equal(t, "completed", deletion["status"], "deletion should complete: %v", deletion["steps"])
+	assert.NotContains(t, deletion, "user_id")
+
+	steps := map[string]string{}
+	for _, raw := range deletion["steps"].([]interface{}) {
+		step := raw.(map[string]interface{})
+		steps[step["service"].(string)] = step["status"].(string)
+	}
+	for _, service := range []string{"auth", "chat", "message", "media", "notification", "user"} {
+		assert.Equal(t, "completed", steps[service], "step %s", service)
+	}
+
+	// The account's tokens no longer work.
+	resp = doRequest(t, "GET", "/api/v1/users/me", nil, tokenA)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// The remaining member took over as group admin.
+	resp = doRequest(t, "PATCH", fmt.Sprintf("/api/v1/chats/%s", chatID), map[string]interface{}{
+		"name": "Renamed By New Admin",
+	}, tokenB)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+func TestUserProfile_BlockEnforcement(t *testing.T) {
+	tokenA, _, userA := registerUser(t, "+14155552021")
+	tokenB, _, userB := registerUser(t, "+14155552022")
+	_, _, userC := registerUser(t, "+14155552023")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	msgID := sendMessage(t, tokenA, chatID, "before the block", uniqueID("block"))
+	pollID := sendPoll(t, tokenA, chatID, map[string]interface{}{
+		"question": "Still friends?",
+		"options":  []map[string]interface{}{{"text": "Yes"}, {"text": "No"}},
+	})["message_id"].(string)
+
+	connA := connectWS(t, tokenA)
+	defer connA.Close()
+	connB := connectWS(t, tokenB)
+	defer connB.Close()
+
+	resp := doRequest(t, "POST", "/api/v1/users/contacts/"+userB+"/block", nil, tokenA)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// Neither side can message the other.
+	for _, token := range []string{tokenA, tokenB} {
+		resp = doRequest(t, "POST", "/api/v1/messages", map[string]interface{}{
+			"chat_id":       chatID,
+			"type":          "text",
+			"payload":       map[string]interface{}{"body": "blocked"},
+			"client_msg_id": uniqueID("block"),
+		}, token)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		body := parseResponse(t, resp)
+		assert.Equal(t, "USER_BLOCKED", body["error"].(map[string]interface{})["code"])
+	}
+
+	// Nor react, edit or vote in the chat they still share.
+	blocked := []*http.Response{
+		doRequest(t, "POST", "/api/v1/messages/"+msgID+"/react", map[string]interface{}{"emoji": "👍"}, tokenB),
+		doRequest(t, "PATCH", "/api/v1/messages/"+msgID, map[string]interface{}{"body": "edited after the block"}, tokenA),
+		pollVote(t, tokenB, pollID, "0"),
+	}
+	for _, resp := range blocked {
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		body := parseResponse(t, resp)
+		assert.Equal(t, "USER_BLOCKED", body["error"].(map[string]interface{})["code"])
+	}
+
+	// B cannot pull A into a group.
+	resp = doRequest(t, "POST", "/api/v1/chats", map[string]interface{}{
+		"name":       "Block Test Group",
+		"member_ids": []string{userA, userC},
+	}, tokenB)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	// Nor add A to a group B already runs.
+	resp = doRequest(t, "POST", "/api/v1/chats", map[string]interface{}{
+		"name":       "Block Test Group",
+		"member_ids": []string{userC},
+	}, tokenB)
+	require.Contains(t, []int{http.StatusOK, http.StatusCreated}, resp.StatusCode)
+	groupID, _ := extractChatInfo(parseResponse(t, resp)["data"].(map[string]interface{}))
+	resp = doRequest(t, "POST", "/api/v1/chats/"+groupID+"/participants", map[string]interface{}{
+		"user_id": userA,
+	}, tokenB)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	body := parseResponse(t, resp)
+	assert.Equal(t, "USER_BLOCKED", body["error"].(map[string]interface{})["code"])
+
+	// A is offline to B even while connected, over HTTP and over the
+	// websocket.
+	resp = doRequest(t, "GET", "/api/v1/users/"+userA+"/presence", nil, tokenB)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	presence := parseResponse(t, resp)["data"].(map[string]interface{})
+	assert.Equal(t, false, presence["online"])
+	assert.Empty(t, presence["last_seen"])
+
+	data, err := json.Marshal(map[string]interface{}{
+		"event": "presence.subscribe",
+		"data":  map[string]interface{}{"user_ids": []string{userA}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, connB.WriteMessage(websocket.TextMessage, data))
+	events := readWSUntil(t, connB, "presence", 5*time.Second)
+	presence = events[len(events)-1]["data"].(map[string]interface{})
+	assert.Equal(t, userA, presence["user_id"])
+	assert.Equal(t, false, presence["online"])
+	assert.NotContains(t, presence, "last_seen")
+
+	// B cannot call A.
+	data, err = json.Marshal(map[string]interface{}{
+		"event": "call.offer",
+		"data": map[string]interface{}{
+			"call_id":        uniqueID("call"),
+			"target_user_id": userA,
+			"sdp":            "v=0",
+			"call_type":      "audio",
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, connB.WriteMessage(websocket.TextMessage, data))
+	events = readWSUntil(t, connB, "error", 5*time.Second)
+	assert.Equal(t, "cannot call this user", events[len(events)-1]["data"].(map[string]interface{})["message"])
+
+	// Unblocking takes effect straight away.
+	resp = doRequest(t, "DELETE", "/api/v1/users/contacts/"+userB+"/block", nil, tokenA)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+	sendMessage(t, tokenB, chatID, "after the block", uniqueID("block"))
+}
+
+func updatePrivacy(t *testing.T, token string, settings map[string]interface{}) {
+	t.Helper()
+	body := map[string]interface{}{
+		"last_seen":     "everyone",
+		"profile_photo": "everyone",
+		"about":         "everyone",
+		"read_receipts": true,
+	}
+	for k, v := range settings {
+		body[k] = v
+	}
+	resp := doRequest(t, "PUT", "/api/v1/users/privacy", body, token)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
+
+func getPresence(t *testing.T, token, userID string) map[string]interface{} {
+	t.Helper()
+	resp := doRequest(t, "GET", "/api/v1/users/"+userID+"/presence", nil, token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	return parseResponse(t, resp)["data"].(map[string]interface{})
+}
+
+func TestUserProfile_PresencePrivacy(t *testing.T) {
+	tokenA, _, userA := registerUser(t, "+14155552024")
+	tokenB, _, userB := registerUser(t, "+14155552025")
+
+	connA := connectWS(t, tokenA)
+	defer connA.Close()
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, true, getPresence(t, tokenB, userA)["online"])
+
+	// Online status hidden along with the last seen.
+	updatePrivacy(t, tokenA, map[string]interface{}{"last_seen": "nobody", "online": "same_as_last_seen"})
+	assert.Equal(t, false, getPresence(t, tokenB, userA)["online"])
+
+	connB := connectWS(t, tokenB)
+	defer connB.Close()
+	data, err := json.Marshal(map[string]interface{}{
+		"event": "presence.subscribe",
+		"data":  map[string]interface{}{"user_ids": []string{userA}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, connB.WriteMessage(websocket.TextMessage, data))
+	events := readWSUntil(t, connB, "presence", 5*time.Second)
+	assert.Equal(t, false, events[len(events)-1]["data"].(map[string]interface{})["online"])
+
+	// "Nobody except B".
+	updatePrivacy(t, tokenA, map[string]interface{}{
+		"last_seen":        "nobody",
+		"online":           "same_as_last_seen",
+		"last_seen_except": []string{userB},
+	})
+	assert.Equal(t, true, getPresence(t, tokenB, userA)["online"])
+
+	// B hides their own last seen, so A's is hidden from B too.
+	updatePrivacy(t, tokenA, nil)
+	updatePrivacy(t, tokenB, map[string]interface{}{"last_seen": "nobody"})
+	connA.Close()
+	events = readWSUntil(t, connB, "presence", 5*time.Second)
+	offline := events[len(events)-1]["data"].(map[string]interface{})
+	assert.Equal(t, false, offline["online"])
+	assert.NotContains(t, offline, "last_seen")
+	assert.NotContains(t, getPresence(t, tokenB, userA), "last_seen")
+
+	updatePrivacy(t, tokenB, nil)
+	assert.Contains(t, getPresence(t, tokenB, userA), "last_seen")
+
+	resp := doRequest(t, "PUT", "/api/v1/users/privacy", map[string]interface{}{
+		"last_seen": "everyone", "profile_photo": "everyone", "about": "everyone", "online": "contacts",
+	}, tokenA)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+}
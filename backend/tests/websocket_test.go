@@ -2,6 +2,8 @@ package tests
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -137,3 +139,246 @@ func TestWebSocket_RealTimeDelivery(t *testing.T) {
 	assert.NotEmpty(t, event["type"], "should receive an event")
 	t.Logf("Received WS event type: %s", event["type"])
 }
+
+// replayedMessages returns the bodies and sequences of message.new events, in
+// the order they were received.
+func replayedMessages(events []map[string]interface{}) (bodies []string, seqs []uint64) {
+	for _, ev := range events {
+		if ev["event"] != "message.new" {
+			continue
+		}
+		data, _ := ev["data"].(map[string]interface{})
+		payload, _ := data["payload"].(map[string]interface{})
+		body, _ := payload["body"].(string)
+		seq, _ := ev["seq"].(float64)
+		bodies = append(bodies, body)
+		seqs = append(seqs, uint64(seq))
+	}
+	return bodies, seqs
+}
+
+func TestWebSocket_OfflineReplay_LastSeq(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155556010")
+	tokenB, _, userB := registerUser(t, "+14155556011")
+
+	chatID := createDirectChat(t, tokenA, userB)
+
+	connB := connectWS(t, tokenB)
+	time.Sleep(300 * time.Millisecond)
+
+	sendMessage(t, tokenA, chatID, "before disconnect", uniqueID("replay"))
+	events := readWSUntil(t, connB, "message.new", 5*time.Second)
+	first := events[len(events)-1]
+	firstSeq, ok := first["seq"].(float64)
+	require.True(t, ok, "live message.new should carry a seq")
+	require.Positive(t, firstSeq)
+	connB.Close()
+
+	// Messages sent while B is offline.
+	offline := []string{"offline 1", "offline 2", "offline 3"}
+	for _, body := range offline {
+		sendMessage(t, tokenA, chatID, body, uniqueID("replay"))
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	connB = connectWSWithQuery(t, tokenB, fmt.Sprintf("last_seq=%d", uint64(firstSeq)))
+	defer connB.Close()
+
+	events = readWSUntil(t, connB, "sync.complete", 10*time.Second)
+	bodies, seqs := replayedMessages(events)
+	require.Equal(t, offline, bodies, "replay should deliver every missed message in order")
+	for i, seq := range seqs {
+		assert.Greater(t, seq, uint64(firstSeq), "replayed seq should be after the cursor")
+		if i > 0 {
+			assert.Greater(t, seq, seqs[i-1], "replayed seqs should be strictly increasing")
+		}
+	}
+
+	done := events[len(events)-1]["data"].(map[string]interface{})
+	assert.Equal(t, float64(len(offline)), done["replayed"])
+	assert.Equal(t, false, done["truncated"])
+
+	// Live delivery resumes after the replay.
+	sendMessage(t, tokenA, chatID, "after reconnect", uniqueID("replay"))
+	events = readWSUntil(t, connB, "message.new", 5*time.Second)
+	bodies, live := replayedMessages(events)
+	require.NotEmpty(t, bodies)
+	assert.Equal(t, "after reconnect", bodies[len(bodies)-1])
+	assert.Greater(t, live[len(live)-1], seqs[len(seqs)-1], "live seq should follow the replayed backlog")
+}
+
+func TestWebSocket_OfflineReplay_DeviceCursor(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155556012")
+	tokenB, _, userB := registerUser(t, "+14155556013")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	deviceQuery := "device_id=" + uniqueID("device")
+
+	connB := connectWSWithQuery(t, tokenB, deviceQuery)
+	time.Sleep(300 * time.Millisecond)
+
+	sendMessage(t, tokenA, chatID, "seen on device", uniqueID("cursor"))
+	readWSUntil(t, connB, "message.new", 5*time.Second)
+	connB.Close()
+	time.Sleep(300 * time.Millisecond)
+
+	sendMessage(t, tokenA, chatID, "missed on device", uniqueID("cursor"))
+	time.Sleep(500 * time.Millisecond)
+
+	// No last_seq: the server resumes from the cursor it tracked for the device.
+	connB = connectWSWithQuery(t, tokenB, deviceQuery)
+	defer connB.Close()
+
+	events := readWSUntil(t, connB, "sync.complete", 10*time.Second)
+	bodies, _ := replayedMessages(events)
+	assert.Equal(t, []string{"missed on device"}, bodies)
+}
+
+func TestWebSocket_MessageEdited(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155556015")
+	tokenB, _, userB := registerUser(t, "+14155556016")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	msgID := sendMessage(t, tokenA, chatID, "typo", uniqueID("ws-edit"))
+
+	connB := connectWS(t, tokenB)
+	defer connB.Close()
+	time.Sleep(300 * time.Millisecond)
+
+	connA := connectWS(t, tokenA)
+	defer connA.Close()
+
+	event := map[string]interface{}{
+		"event": "message.edit",
+		"data": map[string]interface{}{
+			"message_id": msgID,
+			"body":       "fixed",
+		},
+	}
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+	require.NoError(t, connA.WriteMessage(websocket.TextMessage, data))
+
+	events := readWSUntil(t, connB, "message.edited", 5*time.Second)
+	edited := events[len(events)-1]["data"].(map[string]interface{})
+	assert.Equal(t, msgID, edited["message_id"])
+	assert.Equal(t, "fixed", edited["payload"].(map[string]interface{})["body"])
+}
+
+func TestWebSocket_ThreadUpdated(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155556017")
+	tokenB, _, userB := registerUser(t, "+14155556018")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	rootID := sendMessage(t, tokenA, chatID, "thread root", uniqueID("ws-thread"))
+
+	connA := connectWS(t, tokenA)
+	defer connA.Close()
+	time.Sleep(300 * time.Millisecond)
+
+	resp := doRequest(t, "POST", "/api/v1/messages", map[string]interface{}{
+		"chat_id":             chatID,
+		"type":                "text",
+		"payload":             map[string]interface{}{"body": "thread reply"},
+		"client_msg_id":       uniqueID("ws-thread"),
+		"reply_to_message_id": rootID,
+	}, tokenB)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	events := readWSUntil(t, connA, "thread.updated", 5*time.Second)
+	updated := events[len(events)-1]["data"].(map[string]interface{})
+	assert.Equal(t, rootID, updated["message_id"])
+	assert.Equal(t, chatID, updated["chat_id"])
+	assert.Equal(t, float64(1), updated["reply_count"])
+}
+
+func TestWebSocket_PollVoted(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155556019")
+	tokenB, _, userB := registerUser(t, "+14155556020")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	resp := doRequest(t, "POST", "/api/v1/messages", map[string]interface{}{
+		"chat_id": chatID,
+		"type":    "poll",
+		"payload": map[string]interface{}{"poll": map[string]interface{}{
+			"question": "Coffee or tea?",
+			"options":  []map[string]interface{}{{"text": "Coffee"}, {"text": "Tea"}},
+		}},
+		"client_msg_id": uniqueID("ws-poll"),
+	}, tokenA)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	msgID := parseResponse(t, resp)["data"].(map[string]interface{})["message_id"].(string)
+
+	connA := connectWS(t, tokenA)
+	defer connA.Close()
+	time.Sleep(300 * time.Millisecond)
+
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/messages/%s/poll/vote", msgID), map[string]interface{}{
+		"option_ids": []string{"1"},
+	}, tokenB)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	events := readWSUntil(t, connA, "poll.voted", 5*time.Second)
+	voted := events[len(events)-1]["data"].(map[string]interface{})
+	assert.Equal(t, msgID, voted["message_id"])
+	assert.Equal(t, userB, voted["user_id"])
+	assert.Equal(t, []interface{}{"1"}, voted["option_ids"])
+	assert.Equal(t, float64(1), voted["total_voters"])
+}
+
+func TestWebSocket_LiveLocation(t *testing.T) {
+	tokenA, _, _ := registerUser(t, "+14155556021")
+	tokenB, _, userB := registerUser(t, "+14155556022")
+
+	chatID := createDirectChat(t, tokenA, userB)
+	resp := doRequest(t, "POST", "/api/v1/messages", map[string]interface{}{
+		"chat_id": chatID,
+		"type":    "location",
+		"payload": map[string]interface{}{"location": map[string]interface{}{
+			"latitude": 48.8584, "longitude": 2.2945, "live_duration_seconds": 600,
+		}},
+		"client_msg_id": uniqueID("ws-live"),
+	}, tokenA)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	msgID := parseResponse(t, resp)["data"].(map[string]interface{})["message_id"].(string)
+
+	connB := connectWS(t, tokenB)
+	defer connB.Close()
+	time.Sleep(300 * time.Millisecond)
+
+	connA := connectWS(t, tokenA)
+	defer connA.Close()
+
+	data, err := json.Marshal(map[string]interface{}{
+		"event": "location.update",
+		"data": map[string]interface{}{
+			"message_id": msgID,
+			"latitude":   48.8600,
+			"longitude":  2.2950,
+			"accuracy_m": 5,
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, connA.WriteMessage(websocket.TextMessage, data))
+
+	events := readWSUntil(t, connB, "location.updated", 5*time.Second)
+	updated := events[len(events)-1]["data"].(map[string]interface{})
+	assert.Equal(t, msgID, updated["message_id"])
+	assert.Equal(t, 48.8600, updated["latitude"])
+	assert.NotZero(t, updated["live_until"])
+
+	resp = doRequest(t, "POST", fmt.Sprintf("/api/v1/messages/%s/location/stop", msgID), nil, tokenA)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_ = parseResponseRaw(t, resp)
+
+	events = readWSUntil(t, connB, "location.stopped", 5*time.Second)
+	stopped := events[len(events)-1]["data"].(map[string]interface{})
+	assert.Equal(t, msgID, stopped["message_id"])
+	assert.Equal(t, "stopped", stopped["reason"])
+
+	// The share has ended, so further updates are rejected.
+	require.NoError(t, connA.WriteMessage(websocket.TextMessage, data))
+	readWSUntil(t, connA, "error", 5*time.Second)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/caarlos0/env/v11"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nats-io/nats.go"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
@@ -19,6 +20,7 @@ import (
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/whatsapp-clone/backend/pkg/deviceevents"
 	"github.com/whatsapp-clone/backend/pkg/logger"
 	"github.com/whatsapp-clone/backend/pkg/metrics"
 	"github.com/whatsapp-clone/backend/pkg/middleware"
@@ -72,13 +74,29 @@ func main() {
 	defer rdb.Close()
 	log.Info().Msg("connected to Redis")
 
+	// NATS
+	nc, err := nats.Connect(cfg.NATSUrl)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to NATS")
+	}
+	defer nc.Drain()
+	log.Info().Msg("connected to NATS")
+
+	js, err := nc.JetStream()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to get JetStream context")
+	}
+
 	// Repositories
 	userRepo := repository.NewPostgresUserRepository(pgPool)
 	contactRepo := repository.NewPostgresContactRepository(pgPool)
 	privacyRepo := repository.NewPostgresPrivacyRepository(pgPool)
 	deviceTokenRepo := repository.NewPostgresDeviceTokenRepository(pgPool)
 	presenceRepo := repository.NewRedisPresenceRepository(rdb)
+	blockCacheRepo := repository.NewRedisBlockCacheRepository(rdb)
 	statusRepo := repository.NewPostgresStatusRepository(pgPool)
+	keyRepo := repository.NewPostgresKeyRepository(pgPool)
+	deletionRepo := repository.NewPostgresDeletionRepository(pgPool)
 
 	// Service
 	userSvc := service.NewUserService(
@@ -88,15 +106,32 @@ func main() {
 		deviceTokenRepo,
 		presenceRepo,
 		statusRepo,
+		blockCacheRepo,
 		cfg.PresenceTTL,
+		cfg.BlockCacheTTL,
 		cfg.MediaServiceURL,
 		log,
 	)
 
+	keySvc := service.NewKeyService(keyRepo, rdb, cfg.PreKeyLowThreshold, cfg.KeyFetchLimit, cfg.KeyFetchWindow, log)
+	deletionSvc := service.NewDeletionService(deletionRepo, presenceRepo, js, cfg.DeletionGrace, cfg.DeletionPoll, log)
+
 	// Start periodic status cleanup job
 	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
 	go startStatusCleanupJob(cleanupCtx, statusRepo, log)
 
+	// Start due account deletions and track their progress
+	if err := deletionSvc.Start(cleanupCtx); err != nil {
+		log.Fatal().Err(err).Msg("failed to start account deletion saga")
+	}
+
+	// Drop the encryption keys of devices that are logged out
+	if err := deviceevents.Subscribe(cleanupCtx, js, "user", func(ctx context.Context, e *deviceevents.Revoked) error {
+		return keySvc.DeleteLinkedDeviceKeys(ctx, e.UserID, e.DeviceID)
+	}, log); err != nil {
+		log.Fatal().Err(err).Msg("failed to subscribe to revoked devices")
+	}
+
 	// --- HTTP Server ---
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
@@ -114,11 +149,13 @@ func main() {
 	router.GET("/health", func(c *gin.Context) {
 		pgErr := pgPool.Ping(c.Request.Context())
 		redisErr := rdb.Ping(c.Request.Context()).Err()
-		if pgErr != nil || redisErr != nil {
+		natsOK := nc.IsConnected()
+		if pgErr != nil || redisErr != nil || !natsOK {
 			c.JSON(http.StatusServiceUnavailable, gin.H{
 				"status":   "unhealthy",
 				"postgres": errString(pgErr),
 				"redis":    errString(redisErr),
+				"nats":     boolToStatus(natsOK),
 			})
 			return
 		}
@@ -128,6 +165,8 @@ func main() {
 	httpHandler := handler.NewHTTPHandler(userSvc, log)
 	apiV1 := router.Group("/api/v1/users")
 	httpHandler.RegisterRoutes(apiV1)
+	handler.NewKeyHandler(keySvc).RegisterRoutes(apiV1)
+	handler.NewDeletionHandler(deletionSvc).RegisterRoutes(apiV1)
 
 	// Prometheus metrics endpoint
 	metrics.RegisterMetricsEndpoint(router)
@@ -209,6 +248,13 @@ func errString(err error) string {
 	return "ok"
 }
 
+func boolToStatus(ok bool) string {
+	if ok {
+		return "ok"
+	}
+	return "disconnected"
+}
+
 // startStatusCleanupJob runs immediately on startup, then every hour,
 // and deletes expired statuses. It exits when ctx is cancelled.
 func startStatusCleanupJob(ctx context.Context, statusRepo repository.StatusRepository, log zerolog.Logger) {
@@ -3,13 +3,20 @@ package config
 import "time"
 
 type Config struct {
-	HTTPPort        string        `env:"USER_HTTP_PORT"         envDefault:":8082"`
-	GRPCPort        string        `env:"USER_GRPC_PORT"         envDefault:":9082"`
-	PostgresDSN     string        `env:"USER_POSTGRES_DSN"      envRequired:"true"`
-	RedisAddr       string        `env:"USER_REDIS_ADDR"        envDefault:"redis:6379"`
-	RedisPassword   string        `env:"USER_REDIS_PASSWORD"    envDefault:""`
-	PresenceTTL     time.Duration `env:"USER_PRESENCE_TTL"      envDefault:"60s"`
-	MediaServiceURL string        `env:"USER_MEDIA_SERVICE_URL" envDefault:"http://media-service:8080"`
-	LogLevel        string        `env:"USER_LOG_LEVEL"         envDefault:"info"`
-	OTLPEndpoint    string        `env:"OTLP_ENDPOINT"          envDefault:""`
+	HTTPPort           string        `env:"USER_HTTP_PORT"            envDefault:":8082"`
+	GRPCPort           string        `env:"USER_GRPC_PORT"            envDefault:":9082"`
+	PostgresDSN        string        `env:"USER_POSTGRES_DSN"         envRequired:"true"`
+	RedisAddr          string        `env:"USER_REDIS_ADDR"           envDefault:"redis:6379"`
+	RedisPassword      string        `env:"USER_REDIS_PASSWORD"       envDefault:""`
+	PresenceTTL        time.Duration `env:"USER_PRESENCE_TTL"         envDefault:"60s"`
+	BlockCacheTTL      time.Duration `env:"USER_BLOCK_CACHE_TTL"      envDefault:"1m"`
+	MediaServiceURL    string        `env:"USER_MEDIA_SERVICE_URL"    envDefault:"http://media-service:8080"`
+	PreKeyLowThreshold int           `env:"USER_PREKEY_LOW_THRESHOLD" envDefault:"10"`
+	KeyFetchLimit      int           `env:"USER_KEY_FETCH_LIMIT"      envDefault:"30"`
+	KeyFetchWindow     time.Duration `env:"USER_KEY_FETCH_WINDOW"     envDefault:"1h"`
+	NATSUrl            string        `env:"USER_NATS_URL"             envDefault:"nats://nats:4222"`
+	DeletionGrace      time.Duration `env:"USER_DELETION_GRACE"       envDefault:"168h"`
+	DeletionPoll       time.Duration `env:"USER_DELETION_POLL"        envDefault:"1m"`
+	LogLevel           string        `env:"USER_LOG_LEVEL"            envDefault:"info"`
+	OTLPEndpoint       string        `env:"OTLP_ENDPOINT"             envDefault:""`
 }
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/whatsapp-clone/backend/pkg/response"
+	"github.com/whatsapp-clone/backend/user-service/internal/service"
+)
+
+// DeletionHandler serves account deletion requests and their progress.
+type DeletionHandler struct {
+	deletionSvc service.DeletionService
+}
+
+func NewDeletionHandler(deletionSvc service.DeletionService) *DeletionHandler {
+	return &DeletionHandler{deletionSvc: deletionSvc}
+}
+
+func (h *DeletionHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.DELETE("/me", h.RequestDeletion)
+	r.GET("/me/deletion", h.GetMyDeletion)
+	r.DELETE("/me/deletion", h.CancelDeletion)
+	r.GET("/deletions/:id", h.GetDeletion)
+}
+
+// RequestDeletion schedules the caller's account for deletion once the
+// grace period has passed.
+func (h *DeletionHandler) RequestDeletion(c *gin.Context) {
+	d, err := h.deletionSvc.RequestDeletion(c.Request.Context(), extractUserID(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Accepted(c, d)
+}
+
+// GetMyDeletion returns the caller's most recent deletion request.
+func (h *DeletionHandler) GetMyDeletion(c *gin.Context) {
+	d, err := h.deletionSvc.GetMyDeletion(c.Request.Context(), extractUserID(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, d)
+}
+
+// CancelDeletion cancels the caller's scheduled deletion.
+func (h *DeletionHandler) CancelDeletion(c *gin.Context) {
+	d, err := h.deletionSvc.CancelDeletion(c.Request.Context(), extractUserID(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, d)
+}
+
+// GetDeletion returns a deletion's per-service progress. The gateway serves
+// it without authentication, since the account may no longer exist; the
+// deletion ID is the only handle to it.
+func (h *DeletionHandler) GetDeletion(c *gin.Context) {
+	d, err := h.deletionSvc.GetDeletion(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, d)
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -69,6 +70,29 @@ func (h *GRPCHandler) GetUsers(ctx context.Context, req *userv1.GetUsersRequest)
 	return &userv1.GetUsersResponse{Users: profiles}, nil
 }
 
+func (h *GRPCHandler) GetUsersByPhones(ctx context.Context, req *userv1.GetUsersByPhonesRequest) (*userv1.GetUsersResponse, error) {
+	if len(req.Phones) == 0 {
+		return &userv1.GetUsersResponse{}, nil
+	}
+
+	results, err := h.userSvc.LookupByPhones(ctx, req.Phones)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	var profiles []*userv1.UserProfile
+	for _, r := range results {
+		profiles = append(profiles, &userv1.UserProfile{
+			UserId:      r.UserID,
+			Phone:       r.Phone,
+			DisplayName: r.DisplayName,
+			AvatarUrl:   r.AvatarURL,
+		})
+	}
+
+	return &userv1.GetUsersResponse{Users: profiles}, nil
+}
+
 func (h *GRPCHandler) CheckPresence(ctx context.Context, req *userv1.CheckPresenceRequest) (*userv1.CheckPresenceResponse, error) {
 	online, lastSeen, err := h.userSvc.CheckPresence(ctx, req.UserId)
 	if err != nil {
@@ -94,3 +118,104 @@ func (h *GRPCHandler) GetPrivacySettings(ctx context.Context, req *userv1.GetPri
 		ReadReceipts: settings.ReadReceipts,
 	}, nil
 }
+
+func (h *GRPCHandler) ExportUserData(ctx context.Context, req *userv1.ExportUserDataRequest) (*userv1.ExportUserDataResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id required")
+	}
+
+	export, err := h.userSvc.ExportData(ctx, req.UserId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	data, err := json.Marshal(export)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode export: %v", err)
+	}
+	return &userv1.ExportUserDataResponse{Data: data}, nil
+}
+
+func (h *GRPCHandler) IsBlocked(ctx context.Context, req *userv1.IsBlockedRequest) (*userv1.IsBlockedResponse, error) {
+	if req.UserId == "" || req.OtherUserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and other_user_id required")
+	}
+
+	relation, err := h.userSvc.GetBlockRelation(ctx, req.UserId, req.OtherUserId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &userv1.IsBlockedResponse{
+		Blocked:   relation.Blocked(),
+		Blocking:  relation.Blocking,
+		BlockedBy: relation.BlockedBy,
+	}, nil
+}
+
+func (h *GRPCHandler) GetBlockRelations(ctx context.Context, req *userv1.GetBlockRelationsRequest) (*userv1.GetBlockRelationsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id required")
+	}
+	if len(req.OtherUserIds) == 0 {
+		return &userv1.GetBlockRelationsResponse{}, nil
+	}
+
+	relations, err := h.userSvc.GetBlockRelations(ctx, req.UserId, req.OtherUserIds)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &userv1.GetBlockRelationsResponse{Relations: make([]*userv1.BlockRelation, 0, len(relations))}
+	for _, r := range relations {
+		resp.Relations = append(resp.Relations, &userv1.BlockRelation{
+			UserId:    r.UserID,
+			Blocking:  r.Blocking,
+			BlockedBy: r.BlockedBy,
+		})
+	}
+	return resp, nil
+}
+
+func (h *GRPCHandler) GetPresenceVisibility(ctx context.Context, req *userv1.GetPresenceVisibilityRequest) (*userv1.GetPresenceVisibilityResponse, error) {
+	if len(req.ViewerIds) != 1 && len(req.UserIds) != 1 {
+		return nil, status.Error(codes.InvalidArgument, "either viewer_ids or user_ids must hold exactly one user")
+	}
+
+	visibilities, err := h.userSvc.PresenceVisibility(ctx, req.ViewerIds, req.UserIds)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &userv1.GetPresenceVisibilityResponse{Visibilities: make([]*userv1.PresenceVisibility, 0, len(visibilities))}
+	for _, v := range visibilities {
+		resp.Visibilities = append(resp.Visibilities, &userv1.PresenceVisibility{
+			ViewerId: v.ViewerID,
+			UserId:   v.UserID,
+			Online:   v.Online,
+			LastSeen: v.LastSeen,
+		})
+	}
+	return resp, nil
+}
+
+func (h *GRPCHandler) GetReadReceiptSettings(ctx context.Context, req *userv1.GetReadReceiptSettingsRequest) (*userv1.GetReadReceiptSettingsResponse, error) {
+	if len(req.UserIds) == 0 {
+		return &userv1.GetReadReceiptSettingsResponse{}, nil
+	}
+
+	off, err := h.userSvc.ReadReceiptsOff(ctx, req.UserIds)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &userv1.GetReadReceiptSettingsResponse{ReceiptsOffUserIds: off}, nil
+}
+
+func (h *GRPCHandler) CheckGroupAdd(ctx context.Context, req *userv1.CheckGroupAddRequest) (*userv1.CheckGroupAddResponse, error) {
+	if req.AdderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "adder_id required")
+	}
+
+	denied, err := h.userSvc.GroupAddDenied(ctx, req.AdderId, req.UserIds)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &userv1.CheckGroupAddResponse{DeniedUserIds: denied}, nil
+}
@@ -238,7 +238,7 @@ func (h *HTTPHandler) RemoveDevice(c *gin.Context) {
 
 func (h *HTTPHandler) GetPresence(c *gin.Context) {
 	targetID := c.Param("id")
-	online, lastSeen, err := h.userSvc.CheckPresence(c.Request.Context(), targetID)
+	online, lastSeen, err := h.userSvc.GetPresence(c.Request.Context(), extractUserID(c), targetID)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	"github.com/whatsapp-clone/backend/pkg/response"
+	"github.com/whatsapp-clone/backend/user-service/internal/model"
+	"github.com/whatsapp-clone/backend/user-service/internal/service"
+)
+
+// KeyHandler serves the end-to-end encryption key directory.
+type KeyHandler struct {
+	keySvc service.KeyService
+}
+
+func NewKeyHandler(keySvc service.KeyService) *KeyHandler {
+	return &KeyHandler{keySvc: keySvc}
+}
+
+func (h *KeyHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.PUT("/keys", h.UploadKeys)
+	r.POST("/keys/prekeys", h.ReplenishPreKeys)
+	r.GET("/keys/count", h.GetPreKeyCount)
+	r.GET("/:id/keys", h.FetchBundles)
+}
+
+// UploadKeys publishes the caller's device identity, signed prekey and
+// one-time prekeys. The keys are tied to the logged-in device the gateway
+// names in X-Device-ID.
+func (h *KeyHandler) UploadKeys(c *gin.Context) {
+	var req model.UploadKeysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, apperr.NewBadRequest("invalid request body"))
+		return
+	}
+	count, err := h.keySvc.UploadKeys(c.Request.Context(), extractUserID(c), c.GetHeader("X-Device-ID"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, count)
+}
+
+// ReplenishPreKeys adds one-time prekeys to one of the caller's devices and
+// optionally rotates its signed prekey.
+func (h *KeyHandler) ReplenishPreKeys(c *gin.Context) {
+	var req model.ReplenishPreKeysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, apperr.NewBadRequest("invalid request body"))
+		return
+	}
+	count, err := h.keySvc.ReplenishPreKeys(c.Request.Context(), extractUserID(c), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, count)
+}
+
+// GetPreKeyCount reports how many one-time prekeys a device of the caller has left.
+func (h *KeyHandler) GetPreKeyCount(c *gin.Context) {
+	count, err := h.keySvc.GetPreKeyCount(c.Request.Context(), extractUserID(c), c.Query("device_id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, count)
+}
+
+// FetchBundles returns prekey bundles for a user's devices, consuming one
+// one-time prekey per device.
+func (h *KeyHandler) FetchBundles(c *gin.Context) {
+	bundles, err := h.keySvc.FetchBundles(c.Request.Context(), extractUserID(c), c.Param("id"), c.Query("device_id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.OK(c, bundles)
+}
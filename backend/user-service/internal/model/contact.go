@@ -10,3 +10,28 @@ type Contact struct {
 	IsBlocked bool      `json:"is_blocked" db:"is_blocked"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
+
+// BlockList is everyone a user has blocked and everyone who has blocked them.
+type BlockList struct {
+	Blocking  []string `json:"blocking"`
+	BlockedBy []string `json:"blocked_by"`
+}
+
+// ContactLinks lists which of a set of users are in a user's contacts and
+// which have the user in theirs.
+type ContactLinks struct {
+	Contacts  []string
+	ContactOf []string
+}
+
+// BlockRelation describes the blocks between a user and one other user.
+type BlockRelation struct {
+	UserID    string `json:"user_id"`
+	Blocking  bool   `json:"blocking"`
+	BlockedBy bool   `json:"blocked_by"`
+}
+
+// Blocked reports whether either user has blocked the other.
+func (r BlockRelation) Blocked() bool {
+	return r.Blocking || r.BlockedBy
+}
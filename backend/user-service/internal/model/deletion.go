@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+type DeletionStatus string
+
+const (
+	DeletionScheduled  DeletionStatus = "scheduled"
+	DeletionCancelled  DeletionStatus = "cancelled"
+	DeletionInProgress DeletionStatus = "in_progress"
+	DeletionCompleted  DeletionStatus = "completed"
+)
+
+// AccountDeletion is a request to delete a user's account. It waits out a
+// grace period as scheduled, during which the user can cancel it, then every
+// service erases its data and reports back as a Step. The user record goes
+// last, once all other steps have completed.
+type AccountDeletion struct {
+	ID           string         `json:"id"`
+	UserID       string         `json:"-"`
+	Status       DeletionStatus `json:"status"`
+	ExecuteAfter time.Time      `json:"execute_after"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	CompletedAt  *time.Time     `json:"completed_at,omitempty"`
+	Steps        []DeletionStep `json:"steps"`
+}
+
+type DeletionStepStatus string
+
+const (
+	StepPending   DeletionStepStatus = "pending"
+	StepCompleted DeletionStepStatus = "completed"
+	StepFailed    DeletionStepStatus = "failed"
+)
+
+// DeletionStep is one service's progress on erasing the user's data. A
+// failed step is retried until it completes.
+type DeletionStep struct {
+	Service   string             `json:"service"`
+	Status    DeletionStepStatus `json:"status"`
+	Attempts  int                `json:"attempts"`
+	Error     string             `json:"error,omitempty"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
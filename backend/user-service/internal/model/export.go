@@ -0,0 +1,10 @@
+package model
+
+// DataExport is the user-service share of a personal data export: the
+// user's own records, as the REST API returns them.
+type DataExport struct {
+	Profile  *User            `json:"profile"`
+	Contacts []*Contact       `json:"contacts"`
+	Privacy  *PrivacySettings `json:"privacy"`
+	Statuses []*Status        `json:"statuses"`
+}
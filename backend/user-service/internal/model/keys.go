@@ -0,0 +1,69 @@
+package model
+
+import "time"
+
+// DeviceKeys is a device's published end-to-end encryption identity and its
+// current signed prekey. Keys are raw bytes; JSON carries them as base64.
+type DeviceKeys struct {
+	UserID       string       `json:"user_id"`
+	DeviceID     string       `json:"device_id"`
+	IdentityKey  []byte       `json:"identity_key"`
+	SigningKey   []byte       `json:"signing_key"`
+	SignedPreKey SignedPreKey `json:"signed_prekey"`
+	// LinkedDeviceID is the logged-in device that uploaded the keys; they
+	// are dropped when it is revoked. Empty for keys uploaded before the
+	// link was recorded.
+	LinkedDeviceID string    `json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// SignedPreKey is a prekey signed by the device's signing key.
+type SignedPreKey struct {
+	KeyID     uint32 `json:"key_id"     binding:"required"`
+	PublicKey []byte `json:"public_key" binding:"required"`
+	Signature []byte `json:"signature"  binding:"required"`
+}
+
+// OneTimePreKey is handed out to at most one session initiator.
+type OneTimePreKey struct {
+	KeyID     uint32 `json:"key_id"     binding:"required"`
+	PublicKey []byte `json:"public_key" binding:"required"`
+}
+
+// UploadKeysRequest publishes a device's identity, signed prekey and an
+// initial batch of one-time prekeys. Uploading a different identity for a
+// device discards the one-time prekeys left from the old one.
+type UploadKeysRequest struct {
+	DeviceID       string          `json:"device_id"     binding:"required"`
+	IdentityKey    []byte          `json:"identity_key"  binding:"required"`
+	SigningKey     []byte          `json:"signing_key"   binding:"required"`
+	SignedPreKey   SignedPreKey    `json:"signed_prekey" binding:"required"`
+	OneTimePreKeys []OneTimePreKey `json:"one_time_prekeys"`
+}
+
+// ReplenishPreKeysRequest adds one-time prekeys to a device and optionally
+// rotates its signed prekey.
+type ReplenishPreKeysRequest struct {
+	DeviceID       string          `json:"device_id"     binding:"required"`
+	SignedPreKey   *SignedPreKey   `json:"signed_prekey"`
+	OneTimePreKeys []OneTimePreKey `json:"one_time_prekeys"`
+}
+
+// PreKeyCount is how many one-time prekeys a device has left.
+type PreKeyCount struct {
+	DeviceID  string `json:"device_id"`
+	Remaining int    `json:"remaining"`
+	Threshold int    `json:"threshold"`
+}
+
+// PreKeyBundle is what an initiator needs to open a session with one device.
+// OneTimePreKey is nil when the device has run out.
+type PreKeyBundle struct {
+	UserID        string         `json:"user_id"`
+	DeviceID      string         `json:"device_id"`
+	IdentityKey   []byte         `json:"identity_key"`
+	SigningKey    []byte         `json:"signing_key"`
+	SignedPreKey  SignedPreKey   `json:"signed_prekey"`
+	OneTimePreKey *OneTimePreKey `json:"one_time_prekey,omitempty"`
+}
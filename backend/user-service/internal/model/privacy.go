@@ -8,6 +8,12 @@ const (
 	VisibilityEveryone PrivacyVisibility = "everyone"
 	VisibilityContacts PrivacyVisibility = "contacts"
 	VisibilityNobody   PrivacyVisibility = "nobody"
+	// VisibilityContactsExcept is only valid for GroupAdd: contacts, except
+	// those listed in GroupAddExcept.
+	VisibilityContactsExcept PrivacyVisibility = "contacts_except"
+	// VisibilitySameAsLastSeen is only valid for Online: the user is seen
+	// online by whoever may see their last seen.
+	VisibilitySameAsLastSeen PrivacyVisibility = "same_as_last_seen"
 )
 
 type PrivacySettings struct {
@@ -17,4 +23,24 @@ type PrivacySettings struct {
 	About        PrivacyVisibility `json:"about"          db:"about"`
 	ReadReceipts bool              `json:"read_receipts"  db:"read_receipts"`
 	UpdatedAt    time.Time         `json:"updated_at"     db:"updated_at"`
+
+	// Online is "everyone" or "same_as_last_seen".
+	Online PrivacyVisibility `json:"online" db:"online"`
+	// LastSeenExcept inverts LastSeen for the listed users: with "nobody"
+	// they may see it, with "everyone" or "contacts" they may not.
+	LastSeenExcept []string `json:"last_seen_except" db:"last_seen_except"`
+
+	// GroupAdd is who may add the user to groups; anyone else can only
+	// invite them. "everyone", "contacts", "contacts_except" or "nobody".
+	GroupAdd PrivacyVisibility `json:"group_add" db:"group_add"`
+	// GroupAddExcept lists the contacts who may not, under "contacts_except".
+	GroupAddExcept []string `json:"group_add_except" db:"group_add_except"`
+}
+
+// PresenceVisibility is what ViewerID may see of UserID's presence.
+type PresenceVisibility struct {
+	ViewerID string
+	UserID   string
+	Online   bool
+	LastSeen bool
 }
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/whatsapp-clone/backend/user-service/internal/model"
+)
+
+type redisBlockCacheRepository struct {
+	client *redis.Client
+}
+
+func NewRedisBlockCacheRepository(client *redis.Client) BlockCacheRepository {
+	return &redisBlockCacheRepository{client: client}
+}
+
+func blockListKey(userID string) string {
+	return "blocks:" + userID
+}
+
+func (r *redisBlockCacheRepository) Get(ctx context.Context, userID string) (*model.BlockList, error) {
+	val, err := r.client.Get(ctx, blockListKey(userID)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get block list: %w", err)
+	}
+
+	var list model.BlockList
+	if err := json.Unmarshal(val, &list); err != nil {
+		return nil, fmt.Errorf("decode block list: %w", err)
+	}
+	return &list, nil
+}
+
+func (r *redisBlockCacheRepository) Set(ctx context.Context, userID string, list *model.BlockList, ttl time.Duration) error {
+	data, err := json.Marshal(list)
+	if err != nil {
+		return fmt.Errorf("encode block list: %w", err)
+	}
+	if err := r.client.Set(ctx, blockListKey(userID), data, ttl).Err(); err != nil {
+		return fmt.Errorf("set block list: %w", err)
+	}
+	return nil
+}
+
+func (r *redisBlockCacheRepository) Invalidate(ctx context.Context, userIDs ...string) error {
+	keys := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		keys = append(keys, blockListKey(id))
+	}
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("invalidate block lists: %w", err)
+	}
+	return nil
+}
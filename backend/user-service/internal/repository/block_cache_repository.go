@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/whatsapp-clone/backend/user-service/internal/model"
+)
+
+// BlockCacheRepository caches users' block lists for the block checks other
+// services make on every message, call and presence lookup.
+type BlockCacheRepository interface {
+	// Get returns the cached block list of userID, or nil on a miss.
+	Get(ctx context.Context, userID string) (*model.BlockList, error)
+	Set(ctx context.Context, userID string, list *model.BlockList, ttl time.Duration) error
+	// Invalidate drops the cached block lists of the given users.
+	Invalidate(ctx context.Context, userIDs ...string) error
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
@@ -104,6 +105,69 @@ func (r *postgresContactRepository) IsBlocked(ctx context.Context, userID, conta
 	return blocked, nil
 }
 
+func (r *postgresContactRepository) GetBlockList(ctx context.Context, userID string) (*model.BlockList, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT contact_id, true FROM contacts WHERE user_id = $1 AND is_blocked
+		 UNION ALL
+		 SELECT user_id, false FROM contacts WHERE contact_id = $1 AND is_blocked`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get block list: %w", err)
+	}
+	defer rows.Close()
+
+	list := &model.BlockList{Blocking: []string{}, BlockedBy: []string{}}
+	for rows.Next() {
+		var (
+			otherID  string
+			blocking bool
+		)
+		if err := rows.Scan(&otherID, &blocking); err != nil {
+			return nil, fmt.Errorf("scan block: %w", err)
+		}
+		if blocking {
+			list.Blocking = append(list.Blocking, otherID)
+		} else {
+			list.BlockedBy = append(list.BlockedBy, otherID)
+		}
+	}
+	return list, rows.Err()
+}
+
+func (r *postgresContactRepository) GetContactLinks(ctx context.Context, userID string, otherIDs []string) (*model.ContactLinks, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return &model.ContactLinks{}, nil
+	}
+	rows, err := r.pool.Query(ctx,
+		`SELECT contact_id, true FROM contacts WHERE user_id = $1 AND contact_id = ANY($2::uuid[])
+		 UNION ALL
+		 SELECT user_id, false FROM contacts WHERE contact_id = $1 AND user_id = ANY($2::uuid[])`,
+		userID, validUUIDs(otherIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get contact links: %w", err)
+	}
+	defer rows.Close()
+
+	links := &model.ContactLinks{}
+	for rows.Next() {
+		var (
+			otherID string
+			contact bool
+		)
+		if err := rows.Scan(&otherID, &contact); err != nil {
+			return nil, fmt.Errorf("scan contact link: %w", err)
+		}
+		if contact {
+			links.Contacts = append(links.Contacts, otherID)
+		} else {
+			links.ContactOf = append(links.ContactOf, otherID)
+		}
+	}
+	return links, rows.Err()
+}
+
 func (r *postgresContactRepository) Delete(ctx context.Context, userID, contactID string) error {
 	_, err := r.pool.Exec(ctx,
 		`DELETE FROM contacts WHERE user_id = $1 AND contact_id = $2`,
@@ -13,5 +13,11 @@ type ContactRepository interface {
 	Block(ctx context.Context, userID, contactID string) error
 	Unblock(ctx context.Context, userID, contactID string) error
 	IsBlocked(ctx context.Context, userID, contactID string) (bool, error)
+	// GetBlockList returns everyone userID has blocked and everyone who has
+	// blocked userID.
+	GetBlockList(ctx context.Context, userID string) (*model.BlockList, error)
+	// GetContactLinks reports which of otherIDs userID has as contacts and
+	// which have userID as a contact.
+	GetContactLinks(ctx context.Context, userID string, otherIDs []string) (*model.ContactLinks, error)
 	Delete(ctx context.Context, userID, contactID string) error
 }
@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/whatsapp-clone/backend/user-service/internal/model"
+)
+
+type postgresDeletionRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresDeletionRepository(pool *pgxpool.Pool) DeletionRepository {
+	return &postgresDeletionRepository{pool: pool}
+}
+
+const deletionColumns = `id, user_id, status, execute_after, created_at, updated_at, completed_at`
+
+func scanDeletion(row pgx.Row) (*model.AccountDeletion, error) {
+	var d model.AccountDeletion
+	err := row.Scan(&d.ID, &d.UserID, &d.Status, &d.ExecuteAfter, &d.CreatedAt, &d.UpdatedAt, &d.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	d.Steps = []model.DeletionStep{}
+	return &d, nil
+}
+
+func (r *postgresDeletionRepository) Schedule(ctx context.Context, userID string, executeAfter time.Time) (*model.AccountDeletion, bool, error) {
+	d, err := scanDeletion(r.pool.QueryRow(ctx,
+		`INSERT INTO account_deletions (user_id, execute_after) VALUES ($1, $2)
+		 ON CONFLICT (user_id) WHERE status IN ('scheduled', 'in_progress') DO NOTHING
+		 RETURNING `+deletionColumns,
+		userID, executeAfter,
+	))
+	if err == nil {
+		return d, true, nil
+	}
+	if err != pgx.ErrNoRows {
+		return nil, false, fmt.Errorf("schedule account deletion: %w", err)
+	}
+
+	d, err = r.withSteps(ctx, r.pool.QueryRow(ctx,
+		`SELECT `+deletionColumns+` FROM account_deletions
+		 WHERE user_id = $1 AND status IN ('scheduled', 'in_progress')`, userID,
+	))
+	if err != nil {
+		return nil, false, fmt.Errorf("get active account deletion: %w", err)
+	}
+	if d == nil {
+		// The active deletion was cancelled or completed in between.
+		return nil, false, fmt.Errorf("schedule account deletion: active deletion changed concurrently")
+	}
+	return d, false, nil
+}
+
+func (r *postgresDeletionRepository) GetByID(ctx context.Context, id string) (*model.AccountDeletion, error) {
+	d, err := r.withSteps(ctx, r.pool.QueryRow(ctx,
+		`SELECT `+deletionColumns+` FROM account_deletions WHERE id = $1`, id,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("get account deletion: %w", err)
+	}
+	return d, nil
+}
+
+func (r *postgresDeletionRepository) GetLatestByUser(ctx context.Context, userID string) (*model.AccountDeletion, error) {
+	d, err := r.withSteps(ctx, r.pool.QueryRow(ctx,
+		`SELECT `+deletionColumns+` FROM account_deletions
+		 WHERE user_id = $1 ORDER BY created_at DESC LIMIT 1`, userID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("get latest account deletion: %w", err)
+	}
+	return d, nil
+}
+
+// withSteps scans a deletion and loads its steps. It returns nil if row is
+// empty.
+func (r *postgresDeletionRepository) withSteps(ctx context.Context, row pgx.Row) (*model.AccountDeletion, error) {
+	d, err := scanDeletion(row)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT service, status, attempts, error, updated_at FROM account_deletion_steps
+		 WHERE deletion_id = $1 ORDER BY service`, d.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list deletion steps: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s model.DeletionStep
+		if err := rows.Scan(&s.Service, &s.Status, &s.Attempts, &s.Error, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan deletion step: %w", err)
+		}
+		d.Steps = append(d.Steps, s)
+	}
+	return d, rows.Err()
+}
+
+func (r *postgresDeletionRepository) Cancel(ctx context.Context, userID string) (*model.AccountDeletion, error) {
+	d, err := scanDeletion(r.pool.QueryRow(ctx,
+		`UPDATE account_deletions SET status = 'cancelled', updated_at = NOW()
+		 WHERE user_id = $1 AND status = 'scheduled'
+		 RETURNING `+deletionColumns, userID,
+	))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cancel account deletion: %w", err)
+	}
+	return d, nil
+}
+
+func (r *postgresDeletionRepository) ClaimDue(ctx context.Context, now time.Time, services []string, limit int) ([]*model.AccountDeletion, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin claim deletions: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`UPDATE account_deletions SET status = 'in_progress', updated_at = NOW()
+		 WHERE id IN (
+		   SELECT id FROM account_deletions
+		   WHERE status = 'scheduled' AND execute_after <= $1
+		   ORDER BY execute_after
+		   LIMIT $2
+		   FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING `+deletionColumns,
+		now, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("claim due deletions: %w", err)
+	}
+	var claimed []*model.AccountDeletion
+	for rows.Next() {
+		d, err := scanDeletion(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan claimed deletion: %w", err)
+		}
+		claimed = append(claimed, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("claim due deletions: %w", err)
+	}
+
+	for _, d := range claimed {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO account_deletion_steps (deletion_id, service)
+			 SELECT $1, unnest($2::text[])
+			 ON CONFLICT (deletion_id, service) DO NOTHING`,
+			d.ID, services,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("create deletion steps: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit claim deletions: %w", err)
+	}
+	return claimed, nil
+}
+
+func (r *postgresDeletionRepository) Unclaim(ctx context.Context, id string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin unclaim deletion: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE account_deletions SET status = 'scheduled', updated_at = NOW()
+		 WHERE id = $1 AND status = 'in_progress'`, id,
+	); err != nil {
+		return fmt.Errorf("unclaim deletion: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM account_deletion_steps WHERE deletion_id = $1`, id); err != nil {
+		return fmt.Errorf("drop deletion steps: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *postgresDeletionRepository) RecordStep(ctx context.Context, id, service string, status model.DeletionStepStatus, errMsg string) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE account_deletion_steps
+		 SET status = $3, error = $4, attempts = attempts + 1, updated_at = NOW()
+		 WHERE deletion_id = $1 AND service = $2 AND status <> 'completed'`,
+		id, service, status, errMsg,
+	)
+	if err != nil {
+		return fmt.Errorf("record deletion step: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresDeletionRepository) PendingSteps(ctx context.Context, id string) ([]string, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT s.service FROM account_deletion_steps s
+		 JOIN account_deletions d ON d.id = s.deletion_id
+		 WHERE s.deletion_id = $1 AND d.status = 'in_progress' AND s.status <> 'completed'
+		 ORDER BY s.service`, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list pending deletion steps: %w", err)
+	}
+	defer rows.Close()
+
+	var services []string
+	for rows.Next() {
+		var service string
+		if err := rows.Scan(&service); err != nil {
+			return nil, fmt.Errorf("scan pending deletion step: %w", err)
+		}
+		services = append(services, service)
+	}
+	return services, rows.Err()
+}
+
+func (r *postgresDeletionRepository) Complete(ctx context.Context, id, service string) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin complete deletion: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var userID string
+	err = tx.QueryRow(ctx,
+		`UPDATE account_deletions
+		 SET status = 'completed', completed_at = NOW(), updated_at = NOW()
+		 WHERE id = $1 AND status = 'in_progress'
+		 RETURNING user_id`, id,
+	).Scan(&userID)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("complete deletion: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM users WHERE id = $1`, userID); err != nil {
+		return false, fmt.Errorf("delete user: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE account_deletion_steps
+		 SET status = 'completed', error = '', attempts = attempts + 1, updated_at = NOW()
+		 WHERE deletion_id = $1 AND service = $2`, id, service,
+	); err != nil {
+		return false, fmt.Errorf("complete deletion step: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit complete deletion: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/whatsapp-clone/backend/user-service/internal/model"
+)
+
+type DeletionRepository interface {
+	// Schedule creates a scheduled deletion for the user. If one is already
+	// scheduled or in progress it is returned instead, with created false.
+	Schedule(ctx context.Context, userID string, executeAfter time.Time) (deletion *model.AccountDeletion, created bool, err error)
+
+	// GetByID returns a deletion with its steps, or nil if it does not exist.
+	GetByID(ctx context.Context, id string) (*model.AccountDeletion, error)
+
+	// GetLatestByUser returns the user's most recent deletion with its steps,
+	// or nil if there is none.
+	GetLatestByUser(ctx context.Context, userID string) (*model.AccountDeletion, error)
+
+	// Cancel cancels the user's scheduled deletion. It returns nil if none is
+	// scheduled.
+	Cancel(ctx context.Context, userID string) (*model.AccountDeletion, error)
+
+	// ClaimDue moves up to limit scheduled deletions whose grace period has
+	// passed to in_progress, creating a pending step for each service.
+	ClaimDue(ctx context.Context, now time.Time, services []string, limit int) ([]*model.AccountDeletion, error)
+
+	// Unclaim returns an in-progress deletion to scheduled and drops its
+	// steps, for when its erasure could not be started.
+	Unclaim(ctx context.Context, id string) error
+
+	// RecordStep records a service's outcome for an in-progress deletion. A
+	// completed step stays completed.
+	RecordStep(ctx context.Context, id, service string, status model.DeletionStepStatus, errMsg string) error
+
+	// PendingSteps returns the services that have not yet completed their
+	// step of an in-progress deletion.
+	PendingSteps(ctx context.Context, id string) ([]string, error)
+
+	// Complete deletes the user record, which cascades to everything keyed
+	// on it, and marks the deletion and its service step completed. It
+	// reports false if the deletion was not in progress.
+	Complete(ctx context.Context, id, service string) (bool, error)
+}
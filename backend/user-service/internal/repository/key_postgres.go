@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/whatsapp-clone/backend/user-service/internal/model"
+)
+
+type postgresKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresKeyRepository(pool *pgxpool.Pool) KeyRepository {
+	return &postgresKeyRepository{pool: pool}
+}
+
+const deviceKeysColumns = `user_id, device_id, identity_key, signing_key,
+	signed_prekey_id, signed_prekey, signed_prekey_signature, created_at, updated_at`
+
+func scanDeviceKeys(row pgx.Row) (*model.DeviceKeys, error) {
+	var k model.DeviceKeys
+	err := row.Scan(&k.UserID, &k.DeviceID, &k.IdentityKey, &k.SigningKey,
+		&k.SignedPreKey.KeyID, &k.SignedPreKey.PublicKey, &k.SignedPreKey.Signature, &k.CreatedAt, &k.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *postgresKeyRepository) UpsertDevice(ctx context.Context, keys *model.DeviceKeys, preKeys []model.OneTimePreKey) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin upsert device keys: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Prekeys signed for an old identity are useless to initiators.
+	_, err = tx.Exec(ctx,
+		`DELETE FROM one_time_prekeys o USING device_keys d
+		 WHERE o.user_id = d.user_id AND o.device_id = d.device_id
+		   AND d.user_id = $1 AND d.device_id = $2 AND d.identity_key <> $3`,
+		keys.UserID, keys.DeviceID, keys.IdentityKey,
+	)
+	if err != nil {
+		return fmt.Errorf("drop stale prekeys: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO device_keys (user_id, device_id, identity_key, signing_key,
+		   signed_prekey_id, signed_prekey, signed_prekey_signature, linked_device_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, '')::uuid)
+		 ON CONFLICT (user_id, device_id) DO UPDATE SET
+		   identity_key = EXCLUDED.identity_key,
+		   signing_key = EXCLUDED.signing_key,
+		   signed_prekey_id = EXCLUDED.signed_prekey_id,
+		   signed_prekey = EXCLUDED.signed_prekey,
+		   signed_prekey_signature = EXCLUDED.signed_prekey_signature,
+		   linked_device_id = COALESCE(EXCLUDED.linked_device_id, device_keys.linked_device_id),
+		   updated_at = NOW()`,
+		keys.UserID, keys.DeviceID, keys.IdentityKey, keys.SigningKey,
+		keys.SignedPreKey.KeyID, keys.SignedPreKey.PublicKey, keys.SignedPreKey.Signature,
+		keys.LinkedDeviceID,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert device keys: %w", err)
+	}
+
+	if err := insertPreKeys(ctx, tx, keys.UserID, keys.DeviceID, preKeys); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *postgresKeyRepository) GetDevice(ctx context.Context, userID, deviceID string) (*model.DeviceKeys, error) {
+	k, err := scanDeviceKeys(r.pool.QueryRow(ctx,
+		`SELECT `+deviceKeysColumns+` FROM device_keys WHERE user_id = $1 AND device_id = $2`,
+		userID, deviceID,
+	))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get device keys: %w", err)
+	}
+	return k, nil
+}
+
+func (r *postgresKeyRepository) ListDevices(ctx context.Context, userID string) ([]*model.DeviceKeys, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+deviceKeysColumns+` FROM device_keys WHERE user_id = $1 ORDER BY device_id`, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list device keys: %w", err)
+	}
+	defer rows.Close()
+
+	var devices []*model.DeviceKeys
+	for rows.Next() {
+		k, err := scanDeviceKeys(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan device keys: %w", err)
+		}
+		devices = append(devices, k)
+	}
+	return devices, rows.Err()
+}
+
+func (r *postgresKeyRepository) UpdateSignedPreKey(ctx context.Context, userID, deviceID string, spk *model.SignedPreKey) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE device_keys SET signed_prekey_id = $3, signed_prekey = $4, signed_prekey_signature = $5, updated_at = NOW()
+		 WHERE user_id = $1 AND device_id = $2`,
+		userID, deviceID, spk.KeyID, spk.PublicKey, spk.Signature,
+	)
+	if err != nil {
+		return fmt.Errorf("update signed prekey: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresKeyRepository) AddPreKeys(ctx context.Context, userID, deviceID string, preKeys []model.OneTimePreKey) error {
+	return insertPreKeys(ctx, r.pool, userID, deviceID, preKeys)
+}
+
+// insertPreKeys adds one-time prekeys, replacing any with the same key ID.
+func insertPreKeys(ctx context.Context, db interface {
+	SendBatch(context.Context, *pgx.Batch) pgx.BatchResults
+}, userID, deviceID string, preKeys []model.OneTimePreKey) error {
+	if len(preKeys) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	for _, k := range preKeys {
+		batch.Queue(
+			`INSERT INTO one_time_prekeys (user_id, device_id, key_id, public_key)
+			 VALUES ($1, $2, $3, $4)
+			 ON CONFLICT (user_id, device_id, key_id) DO UPDATE SET
+			   public_key = EXCLUDED.public_key, created_at = NOW()`,
+			userID, deviceID, k.KeyID, k.PublicKey,
+		)
+	}
+	if err := db.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("insert one-time prekeys: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresKeyRepository) ClaimPreKey(ctx context.Context, userID, deviceID string) (*model.OneTimePreKey, error) {
+	var k model.OneTimePreKey
+	err := r.pool.QueryRow(ctx,
+		`DELETE FROM one_time_prekeys
+		 WHERE (user_id, device_id, key_id) = (
+		   SELECT user_id, device_id, key_id FROM one_time_prekeys
+		   WHERE user_id = $1 AND device_id = $2
+		   ORDER BY key_id LIMIT 1
+		   FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING key_id, public_key`,
+		userID, deviceID,
+	).Scan(&k.KeyID, &k.PublicKey)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claim one-time prekey: %w", err)
+	}
+	return &k, nil
+}
+
+func (r *postgresKeyRepository) CountPreKeys(ctx context.Context, userID, deviceID string) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM one_time_prekeys WHERE user_id = $1 AND device_id = $2`,
+		userID, deviceID,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count one-time prekeys: %w", err)
+	}
+	return n, nil
+}
+
+func (r *postgresKeyRepository) DeleteByLinkedDevice(ctx context.Context, userID, linkedDeviceID string) (int, error) {
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM device_keys WHERE user_id = $1 AND linked_device_id = $2`,
+		userID, linkedDeviceID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("delete device keys: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
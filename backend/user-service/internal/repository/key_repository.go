@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/whatsapp-clone/backend/user-service/internal/model"
+)
+
+type KeyRepository interface {
+	// UpsertDevice stores a device's keys and adds its one-time prekeys. If
+	// the identity key changed, the device's old one-time prekeys are
+	// dropped first.
+	UpsertDevice(ctx context.Context, keys *model.DeviceKeys, preKeys []model.OneTimePreKey) error
+	GetDevice(ctx context.Context, userID, deviceID string) (*model.DeviceKeys, error)
+	ListDevices(ctx context.Context, userID string) ([]*model.DeviceKeys, error)
+	UpdateSignedPreKey(ctx context.Context, userID, deviceID string, spk *model.SignedPreKey) error
+	AddPreKeys(ctx context.Context, userID, deviceID string, preKeys []model.OneTimePreKey) error
+	// ClaimPreKey removes and returns the device's lowest-numbered one-time
+	// prekey, or nil when none are left.
+	ClaimPreKey(ctx context.Context, userID, deviceID string) (*model.OneTimePreKey, error)
+	CountPreKeys(ctx context.Context, userID, deviceID string) (int, error)
+	// DeleteByLinkedDevice drops the keys, one-time prekeys included, that
+	// were uploaded from the given logged-in device and returns how many
+	// key devices went.
+	DeleteByLinkedDevice(ctx context.Context, userID, linkedDeviceID string) (int, error)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -57,6 +58,10 @@ func (r *redisPresenceRepository) GetLastSeen(ctx context.Context, userID string
 		return time.Time{}, fmt.Errorf("get last seen: %w", err)
 	}
 
+	// Older values written by websocket-service are Unix milliseconds.
+	if ms, err := strconv.ParseInt(val, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
 	t, err := time.Parse(time.RFC3339, val)
 	if err != nil {
 		return time.Time{}, fmt.Errorf("parse last seen time: %w", err)
@@ -71,3 +76,11 @@ func (r *redisPresenceRepository) Remove(ctx context.Context, userID string) err
 	}
 	return nil
 }
+
+func (r *redisPresenceRepository) Forget(ctx context.Context, userID string) error {
+	err := r.client.Del(ctx, presenceKey(userID), lastSeenKey(userID)).Err()
+	if err != nil {
+		return fmt.Errorf("forget presence: %w", err)
+	}
+	return nil
+}
@@ -20,4 +20,7 @@ type PresenceRepository interface {
 
 	// Remove deletes the presence key (explicit offline).
 	Remove(ctx context.Context, userID string) error
+
+	// Forget deletes all presence state of a deleted account.
+	Forget(ctx context.Context, userID string) error
 }
@@ -21,9 +21,9 @@ func NewPostgresPrivacyRepository(pool *pgxpool.Pool) PrivacyRepository {
 func (r *postgresPrivacyRepository) Get(ctx context.Context, userID string) (*model.PrivacySettings, error) {
 	var p model.PrivacySettings
 	err := r.pool.QueryRow(ctx,
-		`SELECT user_id, last_seen, profile_photo, about, read_receipts, updated_at
+		`SELECT user_id, last_seen, profile_photo, about, read_receipts, updated_at, online, last_seen_except, group_add, group_add_except
 		 FROM privacy_settings WHERE user_id = $1`, userID,
-	).Scan(&p.UserID, &p.LastSeen, &p.ProfilePhoto, &p.About, &p.ReadReceipts, &p.UpdatedAt)
+	).Scan(&p.UserID, &p.LastSeen, &p.ProfilePhoto, &p.About, &p.ReadReceipts, &p.UpdatedAt, &p.Online, &p.LastSeenExcept, &p.GroupAdd, &p.GroupAddExcept)
 
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -36,18 +36,44 @@ func (r *postgresPrivacyRepository) Get(ctx context.Context, userID string) (*mo
 
 func (r *postgresPrivacyRepository) Upsert(ctx context.Context, settings *model.PrivacySettings) error {
 	_, err := r.pool.Exec(ctx,
-		`INSERT INTO privacy_settings (user_id, last_seen, profile_photo, about, read_receipts, updated_at)
-		 VALUES ($1, $2, $3, $4, $5, NOW())
+		`INSERT INTO privacy_settings (user_id, last_seen, profile_photo, about, read_receipts, online, last_seen_except, group_add, group_add_except, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
 		 ON CONFLICT (user_id) DO UPDATE SET
 		   last_seen = EXCLUDED.last_seen,
 		   profile_photo = EXCLUDED.profile_photo,
 		   about = EXCLUDED.about,
 		   read_receipts = EXCLUDED.read_receipts,
+		   online = EXCLUDED.online,
+		   last_seen_except = EXCLUDED.last_seen_except,
+		   group_add = EXCLUDED.group_add,
+		   group_add_except = EXCLUDED.group_add_except,
 		   updated_at = NOW()`,
 		settings.UserID, settings.LastSeen, settings.ProfilePhoto, settings.About, settings.ReadReceipts,
+		settings.Online, settings.LastSeenExcept, settings.GroupAdd, settings.GroupAddExcept,
 	)
 	if err != nil {
 		return fmt.Errorf("upsert privacy settings: %w", err)
 	}
 	return nil
 }
+
+func (r *postgresPrivacyRepository) GetByUserIDs(ctx context.Context, userIDs []string) ([]*model.PrivacySettings, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT user_id, last_seen, profile_photo, about, read_receipts, updated_at, online, last_seen_except, group_add, group_add_except
+		 FROM privacy_settings WHERE user_id = ANY($1::uuid[])`, validUUIDs(userIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get privacy settings by ids: %w", err)
+	}
+	defer rows.Close()
+
+	var settings []*model.PrivacySettings
+	for rows.Next() {
+		var p model.PrivacySettings
+		if err := rows.Scan(&p.UserID, &p.LastSeen, &p.ProfilePhoto, &p.About, &p.ReadReceipts, &p.UpdatedAt, &p.Online, &p.LastSeenExcept, &p.GroupAdd, &p.GroupAddExcept); err != nil {
+			return nil, fmt.Errorf("scan privacy settings: %w", err)
+		}
+		settings = append(settings, &p)
+	}
+	return settings, rows.Err()
+}
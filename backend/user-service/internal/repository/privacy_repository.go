@@ -9,4 +9,6 @@ import (
 type PrivacyRepository interface {
 	Get(ctx context.Context, userID string) (*model.PrivacySettings, error)
 	Upsert(ctx context.Context, settings *model.PrivacySettings) error
+	// GetByUserIDs returns the stored settings of those users that have any.
+	GetByUserIDs(ctx context.Context, userIDs []string) ([]*model.PrivacySettings, error)
 }
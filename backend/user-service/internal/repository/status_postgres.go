@@ -59,7 +59,7 @@ func (r *postgresStatusRepository) GetByUserIDs(ctx context.Context, userIDs []s
 		 LEFT JOIN status_viewers sv ON sv.status_id = s.id
 		 WHERE s.user_id = ANY($1::uuid[]) AND s.expires_at > $2
 		 GROUP BY s.id
-		 ORDER BY s.created_at DESC`, userIDs, now,
+		 ORDER BY s.created_at DESC`, validUUIDs(userIDs), now,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("get statuses by user ids: %w", err)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
@@ -38,7 +39,7 @@ func (r *postgresUserRepository) GetByID(ctx context.Context, id string) (*model
 func (r *postgresUserRepository) GetByIDs(ctx context.Context, ids []string) ([]*model.User, error) {
 	rows, err := r.pool.Query(ctx,
 		`SELECT id, phone, display_name, avatar_url, status_text, created_at, updated_at
-		 FROM users WHERE id = ANY($1::uuid[])`, ids,
+		 FROM users WHERE id = ANY($1::uuid[])`, validUUIDs(ids),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("get users by ids: %w", err)
@@ -138,3 +139,16 @@ func (r *postgresUserRepository) Update(ctx context.Context, id string, req *mod
 	}
 	return &u, nil
 }
+
+// validUUIDs drops the IDs that are not UUIDs before they are cast to
+// uuid[]: one malformed ID would otherwise fail the whole query, and it
+// cannot match a row anyway.
+func validUUIDs(ids []string) []string {
+	valid := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err == nil {
+			valid = append(valid, id)
+		}
+	}
+	return valid
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	"github.com/whatsapp-clone/backend/user-service/internal/model"
+)
+
+func (s *userServiceImpl) GetBlockRelations(ctx context.Context, userID string, otherIDs []string) ([]model.BlockRelation, error) {
+	list, err := s.blockList(ctx, userID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get block list", err)
+	}
+
+	blocking := make(map[string]struct{}, len(list.Blocking))
+	for _, id := range list.Blocking {
+		blocking[id] = struct{}{}
+	}
+	blockedBy := make(map[string]struct{}, len(list.BlockedBy))
+	for _, id := range list.BlockedBy {
+		blockedBy[id] = struct{}{}
+	}
+
+	var relations []model.BlockRelation
+	for _, id := range otherIDs {
+		_, isBlocking := blocking[id]
+		_, isBlockedBy := blockedBy[id]
+		if isBlocking || isBlockedBy {
+			relations = append(relations, model.BlockRelation{UserID: id, Blocking: isBlocking, BlockedBy: isBlockedBy})
+		}
+	}
+	return relations, nil
+}
+
+func (s *userServiceImpl) GetBlockRelation(ctx context.Context, userID, otherID string) (model.BlockRelation, error) {
+	relations, err := s.GetBlockRelations(ctx, userID, []string{otherID})
+	if err != nil || len(relations) == 0 {
+		return model.BlockRelation{UserID: otherID}, err
+	}
+	return relations[0], nil
+}
+
+// blockList returns the user's block list, from the cache when it is there.
+// Both users' entries are dropped whenever a block changes, so the TTL only
+// bounds how long a failed invalidation goes unnoticed.
+func (s *userServiceImpl) blockList(ctx context.Context, userID string) (*model.BlockList, error) {
+	list, err := s.blockCacheRepo.Get(ctx, userID)
+	if err != nil {
+		s.log.Warn().Err(err).Str("user_id", userID).Msg("failed to read cached block list")
+	}
+	if list != nil {
+		return list, nil
+	}
+
+	list, err = s.contactRepo.GetBlockList(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.blockCacheRepo.Set(ctx, userID, list, s.blockCacheTTL); err != nil {
+		s.log.Warn().Err(err).Str("user_id", userID).Msg("failed to cache block list")
+	}
+	return list, nil
+}
+
+func (s *userServiceImpl) invalidateBlockLists(ctx context.Context, userIDs ...string) {
+	if err := s.blockCacheRepo.Invalidate(ctx, userIDs...); err != nil {
+		s.log.Warn().Err(err).Strs("user_ids", userIDs).Msg("failed to invalidate cached block lists")
+	}
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+
+	"github.com/whatsapp-clone/backend/user-service/internal/model"
+)
+
+// DeletionService runs the account deletion saga: it schedules deletions,
+// starts them once their grace period has passed and tracks each service's
+// progress until the user record can be deleted.
+type DeletionService interface {
+	// RequestDeletion schedules the user's account for deletion after the
+	// grace period. If a deletion is already scheduled or in progress it is
+	// returned unchanged.
+	RequestDeletion(ctx context.Context, userID string) (*model.AccountDeletion, error)
+	// GetMyDeletion returns the user's most recent deletion.
+	GetMyDeletion(ctx context.Context, userID string) (*model.AccountDeletion, error)
+	// CancelDeletion cancels the user's deletion while it is still in its
+	// grace period.
+	CancelDeletion(ctx context.Context, userID string) (*model.AccountDeletion, error)
+	// GetDeletion returns a deletion's progress by ID. It stays available
+	// after the account is gone.
+	GetDeletion(ctx context.Context, id string) (*model.AccountDeletion, error)
+	// Start begins starting due deletions and consuming progress reports. It
+	// returns once both are running; they stop when ctx is cancelled.
+	Start(ctx context.Context) error
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog"
+
+	"github.com/whatsapp-clone/backend/pkg/accountdeletion"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	"github.com/whatsapp-clone/backend/pkg/validator"
+	"github.com/whatsapp-clone/backend/user-service/internal/model"
+	"github.com/whatsapp-clone/backend/user-service/internal/repository"
+)
+
+// deletionClaimBatch caps how many due deletions one poll starts.
+const deletionClaimBatch = 20
+
+// progressRetryDelay is how long a progress report that could not be
+// applied waits before it is redelivered.
+const progressRetryDelay = 10 * time.Second
+
+type deletionServiceImpl struct {
+	deletionRepo repository.DeletionRepository
+	presenceRepo repository.PresenceRepository
+	js           nats.JetStreamContext
+	gracePeriod  time.Duration
+	pollInterval time.Duration
+	log          zerolog.Logger
+}
+
+// NewDeletionService creates a DeletionService. Deletions wait gracePeriod
+// before they start; due deletions are looked for every pollInterval.
+func NewDeletionService(
+	deletionRepo repository.DeletionRepository,
+	presenceRepo repository.PresenceRepository,
+	js nats.JetStreamContext,
+	gracePeriod, pollInterval time.Duration,
+	log zerolog.Logger,
+) DeletionService {
+	return &deletionServiceImpl{
+		deletionRepo: deletionRepo,
+		presenceRepo: presenceRepo,
+		js:           js,
+		gracePeriod:  gracePeriod,
+		pollInterval: pollInterval,
+		log:          log,
+	}
+}
+
+func (s *deletionServiceImpl) RequestDeletion(ctx context.Context, userID string) (*model.AccountDeletion, error) {
+	d, created, err := s.deletionRepo.Schedule(ctx, userID, time.Now().Add(s.gracePeriod))
+	if err != nil {
+		return nil, apperr.NewInternal("failed to schedule account deletion", err)
+	}
+	if created {
+		s.log.Info().Str("user_id", userID).Str("deletion_id", d.ID).Time("execute_after", d.ExecuteAfter).Msg("account deletion scheduled")
+	}
+	return d, nil
+}
+
+func (s *deletionServiceImpl) GetMyDeletion(ctx context.Context, userID string) (*model.AccountDeletion, error) {
+	d, err := s.deletionRepo.GetLatestByUser(ctx, userID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get account deletion", err)
+	}
+	if d == nil {
+		return nil, apperr.NewNotFound("no account deletion requested")
+	}
+	return d, nil
+}
+
+func (s *deletionServiceImpl) CancelDeletion(ctx context.Context, userID string) (*model.AccountDeletion, error) {
+	d, err := s.deletionRepo.Cancel(ctx, userID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to cancel account deletion", err)
+	}
+	if d == nil {
+		latest, err := s.deletionRepo.GetLatestByUser(ctx, userID)
+		if err != nil {
+			return nil, apperr.NewInternal("failed to get account deletion", err)
+		}
+		if latest != nil && latest.Status == model.DeletionInProgress {
+			return nil, apperr.NewConflict("account deletion is already in progress and can no longer be cancelled")
+		}
+		return nil, apperr.NewNotFound("no account deletion scheduled")
+	}
+	s.log.Info().Str("user_id", userID).Str("deletion_id", d.ID).Msg("account deletion cancelled")
+	return d, nil
+}
+
+func (s *deletionServiceImpl) GetDeletion(ctx context.Context, id string) (*model.AccountDeletion, error) {
+	if !validator.IsValidUUID(id) {
+		return nil, apperr.NewNotFound("account deletion not found")
+	}
+	d, err := s.deletionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get account deletion", err)
+	}
+	if d == nil {
+		return nil, apperr.NewNotFound("account deletion not found")
+	}
+	return d, nil
+}
+
+func (s *deletionServiceImpl) Start(ctx context.Context) error {
+	if err := accountdeletion.EnsureStream(s.js); err != nil {
+		return err
+	}
+
+	_, err := s.js.Subscribe(accountdeletion.SubjectProgress, func(m *nats.Msg) {
+		var p accountdeletion.Progress
+		if err := json.Unmarshal(m.Data, &p); err != nil || p.DeletionID == "" {
+			s.log.Error().Err(err).Msg("dropping malformed deletion progress report")
+			_ = m.Term()
+			return
+		}
+		if err := s.applyProgress(ctx, &p); err != nil {
+			s.log.Error().Err(err).Str("deletion_id", p.DeletionID).Str("service", p.Service).Msg("failed to apply deletion progress")
+			_ = m.NakWithDelay(progressRetryDelay)
+			return
+		}
+		_ = m.Ack()
+	}, nats.Durable("user-deletion-progress"), nats.ManualAck(), nats.AckWait(30*time.Second))
+	if err != nil {
+		return err
+	}
+
+	go s.runScheduler(ctx)
+	return nil
+}
+
+// runScheduler starts due deletions every poll interval until ctx is
+// cancelled.
+func (s *deletionServiceImpl) runScheduler(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.startDue(ctx)
+		}
+	}
+}
+
+// startDue moves deletions past their grace period to in_progress and asks
+// every service to erase the user's data. A deletion whose event cannot be
+// published goes back to scheduled and is retried on the next poll.
+func (s *deletionServiceImpl) startDue(ctx context.Context) {
+	services := append([]string{accountdeletion.ServiceUser}, accountdeletion.Participants...)
+	claimed, err := s.deletionRepo.ClaimDue(ctx, time.Now(), services, deletionClaimBatch)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to claim due account deletions")
+		return
+	}
+
+	for _, d := range claimed {
+		err := accountdeletion.Publish(s.js, &accountdeletion.Event{
+			DeletionID:  d.ID,
+			UserID:      d.UserID,
+			RequestedAt: d.CreatedAt,
+		})
+		if err != nil {
+			s.log.Error().Err(err).Str("deletion_id", d.ID).Msg("failed to publish account deletion, will retry")
+			if err := s.deletionRepo.Unclaim(ctx, d.ID); err != nil {
+				s.log.Error().Err(err).Str("deletion_id", d.ID).Msg("failed to return account deletion to scheduled")
+			}
+			continue
+		}
+		s.log.Info().Str("deletion_id", d.ID).Str("user_id", d.UserID).Msg("account deletion started")
+	}
+}
+
+// applyProgress records a service's outcome. Once every other service has
+// completed, the user record is deleted last so a failed erasure can still
+// be retried against it.
+func (s *deletionServiceImpl) applyProgress(ctx context.Context, p *accountdeletion.Progress) error {
+	status := model.StepCompleted
+	if p.Status == accountdeletion.StatusFailed {
+		status = model.StepFailed
+	}
+	if err := s.deletionRepo.RecordStep(ctx, p.DeletionID, p.Service, status, p.Error); err != nil {
+		return err
+	}
+	if status != model.StepCompleted {
+		return nil
+	}
+
+	pending, err := s.deletionRepo.PendingSteps(ctx, p.DeletionID)
+	if err != nil {
+		return err
+	}
+	if len(pending) != 1 || pending[0] != accountdeletion.ServiceUser {
+		return nil
+	}
+
+	done, err := s.deletionRepo.Complete(ctx, p.DeletionID, accountdeletion.ServiceUser)
+	if err != nil {
+		return err
+	}
+	if !done {
+		return nil
+	}
+	if err := s.presenceRepo.Forget(ctx, p.UserID); err != nil {
+		s.log.Warn().Err(err).Str("user_id", p.UserID).Msg("failed to clear presence of deleted account")
+	}
+	s.log.Info().Str("deletion_id", p.DeletionID).Str("user_id", p.UserID).Msg("account deletion completed")
+	return nil
+}
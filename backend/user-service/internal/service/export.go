@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	"github.com/whatsapp-clone/backend/user-service/internal/model"
+)
+
+// ExportData gathers everything user-service holds about the user for a
+// personal data export.
+func (s *userServiceImpl) ExportData(ctx context.Context, userID string) (*model.DataExport, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get user", err)
+	}
+	if user == nil {
+		return nil, apperr.NewNotFound("user not found")
+	}
+
+	contacts, err := s.GetContacts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	privacy, err := s.GetPrivacySettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := s.GetMyStatuses(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if contacts == nil {
+		contacts = []*model.Contact{}
+	}
+	if statuses == nil {
+		statuses = []*model.Status{}
+	}
+	return &model.DataExport{Profile: user, Contacts: contacts, Privacy: privacy, Statuses: statuses}, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	"github.com/whatsapp-clone/backend/user-service/internal/model"
+)
+
+// GroupAddDenied returns those of userIDs who do not let adderID add them to
+// groups. "contacts" lets in only users they have saved as a contact,
+// "contacts_except" also leaves out the contacts on their exception list.
+func (s *userServiceImpl) GroupAddDenied(ctx context.Context, adderID string, userIDs []string) ([]string, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	stored, err := s.privacyRepo.GetByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get privacy settings", err)
+	}
+	settings := make(map[string]*model.PrivacySettings, len(stored))
+	for _, p := range stored {
+		settings[p.UserID] = p
+	}
+	links, err := s.contactRepo.GetContactLinks(ctx, adderID, userIDs)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get contacts", err)
+	}
+
+	denied := make([]string, 0)
+	for _, uid := range userIDs {
+		p, ok := settings[uid]
+		if !ok || uid == adderID {
+			continue
+		}
+		// ContactOf: the user has the adder saved as a contact.
+		isContact := slices.Contains(links.ContactOf, uid)
+		var allowed bool
+		switch p.GroupAdd {
+		case model.VisibilityContacts:
+			allowed = isContact
+		case model.VisibilityContactsExcept:
+			allowed = isContact && !slices.Contains(p.GroupAddExcept, adderID)
+		case model.VisibilityNobody:
+			allowed = false
+		default:
+			allowed = true
+		}
+		if !allowed {
+			denied = append(denied, uid)
+		}
+	}
+	return denied, nil
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"github.com/whatsapp-clone/backend/user-service/internal/model"
+)
+
+// KeyService is the directory of end-to-end encryption keys. It stores and
+// hands out public keys only; message content never passes through it.
+type KeyService interface {
+	UploadKeys(ctx context.Context, userID, linkedDeviceID string, req *model.UploadKeysRequest) (*model.PreKeyCount, error)
+	ReplenishPreKeys(ctx context.Context, userID string, req *model.ReplenishPreKeysRequest) (*model.PreKeyCount, error)
+	GetPreKeyCount(ctx context.Context, userID, deviceID string) (*model.PreKeyCount, error)
+	FetchBundles(ctx context.Context, requesterID, targetUserID, deviceID string) ([]*model.PreKeyBundle, error)
+	// DeleteLinkedDeviceKeys drops the keys uploaded from a logged-in device
+	// once it is revoked, so nobody encrypts to it any more.
+	DeleteLinkedDeviceKeys(ctx context.Context, userID, linkedDeviceID string) error
+}
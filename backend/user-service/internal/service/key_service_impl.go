@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+
+	"github.com/whatsapp-clone/backend/pkg/e2ee"
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	"github.com/whatsapp-clone/backend/user-service/internal/model"
+	"github.com/whatsapp-clone/backend/user-service/internal/repository"
+)
+
+// maxPreKeysPerUpload caps the one-time prekeys accepted in one request.
+const maxPreKeysPerUpload = 100
+
+var deviceIDRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+type keyServiceImpl struct {
+	keyRepo      repository.KeyRepository
+	rdb          *redis.Client
+	lowThreshold int
+	fetchLimit   int
+	fetchWindow  time.Duration
+	log          zerolog.Logger
+}
+
+// NewKeyService creates a KeyService. Devices whose one-time prekeys fall
+// below lowThreshold are told to replenish them. A user may fetch another
+// user's bundles fetchLimit times per fetchWindow, since every fetch uses up
+// one-time prekeys.
+func NewKeyService(keyRepo repository.KeyRepository, rdb *redis.Client, lowThreshold, fetchLimit int, fetchWindow time.Duration, log zerolog.Logger) KeyService {
+	return &keyServiceImpl{
+		keyRepo:      keyRepo,
+		rdb:          rdb,
+		lowThreshold: lowThreshold,
+		fetchLimit:   fetchLimit,
+		fetchWindow:  fetchWindow,
+		log:          log,
+	}
+}
+
+// UploadKeys stores a key device's keys. linkedDeviceID is the logged-in
+// device making the request; revoking it drops the keys again.
+func (s *keyServiceImpl) UploadKeys(ctx context.Context, userID, linkedDeviceID string, req *model.UploadKeysRequest) (*model.PreKeyCount, error) {
+	if !deviceIDRegex.MatchString(req.DeviceID) {
+		return nil, apperr.NewBadRequest("device_id must be 1-64 letters, digits, '-' or '_'")
+	}
+	if err := e2ee.ValidatePublicKey(req.IdentityKey); err != nil {
+		return nil, apperr.NewBadRequest("identity_key must be a 32-byte X25519 public key")
+	}
+	if len(req.SigningKey) != e2ee.SigningKeySize {
+		return nil, apperr.NewBadRequest("signing_key must be a 32-byte Ed25519 public key")
+	}
+	if err := validateSignedPreKey(req.SigningKey, &req.SignedPreKey); err != nil {
+		return nil, err
+	}
+	if err := validatePreKeys(req.OneTimePreKeys); err != nil {
+		return nil, err
+	}
+
+	keys := &model.DeviceKeys{
+		UserID:       userID,
+		DeviceID:     req.DeviceID,
+		IdentityKey:  req.IdentityKey,
+		SigningKey:   req.SigningKey,
+		SignedPreKey: req.SignedPreKey,
+	}
+	if _, err := uuid.Parse(linkedDeviceID); err == nil {
+		keys.LinkedDeviceID = linkedDeviceID
+	}
+	if err := s.keyRepo.UpsertDevice(ctx, keys, req.OneTimePreKeys); err != nil {
+		return nil, apperr.NewInternal("failed to store device keys", err)
+	}
+	return s.GetPreKeyCount(ctx, userID, req.DeviceID)
+}
+
+func (s *keyServiceImpl) ReplenishPreKeys(ctx context.Context, userID string, req *model.ReplenishPreKeysRequest) (*model.PreKeyCount, error) {
+	device, err := s.getDevice(ctx, userID, req.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if req.SignedPreKey == nil && len(req.OneTimePreKeys) == 0 {
+		return nil, apperr.NewBadRequest("signed_prekey or one_time_prekeys is required")
+	}
+	if err := validatePreKeys(req.OneTimePreKeys); err != nil {
+		return nil, err
+	}
+
+	if req.SignedPreKey != nil {
+		if err := validateSignedPreKey(device.SigningKey, req.SignedPreKey); err != nil {
+			return nil, err
+		}
+		if err := s.keyRepo.UpdateSignedPreKey(ctx, userID, req.DeviceID, req.SignedPreKey); err != nil {
+			return nil, apperr.NewInternal("failed to rotate signed prekey", err)
+		}
+	}
+	if err := s.keyRepo.AddPreKeys(ctx, userID, req.DeviceID, req.OneTimePreKeys); err != nil {
+		return nil, apperr.NewInternal("failed to store one-time prekeys", err)
+	}
+	return s.GetPreKeyCount(ctx, userID, req.DeviceID)
+}
+
+func (s *keyServiceImpl) GetPreKeyCount(ctx context.Context, userID, deviceID string) (*model.PreKeyCount, error) {
+	if _, err := s.getDevice(ctx, userID, deviceID); err != nil {
+		return nil, err
+	}
+	n, err := s.keyRepo.CountPreKeys(ctx, userID, deviceID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to count one-time prekeys", err)
+	}
+	return &model.PreKeyCount{DeviceID: deviceID, Remaining: n, Threshold: s.lowThreshold}, nil
+}
+
+// FetchBundles returns a prekey bundle for each of the target's devices (or
+// just deviceID when set), consuming one one-time prekey from each.
+func (s *keyServiceImpl) FetchBundles(ctx context.Context, requesterID, targetUserID, deviceID string) ([]*model.PreKeyBundle, error) {
+	if _, err := uuid.Parse(targetUserID); err != nil {
+		return nil, apperr.NewBadRequest("invalid user ID")
+	}
+	if err := s.allowFetch(ctx, requesterID, targetUserID); err != nil {
+		return nil, err
+	}
+
+	var devices []*model.DeviceKeys
+	if deviceID != "" {
+		device, err := s.getDevice(ctx, targetUserID, deviceID)
+		if err != nil {
+			return nil, err
+		}
+		devices = []*model.DeviceKeys{device}
+	} else {
+		var err error
+		devices, err = s.keyRepo.ListDevices(ctx, targetUserID)
+		if err != nil {
+			return nil, apperr.NewInternal("failed to list device keys", err)
+		}
+		if len(devices) == 0 {
+			return nil, apperr.NewNotFound("user has not published encryption keys")
+		}
+	}
+
+	bundles := make([]*model.PreKeyBundle, 0, len(devices))
+	for _, d := range devices {
+		opk, err := s.keyRepo.ClaimPreKey(ctx, d.UserID, d.DeviceID)
+		if err != nil {
+			return nil, apperr.NewInternal("failed to claim one-time prekey", err)
+		}
+		bundles = append(bundles, &model.PreKeyBundle{
+			UserID:        d.UserID,
+			DeviceID:      d.DeviceID,
+			IdentityKey:   d.IdentityKey,
+			SigningKey:    d.SigningKey,
+			SignedPreKey:  d.SignedPreKey,
+			OneTimePreKey: opk,
+		})
+		s.checkPreKeysLow(ctx, d.UserID, d.DeviceID)
+	}
+	return bundles, nil
+}
+
+func (s *keyServiceImpl) DeleteLinkedDeviceKeys(ctx context.Context, userID, linkedDeviceID string) error {
+	n, err := s.keyRepo.DeleteByLinkedDevice(ctx, userID, linkedDeviceID)
+	if err != nil {
+		return apperr.NewInternal("failed to delete device keys", err)
+	}
+	if n > 0 {
+		s.log.Info().Str("user_id", userID).Str("linked_device_id", linkedDeviceID).Int("devices", n).Msg("dropped keys of revoked device")
+	}
+	return nil
+}
+
+// allowFetch counts a bundle fetch against the requester's allowance for the
+// target. Without it one caller could drain the target's one-time prekeys,
+// forcing everyone else onto the weaker no-prekey handshake. Fetching one's
+// own bundles, which a device does to reach the user's other devices, is
+// counted too.
+func (s *keyServiceImpl) allowFetch(ctx context.Context, requesterID, targetUserID string) error {
+	key := "keys:fetch:" + requesterID + ":" + targetUserID
+	pipe := s.rdb.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, s.fetchWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return apperr.NewInternal("failed to check key fetch limit", err)
+	}
+	if incr.Val() > int64(s.fetchLimit) {
+		return apperr.NewTooManyRequests("too many key bundle requests for this user; try again later")
+	}
+	return nil
+}
+
+func (s *keyServiceImpl) getDevice(ctx context.Context, userID, deviceID string) (*model.DeviceKeys, error) {
+	if !deviceIDRegex.MatchString(deviceID) {
+		return nil, apperr.NewBadRequest("invalid device_id")
+	}
+	device, err := s.keyRepo.GetDevice(ctx, userID, deviceID)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get device keys", err)
+	}
+	if device == nil {
+		return nil, apperr.NewNotFound("no keys published for this device")
+	}
+	return device, nil
+}
+
+// checkPreKeysLow tells the device's owner to upload more one-time prekeys
+// once they drop below the threshold. The keys.low event goes straight to
+// the owner's websocket channel; offline devices catch up through
+// GET /keys/count when they reconnect.
+func (s *keyServiceImpl) checkPreKeysLow(ctx context.Context, userID, deviceID string) {
+	n, err := s.keyRepo.CountPreKeys(ctx, userID, deviceID)
+	if err != nil {
+		s.log.Warn().Err(err).Str("user_id", userID).Str("device_id", deviceID).Msg("failed to count one-time prekeys")
+		return
+	}
+	if n >= s.lowThreshold {
+		return
+	}
+
+	count, _ := json.Marshal(model.PreKeyCount{DeviceID: deviceID, Remaining: n, Threshold: s.lowThreshold})
+	data, _ := json.Marshal(map[string]json.RawMessage{
+		"event": json.RawMessage(`"keys.low"`),
+		"data":  count,
+	})
+	if err := s.rdb.Publish(ctx, "user:channel:"+userID, data).Err(); err != nil {
+		s.log.Warn().Err(err).Str("user_id", userID).Msg("failed to publish keys.low")
+	}
+}
+
+func validateSignedPreKey(signingKey []byte, spk *model.SignedPreKey) error {
+	if spk.KeyID == 0 {
+		return apperr.NewBadRequest("signed_prekey.key_id must be positive")
+	}
+	if err := e2ee.VerifySignedPreKey(signingKey, spk.PublicKey, spk.Signature); err != nil {
+		return apperr.NewBadRequest("signed_prekey signature does not verify against signing_key")
+	}
+	if err := e2ee.ValidatePublicKey(spk.PublicKey); err != nil {
+		return apperr.NewBadRequest("signed_prekey.public_key must be a 32-byte X25519 public key")
+	}
+	return nil
+}
+
+func validatePreKeys(keys []model.OneTimePreKey) error {
+	if len(keys) > maxPreKeysPerUpload {
+		return apperr.NewBadRequest(fmt.Sprintf("max %d one-time prekeys per request", maxPreKeysPerUpload))
+	}
+	seen := make(map[uint32]bool, len(keys))
+	for _, k := range keys {
+		if k.KeyID == 0 {
+			return apperr.NewBadRequest("one-time prekey key_id must be positive")
+		}
+		if seen[k.KeyID] {
+			return apperr.NewBadRequest(fmt.Sprintf("duplicate one-time prekey key_id %d", k.KeyID))
+		}
+		seen[k.KeyID] = true
+		if err := e2ee.ValidatePublicKey(k.PublicKey); err != nil {
+			return apperr.NewBadRequest(fmt.Sprintf("one-time prekey %d is not a 32-byte X25519 public key", k.KeyID))
+		}
+	}
+	return nil
+}
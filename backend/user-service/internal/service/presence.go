@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	apperr "github.com/whatsapp-clone/backend/pkg/errors"
+	"github.com/whatsapp-clone/backend/user-service/internal/model"
+)
+
+// PresenceVisibility works out, for every pair of a viewer and a user, what
+// the viewer may see of the user's presence. Either viewerIDs or userIDs must
+// hold exactly one user.
+//
+// The last seen is shared only both ways, as in WhatsApp: a viewer who hides
+// their own last seen from the user does not see the user's either. Being
+// online follows the user's own setting alone. Users with a block between
+// them see nothing of each other.
+func (s *userServiceImpl) PresenceVisibility(ctx context.Context, viewerIDs, userIDs []string) ([]model.PresenceVisibility, error) {
+	var (
+		pivot    string
+		others   []string
+		isViewer bool
+	)
+	switch {
+	case len(viewerIDs) == 1:
+		pivot, others, isViewer = viewerIDs[0], userIDs, true
+	case len(userIDs) == 1:
+		pivot, others = userIDs[0], viewerIDs
+	default:
+		return nil, apperr.NewBadRequest("either viewer_ids or user_ids must hold exactly one user")
+	}
+	if len(others) == 0 {
+		return nil, nil
+	}
+
+	relations, err := s.GetBlockRelations(ctx, pivot, others)
+	if err != nil {
+		return nil, err
+	}
+	blocked := make(map[string]struct{}, len(relations))
+	for _, r := range relations {
+		blocked[r.UserID] = struct{}{}
+	}
+
+	links, err := s.contactRepo.GetContactLinks(ctx, pivot, others)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get contacts", err)
+	}
+	stored, err := s.privacyRepo.GetByUserIDs(ctx, append([]string{pivot}, others...))
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get privacy settings", err)
+	}
+	settings := make(map[string]*model.PrivacySettings, len(stored))
+	for _, p := range stored {
+		settings[p.UserID] = p
+	}
+	settingsOf := func(userID string) *model.PrivacySettings {
+		if p, ok := settings[userID]; ok {
+			return p
+		}
+		return defaultPrivacySettings(userID)
+	}
+
+	visibilities := make([]model.PresenceVisibility, 0, len(others))
+	for _, other := range others {
+		viewerID, userID := other, pivot
+		// pivotHas: the pivot has other as a contact; otherHas: the reverse.
+		pivotHas := slices.Contains(links.Contacts, other)
+		otherHas := slices.Contains(links.ContactOf, other)
+		userHasViewer, viewerHasUser := pivotHas, otherHas
+		if isViewer {
+			viewerID, userID = pivot, other
+			userHasViewer, viewerHasUser = otherHas, pivotHas
+		}
+
+		v := model.PresenceVisibility{ViewerID: viewerID, UserID: userID}
+		_, isBlocked := blocked[other]
+		switch {
+		case viewerID == userID:
+			v.Online, v.LastSeen = true, true
+		case !isBlocked:
+			user := settingsOf(userID)
+			shared := lastSeenVisible(user, viewerID, userHasViewer)
+			v.LastSeen = shared && lastSeenVisible(settingsOf(viewerID), userID, viewerHasUser)
+			v.Online = user.Online != model.VisibilitySameAsLastSeen || shared
+		}
+		visibilities = append(visibilities, v)
+	}
+	return visibilities, nil
+}
+
+// lastSeenVisible reports whether p's owner shares their last seen with
+// viewerID. The exception list turns "nobody" into "nobody except" and
+// "everyone" or "contacts" into "... except".
+func lastSeenVisible(p *model.PrivacySettings, viewerID string, isContact bool) bool {
+	excepted := slices.Contains(p.LastSeenExcept, viewerID)
+	if p.LastSeen == model.VisibilityNobody {
+		return excepted
+	}
+	return !excepted && canSee(p.LastSeen, isContact, false)
+}
+
+func defaultPrivacySettings(userID string) *model.PrivacySettings {
+	return &model.PrivacySettings{
+		UserID:         userID,
+		LastSeen:       model.VisibilityEveryone,
+		ProfilePhoto:   model.VisibilityEveryone,
+		About:          model.VisibilityEveryone,
+		ReadReceipts:   true,
+		Online:         model.VisibilityEveryone,
+		LastSeenExcept: []string{},
+		GroupAdd:       model.VisibilityEveryone,
+		GroupAddExcept: []string{},
+	}
+}
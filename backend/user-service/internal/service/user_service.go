@@ -11,22 +11,39 @@ import (
 type UserService interface {
 	GetProfile(ctx context.Context, callerID, targetID string) (*model.User, error)
 	GetUsersByIDs(ctx context.Context, ids []string) ([]*model.User, error)
+	LookupByPhones(ctx context.Context, phones []string) ([]*model.ContactSyncResult, error)
 	UpdateProfile(ctx context.Context, userID string, req *model.UpdateProfileRequest) (*model.User, error)
 	UploadAvatar(ctx context.Context, userID string, file io.Reader, size int64, contentType string) (string, error)
 	ContactSync(ctx context.Context, userID string, phones []string) ([]*model.ContactSyncResult, error)
 	GetContacts(ctx context.Context, userID string) ([]*model.Contact, error)
 	BlockUser(ctx context.Context, userID, targetID string) error
 	UnblockUser(ctx context.Context, userID, targetID string) error
+	// GetBlockRelation reports the blocks between userID and otherID in both
+	// directions.
+	GetBlockRelation(ctx context.Context, userID, otherID string) (model.BlockRelation, error)
+	// GetBlockRelations returns the relations of userID with those of
+	// otherIDs that have a block in either direction.
+	GetBlockRelations(ctx context.Context, userID string, otherIDs []string) ([]model.BlockRelation, error)
 	GetPrivacySettings(ctx context.Context, userID string) (*model.PrivacySettings, error)
 	UpdatePrivacySettings(ctx context.Context, settings *model.PrivacySettings) error
+	// ReadReceiptsOff returns those of userIDs who turned read receipts off.
+	ReadReceiptsOff(ctx context.Context, userIDs []string) ([]string, error)
+	// GroupAddDenied returns those of userIDs whose privacy settings keep
+	// adderID from adding them to groups.
+	GroupAddDenied(ctx context.Context, adderID string, userIDs []string) ([]string, error)
 	RegisterDeviceToken(ctx context.Context, token *model.DeviceToken) error
 	RemoveDeviceToken(ctx context.Context, token string) error
 	RemoveDeviceTokenForUser(ctx context.Context, userID, token string) error
 	SetPresence(ctx context.Context, userID string, online bool) error
 	CheckPresence(ctx context.Context, userID string) (online bool, lastSeen *time.Time, err error)
+	GetPresence(ctx context.Context, viewerID, userID string) (online bool, lastSeen *time.Time, err error)
+	// PresenceVisibility reports what each viewer may see of each user's
+	// presence. Either viewerIDs or userIDs must hold exactly one user.
+	PresenceVisibility(ctx context.Context, viewerIDs, userIDs []string) ([]model.PresenceVisibility, error)
 	CreateStatus(ctx context.Context, userID string, req *model.CreateStatusRequest) (*model.Status, error)
 	GetMyStatuses(ctx context.Context, userID string) ([]*model.Status, error)
 	GetContactStatuses(ctx context.Context, userID string) ([]*model.Status, error)
 	DeleteStatus(ctx context.Context, statusID, userID string) error
 	ViewStatus(ctx context.Context, statusID, viewerID string) error
+	ExportData(ctx context.Context, userID string) (*model.DataExport, error)
 }
@@ -9,7 +9,6 @@ import (
 	"mime/multipart"
 	"net/http"
 	"regexp"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,7 +26,9 @@ type userServiceImpl struct {
 	deviceTokenRepo repository.DeviceTokenRepository
 	presenceRepo    repository.PresenceRepository
 	statusRepo      repository.StatusRepository
+	blockCacheRepo  repository.BlockCacheRepository
 	presenceTTL     time.Duration
+	blockCacheTTL   time.Duration
 	mediaServiceURL string
 	httpClient      *http.Client
 	log             zerolog.Logger
@@ -42,7 +43,9 @@ func NewUserService(
 	deviceTokenRepo repository.DeviceTokenRepository,
 	presenceRepo repository.PresenceRepository,
 	statusRepo repository.StatusRepository,
+	blockCacheRepo repository.BlockCacheRepository,
 	presenceTTL time.Duration,
+	blockCacheTTL time.Duration,
 	mediaServiceURL string,
 	log zerolog.Logger,
 ) UserService {
@@ -56,7 +59,9 @@ func NewUserService(
 		deviceTokenRepo: deviceTokenRepo,
 		presenceRepo:    presenceRepo,
 		statusRepo:      statusRepo,
+		blockCacheRepo:  blockCacheRepo,
 		presenceTTL:     presenceTTL,
+		blockCacheTTL:   blockCacheTTL,
 		mediaServiceURL: mediaServiceURL,
 		httpClient:      &http.Client{Timeout: 30 * time.Second},
 		log:             log,
@@ -122,6 +127,29 @@ func (s *userServiceImpl) GetUsersByIDs(ctx context.Context, ids []string) ([]*m
 	return users, nil
 }
 
+// LookupByPhones resolves E.164 numbers to registered users without touching
+// the caller's contact list. Malformed numbers are skipped.
+func (s *userServiceImpl) LookupByPhones(ctx context.Context, phones []string) ([]*model.ContactSyncResult, error) {
+	if len(phones) > 1000 {
+		return nil, apperr.NewBadRequest("max 1000 phones per lookup")
+	}
+	validPhones := make([]string, 0, len(phones))
+	for _, p := range phones {
+		if e164Regex.MatchString(p) {
+			validPhones = append(validPhones, p)
+		}
+	}
+	if len(validPhones) == 0 {
+		return nil, nil
+	}
+
+	results, err := s.userRepo.GetByPhones(ctx, validPhones)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to look up phones", err)
+	}
+	return results, nil
+}
+
 func (s *userServiceImpl) UpdateProfile(ctx context.Context, userID string, req *model.UpdateProfileRequest) (*model.User, error) {
 	if req.DisplayName == nil && req.AvatarURL == nil && req.StatusText == nil {
 		return nil, apperr.NewBadRequest("at least one field must be provided")
@@ -265,9 +293,13 @@ func (s *userServiceImpl) GetContacts(ctx context.Context, userID string) ([]*mo
 }
 
 func (s *userServiceImpl) BlockUser(ctx context.Context, userID, targetID string) error {
+	if userID == targetID {
+		return apperr.NewBadRequest("cannot block yourself")
+	}
 	if err := s.contactRepo.Block(ctx, userID, targetID); err != nil {
 		return apperr.NewInternal("failed to block user", err)
 	}
+	s.invalidateBlockLists(ctx, userID, targetID)
 	return nil
 }
 
@@ -275,6 +307,7 @@ func (s *userServiceImpl) UnblockUser(ctx context.Context, userID, targetID stri
 	if err := s.contactRepo.Unblock(ctx, userID, targetID); err != nil {
 		return apperr.NewInternal("failed to unblock user", err)
 	}
+	s.invalidateBlockLists(ctx, userID, targetID)
 	return nil
 }
 
@@ -285,24 +318,63 @@ func (s *userServiceImpl) GetPrivacySettings(ctx context.Context, userID string)
 	}
 	if settings == nil {
 		// Return defaults when no settings exist yet.
-		return &model.PrivacySettings{
-			UserID:       userID,
-			LastSeen:     model.VisibilityEveryone,
-			ProfilePhoto: model.VisibilityEveryone,
-			About:        model.VisibilityEveryone,
-			ReadReceipts: true,
-		}, nil
+		return defaultPrivacySettings(userID), nil
 	}
 	return settings, nil
 }
 
 func (s *userServiceImpl) UpdatePrivacySettings(ctx context.Context, settings *model.PrivacySettings) error {
+	switch settings.Online {
+	case "":
+		settings.Online = model.VisibilityEveryone
+	case model.VisibilityEveryone, model.VisibilitySameAsLastSeen:
+	default:
+		return apperr.NewBadRequest("online must be 'everyone' or 'same_as_last_seen'")
+	}
+	if settings.LastSeenExcept == nil {
+		settings.LastSeenExcept = []string{}
+	}
+	for _, id := range settings.LastSeenExcept {
+		if _, err := uuid.Parse(id); err != nil {
+			return apperr.NewBadRequest("last_seen_except must hold user IDs")
+		}
+	}
+	switch settings.GroupAdd {
+	case "":
+		settings.GroupAdd = model.VisibilityEveryone
+	case model.VisibilityEveryone, model.VisibilityContacts, model.VisibilityContactsExcept, model.VisibilityNobody:
+	default:
+		return apperr.NewBadRequest("group_add must be 'everyone', 'contacts', 'contacts_except' or 'nobody'")
+	}
+	if settings.GroupAddExcept == nil {
+		settings.GroupAddExcept = []string{}
+	}
+	for _, id := range settings.GroupAddExcept {
+		if _, err := uuid.Parse(id); err != nil {
+			return apperr.NewBadRequest("group_add_except must hold user IDs")
+		}
+	}
+
 	if err := s.privacyRepo.Upsert(ctx, settings); err != nil {
 		return apperr.NewInternal("failed to update privacy settings", err)
 	}
 	return nil
 }
 
+func (s *userServiceImpl) ReadReceiptsOff(ctx context.Context, userIDs []string) ([]string, error) {
+	settings, err := s.privacyRepo.GetByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, apperr.NewInternal("failed to get privacy settings", err)
+	}
+	off := make([]string, 0)
+	for _, p := range settings {
+		if !p.ReadReceipts {
+			off = append(off, p.UserID)
+		}
+	}
+	return off, nil
+}
+
 func (s *userServiceImpl) RegisterDeviceToken(ctx context.Context, token *model.DeviceToken) error {
 	if err := s.deviceTokenRepo.Upsert(ctx, token); err != nil {
 		return apperr.NewInternal("failed to register device token", err)
@@ -359,6 +431,30 @@ func (s *userServiceImpl) CheckPresence(ctx context.Context, userID string) (boo
 	return false, &lastSeen, nil
 }
 
+// GetPresence is userID's presence as viewerID may see it under both users'
+// privacy settings; see PresenceVisibility.
+func (s *userServiceImpl) GetPresence(ctx context.Context, viewerID, userID string) (bool, *time.Time, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return false, nil, apperr.NewBadRequest("invalid user ID")
+	}
+	online, lastSeen, err := s.CheckPresence(ctx, userID)
+	if err != nil || viewerID == "" || viewerID == userID {
+		return online, lastSeen, err
+	}
+
+	visibility, err := s.PresenceVisibility(ctx, []string{viewerID}, []string{userID})
+	if err != nil {
+		return false, nil, err
+	}
+	if !visibility[0].Online {
+		online = false
+	}
+	if !visibility[0].LastSeen {
+		lastSeen = nil
+	}
+	return online, lastSeen, nil
+}
+
 func (s *userServiceImpl) CreateStatus(ctx context.Context, userID string, req *model.CreateStatusRequest) (*model.Status, error) {
 	if req.Type != "text" && req.Type != "image" {
 		return nil, apperr.NewBadRequest("type must be 'text' or 'image'")
@@ -19,7 +19,9 @@ import (
 	authv1 "github.com/whatsapp-clone/backend/proto/auth/v1"
 	chatv1 "github.com/whatsapp-clone/backend/proto/chat/v1"
 	messagev1 "github.com/whatsapp-clone/backend/proto/message/v1"
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
 
+	"github.com/whatsapp-clone/backend/pkg/jwt"
 	"github.com/whatsapp-clone/backend/pkg/logger"
 	"github.com/whatsapp-clone/backend/pkg/metrics"
 	"github.com/whatsapp-clone/backend/pkg/middleware"
@@ -102,9 +104,16 @@ func main() {
 	}
 	defer chatConn.Close()
 
+	userConn, err := grpc.NewClient(cfg.UserGRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatal().Err(err).Str("addr", cfg.UserGRPCAddr).Msg("failed to create user gRPC client")
+	}
+	defer userConn.Close()
+
 	authClient := authv1.NewAuthServiceClient(authConn)
 	messageClient := messagev1.NewMessageServiceClient(msgConn)
 	chatClient := chatv1.NewChatServiceClient(chatConn)
+	userClient := userv1.NewUserServiceClient(userConn)
 
 	log.Info().Msg("gRPC connections established")
 
@@ -113,11 +122,20 @@ func main() {
 
 	wsSvc := service.NewWebSocketService(
 		hub, rdb, nc, js,
-		messageClient, chatClient,
+		messageClient, chatClient, userClient,
 		&cfg, log,
 	)
 
-	authVal := &grpcAuthValidator{client: authClient}
+	// Tokens are validated locally against auth-service's JWKS; without a
+	// JWKS URL every connection is checked over gRPC.
+	var authVal service.AuthValidator = &grpcAuthValidator{client: authClient}
+	if cfg.JWKSURL != "" {
+		jwks := jwt.NewJWKSValidator(cfg.JWKSURL, cfg.JWKSRefresh)
+		if err := jwks.Refresh(ctx); err != nil {
+			log.Warn().Err(err).Msg("failed to fetch JWKS, will retry on first connection")
+		}
+		authVal = jwt.NewLocalValidator(jwks, redisRevocations(rdb))
+	}
 
 	// Start NATS consumers in background.
 	if err := wsSvc.StartNATSConsumers(context.Background()); err != nil {
@@ -201,15 +219,24 @@ type grpcAuthValidator struct {
 	client authv1.AuthServiceClient
 }
 
-func (v *grpcAuthValidator) ValidateToken(ctx context.Context, token string) (string, string, error) {
+func (v *grpcAuthValidator) ValidateToken(ctx context.Context, token string) (string, string, string, error) {
 	resp, err := v.client.ValidateToken(ctx, &authv1.ValidateTokenRequest{Token: token})
 	if err != nil {
-		return "", "", fmt.Errorf("auth gRPC error: %w", err)
+		return "", "", "", fmt.Errorf("auth gRPC error: %w", err)
 	}
 	if !resp.Valid {
-		return "", "", fmt.Errorf("invalid token")
+		return "", "", "", fmt.Errorf("invalid token")
 	}
-	return resp.UserId, resp.Phone, nil
+	return resp.UserId, resp.Phone, resp.DeviceId, nil
+}
+
+// redisRevocations looks devices up in the revocation markers auth-service
+// keeps in the shared Redis.
+func redisRevocations(rdb *redis.Client) jwt.RevocationChecker {
+	return jwt.RevocationCheckerFunc(func(ctx context.Context, deviceID string) (bool, error) {
+		n, err := rdb.Exists(ctx, jwt.RevokedDeviceKey(deviceID)).Result()
+		return n > 0, err
+	})
 }
 
 func errString(err error) string {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -70,6 +71,7 @@ func NewWSHandler(
 func (h *WSHandler) ServeWS(w http.ResponseWriter, r *http.Request) {
 	userID := r.Header.Get("X-User-ID")
 	phone := r.Header.Get("X-User-Phone")
+	authDeviceID := r.Header.Get("X-Device-ID")
 
 	// If no identity headers from api-gateway, fall back to token validation.
 	if userID == "" {
@@ -87,7 +89,7 @@ func (h *WSHandler) ServeWS(w http.ResponseWriter, r *http.Request) {
 		}
 
 		var err error
-		userID, phone, err = h.authVal.ValidateToken(r.Context(), token)
+		userID, phone, authDeviceID, err = h.authVal.ValidateToken(r.Context(), token)
 		if err != nil {
 			h.log.Warn().Err(err).Msg("token validation failed")
 			http.Error(w, "unauthorized", http.StatusUnauthorized)
@@ -95,6 +97,31 @@ func (h *WSHandler) ServeWS(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Resume cursor: an explicit last_seq wins; otherwise fall back to the
+	// cursor the server tracked for this device on its previous connection.
+	// Clients that do not name a device are tracked by their linked device.
+	deviceID := r.URL.Query().Get("device_id")
+	if deviceID == "" {
+		deviceID = authDeviceID
+	}
+	var lastSeq uint64
+	resume := false
+	if v := r.URL.Query().Get("last_seq"); v != "" {
+		seq, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid last_seq", http.StatusBadRequest)
+			return
+		}
+		lastSeq, resume = seq, true
+	} else if deviceID != "" {
+		seq, err := h.wsSvc.LoadSyncCursor(r.Context(), userID, deviceID)
+		if err != nil {
+			h.log.Warn().Err(err).Str("user_id", userID).Msg("failed to load sync cursor")
+		} else if seq > 0 {
+			lastSeq, resume = seq, true
+		}
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		h.log.Error().Err(err).Msg("websocket upgrade failed")
@@ -102,15 +129,22 @@ func (h *WSHandler) ServeWS(w http.ResponseWriter, r *http.Request) {
 	}
 
 	client := &model.Client{
-		Conn:     conn,
-		UserID:   userID,
-		Phone:    phone,
-		Send:     make(chan []byte, 256),
-		JoinedAt: time.Now(),
+		Conn:         conn,
+		UserID:       userID,
+		Phone:        phone,
+		DeviceID:     deviceID,
+		AuthDeviceID: authDeviceID,
+		Send:         make(chan []byte, 256),
+		JoinedAt:     time.Now(),
+	}
+	// Enter replay mode before subscribing so live events published while
+	// the backlog is replayed are held back and delivered after it.
+	if resume {
+		client.BeginReplay(lastSeq)
 	}
 
 	h.hub.Register(client)
-	h.log.Info().Str("user_id", userID).Msg("client connected")
+	h.log.Info().Str("user_id", userID).Str("device_id", deviceID).Msg("client connected")
 
 	ctx := context.Background()
 	_ = h.wsSvc.SetPresence(ctx, userID, true)
@@ -119,6 +153,14 @@ func (h *WSHandler) ServeWS(w http.ResponseWriter, r *http.Request) {
 
 	go h.writePump(client)
 	go h.readPump(client)
+
+	if resume {
+		go func() {
+			if err := h.wsSvc.ResumeSession(ctx, client, lastSeq); err != nil {
+				h.log.Warn().Err(err).Str("user_id", userID).Uint64("last_seq", lastSeq).Msg("offline replay failed")
+			}
+		}()
+	}
 }
 
 // readPump reads messages from the WebSocket connection and routes them to the service layer.
@@ -133,8 +175,12 @@ func (h *WSHandler) readPump(client *model.Client) {
 			h.wsSvc.CleanupPresenceSubscriptions(client.UserID)
 		}
 
+		if err := h.wsSvc.SaveSyncCursor(context.Background(), client); err != nil {
+			h.log.Warn().Err(err).Str("user_id", client.UserID).Msg("failed to save sync cursor")
+		}
+
 		client.Conn.Close()
-		close(client.Send)
+		client.Close()
 		h.log.Info().Str("user_id", client.UserID).Msg("client disconnected")
 	}()
 
@@ -187,7 +233,11 @@ func (h *WSHandler) writePump(client *model.Client) {
 			if err := client.Conn.WriteMessage(websocket.TextMessage, message); err != nil {
 				return
 			}
+			if seq := model.FrameSeq(message); seq > 0 {
+				client.MarkWritten(seq)
+			}
 		case <-ticker.C:
+			_ = h.wsSvc.SaveSyncCursor(context.Background(), client)
 			_ = client.Conn.SetWriteDeadline(time.Now().Add(h.cfg.WriteTimeout))
 			if err := client.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
@@ -201,8 +251,5 @@ func (h *WSHandler) sendError(client *model.Client, msg string) {
 	event := model.WSEvent{Type: "error"}
 	event.Payload, _ = json.Marshal(map[string]string{"message": msg})
 	data, _ := json.Marshal(event)
-	select {
-	case client.Send <- data:
-	default:
-	}
+	client.Enqueue(0, data)
 }
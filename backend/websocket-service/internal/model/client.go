@@ -2,18 +2,209 @@ package model
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
 )
 
+// CloseDeviceRevoked is the close code sent to a connection whose linked
+// device was logged out.
+const CloseDeviceRevoked = 4001
+
+// CloseResyncRequired is the close code sent to a connection that fell too
+// far behind to be sent a sequenced frame. Its cursor stops before the
+// frame, so reconnecting replays it.
+const CloseResyncRequired = 4002
+
 // Client represents a single WebSocket connection.
 type Client struct {
-	Conn     *websocket.Conn
-	UserID   string
-	Phone    string
-	Send     chan []byte
-	JoinedAt time.Time
+	Conn   *websocket.Conn
+	UserID string
+	Phone  string
+	// DeviceID keys the sync cursor. It is the device_id the client
+	// connected with, defaulting to AuthDeviceID.
+	DeviceID string
+	// AuthDeviceID is the linked device the connection's token was issued
+	// to; empty for tokens not bound to a device.
+	AuthDeviceID string
+	Send         chan []byte
+	JoinedAt     time.Time
+
+	// Sync state for offline replay. Sequenced frames (seq > 0) come from
+	// the per-user event log; unsequenced frames (typing, presence, calls)
+	// are ephemeral and always delivered immediately.
+	syncMu    sync.Mutex
+	closed    bool
+	stalled   bool
+	replaying bool
+	replayEnd uint64
+	pending   []sequencedFrame
+	written   atomic.Uint64
+}
+
+type sequencedFrame struct {
+	seq  uint64
+	data []byte
+}
+
+// Enqueue queues a frame for the write pump without blocking. While a replay
+// is in progress, sequenced live frames are held back so they are delivered
+// after the replayed backlog. Frames already covered by the replay are
+// dropped. Returns false if the frame could not be queued.
+//
+// A sequenced frame that does not fit stalls the client: no later sequenced
+// frame is queued either, so the sync cursor never moves past the missing
+// one. The caller must then disconnect the client with CloseResyncRequired
+// so that it reconnects and has the frame replayed.
+func (c *Client) Enqueue(seq uint64, data []byte) bool {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+
+	if c.closed || (seq != 0 && c.stalled) {
+		return false
+	}
+	if seq != 0 && seq <= c.replayEnd {
+		return true
+	}
+	if seq != 0 && c.replaying {
+		c.pending = append(c.pending, sequencedFrame{seq: seq, data: data})
+		return true
+	}
+	select {
+	case c.Send <- data:
+		return true
+	default:
+		if seq != 0 {
+			c.stalled = true
+		}
+		return false
+	}
+}
+
+// Stall stops the client taking sequenced frames and drops the ones held
+// back, e.g. because a replay failed part way and later frames would move
+// the cursor past the part not replayed.
+func (c *Client) Stall() {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	c.stalled = true
+	c.pending = nil
+	c.replaying = false
+}
+
+// Stalled reports whether a sequenced frame could not be queued, so the
+// client has to reconnect to resync.
+func (c *Client) Stalled() bool {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	return c.stalled
+}
+
+// BeginReplay switches the client into replay mode. Every sequenced frame at
+// or below afterSeq is treated as already delivered.
+func (c *Client) BeginReplay(afterSeq uint64) {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	c.replaying = true
+	c.replayEnd = afterSeq
+}
+
+// EnqueueReplay queues a replayed frame, waiting up to timeout for room in
+// the send buffer. Returns false if the client closed or the buffer stayed full.
+func (c *Client) EnqueueReplay(seq uint64, data []byte, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		c.syncMu.Lock()
+		if c.closed {
+			c.syncMu.Unlock()
+			return false
+		}
+		select {
+		case c.Send <- data:
+			if seq > c.replayEnd {
+				c.replayEnd = seq
+			}
+			c.syncMu.Unlock()
+			return true
+		default:
+		}
+		c.syncMu.Unlock()
+
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// EndReplay leaves replay mode and flushes live frames that arrived during
+// the replay and were not part of the replayed backlog, waiting up to timeout
+// for room in the send buffer. Live frames keep waiting behind the flush. If
+// the buffer stays full the client is stalled and false is returned, as for
+// Enqueue.
+func (c *Client) EndReplay(timeout time.Duration) bool {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+
+	pending := c.pending
+	c.pending = nil
+	c.replaying = false
+	if c.closed {
+		return false
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for _, f := range pending {
+		if f.seq <= c.replayEnd {
+			continue
+		}
+		select {
+		case c.Send <- f.data:
+		case <-timer.C:
+			c.stalled = true
+			return false
+		}
+	}
+	return true
+}
+
+// MarkWritten records that the frame with the given sequence was written to
+// the socket. The highest written sequence is the device's sync cursor.
+func (c *Client) MarkWritten(seq uint64) {
+	for {
+		cur := c.written.Load()
+		if seq <= cur || c.written.CompareAndSwap(cur, seq) {
+			return
+		}
+	}
+}
+
+// WrittenSeq returns the highest sequence written to the socket.
+func (c *Client) WrittenSeq() uint64 {
+	return c.written.Load()
+}
+
+// Disconnect sends a close frame and closes the connection. The read pump
+// then fails and runs the normal disconnect cleanup. Safe to call
+// concurrently with the pumps.
+func (c *Client) Disconnect(code int, reason string) {
+	msg := websocket.FormatCloseMessage(code, reason)
+	_ = c.Conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	_ = c.Conn.Close()
+}
+
+// Close marks the client closed and closes its send channel. Safe to call
+// concurrently with Enqueue.
+func (c *Client) Close() {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	close(c.Send)
 }
 
 // Hub maintains the set of active clients and routes messages.
@@ -1,11 +1,50 @@
 package model
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
 
 // WSEvent represents a WebSocket message envelope (both client->server and server->client).
+// Seq is set on server->client events that are recorded in the per-user event
+// log; clients pass the highest seq they have seen as last_seq on reconnect.
+// It is the event log's stream sequence, shared by all users, so a user's
+// seqs increase but skip values: a gap is not a missed event.
 type WSEvent struct {
 	Type    string          `json:"event"`
 	Payload json.RawMessage `json:"data"`
+	Seq     uint64          `json:"seq,omitempty"`
+}
+
+// FrameSeq extracts the event log sequence from an encoded WSEvent, or 0 if
+// the frame is not sequenced.
+func FrameSeq(data []byte) uint64 {
+	var f struct {
+		Seq uint64 `json:"seq"`
+	}
+	if json.Unmarshal(data, &f) != nil {
+		return 0
+	}
+	return f.Seq
+}
+
+// RevokedDevice returns the device ID of a device.revoked frame, or "" for
+// any other frame.
+func RevokedDevice(data []byte) string {
+	if !bytes.Contains(data, []byte(`"device.revoked"`)) {
+		return ""
+	}
+	var f struct {
+		Event string `json:"event"`
+		Data  struct {
+			DeviceID string `json:"device_id"`
+		} `json:"data"`
+	}
+	if json.Unmarshal(data, &f) != nil || f.Event != "device.revoked" {
+		return ""
+	}
+	return f.Data.DeviceID
 }
 
 // --- Client -> Server event payloads ---
@@ -18,12 +57,87 @@ type MessageSendPayload struct {
 	ReplyToMessageID string         `json:"reply_to_message_id,omitempty"`
 }
 
+// MessageContent mirrors message-service's payload. Poll is passed through
+// as-is for poll messages.
 type MessageContent struct {
-	Body       string `json:"body,omitempty"`
-	MediaID    string `json:"media_id,omitempty"`
-	Caption    string `json:"caption,omitempty"`
-	Filename   string `json:"filename,omitempty"`
-	DurationMs int64  `json:"duration_ms,omitempty"`
+	Body       string          `json:"body,omitempty"`
+	MediaID    string          `json:"media_id,omitempty"`
+	Caption    string          `json:"caption,omitempty"`
+	Filename   string          `json:"filename,omitempty"`
+	DurationMs int64           `json:"duration_ms,omitempty"`
+	Poll       json.RawMessage `json:"poll,omitempty"`
+	Location   *Location       `json:"location,omitempty"`
+	Contacts   []Contact       `json:"contacts,omitempty"`
+	Encrypted  *Encrypted      `json:"encrypted,omitempty"`
+	// GroupInvite is relayed as sent; only chat-service sends group_invite
+	// messages.
+	GroupInvite json.RawMessage `json:"group_invite,omitempty"`
+}
+
+// Encrypted is an end-to-end encrypted message: one ciphertext per recipient
+// device. Each user is only delivered the envelopes for their own devices.
+type Encrypted struct {
+	SenderDeviceID string           `json:"sender_device_id"`
+	Envelopes      []CipherEnvelope `json:"envelopes"`
+}
+
+type CipherEnvelope struct {
+	UserID     string `json:"user_id"`
+	DeviceID   string `json:"device_id"`
+	Type       string `json:"type"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// ViewFor returns the payload with only userID's envelopes.
+func (e *Encrypted) ViewFor(userID string) *Encrypted {
+	view := &Encrypted{SenderDeviceID: e.SenderDeviceID, Envelopes: []CipherEnvelope{}}
+	for _, env := range e.Envelopes {
+		if env.UserID == userID {
+			view.Envelopes = append(view.Envelopes, env)
+		}
+	}
+	return view
+}
+
+// Contact is one card of a contact message. When sending, either VCard or the
+// structured fields are set; delivered cards carry both, with UserID set on
+// phone numbers that belong to registered users.
+type Contact struct {
+	Name         string         `json:"name,omitempty"`
+	Organization string         `json:"organization,omitempty"`
+	Phones       []ContactPhone `json:"phones,omitempty"`
+	Emails       []string       `json:"emails,omitempty"`
+	UserID       string         `json:"user_id,omitempty"`
+	VCard        string         `json:"vcard,omitempty"`
+}
+
+type ContactPhone struct {
+	Number string `json:"number"`
+	Type   string `json:"type,omitempty"`
+	UserID string `json:"user_id,omitempty"`
+}
+
+// Location is a location message's payload. LiveDurationSeconds starts a
+// live share when sending; LiveUntil/StoppedAt describe one that was started.
+type Location struct {
+	Latitude            float64    `json:"latitude"`
+	Longitude           float64    `json:"longitude"`
+	AccuracyM           float64    `json:"accuracy_m,omitempty"`
+	Name                string     `json:"name,omitempty"`
+	Address             string     `json:"address,omitempty"`
+	LiveDurationSeconds int64      `json:"live_duration_seconds,omitempty"`
+	LiveUntil           *time.Time `json:"live_until,omitempty"`
+	StoppedAt           *time.Time `json:"stopped_at,omitempty"`
+	UpdatedAt           *time.Time `json:"updated_at,omitempty"`
+}
+
+// LocationUpdatePayload is a new position for the sender's live location
+// share identified by MessageID.
+type LocationUpdatePayload struct {
+	MessageID string  `json:"message_id"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	AccuracyM float64 `json:"accuracy_m,omitempty"`
 }
 
 type MessageStatusPayload struct {
@@ -39,6 +153,12 @@ type MessageDeletePayload struct {
 	ForEveryone bool   `json:"for_everyone"`
 }
 
+type MessageEditPayload struct {
+	MessageID string `json:"message_id"`
+	Body      string `json:"body,omitempty"`
+	Caption   string `json:"caption,omitempty"`
+}
+
 type TypingPayload struct {
 	ChatID string `json:"chat_id"`
 }
@@ -57,13 +177,84 @@ type MessageReactionPayload struct {
 	Removed   bool   `json:"removed"`
 }
 
+// MessageNewPayload carries the per-chat Seq assigned by message-service,
+// which clients use to detect holes in a chat's history. ThreadID is set on
+// replies to the thread's root message.
 type MessageNewPayload struct {
+	MessageID        string         `json:"message_id"`
+	ChatID           string         `json:"chat_id"`
+	Seq              int64          `json:"seq"`
+	SenderID         string         `json:"sender_id"`
+	Type             string         `json:"type"`
+	ReplyToMessageID string         `json:"reply_to_message_id,omitempty"`
+	ThreadID         string         `json:"thread_id,omitempty"`
+	Payload          MessageContent `json:"payload"`
+	CreatedAt        int64          `json:"created_at"`
+}
+
+type MessageEditedPayload struct {
 	MessageID string         `json:"message_id"`
 	ChatID    string         `json:"chat_id"`
+	Seq       int64          `json:"seq"`
 	SenderID  string         `json:"sender_id"`
-	Type      string         `json:"type"`
 	Payload   MessageContent `json:"payload"`
-	CreatedAt int64          `json:"created_at"`
+	EditedAt  int64          `json:"edited_at"`
+}
+
+// MessageExpiredPayload lists messages in a chat that were removed by the
+// chat's disappearing messages timer.
+type MessageExpiredPayload struct {
+	ChatID     string   `json:"chat_id"`
+	MessageIDs []string `json:"message_ids"`
+}
+
+// ThreadUpdatedPayload is a thread root's reply count after a reply was
+// added, deleted or expired.
+type ThreadUpdatedPayload struct {
+	MessageID   string `json:"message_id"`
+	ChatID      string `json:"chat_id"`
+	ReplyCount  int64  `json:"reply_count"`
+	LastReplyAt int64  `json:"last_reply_at,omitempty"`
+}
+
+type PollTally struct {
+	ID        string `json:"id"`
+	VoteCount int64  `json:"vote_count"`
+}
+
+// PollUpdatedPayload carries a poll's tallies after a vote (poll.voted) or
+// when it was closed (poll.closed). UserID and OptionIDs are omitted for
+// anonymous polls.
+type PollUpdatedPayload struct {
+	MessageID   string      `json:"message_id"`
+	ChatID      string      `json:"chat_id"`
+	UserID      string      `json:"user_id,omitempty"`
+	OptionIDs   []string    `json:"option_ids,omitempty"`
+	Options     []PollTally `json:"options"`
+	TotalVoters int         `json:"total_voters"`
+	ClosedAt    int64       `json:"closed_at,omitempty"`
+}
+
+// LiveLocationPayload is a live location share's latest position.
+type LiveLocationPayload struct {
+	MessageID string  `json:"message_id"`
+	ChatID    string  `json:"chat_id"`
+	SenderID  string  `json:"sender_id"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	AccuracyM float64 `json:"accuracy_m,omitempty"`
+	UpdatedAt int64   `json:"updated_at"`
+	LiveUntil int64   `json:"live_until"`
+}
+
+// LocationStoppedPayload reports that a live location share ended, either
+// because the sender stopped it or its duration ran out ("expired").
+type LocationStoppedPayload struct {
+	MessageID string `json:"message_id"`
+	ChatID    string `json:"chat_id"`
+	SenderID  string `json:"sender_id"`
+	Reason    string `json:"reason"`
+	StoppedAt int64  `json:"stopped_at"`
 }
 
 type MessageSentAckPayload struct {
@@ -75,6 +266,19 @@ type MessageSentAckPayload struct {
 type PresenceEventPayload struct {
 	UserID string `json:"user_id"`
 	Online bool   `json:"online"`
+	// LastSeen is an RFC 3339 time, set only when offline and the user
+	// shares their last seen with the recipient.
+	LastSeen string `json:"last_seen,omitempty"`
+}
+
+// SyncCompletePayload is sent after the offline backlog has been replayed.
+// Truncated is true when the requested cursor is older than the retained log,
+// in which case the client should re-fetch history over REST.
+type SyncCompletePayload struct {
+	FromSeq   uint64 `json:"from_seq"`
+	LastSeq   uint64 `json:"last_seq"`
+	Replayed  int    `json:"replayed"`
+	Truncated bool   `json:"truncated"`
 }
 
 // --- Call signaling payloads ---
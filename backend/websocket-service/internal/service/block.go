@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
+)
+
+// blockedAmong returns those of otherIDs with a block in either direction
+// with userID. A failed lookup is an error, so a user-service outage cannot
+// let a blocked user's calls or typing through.
+func (s *wsServiceImpl) blockedAmong(ctx context.Context, userID string, otherIDs []string) (map[string]struct{}, error) {
+	blocked := make(map[string]struct{})
+	if len(otherIDs) == 0 {
+		return blocked, nil
+	}
+
+	resp, err := s.userClient.GetBlockRelations(ctx, &userv1.GetBlockRelationsRequest{
+		UserId:       userID,
+		OtherUserIds: otherIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("check blocks: %w", err)
+	}
+	for _, r := range resp.Relations {
+		if r.Blocking || r.BlockedBy {
+			blocked[r.UserId] = struct{}{}
+		}
+	}
+	return blocked, nil
+}
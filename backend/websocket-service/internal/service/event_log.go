@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/redis/go-redis/v9"
+	"github.com/whatsapp-clone/backend/websocket-service/internal/model"
+)
+
+const (
+	// eventLogStream is the JetStream stream holding every durable
+	// server->client event, one subject per recipient user.
+	eventLogStream        = "USER_EVENTS"
+	eventLogSubjectPrefix = "user.events."
+)
+
+// ensureEventLog creates the per-user event log stream, or updates its
+// retention if the configured value changed.
+func (s *wsServiceImpl) ensureEventLog() error {
+	cfg := &nats.StreamConfig{
+		Name:     eventLogStream,
+		Subjects: []string{eventLogSubjectPrefix + ">"},
+		Storage:  nats.FileStorage,
+		MaxAge:   s.cfg.EventLogRetention,
+	}
+
+	info, _ := s.js.StreamInfo(eventLogStream)
+	if info != nil {
+		if info.Config.MaxAge == cfg.MaxAge {
+			return nil
+		}
+		_, err := s.js.UpdateStream(cfg)
+		return err
+	}
+	if _, err := s.js.AddStream(cfg); err != nil {
+		return err
+	}
+	s.log.Info().Str("stream", eventLogStream).Dur("retention", cfg.MaxAge).Msg("created JetStream stream")
+	return nil
+}
+
+// publishToUser appends an event to the user's event log and fans it out on
+// the user's Redis channel tagged with the assigned sequence. If the append
+// fails the event is still delivered live, just without a sequence.
+func (s *wsServiceImpl) publishToUser(ctx context.Context, userID string, event model.WSEvent) {
+	event.Seq = 0
+	data, _ := json.Marshal(event)
+
+	ack, err := s.js.Publish(eventLogSubjectPrefix+userID, data)
+	if err != nil {
+		s.log.Error().Err(err).Str("user_id", userID).Str("event", event.Type).Msg("event log append failed")
+	} else {
+		event.Seq = ack.Sequence
+		data, _ = json.Marshal(event)
+	}
+
+	s.rdb.Publish(ctx, "user:channel:"+userID, data)
+}
+
+// ResumeSession replays every logged event for the client's user with a
+// sequence greater than afterSeq, in order, then sends sync.complete and
+// switches the client to live delivery. If the replay fails, or the held
+// back live events cannot be flushed, the client is disconnected with
+// CloseResyncRequired so that it resumes from where delivery stopped.
+func (s *wsServiceImpl) ResumeSession(ctx context.Context, client *model.Client, afterSeq uint64) (err error) {
+	defer func() {
+		if err == nil && client.EndReplay(s.cfg.WriteTimeout) {
+			return
+		}
+		if err != nil {
+			client.Stall()
+		}
+		if client.Stalled() {
+			s.log.Warn().Err(err).Str("user_id", client.UserID).Msg("offline replay incomplete, closing connection to resync")
+			client.Disconnect(model.CloseResyncRequired, "reconnect to resync")
+		}
+	}()
+
+	subject := eventLogSubjectPrefix + client.UserID
+	summary := model.SyncCompletePayload{FromSeq: afterSeq, LastSeq: afterSeq}
+
+	info, err := s.js.StreamInfo(eventLogStream)
+	if err != nil {
+		return fmt.Errorf("event log info: %w", err)
+	}
+	if afterSeq > info.State.LastSeq {
+		// The cursor is ahead of the log (e.g. the stream was recreated), so
+		// it cannot be trusted; stop suppressing live frames at or below it.
+		client.BeginReplay(0)
+		summary.FromSeq, summary.LastSeq = 0, 0
+		summary.Truncated = true
+		afterSeq = 0
+	}
+
+	last, err := s.js.GetLastMsg(eventLogStream, subject)
+	if err != nil && !errors.Is(err, nats.ErrMsgNotFound) {
+		return fmt.Errorf("event log last msg: %w", err)
+	}
+
+	if last != nil && last.Sequence > afterSeq {
+		summary.Truncated = summary.Truncated || afterSeq+1 < info.State.FirstSeq
+
+		sub, err := s.js.SubscribeSync(subject, nats.OrderedConsumer(), nats.StartSequence(afterSeq+1))
+		if err != nil {
+			return fmt.Errorf("event log subscribe: %w", err)
+		}
+		defer sub.Unsubscribe()
+
+		for {
+			m, err := sub.NextMsg(s.cfg.WriteTimeout)
+			if err != nil {
+				return fmt.Errorf("event log read: %w", err)
+			}
+			meta, err := m.Metadata()
+			if err != nil {
+				return fmt.Errorf("event log metadata: %w", err)
+			}
+
+			var event model.WSEvent
+			if err := json.Unmarshal(m.Data, &event); err != nil {
+				s.log.Error().Err(err).Uint64("seq", meta.Sequence.Stream).Msg("skipping malformed event log entry")
+			} else {
+				event.Seq = meta.Sequence.Stream
+				data, _ := json.Marshal(event)
+				if !client.EnqueueReplay(event.Seq, data, s.cfg.WriteTimeout) {
+					return fmt.Errorf("replay aborted at seq %d: client not draining", event.Seq)
+				}
+				summary.Replayed++
+			}
+
+			if meta.Sequence.Stream >= last.Sequence {
+				break
+			}
+		}
+		summary.LastSeq = last.Sequence
+	}
+
+	done := model.WSEvent{Type: "sync.complete"}
+	done.Payload, _ = json.Marshal(summary)
+	data, _ := json.Marshal(done)
+	client.EnqueueReplay(0, data, s.cfg.WriteTimeout)
+
+	s.log.Debug().
+		Str("user_id", client.UserID).
+		Str("device_id", client.DeviceID).
+		Uint64("from_seq", afterSeq).
+		Int("replayed", summary.Replayed).
+		Msg("offline replay complete")
+	return nil
+}
+
+func syncCursorKey(userID, deviceID string) string {
+	return "sync_cursor:" + userID + ":" + deviceID
+}
+
+// LoadSyncCursor returns the last sequence written to the given device, or 0 if unknown.
+func (s *wsServiceImpl) LoadSyncCursor(ctx context.Context, userID, deviceID string) (uint64, error) {
+	seq, err := s.rdb.Get(ctx, syncCursorKey(userID, deviceID)).Uint64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("load sync cursor: %w", err)
+	}
+	return seq, nil
+}
+
+// SaveSyncCursor persists the highest sequence written to the client's device.
+func (s *wsServiceImpl) SaveSyncCursor(ctx context.Context, client *model.Client) error {
+	seq := client.WrittenSeq()
+	if client.DeviceID == "" || seq == 0 {
+		return nil
+	}
+	return s.rdb.Set(ctx, syncCursorKey(client.UserID, client.DeviceID), seq, s.cfg.EventLogRetention).Err()
+}
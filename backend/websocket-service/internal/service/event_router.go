@@ -7,6 +7,7 @@ import (
 	"time"
 
 	messagev1 "github.com/whatsapp-clone/backend/proto/message/v1"
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
 	"github.com/whatsapp-clone/backend/websocket-service/internal/model"
 )
 
@@ -19,8 +20,12 @@ func (s *wsServiceImpl) HandleEvent(ctx context.Context, client *model.Client, e
 		return s.handleMessageStatus(ctx, client, event.Payload, "delivered")
 	case "message.read":
 		return s.handleMessageStatus(ctx, client, event.Payload, "read")
+	case "message.edit":
+		return s.handleMessageEdit(ctx, client, event.Payload)
 	case "message.delete":
 		return s.handleMessageDelete(ctx, client, event.Payload)
+	case "location.update":
+		return s.handleLocationUpdate(ctx, client, event.Payload)
 	case "typing.start":
 		return s.handleTyping(ctx, client, event.Payload, true)
 	case "typing.stop":
@@ -60,6 +65,9 @@ func (s *wsServiceImpl) handleMessageSend(ctx context.Context, client *model.Cli
 			Caption:    p.Payload.Caption,
 			Filename:   p.Payload.Filename,
 			DurationMs: p.Payload.DurationMs,
+			Location:   toProtoLocation(p.Payload.Location),
+			Contacts:   toProtoContacts(p.Payload.Contacts),
+			Encrypted:  toProtoEncrypted(p.Payload.Encrypted),
 		},
 	})
 	if err != nil {
@@ -81,34 +89,11 @@ func (s *wsServiceImpl) handleMessageStatus(ctx context.Context, client *model.C
 		return fmt.Errorf("invalid status payload: %w", err)
 	}
 
-	// Fast path: push the status update directly to the original message
-	// sender's WebSocket connection to avoid the gRPC -> NATS -> Redis
-	// round-trip. If sender_id is provided, target only that user;
-	// otherwise fall back to all other participants (for backwards compat).
-	if p.ChatID != "" {
-		statusEvent := model.WSEvent{Type: "message.status"}
-		statusEvent.Payload, _ = json.Marshal(map[string]string{
-			"message_id": p.MessageID,
-			"chat_id":    p.ChatID,
-			"user_id":    client.UserID,
-			"status":     statusVal,
-		})
-		data, _ := json.Marshal(statusEvent)
-
-		if p.SenderID != "" && p.SenderID != client.UserID {
-			s.rdb.Publish(ctx, "user:channel:"+p.SenderID, data)
-		} else {
-			participants := s.getChatParticipants(ctx, p.ChatID)
-			for _, uid := range participants {
-				if uid == client.UserID {
-					continue
-				}
-				s.rdb.Publish(ctx, "user:channel:"+uid, data)
-			}
-		}
-	}
-
-	// Async persistence via gRPC to message-service (runs in background)
+	// The status goes out only through message-service, which publishes
+	// msg.status.updated once the user's watermark actually moves, so every
+	// member sees it exactly once. It reports a read as delivered if read
+	// receipts are off, and a watermark covers every earlier message in the
+	// chat too. The call runs in the background.
 	go func() {
 		bgCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
@@ -128,6 +113,27 @@ func (s *wsServiceImpl) handleMessageStatus(ctx context.Context, client *model.C
 	return nil
 }
 
+// handleMessageEdit forwards the edit to message-service. The resulting
+// msg.edited event delivers message.edited to every participant, including
+// the sender's other devices.
+func (s *wsServiceImpl) handleMessageEdit(ctx context.Context, client *model.Client, payload json.RawMessage) error {
+	var p model.MessageEditPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("invalid message.edit payload: %w", err)
+	}
+
+	_, err := s.messageClient.EditMessage(ctx, &messagev1.EditMessageRequest{
+		MessageId: p.MessageID,
+		UserId:    client.UserID,
+		Body:      p.Body,
+		Caption:   p.Caption,
+	})
+	if err != nil {
+		return fmt.Errorf("message-service EditMessage failed: %w", err)
+	}
+	return nil
+}
+
 func (s *wsServiceImpl) handleMessageDelete(ctx context.Context, client *model.Client, payload json.RawMessage) error {
 	var p model.MessageDeletePayload
 	if err := json.Unmarshal(payload, &p); err != nil {
@@ -147,12 +153,121 @@ func (s *wsServiceImpl) handleMessageDelete(ctx context.Context, client *model.C
 	return nil
 }
 
+func toProtoLocation(l *model.Location) *messagev1.Location {
+	if l == nil {
+		return nil
+	}
+	return &messagev1.Location{
+		Latitude:            l.Latitude,
+		Longitude:           l.Longitude,
+		AccuracyM:           l.AccuracyM,
+		Name:                l.Name,
+		Address:             l.Address,
+		LiveDurationSeconds: l.LiveDurationSeconds,
+	}
+}
+
+func toProtoContacts(contacts []model.Contact) []*messagev1.Contact {
+	var out []*messagev1.Contact
+	for _, c := range contacts {
+		pc := &messagev1.Contact{
+			Name:         c.Name,
+			Organization: c.Organization,
+			Emails:       c.Emails,
+			Vcard:        c.VCard,
+		}
+		for _, p := range c.Phones {
+			pc.Phones = append(pc.Phones, &messagev1.ContactPhone{Number: p.Number, Type: p.Type})
+		}
+		out = append(out, pc)
+	}
+	return out
+}
+
+func toProtoEncrypted(e *model.Encrypted) *messagev1.EncryptedPayload {
+	if e == nil {
+		return nil
+	}
+	pe := &messagev1.EncryptedPayload{SenderDeviceId: e.SenderDeviceID}
+	for _, env := range e.Envelopes {
+		pe.Envelopes = append(pe.Envelopes, &messagev1.CipherEnvelope{
+			UserId:     env.UserID,
+			DeviceId:   env.DeviceID,
+			Type:       env.Type,
+			Ciphertext: env.Ciphertext,
+		})
+	}
+	return pe
+}
+
+// handleLocationUpdate stores a new live location position via message-service
+// and fans it out to the other participants. Positions are transient, so like
+// typing they go straight over Redis rather than through the durable event log.
+func (s *wsServiceImpl) handleLocationUpdate(ctx context.Context, client *model.Client, payload json.RawMessage) error {
+	var p model.LocationUpdatePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("invalid location.update payload: %w", err)
+	}
+
+	resp, err := s.messageClient.UpdateLiveLocation(ctx, &messagev1.UpdateLiveLocationRequest{
+		MessageId: p.MessageID,
+		UserId:    client.UserID,
+		Latitude:  p.Latitude,
+		Longitude: p.Longitude,
+		AccuracyM: p.AccuracyM,
+	})
+	if err != nil {
+		return fmt.Errorf("message-service UpdateLiveLocation failed: %w", err)
+	}
+
+	event := model.WSEvent{Type: "location.updated"}
+	event.Payload, _ = json.Marshal(model.LiveLocationPayload{
+		MessageID: resp.MessageId,
+		ChatID:    resp.ChatId,
+		SenderID:  client.UserID,
+		Latitude:  p.Latitude,
+		Longitude: p.Longitude,
+		AccuracyM: p.AccuracyM,
+		UpdatedAt: resp.UpdatedAt.AsTime().UnixMilli(),
+		LiveUntil: resp.LiveUntil.AsTime().UnixMilli(),
+	})
+	data, _ := json.Marshal(event)
+
+	participants := s.getChatParticipants(ctx, resp.ChatId)
+	for _, uid := range participants {
+		if uid == client.UserID {
+			continue
+		}
+		s.rdb.Publish(ctx, "user:channel:"+uid, data)
+	}
+	return nil
+}
+
 func (s *wsServiceImpl) handleTyping(ctx context.Context, client *model.Client, payload json.RawMessage, start bool) error {
 	var p model.TypingPayload
 	if err := json.Unmarshal(payload, &p); err != nil {
 		return fmt.Errorf("invalid typing payload: %w", err)
 	}
 
+	// Typing only goes to members with no block either way with the typist.
+	participants := s.getChatParticipants(ctx, p.ChatID)
+	var others []string
+	member := false
+	for _, uid := range participants {
+		if uid == client.UserID {
+			member = true
+			continue
+		}
+		others = append(others, uid)
+	}
+	if !member {
+		return fmt.Errorf("not a member of this chat")
+	}
+	blocked, err := s.blockedAmong(ctx, client.UserID, others)
+	if err != nil {
+		return err
+	}
+
 	key := fmt.Sprintf("typing:%s:%s", p.ChatID, client.UserID)
 	if start {
 		s.rdb.SetEx(ctx, key, "1", s.cfg.TypingTTL)
@@ -168,9 +283,8 @@ func (s *wsServiceImpl) handleTyping(ctx context.Context, client *model.Client,
 	})
 	data, _ := json.Marshal(event)
 
-	participants := s.getChatParticipants(ctx, p.ChatID)
-	for _, uid := range participants {
-		if uid == client.UserID {
+	for _, uid := range others {
+		if _, ok := blocked[uid]; ok {
 			continue
 		}
 		s.rdb.Publish(ctx, "user:channel:"+uid, data)
@@ -185,17 +299,28 @@ func (s *wsServiceImpl) handlePresenceSubscribe(ctx context.Context, client *mod
 		return err
 	}
 
-	// Track the subscription for future presence change notifications
+	// Track the subscription for future presence change notifications. What
+	// the subscriber may see is checked on every change, so later privacy or
+	// block changes apply to existing subscriptions too.
 	s.presenceTracker.Subscribe(client.UserID, p.UserIDs)
 
-	// Return current presence state for each requested user
+	visible := make(map[string]*userv1.PresenceVisibility, len(p.UserIDs))
+	for _, v := range s.presenceVisibility(ctx, []string{client.UserID}, p.UserIDs) {
+		visible[v.UserId] = v
+	}
+
+	// Return current presence state for each requested user, as far as the
+	// subscriber may see it.
 	for _, uid := range p.UserIDs {
-		online := s.hub.IsConnected(uid)
+		payload := model.PresenceEventPayload{UserID: uid}
+		if v := visible[uid]; v != nil {
+			payload.Online = v.Online && s.hub.IsConnected(uid)
+			if !payload.Online && v.LastSeen {
+				payload.LastSeen = s.lastSeen(ctx, uid)
+			}
+		}
 		event := model.WSEvent{Type: "presence"}
-		event.Payload, _ = json.Marshal(model.PresenceEventPayload{
-			UserID: uid,
-			Online: online,
-		})
+		event.Payload, _ = json.Marshal(payload)
 		s.SendToUser(client.UserID, &event)
 	}
 	return nil
@@ -206,6 +331,13 @@ func (s *wsServiceImpl) handleCallOffer(ctx context.Context, client *model.Clien
 	if err := json.Unmarshal(payload, &p); err != nil {
 		return fmt.Errorf("invalid call.offer payload: %w", err)
 	}
+	blocked, err := s.blockedAmong(ctx, client.UserID, []string{p.TargetUserID})
+	if err != nil {
+		return err
+	}
+	if _, ok := blocked[p.TargetUserID]; ok {
+		return fmt.Errorf("cannot call this user")
+	}
 
 	event := model.WSEvent{Type: "call.offer"}
 	event.Payload, _ = json.Marshal(map[string]string{
@@ -10,7 +10,8 @@ import (
 	"github.com/whatsapp-clone/backend/websocket-service/internal/model"
 )
 
-// ensureStreams creates MESSAGES and CHATS JetStream streams if they do not exist.
+// ensureStreams creates MESSAGES and CHATS JetStream streams if they do not exist,
+// along with the per-user event log used for offline replay.
 // This makes the websocket-service resilient to startup ordering.
 func (s *wsServiceImpl) ensureStreams() error {
 	streams := []struct {
@@ -34,7 +35,7 @@ func (s *wsServiceImpl) ensureStreams() error {
 		}
 		s.log.Info().Str("stream", st.name).Msg("created JetStream stream")
 	}
-	return nil
+	return s.ensureEventLog()
 }
 
 // StartNATSConsumers subscribes to NATS JetStream subjects for real-time delivery.
@@ -51,6 +52,21 @@ func (s *wsServiceImpl) StartNATSConsumers(ctx context.Context) error {
 	if err := s.subscribeDeletedMessages(ctx); err != nil {
 		return err
 	}
+	if err := s.subscribeEditedMessages(ctx); err != nil {
+		return err
+	}
+	if err := s.subscribeExpiredMessages(ctx); err != nil {
+		return err
+	}
+	if err := s.subscribeThreadUpdates(ctx); err != nil {
+		return err
+	}
+	if err := s.subscribePollEvents(ctx); err != nil {
+		return err
+	}
+	if err := s.subscribeLocationStopped(ctx); err != nil {
+		return err
+	}
 	if err := s.subscribeReactions(ctx); err != nil {
 		return err
 	}
@@ -64,16 +80,24 @@ func (s *wsServiceImpl) StartNATSConsumers(ctx context.Context) error {
 func (s *wsServiceImpl) subscribeNewMessages(ctx context.Context) error {
 	_, err := s.js.Subscribe("msg.new", func(m *nats.Msg) {
 		var event struct {
-			MessageID string `json:"message_id"`
-			ChatID    string `json:"chat_id"`
-			SenderID  string `json:"sender_id"`
-			Type      string `json:"type"`
-			Payload   struct {
-				Body       string `json:"body"`
-				MediaID    string `json:"media_id"`
-				Caption    string `json:"caption"`
-				Filename   string `json:"filename"`
-				DurationMs int64  `json:"duration_ms"`
+			MessageID        string `json:"message_id"`
+			ChatID           string `json:"chat_id"`
+			Seq              int64  `json:"seq"`
+			SenderID         string `json:"sender_id"`
+			Type             string `json:"type"`
+			ReplyToMessageID string `json:"reply_to_message_id"`
+			ThreadID         string `json:"thread_id"`
+			Payload          struct {
+				Body        string           `json:"body"`
+				MediaID     string           `json:"media_id"`
+				Caption     string           `json:"caption"`
+				Filename    string           `json:"filename"`
+				DurationMs  int64            `json:"duration_ms"`
+				Poll        json.RawMessage  `json:"poll"`
+				Location    *model.Location  `json:"location"`
+				Contacts    []model.Contact  `json:"contacts"`
+				Encrypted   *model.Encrypted `json:"encrypted"`
+				GroupInvite json.RawMessage  `json:"group_invite"`
 			} `json:"payload"`
 			CreatedAt time.Time `json:"created_at"`
 		}
@@ -83,26 +107,38 @@ func (s *wsServiceImpl) subscribeNewMessages(ctx context.Context) error {
 			return
 		}
 
-		wsEvent := model.WSEvent{Type: "message.new"}
-		wsEvent.Payload, _ = json.Marshal(model.MessageNewPayload{
-			MessageID: event.MessageID,
-			ChatID:    event.ChatID,
-			SenderID:  event.SenderID,
-			Type:      event.Type,
+		payload := model.MessageNewPayload{
+			MessageID:        event.MessageID,
+			ChatID:           event.ChatID,
+			Seq:              event.Seq,
+			SenderID:         event.SenderID,
+			Type:             event.Type,
+			ReplyToMessageID: event.ReplyToMessageID,
+			ThreadID:         event.ThreadID,
 			Payload: model.MessageContent{
-				Body:       event.Payload.Body,
-				MediaID:    event.Payload.MediaID,
-				Caption:    event.Payload.Caption,
-				Filename:   event.Payload.Filename,
-				DurationMs: event.Payload.DurationMs,
+				Body:        event.Payload.Body,
+				MediaID:     event.Payload.MediaID,
+				Caption:     event.Payload.Caption,
+				Filename:    event.Payload.Filename,
+				DurationMs:  event.Payload.DurationMs,
+				Poll:        event.Payload.Poll,
+				Location:    event.Payload.Location,
+				Contacts:    event.Payload.Contacts,
+				GroupInvite: event.Payload.GroupInvite,
 			},
 			CreatedAt: event.CreatedAt.UnixMilli(),
-		})
+		}
+		wsEvent := model.WSEvent{Type: "message.new"}
+		wsEvent.Payload, _ = json.Marshal(payload)
 
-		data, _ := json.Marshal(wsEvent)
 		participantIDs := s.getChatParticipants(ctx, event.ChatID)
 		for _, uid := range participantIDs {
-			s.rdb.Publish(ctx, "user:channel:"+uid, data)
+			// Encrypted messages carry only the recipient's own envelopes.
+			if event.Payload.Encrypted != nil {
+				payload.Payload.Encrypted = event.Payload.Encrypted.ViewFor(uid)
+				wsEvent.Payload, _ = json.Marshal(payload)
+			}
+			s.publishToUser(ctx, uid, wsEvent)
 		}
 
 		_ = m.Ack()
@@ -115,15 +151,17 @@ func (s *wsServiceImpl) subscribeNewMessages(ctx context.Context) error {
 	return nil
 }
 
-// subscribeStatusUpdates handles msg.status.updated — notifies the original sender.
+// subscribeStatusUpdates handles msg.status.updated — a member's delivered or
+// read watermark moved. One event covers every message up to seq, so it goes
+// to all the other participants rather than to each message's sender.
 func (s *wsServiceImpl) subscribeStatusUpdates(ctx context.Context) error {
 	_, err := s.js.Subscribe("msg.status.updated", func(m *nats.Msg) {
 		var event struct {
 			MessageID string `json:"message_id"`
 			ChatID    string `json:"chat_id"`
 			UserID    string `json:"user_id"`
-			SenderID  string `json:"sender_id"`
 			Status    string `json:"status"`
+			Seq       int64  `json:"seq"`
 		}
 		if err := json.Unmarshal(m.Data, &event); err != nil {
 			s.log.Error().Err(err).Msg("failed to unmarshal msg.status.updated")
@@ -132,14 +170,19 @@ func (s *wsServiceImpl) subscribeStatusUpdates(ctx context.Context) error {
 		}
 
 		wsEvent := model.WSEvent{Type: "message.status"}
-		wsEvent.Payload, _ = json.Marshal(map[string]string{
+		wsEvent.Payload, _ = json.Marshal(map[string]interface{}{
 			"message_id": event.MessageID,
 			"chat_id":    event.ChatID,
 			"user_id":    event.UserID,
 			"status":     event.Status,
+			"seq":        event.Seq,
 		})
-		data, _ := json.Marshal(wsEvent)
-		s.rdb.Publish(context.Background(), "user:channel:"+event.SenderID, data)
+		for _, uid := range s.getChatParticipants(ctx, event.ChatID) {
+			if uid == event.UserID {
+				continue
+			}
+			s.publishToUser(context.Background(), uid, wsEvent)
+		}
 
 		_ = m.Ack()
 	}, nats.Durable("ws-status-consumer"), nats.ManualAck())
@@ -171,15 +214,14 @@ func (s *wsServiceImpl) subscribeDeletedMessages(ctx context.Context) error {
 			"message_id": event.MessageID,
 			"user_id":    event.UserID,
 		})
-		data, _ := json.Marshal(wsEvent)
 
 		if event.ForEveryone {
 			participantIDs := s.getChatParticipants(ctx, event.ChatID)
 			for _, uid := range participantIDs {
-				s.rdb.Publish(context.Background(), "user:channel:"+uid, data)
+				s.publishToUser(context.Background(), uid, wsEvent)
 			}
 		} else {
-			s.rdb.Publish(context.Background(), "user:channel:"+event.UserID, data)
+			s.publishToUser(context.Background(), event.UserID, wsEvent)
 		}
 
 		_ = m.Ack()
@@ -192,6 +234,218 @@ func (s *wsServiceImpl) subscribeDeletedMessages(ctx context.Context) error {
 	return nil
 }
 
+// subscribeEditedMessages handles msg.edited — delivers the new content to chat participants.
+func (s *wsServiceImpl) subscribeEditedMessages(ctx context.Context) error {
+	_, err := s.js.Subscribe("msg.edited", func(m *nats.Msg) {
+		var event struct {
+			MessageID string               `json:"message_id"`
+			ChatID    string               `json:"chat_id"`
+			Seq       int64                `json:"seq"`
+			SenderID  string               `json:"sender_id"`
+			Payload   model.MessageContent `json:"payload"`
+			EditedAt  time.Time            `json:"edited_at"`
+		}
+		if err := json.Unmarshal(m.Data, &event); err != nil {
+			s.log.Error().Err(err).Msg("failed to unmarshal msg.edited")
+			_ = m.Nak()
+			return
+		}
+
+		wsEvent := model.WSEvent{Type: "message.edited"}
+		wsEvent.Payload, _ = json.Marshal(model.MessageEditedPayload{
+			MessageID: event.MessageID,
+			ChatID:    event.ChatID,
+			Seq:       event.Seq,
+			SenderID:  event.SenderID,
+			Payload:   event.Payload,
+			EditedAt:  event.EditedAt.UnixMilli(),
+		})
+
+		participantIDs := s.getChatParticipants(ctx, event.ChatID)
+		for _, uid := range participantIDs {
+			s.publishToUser(context.Background(), uid, wsEvent)
+		}
+
+		_ = m.Ack()
+	}, nats.Durable("ws-edit-consumer"), nats.ManualAck())
+
+	if err != nil {
+		return err
+	}
+	s.log.Info().Msg("subscribed to msg.edited")
+	return nil
+}
+
+// subscribeExpiredMessages handles msg.expired — tells chat participants which
+// messages were removed by the disappearing messages timer.
+func (s *wsServiceImpl) subscribeExpiredMessages(ctx context.Context) error {
+	_, err := s.js.Subscribe("msg.expired", func(m *nats.Msg) {
+		var event struct {
+			ChatID     string   `json:"chat_id"`
+			MessageIDs []string `json:"message_ids"`
+		}
+		if err := json.Unmarshal(m.Data, &event); err != nil {
+			s.log.Error().Err(err).Msg("failed to unmarshal msg.expired")
+			_ = m.Nak()
+			return
+		}
+
+		wsEvent := model.WSEvent{Type: "message.expired"}
+		wsEvent.Payload, _ = json.Marshal(model.MessageExpiredPayload{
+			ChatID:     event.ChatID,
+			MessageIDs: event.MessageIDs,
+		})
+
+		participantIDs := s.getChatParticipants(ctx, event.ChatID)
+		for _, uid := range participantIDs {
+			s.publishToUser(context.Background(), uid, wsEvent)
+		}
+
+		_ = m.Ack()
+	}, nats.Durable("ws-expire-consumer"), nats.ManualAck())
+
+	if err != nil {
+		return err
+	}
+	s.log.Info().Msg("subscribed to msg.expired")
+	return nil
+}
+
+// subscribeThreadUpdates handles msg.thread.updated — pushes a thread root's
+// new reply count to chat participants.
+func (s *wsServiceImpl) subscribeThreadUpdates(ctx context.Context) error {
+	_, err := s.js.Subscribe("msg.thread.updated", func(m *nats.Msg) {
+		var event struct {
+			MessageID   string     `json:"message_id"`
+			ChatID      string     `json:"chat_id"`
+			ReplyCount  int64      `json:"reply_count"`
+			LastReplyAt *time.Time `json:"last_reply_at"`
+		}
+		if err := json.Unmarshal(m.Data, &event); err != nil {
+			s.log.Error().Err(err).Msg("failed to unmarshal msg.thread.updated")
+			_ = m.Nak()
+			return
+		}
+
+		payload := model.ThreadUpdatedPayload{
+			MessageID:  event.MessageID,
+			ChatID:     event.ChatID,
+			ReplyCount: event.ReplyCount,
+		}
+		if event.LastReplyAt != nil {
+			payload.LastReplyAt = event.LastReplyAt.UnixMilli()
+		}
+		wsEvent := model.WSEvent{Type: "thread.updated"}
+		wsEvent.Payload, _ = json.Marshal(payload)
+
+		participantIDs := s.getChatParticipants(ctx, event.ChatID)
+		for _, uid := range participantIDs {
+			s.publishToUser(context.Background(), uid, wsEvent)
+		}
+
+		_ = m.Ack()
+	}, nats.Durable("ws-thread-consumer"), nats.ManualAck())
+
+	if err != nil {
+		return err
+	}
+	s.log.Info().Msg("subscribed to msg.thread.updated")
+	return nil
+}
+
+// subscribePollEvents handles msg.poll.voted and msg.poll.closed — pushes
+// updated tallies to chat participants as poll.voted / poll.closed.
+func (s *wsServiceImpl) subscribePollEvents(ctx context.Context) error {
+	_, err := s.js.Subscribe("msg.poll.>", func(m *nats.Msg) {
+		var event struct {
+			MessageID   string            `json:"message_id"`
+			ChatID      string            `json:"chat_id"`
+			UserID      string            `json:"user_id"`
+			OptionIDs   []string          `json:"option_ids"`
+			Options     []model.PollTally `json:"options"`
+			TotalVoters int               `json:"total_voters"`
+			ClosedAt    *time.Time        `json:"closed_at"`
+		}
+		if err := json.Unmarshal(m.Data, &event); err != nil {
+			s.log.Error().Err(err).Str("subject", m.Subject).Msg("failed to unmarshal poll event")
+			_ = m.Nak()
+			return
+		}
+
+		payload := model.PollUpdatedPayload{
+			MessageID:   event.MessageID,
+			ChatID:      event.ChatID,
+			UserID:      event.UserID,
+			OptionIDs:   event.OptionIDs,
+			Options:     event.Options,
+			TotalVoters: event.TotalVoters,
+		}
+		if event.ClosedAt != nil {
+			payload.ClosedAt = event.ClosedAt.UnixMilli()
+		}
+		// msg.poll.voted -> poll.voted, msg.poll.closed -> poll.closed
+		wsEvent := model.WSEvent{Type: strings.TrimPrefix(m.Subject, "msg.")}
+		wsEvent.Payload, _ = json.Marshal(payload)
+
+		participantIDs := s.getChatParticipants(ctx, event.ChatID)
+		for _, uid := range participantIDs {
+			s.publishToUser(context.Background(), uid, wsEvent)
+		}
+
+		_ = m.Ack()
+	}, nats.Durable("ws-poll-consumer"), nats.ManualAck())
+
+	if err != nil {
+		return err
+	}
+	s.log.Info().Msg("subscribed to msg.poll.>")
+	return nil
+}
+
+// subscribeLocationStopped handles msg.location.stopped — tells chat
+// participants that a live location share ended.
+func (s *wsServiceImpl) subscribeLocationStopped(ctx context.Context) error {
+	_, err := s.js.Subscribe("msg.location.stopped", func(m *nats.Msg) {
+		var event struct {
+			MessageID string          `json:"message_id"`
+			ChatID    string          `json:"chat_id"`
+			SenderID  string          `json:"sender_id"`
+			Location  *model.Location `json:"location"`
+			Reason    string          `json:"reason"`
+		}
+		if err := json.Unmarshal(m.Data, &event); err != nil {
+			s.log.Error().Err(err).Msg("failed to unmarshal msg.location.stopped")
+			_ = m.Nak()
+			return
+		}
+
+		payload := model.LocationStoppedPayload{
+			MessageID: event.MessageID,
+			ChatID:    event.ChatID,
+			SenderID:  event.SenderID,
+			Reason:    event.Reason,
+		}
+		if event.Location != nil && event.Location.StoppedAt != nil {
+			payload.StoppedAt = event.Location.StoppedAt.UnixMilli()
+		}
+		wsEvent := model.WSEvent{Type: "location.stopped"}
+		wsEvent.Payload, _ = json.Marshal(payload)
+
+		participantIDs := s.getChatParticipants(ctx, event.ChatID)
+		for _, uid := range participantIDs {
+			s.publishToUser(context.Background(), uid, wsEvent)
+		}
+
+		_ = m.Ack()
+	}, nats.Durable("ws-location-consumer"), nats.ManualAck())
+
+	if err != nil {
+		return err
+	}
+	s.log.Info().Msg("subscribed to msg.location.stopped")
+	return nil
+}
+
 // subscribeReactions handles msg.reaction — routes reaction events to chat participants.
 func (s *wsServiceImpl) subscribeReactions(ctx context.Context) error {
 	_, err := s.js.Subscribe("msg.reaction", func(m *nats.Msg) {
@@ -216,11 +470,9 @@ func (s *wsServiceImpl) subscribeReactions(ctx context.Context) error {
 			Emoji:     event.Emoji,
 			Removed:   event.Removed,
 		})
-		data, _ := json.Marshal(wsEvent)
-
 		participantIDs := s.getChatParticipants(ctx, event.ChatID)
 		for _, uid := range participantIDs {
-			s.rdb.Publish(context.Background(), "user:channel:"+uid, data)
+			s.publishToUser(context.Background(), uid, wsEvent)
 		}
 
 		_ = m.Ack()
@@ -233,9 +485,14 @@ func (s *wsServiceImpl) subscribeReactions(ctx context.Context) error {
 	return nil
 }
 
-// subscribeChatAndGroupEvents handles chat.created, chat.updated, group.member.added/removed.
+// subscribeChatAndGroupEvents handles chat.created, chat.updated, group.member.added/removed,
+// invite-link join requests (group.join.requested/rejected) and declined group
+// invitations (group.invitation.declined).
 func (s *wsServiceImpl) subscribeChatAndGroupEvents(_ context.Context) error {
-	subjects := []string{"chat.created", "chat.updated", "group.member.added", "group.member.removed"}
+	subjects := []string{
+		"chat.created", "chat.updated", "group.member.added", "group.member.removed",
+		"group.join.requested", "group.join.rejected", "group.invitation.declined",
+	}
 	for _, subj := range subjects {
 		subject := subj
 		_, err := s.js.Subscribe(subject, func(m *nats.Msg) {
@@ -250,10 +507,9 @@ func (s *wsServiceImpl) subscribeChatAndGroupEvents(_ context.Context) error {
 			wsEvent.Payload = m.Data
 
 			if members, ok := event["participants"].([]interface{}); ok {
-				data, _ := json.Marshal(wsEvent)
 				for _, mid := range members {
 					if uid, ok := mid.(string); ok {
-						s.rdb.Publish(context.Background(), "user:channel:"+uid, data)
+						s.publishToUser(context.Background(), uid, wsEvent)
 					}
 				}
 			}
@@ -3,11 +3,15 @@ package service
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"encoding/json"
 
 	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
 	"github.com/whatsapp-clone/backend/websocket-service/internal/model"
 )
 
@@ -21,27 +25,72 @@ func (s *wsServiceImpl) SetPresence(ctx context.Context, userID string, online b
 
 	pipe := s.rdb.Pipeline()
 	pipe.Del(ctx, key)
-	pipe.Set(ctx, fmt.Sprintf("last_seen:%s", userID), time.Now().UnixMilli(), 0)
+	pipe.Set(ctx, fmt.Sprintf("last_seen:%s", userID), time.Now().UTC().Format(time.RFC3339), 0)
 	_, err := pipe.Exec(ctx)
 	return err
 }
 
 // NotifyPresenceChange notifies all subscribers when a user's presence changes.
+// Each subscriber only hears what the user's privacy settings let them see:
+// nothing if the user's online status is hidden from them, and the last seen
+// on going offline only if it is shared with them.
 func (s *wsServiceImpl) NotifyPresenceChange(userID string, online bool) {
 	subscribers := s.presenceTracker.GetSubscribers(userID)
 	if len(subscribers) == 0 {
 		return
 	}
 
-	event := model.WSEvent{Type: "presence"}
-	event.Payload, _ = json.Marshal(model.PresenceEventPayload{
-		UserID: userID,
-		Online: online,
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var lastSeen string
+	if !online {
+		lastSeen = s.lastSeen(ctx, userID)
+	}
+
+	for _, v := range s.presenceVisibility(ctx, subscribers, []string{userID}) {
+		payload := model.PresenceEventPayload{UserID: userID, Online: online}
+		switch {
+		case !online && v.LastSeen:
+			payload.LastSeen = lastSeen
+		case !v.Online:
+			continue
+		}
+		event := model.WSEvent{Type: "presence"}
+		event.Payload, _ = json.Marshal(payload)
+		s.SendToUser(v.ViewerId, &event)
+	}
+}
+
+// presenceVisibility asks user-service what each viewer may see of each
+// user's presence; either side must hold a single user. Unlike block checks
+// this fails closed: if user-service cannot be reached, nothing is shown.
+func (s *wsServiceImpl) presenceVisibility(ctx context.Context, viewerIDs, userIDs []string) []*userv1.PresenceVisibility {
+	if len(viewerIDs) == 0 || len(userIDs) == 0 {
+		return nil
+	}
+	resp, err := s.userClient.GetPresenceVisibility(ctx, &userv1.GetPresenceVisibilityRequest{
+		ViewerIds: viewerIDs,
+		UserIds:   userIDs,
 	})
+	if err != nil {
+		s.log.Warn().Err(err).Msg("failed to check presence visibility")
+		return nil
+	}
+	return resp.Visibilities
+}
 
-	for _, subID := range subscribers {
-		s.SendToUser(subID, &event)
+// lastSeen returns when the user was last online as an RFC 3339 time, or ""
+// if that is not known. Values written before last seen was stored as
+// RFC 3339 are Unix milliseconds and are converted.
+func (s *wsServiceImpl) lastSeen(ctx context.Context, userID string) string {
+	val, err := s.rdb.Get(ctx, "last_seen:"+userID).Result()
+	if err != nil && err != redis.Nil {
+		s.log.Warn().Err(err).Str("user_id", userID).Msg("failed to read last seen")
+	}
+	if ms, err := strconv.ParseInt(val, 10, 64); err == nil {
+		return time.UnixMilli(ms).UTC().Format(time.RFC3339)
 	}
+	return val
 }
 
 // CleanupPresenceSubscriptions removes all presence subscriptions for a user (e.g., on disconnect).
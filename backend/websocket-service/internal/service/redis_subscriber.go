@@ -30,13 +30,25 @@ func (s *wsServiceImpl) StartRedisSubscriber(ctx context.Context, client *model.
 	go func() {
 		ch := pubsub.Channel()
 		for msg := range ch {
-			select {
-			case client.Send <- []byte(msg.Payload):
-			default:
+			data := []byte(msg.Payload)
+			// A logged-out device is disconnected instead of being told.
+			if id := model.RevokedDevice(data); id != "" && id == client.AuthDeviceID {
+				s.log.Info().Str("user_id", client.UserID).Str("device_id", id).Msg("closing connection of revoked device")
+				client.Disconnect(model.CloseDeviceRevoked, "device logged out")
+				continue
+			}
+			seq := model.FrameSeq(data)
+			if client.Enqueue(seq, data) {
+				continue
+			}
+			if seq == 0 {
 				s.log.Warn().
 					Str("user_id", client.UserID).
 					Msg("redis subscriber: client send buffer full, dropping message")
+				continue
 			}
+			s.log.Warn().Str("user_id", client.UserID).Uint64("seq", seq).Msg("client send buffer full, closing connection to resync")
+			client.Disconnect(model.CloseResyncRequired, "send buffer full, reconnect to resync")
 		}
 	}()
 
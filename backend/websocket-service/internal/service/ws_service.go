@@ -26,6 +26,16 @@ type WebSocketService interface {
 	// StopRedisSubscriber stops the Redis pub-sub subscriber for the given client.
 	StopRedisSubscriber(client *model.Client) error
 
+	// ResumeSession replays logged events after afterSeq to the client in order,
+	// then switches it to live delivery. The client must be in replay mode.
+	ResumeSession(ctx context.Context, client *model.Client, afterSeq uint64) error
+
+	// LoadSyncCursor returns the last sequence delivered to a user's device, or 0.
+	LoadSyncCursor(ctx context.Context, userID, deviceID string) (uint64, error)
+
+	// SaveSyncCursor persists the highest sequence written to the client's device.
+	SaveSyncCursor(ctx context.Context, client *model.Client) error
+
 	// NotifyPresenceChange notifies all subscribers when a user's presence changes.
 	NotifyPresenceChange(userID string, online bool)
 
@@ -36,7 +46,8 @@ type WebSocketService interface {
 	GracefulShutdown()
 }
 
-// AuthValidator validates tokens via the auth-service gRPC.
+// AuthValidator validates tokens via the auth-service gRPC. deviceID is the
+// linked device the token was issued to, or empty for unbound tokens.
 type AuthValidator interface {
-	ValidateToken(ctx context.Context, token string) (userID, phone string, err error)
+	ValidateToken(ctx context.Context, token string) (userID, phone, deviceID string, err error)
 }
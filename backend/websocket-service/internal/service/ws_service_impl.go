@@ -12,6 +12,7 @@ import (
 
 	chatv1 "github.com/whatsapp-clone/backend/proto/chat/v1"
 	messagev1 "github.com/whatsapp-clone/backend/proto/message/v1"
+	userv1 "github.com/whatsapp-clone/backend/proto/user/v1"
 	"github.com/whatsapp-clone/backend/websocket-service/config"
 	"github.com/whatsapp-clone/backend/websocket-service/internal/model"
 )
@@ -23,6 +24,7 @@ type wsServiceImpl struct {
 	js            nats.JetStreamContext
 	messageClient messagev1.MessageServiceClient
 	chatClient    chatv1.ChatServiceClient
+	userClient    userv1.UserServiceClient
 	cfg           *config.Config
 	log             zerolog.Logger
 	subRegistry     *subscriberRegistry
@@ -37,6 +39,7 @@ func NewWebSocketService(
 	js nats.JetStreamContext,
 	messageClient messagev1.MessageServiceClient,
 	chatClient chatv1.ChatServiceClient,
+	userClient userv1.UserServiceClient,
 	cfg *config.Config,
 	log zerolog.Logger,
 ) WebSocketService {
@@ -47,6 +50,7 @@ func NewWebSocketService(
 		js:            js,
 		messageClient: messageClient,
 		chatClient:    chatClient,
+		userClient:    userClient,
 		cfg:           cfg,
 		log:           log,
 		subRegistry:     newSubscriberRegistry(),
@@ -63,9 +67,7 @@ func (s *wsServiceImpl) SendToUser(userID string, event *model.WSEvent) error {
 
 	clients := s.hub.GetClients(userID)
 	for _, c := range clients {
-		select {
-		case c.Send <- data:
-		default:
+		if !c.Enqueue(0, data) {
 			s.log.Warn().Str("user_id", userID).Msg("client send buffer full, dropping message")
 		}
 	}